@@ -79,6 +79,32 @@ pub fn build(b: *std.Build) void {
     const rpc_test_step = b.step("rpc-test", "Run RPC integration tests");
     rpc_test_step.dependOn(&run_rpc_test.step);
 
+    // ── Schema fuzzer ────────────────────────────────────────────
+    // go/cmd/schemafuzz writes a random fuzz.proto to a directory and runs
+    // `zig build schemafuzz -Dschema-dir=DIR -- IN OUT`, which generates code
+    // for that schema and re-encodes every case of IN into OUT.
+    if (b.option([]const u8, "schema-dir", "Directory holding a go/cmd/schemafuzz fuzz.proto")) |schema_dir| {
+        const fuzz_mod = protobuf.generate(b, proto_dep, .{
+            .proto_sources = .{ .cwd_relative = schema_dir },
+        });
+        const schemafuzz = b.addExecutable(.{
+            .name = "schemafuzz",
+            .root_module = b.createModule(.{
+                .root_source_file = b.path("src/schemafuzz.zig"),
+                .target = target,
+                .optimize = optimize,
+                .imports = &.{
+                    .{ .name = "proto", .module = fuzz_mod },
+                    .{ .name = "protobuf", .module = proto_dep.module("protobuf") },
+                },
+            }),
+        });
+        const run_schemafuzz = b.addRunArtifact(schemafuzz);
+        if (b.args) |args| run_schemafuzz.addArgs(args);
+        const schemafuzz_step = b.step("schemafuzz", "Round-trip a schemafuzz corpus through code generated for its schema");
+        schemafuzz_step.dependOn(&run_schemafuzz.step);
+    }
+
     const test_step = b.step("test", "Run compat tests");
     test_step.dependOn(&b.addRunArtifact(compat_test).step);
     test_step.dependOn(&b.addRunArtifact(service_test).step);
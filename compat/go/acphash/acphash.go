@@ -0,0 +1,90 @@
+// Package acphash implements the ACP payload_hash scheme: "sha256:" followed
+// by the lowercase hex SHA-256 of an asset's decompressed bytes.
+package acphash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// Prefix starts every payload_hash.
+const Prefix = "sha256:"
+
+var (
+	// ErrMalformed means a string is not a payload_hash.
+	ErrMalformed = errors.New("malformed payload_hash")
+	// ErrMismatch means data does not hash to the payload_hash claimed for it.
+	ErrMismatch = errors.New("payload_hash mismatch")
+)
+
+// Sum returns the payload_hash of data.
+func Sum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return Prefix + hex.EncodeToString(sum[:])
+}
+
+// Parse returns the digest in a payload_hash. It accepts only the form Sum
+// produces, so two valid hashes of the same data compare equal as strings.
+func Parse(s string) ([sha256.Size]byte, error) {
+	var d [sha256.Size]byte
+	h, ok := strings.CutPrefix(s, Prefix)
+	if !ok || len(h) != hex.EncodedLen(sha256.Size) || strings.ToLower(h) != h {
+		return d, fmt.Errorf("%w: %q", ErrMalformed, s)
+	}
+	if _, err := hex.Decode(d[:], []byte(h)); err != nil {
+		return d, fmt.Errorf("%w: %q", ErrMalformed, s)
+	}
+	return d, nil
+}
+
+// Verify checks data against the claimed payload_hash.
+func Verify(data []byte, claimed string) error {
+	if _, err := Parse(claimed); err != nil {
+		return err
+	}
+	if got := Sum(data); got != claimed {
+		return fmt.Errorf("%w: data hashes to %s, claimed %s", ErrMismatch, got, claimed)
+	}
+	return nil
+}
+
+// Hasher computes a payload_hash over data written in pieces, such as the
+// chunks of an uncompressed transfer.
+type Hasher struct {
+	h hash.Hash
+	n int64
+}
+
+// New returns an empty Hasher.
+func New() *Hasher {
+	return &Hasher{h: sha256.New()}
+}
+
+// Write adds p to the hashed data. It never fails.
+func (h *Hasher) Write(p []byte) (int, error) {
+	h.n += int64(len(p))
+	return h.h.Write(p)
+}
+
+// Len returns the number of bytes written, to check against file_length.
+func (h *Hasher) Len() int64 { return h.n }
+
+// Sum returns the payload_hash of the data written so far.
+func (h *Hasher) Sum() string {
+	return Prefix + hex.EncodeToString(h.h.Sum(nil))
+}
+
+// Verify checks the data written so far against the claimed payload_hash.
+func (h *Hasher) Verify(claimed string) error {
+	if _, err := Parse(claimed); err != nil {
+		return err
+	}
+	if got := h.Sum(); got != claimed {
+		return fmt.Errorf("%w: data hashes to %s, claimed %s", ErrMismatch, got, claimed)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package acphash_test
+
+import (
+	"errors"
+	"testing"
+
+	"compat/acphash"
+)
+
+const abc = "sha256:ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+
+func TestSum(t *testing.T) {
+	if got := acphash.Sum([]byte("abc")); got != abc {
+		t.Errorf("Sum(abc) = %s, want %s", got, abc)
+	}
+	h := acphash.New()
+	h.Write([]byte("a"))
+	h.Write([]byte("bc"))
+	if got := h.Sum(); got != abc || h.Len() != 3 {
+		t.Errorf("Hasher: %s over %d bytes, want %s over 3", got, h.Len(), abc)
+	}
+}
+
+func TestParse(t *testing.T) {
+	if _, err := acphash.Parse(abc); err != nil {
+		t.Errorf("Parse(%s): %v", abc, err)
+	}
+	for _, s := range []string{
+		"",
+		"sha256:deadbeef",
+		"md5:ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		"sha256:BA7816BF8F01CFEA414140DE5DAE2223B00361A396177A9CB410FF61F20015AD",
+		"sha256:zz7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+	} {
+		if _, err := acphash.Parse(s); !errors.Is(err, acphash.ErrMalformed) {
+			t.Errorf("Parse(%q) = %v, want ErrMalformed", s, err)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	if err := acphash.Verify([]byte("abc"), abc); err != nil {
+		t.Errorf("Verify(abc): %v", err)
+	}
+	if err := acphash.Verify([]byte("abd"), abc); !errors.Is(err, acphash.ErrMismatch) {
+		t.Errorf("Verify(abd) = %v, want ErrMismatch", err)
+	}
+	if err := acphash.Verify([]byte("abc"), "sha256:abc"); !errors.Is(err, acphash.ErrMalformed) {
+		t.Errorf("Verify with a short hash = %v, want ErrMalformed", err)
+	}
+	h := acphash.New()
+	h.Write([]byte("ab"))
+	if err := h.Verify(abc); !errors.Is(err, acphash.ErrMismatch) {
+		t.Errorf("Hasher.Verify on a prefix = %v, want ErrMismatch", err)
+	}
+}
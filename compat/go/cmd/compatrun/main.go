@@ -0,0 +1,152 @@
+// Command compatrun reproduces the whole cross-language run in one command.
+// It builds the Zig side with zig build install, keeping one install prefix
+// per compiler version, target and mode, then generates the Go corpus, runs
+// the Zig compat tests against it, validates both directions and runs the
+// RPC interop matrix against the Zig server and client.
+//
+//	compatrun -zig-optimize ReleaseFast -out results
+//
+// Each results file ends up in -out with the Zig compiler version and the
+// build arguments in its run metadata: validate.json, interop.json and
+// zig.pb, the Zig tests' own testdata/zig/results.pb.
+//
+// The interop step runs the Zig pairings against the rpc-server and
+// rpc-client zig build installs, or those named by -zig-server and
+// -zig-client. compat/build.zig installs neither yet, so without those flags
+// compatrun says so, and interop runs only the Go pairings and lists the
+// ones it skipped. -require-zig fails the run instead; -interop=false runs
+// only the corpus steps.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"compat/pb"
+	"compat/testcases"
+)
+
+func main() {
+	zb := zigBuild{}
+	flag.StringVar(&zb.zig, "zig", "zig", "zig binary")
+	flag.StringVar(&zb.compatDir, "compat-dir", "..", "directory containing build.zig")
+	flag.StringVar(&zb.target, "zig-target", "", "-Dtarget to build the Zig side for (empty = host)")
+	flag.StringVar(&zb.optimize, "zig-optimize", "ReleaseSafe", "-Doptimize to build the Zig side with: Debug, ReleaseSafe, ReleaseFast or ReleaseSmall")
+	flag.StringVar(&zb.cacheDir, "zig-cache", defaultZigCache(), "directory zig build installs into, one prefix per zig version, target and mode")
+	zigServer := flag.String("zig-server", "", "Zig server command to use instead of the rpc-server zig build installs")
+	zigClient := flag.String("zig-client", "", "Zig client command to use instead of the rpc-client zig build installs")
+	interop := flag.Bool("interop", true, "run the RPC interop matrix")
+	requireZig := flag.Bool("require-zig", false, "fail unless a Zig server and client are installed or given, so every interop pairing runs")
+	outDir := flag.String("out", "results", "directory to write the results files to")
+	flag.Parse()
+
+	out, err := filepath.Abs(*outDir)
+	if err != nil {
+		fatal(err)
+	}
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		fatal(err)
+	}
+
+	bins, err := zb.build()
+	if err != nil {
+		fatal(err)
+	}
+	fmt.Printf("zig %s: zig %s\n", bins.version, strings.Join(bins.args, " "))
+	if *zigServer == "" {
+		*zigServer = bins.server
+	}
+	if *zigClient == "" {
+		*zigClient = bins.client
+	}
+	if *interop {
+		for _, bin := range []struct{ name, flag, path string }{
+			{zigServerName, "-zig-server", *zigServer},
+			{zigClientName, "-zig-client", *zigClient},
+		} {
+			if bin.path != "" {
+				continue
+			}
+			if *requireZig {
+				fatal(fmt.Errorf("-require-zig: zig build installed no %s and %s is not given", bin.name, bin.flag))
+			}
+			fmt.Printf("NOTICE: zig build installed no %s and %s is not given; interop skips its cells\n", bin.name, bin.flag)
+		}
+	}
+
+	// A failing step does not stop the later ones, so that one run reports
+	// everything that is wrong.
+	failed := 0
+	step := func(dir, name string, args ...string) {
+		fmt.Printf("\n== %s %s\n", name, strings.Join(args, " "))
+		cmd := exec.Command(name, args...)
+		cmd.Dir = dir
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "compatrun: %s: %v\n", name, err)
+			failed++
+		}
+	}
+	step(".", "go", "run", "./cmd/generate")
+	testArgs := []string{"build", "test", "-Doptimize=" + zb.optimize}
+	if zb.target != "" {
+		testArgs = append(testArgs, "-Dtarget="+zb.target)
+	}
+	step(zb.compatDir, zb.zig, testArgs...)
+	validateResults := filepath.Join(out, "validate.json")
+	step(".", "go", "run", "./cmd/validate", "-direction", "both", "-results", validateResults)
+	results := []string{validateResults}
+	if *interop {
+		interopResults := filepath.Join(out, "interop.json")
+		args := []string{"run", "./cmd/interop", "-results", interopResults}
+		if *zigServer != "" {
+			args = append(args, "-zig-server", *zigServer)
+		}
+		if *zigClient != "" {
+			args = append(args, "-zig-client", *zigClient)
+		}
+		step(".", "go", args...)
+		results = append(results, interopResults)
+	}
+
+	zigResults := filepath.Join(zb.compatDir, "testdata", "zig", "results.pb")
+	if err := stamp(zigResults, filepath.Join(out, "zig.pb"), bins); err != nil {
+		fmt.Fprintf(os.Stderr, "compatrun: %v\n", err)
+		failed++
+	}
+	for _, path := range results {
+		if err := stamp(path, path, bins); err != nil {
+			fmt.Fprintf(os.Stderr, "compatrun: %v\n", err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("\n%d step(s) failed\n", failed)
+		os.Exit(1)
+	}
+	fmt.Printf("\nresults in %s\n", out)
+}
+
+// stamp records the Zig build in the run metadata of the results file from
+// and writes it to to.
+func stamp(from, to string, bins zigBinaries) error {
+	m, err := testcases.ReadResults(from)
+	if err != nil {
+		return err
+	}
+	if m.Run == nil {
+		m.Run = &pb.RunMetadata{}
+	}
+	m.Run.ZigVersion, m.Run.ZigBuildArgs = bins.version, bins.args
+	return testcases.WriteResults(to, m)
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "compatrun: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// The names zig build installs the Zig RPC server and client under.
+const (
+	zigServerName = "rpc-server"
+	zigClientName = "rpc-client"
+)
+
+// zigBuild is how the Zig side is built.
+type zigBuild struct {
+	zig       string // zig binary
+	compatDir string // directory containing build.zig
+	target    string // -Dtarget, empty for the host
+	optimize  string // -Doptimize
+	cacheDir  string // where install prefixes are kept
+}
+
+// zigBinaries is what a zigBuild produced. server and client are empty if
+// the build did not install them.
+type zigBinaries struct {
+	version        string
+	args           []string
+	server, client string
+}
+
+// build runs zig build install into a prefix under cacheDir named for the
+// compiler version, target and mode. Switching between them then does not
+// overwrite another's binaries, and zig's own cache keeps rebuilding one
+// prefix incremental.
+func (zb zigBuild) build() (zigBinaries, error) {
+	out, err := exec.Command(zb.zig, "version").Output()
+	if err != nil {
+		return zigBinaries{}, fmt.Errorf("%s version: %w", zb.zig, err)
+	}
+	bins := zigBinaries{version: strings.TrimSpace(string(out))}
+
+	target := zb.target
+	if target == "" {
+		target = "native"
+	}
+	prefix, err := filepath.Abs(filepath.Join(zb.cacheDir, bins.version+"-"+target+"-"+zb.optimize))
+	if err != nil {
+		return zigBinaries{}, err
+	}
+	bins.args = []string{"build", "install", "--prefix", prefix, "-Doptimize=" + zb.optimize}
+	if zb.target != "" {
+		bins.args = append(bins.args, "-Dtarget="+zb.target)
+	}
+	cmd := exec.Command(zb.zig, bins.args...)
+	cmd.Dir = zb.compatDir
+	if b, err := cmd.CombinedOutput(); err != nil {
+		return zigBinaries{}, fmt.Errorf("zig %s: %v\n%s", strings.Join(bins.args, " "), err, b)
+	}
+
+	find := func(name string) string {
+		path := filepath.Join(prefix, "bin", name)
+		if _, err := os.Stat(path); err != nil {
+			return ""
+		}
+		return path
+	}
+	bins.server, bins.client = find(zigServerName), find(zigClientName)
+	return bins, nil
+}
+
+// defaultZigCache is where zigBuild keeps its prefixes unless -zig-cache
+// says otherwise.
+func defaultZigCache() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "protobuf-compat", "zig")
+}
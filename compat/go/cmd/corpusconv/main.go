@@ -0,0 +1,189 @@
+// Command corpusconv converts a corpus file to a directory of JSON files,
+// one per case, and back, so that test vectors can be reviewed and edited in
+// code review instead of in a hex editor.
+//
+//	corpusconv ../testdata/go/map3.bin map3/
+//	corpusconv map3/ map3.bin
+//
+// A file argument is converted to a directory and a directory to a file.
+// Each case is written to <case name>.json as a testcases.CaseJSON: its
+// message in proto3 JSON, decoded as the type -type names or by default the
+// type testcases.Expected reports for "<corpus name>/<case>". Cases that
+// message does not reproduce byte for byte keep their bytes as hex beside
+// it. The case order, which the corpus manifest records, goes to cases.txt;
+// converting back keeps it, adds cases not listed there in name order, and
+// drops listed cases whose file was deleted. A corpus file name ending in
+// .gz is gzipped.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	_ "compat/pb"
+	"compat/testcases"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+func main() {
+	typeName := flag.String("type", "", "full name of the message type of every case (default: from the generators)")
+	flag.Parse()
+	if flag.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: corpusconv [-type name] corpus-file json-dir | json-dir corpus-file")
+		os.Exit(2)
+	}
+	in, out := flag.Arg(0), flag.Arg(1)
+
+	info, err := os.Stat(in)
+	if err != nil {
+		fatal(err)
+	}
+	var n int
+	if info.IsDir() {
+		n, err = toCorpus(in, out)
+	} else {
+		n, err = toJSON(in, out, *typeName)
+	}
+	if err != nil {
+		fatal(err)
+	}
+	fmt.Printf("corpusconv: %d cases from %s written to %s\n", n, in, out)
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "corpusconv: %v\n", err)
+	os.Exit(1)
+}
+
+// toJSON writes every case of the corpus file at path to dir.
+func toJSON(path, dir, typeName string) (int, error) {
+	var fixed protoreflect.MessageType
+	if typeName != "" {
+		mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(typeName))
+		if err != nil {
+			return 0, fmt.Errorf("-type: %w", err)
+		}
+		fixed = mt
+	}
+	cf, err := testcases.OpenCorpus(path, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer cf.Close()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, err
+	}
+
+	category := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), ".gz"), ".bin")
+	seen := map[string]bool{}
+	var order strings.Builder
+	for _, tc := range cf.Cases {
+		if seen[tc.Name] {
+			return 0, fmt.Errorf("%s: case %q appears twice", path, tc.Name)
+		}
+		seen[tc.Name] = true
+		mt := fixed
+		if mt == nil {
+			if expected, ok := testcases.Expected(category + "/" + tc.Name); ok {
+				mt = expected.ProtoReflect().Type()
+			}
+		}
+		data, err := testcases.MarshalCaseJSON(tc, mt)
+		if err != nil {
+			return 0, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, fileName(tc.Name)), data, 0o644); err != nil {
+			return 0, err
+		}
+		order.WriteString(tc.Name + "\n")
+	}
+	return len(cf.Cases), os.WriteFile(filepath.Join(dir, orderFile), []byte(order.String()), 0o644)
+}
+
+// toCorpus frames the cases of every .json file in dir into a corpus file.
+func toCorpus(dir, path string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	var cases []testcases.RawTestCase
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return 0, err
+		}
+		tc, err := testcases.UnmarshalCaseJSON(data)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", file, err)
+		}
+		cases = append(cases, tc)
+	}
+	order, err := readOrder(dir)
+	if err != nil {
+		return 0, err
+	}
+	sort.SliceStable(cases, func(i, j int) bool {
+		a, b := cases[i].Name, cases[j].Name
+		ai, aok := order[a]
+		bi, bok := order[b]
+		if aok || bok {
+			return aok && (!bok || ai < bi)
+		}
+		return a < b
+	})
+
+	var buf bytes.Buffer
+	if err := testcases.WriteCorpusHeader(&buf); err != nil {
+		return 0, err
+	}
+	seen := map[string]bool{}
+	for _, tc := range cases {
+		if seen[tc.Name] {
+			return 0, fmt.Errorf("%s: case %q appears twice", dir, tc.Name)
+		}
+		seen[tc.Name] = true
+		if err := testcases.WriteTestCaseRaw(&buf, tc.Name, tc.Data); err != nil {
+			return 0, err
+		}
+	}
+	data := buf.Bytes()
+	if strings.HasSuffix(path, ".gz") {
+		if data, err = testcases.Compress(data); err != nil {
+			return 0, err
+		}
+	}
+	return len(cases), os.WriteFile(path, data, 0o644)
+}
+
+// orderFile lists a directory's case names in their corpus order.
+const orderFile = "cases.txt"
+
+// readOrder maps each case name in dir's orderFile to its position. A
+// directory without one has no order.
+func readOrder(dir string) (map[string]int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, orderFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	order := map[string]int{}
+	for i, name := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		order[name] = i
+	}
+	return order, nil
+}
+
+// fileName is the file a case is written to. Case names are escaped as URL
+// path segments, so a "/" in one cannot leave the directory.
+func fileName(name string) string {
+	return url.PathEscape(name) + ".json"
+}
@@ -0,0 +1,358 @@
+// Command corpusmin shrinks a failing test vector to a minimal repro. It
+// takes one case out of a corpus file and repeatedly tries smaller variants
+// of it (fields dropped, repeated fields and maps truncated, strings and
+// bytes shortened), keeping each one for which the failure still reproduces.
+//
+// Whether a variant fails is decided by the oracle command: it is run with
+// the path of a one-case corpus holding the variant appended to its
+// arguments, and a nonzero exit status means the failure reproduces. A
+// decode error and a value mismatch are told apart only by the oracle, so any
+// check that can read a corpus file works, e.g. a Zig test binary or a
+// script around cmd/validate.
+//
+//	corpusmin -corpus ../testdata/go/map3.bin -case large_map -oracle "./check.sh"
+//
+// The case is decoded as the message type -type names, or by default the
+// type testcases.Expected reports for "<corpus name>/<case>". Vectors that do
+// not decode, or any vector with -raw, are shrunk byte by byte instead.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	_ "compat/pb"
+	"compat/pbutil"
+	"compat/testcases"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+type minimizer struct {
+	oracle  []string
+	name    string
+	timeout time.Duration
+	tmp     string
+	runs    int
+}
+
+func main() {
+	corpus := flag.String("corpus", "", "corpus file holding the failing case")
+	caseName := flag.String("case", "", "name of the failing case")
+	typeName := flag.String("type", "", "full name of the case's message type (default: from the generators)")
+	oracle := flag.String("oracle", "", "command that exits nonzero while the failure reproduces; the variant's corpus path is appended")
+	out := flag.String("out", "", "where to write the minimal case (default: <case>.min.bin)")
+	raw := flag.Bool("raw", false, "shrink the encoded bytes instead of the decoded message")
+	timeout := flag.Duration("timeout", 30*time.Second, "how long one oracle run may take")
+	flag.Parse()
+	if *corpus == "" || *caseName == "" || *oracle == "" {
+		fmt.Fprintln(os.Stderr, "corpusmin: -corpus, -case and -oracle are required")
+		os.Exit(2)
+	}
+
+	data, err := findCase(*corpus, *caseName)
+	if err != nil {
+		fatal(err)
+	}
+	tmp, err := os.MkdirTemp("", "corpusmin")
+	if err != nil {
+		fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	m := &minimizer{oracle: strings.Fields(*oracle), name: *caseName, timeout: *timeout, tmp: tmp}
+
+	if ok, err := m.reproduces(data); err != nil {
+		fatal(err)
+	} else if !ok {
+		fatal(errors.New("the oracle passes on the original case"))
+	}
+
+	var msg proto.Message
+	if !*raw {
+		msg, err = decode(*corpus, *caseName, *typeName, data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "corpusmin: %v; shrinking bytes instead\n", err)
+		}
+	}
+	small := data
+	if msg != nil {
+		if msg, err = m.shrinkMessage(msg); err == nil {
+			small, err = pbutil.Marshal(msg)
+		}
+	} else {
+		small, err = m.shrinkBytes(data)
+	}
+	if err != nil {
+		fatal(err)
+	}
+
+	if *out == "" {
+		*out = *caseName + ".min.bin"
+	}
+	var buf bytes.Buffer
+	testcases.WriteCorpusHeader(&buf)
+	testcases.WriteTestCaseRaw(&buf, *caseName, small)
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		fatal(err)
+	}
+	fmt.Printf("corpusmin: %d -> %d bytes after %d oracle runs, written to %s\n", len(data), len(small), m.runs, *out)
+	if msg != nil {
+		fmt.Print(prototext.Format(msg))
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "corpusmin: %v\n", err)
+	os.Exit(1)
+}
+
+func findCase(path, name string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cases, err := testcases.ReadTestCases(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, tc := range cases {
+		if tc.Name == name {
+			return tc.Data, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: no case %q", path, name)
+}
+
+// decode parses the case as typeName or, if that is empty, as the type the
+// generators use for it.
+func decode(corpus, name, typeName string, data []byte) (proto.Message, error) {
+	var msg proto.Message
+	if typeName != "" {
+		mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(typeName))
+		if err != nil {
+			return nil, fmt.Errorf("-type: %w", err)
+		}
+		msg = mt.New().Interface()
+	} else {
+		category := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(corpus), ".gz"), ".bin")
+		expected, ok := testcases.Expected(category + "/" + name)
+		if !ok {
+			return nil, fmt.Errorf("no generator case %s/%s to take the type from (use -type)", category, name)
+		}
+		msg = expected.ProtoReflect().New().Interface()
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("decode as %s: %w", msg.ProtoReflect().Descriptor().FullName(), err)
+	}
+	return msg, nil
+}
+
+// reproduces runs the oracle on a one-case corpus holding data.
+func (m *minimizer) reproduces(data []byte) (bool, error) {
+	m.runs++
+	var buf bytes.Buffer
+	testcases.WriteCorpusHeader(&buf)
+	testcases.WriteTestCaseRaw(&buf, m.name, data)
+	path := filepath.Join(m.tmp, "case.bin")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return false, err
+	}
+	cmd := exec.Command(m.oracle[0], append(m.oracle[1:], path)...)
+	if err := cmd.Start(); err != nil {
+		return false, fmt.Errorf("oracle: %w", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			return true, nil
+		}
+		return false, err
+	case <-time.After(m.timeout):
+		cmd.Process.Kill()
+		<-done
+		return false, fmt.Errorf("oracle still running after %v", m.timeout)
+	}
+}
+
+// shrinkMessage applies reductions one at a time, keeping each that makes
+// the message smaller while the failure still reproduces, until none does.
+func (m *minimizer) shrinkMessage(best proto.Message) (proto.Message, error) {
+	for i := 0; ; {
+		candidate := proto.Clone(best)
+		n := i
+		if !reduce(candidate.ProtoReflect(), &n) {
+			return best, nil
+		}
+		if proto.Size(candidate) >= proto.Size(best) {
+			i++
+			continue
+		}
+		data, err := pbutil.Marshal(candidate)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := m.reproduces(data)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			// The reductions after i shift down into its place.
+			best = candidate
+		} else {
+			i++
+		}
+	}
+}
+
+// reduce applies the n-th possible reduction of msg in place, counting
+// *n down past the ones it skips, and reports whether one was applied.
+// Fields are visited in field-number order so the numbering is stable.
+func reduce(msg protoreflect.Message, n *int) bool {
+	take := func() bool {
+		if *n == 0 {
+			return true
+		}
+		*n--
+		return false
+	}
+
+	var fields []protoreflect.FieldDescriptor
+	msg.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Number() < fields[j].Number() })
+
+	for _, fd := range fields {
+		if take() {
+			msg.Clear(fd)
+			return true
+		}
+	}
+	if len(msg.GetUnknown()) > 0 && take() {
+		msg.SetUnknown(nil)
+		return true
+	}
+
+	for _, fd := range fields {
+		switch {
+		case fd.IsList():
+			list := msg.Mutable(fd).List()
+			if list.Len() > 1 && take() {
+				list.Truncate(list.Len() / 2)
+				return true
+			}
+			for j := 0; j < list.Len(); j++ {
+				if take() {
+					for k := j; k+1 < list.Len(); k++ {
+						list.Set(k, list.Get(k+1))
+					}
+					list.Truncate(list.Len() - 1)
+					return true
+				}
+			}
+			for j := 0; j < list.Len(); j++ {
+				if v, ok := reduceValue(fd, list.Get(j), n); ok {
+					list.Set(j, v)
+					return true
+				}
+			}
+		case fd.IsMap():
+			mp := msg.Mutable(fd).Map()
+			var keys []protoreflect.MapKey
+			mp.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys = append(keys, k)
+				return true
+			})
+			sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface()) })
+			for _, k := range keys {
+				if take() {
+					mp.Clear(k)
+					return true
+				}
+			}
+			for _, k := range keys {
+				if v, ok := reduceValue(fd.MapValue(), mp.Get(k), n); ok {
+					mp.Set(k, v)
+					return true
+				}
+			}
+		default:
+			if v, ok := reduceValue(fd, msg.Get(fd), n); ok {
+				msg.Set(fd, v)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reduceValue applies the n-th reduction inside a single value: a reduction
+// of a nested message, or halving or trimming a string or bytes.
+func reduceValue(fd protoreflect.FieldDescriptor, v protoreflect.Value, n *int) (protoreflect.Value, bool) {
+	take := func() bool {
+		if *n == 0 {
+			return true
+		}
+		*n--
+		return false
+	}
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if reduce(v.Message(), n) {
+			return v, true
+		}
+	case protoreflect.StringKind:
+		r := []rune(v.String())
+		if len(r) > 1 && take() {
+			return protoreflect.ValueOfString(string(r[:len(r)/2])), true
+		}
+		if len(r) > 0 && take() {
+			return protoreflect.ValueOfString(string(r[:len(r)-1])), true
+		}
+	case protoreflect.BytesKind:
+		b := v.Bytes()
+		if len(b) > 1 && take() {
+			return protoreflect.ValueOfBytes(b[:len(b)/2]), true
+		}
+		if len(b) > 0 && take() {
+			return protoreflect.ValueOfBytes(b[:len(b)-1]), true
+		}
+	}
+	return v, false
+}
+
+// shrinkBytes removes ever smaller chunks of data while the failure
+// reproduces, for vectors that do not decode.
+func (m *minimizer) shrinkBytes(data []byte) ([]byte, error) {
+	for chunk := len(data) / 2; chunk >= 1; chunk /= 2 {
+		for start := 0; start < len(data); {
+			end := min(start+chunk, len(data))
+			candidate := append(append([]byte(nil), data[:start]...), data[end:]...)
+			ok, err := m.reproduces(candidate)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				data = candidate
+			} else {
+				start += chunk
+			}
+		}
+	}
+	return data, nil
+}
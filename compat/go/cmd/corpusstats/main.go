@@ -0,0 +1,134 @@
+// Command corpusstats reports, as JSON, how a corpus exercises the encoding
+// space: per case its encoded size, field count, maximum nesting depth,
+// wire-type histogram and varint length distribution, with totals per file
+// and overall.
+//
+//	corpusstats ../testdata/go
+//	corpusstats -o stats.json ../testdata/zig/map3.bin
+//
+// Arguments are corpus files or directories of them. Cases are walked with
+// the schema of the generator case of the same name when there is one, so
+// nested and packed fields are recognized; other cases fall back to parsing
+// length-delimited fields as messages when they look like one.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"compat/testcases"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type report struct {
+	Files []fileStats          `json:"files"`
+	Total *testcases.WireStats `json:"total"`
+}
+
+type fileStats struct {
+	Path  string               `json:"path"`
+	Cases []caseStats          `json:"cases"`
+	Total *testcases.WireStats `json:"total"`
+}
+
+type caseStats struct {
+	Name string `json:"name"`
+	// Type is the message type the case was walked as, if known.
+	Type string `json:"type,omitempty"`
+	*testcases.WireStats
+}
+
+func main() {
+	out := flag.String("o", "", "write the report here instead of stdout")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: corpusstats [-o out.json] corpus-file-or-dir...")
+		os.Exit(2)
+	}
+
+	var paths []string
+	for _, arg := range flag.Args() {
+		matches, err := corpusFiles(arg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "corpusstats: %v\n", err)
+			os.Exit(1)
+		}
+		paths = append(paths, matches...)
+	}
+
+	r := report{Total: testcases.NewWireStats(nil, nil)}
+	for _, path := range paths {
+		fs, err := statFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "corpusstats: %v\n", err)
+			os.Exit(1)
+		}
+		r.Files = append(r.Files, fs)
+		r.Total.Add(fs.Total)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "corpusstats: %v\n", err)
+		os.Exit(1)
+	}
+	data = append(data, '\n')
+	if *out == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "corpusstats: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// corpusFiles expands a directory into the corpus files directly inside it.
+func corpusFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	var files []string
+	for _, pattern := range []string{"*.bin", "*.bin.gz"} {
+		matches, err := filepath.Glob(filepath.Join(path, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+func statFile(path string) (fileStats, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fileStats{}, err
+	}
+	cases, err := testcases.ReadTestCases(data)
+	if err != nil {
+		return fileStats{}, fmt.Errorf("%s: %w", path, err)
+	}
+	category := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), ".gz"), ".bin")
+	fs := fileStats{Path: path, Cases: []caseStats{}, Total: testcases.NewWireStats(nil, nil)}
+	for _, tc := range cases {
+		var md protoreflect.MessageDescriptor
+		cs := caseStats{Name: tc.Name}
+		if want, ok := testcases.Expected(category + "/" + tc.Name); ok {
+			md = want.ProtoReflect().Descriptor()
+			cs.Type = string(md.FullName())
+		}
+		cs.WireStats = testcases.NewWireStats(tc.Data, md)
+		fs.Cases = append(fs.Cases, cs)
+		fs.Total.Add(cs.WireStats)
+	}
+	return fs, nil
+}
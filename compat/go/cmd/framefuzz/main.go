@@ -0,0 +1,251 @@
+// Command framefuzz attacks an RPC server's frame parser. It starts the server
+// once per case, writes a malformed frame sequence to its stdin, closes it,
+// and requires the server to answer only with well-formed frames and then
+// exit cleanly. Hangs, crashes and garbled output are failures.
+//
+// Any binary that speaks the pipe protocol on stdin/stdout can be targeted:
+//
+//	framefuzz -server ./rpcserver
+//	framefuzz -server ./zig-out/bin/rpc-server -- -flag
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"compat/pb"
+	"compat/pbutil"
+	"compat/randmsg"
+	"compat/rpcproto"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type fuzzCase struct {
+	name  string
+	input []byte
+}
+
+func main() {
+	server := flag.String("server", "", "server binary to attack (arguments after -- are passed to it)")
+	random := flag.Int("random", 200, "number of random cases to add to the fixed corpus")
+	seed := flag.Uint64("seed", 1, "seed for the random cases")
+	timeout := flag.Duration("timeout", 5*time.Second, "how long the server may take to exit after its input closes")
+	flag.Parse()
+	if *server == "" {
+		fmt.Fprintln(os.Stderr, "framefuzz: -server is required")
+		os.Exit(2)
+	}
+
+	cases := fixedCases()
+	rng := rand.New(rand.NewPCG(*seed, 0))
+	for i := 0; i < *random; i++ {
+		cases = append(cases, randomCase(rng, i))
+	}
+
+	failures := 0
+	for _, c := range cases {
+		if err := run(*server, flag.Args(), c.input, *timeout); err != nil {
+			fmt.Printf("FAIL %s: %v\n", c.name, err)
+			fmt.Printf("  input: %s\n", hex.EncodeToString(c.input))
+			failures++
+		}
+	}
+	fmt.Printf("framefuzz: %d cases, %d failures\n", len(cases), failures)
+	if failures > 0 {
+		os.Exit(1)
+	}
+}
+
+func frame(frameType byte, payload []byte) []byte {
+	var buf bytes.Buffer
+	rpcproto.WriteFrame(&buf, frameType, payload)
+	return buf.Bytes()
+}
+
+func header(frameType byte, n uint32) []byte {
+	return binary.BigEndian.AppendUint32([]byte{frameType}, n)
+}
+
+func callPayload(method string, req []byte) []byte {
+	buf := binary.BigEndian.AppendUint32(nil, uint32(len(method)))
+	return append(append(buf, method...), req...)
+}
+
+func join(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+// methods the servers implement, for garbage request bodies.
+var methods = []string{
+	"/UnaryService/Ping",
+	"/UnaryService/GetItem",
+	"/UnaryService/Health",
+	"/UnaryService/Echo",
+	"/StreamingService/UnaryCall",
+	"/StreamingService/ServerSide",
+	"/StreamingService/ClientSide",
+	"/StreamingService/Bidirectional",
+}
+
+// requests holds the request type of each method, from which random cases
+// start with a well-formed body.
+var requests = map[string]proto.Message{
+	"/UnaryService/Ping":              &pb.PingRequest{},
+	"/UnaryService/GetItem":           &pb.GetItemRequest{},
+	"/UnaryService/Health":            &pb.HealthRequest{},
+	"/UnaryService/Echo":              &pb.EchoMessage{},
+	"/StreamingService/UnaryCall":     &pb.StreamRequest{},
+	"/StreamingService/ServerSide":    &pb.StreamRequest{},
+	"/StreamingService/ClientSide":    &pb.UploadChunk{},
+	"/StreamingService/Bidirectional": &pb.ChatMessage{},
+}
+
+func fixedCases() []fuzzCase {
+	cases := []fuzzCase{
+		{"empty input", nil},
+		{"frame type 0x00", frame(0x00, nil)},
+		{"frame type 0xff", frame(0xff, []byte("junk"))},
+		{"truncated header 1 byte", []byte{rpcproto.FrameCall}},
+		{"truncated header 4 bytes", header(rpcproto.FrameCall, 10)[:4]},
+		{"truncated payload", join(header(rpcproto.FrameCall, 100), []byte("short"))},
+		{"length 0xffffffff", header(rpcproto.FrameCall, 0xffffffff)},
+		{"length over limit", join(header(rpcproto.FrameStreamMsg, rpcproto.MaxPayloadSize+1), make([]byte, 64))},
+		{"CALL empty payload", frame(rpcproto.FrameCall, nil)},
+		{"CALL method length past end", frame(rpcproto.FrameCall, callPayload("/UnaryService/Ping", nil)[:8])},
+		{"CALL method length 0xffffffff", frame(rpcproto.FrameCall, []byte{0xff, 0xff, 0xff, 0xff, 'x'})},
+		{"CALL empty method", frame(rpcproto.FrameCall, callPayload("", nil))},
+		{"CALL unknown method", frame(rpcproto.FrameCall, callPayload("/Nope/Nope", nil))},
+		{"STREAM_MSG without call", frame(rpcproto.FrameStreamMsg, []byte{0x0a, 0x01, 'x'})},
+		{"STREAM_END without call", frame(rpcproto.FrameStreamEnd, nil)},
+		{"RESPONSE from client", frame(rpcproto.FrameResponse, nil)},
+		{"ERROR from client", frame(rpcproto.FrameError, []byte("boom"))},
+		{"STREAM_END twice in client stream", join(
+			frame(rpcproto.FrameCall, callPayload("/StreamingService/ClientSide", nil)),
+			frame(rpcproto.FrameStreamEnd, nil),
+			frame(rpcproto.FrameStreamEnd, nil))},
+		{"CALL inside client stream", join(
+			frame(rpcproto.FrameCall, callPayload("/StreamingService/ClientSide", nil)),
+			frame(rpcproto.FrameCall, callPayload("/UnaryService/Ping", nil)))},
+		{"client stream cut off", frame(rpcproto.FrameCall, callPayload("/StreamingService/Bidirectional", nil))},
+	}
+	garbage := [][]byte{
+		{0xff},       // truncated varint tag
+		{0x0a, 0x7f}, // length past end
+		{0x08, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01}, // overlong varint
+		{0x0f},       // wire type 7
+		{0x0b},       // unterminated group
+		{0x00, 0x00}, // field number 0
+	}
+	for _, m := range methods {
+		for i, g := range garbage {
+			cases = append(cases, fuzzCase{
+				name:  fmt.Sprintf("CALL %s garbage request %d", m, i),
+				input: frame(rpcproto.FrameCall, callPayload(m, g)),
+			})
+		}
+	}
+	return cases
+}
+
+// randomCase mutates a valid exchange: it flips, inserts, drops and
+// truncates bytes in a well-formed CALL sequence with a random request body,
+// or emits raw noise.
+func randomCase(rng *rand.Rand, i int) fuzzCase {
+	name := fmt.Sprintf("random %d", i)
+	if rng.IntN(8) == 0 {
+		noise := make([]byte, rng.IntN(64))
+		for j := range noise {
+			noise[j] = byte(rng.Uint32())
+		}
+		return fuzzCase{name, noise}
+	}
+	m := methods[rng.IntN(len(methods))]
+	req := requests[m].ProtoReflect().New()
+	randmsg.Fill(rng, req, randmsg.Default)
+	body, _ := pbutil.Marshal(req.Interface())
+	input := frame(rpcproto.FrameCall, callPayload(m, body))
+	for n := rng.IntN(3); n > 0; n-- {
+		input = append(input, frame(rpcproto.FrameStreamMsg, []byte{0x0a, 0x01, 'x'})...)
+	}
+	input = append(input, frame(rpcproto.FrameStreamEnd, nil)...)
+	for n := 1 + rng.IntN(3); n > 0; n-- {
+		pos := rng.IntN(len(input))
+		switch rng.IntN(4) {
+		case 0:
+			input[pos] ^= byte(1 << rng.IntN(8))
+		case 1:
+			input = append(input[:pos], append([]byte{byte(rng.Uint32())}, input[pos:]...)...)
+		case 2:
+			input = append(input[:pos], input[pos+1:]...)
+		case 3:
+			input = input[:pos]
+		}
+		if len(input) == 0 {
+			break
+		}
+	}
+	return fuzzCase{name, input}
+}
+
+// run feeds input to a fresh server and checks its behavior.
+func run(server string, args []string, input []byte, timeout time.Duration) error {
+	cmd := exec.Command(server, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	var waitErr error
+	select {
+	case waitErr = <-done:
+	case <-time.After(timeout):
+		cmd.Process.Kill()
+		<-done
+		return fmt.Errorf("server still running %v after its input closed", timeout)
+	}
+
+	var exit *exec.ExitError
+	if errors.As(waitErr, &exit) {
+		if status, ok := exit.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return fmt.Errorf("server killed by %v\n  stderr: %s", status.Signal(), bytes.TrimSpace(stderr.Bytes()))
+		}
+		// Exit status 1 is a clean refusal; a Go panic exits with 2.
+		if exit.ExitCode() != 1 {
+			return fmt.Errorf("server exited with status %d\n  stderr: %s", exit.ExitCode(), bytes.TrimSpace(stderr.Bytes()))
+		}
+	} else if waitErr != nil {
+		return waitErr
+	}
+
+	out := bytes.NewReader(stdout.Bytes())
+	for {
+		f, err := rpcproto.ReadFrame(out)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("malformed output: %w", err)
+		}
+		switch f.Type {
+		case rpcproto.FrameResponse, rpcproto.FrameStreamMsg, rpcproto.FrameStreamEnd, rpcproto.FrameError:
+		default:
+			return fmt.Errorf("unexpected output frame type 0x%02x", f.Type)
+		}
+	}
+}
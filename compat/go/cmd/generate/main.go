@@ -1,32 +1,46 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"errors"
+	"flag"
 	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
+	"runtime"
 
 	"compat/testcases"
 )
 
-type generator struct {
-	name  string
-	cases []testcases.TestCase
-}
-
 func main() {
-	generators := []generator{
-		{"scalar3", testcases.GenerateScalar3()},
-		{"nested3", testcases.GenerateNested3()},
-		{"enum3", testcases.GenerateEnum3()},
-		{"oneof3", testcases.GenerateOneof3()},
-		{"repeated3", testcases.GenerateRepeated3()},
-		{"map3", testcases.GenerateMap3()},
-		{"optional3", testcases.GenerateOptional3()},
-		{"edge3", testcases.GenerateEdge3()},
-		{"scalar2", testcases.GenerateScalar2()},
-		{"required2", testcases.GenerateRequired2()},
-		{"acp", testcases.GenerateAcp()},
+	profileName := flag.String("profile", testcases.DefaultProfile, "corpus size: smoke, standard, extended or stress")
+	compress := flag.Bool("compress", false, "write gzip-compressed .bin.gz corpus files")
+	index := flag.Bool("index", false, "write corpus files in the versioned format with a case index, for looking up single cases")
+	delimited := flag.Bool("delimited", false, "also write each corpus as a varint-delimited (protodelim) "+testcases.DelimitedExt+" stream (default from -profile)")
+	mutate := flag.Int("mutate", 0, "derive this many mutated vectors per case into the mutated/ subdirectory (default from -profile)")
+	mutateSeed := flag.Uint64("mutate-seed", 1, "seed for -mutate; each category's mutants depend only on it and the category name")
+	jobs := flag.Int("jobs", runtime.GOMAXPROCS(0), "number of categories to generate at once")
+	archive := flag.Int("archive", 0, "also pin the generated corpus as archive version N under v<N>/; an existing archive is never rewritten")
+	flag.Parse()
+
+	profile, err := testcases.LookupProfile(*profileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "generate: %v\n", err)
+		os.Exit(2)
+	}
+	// Flags given explicitly override the profile.
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if !set["delimited"] {
+		*delimited = profile.Delimited
+	}
+	if !set["mutate"] {
+		*mutate = profile.Mutants
 	}
 
 	outDir := filepath.Join("..", "testdata", "go")
@@ -35,22 +49,331 @@ func main() {
 		os.Exit(1)
 	}
 
-	for _, g := range generators {
-		var buf bytes.Buffer
-		for _, tc := range g.cases {
-			if err := testcases.WriteTestCase(&buf, tc.Name, tc.Msg); err != nil {
-				fmt.Fprintf(os.Stderr, "write %s/%s: %v\n", g.name, tc.Name, err)
-				os.Exit(1)
+	limits := testcases.DefaultLimits
+	manifest := &testcases.Manifest{Profile: profile.Name, Limits: &limits}
+	var included []testcases.Category
+	for _, g := range testcases.Categories() {
+		if profile.Includes(g.Name) {
+			included = append(included, g)
+			continue
+		}
+		// A corpus left from a larger profile would be validated as if this
+		// one had written it.
+		if err := removeCategory(outDir, g.Name); err != nil {
+			fmt.Fprintf(os.Stderr, "remove %s: %v\n", g.Name, err)
+			os.Exit(1)
+		}
+	}
+
+	// Categories are generated concurrently, but each one's files depend
+	// only on the category, so the output is the same for any -jobs; logs
+	// are printed in category order as each finishes.
+	opts := options{compress: *compress, index: *index, delimited: *delimited, mutate: *mutate, mutateSeed: *mutateSeed}
+	type result struct {
+		log   bytes.Buffer
+		entry testcases.ManifestFile
+		err   error
+		done  chan struct{}
+	}
+	results := make([]result, len(included))
+	next := make(chan int)
+	for i := range results {
+		results[i].done = make(chan struct{})
+	}
+	for range max(*jobs, 1) {
+		go func() {
+			for i := range next {
+				r := &results[i]
+				r.entry, r.err = generateCategory(outDir, included[i], opts, &r.log)
+				close(r.done)
 			}
+		}()
+	}
+	go func() {
+		for i := range included {
+			next <- i
+		}
+		close(next)
+	}()
+	for i := range results {
+		r := &results[i]
+		<-r.done
+		os.Stdout.Write(r.log.Bytes())
+		if r.err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", r.err)
+			os.Exit(1)
 		}
+		manifest.Files = append(manifest.Files, r.entry)
+	}
+
+	if err := writeDescriptorSets(outDir); err != nil {
+		fmt.Fprintf(os.Stderr, "write descriptor sets: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := testcases.WriteManifest(outDir, manifest); err != nil {
+		fmt.Fprintf(os.Stderr, "write manifest: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("wrote %s (%s profile, %d files)\n", filepath.Join(outDir, testcases.ManifestName), profile.Name, len(manifest.Files))
 
-		path := filepath.Join(outDir, g.name+".bin")
-		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
-			fmt.Fprintf(os.Stderr, "write file %s: %v\n", path, err)
+	if *archive != 0 {
+		if err := testcases.WriteArchive(outDir, *archive, manifest); err != nil {
+			fmt.Fprintf(os.Stderr, "archive: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Printf("wrote %s (%d bytes, %d cases)\n", path, buf.Len(), len(g.cases))
+		fmt.Printf("archived corpus as %s\n", testcases.ArchiveDir(outDir, *archive))
 	}
 
 	fmt.Println("All Go test vectors generated.")
 }
+
+// options are the flags that shape a category's files.
+type options struct {
+	compress   bool
+	index      bool
+	delimited  bool
+	mutate     int
+	mutateSeed uint64
+}
+
+// generateCategory writes every file of the category to dir, describing
+// each on log, and returns the manifest entry for its corpus. It shares
+// nothing with other categories, so categories can be generated in
+// parallel.
+func generateCategory(dir string, g testcases.Category, opts options, log io.Writer) (testcases.ManifestFile, error) {
+	file, stale := g.Name+".bin", g.Name+".bin.gz"
+	if opts.compress {
+		file, stale = stale, file
+	}
+
+	path := filepath.Join(dir, file)
+	entry, err := testcases.WriteCorpusFile(dir, file, g, opts.index)
+	if err != nil {
+		return entry, fmt.Errorf("write file %s: %w", path, err)
+	}
+	// Drop the other variant so validators never pick up a stale corpus.
+	if err := os.Remove(filepath.Join(dir, stale)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return entry, fmt.Errorf("remove stale %s: %w", stale, err)
+	}
+	fmt.Fprintf(log, "wrote %s (%d bytes, %d cases)\n", path, entry.Size, len(entry.Cases))
+
+	if testcases.HasJSON(g.Name) {
+		if err := writeJSON(dir, g, log); err != nil {
+			return entry, fmt.Errorf("write json %s: %w", g.Name, err)
+		}
+	}
+
+	if len(testcases.Rejects(g.Name)) > 0 {
+		if err := writeRejects(dir, g, log); err != nil {
+			return entry, fmt.Errorf("write rejects %s: %w", g.Name, err)
+		}
+	}
+
+	if len(testcases.OverLimit(g.Name)) > 0 {
+		if err := writeOverLimit(dir, g, log); err != nil {
+			return entry, fmt.Errorf("write over-limit %s: %w", g.Name, err)
+		}
+	}
+
+	if opts.delimited {
+		if err := writeDelimited(dir, g, log); err != nil {
+			return entry, fmt.Errorf("write delimited %s: %w", g.Name, err)
+		}
+	}
+
+	if opts.mutate > 0 {
+		h := fnv.New64a()
+		h.Write([]byte(g.Name))
+		rng := rand.New(rand.NewPCG(opts.mutateSeed, h.Sum64()))
+		if err := writeMutants(dir, g, rng, opts.mutate, log); err != nil {
+			return entry, fmt.Errorf("write mutants %s: %w", g.Name, err)
+		}
+	}
+	return entry, nil
+}
+
+// removeCategory deletes every file generate may have written for the
+// category in dir.
+func removeCategory(dir, name string) error {
+	for _, path := range []string{
+		name + ".bin",
+		name + ".bin.gz",
+		name + testcases.DelimitedExt,
+		filepath.Join(testcases.JSONDir, name+".bin"),
+		filepath.Join(testcases.RejectDir, name+".bin"),
+		filepath.Join(testcases.LimitsDir, name+".bin"),
+		filepath.Join("mutated", name+".bin"),
+	} {
+		if err := os.Remove(filepath.Join(dir, path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDelimited writes the category's cases, in case order, as a
+// protodelim stream so tools from other ecosystems can read the corpus.
+func writeDelimited(dir string, c testcases.Category, log io.Writer) error {
+	path := filepath.Join(dir, c.Name+testcases.DelimitedExt)
+	f, err := create(path)
+	if err != nil {
+		return err
+	}
+	n, err := testcases.WriteDelimitedCorpus(f, c)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(log, "wrote %s (%d bytes, %d messages)\n", path, f.n, n)
+	return nil
+}
+
+// writeJSON writes the category's JSON corpus to json/<category>.bin.
+func writeJSON(dir string, c testcases.Category, log io.Writer) error {
+	dir = filepath.Join(dir, testcases.JSONDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, n, err := testcases.BuildJSONCorpus(c)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, c.Name+".bin")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(log, "wrote %s (%d bytes, %d cases)\n", path, len(data), n)
+	return nil
+}
+
+// writeRejects writes the category's malformed vectors to
+// reject/<category>.bin.
+func writeRejects(dir string, c testcases.Category, log io.Writer) error {
+	dir = filepath.Join(dir, testcases.RejectDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, n, err := testcases.BuildRejectCorpus(c.Name)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, c.Name+".bin")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(log, "wrote %s (%d bytes, %d cases)\n", path, len(data), n)
+	return nil
+}
+
+// writeOverLimit writes the category's over-limit vectors to
+// limits/<category>.bin.
+func writeOverLimit(dir string, c testcases.Category, log io.Writer) error {
+	dir = filepath.Join(dir, testcases.LimitsDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, n, err := testcases.BuildOverLimitCorpus(c.Name)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, c.Name+".bin")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(log, "wrote %s (%d bytes, %d cases)\n", path, len(data), n)
+	return nil
+}
+
+// writeDescriptorSets writes the descriptor sets of the schemas whose
+// declarations other generators' handling is compared with Go's.
+func writeDescriptorSets(dir string) error {
+	for _, ds := range testcases.DescriptorSets {
+		data, err := ds.Build()
+		if err != nil {
+			return fmt.Errorf("%s: %w", ds.Name, err)
+		}
+		path := filepath.Join(dir, ds.Name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("wrote %s (%d bytes)\n", path, len(data))
+	}
+	return nil
+}
+
+// writeMutants derives n vectors from each case of the category with
+// testcases.Mutate and frames them into mutated/<category>.bin. Each name
+// carries its must-decode or may-fail tag.
+func writeMutants(dir string, c testcases.Category, rng *rand.Rand, n int, log io.Writer) error {
+	dir = filepath.Join(dir, "mutated")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, c.Name+".bin")
+	f, err := create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := testcases.WriteCorpusHeader(f); err != nil {
+		return err
+	}
+	count, must := 0, 0
+	for _, tc := range c.Generate() {
+		mutants, err := testcases.Mutate(rng, tc, n)
+		if err != nil {
+			return err
+		}
+		for _, m := range mutants {
+			if err := testcases.WriteTestCaseRaw(f, m.Name, m.Data); err != nil {
+				return err
+			}
+			if testcases.MustDecode(m.Name) {
+				must++
+			}
+		}
+		count += len(mutants)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(log, "wrote %s (%d bytes, %d mutants, %d must decode)\n", path, f.n, count, must)
+	return nil
+}
+
+// streamFile buffers writes to a file.
+type streamFile struct {
+	*bufio.Writer
+	f *os.File
+	n int64 // size of the file, once closed
+}
+
+// create opens path for streaming to.
+func create(path string) (*streamFile, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &streamFile{Writer: bufio.NewWriterSize(f, 64<<10), f: f}, nil
+}
+
+// Close flushes and closes the file. Only the first call does anything, so
+// it can be deferred as well as checked.
+func (sf *streamFile) Close() error {
+	if sf.f == nil {
+		return nil
+	}
+	err := sf.Flush()
+	if err == nil {
+		sf.n, err = sf.f.Seek(0, io.SeekCurrent)
+	}
+	if cerr := sf.f.Close(); err == nil {
+		err = cerr
+	}
+	sf.f = nil
+	return err
+}
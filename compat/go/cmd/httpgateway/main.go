@@ -0,0 +1,92 @@
+// Command httpgateway serves UnaryService as REST+JSON endpoints, following
+// the HTTP rules in options/gateway3.proto, and makes each request a unary
+// call on a pipe RPC server:
+//
+//	httpgateway -backend tcp:127.0.0.1:9000
+//	httpgateway -listen 127.0.0.1:8080 ./zig-out/bin/rpc-server
+//
+// With -backend the gateway connects to a listening server; otherwise it
+// starts the command given as arguments and talks to it over stdin/stdout.
+// Calls share one connection and run one at a time.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"compat/httpgatewaylib"
+	"compat/pb"
+	"compat/rpcproto"
+)
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:8080", "HTTP address to serve on")
+	backendAddr := flag.String("backend", "", "connect to a server socket (unix:/path, [tcp:]host:port or ws:host:port[/path]) instead of starting the command given as arguments")
+	flag.Parse()
+	if (*backendAddr == "") == (flag.NArg() == 0) {
+		fmt.Fprintln(os.Stderr, "httpgateway: give either -backend or a server command")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var rw io.ReadWriter
+	if *backendAddr != "" {
+		conn, err := rpcproto.Dial(ctx, *backendAddr)
+		if err != nil {
+			fatal(err)
+		}
+		defer conn.Close()
+		rw = conn
+	} else {
+		cmd := exec.Command(flag.Arg(0), flag.Args()[1:]...)
+		cmd.Stderr = os.Stderr
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			fatal(err)
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			fatal(err)
+		}
+		if err := cmd.Start(); err != nil {
+			fatal(err)
+		}
+		defer func() {
+			stdin.Close()
+			cmd.Wait()
+		}()
+		rw = rpcproto.JoinReadWriter(stdout, stdin)
+	}
+
+	backend := httpgatewaylib.NewBackend(rw)
+	defer backend.Close()
+	gw, err := httpgatewaylib.New(pb.File_gateway3_proto.Services().ByName("UnaryService"), backend)
+	if err != nil {
+		fatal(err)
+	}
+
+	srv := &http.Server{Addr: *listen, Handler: gw}
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+	fmt.Fprintf(os.Stderr, "httpgateway: listening on %s\n", *listen)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "httpgateway: %v\n", err)
+	os.Exit(1)
+}
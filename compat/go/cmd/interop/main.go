@@ -0,0 +1,492 @@
+// Command interop runs the RPC suites across every client/server pairing and
+// transport and prints a matrix of the results. Each cell runs one client
+// against a fresh server, connected by pipes on stdin/stdout, over TCP or
+// over WebSocket, and passes if the client exits cleanly (and, over pipes, so
+// does the server).
+//
+//	interop -transports stdio,tcp,ws -results interop.json
+//
+// The Go binaries are built from this module unless -go-server/-go-client
+// name existing ones. Other implementations must accept the same flags:
+// servers -listen tcp:ADDR or ws:ADDR, clients -connect with the same
+// address, -suites LIST and, when -codec is not binary, -codec NAME.
+//
+// compat/build.zig builds no Zig server or client yet, so the Zig pairings
+// run only with binaries named by -zig-server and -zig-client. A pairing
+// whose binary is not given is reported as skipped, and a warning after the
+// matrix lists every such pairing. With -require-zig, a missing Zig server
+// or client fails the run before any cell runs.
+//
+// With -chaos N, each TCP or WebSocket cell instead kills its server N times
+// at a random point in the suite, seeded by -chaos-seed; see runChaos.
+//
+//	interop -chaos 5 -chaos-seed 7 -transports tcp -suites core,flow
+//
+// cmd/compatrun builds the Zig binaries and runs interop with them as part
+// of the whole cross-language run.
+//
+// A cell still running after -timeout is reported as hung. Its processes
+// are sent SIGQUIT, so the Go binaries dump their goroutines, and the whole
+// of their stderr goes into the report instead of its tail.
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"compat/pb"
+	"compat/testcases"
+)
+
+type impl struct {
+	name           string
+	server, client []string
+}
+
+// chaos configures -chaos runs: how many times each server is killed, the
+// longest a client runs before that, and how long it then has to exit.
+type chaos struct {
+	rounds        int
+	window, grace time.Duration
+	rng           *rand.Rand
+}
+
+type cell struct {
+	client, server, transport string
+	status                    string // PASS, FAIL or SKIP
+	detail                    string
+	d                         time.Duration
+}
+
+func main() {
+	goServer := flag.String("go-server", "", "Go server command (default: build ./cmd/rpcserver)")
+	goClient := flag.String("go-client", "", "Go client command (default: build ./cmd/rpcclient)")
+	zigServer := flag.String("zig-server", "", "Zig server command (empty: skip its cells)")
+	zigClient := flag.String("zig-client", "", "Zig client command (empty: skip its cells)")
+	suites := flag.String("suites", "core", "suites every client runs")
+	codec := flag.String("codec", "binary", "payload codec every client asks for: binary, json or text")
+	transports := flag.String("transports", "stdio,tcp", "comma-separated transports: stdio, tcp, ws")
+	timeout := flag.Duration("timeout", 60*time.Second, "time limit per cell")
+	chaosRounds := flag.Int("chaos", 0, "kill each TCP or WebSocket server this many times mid-suite and check the client copes (0 = off)")
+	chaosSeed := flag.Uint64("chaos-seed", 1, "seed for the -chaos kill times")
+	chaosWindow := flag.Duration("chaos-window", 200*time.Millisecond, "longest a client runs before -chaos kills its server")
+	chaosGrace := flag.Duration("chaos-grace", 10*time.Second, "how long a client has to exit once -chaos killed its server")
+	requireZig := flag.Bool("require-zig", false, "fail unless -zig-server and -zig-client are both given, so every pairing runs")
+	resultsPath := flag.String("results", "", "also write the cells to this file as a CompatResults message (proto3 JSON if the name ends in .json)")
+	flag.Parse()
+	if *chaosRounds > 0 && *chaosWindow <= 0 {
+		fmt.Fprintln(os.Stderr, "interop: -chaos-window must be positive")
+		os.Exit(2)
+	}
+	ch := &chaos{*chaosRounds, *chaosWindow, *chaosGrace, rand.New(rand.NewPCG(*chaosSeed, 0))}
+	if *requireZig && (*zigServer == "" || *zigClient == "") {
+		fatal(errors.New("-require-zig: -zig-server and -zig-client must both be given"))
+	}
+
+	if *goServer == "" || *goClient == "" {
+		dir, err := os.MkdirTemp("", "interop")
+		if err != nil {
+			fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		if *goServer == "" {
+			*goServer = filepath.Join(dir, "rpcserver")
+			build(*goServer, "./cmd/rpcserver")
+		}
+		if *goClient == "" {
+			*goClient = filepath.Join(dir, "rpcclient")
+			build(*goClient, "./cmd/rpcclient")
+		}
+	}
+	res := testcases.NewResults(strings.Join(append([]string{"interop"}, os.Args[1:]...), " "))
+	impls := []impl{
+		{"go", strings.Fields(*goServer), strings.Fields(*goClient)},
+		{"zig", strings.Fields(*zigServer), strings.Fields(*zigClient)},
+	}
+
+	var cells []cell
+	for _, c := range impls {
+		for _, s := range impls {
+			for _, t := range strings.Split(*transports, ",") {
+				cl := cell{client: c.name, server: s.name, transport: strings.TrimSpace(t)}
+				switch {
+				case len(c.client) == 0:
+					cl.status, cl.detail = "SKIP", "no "+c.name+" client"
+				case len(s.server) == 0:
+					cl.status, cl.detail = "SKIP", "no "+s.name+" server"
+				case ch.rounds > 0 && cl.transport == "stdio":
+					cl.status, cl.detail = "SKIP", "-chaos needs a tcp or ws transport"
+				default:
+					start := time.Now()
+					ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+					var err error
+					switch cl.transport {
+					case "stdio":
+						err = runStdio(ctx, s.server, withSuites(c.client, *suites, *codec))
+					case "tcp", "ws":
+						if ch.rounds > 0 {
+							err = runChaos(ctx, cl.transport, s.server, withSuites(c.client, *suites, *codec), ch)
+						} else {
+							err = runSocket(ctx, cl.transport, s.server, withSuites(c.client, *suites, *codec))
+						}
+					default:
+						err = fmt.Errorf("unknown transport %q", cl.transport)
+					}
+					cancel()
+					cl.d = time.Since(start)
+					cl.status = "PASS"
+					if err != nil {
+						cl.status, cl.detail = "FAIL", err.Error()
+						if ctx.Err() == context.DeadlineExceeded {
+							cl.detail = fmt.Sprintf("hung after %v: %v", *timeout, err)
+						}
+					}
+				}
+				cells = append(cells, cl)
+			}
+		}
+	}
+
+	failed := report(os.Stdout, cells)
+	if pairs := unrun(impls); len(pairs) > 0 {
+		fmt.Printf("\nWARNING: %d of %d client/server pairings did not run, for want of a binary: %s\n",
+			len(pairs), len(impls)*len(impls), strings.Join(pairs, ", "))
+	}
+	if *resultsPath != "" {
+		if err := testcases.WriteResults(*resultsPath, results(res, cells, *suites)); err != nil {
+			fatal(err)
+		}
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// unrun returns the client/server pairings of impls that cannot run because
+// one of their binaries was not given.
+func unrun(impls []impl) []string {
+	var pairs []string
+	for _, c := range impls {
+		for _, s := range impls {
+			if len(c.client) == 0 || len(s.server) == 0 {
+				pairs = append(pairs, c.name+" client/"+s.name+" server")
+			}
+		}
+	}
+	return pairs
+}
+
+// results records each cell as a case: the client and server pairing is
+// the direction, the transport the category and the suites the name.
+func results(res *testcases.Results, cells []cell, suites string) *pb.CompatResults {
+	for _, c := range cells {
+		status := pb.CaseStatus_CASE_STATUS_PASS
+		switch c.status {
+		case "FAIL":
+			status = pb.CaseStatus_CASE_STATUS_FAIL
+		case "SKIP":
+			status = pb.CaseStatus_CASE_STATUS_SKIP
+		}
+		res.Add(&pb.CaseResult{
+			Direction:     c.client + "-client-" + c.server + "-server",
+			Category:      c.transport,
+			Name:          suites,
+			Status:        status,
+			Detail:        c.detail,
+			DurationNanos: c.d.Nanoseconds(),
+		})
+	}
+	m := res.Finish()
+	m.Run.Implementation = "interop"
+	return m
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "interop: %v\n", err)
+	os.Exit(1)
+}
+
+func build(out, pkg string) {
+	cmd := exec.Command("go", "build", "-o", out, pkg)
+	if b, err := cmd.CombinedOutput(); err != nil {
+		fatal(fmt.Errorf("build %s: %v\n%s", pkg, err, b))
+	}
+}
+
+func withSuites(client []string, suites, codec string) []string {
+	args := append(append([]string(nil), client...), "-suites", suites)
+	if codec != "binary" {
+		args = append(args, "-codec", codec)
+	}
+	return args
+}
+
+// hangGrace is how long a process sent SIGQUIT by command has to dump its
+// goroutines and exit before it is killed.
+const hangGrace = 5 * time.Second
+
+// command is exec.CommandContext, except that when ctx expires the process
+// is sent SIGQUIT rather than killed, so that a Go binary dumps its
+// goroutines, and is only killed if it is still running hangGrace later.
+func command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Cancel = func() error { return cmd.Process.Signal(syscall.SIGQUIT) }
+	cmd.WaitDelay = hangGrace
+	return cmd
+}
+
+// runStdio connects the client's stdout to the server's stdin and back.
+func runStdio(ctx context.Context, server, client []string) error {
+	srv := command(ctx, server[0], server[1:]...)
+	cli := command(ctx, client[0], client[1:]...)
+	var srvErr, cliErr bytes.Buffer
+	srv.Stderr, cli.Stderr = &srvErr, &cliErr
+
+	var err error
+	if cli.Stdin, err = srv.StdoutPipe(); err != nil {
+		return err
+	}
+	if srv.Stdin, err = cli.StdoutPipe(); err != nil {
+		return err
+	}
+	if err := srv.Start(); err != nil {
+		return err
+	}
+	if err := cli.Start(); err != nil {
+		srv.Process.Kill()
+		srv.Wait()
+		return err
+	}
+	cliWait := cli.Wait()
+	srvWait := srv.Wait()
+	if cliWait != nil {
+		return fmt.Errorf("client: %v%s", cliWait, stderr(ctx, &cliErr))
+	}
+	if srvWait != nil {
+		return fmt.Errorf("server: %v%s", srvWait, stderr(ctx, &srvErr))
+	}
+	return nil
+}
+
+// runSocket starts the server listening on a free loopback port with the
+// given scheme, tcp or ws, runs the client against it and then stops the
+// server.
+func runSocket(ctx context.Context, scheme string, server, client []string) error {
+	addr, err := freeAddr()
+	if err != nil {
+		return err
+	}
+	return runSocketAt(ctx, scheme, addr, server, client)
+}
+
+func runSocketAt(ctx context.Context, scheme, addr string, server, client []string) error {
+	srv, err := startServer(ctx, scheme, addr, server)
+	if err != nil {
+		return err
+	}
+	defer stopServer(srv)
+
+	cli := command(ctx, client[0], append(append([]string(nil), client[1:]...), "-connect", scheme+":"+addr)...)
+	var cliErr bytes.Buffer
+	cli.Stderr = &cliErr
+	if err := cli.Run(); err != nil {
+		if ctx.Err() != nil {
+			// The server was sent SIGQUIT too; its dump says what it was
+			// doing while the client waited.
+			srv.Wait()
+			return fmt.Errorf("client: %v%s\n  server:%s", err, stderr(ctx, &cliErr), stderr(ctx, srv.Stderr.(*bytes.Buffer)))
+		}
+		return fmt.Errorf("client: %v%s", err, tail(&cliErr))
+	}
+	return nil
+}
+
+// startServer starts the server listening on addr and waits until it
+// accepts connections.
+func startServer(ctx context.Context, scheme, addr string, server []string) (*exec.Cmd, error) {
+	srv := command(ctx, server[0], append(append([]string(nil), server[1:]...), "-listen", scheme+":"+addr)...)
+	var srvErr bytes.Buffer
+	srv.Stderr = &srvErr
+	if err := srv.Start(); err != nil {
+		return nil, err
+	}
+	if err := waitListening(ctx, addr); err != nil {
+		stopServer(srv)
+		return nil, fmt.Errorf("server: %v%s", err, tail(&srvErr))
+	}
+	return srv, nil
+}
+
+func stopServer(srv *exec.Cmd) {
+	srv.Process.Kill()
+	srv.Wait()
+}
+
+// runChaos runs c.rounds rounds against servers on one loopback address. In
+// each, the server is killed after a random delay while the client runs its
+// suites; the client must then exit within c.grace, either having finished
+// or having reported the lost connection without crashing. The server is
+// then restarted on the same address and the client run again, and that
+// run must pass. The clients do not reconnect by themselves, so the second
+// run stands in for the first resuming. Failures name the round and the
+// delay, which with -chaos-seed reproduce it.
+func runChaos(ctx context.Context, scheme string, server, client []string, c *chaos) error {
+	addr, err := freeAddr()
+	if err != nil {
+		return err
+	}
+	for round := 1; round <= c.rounds; round++ {
+		delay := time.Duration(c.rng.Int64N(int64(c.window)))
+		if err := chaosRound(ctx, scheme, addr, server, client, delay, c.grace); err != nil {
+			return fmt.Errorf("chaos round %d, server killed after %v: %w", round, delay, err)
+		}
+		if err := runSocketAt(ctx, scheme, addr, server, client); err != nil {
+			return fmt.Errorf("chaos round %d, after restart: %w", round, err)
+		}
+	}
+	return nil
+}
+
+func chaosRound(ctx context.Context, scheme, addr string, server, client []string, delay, grace time.Duration) error {
+	srv, err := startServer(ctx, scheme, addr, server)
+	if err != nil {
+		return err
+	}
+	defer stopServer(srv)
+
+	cli := command(ctx, client[0], append(append([]string(nil), client[1:]...), "-connect", scheme+":"+addr)...)
+	var cliErr bytes.Buffer
+	cli.Stderr = &cliErr
+	if err := cli.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cli.Wait() }()
+
+	select {
+	case err := <-done:
+		// The suites finished before the kill.
+		if err != nil {
+			return fmt.Errorf("client, before the server was killed: %v%s", err, tail(&cliErr))
+		}
+		return nil
+	case <-time.After(delay):
+	}
+	srv.Process.Kill()
+
+	select {
+	case err = <-done:
+	case <-time.After(grace):
+		cli.Process.Kill()
+		<-done
+		return fmt.Errorf("client still running %v after its server was killed%s", grace, tail(&cliErr))
+	}
+	if err == nil {
+		return nil
+	}
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || !exit.Exited() || strings.Contains(cliErr.String(), "panic") {
+		return fmt.Errorf("client crashed when its server was killed: %v%s", err, tail(&cliErr))
+	}
+	return nil
+}
+
+func freeAddr() (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer ln.Close()
+	return ln.Addr().String(), nil
+}
+
+func waitListening(ctx context.Context, addr string) error {
+	for {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			return conn.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return errors.New("never started listening")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// stderr returns tail(b), or all of b once ctx has expired: the process was
+// then sent SIGQUIT, and a goroutine dump is no use cut short.
+func stderr(ctx context.Context, b *bytes.Buffer) string {
+	if ctx.Err() == nil {
+		return tail(b)
+	}
+	return "\n    " + strings.ReplaceAll(strings.TrimSpace(b.String()), "\n", "\n    ")
+}
+
+// tail returns the last lines of a process's stderr for a failure report.
+func tail(b *bytes.Buffer) string {
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) > 10 {
+		lines = lines[len(lines)-10:]
+	}
+	if len(lines) == 1 && lines[0] == "" {
+		return ""
+	}
+	return "\n    " + strings.Join(lines, "\n    ")
+}
+
+// report prints the matrix, clients down and server/transport across, then
+// the details of every failed cell, and returns the number of failures.
+func report(w io.Writer, cells []cell) int {
+	var cols []string
+	seen := map[string]bool{}
+	rows := []string{}
+	for _, c := range cells {
+		col := c.server + "/" + c.transport
+		if !seen[col] {
+			seen[col] = true
+			cols = append(cols, col)
+		}
+		if !seen["row "+c.client] {
+			seen["row "+c.client] = true
+			rows = append(rows, c.client)
+		}
+	}
+	fmt.Fprintf(w, "%-14s", "client\\server")
+	for _, col := range cols {
+		fmt.Fprintf(w, " %-10s", col)
+	}
+	fmt.Fprintln(w)
+	for _, row := range rows {
+		fmt.Fprintf(w, "%-14s", row)
+		for _, col := range cols {
+			for _, c := range cells {
+				if c.client == row && c.server+"/"+c.transport == col {
+					fmt.Fprintf(w, " %-10s", c.status)
+				}
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	failed := 0
+	for _, c := range cells {
+		if c.status == "FAIL" {
+			failed++
+			fmt.Fprintf(w, "\nFAIL %s client, %s server over %s: %s\n", c.client, c.server, c.transport, c.detail)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,300 @@
+// Command report renders CompatResults files, as written by validate
+// -results and by the Zig compat tests, as a markdown or HTML report for
+// posting with a release: the runs it was built from, a matrix of corpus
+// categories against implementations and directions, and every failing case
+// with a hex dump of where its bytes first differ from the reference.
+//
+//	report ../testdata/go/results.json ../testdata/zig/results.pb
+//	report -format html -o report.html results.json
+//
+// Files whose names end in .json are read as proto3 JSON, others as the
+// binary encoding.
+package main
+
+import (
+	"flag"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"compat/pb"
+	"compat/testcases"
+)
+
+// column is one implementation reading in one direction.
+type column struct {
+	Impl, Direction string
+}
+
+func (c column) String() string { return c.Impl + " " + c.Direction }
+
+// cell counts the cases of one category under one column. Expected
+// failures count as run but neither passed nor failed.
+type cell struct {
+	Pass, Fail, Skip, XFail int
+}
+
+func (c *cell) add(s pb.CaseStatus) {
+	switch s {
+	case pb.CaseStatus_CASE_STATUS_PASS:
+		c.Pass++
+	case pb.CaseStatus_CASE_STATUS_SKIP:
+		c.Skip++
+	case pb.CaseStatus_CASE_STATUS_XFAIL:
+		c.XFail++
+	default:
+		c.Fail++
+	}
+}
+
+func (c cell) String() string {
+	xfail := ""
+	if c.XFail > 0 {
+		xfail = fmt.Sprintf(" (%d xfail)", c.XFail)
+	}
+	switch run := c.Pass + c.Fail + c.XFail; {
+	case run == 0 && c.Skip == 0:
+		return "-"
+	case run == 0:
+		return "skipped"
+	case c.Fail > 0:
+		return fmt.Sprintf("%d/%d FAIL%s", c.Pass, run, xfail)
+	default:
+		return fmt.Sprintf("%d/%d%s", c.Pass, run, xfail)
+	}
+}
+
+type row struct {
+	Category string
+	Cells    []cell
+}
+
+// failure is a failing case with the implementation that ran it.
+type failure struct {
+	Impl string
+	*pb.CaseResult
+}
+
+type report struct {
+	Title    string
+	Runs     []*pb.RunMetadata
+	Columns  []column
+	Rows     []row
+	Total    row
+	Failures []failure
+}
+
+func main() {
+	format := flag.String("format", "markdown", "output format: markdown or html")
+	out := flag.String("o", "", "write the report here instead of stdout")
+	title := flag.String("title", "Protobuf compatibility report", "report title")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: report [-format markdown|html] [-o out] results-file...")
+		os.Exit(2)
+	}
+
+	var results []*pb.CompatResults
+	for _, path := range flag.Args() {
+		r, err := testcases.ReadResults(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "report: %v\n", err)
+			os.Exit(1)
+		}
+		results = append(results, r)
+	}
+	r := build(*title, results)
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "report: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+	var err error
+	switch *format {
+	case "markdown":
+		err = markdown.Execute(w, r)
+	case "html":
+		err = html.Execute(w, r)
+	default:
+		fmt.Fprintf(os.Stderr, "report: unknown -format %q\n", *format)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "report: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// build lays the cases of every run out as the report's matrix, with
+// categories and columns sorted by name, and collects the failures in the
+// order the runs recorded them.
+func build(title string, results []*pb.CompatResults) report {
+	r := report{Title: title}
+	cells := map[string]map[column]*cell{}
+	seen := map[column]bool{}
+	for _, res := range results {
+		impl := res.GetRun().GetImplementation()
+		r.Runs = append(r.Runs, res.GetRun())
+		for _, c := range res.GetCases() {
+			col := column{impl, c.GetDirection()}
+			if !seen[col] {
+				seen[col] = true
+				r.Columns = append(r.Columns, col)
+			}
+			if cells[c.GetCategory()] == nil {
+				cells[c.GetCategory()] = map[column]*cell{}
+			}
+			if cells[c.GetCategory()][col] == nil {
+				cells[c.GetCategory()][col] = &cell{}
+			}
+			cells[c.GetCategory()][col].add(c.GetStatus())
+			if c.GetStatus() == pb.CaseStatus_CASE_STATUS_FAIL {
+				r.Failures = append(r.Failures, failure{impl, c})
+			}
+		}
+	}
+	sort.Slice(r.Columns, func(i, j int) bool {
+		a, b := r.Columns[i], r.Columns[j]
+		return a.Impl < b.Impl || a.Impl == b.Impl && a.Direction < b.Direction
+	})
+
+	var categories []string
+	for name := range cells {
+		categories = append(categories, name)
+	}
+	sort.Strings(categories)
+	r.Total = row{Category: "total", Cells: make([]cell, len(r.Columns))}
+	for _, name := range categories {
+		rw := row{Category: name}
+		for i, col := range r.Columns {
+			var c cell
+			if p := cells[name][col]; p != nil {
+				c = *p
+			}
+			rw.Cells = append(rw.Cells, c)
+			r.Total.Cells[i].Pass += c.Pass
+			r.Total.Cells[i].Fail += c.Fail
+			r.Total.Cells[i].Skip += c.Skip
+			r.Total.Cells[i].XFail += c.XFail
+		}
+		r.Rows = append(r.Rows, rw)
+	}
+	return r
+}
+
+// hexDump formats data, which starts at offset start of its encoding, in
+// rows of 16 bytes labeled with their offsets.
+func hexDump(data []byte, start uint64) string {
+	var b strings.Builder
+	for i := 0; i < len(data); i += 16 {
+		fmt.Fprintf(&b, "%08x  % x\n", start+uint64(i), data[i:min(i+16, len(data))])
+	}
+	return b.String()
+}
+
+func caseName(c failure) string {
+	if c.GetName() == "" {
+		return c.GetCategory() + " (corpus file)"
+	}
+	return c.GetCategory() + "/" + c.GetName()
+}
+
+var funcs = map[string]any{
+	"hexdump":  hexDump,
+	"casename": caseName,
+	"started": func(m *pb.RunMetadata) string {
+		return time.Unix(0, m.GetStartUnixNanos()).UTC().Format(time.RFC3339)
+	},
+	"duration": func(ns int64) string {
+		return time.Duration(ns).Round(time.Millisecond).String()
+	},
+}
+
+var markdown = template.Must(template.New("markdown").Funcs(funcs).Parse(`# {{.Title}}
+
+## Runs
+
+| implementation | version | command | platform | started | duration |
+|---|---|---|---|---|---|
+{{range .Runs}}| {{.Implementation}} | {{.Version}} | ` + "`{{.Command}}`" + ` | {{.Os}}/{{.Arch}} | {{started .}} | {{duration .DurationNanos}} |
+{{end}}
+## Results
+
+Passing cases out of those run, per category and per implementation reading
+in each direction.
+
+| category |{{range .Columns}} {{.}} |{{end}}
+|---|{{range .Columns}}---|{{end}}
+{{range .Rows}}| {{.Category}} |{{range .Cells}} {{.}} |{{end}}
+{{end}}| **{{.Total.Category}}** |{{range .Total.Cells}} **{{.}}** |{{end}}
+
+## Failures
+{{if not .Failures}}
+None.
+{{end}}{{range .Failures}}
+### {{.Impl}} {{.Direction}}: {{casename .}}
+
+{{.Detail}}{{with .ErrorCategory}} (error category: {{.}}){{end}}
+{{with .Diff}}
+First difference at byte {{.Offset}}; want {{.WantLen}} bytes, got {{.GotLen}}.
+
+` + "```" + `
+want:
+{{hexdump .Want .WindowStart}}got:
+{{hexdump .Got .WindowStart}}` + "```" + `
+{{end}}{{end}}`))
+
+var html = htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; text-align: left; }
+td.fail { background: #fdd; }
+td.pass { background: #dfd; }
+pre { background: #f6f6f6; padding: 0.5em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+
+<h2>Runs</h2>
+<table>
+<tr><th>implementation</th><th>version</th><th>command</th><th>platform</th><th>started</th><th>duration</th></tr>
+{{range .Runs}}<tr><td>{{.Implementation}}</td><td>{{.Version}}</td><td><code>{{.Command}}</code></td><td>{{.Os}}/{{.Arch}}</td><td>{{started .}}</td><td>{{duration .DurationNanos}}</td></tr>
+{{end}}</table>
+
+<h2>Results</h2>
+<table>
+<tr><th>category</th>{{range .Columns}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr><td>{{.Category}}</td>{{range .Cells}}<td class="{{if .Fail}}fail{{else if .Pass}}pass{{end}}">{{.}}</td>{{end}}</tr>
+{{end}}<tr><th>{{.Total.Category}}</th>{{range .Total.Cells}}<th>{{.}}</th>{{end}}</tr>
+</table>
+
+<h2>Failures</h2>
+{{if not .Failures}}<p>None.</p>
+{{end}}{{range .Failures}}<details>
+<summary>{{.Impl}} {{.Direction}}: {{casename .}}</summary>
+<p>{{.Detail}}{{with .ErrorCategory}} (error category: {{.}}){{end}}</p>
+{{with .Diff}}<p>First difference at byte {{.Offset}}; want {{.WantLen}} bytes, got {{.GotLen}}.</p>
+<pre>want:
+{{hexdump .Want .WindowStart}}got:
+{{hexdump .Got .WindowStart}}</pre>
+{{end}}</details>
+{{end}}</body>
+</html>
+`))
@@ -1,319 +1,247 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"flag"
 	"fmt"
-	"io"
+	"net"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"compat/pb"
+	"compat/rpcclientlib"
 	"compat/rpcproto"
-
-	"google.golang.org/protobuf/proto"
+	"compat/rpctrace"
+	"compat/watchdog"
 )
 
 func main() {
-	r := os.Stdin
-	w := os.Stdout
-	failures := 0
-
-	// Test 1: Ping
-	failures += testPing(r, w)
-	// Test 2: GetItem
-	failures += testGetItem(r, w)
-	// Test 3: Health
-	failures += testHealth(r, w)
-	// Test 4: Echo
-	failures += testEcho(r, w)
-	// Test 5: ServerSide streaming
-	failures += testServerSide(r, w)
-	// Test 6: ClientSide streaming
-	failures += testClientSide(r, w)
-	// Test 7: Bidirectional streaming
-	failures += testBidirectional(r, w)
-
-	// Send shutdown
-	if err := rpcproto.WriteShutdown(w); err != nil {
-		fmt.Fprintf(os.Stderr, "rpcclient: write shutdown: %v\n", err)
-		os.Exit(1)
-	}
-
-	if failures > 0 {
-		fmt.Fprintf(os.Stderr, "rpcclient: %d test(s) failed\n", failures)
-		os.Exit(1)
+	suiteList := flag.String("suites", "core", "comma-separated test suites to run: core, flow, errors, limits, property, stats, any, unknown, acp, validate")
+	handshake := flag.Bool("handshake", false, "send HELLO before the first call (implied by the flow and limits suites)")
+	window := flag.Uint("window", uint(rpcclientlib.DefaultSettings.InitialWindow), "receive window in bytes advertised in HELLO (0 = unlimited)")
+	codecName := flag.String("codec", "binary", "payload codec asked for in HELLO: binary, json or text (anything but binary implies -handshake)")
+	sequence := flag.Bool("sequence", false, "ask the server to number frames after HELLO and check the numbers (implies -handshake)")
+	maxFrame := flag.Uint("max-frame", uint(rpcclientlib.DefaultSettings.MaxFrameSize), "largest frame payload in bytes advertised in HELLO (0 = protocol maximum)")
+	connect := flag.String("connect", "", "connect to a server socket (unix:/path, [tcp:]host:port or ws:host:port[/path]) instead of using stdin/stdout")
+	tlsCert := flag.String("tls-cert", "", "client certificate (PEM) to present for mutual TLS")
+	tlsKey := flag.String("tls-key", "", "private key (PEM) for -tls-cert")
+	tlsCA := flag.String("tls-ca", "", "connect over TLS, trusting the CAs in this file")
+	tracePath := flag.String("trace", "", "write a JSON-lines log of every frame to this file (- for stderr)")
+	statsPath := flag.String("stats", "", "at exit, write the client's own frame, byte and latency counters as JSON to this file (- for stderr)")
+	goldenPath := flag.String("golden", "", "record every frame, with its payload, to this golden transcript file")
+	replayPath := flag.String("replay", "", "instead of running suites, replay this golden transcript against the server and report responses that differ")
+	readDelay := flag.Duration("read-delay", time.Millisecond, "pause before handling each Firehose chunk, to simulate a slow reader")
+	seed := flag.Int64("seed", 0, "first seed for the property suite (0 = derive from the clock)")
+	iterations := flag.Int("iterations", 200, "random payloads per property test")
+	readTimeout := flag.Duration("read-timeout", 0, "give up, naming the frame it was waiting for, if the server sends nothing for this long (0 = no limit)")
+	rpcTimeout := flag.Duration("rpc-timeout", 30*time.Second, "exit, naming the method and dumping goroutines, if a call goes unanswered this long (0 = no limit)")
+	flag.Parse()
+
+	codec, ok := rpcproto.CodecByName(*codecName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "rpcclient: unknown codec %q\n", *codecName)
+		os.Exit(2)
+	}
+	if codec.ID() != rpcproto.CodecBinary || *sequence {
+		*handshake = true
+	}
+	var features uint32
+	if *sequence {
+		features |= rpcproto.FeatureSequence
+	}
+
+	var tests []rpcclientlib.Test
+	for _, name := range strings.Split(*suiteList, ",") {
+		name = strings.TrimSpace(name)
+		suite, ok := rpcclientlib.Suites[name]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "rpcclient: unknown suite %q\n", name)
+			os.Exit(2)
+		}
+		if suite.BinaryOnly && codec.ID() != rpcproto.CodecBinary {
+			fmt.Fprintf(os.Stderr, "rpcclient: suite %q needs the binary codec\n", name)
+			os.Exit(2)
+		}
+		if suite.Handshake {
+			*handshake = true
+		}
+		tests = append(tests, suite.Tests...)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	rw := rpcproto.JoinReadWriter(os.Stdin, os.Stdout)
+	useTLS := *tlsCert != "" || *tlsKey != "" || *tlsCA != ""
+	if useTLS && *connect == "" {
+		fmt.Fprintln(os.Stderr, "rpcclient: TLS needs -connect")
+		os.Exit(2)
+	}
+	if *connect != "" {
+		var conn net.Conn
+		var err error
+		if useTLS {
+			var cfg *tls.Config
+			if cfg, err = rpcproto.ClientTLS(*tlsCert, *tlsKey, *tlsCA); err == nil {
+				conn, err = rpcproto.DialTLS(ctx, *connect, cfg)
+			}
+		} else {
+			conn, err = rpcproto.Dial(ctx, *connect)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "rpcclient: %v\n", err)
+			os.Exit(1)
+		}
+		defer conn.Close()
+		rw = conn
+	}
+	c := rpcclientlib.NewClient(rw)
+	c.ReadDelay = *readDelay
+	c.Conn.ReadTimeout = *readTimeout
+	c.Seed = *seed
+	c.Iterations = *iterations
+	watchCalls(c.Conn, watchdog.New(*rpcTimeout, watchdog.Exit("rpcclient")))
+	if *tracePath != "" {
+		f, err := rpctrace.Open(*tracePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "rpcclient: trace: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		rpctrace.New(f, "client").Attach(c.Conn)
 	}
-}
 
-func callUnary(r io.Reader, w io.Writer, method string, req proto.Message) ([]byte, error) {
-	reqBytes, err := proto.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
-	}
-	if err := rpcproto.WriteCall(w, method, reqBytes); err != nil {
-		return nil, fmt.Errorf("write call: %w", err)
-	}
-	frame, err := rpcproto.ReadFrame(r)
-	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
-	}
-	if frame.Type == rpcproto.FrameError {
-		return nil, fmt.Errorf("server error: %s", string(frame.Payload))
+	if *replayPath != "" {
+		os.Exit(replay(ctx, c.Conn, *replayPath))
 	}
-	if frame.Type != rpcproto.FrameResponse {
-		return nil, fmt.Errorf("expected RESPONSE, got 0x%02x", frame.Type)
+	if *goldenPath != "" {
+		f, err := os.Create(*goldenPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "rpcclient: golden: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		rec := rpctrace.NewRecorder(f)
+		rec.Attach(c.Conn)
+		defer func() {
+			if err := rec.Err(); err != nil {
+				fmt.Fprintf(os.Stderr, "rpcclient: golden: %v\n", err)
+			}
+		}()
+	}
+
+	if *handshake {
+		if err := c.Hello(ctx, rpcproto.Settings{InitialWindow: uint32(*window), MaxFrameSize: uint32(*maxFrame), Codec: codec.ID(), Features: features}); err != nil {
+			fmt.Fprintf(os.Stderr, "rpcclient: handshake: %v\n", err)
+			c.W.WriteShutdown()
+			os.Exit(1)
+		}
 	}
-	return frame.Payload, nil
-}
 
-func testPing(r io.Reader, w io.Writer) int {
-	respBytes, err := callUnary(r, w, "/UnaryService/Ping", &pb.PingRequest{Payload: "hello"})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL Ping: %v\n", err)
-		return 1
-	}
-	resp := &pb.PingResponse{}
-	if err := proto.Unmarshal(respBytes, resp); err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL Ping unmarshal: %v\n", err)
-		return 1
-	}
-	if resp.Payload != "hello" {
-		fmt.Fprintf(os.Stderr, "FAIL Ping: payload=%q want %q\n", resp.Payload, "hello")
-		return 1
-	}
-	return 0
-}
+	failures := c.Run(ctx, tests)
 
-func testGetItem(r io.Reader, w io.Writer) int {
-	respBytes, err := callUnary(r, w, "/UnaryService/GetItem", &pb.GetItemRequest{Id: 42, Query: "test"})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL GetItem: %v\n", err)
-		return 1
+	if ctx.Err() != nil {
+		// Interrupted: tell the server to stop rather than leaving it
+		// waiting on a half-finished exchange.
+		fmt.Fprintf(os.Stderr, "rpcclient: %v\n", context.Cause(ctx))
+		c.W.WriteShutdown()
+		writeClientStats(*statsPath, c)
+		os.Exit(130)
 	}
-	resp := &pb.GetItemResponse{}
-	if err := proto.Unmarshal(respBytes, resp); err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL GetItem unmarshal: %v\n", err)
-		return 1
-	}
-	if resp.Id != 42 {
-		fmt.Fprintf(os.Stderr, "FAIL GetItem: id=%d want 42\n", resp.Id)
-		return 1
-	}
-	if resp.Name != "item_42" {
-		fmt.Fprintf(os.Stderr, "FAIL GetItem: name=%q want %q\n", resp.Name, "item_42")
-		return 1
-	}
-	return 0
-}
 
-func testHealth(r io.Reader, w io.Writer) int {
-	respBytes, err := callUnary(r, w, "/UnaryService/Health", &pb.HealthRequest{ServiceName: "svc"})
+	report, err := c.Shutdown(ctx)
+	writeClientStats(*statsPath, c)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL Health: %v\n", err)
-		return 1
-	}
-	resp := &pb.HealthResponse{}
-	if err := proto.Unmarshal(respBytes, resp); err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL Health unmarshal: %v\n", err)
-		return 1
+		fmt.Fprintf(os.Stderr, "rpcclient: %v\n", err)
+		os.Exit(1)
 	}
-	if resp.Status != "serving" {
-		fmt.Fprintf(os.Stderr, "FAIL Health: status=%q want %q\n", resp.Status, "serving")
-		return 1
+	if report != nil {
+		printStats(report)
 	}
-	return 0
-}
 
-func testEcho(r io.Reader, w io.Writer) int {
-	respBytes, err := callUnary(r, w, "/UnaryService/Echo", &pb.EchoMessage{Text: "hi", Code: 10})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL Echo: %v\n", err)
-		return 1
-	}
-	resp := &pb.EchoMessage{}
-	if err := proto.Unmarshal(respBytes, resp); err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL Echo unmarshal: %v\n", err)
-		return 1
-	}
-	if resp.Text != "hi" {
-		fmt.Fprintf(os.Stderr, "FAIL Echo: text=%q want %q\n", resp.Text, "hi")
-		return 1
-	}
-	if resp.Code != 11 {
-		fmt.Fprintf(os.Stderr, "FAIL Echo: code=%d want 11\n", resp.Code)
-		return 1
+	if failures > 0 {
+		fmt.Fprintf(os.Stderr, "rpcclient: %d test(s) failed\n", failures)
+		os.Exit(1)
 	}
-	return 0
 }
 
-func testServerSide(r io.Reader, w io.Writer) int {
-	reqBytes, err := proto.Marshal(&pb.StreamRequest{Query: "q"})
+// replay runs a golden transcript against the server on conn and returns the
+// exit status: 0 if every response matched, 1 otherwise.
+func replay(ctx context.Context, conn *rpcproto.Conn, path string) int {
+	f, err := os.Open(path)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL ServerSide marshal: %v\n", err)
+		fmt.Fprintf(os.Stderr, "rpcclient: replay: %v\n", err)
 		return 1
 	}
-	if err := rpcproto.WriteCall(w, "/StreamingService/ServerSide", reqBytes); err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL ServerSide write call: %v\n", err)
+	frames, err := rpctrace.ReadGolden(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rpcclient: replay: %v\n", err)
 		return 1
 	}
-
-	// Read 3 STREAM_MSG + STREAM_END
-	for i := int32(0); i < 3; i++ {
-		frame, err := rpcproto.ReadFrame(r)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "FAIL ServerSide read msg %d: %v\n", i, err)
-			return 1
-		}
-		if frame.Type != rpcproto.FrameStreamMsg {
-			fmt.Fprintf(os.Stderr, "FAIL ServerSide: expected STREAM_MSG, got 0x%02x\n", frame.Type)
-			return 1
-		}
-		resp := &pb.StreamResponse{}
-		if err := proto.Unmarshal(frame.Payload, resp); err != nil {
-			fmt.Fprintf(os.Stderr, "FAIL ServerSide unmarshal %d: %v\n", i, err)
-			return 1
-		}
-		expected := fmt.Sprintf("q_%d", i)
-		if resp.Result != expected {
-			fmt.Fprintf(os.Stderr, "FAIL ServerSide: result=%q want %q\n", resp.Result, expected)
-			return 1
-		}
-		if resp.Index != i {
-			fmt.Fprintf(os.Stderr, "FAIL ServerSide: index=%d want %d\n", resp.Index, i)
-			return 1
-		}
-	}
-
-	frame, err := rpcproto.ReadFrame(r)
+	diffs, err := rpctrace.Replay(ctx, conn, frames, os.Stderr)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL ServerSide read end: %v\n", err)
+		fmt.Fprintf(os.Stderr, "rpcclient: replay: %v\n", err)
 		return 1
 	}
-	if frame.Type != rpcproto.FrameStreamEnd {
-		fmt.Fprintf(os.Stderr, "FAIL ServerSide: expected STREAM_END, got 0x%02x\n", frame.Type)
+	if diffs > 0 {
+		fmt.Fprintf(os.Stderr, "rpcclient: %d of %d frames drifted from %s\n", diffs, len(frames), path)
 		return 1
 	}
+	fmt.Fprintf(os.Stderr, "rpcclient: %d frames match %s\n", len(frames), path)
 	return 0
 }
 
-func testClientSide(r io.Reader, w io.Writer) int {
-	// Send CALL with empty request (client streaming)
-	if err := rpcproto.WriteCall(w, "/StreamingService/ClientSide", nil); err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL ClientSide write call: %v\n", err)
-		return 1
-	}
-
-	// Send 3 chunks
-	chunks := []string{"a", "bb", "ccc"}
-	for _, data := range chunks {
-		chunk := &pb.UploadChunk{Data: []byte(data)}
-		chunkBytes, err := proto.Marshal(chunk)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "FAIL ClientSide marshal chunk: %v\n", err)
-			return 1
+// watchCalls times each call on conn with wd, from writing its CALL to
+// reading the RESPONSE, ERROR or STREAM_END that ends it.
+func watchCalls(conn *rpcproto.Conn, wd *watchdog.Watchdog) {
+	if wd == nil {
+		return
+	}
+	conn.W.AddObserver(func(f *rpcproto.Frame) {
+		if f.Type == rpcproto.FrameCall {
+			if method, _, err := rpcproto.ParseCallPayload(f.Payload); err == nil {
+				wd.Start(method)
+			}
 		}
-		if err := rpcproto.WriteStreamMsg(w, chunkBytes); err != nil {
-			fmt.Fprintf(os.Stderr, "FAIL ClientSide write chunk: %v\n", err)
-			return 1
+	})
+	conn.R.AddObserver(func(f *rpcproto.Frame) {
+		switch f.Type {
+		case rpcproto.FrameResponse, rpcproto.FrameError, rpcproto.FrameStreamEnd:
+			wd.Stop()
 		}
-	}
+	})
+}
 
-	// Send STREAM_END
-	if err := rpcproto.WriteStreamEnd(w); err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL ClientSide write end: %v\n", err)
-		return 1
+// writeClientStats dumps c's counters to path, if set, as indented JSON.
+func writeClientStats(path string, c *rpcclientlib.Client) {
+	if path == "" {
+		return
 	}
-
-	// Read RESPONSE
-	frame, err := rpcproto.ReadFrame(r)
+	f, err := rpctrace.Open(path)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL ClientSide read response: %v\n", err)
-		return 1
-	}
-	if frame.Type != rpcproto.FrameResponse {
-		fmt.Fprintf(os.Stderr, "FAIL ClientSide: expected RESPONSE, got 0x%02x\n", frame.Type)
-		return 1
-	}
-	resp := &pb.UploadResult{}
-	if err := proto.Unmarshal(frame.Payload, resp); err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL ClientSide unmarshal: %v\n", err)
-		return 1
+		fmt.Fprintf(os.Stderr, "rpcclient: stats: %v\n", err)
+		return
 	}
-	if resp.TotalChunks != 3 {
-		fmt.Fprintf(os.Stderr, "FAIL ClientSide: total_chunks=%d want 3\n", resp.TotalChunks)
-		return 1
-	}
-	if resp.Summary != "received_3_chunks" {
-		fmt.Fprintf(os.Stderr, "FAIL ClientSide: summary=%q want %q\n", resp.Summary, "received_3_chunks")
-		return 1
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c.ClientStats()); err != nil {
+		fmt.Fprintf(os.Stderr, "rpcclient: stats: %v\n", err)
 	}
-	return 0
 }
 
-func testBidirectional(r io.Reader, w io.Writer) int {
-	// Send CALL with empty request (bidi streaming)
-	if err := rpcproto.WriteCall(w, "/StreamingService/Bidirectional", nil); err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL Bidirectional write call: %v\n", err)
-		return 1
-	}
-
-	// Send 2 messages
-	msgs := []struct{ sender, text string }{
-		{"test", "hi"},
-		{"test", "bye"},
+// printStats summarizes a server's final report on one line.
+func printStats(r *pb.StatsReport) {
+	var calls uint64
+	for _, m := range r.Methods {
+		calls += m.Calls
 	}
-	for _, m := range msgs {
-		msg := &pb.ChatMessage{Sender: m.sender, Text: m.text}
-		msgBytes, err := proto.Marshal(msg)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "FAIL Bidirectional marshal: %v\n", err)
-			return 1
-		}
-		if err := rpcproto.WriteStreamMsg(w, msgBytes); err != nil {
-			fmt.Fprintf(os.Stderr, "FAIL Bidirectional write msg: %v\n", err)
-			return 1
-		}
-	}
-
-	// Send STREAM_END
-	if err := rpcproto.WriteStreamEnd(w); err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL Bidirectional write end: %v\n", err)
-		return 1
-	}
-
-	// Read 2 echoed messages + STREAM_END
-	expectedTexts := []string{"hi", "bye"}
-	for i, expectedText := range expectedTexts {
-		frame, err := rpcproto.ReadFrame(r)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "FAIL Bidirectional read msg %d: %v\n", i, err)
-			return 1
-		}
-		if frame.Type != rpcproto.FrameStreamMsg {
-			fmt.Fprintf(os.Stderr, "FAIL Bidirectional: expected STREAM_MSG, got 0x%02x\n", frame.Type)
-			return 1
-		}
-		resp := &pb.ChatMessage{}
-		if err := proto.Unmarshal(frame.Payload, resp); err != nil {
-			fmt.Fprintf(os.Stderr, "FAIL Bidirectional unmarshal %d: %v\n", i, err)
-			return 1
-		}
-		if resp.Sender != "echo" {
-			fmt.Fprintf(os.Stderr, "FAIL Bidirectional: sender=%q want %q\n", resp.Sender, "echo")
-			return 1
-		}
-		if resp.Text != expectedText {
-			fmt.Fprintf(os.Stderr, "FAIL Bidirectional: text=%q want %q\n", resp.Text, expectedText)
-			return 1
-		}
-	}
-
-	frame, err := rpcproto.ReadFrame(r)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "FAIL Bidirectional read end: %v\n", err)
-		return 1
-	}
-	if frame.Type != rpcproto.FrameStreamEnd {
-		fmt.Fprintf(os.Stderr, "FAIL Bidirectional: expected STREAM_END, got 0x%02x\n", frame.Type)
-		return 1
-	}
-	return 0
+	fmt.Fprintf(os.Stderr, "rpcclient: server stats: %d calls over %d methods, %d errors, %d/%d frames (%d/%d bytes) in/out, max %d concurrent\n",
+		calls, len(r.Methods), r.Errors, r.FramesIn, r.FramesOut, r.BytesIn, r.BytesOut, r.MaxConcurrentStreams)
 }
@@ -0,0 +1,58 @@
+// Command rpcgen writes the Go server interfaces and client stubs of every
+// service in the compat schemas, over the rpcproto transport. go generate in
+// compat/pbrpc runs it:
+//
+//	rpcgen -o services.go
+//
+// By default it reads the descriptors of the files linked in from compat/pb.
+// With -descriptor-set it reads a FileDescriptorSet instead, such as protoc
+// writes with --include_imports --descriptor_set_out; the Go types of its
+// messages must still be those of compat/pb.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	_ "compat/pb"
+	"compat/rpcgenlib"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func main() {
+	setPath := flag.String("descriptor-set", "", "read services from this FileDescriptorSet (default: the files linked from compat/pb)")
+	pkg := flag.String("package", "pbrpc", "package name of the generated file")
+	out := flag.String("o", "", "write the generated file here (default: stdout)")
+	flag.Parse()
+
+	var set *descriptorpb.FileDescriptorSet
+	if *setPath == "" {
+		set = rpcgenlib.LinkedSet()
+	} else {
+		data, err := os.ReadFile(*setPath)
+		if err != nil {
+			fatal(err)
+		}
+		if set, err = rpcgenlib.ParseSet(data); err != nil {
+			fatal(err)
+		}
+	}
+	src, err := rpcgenlib.Generate(set, *pkg)
+	if err != nil {
+		fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "rpcgen: %v\n", err)
+	os.Exit(1)
+}
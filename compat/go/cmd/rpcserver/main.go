@@ -1,229 +1,171 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
 	"fmt"
-	"io"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
-	"compat/pb"
+	"compat/pbutil"
 	"compat/rpcproto"
-
-	"google.golang.org/protobuf/proto"
+	"compat/rpcserverlib"
+	"compat/rpctrace"
 )
 
 func main() {
-	r := os.Stdin
-	w := os.Stdout
+	window := flag.Uint("window", uint(rpcserverlib.DefaultSettings.InitialWindow), "receive window in bytes advertised to clients that send HELLO (0 = unlimited)")
+	maxFrame := flag.Uint("max-frame", uint(rpcserverlib.DefaultSettings.MaxFrameSize), "largest frame payload in bytes advertised to clients that send HELLO (0 = protocol maximum)")
+	sequence := flag.Bool("sequence", true, "number frames after HELLO for clients that ask for it, and check the numbers they send")
+	tracePath := flag.String("trace", "", "write a JSON-lines log of every frame to this file (- for stderr)")
+	listen := flag.String("listen", "", "serve connections on this socket (unix:/path, [tcp:]host:port or ws:host:port[/path]) instead of stdin/stdout")
+	tlsCert := flag.String("tls-cert", "", "serve -listen connections over TLS with this certificate (PEM)")
+	tlsKey := flag.String("tls-key", "", "private key (PEM) for -tls-cert")
+	tlsCA := flag.String("tls-ca", "", "require client certificates signed by a CA in this file (mutual TLS)")
+	drainTimeout := flag.Duration("drain-timeout", 5*time.Second, "after SIGTERM, how long calls in progress may take before the server stops anyway")
+	validate := flag.Bool("validate", false, "fail requests that break the built-in validation rules with INVALID_ARGUMENT")
+	statsOut := flag.String("stats-out", "", "write the final StatsReport (binary protobuf) to this file at exit")
+	metricsAddr := flag.String("metrics", "", "serve Prometheus metrics over HTTP at host:port/metrics, for watching long -listen soak runs")
+	faults := &rpcserverlib.Faults{Slow: map[string]time.Duration{}}
+	flag.DurationVar(&faults.Latency, "latency", 0, "delay every call by this much before handling it")
+	flag.DurationVar(&faults.Jitter, "jitter", 0, "add a random delay up to this much to every call")
+	flag.Float64Var(&faults.ErrorRate, "error-rate", 0, "fraction of calls (0-1) to fail with an injected ERROR")
+	flag.Uint64Var(&faults.Seed, "fault-seed", 1, "seed for -jitter and -error-rate")
+	flag.Func("slow-method", "delay calls to one method, as `path=duration` (repeatable)", func(v string) error {
+		path, d, ok := strings.Cut(v, "=")
+		if !ok {
+			return fmt.Errorf("want path=duration, got %q", v)
+		}
+		delay, err := time.ParseDuration(d)
+		if err != nil {
+			return err
+		}
+		faults.Slow[rpcproto.CanonicalPath(path)] = delay
+		return nil
+	})
+	flag.Parse()
+	if (*tlsCert != "" || *tlsKey != "" || *tlsCA != "") && (*listen == "" || *tlsCert == "" || *tlsKey == "") {
+		fmt.Fprintln(os.Stderr, "rpcserver: TLS needs -listen, -tls-cert and -tls-key")
+		os.Exit(2)
+	}
+	if faults.ErrorRate < 0 || faults.ErrorRate > 1 {
+		fmt.Fprintf(os.Stderr, "rpcserver: -error-rate %v out of range [0, 1]\n", faults.ErrorRate)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	srv := &rpcserverlib.Server{
+		Registry: rpcserverlib.DefaultRegistry(),
+		Settings: rpcproto.Settings{InitialWindow: uint32(*window), MaxFrameSize: uint32(*maxFrame), Features: features(*sequence)},
+		Stats:    rpcserverlib.NewStats(),
+		Log:      os.Stderr,
+		Faults:   faults,
+	}
+	if *validate {
+		srv.Validator = rpcserverlib.DefaultValidator()
+	}
+	// SIGTERM drains: no new calls, the ones in progress finish, then the
+	// server exits. A second SIGTERM or -drain-timeout stops it outright.
+	term := make(chan os.Signal, 2)
+	signal.Notify(term, syscall.SIGTERM)
+	go func() {
+		<-term
+		srv.Drain()
+		select {
+		case <-term:
+			cancel(errors.New("terminated while draining"))
+		case <-time.After(*drainTimeout):
+			cancel(errors.New("drain timed out"))
+		case <-ctx.Done():
+		}
+	}()
 
-	for {
-		frame, err := rpcproto.ReadFrame(r)
+	if *tracePath != "" {
+		f, err := rpctrace.Open(*tracePath)
 		if err != nil {
-			if err == io.EOF {
-				return
-			}
-			fmt.Fprintf(os.Stderr, "rpcserver: read frame: %v\n", err)
+			fmt.Fprintf(os.Stderr, "rpcserver: trace: %v\n", err)
 			os.Exit(1)
 		}
+		defer f.Close()
+		srv.Trace = f
+	}
 
-		switch frame.Type {
-		case rpcproto.FrameShutdown:
-			return
+	if *metricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", srv.Stats.MetricsHandler())
+		mln, err := net.Listen("tcp", *metricsAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "rpcserver: metrics: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "rpcserver: metrics on http://%s/metrics\n", mln.Addr())
+		go func() {
+			if err := http.Serve(mln, mux); err != nil {
+				fmt.Fprintf(os.Stderr, "rpcserver: metrics: %v\n", err)
+			}
+		}()
+	}
 
-		case rpcproto.FrameCall:
-			method, reqBytes, err := rpcproto.ParseCallPayload(frame.Payload)
+	var err error
+	if *listen != "" {
+		var ln net.Listener
+		if ln, err = rpcproto.Listen(*listen); err != nil {
+			fmt.Fprintf(os.Stderr, "rpcserver: %v\n", err)
+			os.Exit(1)
+		}
+		if *tlsCert != "" {
+			cfg, err := rpcproto.ServerTLS(*tlsCert, *tlsKey, *tlsCA)
 			if err != nil {
-				rpcproto.WriteError(w, err.Error())
-				continue
+				fmt.Fprintf(os.Stderr, "rpcserver: tls: %v\n", err)
+				os.Exit(1)
 			}
-			if err := handleCall(r, w, method, reqBytes); err != nil {
-				fmt.Fprintf(os.Stderr, "rpcserver: %s: %v\n", method, err)
-				rpcproto.WriteError(w, err.Error())
-			}
-
-		default:
-			rpcproto.WriteError(w, fmt.Sprintf("unexpected frame type: 0x%02x", frame.Type))
+			ln = tls.NewListener(ln, cfg)
 		}
+		fmt.Fprintf(os.Stderr, "rpcserver: listening on %s\n", ln.Addr())
+		err = srv.ServeListener(ctx, ln)
+	} else {
+		err = srv.Serve(ctx, rpcproto.JoinReadWriter(os.Stdin, os.Stdout))
 	}
-}
-
-func handleCall(r io.Reader, w io.Writer, method string, reqBytes []byte) error {
-	switch method {
-	// UnaryService methods
-	case "/UnaryService/Ping":
-		return handlePing(w, reqBytes)
-	case "/UnaryService/GetItem":
-		return handleGetItem(w, reqBytes)
-	case "/UnaryService/Health":
-		return handleHealth(w, reqBytes)
-	case "/UnaryService/Echo":
-		return handleEcho(w, reqBytes)
-
-	// StreamingService methods
-	case "/StreamingService/UnaryCall":
-		return handleUnaryCall(w, reqBytes)
-	case "/StreamingService/ServerSide":
-		return handleServerSide(w, reqBytes)
-	case "/StreamingService/ClientSide":
-		return handleClientSide(r, w)
-	case "/StreamingService/Bidirectional":
-		return handleBidirectional(r, w)
-
-	default:
-		return fmt.Errorf("unknown method: %s", method)
-	}
-}
-
-func handlePing(w io.Writer, reqBytes []byte) error {
-	req := &pb.PingRequest{}
-	if err := proto.Unmarshal(reqBytes, req); err != nil {
-		return err
-	}
-	resp := &pb.PingResponse{Payload: req.Payload}
-	respBytes, err := proto.Marshal(resp)
-	if err != nil {
-		return err
-	}
-	return rpcproto.WriteResponse(w, respBytes)
-}
 
-func handleGetItem(w io.Writer, reqBytes []byte) error {
-	req := &pb.GetItemRequest{}
-	if err := proto.Unmarshal(reqBytes, req); err != nil {
-		return err
-	}
-	resp := &pb.GetItemResponse{
-		Id:   req.Id,
-		Name: fmt.Sprintf("item_%d", req.Id),
-	}
-	respBytes, err := proto.Marshal(resp)
-	if err != nil {
-		return err
-	}
-	return rpcproto.WriteResponse(w, respBytes)
-}
-
-func handleHealth(w io.Writer, reqBytes []byte) error {
-	req := &pb.HealthRequest{}
-	if err := proto.Unmarshal(reqBytes, req); err != nil {
-		return err
-	}
-	resp := &pb.HealthResponse{Status: "serving"}
-	respBytes, err := proto.Marshal(resp)
-	if err != nil {
-		return err
-	}
-	return rpcproto.WriteResponse(w, respBytes)
-}
-
-func handleEcho(w io.Writer, reqBytes []byte) error {
-	req := &pb.EchoMessage{}
-	if err := proto.Unmarshal(reqBytes, req); err != nil {
-		return err
+	if *statsOut != "" {
+		if err := writeStats(*statsOut, srv.Stats); err != nil {
+			fmt.Fprintf(os.Stderr, "rpcserver: stats: %v\n", err)
+		}
 	}
-	resp := &pb.EchoMessage{Text: req.Text, Code: req.Code + 1}
-	respBytes, err := proto.Marshal(resp)
 	if err != nil {
-		return err
+		fmt.Fprintf(os.Stderr, "rpcserver: %v\n", err)
+		// Interrupted between frames: every response written so far is
+		// complete, so just stop.
+		if ctx.Err() == nil {
+			os.Exit(1)
+		}
 	}
-	return rpcproto.WriteResponse(w, respBytes)
 }
 
-func handleUnaryCall(w io.Writer, reqBytes []byte) error {
-	req := &pb.StreamRequest{}
-	if err := proto.Unmarshal(reqBytes, req); err != nil {
-		return err
-	}
-	resp := &pb.StreamResponse{Result: req.Query, Index: 0}
-	respBytes, err := proto.Marshal(resp)
-	if err != nil {
-		return err
+// features returns the feature bits for the server's HELLO.
+func features(sequence bool) uint32 {
+	if sequence {
+		return rpcproto.FeatureSequence
 	}
-	return rpcproto.WriteResponse(w, respBytes)
+	return 0
 }
 
-func handleServerSide(w io.Writer, reqBytes []byte) error {
-	req := &pb.StreamRequest{}
-	if err := proto.Unmarshal(reqBytes, req); err != nil {
-		return err
-	}
-	for i := int32(0); i < 3; i++ {
-		resp := &pb.StreamResponse{
-			Result: fmt.Sprintf("%s_%d", req.Query, i),
-			Index:  i,
-		}
-		respBytes, err := proto.Marshal(resp)
-		if err != nil {
-			return err
-		}
-		if err := rpcproto.WriteStreamMsg(w, respBytes); err != nil {
-			return err
-		}
-	}
-	return rpcproto.WriteStreamEnd(w)
-}
-
-func handleClientSide(r io.Reader, w io.Writer) error {
-	count := int32(0)
-	for {
-		frame, err := rpcproto.ReadFrame(r)
-		if err != nil {
-			return err
-		}
-		if frame.Type == rpcproto.FrameStreamEnd {
-			break
-		}
-		if frame.Type != rpcproto.FrameStreamMsg {
-			return fmt.Errorf("expected STREAM_MSG or STREAM_END, got 0x%02x", frame.Type)
-		}
-		// Decode to verify it's valid, but we just count
-		chunk := &pb.UploadChunk{}
-		if err := proto.Unmarshal(frame.Payload, chunk); err != nil {
-			return err
-		}
-		count++
-	}
-	resp := &pb.UploadResult{
-		TotalChunks: count,
-		Summary:     fmt.Sprintf("received_%d_chunks", count),
-	}
-	respBytes, err := proto.Marshal(resp)
+// writeStats saves a snapshot of stats to path as a binary StatsReport.
+func writeStats(path string, stats *rpcserverlib.Stats) error {
+	b, err := pbutil.Marshal(stats.Report())
 	if err != nil {
 		return err
 	}
-	return rpcproto.WriteResponse(w, respBytes)
-}
-
-func handleBidirectional(r io.Reader, w io.Writer) error {
-	// Read all incoming messages
-	var messages []*pb.ChatMessage
-	for {
-		frame, err := rpcproto.ReadFrame(r)
-		if err != nil {
-			return err
-		}
-		if frame.Type == rpcproto.FrameStreamEnd {
-			break
-		}
-		if frame.Type != rpcproto.FrameStreamMsg {
-			return fmt.Errorf("expected STREAM_MSG or STREAM_END, got 0x%02x", frame.Type)
-		}
-		msg := &pb.ChatMessage{}
-		if err := proto.Unmarshal(frame.Payload, msg); err != nil {
-			return err
-		}
-		messages = append(messages, msg)
-	}
-
-	// Echo all messages back
-	for _, msg := range messages {
-		echo := &pb.ChatMessage{Sender: "echo", Text: msg.Text}
-		echoBytes, err := proto.Marshal(echo)
-		if err != nil {
-			return err
-		}
-		if err := rpcproto.WriteStreamMsg(w, echoBytes); err != nil {
-			return err
-		}
-	}
-	return rpcproto.WriteStreamEnd(w)
+	return os.WriteFile(path, b, 0o644)
 }
@@ -0,0 +1,258 @@
+// Command schemafuzz tests the Zig code generator rather than the runtime.
+// For each seed it writes a random but valid proto3 schema (nested messages,
+// oneofs, maps, enums, big field numbers), compiles it with protoc to a
+// descriptor set, and frames random dynamicpb messages of every type into a
+// corpus. It then runs `zig build schemafuzz` from the compat directory,
+// which generates Zig code for the schema, decodes every case and encodes it
+// again, and requires each re-encoded message to equal the original.
+//
+//	schemafuzz -seed 1 -schemas 50
+//	schemafuzz -protoc "" -zig ""   # only generate; no external tools
+//
+// With -protoc "" the descriptor is built in-process instead, and with
+// -zig "" the Zig step is skipped. Failing schemas are left in -out with
+// their corpora so they can be rerun by hand.
+//
+// With -oracle, every case is also piped to an external decoder, with
+// {include} the schema's directory, and a disagreement names which of Go,
+// Zig and the oracle deviates:
+//
+//	schemafuzz -oracle "protoc --decode={type} -I{include} {file}"
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"compat/oracle"
+	"compat/randmsg"
+	"compat/testcases"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// shape is deeper than randmsg.Default, since the schemas nest, and sets
+// unknown enum numbers because proto3 enums are open.
+var shape = randmsg.Options{MaxDepth: 4, MaxRepeat: 4, MaxString: 16, Presence: 0.6, UnknownEnums: 0.1}
+
+type fuzzer struct {
+	out       string
+	protoc    string
+	zig       string
+	compatDir string
+	messages  int
+	keep      bool
+	oracle    *oracle.Oracle
+}
+
+func main() {
+	f := &fuzzer{}
+	seed := flag.Uint64("seed", 1, "seed of the first schema")
+	schemas := flag.Int("schemas", 20, "number of schemas to generate")
+	flag.IntVar(&f.messages, "messages", 5, "random messages per message type")
+	flag.StringVar(&f.out, "out", "../testdata/schemafuzz", "directory for schemas and corpora")
+	flag.StringVar(&f.protoc, "protoc", "protoc", `protoc binary ("" builds descriptors in-process)`)
+	flag.StringVar(&f.zig, "zig", "zig", `zig binary ("" skips the Zig round trip)`)
+	flag.StringVar(&f.compatDir, "compat-dir", "..", "directory containing build.zig")
+	flag.BoolVar(&f.keep, "keep", false, "keep the files of passing schemas too")
+	oracleCmd := flag.String("oracle", "", "external decoder to compare with Go and Zig on every case; see the package comment")
+	oracleFormat := flag.String("oracle-format", string(oracle.FormatText), "what -oracle writes: text, json or binary")
+	oracleTimeout := flag.Duration("oracle-timeout", 30*time.Second, "how long one -oracle run may take")
+	flag.Parse()
+
+	var err error
+	if f.oracle, err = oracle.New(*oracleCmd, oracle.Format(*oracleFormat), "", *oracleTimeout); err != nil {
+		fmt.Fprintf(os.Stderr, "schemafuzz: -oracle: %v\n", err)
+		os.Exit(2)
+	}
+
+	failed := 0
+	for i := 0; i < *schemas; i++ {
+		s := *seed + uint64(i)
+		if err := f.run(s); err != nil {
+			fmt.Printf("FAIL seed %d: %v\n", s, err)
+			failed++
+		}
+	}
+	fmt.Printf("schemafuzz: %d schemas, %d failures\n", *schemas, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// run generates, compiles and round-trips the schema for one seed.
+func (f *fuzzer) run(seed uint64) error {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	s := newSchema(rng)
+
+	dir, err := filepath.Abs(filepath.Join(f.out, fmt.Sprint(seed)))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, fuzzPackage+".proto"), []byte(s.render()), 0o644); err != nil {
+		return err
+	}
+
+	file, err := f.compile(dir, s)
+	if err != nil {
+		return fmt.Errorf("compile %s: %w", dir, err)
+	}
+
+	// Every message type, nested ones included, gets f.messages cases named
+	// after its path within the package.
+	var corpus bytes.Buffer
+	testcases.WriteCorpusHeader(&corpus)
+	types := map[string]protoreflect.MessageDescriptor{}
+	var walk func(protoreflect.MessageDescriptors) error
+	walk = func(mds protoreflect.MessageDescriptors) error {
+		for i := 0; i < mds.Len(); i++ {
+			md := mds.Get(i)
+			if md.IsMapEntry() {
+				continue
+			}
+			name := strings.TrimPrefix(string(md.FullName()), fuzzPackage+".")
+			types[name] = md
+			for j := 0; j < f.messages; j++ {
+				if err := testcases.WriteTestCase(&corpus, fmt.Sprintf("%s/%d", name, j), randmsg.New(rng, md, shape)); err != nil {
+					return err
+				}
+			}
+			if err := walk(md.Messages()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(file.Messages()); err != nil {
+		return err
+	}
+	in := filepath.Join(dir, "in.bin")
+	if err := os.WriteFile(in, corpus.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	if f.zig != "" {
+		if err := f.roundTrip(dir, in, types); err != nil {
+			return fmt.Errorf("%w (schema and corpus kept in %s)", err, dir)
+		}
+	}
+	if !f.keep {
+		return os.RemoveAll(dir)
+	}
+	return nil
+}
+
+// compile turns the schema written to dir into a file descriptor, with protoc
+// if one is configured.
+func (f *fuzzer) compile(dir string, s *schema) (protoreflect.FileDescriptor, error) {
+	if f.protoc == "" {
+		return protodesc.NewFile(s.descriptor(), nil)
+	}
+	set := filepath.Join(dir, fuzzPackage+".pb")
+	cmd := exec.Command(f.protoc, "-I", dir, "--include_imports", "--descriptor_set_out="+set, fuzzPackage+".proto")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("protoc: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(set)
+	if err != nil {
+		return nil, err
+	}
+	var fds descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &fds); err != nil {
+		return nil, fmt.Errorf("descriptor set: %w", err)
+	}
+	files, err := protodesc.NewFiles(&fds)
+	if err != nil {
+		return nil, err
+	}
+	return files.FindFileByPath(fuzzPackage + ".proto")
+}
+
+// roundTrip has the Zig side re-encode the corpus in and checks every case
+// comes back equal to what was sent, and with an oracle, that the oracle
+// decodes what was sent to the same message.
+func (f *fuzzer) roundTrip(dir, in string, types map[string]protoreflect.MessageDescriptor) error {
+	outPath := filepath.Join(dir, "out.bin")
+	cmd := exec.Command(f.zig, "build", "schemafuzz", "-Dschema-dir="+dir, "--", in, outPath)
+	cmd.Dir = f.compatDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("zig: %v\n%s", err, out)
+	}
+
+	want, err := readCorpus(in)
+	if err != nil {
+		return err
+	}
+	out, err := readCorpus(outPath)
+	if err != nil {
+		return err
+	}
+	got := map[string][]byte{}
+	for _, tc := range out {
+		got[tc.Name] = tc.Data
+	}
+	orc := f.oracle.In(dir)
+	var problems []string
+	for _, tc := range want {
+		data, ok := got[tc.Name]
+		if !ok {
+			problems = append(problems, tc.Name+": missing from Zig output")
+			continue
+		}
+		md := types[tc.Name[:strings.LastIndexByte(tc.Name, '/')]]
+		a, b := dynamicpb.NewMessage(md), dynamicpb.NewMessage(md)
+		if err := proto.Unmarshal(tc.Data, a); err != nil {
+			return fmt.Errorf("%s: %w", tc.Name, err)
+		}
+		zigErr := proto.Unmarshal(data, b)
+		if orc != nil {
+			zig := oracle.View{Impl: "zig", Msg: b}
+			if zigErr != nil {
+				zig = oracle.View{Impl: "zig", Err: &oracle.Rejected{Reason: zigErr.Error()}}
+			}
+			report, err := orc.Check(md, tc.Data, oracle.View{Impl: "go", Msg: a}, zig)
+			if err != nil {
+				return fmt.Errorf("%s: %w", tc.Name, err)
+			}
+			if report != "" {
+				problems = append(problems, tc.Name+": "+strings.ReplaceAll(report, "\n", "\n  "))
+			}
+			continue
+		}
+		if zigErr != nil {
+			problems = append(problems, fmt.Sprintf("%s: Zig output does not decode: %v", tc.Name, zigErr))
+			continue
+		}
+		if !proto.Equal(a, b) {
+			problems = append(problems, fmt.Sprintf("%s: round trip changed the message\n  sent: %s\n  got:  %s",
+				tc.Name, prototext.Format(a), prototext.Format(b)))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d of %d cases failed:\n  %s", len(problems), len(want), strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+func readCorpus(path string) ([]testcases.RawTestCase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return testcases.ReadTestCases(data)
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// fuzzPackage is the package of every generated schema. The file is named
+// after it too, so the generated Zig module is proto.fuzz.
+const fuzzPackage = "fuzz"
+
+// maxTag is the largest field number protobuf allows.
+const maxTag = 1<<29 - 1
+
+// schema is a randomly generated proto3 file. It is kept as a small model so
+// it can be rendered both as .proto text, for protoc and the Zig generator,
+// and as a descriptor, for running without protoc.
+type schema struct {
+	enums    []*enumDef
+	messages []*msgDef
+	all      []*msgDef // every message, nested ones included
+	allEnums []*enumDef
+}
+
+type enumDef struct {
+	name   string
+	path   string // name relative to the package, e.g. "M0.E1"
+	values []enumValue
+}
+
+type enumValue struct {
+	name   string
+	number int32
+}
+
+type msgDef struct {
+	name   string
+	path   string
+	fields []*fieldDef
+	oneofs []string
+	nested []*msgDef
+	enums  []*enumDef
+}
+
+type fieldDef struct {
+	name     string
+	number   int32
+	typ      typeRef
+	key      string // map key scalar; empty for non-map fields
+	repeated bool
+	optional bool // proto3 explicit presence
+	oneof    int  // index into msgDef.oneofs, or -1
+}
+
+// typeRef is a field's value type: a scalar keyword, an enum or a message.
+type typeRef struct {
+	scalar string
+	enum   *enumDef
+	msg    *msgDef
+}
+
+func (t typeRef) String() string {
+	switch {
+	case t.enum != nil:
+		return t.enum.path
+	case t.msg != nil:
+		return t.msg.path
+	}
+	return t.scalar
+}
+
+var scalarTypes = []string{
+	"double", "float", "int32", "int64", "uint32", "uint64", "sint32", "sint64",
+	"fixed32", "fixed64", "sfixed32", "sfixed64", "bool", "string", "bytes",
+}
+
+var mapKeyTypes = []string{
+	"int32", "int64", "uint32", "uint64", "sint32", "sint64",
+	"fixed32", "fixed64", "sfixed32", "sfixed64", "bool", "string",
+}
+
+// schemaGen builds one schema. Names are numbered across the whole file, so
+// no two declarations share a name and every type can be referred to by its
+// path from the package scope.
+type schemaGen struct {
+	rng       *rand.Rand
+	s         *schema
+	nextMsg   int
+	nextEnum  int
+	pendingTy []*fieldDef // fields whose type is chosen once all types exist
+}
+
+func newSchema(rng *rand.Rand) *schema {
+	g := &schemaGen{rng: rng, s: &schema{}}
+	for n := rng.IntN(3); n > 0; n-- {
+		g.s.enums = append(g.s.enums, g.enum(""))
+	}
+	for n := 1 + rng.IntN(4); n > 0; n-- {
+		g.s.messages = append(g.s.messages, g.message("", 0))
+	}
+	// Types are picked last so fields can refer to any message, including
+	// ones declared later and the enclosing message itself.
+	for _, f := range g.pendingTy {
+		g.pickType(f)
+	}
+	return g.s
+}
+
+func (g *schemaGen) enum(parent string) *enumDef {
+	e := &enumDef{name: fmt.Sprintf("E%d", g.nextEnum)}
+	g.nextEnum++
+	e.path = qualify(parent, e.name)
+	// Enum values are scoped to the enclosing scope, not the enum, so they
+	// carry the enum's name to stay unique.
+	e.values = []enumValue{{name: e.name + "_V0", number: 0}}
+	used := map[int32]bool{0: true}
+	for n := g.rng.IntN(5); n > 0; n-- {
+		num := int32(g.rng.IntN(1000)) - 5
+		if g.rng.IntN(8) == 0 {
+			num = 1<<31 - 1 - int32(g.rng.IntN(3))
+		}
+		if used[num] {
+			continue
+		}
+		used[num] = true
+		e.values = append(e.values, enumValue{name: fmt.Sprintf("%s_V%d", e.name, len(e.values)), number: num})
+	}
+	g.s.allEnums = append(g.s.allEnums, e)
+	return e
+}
+
+func (g *schemaGen) message(parent string, depth int) *msgDef {
+	m := &msgDef{name: fmt.Sprintf("M%d", g.nextMsg)}
+	g.nextMsg++
+	m.path = qualify(parent, m.name)
+	g.s.all = append(g.s.all, m)
+
+	if depth < 2 {
+		for n := g.rng.IntN(2); n > 0; n-- {
+			m.enums = append(m.enums, g.enum(m.path))
+		}
+		for n := g.rng.IntN(3); n > 0; n-- {
+			m.nested = append(m.nested, g.message(m.path, depth+1))
+		}
+	}
+
+	tags := map[int32]bool{}
+	newField := func() *fieldDef {
+		f := &fieldDef{name: fmt.Sprintf("f%d", len(m.fields)), number: g.tag(tags), oneof: -1}
+		m.fields = append(m.fields, f)
+		g.pendingTy = append(g.pendingTy, f)
+		return f
+	}
+	for n := 1 + g.rng.IntN(8); n > 0; n-- {
+		f := newField()
+		switch g.rng.IntN(10) {
+		case 0, 1:
+			f.repeated = true
+		case 2:
+			f.optional = true
+		case 3:
+			f.key = mapKeyTypes[g.rng.IntN(len(mapKeyTypes))]
+		}
+	}
+	if g.rng.IntN(3) == 0 {
+		m.oneofs = append(m.oneofs, fmt.Sprintf("o%d", len(m.oneofs)))
+		for n := 2 + g.rng.IntN(2); n > 0; n-- {
+			newField().oneof = len(m.oneofs) - 1
+		}
+	}
+	return m
+}
+
+// tag picks an unused field number: mostly small ones, some in the two- and
+// three-byte varint ranges and some near the top of the range. Numbers
+// reserved for the implementation (19000-19999) are skipped.
+func (g *schemaGen) tag(used map[int32]bool) int32 {
+	for {
+		var n int32
+		switch r := g.rng.IntN(10); {
+		case r < 7:
+			n = 1 + int32(g.rng.IntN(32))
+		case r < 9:
+			n = 16 + int32(g.rng.IntN(30000))
+		default:
+			n = maxTag - int32(g.rng.IntN(1000))
+		}
+		if n >= 19000 && n <= 19999 || used[n] {
+			continue
+		}
+		used[n] = true
+		return n
+	}
+}
+
+func (g *schemaGen) pickType(f *fieldDef) {
+	switch r := g.rng.IntN(10); {
+	case r < 2 && len(g.s.allEnums) > 0:
+		f.typ.enum = g.s.allEnums[g.rng.IntN(len(g.s.allEnums))]
+	case r < 4:
+		f.typ.msg = g.s.all[g.rng.IntN(len(g.s.all))]
+	default:
+		f.typ.scalar = scalarTypes[g.rng.IntN(len(scalarTypes))]
+	}
+}
+
+func qualify(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// render returns the schema as .proto source.
+func (s *schema) render() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "syntax = \"proto3\";\n\npackage %s;\n", fuzzPackage)
+	for _, e := range s.enums {
+		b.WriteString("\n")
+		e.render(&b, "")
+	}
+	for _, m := range s.messages {
+		b.WriteString("\n")
+		m.render(&b, "")
+	}
+	return b.String()
+}
+
+func (e *enumDef) render(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%senum %s {\n", indent, e.name)
+	for _, v := range e.values {
+		fmt.Fprintf(b, "%s  %s = %d;\n", indent, v.name, v.number)
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+func (m *msgDef) render(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%smessage %s {\n", indent, m.name)
+	inner := indent + "  "
+	for _, e := range m.enums {
+		e.render(b, inner)
+	}
+	for _, n := range m.nested {
+		n.render(b, inner)
+	}
+	for _, f := range m.fields {
+		if f.oneof >= 0 {
+			continue
+		}
+		switch {
+		case f.key != "":
+			fmt.Fprintf(b, "%smap<%s, %s> %s = %d;\n", inner, f.key, f.typ, f.name, f.number)
+		case f.repeated:
+			fmt.Fprintf(b, "%srepeated %s %s = %d;\n", inner, f.typ, f.name, f.number)
+		case f.optional:
+			fmt.Fprintf(b, "%soptional %s %s = %d;\n", inner, f.typ, f.name, f.number)
+		default:
+			fmt.Fprintf(b, "%s%s %s = %d;\n", inner, f.typ, f.name, f.number)
+		}
+	}
+	for i, o := range m.oneofs {
+		fmt.Fprintf(b, "%soneof %s {\n", inner, o)
+		for _, f := range m.fields {
+			if f.oneof == i {
+				fmt.Fprintf(b, "%s  %s %s = %d;\n", inner, f.typ, f.name, f.number)
+			}
+		}
+		fmt.Fprintf(b, "%s}\n", inner)
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+// descriptor returns the schema as protoc would compile it, for running
+// without protoc.
+func (s *schema) descriptor() *descriptorpb.FileDescriptorProto {
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String(fuzzPackage + ".proto"),
+		Package: proto.String(fuzzPackage),
+		Syntax:  proto.String("proto3"),
+	}
+	for _, e := range s.enums {
+		fd.EnumType = append(fd.EnumType, e.descriptor())
+	}
+	for _, m := range s.messages {
+		fd.MessageType = append(fd.MessageType, m.descriptor())
+	}
+	return fd
+}
+
+func (e *enumDef) descriptor() *descriptorpb.EnumDescriptorProto {
+	d := &descriptorpb.EnumDescriptorProto{Name: proto.String(e.name)}
+	for _, v := range e.values {
+		d.Value = append(d.Value, &descriptorpb.EnumValueDescriptorProto{Name: proto.String(v.name), Number: proto.Int32(v.number)})
+	}
+	return d
+}
+
+func (m *msgDef) descriptor() *descriptorpb.DescriptorProto {
+	d := &descriptorpb.DescriptorProto{Name: proto.String(m.name)}
+	for _, e := range m.enums {
+		d.EnumType = append(d.EnumType, e.descriptor())
+	}
+	for _, n := range m.nested {
+		d.NestedType = append(d.NestedType, n.descriptor())
+	}
+	for _, o := range m.oneofs {
+		d.OneofDecl = append(d.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(o)})
+	}
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	for _, f := range m.fields {
+		fp := &descriptorpb.FieldDescriptorProto{Name: proto.String(f.name), Number: proto.Int32(f.number), Label: optional}
+		switch {
+		case f.key != "":
+			// A map is a repeated field of a synthesized entry message.
+			entry := &descriptorpb.DescriptorProto{
+				Name:    proto.String(strings.ToUpper(f.name[:1]) + f.name[1:] + "Entry"),
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				Field: []*descriptorpb.FieldDescriptorProto{
+					setType(&descriptorpb.FieldDescriptorProto{Name: proto.String("key"), Number: proto.Int32(1), Label: optional}, typeRef{scalar: f.key}),
+					setType(&descriptorpb.FieldDescriptorProto{Name: proto.String("value"), Number: proto.Int32(2), Label: optional}, f.typ),
+				},
+			}
+			d.NestedType = append(d.NestedType, entry)
+			fp.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+			fp.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+			fp.TypeName = proto.String("." + fuzzPackage + "." + m.path + "." + entry.GetName())
+		case f.repeated:
+			fp.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+			setType(fp, f.typ)
+		default:
+			setType(fp, f.typ)
+		}
+		if f.oneof >= 0 {
+			fp.OneofIndex = proto.Int32(int32(f.oneof))
+		}
+		d.Field = append(d.Field, fp)
+	}
+	// Each proto3 optional field gets a synthetic oneof, after the real ones.
+	for i, f := range m.fields {
+		if f.optional {
+			d.Field[i].Proto3Optional = proto.Bool(true)
+			d.Field[i].OneofIndex = proto.Int32(int32(len(d.OneofDecl)))
+			d.OneofDecl = append(d.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String("_" + f.name)})
+		}
+	}
+	return d
+}
+
+func setType(fp *descriptorpb.FieldDescriptorProto, t typeRef) *descriptorpb.FieldDescriptorProto {
+	switch {
+	case t.enum != nil:
+		fp.Type = descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum()
+		fp.TypeName = proto.String("." + fuzzPackage + "." + t.enum.path)
+	case t.msg != nil:
+		fp.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+		fp.TypeName = proto.String("." + fuzzPackage + "." + t.msg.path)
+	default:
+		v := descriptorpb.FieldDescriptorProto_Type_value["TYPE_"+strings.ToUpper(t.scalar)]
+		fp.Type = descriptorpb.FieldDescriptorProto_Type(v).Enum()
+	}
+	return fp
+}
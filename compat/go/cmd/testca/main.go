@@ -0,0 +1,37 @@
+// Command testca writes a throwaway CA and the server and client
+// certificates it signs, for running rpcserver and rpcclient under TLS:
+//
+//	testca -out certs -hosts 127.0.0.1,localhost
+//	rpcserver -listen tcp:127.0.0.1:9000 -tls-cert certs/server.pem -tls-key certs/server-key.pem -tls-ca certs/ca.pem
+//	rpcclient -connect tcp:127.0.0.1:9000 -tls-ca certs/ca.pem -tls-cert certs/client.pem -tls-key certs/client-key.pem
+//
+// Giving the server -tls-ca makes it demand a client certificate (mutual
+// TLS); leave it and the client's -tls-cert/-tls-key out for plain TLS.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"compat/testca"
+)
+
+func main() {
+	out := flag.String("out", ".", "directory to write the certificates and keys to")
+	hosts := flag.String("hosts", "127.0.0.1,::1,localhost", "comma-separated names and IP addresses the server certificate is valid for")
+	flag.Parse()
+
+	b, err := testca.New(strings.Split(*hosts, ",")...)
+	if err == nil {
+		err = os.MkdirAll(*out, 0o755)
+	}
+	if err == nil {
+		err = b.WriteDir(*out)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "testca: %v\n", err)
+		os.Exit(1)
+	}
+}
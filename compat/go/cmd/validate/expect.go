@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"compat/pb"
+	"compat/testcases"
+)
+
+// expectations selects cases by their tags, for -skip-tags and -only-tags,
+// and with xfail, for -expect-failures, judges those tagged with a known Zig
+// bug: such a case failing is expected and does not fail the run, while one
+// passing does, so the stale tag is noticed. It counts what it did for
+// report.
+type expectations struct {
+	filter testcases.TagFilter
+	xfail  bool
+
+	skipped int
+	// xfailed lists the cases that failed as expected, by bug, and
+	// xpassed the tagged cases that passed.
+	xfailed map[testcases.Tag][]string
+	xpassed []string
+}
+
+// caseTags returns the tags of the generated cases by name.
+func caseTags(c testcases.Category, generated []testcases.TestCase) map[string][]testcases.Tag {
+	tags := map[string][]testcases.Tag{}
+	for _, tc := range generated {
+		tags[tc.Name] = c.CaseTags(tc)
+	}
+	return tags
+}
+
+// count describes how many of cases will be validated, for the line that
+// starts a file.
+func (e *expectations) count(cases []testcases.RawTestCase, tags map[string][]testcases.Tag) string {
+	out := 0
+	for _, tc := range cases {
+		if _, ok := e.filter.Excludes(tags[tc.Name]); ok {
+			out++
+		}
+	}
+	if out == 0 {
+		return fmt.Sprintf("%d cases", len(cases))
+	}
+	return fmt.Sprintf("%d cases, %d left out by tag", len(cases), out)
+}
+
+// skip reports whether the filter leaves the case out, recording it in res
+// as skipped if so.
+func (e *expectations) skip(res *testcases.Results, direction, category, name string, tags []testcases.Tag) bool {
+	reason, out := e.filter.Excludes(tags)
+	if out {
+		e.skipped++
+		res.AddSkipped(direction, category, name, reason)
+	}
+	return out
+}
+
+// judge records the outcome of a case that failed n checks in res and
+// returns the failures it counts for.
+func (e *expectations) judge(res *testcases.Results, direction, category, name string, tags []testcases.Tag, n int, d time.Duration, diff *pb.ByteDiff) int {
+	bug, known := testcases.KnownBugOf(tags)
+	if !e.xfail || !known {
+		res.AddCase(direction, category, name, n, d, diff)
+		return n
+	}
+	res.AddKnownBug(direction, category, name, n, bug, d, diff)
+	label := fmt.Sprintf("%s %s/%s", direction, category, name)
+	if n == 0 {
+		fmt.Printf("  XPASS %s: passes, but is tagged %s; drop the tag\n", name, bug)
+		e.xpassed = append(e.xpassed, label+" ("+string(bug)+")")
+		return 1
+	}
+	fmt.Printf("  XFAIL %s: %d check(s) failed, as expected with %s\n", name, n, bug)
+	e.xfailed[bug] = append(e.xfailed[bug], label)
+	return 0
+}
+
+// report summarizes the cases left out and the expected failures, by bug.
+func (e *expectations) report() {
+	if e.skipped == 0 && len(e.xfailed) == 0 && len(e.xpassed) == 0 {
+		return
+	}
+	fmt.Println()
+	if e.skipped > 0 {
+		fmt.Printf("%d case(s) left out by tag\n", e.skipped)
+	}
+	bugs := make([]testcases.Tag, 0, len(e.xfailed))
+	for bug := range e.xfailed {
+		bugs = append(bugs, bug)
+	}
+	sort.Slice(bugs, func(i, j int) bool {
+		a, _ := bugs[i].KnownBug()
+		b, _ := bugs[j].KnownBug()
+		return a < b
+	})
+	for _, bug := range bugs {
+		fmt.Printf("XFAIL %s: %d case(s): %s\n", bug, len(e.xfailed[bug]), strings.Join(e.xfailed[bug], ", "))
+	}
+	for _, c := range e.xpassed {
+		fmt.Printf("XPASS %s\n", c)
+	}
+}
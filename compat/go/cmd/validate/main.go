@@ -1,542 +1,732 @@
 package main
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"flag"
 	"fmt"
-	"math"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
 
+	"compat/oracle"
 	"compat/pb"
+	"compat/pbutil"
 	"compat/testcases"
+	"compat/watchdog"
 
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 func main() {
-	zigDir := filepath.Join("..", "testdata", "zig")
-	failures := 0
-
-	failures += validateFile(zigDir, "scalar3", validateScalar3)
-	failures += validateFile(zigDir, "nested3", validateNested3)
-	failures += validateFile(zigDir, "enum3", validateEnum3)
-	failures += validateFile(zigDir, "oneof3", validateOneof3)
-	failures += validateFile(zigDir, "repeated3", validateRepeated3)
-	failures += validateFile(zigDir, "map3", validateMap3)
-	failures += validateFile(zigDir, "optional3", validateOptional3)
-	failures += validateFile(zigDir, "edge3", validateEdge3)
-	failures += validateFile(zigDir, "scalar2", validateScalar2)
-	failures += validateFile(zigDir, "required2", validateRequired2)
-	failures += validateFile(zigDir, "acp", validateAcp)
+	zigDir := flag.String("zig-dir", filepath.Join("..", "testdata", "zig"), "directory holding the Zig-produced corpus")
+	goDir := flag.String("go-dir", filepath.Join("..", "testdata", "go"), "directory holding the Go-generated corpus")
+	direction := flag.String("direction", "zig-to-go", "what to validate: zig-to-go, go-to-zig (the Zig re-encodings of the Go corpus), or both")
+	strict := flag.Bool("strict", false, "require Zig-produced bytes to match the Go deterministic encoding exactly")
+	strictAllow := flag.String("strict-allow", string(testcases.DivergenceMapOrder), "comma-separated divergences tolerated by -strict (map-order)")
+	coverage := flag.Bool("coverage", false, "report message fields never populated or asserted across the corpus")
+	warnCaseNames := flag.Bool("warn-case-names", false, "report Zig cases the validators do not know, and expected cases missing from the Zig corpus, as warnings instead of failures")
+	timing := flag.Bool("timing", false, "report the time taken per corpus file and the slowest cases")
+	slowThreshold := flag.Duration("slow-threshold", 100*time.Millisecond, "warn about cases that take longer than this to validate (0 = never)")
+	maxDecode := flag.Int("max-decode-bytes", 64<<20, "fail cases larger than this without decoding them (0 = no limit)")
+	var rd reader
+	flag.Int64Var(&rd.mmapMin, "mmap-min-bytes", testcases.DefaultMmapMin, "memory-map uncompressed corpus files at least this large instead of reading them (0 = never)")
+	only := flag.String("case", "", "validate only this case, named category/case; found through the corpus index if it has one")
+	scribble := flag.Bool("scribble", false, "also check that decoded messages do not change when their input buffer is overwritten")
+	archives := flag.Bool("archives", false, "also validate every archived corpus under -go-dir (v<N>/) with the current validators")
+	resultsPath := flag.String("results", "", "write every case's outcome to this file as a CompatResults message (proto3 JSON if the name ends in .json)")
+	fileTimeout := flag.Duration("file-timeout", 30*time.Second, "fail the run, naming the case and dumping goroutines, if one corpus file takes longer than this to validate (0 = no limit)")
+	oracleCmd := flag.String("oracle", "", `external decoder to compare with Go and Zig on every case, e.g. "protoc --decode={type} -I{include} {file}"; each payload is piped to it`)
+	oracleFormat := flag.String("oracle-format", string(oracle.FormatText), "what -oracle writes: text, json or binary")
+	oracleTimeout := flag.Duration("oracle-timeout", 30*time.Second, "how long one -oracle run may take")
+	protoDir := flag.String("proto-dir", filepath.Join("..", "proto"), "directory holding the .proto files, for -oracle's {include}")
+	skipTags := flag.String("skip-tags", "", "leave out cases with any of these comma-separated tags: proto2, requires-utf8-strict, large, known-zig-bug#N, or known-zig-bug for every known bug")
+	onlyTags := flag.String("only-tags", "", "validate only cases with at least one of these comma-separated tags, as -skip-tags names them")
+	expectFailures := flag.Bool("expect-failures", true, "report cases tagged known-zig-bug#N that fail as XFAIL instead of failures, and fail those that pass")
+	rejectResults := flag.Bool("reject-results", false, "check that the Zig side rejected each reject vector with its error category, from "+testcases.RejectResultsName+" in -zig-dir")
+	flag.Parse()
+
+	var zigToGo, goToZig bool
+	switch *direction {
+	case "zig-to-go":
+		zigToGo = true
+	case "go-to-zig":
+		goToZig = true
+	case "both":
+		zigToGo, goToZig = true, true
+	default:
+		fmt.Fprintf(os.Stderr, "validate: unknown -direction %q\n", *direction)
+		os.Exit(2)
+	}
 
-	if failures > 0 {
-		fmt.Fprintf(os.Stderr, "\n%d validation failure(s)\n", failures)
-		os.Exit(1)
+	if *only != "" {
+		var ok bool
+		if rd.category, rd.name, ok = strings.Cut(*only, "/"); !ok {
+			fmt.Fprintf(os.Stderr, "validate: -case %q is not category/case\n", *only)
+			os.Exit(2)
+		}
+		if _, ok := testcases.Lookup(rd.category); !ok {
+			fmt.Fprintf(os.Stderr, "validate: -case: unknown category %q\n", rd.category)
+			os.Exit(2)
+		}
 	}
-	fmt.Println("\nAll Zig test vectors validated successfully.")
-}
 
-func validateFile(dir, name string, validate func([]testcases.RawTestCase) int) int {
-	path := filepath.Join(dir, name+".bin")
-	data, err := os.ReadFile(path)
-	if err != nil {
-		fmt.Printf("SKIP %s: %v\n", name, err)
-		return 0
+	ex := &expectations{xfail: *expectFailures, xfailed: map[testcases.Tag][]string{}}
+	var err error
+	if ex.filter.Skip, err = testcases.ParseTags(*skipTags); err != nil {
+		fmt.Fprintf(os.Stderr, "validate: -skip-tags: %v\n", err)
+		os.Exit(2)
 	}
-	if len(data) == 0 {
-		fmt.Printf("SKIP %s: empty file\n", name)
-		return 0
+	if ex.filter.Only, err = testcases.ParseTags(*onlyTags); err != nil {
+		fmt.Fprintf(os.Stderr, "validate: -only-tags: %v\n", err)
+		os.Exit(2)
 	}
 
-	cases, err := testcases.ReadTestCases(data)
+	var exact map[testcases.Divergence]bool
+	if *strict {
+		if exact, err = testcases.ParseDivergences(*strictAllow); err != nil {
+			fmt.Fprintf(os.Stderr, "validate: -strict-allow: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	orc, err := oracle.New(*oracleCmd, oracle.Format(*oracleFormat), *protoDir, *oracleTimeout)
 	if err != nil {
-		fmt.Printf("FAIL %s: framing error: %v\n", name, err)
-		return 1
+		fmt.Fprintf(os.Stderr, "validate: -oracle: %v\n", err)
+		os.Exit(2)
 	}
 
-	fmt.Printf("validating %s (%d cases)...\n", name, len(cases))
-	return validate(cases)
-}
+	var cov *testcases.Coverage
+	if *coverage {
+		cov = testcases.NewCoverage()
+	}
 
-func check(name, field string, ok bool) int {
-	if !ok {
-		fmt.Printf("  FAIL %s.%s\n", name, field)
-		return 1
+	cfg := runConfig{
+		exact:     exact,
+		scribble:  *scribble,
+		warnNames: *warnCaseNames,
+		maxDecode: *maxDecode,
+		rd:        rd,
+		cov:       cov,
+		tm:        testcases.NewTiming(*slowThreshold),
+		wd:        watchdog.New(*fileTimeout, watchdog.Exit("validate")),
+		orc:       orc,
+		ex:        ex,
+	}
+	if *resultsPath != "" {
+		cfg.res = testcases.NewResults(strings.Join(append([]string{"validate"}, os.Args[1:]...), " "))
 	}
-	return 0
-}
 
-func validateScalar3(cases []testcases.RawTestCase) int {
-	failures := 0
-	for _, tc := range cases {
-		msg := &pb.ScalarMessage{}
-		if err := proto.Unmarshal(tc.Data, msg); err != nil {
-			fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
-			failures++
-			continue
+	failures := checkDeterminism()
+	// Only cmd/generate writes a manifest. Every direction depends on the
+	// Go corpus, which the Zig side reads too, so it is checked up front.
+	failures += verifyManifest(*goDir)
+	if zigToGo {
+		fmt.Printf("== zig-to-go: validating %s\n", *zigDir)
+		for _, c := range testcases.Categories() {
+			if rd.skip(c.Name) {
+				continue
+			}
+			failures += validateFile(*zigDir, c, cfg)
+			if testcases.HasJSON(c.Name) {
+				failures += validateJSONFile(filepath.Join(*zigDir, testcases.JSONDir), c, cfg)
+			}
 		}
+		failures += validateDescriptorSets(*zigDir)
+	}
+	if goToZig {
+		fmt.Printf("== go-to-zig: comparing %s with %s\n", *goDir, *zigDir)
+		for _, c := range testcases.Categories() {
+			if rd.skip(c.Name) {
+				continue
+			}
+			failures += validateRoundTrip(*goDir, *zigDir, c, cfg)
+		}
+	}
+
+	if *archives {
+		failures += validateArchives(*goDir, cfg)
+	}
+	if *rejectResults {
+		failures += validateRejectResults(*zigDir, cfg.res)
+	}
 
-		switch tc.Name {
-		case "all_defaults":
-			failures += check(tc.Name, "f_double", msg.FDouble == 0)
-			failures += check(tc.Name, "f_int32", msg.FInt32 == 0)
-			failures += check(tc.Name, "f_bool", msg.FBool == false)
-		case "all_set":
-			failures += check(tc.Name, "f_double", msg.FDouble == 1.5)
-			failures += check(tc.Name, "f_float", msg.FFloat == 2.5)
-			failures += check(tc.Name, "f_int32", msg.FInt32 == 42)
-			failures += check(tc.Name, "f_int64", msg.FInt64 == 100000)
-			failures += check(tc.Name, "f_uint32", msg.FUint32 == 200)
-			failures += check(tc.Name, "f_uint64", msg.FUint64 == 300000)
-			failures += check(tc.Name, "f_sint32", msg.FSint32 == -10)
-			failures += check(tc.Name, "f_sint64", msg.FSint64 == -20000)
-			failures += check(tc.Name, "f_fixed32", msg.FFixed32 == 999)
-			failures += check(tc.Name, "f_fixed64", msg.FFixed64 == 888888)
-			failures += check(tc.Name, "f_sfixed32", msg.FSfixed32 == -55)
-			failures += check(tc.Name, "f_sfixed64", msg.FSfixed64 == -66666)
-			failures += check(tc.Name, "f_bool", msg.FBool == true)
-			failures += check(tc.Name, "f_string", msg.FString == "hello")
-			failures += check(tc.Name, "f_bytes", string(msg.FBytes) == "world")
-			failures += check(tc.Name, "f_large_tag", msg.FLargeTag == 77)
-		case "max_values":
-			failures += check(tc.Name, "f_int32", msg.FInt32 == math.MaxInt32)
-			failures += check(tc.Name, "f_int64", msg.FInt64 == math.MaxInt64)
-			failures += check(tc.Name, "f_uint32", msg.FUint32 == math.MaxUint32)
-			failures += check(tc.Name, "f_uint64", msg.FUint64 == math.MaxUint64)
-		case "min_values":
-			failures += check(tc.Name, "f_int32", msg.FInt32 == math.MinInt32)
-			failures += check(tc.Name, "f_int64", msg.FInt64 == math.MinInt64)
-		case "large_tag_only":
-			failures += check(tc.Name, "f_large_tag", msg.FLargeTag == 12345)
+	ex.report()
+	if cov != nil {
+		fmt.Println()
+		cov.Report(os.Stdout)
+	}
+	if *timing {
+		fmt.Println()
+		cfg.tm.Report(os.Stdout)
+	}
+
+	if cfg.res != nil {
+		if err := testcases.WriteResults(*resultsPath, cfg.res.Finish()); err != nil {
+			fmt.Fprintf(os.Stderr, "validate: -results: %v\n", err)
+			os.Exit(2)
 		}
+		fmt.Printf("wrote %s\n", *resultsPath)
 	}
-	return failures
+
+	if failures > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d validation failure(s)\n", failures)
+		os.Exit(1)
+	}
+	fmt.Println("\nAll Zig test vectors validated successfully.")
 }
 
-func validateNested3(cases []testcases.RawTestCase) int {
+// checkDeterminism builds every category's corpus twice and fails if the
+// bytes differ, which would turn map ordering into spurious cross-run diffs.
+func checkDeterminism() int {
 	failures := 0
-	for _, tc := range cases {
-		msg := &pb.Outer{}
-		if err := proto.Unmarshal(tc.Data, msg); err != nil {
-			fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+	for _, c := range testcases.Categories() {
+		first, _, err := testcases.BuildCorpus(c)
+		if err != nil {
+			fmt.Printf("FAIL determinism %s: %v\n", c.Name, err)
 			failures++
 			continue
 		}
-
-		switch tc.Name {
-		case "empty":
-			failures += check(tc.Name, "middle", msg.Middle == nil)
-			failures += check(tc.Name, "direct_inner", msg.DirectInner == nil)
-		case "two_levels":
-			failures += check(tc.Name, "middle.id", msg.Middle != nil && msg.Middle.Id == 10)
-			failures += check(tc.Name, "middle.inner.value", msg.Middle != nil && msg.Middle.Inner != nil && msg.Middle.Inner.Value == 42)
-			failures += check(tc.Name, "middle.inner.label", msg.Middle != nil && msg.Middle.Inner != nil && msg.Middle.Inner.Label == "inner_label")
-			failures += check(tc.Name, "direct_inner.value", msg.DirectInner != nil && msg.DirectInner.Value == 99)
-			failures += check(tc.Name, "direct_inner.label", msg.DirectInner != nil && msg.DirectInner.Label == "direct")
-			failures += check(tc.Name, "name", msg.Name == "outer")
-		case "single_level":
-			failures += check(tc.Name, "middle", msg.Middle == nil)
-			failures += check(tc.Name, "direct_inner.value", msg.DirectInner != nil && msg.DirectInner.Value == 5)
-			failures += check(tc.Name, "direct_inner.label", msg.DirectInner != nil && msg.DirectInner.Label == "only")
+		second, _, err := testcases.BuildCorpus(c)
+		if err != nil {
+			fmt.Printf("FAIL determinism %s: %v\n", c.Name, err)
+			failures++
+			continue
+		}
+		if !bytes.Equal(first, second) {
+			fmt.Printf("FAIL determinism %s: repeated generation produced different bytes\n", c.Name)
+			failures++
 		}
 	}
+	if failures == 0 {
+		fmt.Println("determinism ok")
+	}
 	return failures
 }
 
-func validateEnum3(cases []testcases.RawTestCase) int {
-	failures := 0
-	for _, tc := range cases {
-		msg := &pb.EnumMessage{}
-		if err := proto.Unmarshal(tc.Data, msg); err != nil {
-			fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
-			failures++
-			continue
+// verifyManifest checks the corpus files in dir, a Go corpus or an archive
+// of one, against its manifest, if one exists, so stale, truncated or
+// tampered corpora are reported before decoding.
+func verifyManifest(dir string) int {
+	manifest, err := testcases.ReadManifest(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			fmt.Printf("SKIP manifest: %v\n", err)
+			return 0
 		}
+		fmt.Printf("FAIL manifest: %v\n", err)
+		return 1
+	}
+	errs := manifest.Verify(dir)
+	for _, err := range errs {
+		fmt.Printf("FAIL manifest: %v\n", err)
+	}
+	if len(errs) == 0 {
+		fmt.Printf("manifest ok (%d files)\n", len(manifest.Files))
+	}
+	return len(errs)
+}
 
-		switch tc.Name {
-		case "default":
-			failures += check(tc.Name, "color", msg.Color == pb.Color_COLOR_UNSPECIFIED)
-		case "red":
-			failures += check(tc.Name, "color", msg.Color == pb.Color_COLOR_RED)
-			failures += check(tc.Name, "name", msg.Name == "red_test")
-		case "repeated":
-			failures += check(tc.Name, "color", msg.Color == pb.Color_COLOR_BLUE)
-			failures += check(tc.Name, "colors.len", len(msg.Colors) == 3)
-			if len(msg.Colors) == 3 {
-				failures += check(tc.Name, "colors[0]", msg.Colors[0] == pb.Color_COLOR_RED)
-				failures += check(tc.Name, "colors[1]", msg.Colors[1] == pb.Color_COLOR_GREEN)
-				failures += check(tc.Name, "colors[2]", msg.Colors[2] == pb.Color_COLOR_BLUE)
-			}
-			failures += check(tc.Name, "name", msg.Name == "multi")
-		}
+// reader is how readCorpus opens corpus files.
+type reader struct {
+	mmapMin int64 // see testcases.OpenCorpus
+	// category and name, if set, restrict validation to one case, for -case.
+	category, name string
+}
+
+// skip reports whether the category is left out by -case.
+func (rd reader) skip(category string) bool {
+	return rd.category != "" && rd.category != category
+}
+
+// open opens one corpus file, or only the -case case of it.
+func (rd reader) open(path string) (*testcases.CorpusFile, error) {
+	if rd.name != "" {
+		return testcases.OpenCorpusCase(path, rd.name, rd.mmapMin)
 	}
-	return failures
+	return testcases.OpenCorpus(path, rd.mmapMin)
 }
 
-func validateOneof3(cases []testcases.RawTestCase) int {
-	failures := 0
+// runConfig is how each corpus file is validated, as the flags say. main
+// builds it once and passes it to every validate function.
+type runConfig struct {
+	// exact, if non-nil, requires Zig bytes to match the Go deterministic
+	// encoding up to the divergences it allows, for -strict.
+	exact     map[testcases.Divergence]bool
+	scribble  bool // see testcases.CheckOwnership
+	warnNames bool // see checkCaseNames
+	maxDecode int  // see guardCase
+	rd        reader
+
+	cov *testcases.Coverage // nil unless -coverage
+	tm  *testcases.Timing
+	wd  *watchdog.Watchdog
+	orc *oracle.Oracle // nil unless -oracle
+	ex  *expectations
+	res *testcases.Results // nil unless -results
+}
+
+// readCorpus opens and unframes dir/name.bin, falling back to name.bin.gz,
+// as rd says. The caller closes the corpus. Missing or empty files are
+// reported as skipped and yield ok == false with no failure; framing errors
+// count as one failure.
+func readCorpus(dir, name string, rd reader) (corpus *testcases.CorpusFile, ok bool, failures int) {
+	path := filepath.Join(dir, name+".bin")
+	corpus, err := rd.open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		path += ".gz"
+		corpus, err = rd.open(path)
+	}
+	var pathErr *fs.PathError
+	switch {
+	case errors.As(err, &pathErr):
+		fmt.Printf("SKIP %s: %v\n", name, err)
+		return nil, false, 0
+	case err != nil:
+		fmt.Printf("FAIL %s: framing error: %v\n", path, err)
+		return nil, false, 1
+	case len(corpus.Cases) == 0 && rd.name != "":
+		fmt.Printf("FAIL %s: no case %s\n", path, rd.name)
+		corpus.Close()
+		return nil, false, 1
+	case len(corpus.Cases) == 0:
+		fmt.Printf("SKIP %s: empty file\n", name)
+		corpus.Close()
+		return nil, false, 0
+	}
+	return corpus, true, 0
+}
+
+// validateFile runs the category's validator over every case in dir. When
+// cfg.exact is non-nil, each case must also match the Go deterministic
+// encoding byte-for-byte, up to the divergences it allows. With
+// cfg.scribble, each case must also pass testcases.CheckOwnership. The case
+// names must match the generator's; see checkCaseNames. Cases cfg.ex leaves
+// out by their tags are skipped, and the rest decoded under guardCase and
+// judged by cfg.ex, and their outcome recorded in cfg.res. cfg.wd times the
+// file as a whole. With cfg.orc, each case is also compared with what the
+// oracle decodes it to.
+func validateFile(dir string, c testcases.Category, cfg runConfig) int {
+	start := time.Now()
+	corpus, ok, failures := readCorpus(dir, c.Name, cfg.rd)
+	if !ok {
+		cfg.res.AddFile(testcases.DirectionZigToGo, c.Name, failures > 0, "corpus file missing or unreadable")
+		return failures
+	}
+	defer corpus.Close()
+	cases := corpus.Cases
+	defer func() { cfg.tm.AddFile(c.Name, time.Since(start)) }()
+
+	generated := c.Generate()
+	expected := map[string]proto.Message{}
+	for _, tc := range generated {
+		expected[tc.Name] = tc.Msg
+	}
+	tags := caseTags(c, generated)
+	fmt.Printf("validating %s (%s)...\n", c.Name, cfg.ex.count(cases, tags))
+	cfg.wd.Start(c.Name)
+	defer cfg.wd.Stop()
+	if cfg.rd.name == "" {
+		failures += checkCaseNames(cases, generated, cfg.warnNames)
+	}
+
 	for _, tc := range cases {
-		msg := &pb.OneofMessage{}
-		if err := proto.Unmarshal(tc.Data, msg); err != nil {
-			fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
-			failures++
+		if cfg.ex.skip(cfg.res, testcases.DirectionZigToGo, c.Name, tc.Name, tags[tc.Name]) {
 			continue
 		}
-
-		switch tc.Name {
-		case "none_set":
-			failures += check(tc.Name, "name", msg.Name == "empty")
-			failures += check(tc.Name, "value", msg.Value == nil)
-		case "string_variant":
-			failures += check(tc.Name, "name", msg.Name == "test")
-			if v, ok := msg.Value.(*pb.OneofMessage_StrVal); ok {
-				failures += check(tc.Name, "str_val", v.StrVal == "hello")
-			} else {
-				failures += check(tc.Name, "value_type", false)
+		testcases.TakeAssertions()
+		cfg.wd.Rename(c.Name + "/" + tc.Name)
+		caseStart := time.Now()
+		n := guardCase(tc, cfg.maxDecode, func() int {
+			n := c.Validate(tc)
+			if want := expected[tc.Name]; cfg.exact != nil && want != nil {
+				if err := testcases.CompareExact(tc.Data, want, cfg.exact); err != nil {
+					fmt.Printf("  FAIL %s: %v\n", tc.Name, err)
+					n++
+				}
 			}
-		case "int_variant":
-			failures += check(tc.Name, "name", msg.Name == "test")
-			if v, ok := msg.Value.(*pb.OneofMessage_IntVal); ok {
-				failures += check(tc.Name, "int_val", v.IntVal == 42)
-			} else {
-				failures += check(tc.Name, "value_type", false)
+			if cfg.scribble {
+				n += testcases.CheckOwnership(c, tc)
 			}
-		case "bytes_variant":
-			failures += check(tc.Name, "name", msg.Name == "test")
-			if v, ok := msg.Value.(*pb.OneofMessage_BytesVal); ok {
-				failures += check(tc.Name, "bytes_val", len(v.BytesVal) == 3 && v.BytesVal[0] == 0x01 && v.BytesVal[1] == 0x02 && v.BytesVal[2] == 0x03)
-			} else {
-				failures += check(tc.Name, "value_type", false)
+			if want := expected[tc.Name]; cfg.orc != nil && want != nil {
+				// The Zig side meant to encode want.
+				n += checkOracle(cfg.orc, tc.Name, want.ProtoReflect().Descriptor(), tc.Data, goView(tc.Data, want), oracle.View{Impl: "zig", Msg: want})
 			}
-		case "msg_variant":
-			failures += check(tc.Name, "name", msg.Name == "test")
-			if v, ok := msg.Value.(*pb.OneofMessage_MsgVal); ok {
-				failures += check(tc.Name, "msg_val.id", v.MsgVal != nil && v.MsgVal.Id == 1)
-				failures += check(tc.Name, "msg_val.text", v.MsgVal != nil && v.MsgVal.Text == "sub")
-			} else {
-				failures += check(tc.Name, "value_type", false)
+			if n > 0 {
+				dumpCase(tc, expected[tc.Name])
 			}
-		}
+			if cfg.cov != nil {
+				recordCoverage(cfg.cov, tc, expected[tc.Name], testcases.TakeAssertions())
+			}
+			return n
+		})
+		d := time.Since(caseStart)
+		reportSlow(cfg.tm, c.Name, tc, d)
+		failures += cfg.ex.judge(cfg.res, testcases.DirectionZigToGo, c.Name, tc.Name, tags[tc.Name], n, d, failedDiff(n, tc, expected[tc.Name]))
 	}
 	return failures
 }
 
-func validateOptional3(cases []testcases.RawTestCase) int {
-	failures := 0
+// validateJSONFile runs testcases.ValidateJSON over the category's JSON
+// corpus in dir, selecting and judging cases as validateFile does.
+func validateJSONFile(dir string, c testcases.Category, cfg runConfig) int {
+	corpus, ok, failures := readCorpus(dir, c.Name, cfg.rd)
+	if !ok {
+		cfg.res.AddFile(testcases.DirectionJSON, c.Name, failures > 0, "JSON corpus file missing or unreadable")
+		return failures
+	}
+	defer corpus.Close()
+	cases := corpus.Cases
+	generated := c.Generate()
+	tags := caseTags(c, generated)
+	fmt.Printf("validating %s JSON (%s)...\n", c.Name, cfg.ex.count(cases, tags))
+	cfg.wd.Start(c.Name + " JSON")
+	defer cfg.wd.Stop()
+	if cfg.rd.name == "" {
+		failures += checkCaseNames(cases, generated, cfg.warnNames)
+	}
 	for _, tc := range cases {
-		msg := &pb.OptionalMessage{}
-		if err := proto.Unmarshal(tc.Data, msg); err != nil {
-			fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
-			failures++
+		if cfg.ex.skip(cfg.res, testcases.DirectionJSON, c.Name, tc.Name, tags[tc.Name]) {
 			continue
 		}
-
-		switch tc.Name {
-		case "all_unset":
-			failures += check(tc.Name, "opt_int", msg.OptInt == nil)
-			failures += check(tc.Name, "opt_str", msg.OptStr == nil)
-			failures += check(tc.Name, "opt_bool", msg.OptBool == nil)
-			failures += check(tc.Name, "opt_double", msg.OptDouble == nil)
-		case "all_zero":
-			failures += check(tc.Name, "opt_int", msg.OptInt != nil && *msg.OptInt == 0)
-			failures += check(tc.Name, "opt_bool", msg.OptBool != nil && *msg.OptBool == false)
-			failures += check(tc.Name, "opt_double", msg.OptDouble != nil && *msg.OptDouble == 0.0)
-		case "all_nonzero":
-			failures += check(tc.Name, "opt_int", msg.OptInt != nil && *msg.OptInt == 42)
-			failures += check(tc.Name, "opt_str", msg.OptStr != nil && *msg.OptStr == "hello")
-			failures += check(tc.Name, "opt_bool", msg.OptBool != nil && *msg.OptBool == true)
-			failures += check(tc.Name, "opt_double", msg.OptDouble != nil && *msg.OptDouble == 3.14)
-			failures += check(tc.Name, "regular_int", msg.RegularInt == 100)
-		}
+		cfg.wd.Rename(c.Name + " JSON/" + tc.Name)
+		start := time.Now()
+		n := guardCase(tc, cfg.maxDecode, func() int {
+			n := testcases.ValidateJSON(c, tc)
+			if n > 0 {
+				fmt.Printf("    zig JSON: %s\n", tc.Data)
+			}
+			return n
+		})
+		failures += cfg.ex.judge(cfg.res, testcases.DirectionJSON, c.Name, tc.Name, tags[tc.Name], n, time.Since(start), nil)
 	}
 	return failures
 }
 
-func validateEdge3(cases []testcases.RawTestCase) int {
+// validateArchives runs the validators over each archived corpus in dir, so
+// an encoding that was valid when it was pinned stays valid. Cases added since
+// an archive was written are missing from it, and cases removed since are
+// unknown, so case names are only warned about. Known Zig bugs do not apply
+// to Go's corpora, so no failures are expected, whatever cfg.ex says.
+func validateArchives(dir string, cfg runConfig) int {
+	versions, err := testcases.Archives(dir)
+	if err != nil {
+		fmt.Printf("FAIL archives: %v\n", err)
+		return 1
+	}
+	if len(versions) == 0 {
+		fmt.Printf("SKIP archives: none under %s\n", dir)
+		return 0
+	}
+	// Archives are validated with the current validators only: no exact
+	// bytes, coverage, oracle or results, and names are only warned about.
+	cfg.exact, cfg.cov, cfg.orc, cfg.res = nil, nil, nil, nil
+	cfg.warnNames = true
+	cfg.ex = &expectations{filter: cfg.ex.filter}
 	failures := 0
-	for _, tc := range cases {
-		msg := &pb.EdgeMessage{}
-		if err := proto.Unmarshal(tc.Data, msg); err != nil {
-			fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
-			failures++
-			continue
-		}
-
-		switch tc.Name {
-		case "special_floats":
-			failures += check(tc.Name, "f_nan", math.IsNaN(msg.FNan))
-			failures += check(tc.Name, "f_pos_inf", math.IsInf(msg.FPosInf, 1))
-			failures += check(tc.Name, "f_neg_inf", math.IsInf(msg.FNegInf, -1))
-		case "extreme_ints":
-			failures += check(tc.Name, "f_max_int32", msg.FMaxInt32 == math.MaxInt32)
-			failures += check(tc.Name, "f_min_int32", msg.FMinInt32 == math.MinInt32)
-			failures += check(tc.Name, "f_max_int64", msg.FMaxInt64 == math.MaxInt64)
-			failures += check(tc.Name, "f_min_int64", msg.FMinInt64 == math.MinInt64)
-			failures += check(tc.Name, "f_max_uint32", msg.FMaxUint32 == math.MaxUint32)
-			failures += check(tc.Name, "f_max_uint64", msg.FMaxUint64 == math.MaxUint64)
-		case "unicode_and_binary":
-			failures += check(tc.Name, "f_unicode", msg.FUnicode == "hello \xc3\xa9\xc3\xa0\xc3\xbc \xe4\xb8\x96\xe7\x95\x8c")
-			failures += check(tc.Name, "f_binary.len", len(msg.FBinary) == 6)
-			if len(msg.FBinary) == 6 {
-				failures += check(tc.Name, "f_binary", msg.FBinary[0] == 0x00 && msg.FBinary[1] == 0x01 && msg.FBinary[2] == 0x02 && msg.FBinary[3] == 0xff && msg.FBinary[4] == 0xfe && msg.FBinary[5] == 0xfd)
+	for _, v := range versions {
+		adir := testcases.ArchiveDir(dir, v)
+		fmt.Printf("== archive v%d: validating %s\n", v, adir)
+		failures += verifyManifest(adir)
+		for _, c := range testcases.Categories() {
+			if cfg.rd.skip(c.Name) {
+				continue
+			}
+			failures += validateFile(adir, c, cfg)
+			if testcases.HasJSON(c.Name) {
+				failures += validateJSONFile(filepath.Join(adir, testcases.JSONDir), c, cfg)
 			}
 		}
+		failures += validateDescriptorSets(adir)
 	}
 	return failures
 }
 
-func validateScalar2(cases []testcases.RawTestCase) int {
+// validateRejectResults checks the error category the Zig side recorded for
+// each reject vector against the one the vector was built to trigger.
+func validateRejectResults(dir string, res *testcases.Results) int {
+	path := filepath.Join(dir, testcases.RejectResultsName)
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Printf("FAIL reject results: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+	results, err := testcases.ReadRejectResults(f)
+	if err != nil {
+		fmt.Printf("FAIL %s: %v\n", path, err)
+		return 1
+	}
+	fmt.Printf("validating %s (%d results)...\n", path, len(results))
+	return testcases.CheckRejectResults(results, res)
+}
+
+// validateDescriptorSets checks each descriptor set the Zig side emitted in
+// dir, skipping those it did not emit.
+func validateDescriptorSets(dir string) int {
 	failures := 0
-	for _, tc := range cases {
-		msg := &pb.Scalar2Message{}
-		if err := proto.Unmarshal(tc.Data, msg); err != nil {
-			fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
-			failures++
+	for _, ds := range testcases.DescriptorSets {
+		data, err := os.ReadFile(filepath.Join(dir, ds.Name))
+		if err != nil {
+			fmt.Printf("SKIP %s: %v\n", ds.Name, err)
 			continue
 		}
-
-		switch tc.Name {
-		case "all_absent":
-			failures += check(tc.Name, "f_double", msg.FDouble == nil)
-			failures += check(tc.Name, "f_int32", msg.FInt32 == nil)
-			failures += check(tc.Name, "f_string", msg.FString == nil)
-		case "all_set":
-			failures += check(tc.Name, "f_double", msg.FDouble != nil && *msg.FDouble == 1.5)
-			failures += check(tc.Name, "f_float", msg.FFloat != nil && *msg.FFloat == 2.5)
-			failures += check(tc.Name, "f_int32", msg.FInt32 != nil && *msg.FInt32 == 42)
-			failures += check(tc.Name, "f_int64", msg.FInt64 != nil && *msg.FInt64 == 100000)
-			failures += check(tc.Name, "f_uint32", msg.FUint32 != nil && *msg.FUint32 == 200)
-			failures += check(tc.Name, "f_uint64", msg.FUint64 != nil && *msg.FUint64 == 300000)
-			failures += check(tc.Name, "f_sint32", msg.FSint32 != nil && *msg.FSint32 == -10)
-			failures += check(tc.Name, "f_sint64", msg.FSint64 != nil && *msg.FSint64 == -20000)
-			failures += check(tc.Name, "f_fixed32", msg.FFixed32 != nil && *msg.FFixed32 == 999)
-			failures += check(tc.Name, "f_fixed64", msg.FFixed64 != nil && *msg.FFixed64 == 888888)
-			failures += check(tc.Name, "f_sfixed32", msg.FSfixed32 != nil && *msg.FSfixed32 == -55)
-			failures += check(tc.Name, "f_sfixed64", msg.FSfixed64 != nil && *msg.FSfixed64 == -66666)
-			failures += check(tc.Name, "f_bool", msg.FBool != nil && *msg.FBool == true)
-			failures += check(tc.Name, "f_string", msg.FString != nil && *msg.FString == "hello")
-			failures += check(tc.Name, "f_bytes", string(msg.FBytes) == "world")
-		}
+		fmt.Printf("validating %s...\n", ds.Name)
+		failures += ds.Validate(data)
 	}
 	return failures
 }
 
-func validateRepeated3(cases []testcases.RawTestCase) int {
-	failures := 0
+// checkCaseNames compares the case names of a Zig corpus file with the
+// generator's. A name the generator does not have gets no expectations, since
+// validators switch on names and ignore the rest, and a missing name means a
+// case the Zig side stopped writing; both would otherwise pass silently.
+// Cases marked GoOnly are not expected. Mismatches are failures unless warn
+// is set.
+func checkCaseNames(cases []testcases.RawTestCase, generated []testcases.TestCase, warn bool) int {
+	label := "FAIL"
+	if warn {
+		label = "WARN"
+	}
+	known := map[string]bool{}
+	for _, tc := range generated {
+		known[tc.Name] = true
+	}
+	seen := map[string]bool{}
+	mismatches := 0
 	for _, tc := range cases {
-		msg := &pb.RepeatedMessage{}
-		if err := proto.Unmarshal(tc.Data, msg); err != nil {
-			fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
-			failures++
-			continue
+		seen[tc.Name] = true
+		if !known[tc.Name] {
+			fmt.Printf("  %s %s: no expectations for this case name\n", label, tc.Name)
+			mismatches++
 		}
-
-		switch tc.Name {
-		case "empty":
-			failures += check(tc.Name, "ints.len", len(msg.Ints) == 0)
-			failures += check(tc.Name, "strings.len", len(msg.Strings) == 0)
-			failures += check(tc.Name, "doubles.len", len(msg.Doubles) == 0)
-			failures += check(tc.Name, "bools.len", len(msg.Bools) == 0)
-			failures += check(tc.Name, "byte_slices.len", len(msg.ByteSlices) == 0)
-			failures += check(tc.Name, "items.len", len(msg.Items) == 0)
-		case "single":
-			failures += check(tc.Name, "ints.len", len(msg.Ints) == 1)
-			if len(msg.Ints) == 1 {
-				failures += check(tc.Name, "ints[0]", msg.Ints[0] == 1)
-			}
-			failures += check(tc.Name, "strings.len", len(msg.Strings) == 1)
-			if len(msg.Strings) == 1 {
-				failures += check(tc.Name, "strings[0]", msg.Strings[0] == "hello")
-			}
-			failures += check(tc.Name, "doubles.len", len(msg.Doubles) == 1)
-			if len(msg.Doubles) == 1 {
-				failures += check(tc.Name, "doubles[0]", msg.Doubles[0] == 1.5)
-			}
-			failures += check(tc.Name, "bools.len", len(msg.Bools) == 1)
-			if len(msg.Bools) == 1 {
-				failures += check(tc.Name, "bools[0]", msg.Bools[0] == true)
-			}
-			failures += check(tc.Name, "byte_slices.len", len(msg.ByteSlices) == 1)
-			if len(msg.ByteSlices) == 1 {
-				failures += check(tc.Name, "byte_slices[0]", len(msg.ByteSlices[0]) == 1 && msg.ByteSlices[0][0] == 0x01)
-			}
-			failures += check(tc.Name, "items.len", len(msg.Items) == 1)
-			if len(msg.Items) == 1 {
-				failures += check(tc.Name, "items[0].id", msg.Items[0].Id == 1)
-				failures += check(tc.Name, "items[0].name", msg.Items[0].Name == "first")
-			}
-		case "multiple":
-			failures += check(tc.Name, "ints.len", len(msg.Ints) == 3)
-			if len(msg.Ints) == 3 {
-				failures += check(tc.Name, "ints[0]", msg.Ints[0] == 1)
-				failures += check(tc.Name, "ints[1]", msg.Ints[1] == 2)
-				failures += check(tc.Name, "ints[2]", msg.Ints[2] == 3)
-			}
-			failures += check(tc.Name, "strings.len", len(msg.Strings) == 3)
-			if len(msg.Strings) == 3 {
-				failures += check(tc.Name, "strings[0]", msg.Strings[0] == "a")
-				failures += check(tc.Name, "strings[1]", msg.Strings[1] == "b")
-				failures += check(tc.Name, "strings[2]", msg.Strings[2] == "c")
-			}
-			failures += check(tc.Name, "doubles.len", len(msg.Doubles) == 3)
-			failures += check(tc.Name, "bools.len", len(msg.Bools) == 3)
-			if len(msg.Bools) == 3 {
-				failures += check(tc.Name, "bools[0]", msg.Bools[0] == true)
-				failures += check(tc.Name, "bools[1]", msg.Bools[1] == false)
-				failures += check(tc.Name, "bools[2]", msg.Bools[2] == true)
-			}
-			failures += check(tc.Name, "byte_slices.len", len(msg.ByteSlices) == 2)
-			failures += check(tc.Name, "items.len", len(msg.Items) == 2)
-			if len(msg.Items) == 2 {
-				failures += check(tc.Name, "items[0].id", msg.Items[0].Id == 1)
-				failures += check(tc.Name, "items[0].name", msg.Items[0].Name == "one")
-				failures += check(tc.Name, "items[1].id", msg.Items[1].Id == 2)
-				failures += check(tc.Name, "items[1].name", msg.Items[1].Name == "two")
-			}
+	}
+	for _, tc := range generated {
+		if !tc.GoOnly && !seen[tc.Name] {
+			fmt.Printf("  %s %s: expected case missing from corpus\n", label, tc.Name)
+			mismatches++
 		}
 	}
-	return failures
+	if warn {
+		return 0
+	}
+	return mismatches
 }
 
-func validateMap3(cases []testcases.RawTestCase) int {
-	failures := 0
-	for _, tc := range cases {
-		msg := &pb.MapMessage{}
-		if err := proto.Unmarshal(tc.Data, msg); err != nil {
-			fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
-			failures++
-			continue
+// validateRoundTrip checks that the Zig side decoded every case of the Go
+// corpus and re-encoded it without loss: each must reappear under
+// testcases.RoundTripDir in zigDir and decode to a message equal to the Go
+// vector's. A Go corpus with no round-trip file fails. GoOnly cases are included: the Zig side cannot build them, but
+// it re-encodes what it decoded. Cases are selected and judged as
+// validateFile does. With cfg.orc, the Go vector is also compared with
+// what the oracle decodes it to.
+func validateRoundTrip(goDir, zigDir string, c testcases.Category, cfg runConfig) int {
+	start := time.Now()
+	goCorpus, ok, failures := readCorpus(goDir, c.Name, cfg.rd)
+	if !ok {
+		cfg.res.AddFile(testcases.DirectionGoToZig, c.Name, failures > 0, "Go corpus file missing or unreadable")
+		return failures
+	}
+	defer goCorpus.Close()
+	zigCorpus, ok, failures := readCorpus(filepath.Join(zigDir, testcases.RoundTripDir), c.Name, cfg.rd)
+	if !ok {
+		// The Go corpus has cases, so the Zig side should have re-encoded
+		// them; without this, a category it never writes would pass.
+		if failures == 0 {
+			fmt.Printf("FAIL %s: no Zig round trip of the Go corpus\n", c.Name)
+			failures = 1
 		}
+		cfg.res.AddFile(testcases.DirectionGoToZig, c.Name, true, "Zig round trip file missing or empty")
+		return failures
+	}
+	defer zigCorpus.Close()
+	goCases, zigCases := goCorpus.Cases, zigCorpus.Cases
+	zigByName := map[string]testcases.RawTestCase{}
+	for _, tc := range zigCases {
+		zigByName[tc.Name] = tc
+	}
 
-		switch tc.Name {
-		case "empty":
-			failures += check(tc.Name, "str_str.len", len(msg.StrStr) == 0)
-			failures += check(tc.Name, "int_str.len", len(msg.IntStr) == 0)
-			failures += check(tc.Name, "str_msg.len", len(msg.StrMsg) == 0)
-		case "single":
-			failures += check(tc.Name, "str_str.len", len(msg.StrStr) == 1)
-			failures += check(tc.Name, "str_str[key]", msg.StrStr["key"] == "val")
-			failures += check(tc.Name, "int_str.len", len(msg.IntStr) == 1)
-			failures += check(tc.Name, "int_str[1]", msg.IntStr[1] == "one")
-			failures += check(tc.Name, "str_msg.len", len(msg.StrMsg) == 1)
-			if sub, ok := msg.StrMsg["a"]; ok {
-				failures += check(tc.Name, "str_msg[a].id", sub.Id == 1)
-				failures += check(tc.Name, "str_msg[a].text", sub.Text == "alpha")
-			} else {
-				failures += check(tc.Name, "str_msg[a]", false)
-			}
-		case "multiple":
-			failures += check(tc.Name, "str_str.len", len(msg.StrStr) == 2)
-			failures += check(tc.Name, "str_str[a]", msg.StrStr["a"] == "1")
-			failures += check(tc.Name, "str_str[b]", msg.StrStr["b"] == "2")
-			failures += check(tc.Name, "int_str.len", len(msg.IntStr) == 2)
-			failures += check(tc.Name, "int_str[1]", msg.IntStr[1] == "one")
-			failures += check(tc.Name, "int_str[2]", msg.IntStr[2] == "two")
-			failures += check(tc.Name, "str_msg.len", len(msg.StrMsg) == 2)
-			if sub, ok := msg.StrMsg["x"]; ok {
-				failures += check(tc.Name, "str_msg[x].id", sub.Id == 10)
-				failures += check(tc.Name, "str_msg[x].text", sub.Text == "x")
-			} else {
-				failures += check(tc.Name, "str_msg[x]", false)
-			}
-			if sub, ok := msg.StrMsg["y"]; ok {
-				failures += check(tc.Name, "str_msg[y].id", sub.Id == 20)
-				failures += check(tc.Name, "str_msg[y].text", sub.Text == "y")
-			} else {
-				failures += check(tc.Name, "str_msg[y]", false)
-			}
+	defer func() { cfg.tm.AddFile(c.Name+" (round trip)", time.Since(start)) }()
+
+	generated := c.Generate()
+	msgType := map[string]proto.Message{}
+	for _, tc := range generated {
+		msgType[tc.Name] = tc.Msg
+	}
+	tags := caseTags(c, generated)
+	fmt.Printf("round-tripping %s (%s)...\n", c.Name, cfg.ex.count(goCases, tags))
+	cfg.wd.Start(c.Name + " round trip")
+	defer cfg.wd.Stop()
+	for _, goCase := range goCases {
+		if cfg.ex.skip(cfg.res, testcases.DirectionGoToZig, c.Name, goCase.Name, tags[goCase.Name]) {
+			continue
 		}
+		ref, known := msgType[goCase.Name]
+		if !known {
+			fmt.Printf("  FAIL %s: no reference case in generator\n", goCase.Name)
+			failures += cfg.ex.judge(cfg.res, testcases.DirectionGoToZig, c.Name, goCase.Name, tags[goCase.Name], 1, 0, nil)
+			continue
+		}
+		zigCase, found := zigByName[goCase.Name]
+		if !found {
+			// Usually a Zig decoding bug, so a case tagged with one is
+			// judged like any other failure.
+			fmt.Printf("  FAIL %s: missing from Zig round trip; the Zig side could not decode it\n", goCase.Name)
+			failures += cfg.ex.judge(cfg.res, testcases.DirectionGoToZig, c.Name, goCase.Name, tags[goCase.Name], 1, 0, nil)
+			continue
+		}
+		cfg.wd.Rename(c.Name + " round trip/" + goCase.Name)
+		caseStart := time.Now()
+		n := guardCase(zigCase, cfg.maxDecode, func() int { return roundTripCase(goCase, zigCase, ref, cfg.orc) })
+		d := time.Since(caseStart)
+		reportSlow(cfg.tm, c.Name, goCase, d)
+		var diff *pb.ByteDiff
+		if n > 0 {
+			diff = testcases.DiffBytes(goCase.Data, zigCase.Data)
+		}
+		failures += cfg.ex.judge(cfg.res, testcases.DirectionGoToZig, c.Name, goCase.Name, tags[goCase.Name], n, d, diff)
 	}
 	return failures
 }
 
-func validateRequired2(cases []testcases.RawTestCase) int {
-	failures := 0
-	for _, tc := range cases {
-		msg := &pb.Required2Message{}
-		if err := proto.Unmarshal(tc.Data, msg); err != nil {
-			fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
-			failures++
-			continue
-		}
+// roundTripCase decodes both sides of one case as ref's type and compares
+// them, and with orc, both with the oracle's decoding of the Go vector.
+func roundTripCase(goCase, zigCase testcases.RawTestCase, ref proto.Message, orc *oracle.Oracle) int {
+	want := ref.ProtoReflect().Type().New().Interface()
+	if err := proto.Unmarshal(goCase.Data, want); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal Go vector: %v\n", goCase.Name, err)
+		return 1
+	}
+	n := 0
+	zig := goView(zigCase.Data, ref)
+	zig.Impl = "zig"
+	if zig.Err != nil {
+		fmt.Printf("  FAIL %s: unmarshal Zig re-encoding: %v\n", goCase.Name, zig.Err)
+		dumpCase(zigCase, want)
+		n++
+	} else if !proto.Equal(want, zig.Msg) {
+		fmt.Printf("  FAIL %s: Zig re-encoding differs from Go vector\n", goCase.Name)
+		dumpCase(zigCase, want)
+		n++
+	}
+	if orc != nil {
+		n += checkOracle(orc, goCase.Name, ref.ProtoReflect().Descriptor(), goCase.Data, oracle.View{Impl: "go", Msg: want}, zig)
+	}
+	return n
+}
 
-		switch tc.Name {
-		case "all_present":
-			failures += check(tc.Name, "req_id", msg.ReqId != nil && *msg.ReqId == 42)
-			failures += check(tc.Name, "req_name", msg.ReqName != nil && *msg.ReqName == "required")
-			failures += check(tc.Name, "opt_value", msg.OptValue != nil && *msg.OptValue == 10)
-			failures += check(tc.Name, "opt_label", msg.OptLabel != nil && *msg.OptLabel == "optional")
-		case "required_only":
-			failures += check(tc.Name, "req_id", msg.ReqId != nil && *msg.ReqId == 1)
-			failures += check(tc.Name, "req_name", msg.ReqName != nil && *msg.ReqName == "min")
-			failures += check(tc.Name, "opt_value", msg.OptValue == nil)
-			failures += check(tc.Name, "opt_label", msg.OptLabel == nil)
-		}
+// goView is Go's decoding of payload as a message of ref's type.
+func goView(payload []byte, ref proto.Message) oracle.View {
+	msg := ref.ProtoReflect().Type().New().Interface()
+	if err := proto.Unmarshal(payload, msg); err != nil {
+		return oracle.View{Impl: "go", Err: &oracle.Rejected{Reason: err.Error()}}
 	}
-	return failures
+	return oracle.View{Impl: "go", Msg: msg}
 }
 
-func validateAcp(cases []testcases.RawTestCase) int {
-	failures := 0
-	for _, tc := range cases {
-		msg := &pb.AcpMessage{}
-		if err := proto.Unmarshal(tc.Data, msg); err != nil {
-			fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
-			failures++
-			continue
+// checkOracle has orc decode payload and fails the case, naming the
+// implementation that deviates, unless it agrees with views.
+func checkOracle(orc *oracle.Oracle, name string, md protoreflect.MessageDescriptor, payload []byte, views ...oracle.View) int {
+	report, err := orc.Check(md, payload, views...)
+	if err != nil {
+		fmt.Printf("  FAIL %s: %v\n", name, err)
+		return 1
+	}
+	if report != "" {
+		fmt.Printf("  FAIL %s: %s\n", name, strings.ReplaceAll(report, "\n", "\n  "))
+		return 1
+	}
+	return 0
+}
+
+// guardCase runs check over one untrusted case. A case over maxDecode bytes
+// fails without being decoded, since decoding can take several times its
+// size in memory and one pathological vector must not take the whole run
+// down; a panic in a decoder or validator fails just that case. Both are
+// reported with enough of the payload to start debugging.
+func guardCase(tc testcases.RawTestCase, maxDecode int, check func() int) (failures int) {
+	if maxDecode > 0 && len(tc.Data) > maxDecode {
+		fmt.Printf("  FAIL %s: %d bytes is over the -max-decode-bytes limit of %d; not decoded\n", tc.Name, len(tc.Data), maxDecode)
+		fmt.Printf("    first 64 bytes:\n")
+		printIndented(hex.Dump(tc.Data[:min(64, len(tc.Data))]))
+		return 1
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("  FAIL %s: panic while decoding %d bytes: %v\n", tc.Name, len(tc.Data), r)
+			printIndented(string(debug.Stack()))
+			failures = 1
 		}
+	}()
+	return check()
+}
 
-		switch tc.Name {
-		case "empty":
-			failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_HELLO)
-			failures += check(tc.Name, "request_id", msg.RequestId == 0)
-		case "hello":
-			failures += check(tc.Name, "version", msg.Version != nil && *msg.Version == 1)
-			failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_HELLO)
-		case "request_with_uri":
-			failures += check(tc.Name, "version", msg.Version != nil && *msg.Version == 1)
-			failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_REQUEST)
-			failures += check(tc.Name, "request_id", msg.RequestId == 42)
-			failures += check(tc.Name, "uri", msg.Uri != nil && *msg.Uri == "asset://textures/wood.png")
-		case "discover_with_uris":
-			failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_DISCOVER)
-			failures += check(tc.Name, "request_id", msg.RequestId == 100)
-			failures += check(tc.Name, "uris.len", len(msg.Uris) == 3)
-			if len(msg.Uris) == 3 {
-				failures += check(tc.Name, "uris[0]", msg.Uris[0] == "asset://models/tree.glb")
-				failures += check(tc.Name, "uris[1]", msg.Uris[1] == "asset://textures/bark.png")
-				failures += check(tc.Name, "uris[2]", msg.Uris[2] == "asset://shaders/pbr.wgsl")
-			}
-		case "status_ok_with_metadata":
-			failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_STATUS)
-			failures += check(tc.Name, "status", msg.Status != nil && *msg.Status == pb.AcpStatusCode_OK)
-			failures += check(tc.Name, "metadata", msg.Metadata != nil)
-			if msg.Metadata != nil {
-				failures += check(tc.Name, "metadata.uri", msg.Metadata.Uri == "asset://textures/wood.png")
-				failures += check(tc.Name, "metadata.cache_path", msg.Metadata.CachePath == "/tmp/cache/abc123")
-				failures += check(tc.Name, "metadata.payload_hash", msg.Metadata.PayloadHash == "sha256:deadbeef")
-				failures += check(tc.Name, "metadata.file_length", msg.Metadata.FileLength == 1048576)
-				failures += check(tc.Name, "metadata.uri_version", msg.Metadata.UriVersion == 3)
-				failures += check(tc.Name, "metadata.updated_at_ns", msg.Metadata.UpdatedAtNs == 1700000000000000000)
-			}
-		case "status_not_found":
-			failures += check(tc.Name, "status", msg.Status != nil && *msg.Status == pb.AcpStatusCode_NOT_FOUND)
-			failures += check(tc.Name, "detail", msg.Detail != nil && *msg.Detail == "asset not found in registry")
-		case "updated_with_chunks":
-			failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_UPDATED)
-			failures += check(tc.Name, "chunk_index", msg.ChunkIndex == 3)
-			failures += check(tc.Name, "chunk_total", msg.ChunkTotal == 10)
-			failures += check(tc.Name, "metadata", msg.Metadata != nil)
-		case "force_recook":
-			failures += check(tc.Name, "version", msg.Version != nil && *msg.Version == 2)
-			failures += check(tc.Name, "force_recook", msg.ForceRecook != nil && *msg.ForceRecook == true)
-			failures += check(tc.Name, "uri", msg.Uri != nil && *msg.Uri == "asset://textures/grass.png")
-		case "all_status_codes":
-			failures += check(tc.Name, "status", msg.Status != nil && *msg.Status == pb.AcpStatusCode_INTERNAL_ERROR)
-			failures += check(tc.Name, "detail", msg.Detail != nil && *msg.Detail == "unexpected codec failure")
-		case "deload":
-			failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_DELOAD)
-			failures += check(tc.Name, "uris.len", len(msg.Uris) == 1)
+// reportSlow records how long a case took and warns if it was over the
+// threshold. Slow cases are not failures: a pathological input shows up here
+// long before it times out a run.
+func reportSlow(tm *testcases.Timing, file string, tc testcases.RawTestCase, d time.Duration) {
+	if tm.AddCase(file, tc.Name, d) {
+		fmt.Printf("  SLOW %s: %v (%d bytes)\n", tc.Name, d, len(tc.Data))
+	}
+}
+
+// failedDiff locates where the Zig bytes of a failed case first differ from
+// the Go encoding of want. It returns nil for a passing case or one with no
+// reference.
+func failedDiff(n int, tc testcases.RawTestCase, want proto.Message) *pb.ByteDiff {
+	if n == 0 || want == nil {
+		return nil
+	}
+	wantData, err := pbutil.Marshal(want)
+	if err != nil {
+		return nil
+	}
+	return testcases.DiffBytes(wantData, tc.Data)
+}
+
+// recordCoverage decodes the case into the expected message type and records
+// its populated fields and the fields its validator asserted on.
+func recordCoverage(cov *testcases.Coverage, tc testcases.RawTestCase, want proto.Message, asserted []string) {
+	if want == nil {
+		return
+	}
+	msg := want.ProtoReflect().Type().New()
+	if err := proto.Unmarshal(tc.Data, msg.Interface()); err != nil {
+		return
+	}
+	cov.AddPopulated(msg)
+	cov.AddAssertions(msg.Descriptor(), asserted)
+}
+
+// dumpCase prints the Zig payload of a failed case next to the Go encoding of
+// the expected message, both as a hex dump and as decoded wire structure.
+func dumpCase(tc testcases.RawTestCase, want proto.Message) {
+	fmt.Printf("    zig payload (%d bytes):\n", len(tc.Data))
+	printIndented(hex.Dump(tc.Data))
+	printIndented(testcases.DumpWire(tc.Data))
+	if want == nil {
+		fmt.Printf("    go expected: no reference case named %q\n", tc.Name)
+		return
+	}
+	wantData, err := pbutil.Marshal(want)
+	if err != nil {
+		fmt.Printf("    go expected: marshal: %v\n", err)
+		return
+	}
+	fmt.Printf("    go expected (%d bytes):\n", len(wantData))
+	printIndented(hex.Dump(wantData))
+	printIndented(testcases.DumpWire(wantData))
+}
+
+func printIndented(text string) {
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		if line != "" {
+			fmt.Printf("      %s\n", line)
 		}
 	}
-	return failures
 }
@@ -16,11 +16,46 @@ for proto in "$PROTO_DIR"/*.proto; do
     M_OPTS="$M_OPTS --go_opt=M${base}=compat/pb"
 done
 
+# protoc-gen-go rejects message_set_wire_format unless built with the
+# protolegacy tag. The generated code itself works without it.
+LEGACY_PROTOS="messageset2.proto"
+PROTOS=""
+for proto in "$PROTO_DIR"/*.proto; do
+    case " $LEGACY_PROTOS " in
+        *" $(basename "$proto") "*) ;;
+        *) PROTOS="$PROTOS $proto" ;;
+    esac
+done
+
+protoc \
+    --go_out="$OUT_DIR" \
+    --go_opt=paths=source_relative \
+    $M_OPTS \
+    -I "$PROTO_DIR" \
+    $PROTOS
+
+LEGACY_PLUGIN="$(mktemp -d)/protoc-gen-go"
+(cd "$SCRIPT_DIR" && go build -tags protolegacy -o "$LEGACY_PLUGIN" google.golang.org/protobuf/cmd/protoc-gen-go)
+protoc \
+    --plugin=protoc-gen-go="$LEGACY_PLUGIN" \
+    --go_out="$OUT_DIR" \
+    --go_opt=paths=source_relative \
+    $M_OPTS \
+    -I "$PROTO_DIR" \
+    $(for p in $LEGACY_PROTOS; do echo "$PROTO_DIR/$p"; done)
+rm -rf "$(dirname "$LEGACY_PLUGIN")"
+
+# Schemas with custom options import descriptor.proto, which protoc ships.
+# gateway3.proto also imports service_unary.proto from proto/.
+OPTIONS_DIR="$SCRIPT_DIR/../options"
 protoc \
     --go_out="$OUT_DIR" \
     --go_opt=paths=source_relative \
+    --go_opt=Moptions3.proto=compat/pb \
+    --go_opt=Mgateway3.proto=compat/pb \
     $M_OPTS \
+    -I "$OPTIONS_DIR" \
     -I "$PROTO_DIR" \
-    "$PROTO_DIR"/*.proto
+    "$OPTIONS_DIR"/*.proto
 
 echo "Done. Generated files in $OUT_DIR/"
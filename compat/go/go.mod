@@ -2,4 +2,7 @@ module compat
 
 go 1.23
 
-require google.golang.org/protobuf v1.36.11
+require (
+	github.com/klauspost/compress v1.18.0
+	google.golang.org/protobuf v1.36.11
+)
@@ -0,0 +1,376 @@
+// Package httpgatewaylib serves the methods of a service annotated with HTTP
+// rules as REST+JSON endpoints, turning each HTTP request into a unary call
+// on a pipe RPC backend, so that HTTP clients can exercise the same handlers
+// as the RPC suites.
+package httpgatewaylib
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"compat/pb"
+	"compat/pbutil"
+	"compat/rpcproto"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Backend makes unary calls over one pipe RPC connection, one at a time.
+type Backend struct {
+	mu     sync.Mutex
+	s      *rpcproto.StreamConn
+	broken error
+}
+
+// NewBackend returns a Backend speaking the protocol over rw.
+func NewBackend(rw io.ReadWriter) *Backend {
+	return &Backend{s: rpcproto.NewStreamConn(rpcproto.NewConn(rw, rw))}
+}
+
+// Unary calls method with reqBytes and returns the RESPONSE payload. A call
+// that stops before its RESPONSE or ERROR leaves frames in flight, so every
+// later call fails with the same error.
+func (b *Backend) Unary(ctx context.Context, method string, reqBytes []byte) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.broken != nil {
+		return nil, b.broken
+	}
+	respBytes, err := b.call(ctx, method, reqBytes)
+	if st := b.s.State(); st != rpcproto.StreamClosed {
+		b.broken = fmt.Errorf("backend connection left %s: %w", st, err)
+	}
+	return respBytes, err
+}
+
+func (b *Backend) call(ctx context.Context, method string, reqBytes []byte) ([]byte, error) {
+	if err := b.s.Call(ctx, method, reqBytes, false); err != nil {
+		return nil, fmt.Errorf("write call: %w", err)
+	}
+	return b.s.RecvResponse(ctx)
+}
+
+// Close tells the backend the gateway is done with it.
+func (b *Backend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.s.W.WriteShutdown()
+}
+
+// route is one method's HTTP rule, with its path split into segments. A
+// segment in braces binds the named request field.
+type route struct {
+	method   protoreflect.MethodDescriptor
+	verb     string
+	segments []string
+	body     string
+	rpcPath  string
+}
+
+// Gateway is an http.Handler for the annotated methods of a service.
+type Gateway struct {
+	backend *Backend
+	routes  []route
+}
+
+// New returns a Gateway for the methods of sd that carry an HTTP rule. Each
+// maps to the method of the same name on the backend, under the service's
+// short name as the reference servers register it.
+func New(sd protoreflect.ServiceDescriptor, backend *Backend) (*Gateway, error) {
+	g := &Gateway{backend: backend}
+	methods := sd.Methods()
+	for i := range methods.Len() {
+		md := methods.Get(i)
+		rule, ok := proto.GetExtension(md.Options(), pb.E_Http).(*pb.HttpRule)
+		if !ok || rule.GetPattern() == nil {
+			continue
+		}
+		if md.IsStreamingClient() || md.IsStreamingServer() {
+			return nil, fmt.Errorf("%s: streaming methods cannot have HTTP rules", md.FullName())
+		}
+		r, err := newRoute(md, rule)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", md.FullName(), err)
+		}
+		r.rpcPath = "/" + string(sd.Name()) + "/" + string(md.Name())
+		g.routes = append(g.routes, r)
+	}
+	return g, nil
+}
+
+func newRoute(md protoreflect.MethodDescriptor, rule *pb.HttpRule) (route, error) {
+	r := route{method: md, body: rule.GetBody()}
+	var path string
+	switch p := rule.GetPattern().(type) {
+	case *pb.HttpRule_Get:
+		r.verb, path = http.MethodGet, p.Get
+	case *pb.HttpRule_Put:
+		r.verb, path = http.MethodPut, p.Put
+	case *pb.HttpRule_Post:
+		r.verb, path = http.MethodPost, p.Post
+	case *pb.HttpRule_Delete:
+		r.verb, path = http.MethodDelete, p.Delete
+	case *pb.HttpRule_Patch:
+		r.verb, path = http.MethodPatch, p.Patch
+	}
+	if !strings.HasPrefix(path, "/") {
+		return r, fmt.Errorf("path %q does not start with /", path)
+	}
+	r.segments = strings.Split(path[1:], "/")
+	fields := md.Input().Fields()
+	for _, seg := range r.segments {
+		if name, ok := binding(seg); ok {
+			fd := fields.ByName(protoreflect.Name(name))
+			if fd == nil || fd.IsList() || fd.IsMap() || fd.Message() != nil {
+				return r, fmt.Errorf("path binds %q, which is not a singular scalar field", name)
+			}
+		}
+	}
+	if r.body != "" && r.body != "*" {
+		fd := fields.ByName(protoreflect.Name(r.body))
+		if fd == nil {
+			return r, fmt.Errorf("body names unknown field %q", r.body)
+		}
+	}
+	return r, nil
+}
+
+// binding returns the field name of a path segment such as "{id}".
+func binding(seg string) (string, bool) {
+	if len(seg) > 2 && seg[0] == '{' && seg[len(seg)-1] == '}' {
+		return seg[1 : len(seg)-1], true
+	}
+	return "", false
+}
+
+// match returns the path bindings if path fits r's template.
+func (r *route) match(path string) (map[string]string, bool) {
+	segs := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segs) != len(r.segments) {
+		return nil, false
+	}
+	vars := map[string]string{}
+	for i, seg := range r.segments {
+		if name, ok := binding(seg); ok {
+			if segs[i] == "" {
+				return nil, false
+			}
+			vars[name] = segs[i]
+		} else if seg != segs[i] {
+			return nil, false
+		}
+	}
+	return vars, true
+}
+
+// httpError is an error with the HTTP status it is reported with.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e *httpError) Error() string { return e.err.Error() }
+
+func badRequest(format string, args ...any) error {
+	return &httpError{http.StatusBadRequest, fmt.Errorf(format, args...)}
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var found *route
+	var vars map[string]string
+	allowed := false
+	for i := range g.routes {
+		r := &g.routes[i]
+		v, ok := r.match(req.URL.Path)
+		if !ok {
+			continue
+		}
+		allowed = true
+		if r.verb == req.Method {
+			found, vars = r, v
+			break
+		}
+	}
+	switch {
+	case found != nil:
+	case allowed:
+		writeError(w, &httpError{http.StatusMethodNotAllowed, fmt.Errorf("%s not allowed on %s", req.Method, req.URL.Path)})
+		return
+	default:
+		writeError(w, &httpError{http.StatusNotFound, fmt.Errorf("no method at %s", req.URL.Path)})
+		return
+	}
+
+	resp, err := g.serve(req, found, vars)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	b, err := protojson.Marshal(resp)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// serve builds the request message for r from req and makes the call.
+func (g *Gateway) serve(req *http.Request, r *route, vars map[string]string) (proto.Message, error) {
+	in := dynamicpb.NewMessage(r.method.Input())
+	if r.body != "" {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, badRequest("read body: %v", err)
+		}
+		if err := decodeBody(in, r.body, body); err != nil {
+			return nil, err
+		}
+	}
+	fields := in.Descriptor().Fields()
+	for name, value := range vars {
+		if err := setField(in, fields.ByName(protoreflect.Name(name)), value); err != nil {
+			return nil, err
+		}
+	}
+	if r.body != "*" {
+		for key, values := range req.URL.Query() {
+			fd := fields.ByName(protoreflect.Name(key))
+			if fd == nil {
+				fd = fields.ByJSONName(key)
+			}
+			if fd == nil || fd.Message() != nil || fd.IsMap() || string(fd.Name()) == r.body {
+				return nil, badRequest("unknown query parameter %q", key)
+			}
+			if _, bound := vars[string(fd.Name())]; bound {
+				return nil, badRequest("query parameter %q is bound by the path", key)
+			}
+			if !fd.IsList() && len(values) > 1 {
+				return nil, badRequest("query parameter %q repeated", key)
+			}
+			for _, v := range values {
+				if err := setField(in, fd, v); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	reqBytes, err := pbutil.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	respBytes, err := g.backend.Unary(req.Context(), r.rpcPath, reqBytes)
+	if err != nil {
+		var remote *rpcproto.RemoteError
+		if errors.As(err, &remote) {
+			return nil, &httpError{http.StatusInternalServerError, errors.New(remote.Message)}
+		}
+		return nil, &httpError{http.StatusBadGateway, err}
+	}
+	out := dynamicpb.NewMessage(r.method.Output())
+	if err := proto.Unmarshal(respBytes, out); err != nil {
+		return nil, &httpError{http.StatusBadGateway, fmt.Errorf("decode response: %w", err)}
+	}
+	return out, nil
+}
+
+// decodeBody reads a JSON body into the whole of m, or into its field
+// named field.
+func decodeBody(m *dynamicpb.Message, field string, body []byte) error {
+	if field != "*" {
+		if !json.Valid(body) {
+			return badRequest("body is not JSON")
+		}
+		fd := m.Descriptor().Fields().ByName(protoreflect.Name(field))
+		wrapped := append([]byte(`{"`+fd.JSONName()+`":`), body...)
+		body = append(wrapped, '}')
+	}
+	if err := protojson.Unmarshal(body, m); err != nil {
+		return badRequest("body: %v", err)
+	}
+	return nil
+}
+
+// setField sets, or for a repeated field appends, the scalar field fd of m
+// from its path or query string form.
+func setField(m *dynamicpb.Message, fd protoreflect.FieldDescriptor, s string) error {
+	v, err := parseScalar(fd, s)
+	if err != nil {
+		return badRequest("%s: %v", fd.Name(), err)
+	}
+	if fd.IsList() {
+		m.Mutable(fd).List().Append(v)
+	} else {
+		m.Set(fd, v)
+	}
+	return nil
+}
+
+func parseScalar(fd protoreflect.FieldDescriptor, s string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BytesKind:
+		b, err := base64.URLEncoding.DecodeString(s)
+		if err != nil {
+			b, err = base64.StdEncoding.DecodeString(s)
+		}
+		return protoreflect.ValueOfBytes(b), err
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		return protoreflect.ValueOfBool(b), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfInt32(int32(n)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(s, 10, 64)
+		return protoreflect.ValueOfInt64(n), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(s, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(n)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(s, 10, 64)
+		return protoreflect.ValueOfUint64(n), err
+	case protoreflect.FloatKind:
+		f, err := strconv.ParseFloat(s, 32)
+		return protoreflect.ValueOfFloat32(float32(f)), err
+	case protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(s, 64)
+		return protoreflect.ValueOfFloat64(f), err
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(s)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		n, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), err
+	}
+	return protoreflect.Value{}, fmt.Errorf("cannot bind %s fields", fd.Kind())
+}
+
+// writeError answers with err's status, or 500, and a JSON body holding its
+// message.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var he *httpError
+	if errors.As(err, &he) {
+		status = he.status
+	}
+	b, _ := json.Marshal(struct {
+		Error string `json:"error"`
+	}{err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(b)
+}
@@ -0,0 +1,99 @@
+package httpgatewaylib_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"compat/httpgatewaylib"
+	"compat/pb"
+	"compat/rpcproto"
+	"compat/rpcserverlib"
+)
+
+// newGateway serves the annotated UnaryService over HTTP, backed by an
+// in-process reference server on a pipe.
+func newGateway(t *testing.T) *httptest.Server {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	clientEnd, serverEnd := rpcproto.Pipe()
+	served := make(chan error, 1)
+	go func() {
+		served <- rpcserverlib.Serve(ctx, serverEnd, rpcserverlib.DefaultRegistry())
+		serverEnd.Close()
+	}()
+	backend := httpgatewaylib.NewBackend(clientEnd)
+	gw, err := httpgatewaylib.New(pb.File_gateway3_proto.Services().ByName("UnaryService"), backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs := httptest.NewServer(gw)
+	t.Cleanup(func() {
+		hs.Close()
+		backend.Close()
+		if err := <-served; err != nil {
+			t.Errorf("server: %v", err)
+		}
+		cancel()
+		clientEnd.Close()
+	})
+	return hs
+}
+
+func TestGateway(t *testing.T) {
+	hs := newGateway(t)
+	tests := []struct {
+		method, path, body string
+		status             int
+		want               map[string]any
+	}{
+		{"GET", "/v1/ping?payload=hi", "", 200, map[string]any{"payload": "hi"}},
+		{"GET", "/v1/items/7?query=x", "", 200, map[string]any{"id": 7.0, "name": "item_7"}},
+		{"GET", "/v1/health/db", "", 200, map[string]any{"status": "serving"}},
+		{"POST", "/v1/echo", `{"text":"hello","code":41}`, 200, map[string]any{"text": "hello", "code": 42.0}},
+		{"GET", "/v1/items/seven", "", 400, nil},
+		{"GET", "/v1/ping?nope=1", "", 400, nil},
+		{"GET", "/v1/items/7?id=8", "", 400, nil},
+		{"POST", "/v1/echo", `{"text":`, 400, nil},
+		{"GET", "/v1/echo", "", 405, nil},
+		{"GET", "/v1/missing", "", 404, nil},
+		// The backend is still usable after rejected requests.
+		{"GET", "/v1/ping?payload=again", "", 200, map[string]any{"payload": "again"}},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, hs.URL+tt.path, strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := hs.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s %s: status %d, want %d: %s", tt.method, tt.path, resp.StatusCode, tt.status, b)
+			continue
+		}
+		var got map[string]any
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Errorf("%s %s: %v: %s", tt.method, tt.path, err, b)
+			continue
+		}
+		if tt.want == nil {
+			if _, ok := got["error"]; !ok {
+				t.Errorf("%s %s: no error in %s", tt.method, tt.path, b)
+			}
+			continue
+		}
+		for k, v := range tt.want {
+			if got[k] != v {
+				t.Errorf("%s %s: %s = %v, want %v", tt.method, tt.path, k, got[k], v)
+			}
+		}
+	}
+}
@@ -0,0 +1,271 @@
+// Package oracle runs an external decoder, such as protoc --decode or a
+// reference binary, over a payload and compares what it made of it with
+// what the Go and Zig implementations made of it. With a third opinion a
+// disagreement between two implementations names the one that deviates,
+// instead of only saying that they differ.
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Format is how an oracle writes the message it decoded to stdout.
+type Format string
+
+const (
+	// FormatText is the text format, as protoc --decode writes it.
+	FormatText Format = "text"
+	// FormatJSON is proto3 JSON.
+	FormatJSON Format = "json"
+	// FormatBinary is the binary encoding, for an oracle that decodes and
+	// re-encodes the payload. Only it carries unknown fields.
+	FormatBinary Format = "binary"
+)
+
+// Oracle is an external decoder. A nil *Oracle is not consulted; see Check.
+type Oracle struct {
+	argv    []string
+	format  Format
+	include string
+	timeout time.Duration
+}
+
+// New parses cmd, a command line split on spaces, into an oracle that writes
+// format. Each payload is piped to a fresh run of it, with these
+// placeholders in its arguments replaced:
+//
+//	{type}     full name of the payload's message type
+//	{file}     path of the .proto file declaring it, relative to {include}
+//	{include}  include, the directory the .proto files are under
+//
+// so that "protoc --decode={type} -I{include} {file}" is an oracle. The
+// oracle rejects a payload by exiting with a nonzero status. An empty cmd
+// returns nil.
+func New(cmd string, format Format, include string, timeout time.Duration) (*Oracle, error) {
+	argv := strings.Fields(cmd)
+	if len(argv) == 0 {
+		return nil, nil
+	}
+	switch format {
+	case FormatText, FormatJSON, FormatBinary:
+	default:
+		return nil, fmt.Errorf("unknown oracle format %q (want text, json or binary)", format)
+	}
+	return &Oracle{argv: argv, format: format, include: include, timeout: timeout}, nil
+}
+
+// In returns a copy of o whose {include} is include, for payloads of a
+// schema kept somewhere else. In on a nil oracle returns nil.
+func (o *Oracle) In(include string) *Oracle {
+	if o == nil {
+		return nil
+	}
+	c := *o
+	c.include = include
+	return &c
+}
+
+// Name is how reports refer to the oracle: its program's name.
+func (o *Oracle) Name() string {
+	return o.argv[0]
+}
+
+// Rejected is the error a View holds when an implementation refused the
+// payload.
+type Rejected struct {
+	Reason string
+}
+
+func (e *Rejected) Error() string { return "rejected: " + e.Reason }
+
+// Decode runs the oracle over data as a message of type md. An oracle that
+// rejects the payload yields a *Rejected; any other error means the oracle
+// could not be run or its output not read.
+func (o *Oracle) Decode(md protoreflect.MessageDescriptor, data []byte) (proto.Message, error) {
+	replacer := strings.NewReplacer(
+		"{type}", string(md.FullName()),
+		"{file}", md.ParentFile().Path(),
+		"{include}", o.include,
+	)
+	args := make([]string, len(o.argv))
+	for i, arg := range o.argv {
+		args[i] = replacer.Replace(arg)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	var exit *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		return nil, fmt.Errorf("oracle %s still running after %v", o.Name(), o.timeout)
+	case errors.As(err, &exit):
+		reason := strings.TrimSpace(stderr.String())
+		if reason == "" {
+			reason = exit.String()
+		}
+		return nil, &Rejected{Reason: reason}
+	case err != nil:
+		return nil, fmt.Errorf("oracle: %w", err)
+	}
+
+	msg := dynamicpb.NewMessage(md)
+	switch o.format {
+	case FormatText:
+		err = prototext.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(stdout.Bytes(), msg)
+	case FormatJSON:
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(stdout.Bytes(), msg)
+	case FormatBinary:
+		err = proto.Unmarshal(stdout.Bytes(), msg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("oracle %s output (%s): %w", o.Name(), o.format, err)
+	}
+	return msg, nil
+}
+
+// View is what one implementation made of a payload: the message it decoded,
+// or the error it rejected the payload with.
+type View struct {
+	Impl string
+	Msg  proto.Message
+	Err  error
+}
+
+// Check has the oracle decode data as md and compares the result with the
+// other views of it. It returns an empty report when all agree, and
+// otherwise one naming the implementation that deviates from the rest, or
+// saying that none agree. Since only FormatBinary carries unknown fields,
+// with the other formats they are left out of the comparison. A nil oracle
+// reports nothing; err is set only when the oracle could not be consulted.
+func (o *Oracle) Check(md protoreflect.MessageDescriptor, data []byte, views ...View) (report string, err error) {
+	if o == nil {
+		return "", nil
+	}
+	v := View{Impl: o.Name()}
+	v.Msg, v.Err = o.Decode(md, data)
+	var rejected *Rejected
+	if v.Err != nil && !errors.As(v.Err, &rejected) {
+		return "", v.Err
+	}
+	views = append(views, v)
+	if o.format != FormatBinary {
+		for i := range views {
+			if views[i].Msg != nil {
+				views[i].Msg = proto.Clone(views[i].Msg)
+				discardUnknown(views[i].Msg.ProtoReflect())
+			}
+		}
+	}
+	return Compare(views...), nil
+}
+
+// Compare groups views that agree, both rejecting the payload or both
+// decoding equal messages, and describes the disagreement. It returns ""
+// when all views agree.
+func Compare(views ...View) string {
+	var groups [][]View
+	for _, v := range views {
+		placed := false
+		for i, g := range groups {
+			if agree(g[0], v) {
+				groups[i] = append(g, v)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			groups = append(groups, []View{v})
+		}
+	}
+	if len(groups) == 1 {
+		return ""
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return len(groups[i]) > len(groups[j]) })
+
+	var b strings.Builder
+	if len(groups[0]) > len(views)/2 {
+		var deviating []string
+		for _, g := range groups[1:] {
+			deviating = append(deviating, impls(g))
+		}
+		verb := "deviates"
+		if len(views)-len(groups[0]) > 1 {
+			verb = "deviate"
+		}
+		fmt.Fprintf(&b, "%s %s from %s", strings.Join(deviating, " and "), verb, impls(groups[0]))
+	} else {
+		fmt.Fprintf(&b, "no two of %d implementations agree", len(views))
+	}
+	for _, g := range groups {
+		fmt.Fprintf(&b, "\n  %s: %s", impls(g), describe(g[0]))
+	}
+	return b.String()
+}
+
+func agree(a, b View) bool {
+	if a.Err != nil || b.Err != nil {
+		return a.Err != nil && b.Err != nil
+	}
+	return proto.Equal(a.Msg, b.Msg)
+}
+
+func impls(g []View) string {
+	var names []string
+	for _, v := range g {
+		names = append(names, v.Impl)
+	}
+	return strings.Join(names, ", ")
+}
+
+func describe(v View) string {
+	if v.Err != nil {
+		return v.Err.Error()
+	}
+	text := prototext.MarshalOptions{EmitUnknown: true}.Format(v.Msg)
+	if text == "" {
+		return "{}"
+	}
+	return "{" + text + "}"
+}
+
+// discardUnknown clears the unknown fields of m and of every message in it.
+func discardUnknown(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				discardUnknown(list.Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				discardUnknown(v.Message())
+				return true
+			})
+		case fd.Message() != nil && !fd.IsMap():
+			discardUnknown(v.Message())
+		}
+		return true
+	})
+	if m.GetUnknown() != nil {
+		m.SetUnknown(nil)
+	}
+}
@@ -0,0 +1,77 @@
+package oracle_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"compat/oracle"
+	"compat/pb"
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCheck(t *testing.T) {
+	msg := &pb.ScalarMessage{FInt32: 150, FString: "hi"}
+	data, err := pbutil.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := msg.ProtoReflect().Descriptor()
+	other := &pb.ScalarMessage{FInt32: 151, FString: "hi"}
+
+	// cat re-encodes every payload unchanged, so it agrees with a correct
+	// decoder and outvotes a wrong one.
+	cat, err := oracle.New("cat", oracle.FormatBinary, "", 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := cat.Check(md, data, oracle.View{Impl: "go", Msg: msg}, oracle.View{Impl: "zig", Msg: msg})
+	if err != nil || report != "" {
+		t.Errorf("agreeing views: report %q, err %v", report, err)
+	}
+	report, err = cat.Check(md, data, oracle.View{Impl: "go", Msg: msg}, oracle.View{Impl: "zig", Msg: other})
+	if err != nil || !strings.HasPrefix(report, "zig deviates from go, cat") {
+		t.Errorf("zig decoding wrong: report %q, err %v", report, err)
+	}
+
+	// false rejects every payload, and is outvoted by two decoders.
+	reject, _ := oracle.New("false", oracle.FormatText, "", 10*time.Second)
+	report, err = reject.Check(md, data, oracle.View{Impl: "go", Msg: msg}, oracle.View{Impl: "zig", Msg: msg})
+	if err != nil || !strings.HasPrefix(report, "false deviates from go, zig") {
+		t.Errorf("oracle rejecting: report %q, err %v", report, err)
+	}
+
+	// Text carries no unknown fields, so they are left out of the
+	// comparison.
+	text, _ := oracle.New("echo f_int32: 150 f_string: 'hi' 1001: 5", oracle.FormatText, "", 10*time.Second)
+	withUnknown := proto.Clone(msg).(*pb.ScalarMessage)
+	withUnknown.ProtoReflect().SetUnknown(pbutil.UnknownFields())
+	report, err = text.Check(md, data, oracle.View{Impl: "go", Msg: withUnknown}, oracle.View{Impl: "zig", Msg: msg})
+	if err != nil || report != "" {
+		t.Errorf("text oracle: report %q, err %v", report, err)
+	}
+
+	// An oracle that cannot be run is an error, not a disagreement.
+	missing, _ := oracle.New("/nonexistent/oracle", oracle.FormatText, "", 10*time.Second)
+	if _, err := missing.Check(md, data, oracle.View{Impl: "go", Msg: msg}); err == nil {
+		t.Error("missing oracle binary: no error")
+	}
+
+	var none *oracle.Oracle
+	if report, err := none.Check(md, data, oracle.View{Impl: "go", Msg: msg}, oracle.View{Impl: "zig", Msg: other}); report != "" || err != nil {
+		t.Errorf("nil oracle: report %q, err %v", report, err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a, b, c := &pb.ScalarMessage{FInt32: 1}, &pb.ScalarMessage{FInt32: 2}, &pb.ScalarMessage{FInt32: 3}
+	if r := oracle.Compare(oracle.View{Impl: "go", Msg: a}, oracle.View{Impl: "zig", Msg: proto.Clone(a)}); r != "" {
+		t.Errorf("equal messages: %q", r)
+	}
+	r := oracle.Compare(oracle.View{Impl: "go", Msg: a}, oracle.View{Impl: "zig", Msg: b}, oracle.View{Impl: "ref", Msg: c})
+	if !strings.HasPrefix(r, "no two of 3 implementations agree") {
+		t.Errorf("three-way split: %q", r)
+	}
+}
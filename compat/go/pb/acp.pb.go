@@ -152,6 +152,57 @@ func (AcpStatusCode) EnumDescriptor() ([]byte, []int) {
 	return file_acp_proto_rawDescGZIP(), []int{1}
 }
 
+// How an AcpMessage's payload_chunk is encoded. payload_hash and file_length
+// always describe the decompressed bytes.
+type AcpCompression int32
+
+const (
+	AcpCompression_UNCOMPRESSED AcpCompression = 0
+	AcpCompression_GZIP         AcpCompression = 1
+	AcpCompression_ZSTD         AcpCompression = 2
+)
+
+// Enum value maps for AcpCompression.
+var (
+	AcpCompression_name = map[int32]string{
+		0: "UNCOMPRESSED",
+		1: "GZIP",
+		2: "ZSTD",
+	}
+	AcpCompression_value = map[string]int32{
+		"UNCOMPRESSED": 0,
+		"GZIP":         1,
+		"ZSTD":         2,
+	}
+)
+
+func (x AcpCompression) Enum() *AcpCompression {
+	p := new(AcpCompression)
+	*p = x
+	return p
+}
+
+func (x AcpCompression) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AcpCompression) Descriptor() protoreflect.EnumDescriptor {
+	return file_acp_proto_enumTypes[2].Descriptor()
+}
+
+func (AcpCompression) Type() protoreflect.EnumType {
+	return &file_acp_proto_enumTypes[2]
+}
+
+func (x AcpCompression) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AcpCompression.Descriptor instead.
+func (AcpCompression) EnumDescriptor() ([]byte, []int) {
+	return file_acp_proto_rawDescGZIP(), []int{2}
+}
+
 type AcpAssetMetadata struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Uri           string                 `protobuf:"bytes,1,opt,name=uri,proto3" json:"uri,omitempty"`
@@ -160,6 +211,7 @@ type AcpAssetMetadata struct {
 	FileLength    int64                  `protobuf:"varint,4,opt,name=file_length,json=fileLength,proto3" json:"file_length,omitempty"`
 	UriVersion    int64                  `protobuf:"varint,5,opt,name=uri_version,json=uriVersion,proto3" json:"uri_version,omitempty"`
 	UpdatedAtNs   int64                  `protobuf:"varint,6,opt,name=updated_at_ns,json=updatedAtNs,proto3" json:"updated_at_ns,omitempty"`
+	Compression   AcpCompression         `protobuf:"varint,7,opt,name=compression,proto3,enum=acp.AcpCompression" json:"compression,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -236,6 +288,13 @@ func (x *AcpAssetMetadata) GetUpdatedAtNs() int64 {
 	return 0
 }
 
+func (x *AcpAssetMetadata) GetCompression() AcpCompression {
+	if x != nil {
+		return x.Compression
+	}
+	return AcpCompression_UNCOMPRESSED
+}
+
 type AcpMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Version       *uint32                `protobuf:"varint,1,opt,name=version,proto3,oneof" json:"version,omitempty"`
@@ -249,6 +308,7 @@ type AcpMessage struct {
 	Metadata      *AcpAssetMetadata      `protobuf:"bytes,9,opt,name=metadata,proto3,oneof" json:"metadata,omitempty"`
 	ChunkIndex    uint32                 `protobuf:"varint,10,opt,name=chunk_index,json=chunkIndex,proto3" json:"chunk_index,omitempty"`
 	ChunkTotal    uint32                 `protobuf:"varint,11,opt,name=chunk_total,json=chunkTotal,proto3" json:"chunk_total,omitempty"`
+	PayloadChunk  []byte                 `protobuf:"bytes,12,opt,name=payload_chunk,json=payloadChunk,proto3" json:"payload_chunk,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -360,11 +420,18 @@ func (x *AcpMessage) GetChunkTotal() uint32 {
 	return 0
 }
 
+func (x *AcpMessage) GetPayloadChunk() []byte {
+	if x != nil {
+		return x.PayloadChunk
+	}
+	return nil
+}
+
 var File_acp_proto protoreflect.FileDescriptor
 
 const file_acp_proto_rawDesc = "" +
 	"\n" +
-	"\tacp.proto\x12\x03acp\"\xcc\x01\n" +
+	"\tacp.proto\x12\x03acp\"\x83\x02\n" +
 	"\x10AcpAssetMetadata\x12\x10\n" +
 	"\x03uri\x18\x01 \x01(\tR\x03uri\x12\x1d\n" +
 	"\n" +
@@ -374,7 +441,8 @@ const file_acp_proto_rawDesc = "" +
 	"fileLength\x12\x1f\n" +
 	"\vuri_version\x18\x05 \x01(\x03R\n" +
 	"uriVersion\x12\"\n" +
-	"\rupdated_at_ns\x18\x06 \x01(\x03R\vupdatedAtNs\"\xd6\x03\n" +
+	"\rupdated_at_ns\x18\x06 \x01(\x03R\vupdatedAtNs\x125\n" +
+	"\vcompression\x18\a \x01(\x0e2\x13.acp.AcpCompressionR\vcompression\"\xfb\x03\n" +
 	"\n" +
 	"AcpMessage\x12\x1d\n" +
 	"\aversion\x18\x01 \x01(\rH\x00R\aversion\x88\x01\x01\x12'\n" +
@@ -391,7 +459,8 @@ const file_acp_proto_rawDesc = "" +
 	" \x01(\rR\n" +
 	"chunkIndex\x12\x1f\n" +
 	"\vchunk_total\x18\v \x01(\rR\n" +
-	"chunkTotalB\n" +
+	"chunkTotal\x12#\n" +
+	"\rpayload_chunk\x18\f \x01(\fR\fpayloadChunkB\n" +
 	"\n" +
 	"\b_versionB\x06\n" +
 	"\x04_uriB\x0f\n" +
@@ -420,7 +489,14 @@ const file_acp_proto_rawDesc = "" +
 	"\x02OK\x10\x00\x12\r\n" +
 	"\tNOT_FOUND\x10\x01\x12\x0f\n" +
 	"\vBAD_REQUEST\x10\x02\x12\x12\n" +
-	"\x0eINTERNAL_ERROR\x10\x03b\x06proto3"
+	"\x0eINTERNAL_ERROR\x10\x03*6\n" +
+	"\x0eAcpCompression\x12\x10\n" +
+	"\fUNCOMPRESSED\x10\x00\x12\b\n" +
+	"\x04GZIP\x10\x01\x12\b\n" +
+	"\x04ZSTD\x10\x022:\n" +
+	"\n" +
+	"AcpService\x12,\n" +
+	"\x06Verify\x12\x0f.acp.AcpMessage\x1a\x0f.acp.AcpMessage(\x01b\x06proto3"
 
 var (
 	file_acp_proto_rawDescOnce sync.Once
@@ -434,23 +510,27 @@ func file_acp_proto_rawDescGZIP() []byte {
 	return file_acp_proto_rawDescData
 }
 
-var file_acp_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_acp_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
 var file_acp_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_acp_proto_goTypes = []any{
 	(AcpMessageKind)(0),      // 0: acp.AcpMessageKind
 	(AcpStatusCode)(0),       // 1: acp.AcpStatusCode
-	(*AcpAssetMetadata)(nil), // 2: acp.AcpAssetMetadata
-	(*AcpMessage)(nil),       // 3: acp.AcpMessage
+	(AcpCompression)(0),      // 2: acp.AcpCompression
+	(*AcpAssetMetadata)(nil), // 3: acp.AcpAssetMetadata
+	(*AcpMessage)(nil),       // 4: acp.AcpMessage
 }
 var file_acp_proto_depIdxs = []int32{
-	0, // 0: acp.AcpMessage.kind:type_name -> acp.AcpMessageKind
-	1, // 1: acp.AcpMessage.status:type_name -> acp.AcpStatusCode
-	2, // 2: acp.AcpMessage.metadata:type_name -> acp.AcpAssetMetadata
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	2, // 0: acp.AcpAssetMetadata.compression:type_name -> acp.AcpCompression
+	0, // 1: acp.AcpMessage.kind:type_name -> acp.AcpMessageKind
+	1, // 2: acp.AcpMessage.status:type_name -> acp.AcpStatusCode
+	3, // 3: acp.AcpMessage.metadata:type_name -> acp.AcpAssetMetadata
+	4, // 4: acp.AcpService.Verify:input_type -> acp.AcpMessage
+	4, // 5: acp.AcpService.Verify:output_type -> acp.AcpMessage
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_acp_proto_init() }
@@ -464,10 +544,10 @@ func file_acp_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_acp_proto_rawDesc), len(file_acp_proto_rawDesc)),
-			NumEnums:      2,
+			NumEnums:      3,
 			NumMessages:   2,
 			NumExtensions: 0,
-			NumServices:   0,
+			NumServices:   1,
 		},
 		GoTypes:           file_acp_proto_goTypes,
 		DependencyIndexes: file_acp_proto_depIdxs,
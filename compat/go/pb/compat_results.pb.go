@@ -0,0 +1,543 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: compat_results.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CaseStatus int32
+
+const (
+	CaseStatus_CASE_STATUS_UNSPECIFIED CaseStatus = 0
+	CaseStatus_CASE_STATUS_PASS        CaseStatus = 1
+	CaseStatus_CASE_STATUS_FAIL        CaseStatus = 2
+	// The case was not run, for example because its corpus file is missing.
+	CaseStatus_CASE_STATUS_SKIP CaseStatus = 3
+	// The case failed, as it is tagged to while a known bug stands; see
+	// go/testcases.KnownZigBug. It counts neither as a pass nor a failure.
+	CaseStatus_CASE_STATUS_XFAIL CaseStatus = 4
+)
+
+// Enum value maps for CaseStatus.
+var (
+	CaseStatus_name = map[int32]string{
+		0: "CASE_STATUS_UNSPECIFIED",
+		1: "CASE_STATUS_PASS",
+		2: "CASE_STATUS_FAIL",
+		3: "CASE_STATUS_SKIP",
+		4: "CASE_STATUS_XFAIL",
+	}
+	CaseStatus_value = map[string]int32{
+		"CASE_STATUS_UNSPECIFIED": 0,
+		"CASE_STATUS_PASS":        1,
+		"CASE_STATUS_FAIL":        2,
+		"CASE_STATUS_SKIP":        3,
+		"CASE_STATUS_XFAIL":       4,
+	}
+)
+
+func (x CaseStatus) Enum() *CaseStatus {
+	p := new(CaseStatus)
+	*p = x
+	return p
+}
+
+func (x CaseStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CaseStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_compat_results_proto_enumTypes[0].Descriptor()
+}
+
+func (CaseStatus) Type() protoreflect.EnumType {
+	return &file_compat_results_proto_enumTypes[0]
+}
+
+func (x CaseStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CaseStatus.Descriptor instead.
+func (CaseStatus) EnumDescriptor() ([]byte, []int) {
+	return file_compat_results_proto_rawDescGZIP(), []int{0}
+}
+
+// The outcome of one validation run over a corpus, written in the same form
+// by go/cmd/validate and the Zig compat tests so that results from both
+// sides can be merged and diffed.
+type CompatResults struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Run           *RunMetadata           `protobuf:"bytes,1,opt,name=run,proto3" json:"run,omitempty"`
+	Cases         []*CaseResult          `protobuf:"bytes,2,rep,name=cases,proto3" json:"cases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompatResults) Reset() {
+	*x = CompatResults{}
+	mi := &file_compat_results_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompatResults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompatResults) ProtoMessage() {}
+
+func (x *CompatResults) ProtoReflect() protoreflect.Message {
+	mi := &file_compat_results_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompatResults.ProtoReflect.Descriptor instead.
+func (*CompatResults) Descriptor() ([]byte, []int) {
+	return file_compat_results_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CompatResults) GetRun() *RunMetadata {
+	if x != nil {
+		return x.Run
+	}
+	return nil
+}
+
+func (x *CompatResults) GetCases() []*CaseResult {
+	if x != nil {
+		return x.Cases
+	}
+	return nil
+}
+
+// Who ran the validation, with what, and when.
+type RunMetadata struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "go" or "zig".
+	Implementation string `protobuf:"bytes,1,opt,name=implementation,proto3" json:"implementation,omitempty"`
+	// The toolchain and protobuf runtime versions, for example "go1.23.4
+	// google.golang.org/protobuf v1.36.11".
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// The command line or test that produced the results.
+	Command        string `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"`
+	Os             string `protobuf:"bytes,4,opt,name=os,proto3" json:"os,omitempty"`
+	Arch           string `protobuf:"bytes,5,opt,name=arch,proto3" json:"arch,omitempty"`
+	StartUnixNanos int64  `protobuf:"varint,6,opt,name=start_unix_nanos,json=startUnixNanos,proto3" json:"start_unix_nanos,omitempty"`
+	DurationNanos  int64  `protobuf:"varint,7,opt,name=duration_nanos,json=durationNanos,proto3" json:"duration_nanos,omitempty"`
+	// For runs that built the Zig side themselves, the output of `zig
+	// version` and the arguments zig build was run with.
+	ZigVersion    string   `protobuf:"bytes,8,opt,name=zig_version,json=zigVersion,proto3" json:"zig_version,omitempty"`
+	ZigBuildArgs  []string `protobuf:"bytes,9,rep,name=zig_build_args,json=zigBuildArgs,proto3" json:"zig_build_args,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunMetadata) Reset() {
+	*x = RunMetadata{}
+	mi := &file_compat_results_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunMetadata) ProtoMessage() {}
+
+func (x *RunMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_compat_results_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunMetadata.ProtoReflect.Descriptor instead.
+func (*RunMetadata) Descriptor() ([]byte, []int) {
+	return file_compat_results_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RunMetadata) GetImplementation() string {
+	if x != nil {
+		return x.Implementation
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetStartUnixNanos() int64 {
+	if x != nil {
+		return x.StartUnixNanos
+	}
+	return 0
+}
+
+func (x *RunMetadata) GetDurationNanos() int64 {
+	if x != nil {
+		return x.DurationNanos
+	}
+	return 0
+}
+
+func (x *RunMetadata) GetZigVersion() string {
+	if x != nil {
+		return x.ZigVersion
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetZigBuildArgs() []string {
+	if x != nil {
+		return x.ZigBuildArgs
+	}
+	return nil
+}
+
+// One case, identified by corpus category and name within a direction:
+// "zig-to-go", "go-to-zig", "json" or "reject".
+type CaseResult struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Direction string                 `protobuf:"bytes,1,opt,name=direction,proto3" json:"direction,omitempty"`
+	Category  string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	Name      string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Status    CaseStatus             `protobuf:"varint,4,opt,name=status,proto3,enum=CaseStatus" json:"status,omitempty"`
+	// For reject vectors, the error category the reader failed with, as
+	// go/testcases.ErrorCategory spells it; empty if it accepted the vector.
+	ErrorCategory string `protobuf:"bytes,5,opt,name=error_category,json=errorCategory,proto3" json:"error_category,omitempty"`
+	// Why the case failed or was skipped.
+	Detail        string    `protobuf:"bytes,6,opt,name=detail,proto3" json:"detail,omitempty"`
+	DurationNanos int64     `protobuf:"varint,7,opt,name=duration_nanos,json=durationNanos,proto3" json:"duration_nanos,omitempty"`
+	Diff          *ByteDiff `protobuf:"bytes,8,opt,name=diff,proto3" json:"diff,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CaseResult) Reset() {
+	*x = CaseResult{}
+	mi := &file_compat_results_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaseResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaseResult) ProtoMessage() {}
+
+func (x *CaseResult) ProtoReflect() protoreflect.Message {
+	mi := &file_compat_results_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaseResult.ProtoReflect.Descriptor instead.
+func (*CaseResult) Descriptor() ([]byte, []int) {
+	return file_compat_results_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CaseResult) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *CaseResult) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CaseResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CaseResult) GetStatus() CaseStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CaseStatus_CASE_STATUS_UNSPECIFIED
+}
+
+func (x *CaseResult) GetErrorCategory() string {
+	if x != nil {
+		return x.ErrorCategory
+	}
+	return ""
+}
+
+func (x *CaseResult) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *CaseResult) GetDurationNanos() int64 {
+	if x != nil {
+		return x.DurationNanos
+	}
+	return 0
+}
+
+func (x *CaseResult) GetDiff() *ByteDiff {
+	if x != nil {
+		return x.Diff
+	}
+	return nil
+}
+
+// Where the bytes a reader produced first differ from the reference
+// encoding, with a window of each side from a little before that offset.
+type ByteDiff struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Offset        uint64                 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	WindowStart   uint64                 `protobuf:"varint,2,opt,name=window_start,json=windowStart,proto3" json:"window_start,omitempty"`
+	Want          []byte                 `protobuf:"bytes,3,opt,name=want,proto3" json:"want,omitempty"`
+	Got           []byte                 `protobuf:"bytes,4,opt,name=got,proto3" json:"got,omitempty"`
+	WantLen       uint64                 `protobuf:"varint,5,opt,name=want_len,json=wantLen,proto3" json:"want_len,omitempty"`
+	GotLen        uint64                 `protobuf:"varint,6,opt,name=got_len,json=gotLen,proto3" json:"got_len,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ByteDiff) Reset() {
+	*x = ByteDiff{}
+	mi := &file_compat_results_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ByteDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ByteDiff) ProtoMessage() {}
+
+func (x *ByteDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_compat_results_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ByteDiff.ProtoReflect.Descriptor instead.
+func (*ByteDiff) Descriptor() ([]byte, []int) {
+	return file_compat_results_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ByteDiff) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ByteDiff) GetWindowStart() uint64 {
+	if x != nil {
+		return x.WindowStart
+	}
+	return 0
+}
+
+func (x *ByteDiff) GetWant() []byte {
+	if x != nil {
+		return x.Want
+	}
+	return nil
+}
+
+func (x *ByteDiff) GetGot() []byte {
+	if x != nil {
+		return x.Got
+	}
+	return nil
+}
+
+func (x *ByteDiff) GetWantLen() uint64 {
+	if x != nil {
+		return x.WantLen
+	}
+	return 0
+}
+
+func (x *ByteDiff) GetGotLen() uint64 {
+	if x != nil {
+		return x.GotLen
+	}
+	return 0
+}
+
+var File_compat_results_proto protoreflect.FileDescriptor
+
+const file_compat_results_proto_rawDesc = "" +
+	"\n" +
+	"\x14compat_results.proto\"R\n" +
+	"\rCompatResults\x12\x1e\n" +
+	"\x03run\x18\x01 \x01(\v2\f.RunMetadataR\x03run\x12!\n" +
+	"\x05cases\x18\x02 \x03(\v2\v.CaseResultR\x05cases\"\xa5\x02\n" +
+	"\vRunMetadata\x12&\n" +
+	"\x0eimplementation\x18\x01 \x01(\tR\x0eimplementation\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x18\n" +
+	"\acommand\x18\x03 \x01(\tR\acommand\x12\x0e\n" +
+	"\x02os\x18\x04 \x01(\tR\x02os\x12\x12\n" +
+	"\x04arch\x18\x05 \x01(\tR\x04arch\x12(\n" +
+	"\x10start_unix_nanos\x18\x06 \x01(\x03R\x0estartUnixNanos\x12%\n" +
+	"\x0eduration_nanos\x18\a \x01(\x03R\rdurationNanos\x12\x1f\n" +
+	"\vzig_version\x18\b \x01(\tR\n" +
+	"zigVersion\x12$\n" +
+	"\x0ezig_build_args\x18\t \x03(\tR\fzigBuildArgs\"\x84\x02\n" +
+	"\n" +
+	"CaseResult\x12\x1c\n" +
+	"\tdirection\x18\x01 \x01(\tR\tdirection\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12#\n" +
+	"\x06status\x18\x04 \x01(\x0e2\v.CaseStatusR\x06status\x12%\n" +
+	"\x0eerror_category\x18\x05 \x01(\tR\rerrorCategory\x12\x16\n" +
+	"\x06detail\x18\x06 \x01(\tR\x06detail\x12%\n" +
+	"\x0eduration_nanos\x18\a \x01(\x03R\rdurationNanos\x12\x1d\n" +
+	"\x04diff\x18\b \x01(\v2\t.ByteDiffR\x04diff\"\x9f\x01\n" +
+	"\bByteDiff\x12\x16\n" +
+	"\x06offset\x18\x01 \x01(\x04R\x06offset\x12!\n" +
+	"\fwindow_start\x18\x02 \x01(\x04R\vwindowStart\x12\x12\n" +
+	"\x04want\x18\x03 \x01(\fR\x04want\x12\x10\n" +
+	"\x03got\x18\x04 \x01(\fR\x03got\x12\x19\n" +
+	"\bwant_len\x18\x05 \x01(\x04R\awantLen\x12\x17\n" +
+	"\agot_len\x18\x06 \x01(\x04R\x06gotLen*\x82\x01\n" +
+	"\n" +
+	"CaseStatus\x12\x1b\n" +
+	"\x17CASE_STATUS_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10CASE_STATUS_PASS\x10\x01\x12\x14\n" +
+	"\x10CASE_STATUS_FAIL\x10\x02\x12\x14\n" +
+	"\x10CASE_STATUS_SKIP\x10\x03\x12\x15\n" +
+	"\x11CASE_STATUS_XFAIL\x10\x04b\x06proto3"
+
+var (
+	file_compat_results_proto_rawDescOnce sync.Once
+	file_compat_results_proto_rawDescData []byte
+)
+
+func file_compat_results_proto_rawDescGZIP() []byte {
+	file_compat_results_proto_rawDescOnce.Do(func() {
+		file_compat_results_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_compat_results_proto_rawDesc), len(file_compat_results_proto_rawDesc)))
+	})
+	return file_compat_results_proto_rawDescData
+}
+
+var file_compat_results_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_compat_results_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_compat_results_proto_goTypes = []any{
+	(CaseStatus)(0),       // 0: CaseStatus
+	(*CompatResults)(nil), // 1: CompatResults
+	(*RunMetadata)(nil),   // 2: RunMetadata
+	(*CaseResult)(nil),    // 3: CaseResult
+	(*ByteDiff)(nil),      // 4: ByteDiff
+}
+var file_compat_results_proto_depIdxs = []int32{
+	2, // 0: CompatResults.run:type_name -> RunMetadata
+	3, // 1: CompatResults.cases:type_name -> CaseResult
+	0, // 2: CaseResult.status:type_name -> CaseStatus
+	4, // 3: CaseResult.diff:type_name -> ByteDiff
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_compat_results_proto_init() }
+func file_compat_results_proto_init() {
+	if File_compat_results_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_compat_results_proto_rawDesc), len(file_compat_results_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_compat_results_proto_goTypes,
+		DependencyIndexes: file_compat_results_proto_depIdxs,
+		EnumInfos:         file_compat_results_proto_enumTypes,
+		MessageInfos:      file_compat_results_proto_msgTypes,
+	}.Build()
+	File_compat_results_proto = out.File
+	file_compat_results_proto_goTypes = nil
+	file_compat_results_proto_depIdxs = nil
+}
@@ -0,0 +1,291 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: evolution3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// One message as first released, and again after a later release changed
+// it. Every change keeps the wire format compatible, so each version must
+// read what the other writes.
+type EvolutionV1 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Count         int32                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Note          string                 `protobuf:"bytes,4,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvolutionV1) Reset() {
+	*x = EvolutionV1{}
+	mi := &file_evolution3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvolutionV1) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvolutionV1) ProtoMessage() {}
+
+func (x *EvolutionV1) ProtoReflect() protoreflect.Message {
+	mi := &file_evolution3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvolutionV1.ProtoReflect.Descriptor instead.
+func (*EvolutionV1) Descriptor() ([]byte, []int) {
+	return file_evolution3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EvolutionV1) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EvolutionV1) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EvolutionV1) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *EvolutionV1) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+// EvolutionV1 with name renamed to title, count widened from int32 to
+// int64, note moved into a oneof, and the fields tags, archived and code
+// added.
+type EvolutionV2 struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Count int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	// Types that are valid to be assigned to Detail:
+	//
+	//	*EvolutionV2_Note
+	//	*EvolutionV2_Code
+	Detail        isEvolutionV2_Detail `protobuf_oneof:"detail"`
+	Tags          []string             `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	Archived      bool                 `protobuf:"varint,6,opt,name=archived,proto3" json:"archived,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvolutionV2) Reset() {
+	*x = EvolutionV2{}
+	mi := &file_evolution3_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvolutionV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvolutionV2) ProtoMessage() {}
+
+func (x *EvolutionV2) ProtoReflect() protoreflect.Message {
+	mi := &file_evolution3_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvolutionV2.ProtoReflect.Descriptor instead.
+func (*EvolutionV2) Descriptor() ([]byte, []int) {
+	return file_evolution3_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EvolutionV2) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EvolutionV2) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *EvolutionV2) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *EvolutionV2) GetDetail() isEvolutionV2_Detail {
+	if x != nil {
+		return x.Detail
+	}
+	return nil
+}
+
+func (x *EvolutionV2) GetNote() string {
+	if x != nil {
+		if x, ok := x.Detail.(*EvolutionV2_Note); ok {
+			return x.Note
+		}
+	}
+	return ""
+}
+
+func (x *EvolutionV2) GetCode() int32 {
+	if x != nil {
+		if x, ok := x.Detail.(*EvolutionV2_Code); ok {
+			return x.Code
+		}
+	}
+	return 0
+}
+
+func (x *EvolutionV2) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *EvolutionV2) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+type isEvolutionV2_Detail interface {
+	isEvolutionV2_Detail()
+}
+
+type EvolutionV2_Note struct {
+	Note string `protobuf:"bytes,4,opt,name=note,proto3,oneof"`
+}
+
+type EvolutionV2_Code struct {
+	Code int32 `protobuf:"varint,7,opt,name=code,proto3,oneof"`
+}
+
+func (*EvolutionV2_Note) isEvolutionV2_Detail() {}
+
+func (*EvolutionV2_Code) isEvolutionV2_Detail() {}
+
+var File_evolution3_proto protoreflect.FileDescriptor
+
+const file_evolution3_proto_rawDesc = "" +
+	"\n" +
+	"\x10evolution3.proto\"[\n" +
+	"\vEvolutionV1\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x05R\x05count\x12\x12\n" +
+	"\x04note\x18\x04 \x01(\tR\x04note\"\xaf\x01\n" +
+	"\vEvolutionV2\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05count\x12\x14\n" +
+	"\x04note\x18\x04 \x01(\tH\x00R\x04note\x12\x14\n" +
+	"\x04code\x18\a \x01(\x05H\x00R\x04code\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12\x1a\n" +
+	"\barchived\x18\x06 \x01(\bR\barchivedB\b\n" +
+	"\x06detailb\x06proto3"
+
+var (
+	file_evolution3_proto_rawDescOnce sync.Once
+	file_evolution3_proto_rawDescData []byte
+)
+
+func file_evolution3_proto_rawDescGZIP() []byte {
+	file_evolution3_proto_rawDescOnce.Do(func() {
+		file_evolution3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_evolution3_proto_rawDesc), len(file_evolution3_proto_rawDesc)))
+	})
+	return file_evolution3_proto_rawDescData
+}
+
+var file_evolution3_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_evolution3_proto_goTypes = []any{
+	(*EvolutionV1)(nil), // 0: EvolutionV1
+	(*EvolutionV2)(nil), // 1: EvolutionV2
+}
+var file_evolution3_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_evolution3_proto_init() }
+func file_evolution3_proto_init() {
+	if File_evolution3_proto != nil {
+		return
+	}
+	file_evolution3_proto_msgTypes[1].OneofWrappers = []any{
+		(*EvolutionV2_Note)(nil),
+		(*EvolutionV2_Code)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_evolution3_proto_rawDesc), len(file_evolution3_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_evolution3_proto_goTypes,
+		DependencyIndexes: file_evolution3_proto_depIdxs,
+		MessageInfos:      file_evolution3_proto_msgTypes,
+	}.Build()
+	File_evolution3_proto = out.File
+	file_evolution3_proto_goTypes = nil
+	file_evolution3_proto_depIdxs = nil
+}
@@ -0,0 +1,282 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: gateway3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// HttpRule is the part of google.api.HttpRule that cmd/httpgateway
+// understands, with the same field numbers, so schemas annotated with
+// google.api.http decode as these rules.
+type HttpRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Pattern:
+	//
+	//	*HttpRule_Get
+	//	*HttpRule_Put
+	//	*HttpRule_Post
+	//	*HttpRule_Delete
+	//	*HttpRule_Patch
+	Pattern isHttpRule_Pattern `protobuf_oneof:"pattern"`
+	// Field of the request message filled from the request body, or "*" for
+	// the whole message. Fields not bound by the path or the body are read
+	// from the query string.
+	Body          string `protobuf:"bytes,7,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HttpRule) Reset() {
+	*x = HttpRule{}
+	mi := &file_gateway3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HttpRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HttpRule) ProtoMessage() {}
+
+func (x *HttpRule) ProtoReflect() protoreflect.Message {
+	mi := &file_gateway3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HttpRule.ProtoReflect.Descriptor instead.
+func (*HttpRule) Descriptor() ([]byte, []int) {
+	return file_gateway3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HttpRule) GetPattern() isHttpRule_Pattern {
+	if x != nil {
+		return x.Pattern
+	}
+	return nil
+}
+
+func (x *HttpRule) GetGet() string {
+	if x != nil {
+		if x, ok := x.Pattern.(*HttpRule_Get); ok {
+			return x.Get
+		}
+	}
+	return ""
+}
+
+func (x *HttpRule) GetPut() string {
+	if x != nil {
+		if x, ok := x.Pattern.(*HttpRule_Put); ok {
+			return x.Put
+		}
+	}
+	return ""
+}
+
+func (x *HttpRule) GetPost() string {
+	if x != nil {
+		if x, ok := x.Pattern.(*HttpRule_Post); ok {
+			return x.Post
+		}
+	}
+	return ""
+}
+
+func (x *HttpRule) GetDelete() string {
+	if x != nil {
+		if x, ok := x.Pattern.(*HttpRule_Delete); ok {
+			return x.Delete
+		}
+	}
+	return ""
+}
+
+func (x *HttpRule) GetPatch() string {
+	if x != nil {
+		if x, ok := x.Pattern.(*HttpRule_Patch); ok {
+			return x.Patch
+		}
+	}
+	return ""
+}
+
+func (x *HttpRule) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+type isHttpRule_Pattern interface {
+	isHttpRule_Pattern()
+}
+
+type HttpRule_Get struct {
+	Get string `protobuf:"bytes,2,opt,name=get,proto3,oneof"`
+}
+
+type HttpRule_Put struct {
+	Put string `protobuf:"bytes,3,opt,name=put,proto3,oneof"`
+}
+
+type HttpRule_Post struct {
+	Post string `protobuf:"bytes,4,opt,name=post,proto3,oneof"`
+}
+
+type HttpRule_Delete struct {
+	Delete string `protobuf:"bytes,5,opt,name=delete,proto3,oneof"`
+}
+
+type HttpRule_Patch struct {
+	Patch string `protobuf:"bytes,6,opt,name=patch,proto3,oneof"`
+}
+
+func (*HttpRule_Get) isHttpRule_Pattern() {}
+
+func (*HttpRule_Put) isHttpRule_Pattern() {}
+
+func (*HttpRule_Post) isHttpRule_Pattern() {}
+
+func (*HttpRule_Delete) isHttpRule_Pattern() {}
+
+func (*HttpRule_Patch) isHttpRule_Pattern() {}
+
+var file_gateway3_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*HttpRule)(nil),
+		Field:         72295728,
+		Name:          "compat.gateway.http",
+		Tag:           "bytes,72295728,opt,name=http",
+		Filename:      "gateway3.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// Same number as google.api.http.
+	//
+	// optional compat.gateway.HttpRule http = 72295728;
+	E_Http = &file_gateway3_proto_extTypes[0]
+)
+
+var File_gateway3_proto protoreflect.FileDescriptor
+
+const file_gateway3_proto_rawDesc = "" +
+	"\n" +
+	"\x0egateway3.proto\x12\x0ecompat.gateway\x1a google/protobuf/descriptor.proto\x1a\x13service_unary.proto\"\x99\x01\n" +
+	"\bHttpRule\x12\x12\n" +
+	"\x03get\x18\x02 \x01(\tH\x00R\x03get\x12\x12\n" +
+	"\x03put\x18\x03 \x01(\tH\x00R\x03put\x12\x14\n" +
+	"\x04post\x18\x04 \x01(\tH\x00R\x04post\x12\x18\n" +
+	"\x06delete\x18\x05 \x01(\tH\x00R\x06delete\x12\x16\n" +
+	"\x05patch\x18\x06 \x01(\tH\x00R\x05patch\x12\x12\n" +
+	"\x04body\x18\a \x01(\tR\x04bodyB\t\n" +
+	"\apattern2\x92\x02\n" +
+	"\fUnaryService\x125\n" +
+	"\x04Ping\x12\f.PingRequest\x1a\r.PingResponse\"\x10\x82\xd3\xe4\x93\x02\n" +
+	"\x12\b/v1/ping\x12D\n" +
+	"\aGetItem\x12\x0f.GetItemRequest\x1a\x10.GetItemResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/items/{id}\x12L\n" +
+	"\x06Health\x12\x0e.HealthRequest\x1a\x0f.HealthResponse\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/v1/health/{service_name}\x127\n" +
+	"\x04Echo\x12\f.EchoMessage\x1a\f.EchoMessage\"\x13\x82\xd3\xe4\x93\x02\r:\x01*\"\b/v1/echo:O\n" +
+	"\x04http\x12\x1e.google.protobuf.MethodOptions\x18\xb0ʼ\" \x01(\v2\x18.compat.gateway.HttpRuleR\x04httpb\x06proto3"
+
+var (
+	file_gateway3_proto_rawDescOnce sync.Once
+	file_gateway3_proto_rawDescData []byte
+)
+
+func file_gateway3_proto_rawDescGZIP() []byte {
+	file_gateway3_proto_rawDescOnce.Do(func() {
+		file_gateway3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_gateway3_proto_rawDesc), len(file_gateway3_proto_rawDesc)))
+	})
+	return file_gateway3_proto_rawDescData
+}
+
+var file_gateway3_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_gateway3_proto_goTypes = []any{
+	(*HttpRule)(nil),                   // 0: compat.gateway.HttpRule
+	(*descriptorpb.MethodOptions)(nil), // 1: google.protobuf.MethodOptions
+	(*PingRequest)(nil),                // 2: PingRequest
+	(*GetItemRequest)(nil),             // 3: GetItemRequest
+	(*HealthRequest)(nil),              // 4: HealthRequest
+	(*EchoMessage)(nil),                // 5: EchoMessage
+	(*PingResponse)(nil),               // 6: PingResponse
+	(*GetItemResponse)(nil),            // 7: GetItemResponse
+	(*HealthResponse)(nil),             // 8: HealthResponse
+}
+var file_gateway3_proto_depIdxs = []int32{
+	1, // 0: compat.gateway.http:extendee -> google.protobuf.MethodOptions
+	0, // 1: compat.gateway.http:type_name -> compat.gateway.HttpRule
+	2, // 2: compat.gateway.UnaryService.Ping:input_type -> PingRequest
+	3, // 3: compat.gateway.UnaryService.GetItem:input_type -> GetItemRequest
+	4, // 4: compat.gateway.UnaryService.Health:input_type -> HealthRequest
+	5, // 5: compat.gateway.UnaryService.Echo:input_type -> EchoMessage
+	6, // 6: compat.gateway.UnaryService.Ping:output_type -> PingResponse
+	7, // 7: compat.gateway.UnaryService.GetItem:output_type -> GetItemResponse
+	8, // 8: compat.gateway.UnaryService.Health:output_type -> HealthResponse
+	5, // 9: compat.gateway.UnaryService.Echo:output_type -> EchoMessage
+	6, // [6:10] is the sub-list for method output_type
+	2, // [2:6] is the sub-list for method input_type
+	1, // [1:2] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_gateway3_proto_init() }
+func file_gateway3_proto_init() {
+	if File_gateway3_proto != nil {
+		return
+	}
+	file_service_unary_proto_init()
+	file_gateway3_proto_msgTypes[0].OneofWrappers = []any{
+		(*HttpRule_Get)(nil),
+		(*HttpRule_Put)(nil),
+		(*HttpRule_Post)(nil),
+		(*HttpRule_Delete)(nil),
+		(*HttpRule_Patch)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gateway3_proto_rawDesc), len(file_gateway3_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 1,
+			NumServices:   1,
+		},
+		GoTypes:           file_gateway3_proto_goTypes,
+		DependencyIndexes: file_gateway3_proto_depIdxs,
+		MessageInfos:      file_gateway3_proto_msgTypes,
+		ExtensionInfos:    file_gateway3_proto_extTypes,
+	}.Build()
+	File_gateway3_proto = out.File
+	file_gateway3_proto_goTypes = nil
+	file_gateway3_proto_depIdxs = nil
+}
@@ -0,0 +1,636 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: harness.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FirehoseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int32                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	ChunkSize     int32                  `protobuf:"varint,2,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FirehoseRequest) Reset() {
+	*x = FirehoseRequest{}
+	mi := &file_harness_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FirehoseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FirehoseRequest) ProtoMessage() {}
+
+func (x *FirehoseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_harness_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FirehoseRequest.ProtoReflect.Descriptor instead.
+func (*FirehoseRequest) Descriptor() ([]byte, []int) {
+	return file_harness_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FirehoseRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *FirehoseRequest) GetChunkSize() int32 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+type FirehoseChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           int32                  `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FirehoseChunk) Reset() {
+	*x = FirehoseChunk{}
+	mi := &file_harness_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FirehoseChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FirehoseChunk) ProtoMessage() {}
+
+func (x *FirehoseChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_harness_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FirehoseChunk.ProtoReflect.Descriptor instead.
+func (*FirehoseChunk) Descriptor() ([]byte, []int) {
+	return file_harness_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FirehoseChunk) GetSeq() int32 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *FirehoseChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type FailAfterNRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	N             int32                  `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FailAfterNRequest) Reset() {
+	*x = FailAfterNRequest{}
+	mi := &file_harness_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailAfterNRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailAfterNRequest) ProtoMessage() {}
+
+func (x *FailAfterNRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_harness_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailAfterNRequest.ProtoReflect.Descriptor instead.
+func (*FailAfterNRequest) Descriptor() ([]byte, []int) {
+	return file_harness_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FailAfterNRequest) GetN() int32 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+func (x *FailAfterNRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type BlobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	ResponseSize  int32                  `protobuf:"varint,2,opt,name=response_size,json=responseSize,proto3" json:"response_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlobRequest) Reset() {
+	*x = BlobRequest{}
+	mi := &file_harness_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobRequest) ProtoMessage() {}
+
+func (x *BlobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_harness_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobRequest.ProtoReflect.Descriptor instead.
+func (*BlobRequest) Descriptor() ([]byte, []int) {
+	return file_harness_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BlobRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *BlobRequest) GetResponseSize() int32 {
+	if x != nil {
+		return x.ResponseSize
+	}
+	return 0
+}
+
+type BlobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlobResponse) Reset() {
+	*x = BlobResponse{}
+	mi := &file_harness_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobResponse) ProtoMessage() {}
+
+func (x *BlobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_harness_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobResponse.ProtoReflect.Descriptor instead.
+func (*BlobResponse) Descriptor() ([]byte, []int) {
+	return file_harness_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BlobResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// Traffic counters reported by the Go rpcserver in STATS frames. Byte counts
+// include the 5-byte frame headers.
+type MethodStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Method        string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Calls         uint64                 `protobuf:"varint,2,opt,name=calls,proto3" json:"calls,omitempty"`
+	Errors        uint64                 `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"`
+	BytesIn       uint64                 `protobuf:"varint,4,opt,name=bytes_in,json=bytesIn,proto3" json:"bytes_in,omitempty"`
+	BytesOut      uint64                 `protobuf:"varint,5,opt,name=bytes_out,json=bytesOut,proto3" json:"bytes_out,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MethodStats) Reset() {
+	*x = MethodStats{}
+	mi := &file_harness_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MethodStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MethodStats) ProtoMessage() {}
+
+func (x *MethodStats) ProtoReflect() protoreflect.Message {
+	mi := &file_harness_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MethodStats.ProtoReflect.Descriptor instead.
+func (*MethodStats) Descriptor() ([]byte, []int) {
+	return file_harness_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MethodStats) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *MethodStats) GetCalls() uint64 {
+	if x != nil {
+		return x.Calls
+	}
+	return 0
+}
+
+func (x *MethodStats) GetErrors() uint64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *MethodStats) GetBytesIn() uint64 {
+	if x != nil {
+		return x.BytesIn
+	}
+	return 0
+}
+
+func (x *MethodStats) GetBytesOut() uint64 {
+	if x != nil {
+		return x.BytesOut
+	}
+	return 0
+}
+
+type StatsReport struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Methods              []*MethodStats         `protobuf:"bytes,1,rep,name=methods,proto3" json:"methods,omitempty"`
+	FramesIn             uint64                 `protobuf:"varint,2,opt,name=frames_in,json=framesIn,proto3" json:"frames_in,omitempty"`
+	FramesOut            uint64                 `protobuf:"varint,3,opt,name=frames_out,json=framesOut,proto3" json:"frames_out,omitempty"`
+	BytesIn              uint64                 `protobuf:"varint,4,opt,name=bytes_in,json=bytesIn,proto3" json:"bytes_in,omitempty"`
+	BytesOut             uint64                 `protobuf:"varint,5,opt,name=bytes_out,json=bytesOut,proto3" json:"bytes_out,omitempty"`
+	Errors               uint64                 `protobuf:"varint,6,opt,name=errors,proto3" json:"errors,omitempty"`
+	MaxConcurrentStreams uint32                 `protobuf:"varint,7,opt,name=max_concurrent_streams,json=maxConcurrentStreams,proto3" json:"max_concurrent_streams,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *StatsReport) Reset() {
+	*x = StatsReport{}
+	mi := &file_harness_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsReport) ProtoMessage() {}
+
+func (x *StatsReport) ProtoReflect() protoreflect.Message {
+	mi := &file_harness_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsReport.ProtoReflect.Descriptor instead.
+func (*StatsReport) Descriptor() ([]byte, []int) {
+	return file_harness_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StatsReport) GetMethods() []*MethodStats {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+func (x *StatsReport) GetFramesIn() uint64 {
+	if x != nil {
+		return x.FramesIn
+	}
+	return 0
+}
+
+func (x *StatsReport) GetFramesOut() uint64 {
+	if x != nil {
+		return x.FramesOut
+	}
+	return 0
+}
+
+func (x *StatsReport) GetBytesIn() uint64 {
+	if x != nil {
+		return x.BytesIn
+	}
+	return 0
+}
+
+func (x *StatsReport) GetBytesOut() uint64 {
+	if x != nil {
+		return x.BytesOut
+	}
+	return 0
+}
+
+func (x *StatsReport) GetErrors() uint64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *StatsReport) GetMaxConcurrentStreams() uint32 {
+	if x != nil {
+		return x.MaxConcurrentStreams
+	}
+	return 0
+}
+
+// Wire-compatible with google.protobuf.Any, for the EchoAny method on
+// runtimes that do not ship the well-known types. The Go side uses anypb.
+type AnyMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TypeUrl       string                 `protobuf:"bytes,1,opt,name=type_url,json=typeUrl,proto3" json:"type_url,omitempty"`
+	Value         []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnyMessage) Reset() {
+	*x = AnyMessage{}
+	mi := &file_harness_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnyMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnyMessage) ProtoMessage() {}
+
+func (x *AnyMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_harness_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnyMessage.ProtoReflect.Descriptor instead.
+func (*AnyMessage) Descriptor() ([]byte, []int) {
+	return file_harness_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AnyMessage) GetTypeUrl() string {
+	if x != nil {
+		return x.TypeUrl
+	}
+	return ""
+}
+
+func (x *AnyMessage) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+var File_harness_proto protoreflect.FileDescriptor
+
+const file_harness_proto_rawDesc = "" +
+	"\n" +
+	"\rharness.proto\x1a\tacp.proto\x1a\rscalar3.proto\x1a\x17service_streaming.proto\x1a\x13service_unary.proto\"F\n" +
+	"\x0fFirehoseRequest\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x05R\x05count\x12\x1d\n" +
+	"\n" +
+	"chunk_size\x18\x02 \x01(\x05R\tchunkSize\"5\n" +
+	"\rFirehoseChunk\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x05R\x03seq\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\";\n" +
+	"\x11FailAfterNRequest\x12\f\n" +
+	"\x01n\x18\x01 \x01(\x05R\x01n\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"F\n" +
+	"\vBlobRequest\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12#\n" +
+	"\rresponse_size\x18\x02 \x01(\x05R\fresponseSize\"\"\n" +
+	"\fBlobResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"\x8b\x01\n" +
+	"\vMethodStats\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x14\n" +
+	"\x05calls\x18\x02 \x01(\x04R\x05calls\x12\x16\n" +
+	"\x06errors\x18\x03 \x01(\x04R\x06errors\x12\x19\n" +
+	"\bbytes_in\x18\x04 \x01(\x04R\abytesIn\x12\x1b\n" +
+	"\tbytes_out\x18\x05 \x01(\x04R\bbytesOut\"\xf7\x01\n" +
+	"\vStatsReport\x12&\n" +
+	"\amethods\x18\x01 \x03(\v2\f.MethodStatsR\amethods\x12\x1b\n" +
+	"\tframes_in\x18\x02 \x01(\x04R\bframesIn\x12\x1d\n" +
+	"\n" +
+	"frames_out\x18\x03 \x01(\x04R\tframesOut\x12\x19\n" +
+	"\bbytes_in\x18\x04 \x01(\x04R\abytesIn\x12\x1b\n" +
+	"\tbytes_out\x18\x05 \x01(\x04R\bbytesOut\x12\x16\n" +
+	"\x06errors\x18\x06 \x01(\x04R\x06errors\x124\n" +
+	"\x16max_concurrent_streams\x18\a \x01(\rR\x14maxConcurrentStreams\"=\n" +
+	"\n" +
+	"AnyMessage\x12\x19\n" +
+	"\btype_url\x18\x01 \x01(\tR\atypeUrl\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value2\xad\x03\n" +
+	"\x0eHarnessService\x12,\n" +
+	"\n" +
+	"EchoScalar\x12\x0e.ScalarMessage\x1a\x0e.ScalarMessage\x12#\n" +
+	"\aEchoAny\x12\v.AnyMessage\x1a\v.AnyMessage\x12#\n" +
+	"\x04Blob\x12\f.BlobRequest\x1a\r.BlobResponse\x12+\n" +
+	"\rUnknownFields\x12\f.EchoMessage\x1a\f.EchoMessage\x120\n" +
+	"\x12CheckUnknownFields\x12\f.EchoMessage\x1a\f.EchoMessage\x12.\n" +
+	"\bFirehose\x12\x10.FirehoseRequest\x1a\x0e.FirehoseChunk0\x01\x123\n" +
+	"\n" +
+	"FailAfterN\x12\x12.FailAfterNRequest\x1a\x0f.StreamResponse0\x01\x12+\n" +
+	"\n" +
+	"UploadBlob\x12\f.UploadChunk\x1a\r.UploadResult(\x01\x122\n" +
+	"\n" +
+	"AcpSession\x12\x0f.acp.AcpMessage\x1a\x0f.acp.AcpMessage(\x010\x01b\x06proto3"
+
+var (
+	file_harness_proto_rawDescOnce sync.Once
+	file_harness_proto_rawDescData []byte
+)
+
+func file_harness_proto_rawDescGZIP() []byte {
+	file_harness_proto_rawDescOnce.Do(func() {
+		file_harness_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_harness_proto_rawDesc), len(file_harness_proto_rawDesc)))
+	})
+	return file_harness_proto_rawDescData
+}
+
+var file_harness_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_harness_proto_goTypes = []any{
+	(*FirehoseRequest)(nil),   // 0: FirehoseRequest
+	(*FirehoseChunk)(nil),     // 1: FirehoseChunk
+	(*FailAfterNRequest)(nil), // 2: FailAfterNRequest
+	(*BlobRequest)(nil),       // 3: BlobRequest
+	(*BlobResponse)(nil),      // 4: BlobResponse
+	(*MethodStats)(nil),       // 5: MethodStats
+	(*StatsReport)(nil),       // 6: StatsReport
+	(*AnyMessage)(nil),        // 7: AnyMessage
+	(*ScalarMessage)(nil),     // 8: ScalarMessage
+	(*EchoMessage)(nil),       // 9: EchoMessage
+	(*UploadChunk)(nil),       // 10: UploadChunk
+	(*AcpMessage)(nil),        // 11: acp.AcpMessage
+	(*StreamResponse)(nil),    // 12: StreamResponse
+	(*UploadResult)(nil),      // 13: UploadResult
+}
+var file_harness_proto_depIdxs = []int32{
+	5,  // 0: StatsReport.methods:type_name -> MethodStats
+	8,  // 1: HarnessService.EchoScalar:input_type -> ScalarMessage
+	7,  // 2: HarnessService.EchoAny:input_type -> AnyMessage
+	3,  // 3: HarnessService.Blob:input_type -> BlobRequest
+	9,  // 4: HarnessService.UnknownFields:input_type -> EchoMessage
+	9,  // 5: HarnessService.CheckUnknownFields:input_type -> EchoMessage
+	0,  // 6: HarnessService.Firehose:input_type -> FirehoseRequest
+	2,  // 7: HarnessService.FailAfterN:input_type -> FailAfterNRequest
+	10, // 8: HarnessService.UploadBlob:input_type -> UploadChunk
+	11, // 9: HarnessService.AcpSession:input_type -> acp.AcpMessage
+	8,  // 10: HarnessService.EchoScalar:output_type -> ScalarMessage
+	7,  // 11: HarnessService.EchoAny:output_type -> AnyMessage
+	4,  // 12: HarnessService.Blob:output_type -> BlobResponse
+	9,  // 13: HarnessService.UnknownFields:output_type -> EchoMessage
+	9,  // 14: HarnessService.CheckUnknownFields:output_type -> EchoMessage
+	1,  // 15: HarnessService.Firehose:output_type -> FirehoseChunk
+	12, // 16: HarnessService.FailAfterN:output_type -> StreamResponse
+	13, // 17: HarnessService.UploadBlob:output_type -> UploadResult
+	11, // 18: HarnessService.AcpSession:output_type -> acp.AcpMessage
+	10, // [10:19] is the sub-list for method output_type
+	1,  // [1:10] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_harness_proto_init() }
+func file_harness_proto_init() {
+	if File_harness_proto != nil {
+		return
+	}
+	file_acp_proto_init()
+	file_scalar3_proto_init()
+	file_service_streaming_proto_init()
+	file_service_unary_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_harness_proto_rawDesc), len(file_harness_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_harness_proto_goTypes,
+		DependencyIndexes: file_harness_proto_depIdxs,
+		MessageInfos:      file_harness_proto_msgTypes,
+	}.Build()
+	File_harness_proto = out.File
+	file_harness_proto_goTypes = nil
+	file_harness_proto_depIdxs = nil
+}
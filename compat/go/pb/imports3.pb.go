@@ -0,0 +1,157 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: imports3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ImportsMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Origin        *Point                 `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Level         Level                  `protobuf:"varint,2,opt,name=level,proto3,enum=imports.base.Level" json:"level,omitempty"`
+	Tagged        *Tagged                `protobuf:"bytes,3,opt,name=tagged,proto3" json:"tagged,omitempty"`
+	Path          []*Point               `protobuf:"bytes,4,rep,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportsMessage) Reset() {
+	*x = ImportsMessage{}
+	mi := &file_imports3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportsMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportsMessage) ProtoMessage() {}
+
+func (x *ImportsMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_imports3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportsMessage.ProtoReflect.Descriptor instead.
+func (*ImportsMessage) Descriptor() ([]byte, []int) {
+	return file_imports3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ImportsMessage) GetOrigin() *Point {
+	if x != nil {
+		return x.Origin
+	}
+	return nil
+}
+
+func (x *ImportsMessage) GetLevel() Level {
+	if x != nil {
+		return x.Level
+	}
+	return Level_LEVEL_UNSPECIFIED
+}
+
+func (x *ImportsMessage) GetTagged() *Tagged {
+	if x != nil {
+		return x.Tagged
+	}
+	return nil
+}
+
+func (x *ImportsMessage) GetPath() []*Point {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+var File_imports3_proto protoreflect.FileDescriptor
+
+const file_imports3_proto_rawDesc = "" +
+	"\n" +
+	"\x0eimports3.proto\x1a\x12imports_mid3.proto\"\xbe\x01\n" +
+	"\x0eImportsMessage\x12+\n" +
+	"\x06origin\x18\x01 \x01(\v2\x13.imports.base.PointR\x06origin\x12)\n" +
+	"\x05level\x18\x02 \x01(\x0e2\x13.imports.base.LevelR\x05level\x12+\n" +
+	"\x06tagged\x18\x03 \x01(\v2\x13.imports.mid.TaggedR\x06tagged\x12'\n" +
+	"\x04path\x18\x04 \x03(\v2\x13.imports.base.PointR\x04pathb\x06proto3"
+
+var (
+	file_imports3_proto_rawDescOnce sync.Once
+	file_imports3_proto_rawDescData []byte
+)
+
+func file_imports3_proto_rawDescGZIP() []byte {
+	file_imports3_proto_rawDescOnce.Do(func() {
+		file_imports3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_imports3_proto_rawDesc), len(file_imports3_proto_rawDesc)))
+	})
+	return file_imports3_proto_rawDescData
+}
+
+var file_imports3_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_imports3_proto_goTypes = []any{
+	(*ImportsMessage)(nil), // 0: ImportsMessage
+	(*Point)(nil),          // 1: imports.base.Point
+	(Level)(0),             // 2: imports.base.Level
+	(*Tagged)(nil),         // 3: imports.mid.Tagged
+}
+var file_imports3_proto_depIdxs = []int32{
+	1, // 0: ImportsMessage.origin:type_name -> imports.base.Point
+	2, // 1: ImportsMessage.level:type_name -> imports.base.Level
+	3, // 2: ImportsMessage.tagged:type_name -> imports.mid.Tagged
+	1, // 3: ImportsMessage.path:type_name -> imports.base.Point
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_imports3_proto_init() }
+func file_imports3_proto_init() {
+	if File_imports3_proto != nil {
+		return
+	}
+	file_imports_mid3_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_imports3_proto_rawDesc), len(file_imports3_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_imports3_proto_goTypes,
+		DependencyIndexes: file_imports3_proto_depIdxs,
+		MessageInfos:      file_imports3_proto_msgTypes,
+	}.Build()
+	File_imports3_proto = out.File
+	file_imports3_proto_goTypes = nil
+	file_imports3_proto_depIdxs = nil
+}
@@ -0,0 +1,145 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: imports_mid3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Tagged struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Point         *Point                 `protobuf:"bytes,2,opt,name=point,proto3" json:"point,omitempty"`
+	Level         Level                  `protobuf:"varint,3,opt,name=level,proto3,enum=imports.base.Level" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tagged) Reset() {
+	*x = Tagged{}
+	mi := &file_imports_mid3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tagged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tagged) ProtoMessage() {}
+
+func (x *Tagged) ProtoReflect() protoreflect.Message {
+	mi := &file_imports_mid3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tagged.ProtoReflect.Descriptor instead.
+func (*Tagged) Descriptor() ([]byte, []int) {
+	return file_imports_mid3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Tagged) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *Tagged) GetPoint() *Point {
+	if x != nil {
+		return x.Point
+	}
+	return nil
+}
+
+func (x *Tagged) GetLevel() Level {
+	if x != nil {
+		return x.Level
+	}
+	return Level_LEVEL_UNSPECIFIED
+}
+
+var File_imports_mid3_proto protoreflect.FileDescriptor
+
+const file_imports_mid3_proto_rawDesc = "" +
+	"\n" +
+	"\x12imports_mid3.proto\x12\vimports.mid\x1a\x13imports_base3.proto\"p\n" +
+	"\x06Tagged\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12)\n" +
+	"\x05point\x18\x02 \x01(\v2\x13.imports.base.PointR\x05point\x12)\n" +
+	"\x05level\x18\x03 \x01(\x0e2\x13.imports.base.LevelR\x05levelP\x00b\x06proto3"
+
+var (
+	file_imports_mid3_proto_rawDescOnce sync.Once
+	file_imports_mid3_proto_rawDescData []byte
+)
+
+func file_imports_mid3_proto_rawDescGZIP() []byte {
+	file_imports_mid3_proto_rawDescOnce.Do(func() {
+		file_imports_mid3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_imports_mid3_proto_rawDesc), len(file_imports_mid3_proto_rawDesc)))
+	})
+	return file_imports_mid3_proto_rawDescData
+}
+
+var file_imports_mid3_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_imports_mid3_proto_goTypes = []any{
+	(*Tagged)(nil), // 0: imports.mid.Tagged
+	(*Point)(nil),  // 1: imports.base.Point
+	(Level)(0),     // 2: imports.base.Level
+}
+var file_imports_mid3_proto_depIdxs = []int32{
+	1, // 0: imports.mid.Tagged.point:type_name -> imports.base.Point
+	2, // 1: imports.mid.Tagged.level:type_name -> imports.base.Level
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_imports_mid3_proto_init() }
+func file_imports_mid3_proto_init() {
+	if File_imports_mid3_proto != nil {
+		return
+	}
+	file_imports_base3_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_imports_mid3_proto_rawDesc), len(file_imports_mid3_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_imports_mid3_proto_goTypes,
+		DependencyIndexes: file_imports_mid3_proto_depIdxs,
+		MessageInfos:      file_imports_mid3_proto_msgTypes,
+	}.Build()
+	File_imports_mid3_proto = out.File
+	file_imports_mid3_proto_goTypes = nil
+	file_imports_mid3_proto_depIdxs = nil
+}
@@ -0,0 +1,190 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: json3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Field names whose JSON names are easy to get wrong, and custom json_name
+// options that override them.
+type JsonNames struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Plain              int32                  `protobuf:"varint,1,opt,name=plain,json=customName,proto3" json:"plain,omitempty"`
+	XLeadingUnderscore string                 `protobuf:"bytes,2,opt,name=_leading_underscore,json=LeadingUnderscore,proto3" json:"_leading_underscore,omitempty"`
+	Field_1Digit       int32                  `protobuf:"varint,3,opt,name=field_1_digit,json=field1Digit,proto3" json:"field_1_digit,omitempty"`
+	ALLCAPS            string                 `protobuf:"bytes,4,opt,name=ALLCAPS,proto3" json:"ALLCAPS,omitempty"`
+	Mixed_CaseField    int32                  `protobuf:"varint,5,opt,name=mixed_Case_field,json=mixedCaseField,proto3" json:"mixed_Case_field,omitempty"`
+	Renamed            string                 `protobuf:"bytes,6,opt,name=renamed,json=snake_case_json,proto3" json:"renamed,omitempty"`
+	Child              *JsonNames             `protobuf:"bytes,7,opt,name=child,json=kid,proto3" json:"child,omitempty"`
+	ListOfValues       []int32                `protobuf:"varint,8,rep,packed,name=list_of_values,json=listOfValues,proto3" json:"list_of_values,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *JsonNames) Reset() {
+	*x = JsonNames{}
+	mi := &file_json3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JsonNames) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JsonNames) ProtoMessage() {}
+
+func (x *JsonNames) ProtoReflect() protoreflect.Message {
+	mi := &file_json3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JsonNames.ProtoReflect.Descriptor instead.
+func (*JsonNames) Descriptor() ([]byte, []int) {
+	return file_json3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *JsonNames) GetPlain() int32 {
+	if x != nil {
+		return x.Plain
+	}
+	return 0
+}
+
+func (x *JsonNames) GetXLeadingUnderscore() string {
+	if x != nil {
+		return x.XLeadingUnderscore
+	}
+	return ""
+}
+
+func (x *JsonNames) GetField_1Digit() int32 {
+	if x != nil {
+		return x.Field_1Digit
+	}
+	return 0
+}
+
+func (x *JsonNames) GetALLCAPS() string {
+	if x != nil {
+		return x.ALLCAPS
+	}
+	return ""
+}
+
+func (x *JsonNames) GetMixed_CaseField() int32 {
+	if x != nil {
+		return x.Mixed_CaseField
+	}
+	return 0
+}
+
+func (x *JsonNames) GetRenamed() string {
+	if x != nil {
+		return x.Renamed
+	}
+	return ""
+}
+
+func (x *JsonNames) GetChild() *JsonNames {
+	if x != nil {
+		return x.Child
+	}
+	return nil
+}
+
+func (x *JsonNames) GetListOfValues() []int32 {
+	if x != nil {
+		return x.ListOfValues
+	}
+	return nil
+}
+
+var File_json3_proto protoreflect.FileDescriptor
+
+const file_json3_proto_rawDesc = "" +
+	"\n" +
+	"\vjson3.proto\"\xa6\x02\n" +
+	"\tJsonNames\x12\x19\n" +
+	"\x05plain\x18\x01 \x01(\x05R\n" +
+	"customName\x12.\n" +
+	"\x13_leading_underscore\x18\x02 \x01(\tR\x11LeadingUnderscore\x12\"\n" +
+	"\rfield_1_digit\x18\x03 \x01(\x05R\vfield1Digit\x12\x18\n" +
+	"\aALLCAPS\x18\x04 \x01(\tR\aALLCAPS\x12(\n" +
+	"\x10mixed_Case_field\x18\x05 \x01(\x05R\x0emixedCaseField\x12 \n" +
+	"\arenamed\x18\x06 \x01(\tR\x0fsnake_case_json\x12\x1e\n" +
+	"\x05child\x18\a \x01(\v2\n" +
+	".JsonNamesR\x03kid\x12$\n" +
+	"\x0elist_of_values\x18\b \x03(\x05R\flistOfValuesb\x06proto3"
+
+var (
+	file_json3_proto_rawDescOnce sync.Once
+	file_json3_proto_rawDescData []byte
+)
+
+func file_json3_proto_rawDescGZIP() []byte {
+	file_json3_proto_rawDescOnce.Do(func() {
+		file_json3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_json3_proto_rawDesc), len(file_json3_proto_rawDesc)))
+	})
+	return file_json3_proto_rawDescData
+}
+
+var file_json3_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_json3_proto_goTypes = []any{
+	(*JsonNames)(nil), // 0: JsonNames
+}
+var file_json3_proto_depIdxs = []int32{
+	0, // 0: JsonNames.child:type_name -> JsonNames
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_json3_proto_init() }
+func file_json3_proto_init() {
+	if File_json3_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_json3_proto_rawDesc), len(file_json3_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_json3_proto_goTypes,
+		DependencyIndexes: file_json3_proto_depIdxs,
+		MessageInfos:      file_json3_proto_msgTypes,
+	}.Build()
+	File_json3_proto = out.File
+	file_json3_proto_goTypes = nil
+	file_json3_proto_depIdxs = nil
+}
@@ -0,0 +1,156 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: limits3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A recursive message for vectors that are small on the wire but expensive
+// to decode: deep nesting, many empty submessages, and length prefixes far
+// larger than the data that follows.
+type LimitNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Child         *LimitNode             `protobuf:"bytes,1,opt,name=child,proto3" json:"child,omitempty"`
+	Children      []*LimitNode           `protobuf:"bytes,2,rep,name=children,proto3" json:"children,omitempty"`
+	Blobs         [][]byte               `protobuf:"bytes,3,rep,name=blobs,proto3" json:"blobs,omitempty"`
+	Values        []int32                `protobuf:"varint,4,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LimitNode) Reset() {
+	*x = LimitNode{}
+	mi := &file_limits3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LimitNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LimitNode) ProtoMessage() {}
+
+func (x *LimitNode) ProtoReflect() protoreflect.Message {
+	mi := &file_limits3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LimitNode.ProtoReflect.Descriptor instead.
+func (*LimitNode) Descriptor() ([]byte, []int) {
+	return file_limits3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LimitNode) GetChild() *LimitNode {
+	if x != nil {
+		return x.Child
+	}
+	return nil
+}
+
+func (x *LimitNode) GetChildren() []*LimitNode {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+func (x *LimitNode) GetBlobs() [][]byte {
+	if x != nil {
+		return x.Blobs
+	}
+	return nil
+}
+
+func (x *LimitNode) GetValues() []int32 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+var File_limits3_proto protoreflect.FileDescriptor
+
+const file_limits3_proto_rawDesc = "" +
+	"\n" +
+	"\rlimits3.proto\"\x83\x01\n" +
+	"\tLimitNode\x12 \n" +
+	"\x05child\x18\x01 \x01(\v2\n" +
+	".LimitNodeR\x05child\x12&\n" +
+	"\bchildren\x18\x02 \x03(\v2\n" +
+	".LimitNodeR\bchildren\x12\x14\n" +
+	"\x05blobs\x18\x03 \x03(\fR\x05blobs\x12\x16\n" +
+	"\x06values\x18\x04 \x03(\x05R\x06valuesb\x06proto3"
+
+var (
+	file_limits3_proto_rawDescOnce sync.Once
+	file_limits3_proto_rawDescData []byte
+)
+
+func file_limits3_proto_rawDescGZIP() []byte {
+	file_limits3_proto_rawDescOnce.Do(func() {
+		file_limits3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_limits3_proto_rawDesc), len(file_limits3_proto_rawDesc)))
+	})
+	return file_limits3_proto_rawDescData
+}
+
+var file_limits3_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_limits3_proto_goTypes = []any{
+	(*LimitNode)(nil), // 0: LimitNode
+}
+var file_limits3_proto_depIdxs = []int32{
+	0, // 0: LimitNode.child:type_name -> LimitNode
+	0, // 1: LimitNode.children:type_name -> LimitNode
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_limits3_proto_init() }
+func file_limits3_proto_init() {
+	if File_limits3_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_limits3_proto_rawDesc), len(file_limits3_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_limits3_proto_goTypes,
+		DependencyIndexes: file_limits3_proto_depIdxs,
+		MessageInfos:      file_limits3_proto_msgTypes,
+	}.Build()
+	File_limits3_proto = out.File
+	file_limits3_proto_goTypes = nil
+	file_limits3_proto_depIdxs = nil
+}
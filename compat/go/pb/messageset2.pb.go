@@ -0,0 +1,287 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: messageset2.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// MessageSetContainer uses the legacy MessageSet encoding: every extension
+// is a group at field 1 holding its field number as type_id (field 2) and
+// the encoded message (field 3).
+type MessageSetContainer struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	extensionFields protoimpl.ExtensionFields
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MessageSetContainer) Reset() {
+	*x = MessageSetContainer{}
+	mi := &file_messageset2_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageSetContainer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageSetContainer) ProtoMessage() {}
+
+func (x *MessageSetContainer) ProtoReflect() protoreflect.Message {
+	mi := &file_messageset2_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageSetContainer.ProtoReflect.Descriptor instead.
+func (*MessageSetContainer) Descriptor() ([]byte, []int) {
+	return file_messageset2_proto_rawDescGZIP(), []int{0}
+}
+
+// MessageSetCarrier has the same extension range with the ordinary encoding.
+// The Go runtime drops unknown fields when encoding a MessageSet unless it is
+// built with the protolegacy tag, so the Go side holds the vectors in this.
+type MessageSetCarrier struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	extensionFields protoimpl.ExtensionFields
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MessageSetCarrier) Reset() {
+	*x = MessageSetCarrier{}
+	mi := &file_messageset2_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageSetCarrier) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageSetCarrier) ProtoMessage() {}
+
+func (x *MessageSetCarrier) ProtoReflect() protoreflect.Message {
+	mi := &file_messageset2_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageSetCarrier.ProtoReflect.Descriptor instead.
+func (*MessageSetCarrier) Descriptor() ([]byte, []int) {
+	return file_messageset2_proto_rawDescGZIP(), []int{1}
+}
+
+type MessageSetItemA struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         *int32                 `protobuf:"varint,1,opt,name=value" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageSetItemA) Reset() {
+	*x = MessageSetItemA{}
+	mi := &file_messageset2_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageSetItemA) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageSetItemA) ProtoMessage() {}
+
+func (x *MessageSetItemA) ProtoReflect() protoreflect.Message {
+	mi := &file_messageset2_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageSetItemA.ProtoReflect.Descriptor instead.
+func (*MessageSetItemA) Descriptor() ([]byte, []int) {
+	return file_messageset2_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MessageSetItemA) GetValue() int32 {
+	if x != nil && x.Value != nil {
+		return *x.Value
+	}
+	return 0
+}
+
+type MessageSetItemB struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          *string                `protobuf:"bytes,1,opt,name=text" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageSetItemB) Reset() {
+	*x = MessageSetItemB{}
+	mi := &file_messageset2_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageSetItemB) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageSetItemB) ProtoMessage() {}
+
+func (x *MessageSetItemB) ProtoReflect() protoreflect.Message {
+	mi := &file_messageset2_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageSetItemB.ProtoReflect.Descriptor instead.
+func (*MessageSetItemB) Descriptor() ([]byte, []int) {
+	return file_messageset2_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MessageSetItemB) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+var file_messageset2_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*MessageSetContainer)(nil),
+		ExtensionType: (*MessageSetItemA)(nil),
+		Field:         1000,
+		Name:          "item_a",
+		Tag:           "bytes,1000,opt,name=item_a",
+		Filename:      "messageset2.proto",
+	},
+	{
+		ExtendedType:  (*MessageSetContainer)(nil),
+		ExtensionType: (*MessageSetItemB)(nil),
+		Field:         2000,
+		Name:          "item_b",
+		Tag:           "bytes,2000,opt,name=item_b",
+		Filename:      "messageset2.proto",
+	},
+}
+
+// Extension fields to MessageSetContainer.
+var (
+	// optional MessageSetItemA item_a = 1000;
+	E_ItemA = &file_messageset2_proto_extTypes[0]
+	// optional MessageSetItemB item_b = 2000;
+	E_ItemB = &file_messageset2_proto_extTypes[1]
+)
+
+var File_messageset2_proto protoreflect.FileDescriptor
+
+const file_messageset2_proto_rawDesc = "" +
+	"\n" +
+	"\x11messageset2.proto\"#\n" +
+	"\x13MessageSetContainer*\b\b\x04\x10\xff\xff\xff\xff\a:\x02\b\x01\"\x1d\n" +
+	"\x11MessageSetCarrier*\b\b\x04\x10\x80\x80\x80\x80\x02\"'\n" +
+	"\x0fMessageSetItemA\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x05R\x05value\"%\n" +
+	"\x0fMessageSetItemB\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text:>\n" +
+	"\x06item_a\x12\x14.MessageSetContainer\x18\xe8\a \x01(\v2\x10.MessageSetItemAR\x05itemA:>\n" +
+	"\x06item_b\x12\x14.MessageSetContainer\x18\xd0\x0f \x01(\v2\x10.MessageSetItemBR\x05itemB"
+
+var (
+	file_messageset2_proto_rawDescOnce sync.Once
+	file_messageset2_proto_rawDescData []byte
+)
+
+func file_messageset2_proto_rawDescGZIP() []byte {
+	file_messageset2_proto_rawDescOnce.Do(func() {
+		file_messageset2_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_messageset2_proto_rawDesc), len(file_messageset2_proto_rawDesc)))
+	})
+	return file_messageset2_proto_rawDescData
+}
+
+var file_messageset2_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_messageset2_proto_goTypes = []any{
+	(*MessageSetContainer)(nil), // 0: MessageSetContainer
+	(*MessageSetCarrier)(nil),   // 1: MessageSetCarrier
+	(*MessageSetItemA)(nil),     // 2: MessageSetItemA
+	(*MessageSetItemB)(nil),     // 3: MessageSetItemB
+}
+var file_messageset2_proto_depIdxs = []int32{
+	0, // 0: item_a:extendee -> MessageSetContainer
+	0, // 1: item_b:extendee -> MessageSetContainer
+	2, // 2: item_a:type_name -> MessageSetItemA
+	3, // 3: item_b:type_name -> MessageSetItemB
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	2, // [2:4] is the sub-list for extension type_name
+	0, // [0:2] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_messageset2_proto_init() }
+func file_messageset2_proto_init() {
+	if File_messageset2_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_messageset2_proto_rawDesc), len(file_messageset2_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 2,
+			NumServices:   0,
+		},
+		GoTypes:           file_messageset2_proto_goTypes,
+		DependencyIndexes: file_messageset2_proto_depIdxs,
+		MessageInfos:      file_messageset2_proto_msgTypes,
+		ExtensionInfos:    file_messageset2_proto_extTypes,
+	}.Build()
+	File_messageset2_proto = out.File
+	file_messageset2_proto_goTypes = nil
+	file_messageset2_proto_depIdxs = nil
+}
@@ -0,0 +1,694 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: naming3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Naming_Node_Kind int32
+
+const (
+	Naming_Node_KIND_UNSPECIFIED Naming_Node_Kind = 0
+	Naming_Node_KIND_LEAF        Naming_Node_Kind = 1
+	Naming_Node_KIND_BRANCH      Naming_Node_Kind = 2
+)
+
+// Enum value maps for Naming_Node_Kind.
+var (
+	Naming_Node_Kind_name = map[int32]string{
+		0: "KIND_UNSPECIFIED",
+		1: "KIND_LEAF",
+		2: "KIND_BRANCH",
+	}
+	Naming_Node_Kind_value = map[string]int32{
+		"KIND_UNSPECIFIED": 0,
+		"KIND_LEAF":        1,
+		"KIND_BRANCH":      2,
+	}
+)
+
+func (x Naming_Node_Kind) Enum() *Naming_Node_Kind {
+	p := new(Naming_Node_Kind)
+	*p = x
+	return p
+}
+
+func (x Naming_Node_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Naming_Node_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_naming3_proto_enumTypes[0].Descriptor()
+}
+
+func (Naming_Node_Kind) Type() protoreflect.EnumType {
+	return &file_naming3_proto_enumTypes[0]
+}
+
+func (x Naming_Node_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Naming_Node_Kind.Descriptor instead.
+func (Naming_Node_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_naming3_proto_rawDescGZIP(), []int{0, 0, 0}
+}
+
+// Same short names as in Naming.Node; enum values are scoped to
+// the enclosing message, so they may repeat too.
+type Naming_Other_Kind int32
+
+const (
+	Naming_Other_KIND_UNSPECIFIED Naming_Other_Kind = 0
+	Naming_Other_KIND_LEAF        Naming_Other_Kind = 1
+)
+
+// Enum value maps for Naming_Other_Kind.
+var (
+	Naming_Other_Kind_name = map[int32]string{
+		0: "KIND_UNSPECIFIED",
+		1: "KIND_LEAF",
+	}
+	Naming_Other_Kind_value = map[string]int32{
+		"KIND_UNSPECIFIED": 0,
+		"KIND_LEAF":        1,
+	}
+)
+
+func (x Naming_Other_Kind) Enum() *Naming_Other_Kind {
+	p := new(Naming_Other_Kind)
+	*p = x
+	return p
+}
+
+func (x Naming_Other_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Naming_Other_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_naming3_proto_enumTypes[1].Descriptor()
+}
+
+func (Naming_Other_Kind) Type() protoreflect.EnumType {
+	return &file_naming3_proto_enumTypes[1]
+}
+
+func (x Naming_Other_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Naming_Other_Kind.Descriptor instead.
+func (Naming_Other_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_naming3_proto_rawDescGZIP(), []int{0, 1, 0}
+}
+
+type Naming_Deep_Deeper_Deepest_Depth int32
+
+const (
+	Naming_Deep_Deeper_Deepest_DEPTH_UNSPECIFIED Naming_Deep_Deeper_Deepest_Depth = 0
+	Naming_Deep_Deeper_Deepest_DEPTH_BOTTOM      Naming_Deep_Deeper_Deepest_Depth = 4
+)
+
+// Enum value maps for Naming_Deep_Deeper_Deepest_Depth.
+var (
+	Naming_Deep_Deeper_Deepest_Depth_name = map[int32]string{
+		0: "DEPTH_UNSPECIFIED",
+		4: "DEPTH_BOTTOM",
+	}
+	Naming_Deep_Deeper_Deepest_Depth_value = map[string]int32{
+		"DEPTH_UNSPECIFIED": 0,
+		"DEPTH_BOTTOM":      4,
+	}
+)
+
+func (x Naming_Deep_Deeper_Deepest_Depth) Enum() *Naming_Deep_Deeper_Deepest_Depth {
+	p := new(Naming_Deep_Deeper_Deepest_Depth)
+	*p = x
+	return p
+}
+
+func (x Naming_Deep_Deeper_Deepest_Depth) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Naming_Deep_Deeper_Deepest_Depth) Descriptor() protoreflect.EnumDescriptor {
+	return file_naming3_proto_enumTypes[2].Descriptor()
+}
+
+func (Naming_Deep_Deeper_Deepest_Depth) Type() protoreflect.EnumType {
+	return &file_naming3_proto_enumTypes[2]
+}
+
+func (x Naming_Deep_Deeper_Deepest_Depth) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Naming_Deep_Deeper_Deepest_Depth.Descriptor instead.
+func (Naming_Deep_Deeper_Deepest_Depth) EnumDescriptor() ([]byte, []int) {
+	return file_naming3_proto_rawDescGZIP(), []int{0, 2, 0, 0, 0}
+}
+
+// Nested declarations, sibling types sharing a short name, and fields named
+// after Go and Zig keywords: generators have to scope and escape these.
+type Naming struct {
+	state         protoimpl.MessageState      `protogen:"open.v1"`
+	Node          *Naming_Node                `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	OtherNode     *Naming_Other_Node          `protobuf:"bytes,2,opt,name=other_node,json=otherNode,proto3" json:"other_node,omitempty"`
+	Other         *Naming_Other               `protobuf:"bytes,3,opt,name=other,proto3" json:"other,omitempty"`
+	Type          string                      `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Fn            int32                       `protobuf:"varint,5,opt,name=fn,proto3" json:"fn,omitempty"`
+	Error         string                      `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	Var           bool                        `protobuf:"varint,7,opt,name=var,proto3" json:"var,omitempty"`
+	Deepest       *Naming_Deep_Deeper_Deepest `protobuf:"bytes,8,opt,name=deepest,proto3" json:"deepest,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Naming) Reset() {
+	*x = Naming{}
+	mi := &file_naming3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Naming) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Naming) ProtoMessage() {}
+
+func (x *Naming) ProtoReflect() protoreflect.Message {
+	mi := &file_naming3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Naming.ProtoReflect.Descriptor instead.
+func (*Naming) Descriptor() ([]byte, []int) {
+	return file_naming3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Naming) GetNode() *Naming_Node {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *Naming) GetOtherNode() *Naming_Other_Node {
+	if x != nil {
+		return x.OtherNode
+	}
+	return nil
+}
+
+func (x *Naming) GetOther() *Naming_Other {
+	if x != nil {
+		return x.Other
+	}
+	return nil
+}
+
+func (x *Naming) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Naming) GetFn() int32 {
+	if x != nil {
+		return x.Fn
+	}
+	return 0
+}
+
+func (x *Naming) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Naming) GetVar() bool {
+	if x != nil {
+		return x.Var
+	}
+	return false
+}
+
+func (x *Naming) GetDeepest() *Naming_Deep_Deeper_Deepest {
+	if x != nil {
+		return x.Deepest
+	}
+	return nil
+}
+
+type Naming_Node struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          Naming_Node_Kind       `protobuf:"varint,1,opt,name=kind,proto3,enum=Naming_Node_Kind" json:"kind,omitempty"`
+	Child         *Naming_Node_Node      `protobuf:"bytes,2,opt,name=child,proto3" json:"child,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Naming_Node) Reset() {
+	*x = Naming_Node{}
+	mi := &file_naming3_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Naming_Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Naming_Node) ProtoMessage() {}
+
+func (x *Naming_Node) ProtoReflect() protoreflect.Message {
+	mi := &file_naming3_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Naming_Node.ProtoReflect.Descriptor instead.
+func (*Naming_Node) Descriptor() ([]byte, []int) {
+	return file_naming3_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *Naming_Node) GetKind() Naming_Node_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return Naming_Node_KIND_UNSPECIFIED
+}
+
+func (x *Naming_Node) GetChild() *Naming_Node_Node {
+	if x != nil {
+		return x.Child
+	}
+	return nil
+}
+
+type Naming_Other struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Node          *Naming_Other_Node     `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Kind          Naming_Other_Kind      `protobuf:"varint,2,opt,name=kind,proto3,enum=Naming_Other_Kind" json:"kind,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Naming_Other) Reset() {
+	*x = Naming_Other{}
+	mi := &file_naming3_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Naming_Other) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Naming_Other) ProtoMessage() {}
+
+func (x *Naming_Other) ProtoReflect() protoreflect.Message {
+	mi := &file_naming3_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Naming_Other.ProtoReflect.Descriptor instead.
+func (*Naming_Other) Descriptor() ([]byte, []int) {
+	return file_naming3_proto_rawDescGZIP(), []int{0, 1}
+}
+
+func (x *Naming_Other) GetNode() *Naming_Other_Node {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *Naming_Other) GetKind() Naming_Other_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return Naming_Other_KIND_UNSPECIFIED
+}
+
+type Naming_Deep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Naming_Deep) Reset() {
+	*x = Naming_Deep{}
+	mi := &file_naming3_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Naming_Deep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Naming_Deep) ProtoMessage() {}
+
+func (x *Naming_Deep) ProtoReflect() protoreflect.Message {
+	mi := &file_naming3_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Naming_Deep.ProtoReflect.Descriptor instead.
+func (*Naming_Deep) Descriptor() ([]byte, []int) {
+	return file_naming3_proto_rawDescGZIP(), []int{0, 2}
+}
+
+// Resolves to Naming.Node.Node, not Naming.Node.
+type Naming_Node_Node struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Naming_Node_Node) Reset() {
+	*x = Naming_Node_Node{}
+	mi := &file_naming3_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Naming_Node_Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Naming_Node_Node) ProtoMessage() {}
+
+func (x *Naming_Node_Node) ProtoReflect() protoreflect.Message {
+	mi := &file_naming3_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Naming_Node_Node.ProtoReflect.Descriptor instead.
+func (*Naming_Node_Node) Descriptor() ([]byte, []int) {
+	return file_naming3_proto_rawDescGZIP(), []int{0, 0, 0}
+}
+
+func (x *Naming_Node_Node) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type Naming_Other_Node struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fn            int32                  `protobuf:"varint,1,opt,name=fn,proto3" json:"fn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Naming_Other_Node) Reset() {
+	*x = Naming_Other_Node{}
+	mi := &file_naming3_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Naming_Other_Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Naming_Other_Node) ProtoMessage() {}
+
+func (x *Naming_Other_Node) ProtoReflect() protoreflect.Message {
+	mi := &file_naming3_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Naming_Other_Node.ProtoReflect.Descriptor instead.
+func (*Naming_Other_Node) Descriptor() ([]byte, []int) {
+	return file_naming3_proto_rawDescGZIP(), []int{0, 1, 0}
+}
+
+func (x *Naming_Other_Node) GetFn() int32 {
+	if x != nil {
+		return x.Fn
+	}
+	return 0
+}
+
+type Naming_Deep_Deeper struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Naming_Deep_Deeper) Reset() {
+	*x = Naming_Deep_Deeper{}
+	mi := &file_naming3_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Naming_Deep_Deeper) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Naming_Deep_Deeper) ProtoMessage() {}
+
+func (x *Naming_Deep_Deeper) ProtoReflect() protoreflect.Message {
+	mi := &file_naming3_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Naming_Deep_Deeper.ProtoReflect.Descriptor instead.
+func (*Naming_Deep_Deeper) Descriptor() ([]byte, []int) {
+	return file_naming3_proto_rawDescGZIP(), []int{0, 2, 0}
+}
+
+type Naming_Deep_Deeper_Deepest struct {
+	state         protoimpl.MessageState           `protogen:"open.v1"`
+	Depth         Naming_Deep_Deeper_Deepest_Depth `protobuf:"varint,1,opt,name=depth,proto3,enum=Naming_Deep_Deeper_Deepest_Depth" json:"depth,omitempty"`
+	Var           string                           `protobuf:"bytes,2,opt,name=var,proto3" json:"var,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Naming_Deep_Deeper_Deepest) Reset() {
+	*x = Naming_Deep_Deeper_Deepest{}
+	mi := &file_naming3_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Naming_Deep_Deeper_Deepest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Naming_Deep_Deeper_Deepest) ProtoMessage() {}
+
+func (x *Naming_Deep_Deeper_Deepest) ProtoReflect() protoreflect.Message {
+	mi := &file_naming3_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Naming_Deep_Deeper_Deepest.ProtoReflect.Descriptor instead.
+func (*Naming_Deep_Deeper_Deepest) Descriptor() ([]byte, []int) {
+	return file_naming3_proto_rawDescGZIP(), []int{0, 2, 0, 0}
+}
+
+func (x *Naming_Deep_Deeper_Deepest) GetDepth() Naming_Deep_Deeper_Deepest_Depth {
+	if x != nil {
+		return x.Depth
+	}
+	return Naming_Deep_Deeper_Deepest_DEPTH_UNSPECIFIED
+}
+
+func (x *Naming_Deep_Deeper_Deepest) GetVar() string {
+	if x != nil {
+		return x.Var
+	}
+	return ""
+}
+
+var File_naming3_proto protoreflect.FileDescriptor
+
+const file_naming3_proto_rawDesc = "" +
+	"\n" +
+	"\rnaming3.proto\"\xf4\x05\n" +
+	"\x06Naming\x12 \n" +
+	"\x04node\x18\x01 \x01(\v2\f.Naming.NodeR\x04node\x121\n" +
+	"\n" +
+	"other_node\x18\x02 \x01(\v2\x12.Naming.Other.NodeR\totherNode\x12#\n" +
+	"\x05other\x18\x03 \x01(\v2\r.Naming.OtherR\x05other\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02fn\x18\x05 \x01(\x05R\x02fn\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12\x10\n" +
+	"\x03var\x18\a \x01(\bR\x03var\x125\n" +
+	"\adeepest\x18\b \x01(\v2\x1b.Naming.Deep.Deeper.DeepestR\adeepest\x1a\xb0\x01\n" +
+	"\x04Node\x12%\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x11.Naming.Node.KindR\x04kind\x12'\n" +
+	"\x05child\x18\x02 \x01(\v2\x11.Naming.Node.NodeR\x05child\x1a\x1a\n" +
+	"\x04Node\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\"<\n" +
+	"\x04Kind\x12\x14\n" +
+	"\x10KIND_UNSPECIFIED\x10\x00\x12\r\n" +
+	"\tKIND_LEAF\x10\x01\x12\x0f\n" +
+	"\vKIND_BRANCH\x10\x02\x1a\x9c\x01\n" +
+	"\x05Other\x12&\n" +
+	"\x04node\x18\x01 \x01(\v2\x12.Naming.Other.NodeR\x04node\x12&\n" +
+	"\x04kind\x18\x02 \x01(\x0e2\x12.Naming.Other.KindR\x04kind\x1a\x16\n" +
+	"\x04Node\x12\x0e\n" +
+	"\x02fn\x18\x01 \x01(\x05R\x02fn\"+\n" +
+	"\x04Kind\x12\x14\n" +
+	"\x10KIND_UNSPECIFIED\x10\x00\x12\r\n" +
+	"\tKIND_LEAF\x10\x01\x1a\x9a\x01\n" +
+	"\x04Deep\x1a\x91\x01\n" +
+	"\x06Deeper\x1a\x86\x01\n" +
+	"\aDeepest\x127\n" +
+	"\x05depth\x18\x01 \x01(\x0e2!.Naming.Deep.Deeper.Deepest.DepthR\x05depth\x12\x10\n" +
+	"\x03var\x18\x02 \x01(\tR\x03var\"0\n" +
+	"\x05Depth\x12\x15\n" +
+	"\x11DEPTH_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fDEPTH_BOTTOM\x10\x04b\x06proto3"
+
+var (
+	file_naming3_proto_rawDescOnce sync.Once
+	file_naming3_proto_rawDescData []byte
+)
+
+func file_naming3_proto_rawDescGZIP() []byte {
+	file_naming3_proto_rawDescOnce.Do(func() {
+		file_naming3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_naming3_proto_rawDesc), len(file_naming3_proto_rawDesc)))
+	})
+	return file_naming3_proto_rawDescData
+}
+
+var file_naming3_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_naming3_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_naming3_proto_goTypes = []any{
+	(Naming_Node_Kind)(0),                 // 0: Naming.Node.Kind
+	(Naming_Other_Kind)(0),                // 1: Naming.Other.Kind
+	(Naming_Deep_Deeper_Deepest_Depth)(0), // 2: Naming.Deep.Deeper.Deepest.Depth
+	(*Naming)(nil),                        // 3: Naming
+	(*Naming_Node)(nil),                   // 4: Naming.Node
+	(*Naming_Other)(nil),                  // 5: Naming.Other
+	(*Naming_Deep)(nil),                   // 6: Naming.Deep
+	(*Naming_Node_Node)(nil),              // 7: Naming.Node.Node
+	(*Naming_Other_Node)(nil),             // 8: Naming.Other.Node
+	(*Naming_Deep_Deeper)(nil),            // 9: Naming.Deep.Deeper
+	(*Naming_Deep_Deeper_Deepest)(nil),    // 10: Naming.Deep.Deeper.Deepest
+}
+var file_naming3_proto_depIdxs = []int32{
+	4,  // 0: Naming.node:type_name -> Naming.Node
+	8,  // 1: Naming.other_node:type_name -> Naming.Other.Node
+	5,  // 2: Naming.other:type_name -> Naming.Other
+	10, // 3: Naming.deepest:type_name -> Naming.Deep.Deeper.Deepest
+	0,  // 4: Naming.Node.kind:type_name -> Naming.Node.Kind
+	7,  // 5: Naming.Node.child:type_name -> Naming.Node.Node
+	8,  // 6: Naming.Other.node:type_name -> Naming.Other.Node
+	1,  // 7: Naming.Other.kind:type_name -> Naming.Other.Kind
+	2,  // 8: Naming.Deep.Deeper.Deepest.depth:type_name -> Naming.Deep.Deeper.Deepest.Depth
+	9,  // [9:9] is the sub-list for method output_type
+	9,  // [9:9] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_naming3_proto_init() }
+func file_naming3_proto_init() {
+	if File_naming3_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_naming3_proto_rawDesc), len(file_naming3_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_naming3_proto_goTypes,
+		DependencyIndexes: file_naming3_proto_depIdxs,
+		EnumInfos:         file_naming3_proto_enumTypes,
+		MessageInfos:      file_naming3_proto_msgTypes,
+	}.Build()
+	File_naming3_proto = out.File
+	file_naming3_proto_goTypes = nil
+	file_naming3_proto_depIdxs = nil
+}
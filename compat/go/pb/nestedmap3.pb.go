@@ -0,0 +1,355 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: nestedmap3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Maps whose values are messages holding maps of their own, directly and in
+// repeated fields, three levels down. Each level's map entries are
+// synthesized separately, and keys repeat across levels, so an entry read
+// into the wrong map or level is caught.
+type NestedMaps struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Layers        map[string]*NestedMapLayer `protobuf:"bytes,1,rep,name=layers,proto3" json:"layers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ById          map[int32]*NestedMapLayer  `protobuf:"bytes,2,rep,name=by_id,json=byId,proto3" json:"by_id,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NestedMaps) Reset() {
+	*x = NestedMaps{}
+	mi := &file_nestedmap3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NestedMaps) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NestedMaps) ProtoMessage() {}
+
+func (x *NestedMaps) ProtoReflect() protoreflect.Message {
+	mi := &file_nestedmap3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NestedMaps.ProtoReflect.Descriptor instead.
+func (*NestedMaps) Descriptor() ([]byte, []int) {
+	return file_nestedmap3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *NestedMaps) GetLayers() map[string]*NestedMapLayer {
+	if x != nil {
+		return x.Layers
+	}
+	return nil
+}
+
+func (x *NestedMaps) GetById() map[int32]*NestedMapLayer {
+	if x != nil {
+		return x.ById
+	}
+	return nil
+}
+
+type NestedMapLayer struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Counts        map[string]int32         `protobuf:"bytes,1,rep,name=counts,proto3" json:"counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Leaves        map[int64]*NestedMapLeaf `protobuf:"bytes,2,rep,name=leaves,proto3" json:"leaves,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Buckets       []*NestedMapBucket       `protobuf:"bytes,3,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NestedMapLayer) Reset() {
+	*x = NestedMapLayer{}
+	mi := &file_nestedmap3_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NestedMapLayer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NestedMapLayer) ProtoMessage() {}
+
+func (x *NestedMapLayer) ProtoReflect() protoreflect.Message {
+	mi := &file_nestedmap3_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NestedMapLayer.ProtoReflect.Descriptor instead.
+func (*NestedMapLayer) Descriptor() ([]byte, []int) {
+	return file_nestedmap3_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *NestedMapLayer) GetCounts() map[string]int32 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+func (x *NestedMapLayer) GetLeaves() map[int64]*NestedMapLeaf {
+	if x != nil {
+		return x.Leaves
+	}
+	return nil
+}
+
+func (x *NestedMapLayer) GetBuckets() []*NestedMapBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+type NestedMapBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Names         map[uint32]string      `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Flags         map[bool][]byte        `protobuf:"bytes,2,rep,name=flags,proto3" json:"flags,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NestedMapBucket) Reset() {
+	*x = NestedMapBucket{}
+	mi := &file_nestedmap3_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NestedMapBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NestedMapBucket) ProtoMessage() {}
+
+func (x *NestedMapBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_nestedmap3_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NestedMapBucket.ProtoReflect.Descriptor instead.
+func (*NestedMapBucket) Descriptor() ([]byte, []int) {
+	return file_nestedmap3_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *NestedMapBucket) GetNames() map[uint32]string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *NestedMapBucket) GetFlags() map[bool][]byte {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+type NestedMapLeaf struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Attrs         map[string]string      `protobuf:"bytes,2,rep,name=attrs,proto3" json:"attrs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NestedMapLeaf) Reset() {
+	*x = NestedMapLeaf{}
+	mi := &file_nestedmap3_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NestedMapLeaf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NestedMapLeaf) ProtoMessage() {}
+
+func (x *NestedMapLeaf) ProtoReflect() protoreflect.Message {
+	mi := &file_nestedmap3_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NestedMapLeaf.ProtoReflect.Descriptor instead.
+func (*NestedMapLeaf) Descriptor() ([]byte, []int) {
+	return file_nestedmap3_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *NestedMapLeaf) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *NestedMapLeaf) GetAttrs() map[string]string {
+	if x != nil {
+		return x.Attrs
+	}
+	return nil
+}
+
+var File_nestedmap3_proto protoreflect.FileDescriptor
+
+const file_nestedmap3_proto_rawDesc = "" +
+	"\n" +
+	"\x10nestedmap3.proto\"\xff\x01\n" +
+	"\n" +
+	"NestedMaps\x12/\n" +
+	"\x06layers\x18\x01 \x03(\v2\x17.NestedMaps.LayersEntryR\x06layers\x12*\n" +
+	"\x05by_id\x18\x02 \x03(\v2\x15.NestedMaps.ByIdEntryR\x04byId\x1aJ\n" +
+	"\vLayersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12%\n" +
+	"\x05value\x18\x02 \x01(\v2\x0f.NestedMapLayerR\x05value:\x028\x01\x1aH\n" +
+	"\tByIdEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x05R\x03key\x12%\n" +
+	"\x05value\x18\x02 \x01(\v2\x0f.NestedMapLayerR\x05value:\x028\x01\"\xac\x02\n" +
+	"\x0eNestedMapLayer\x123\n" +
+	"\x06counts\x18\x01 \x03(\v2\x1b.NestedMapLayer.CountsEntryR\x06counts\x123\n" +
+	"\x06leaves\x18\x02 \x03(\v2\x1b.NestedMapLayer.LeavesEntryR\x06leaves\x12*\n" +
+	"\abuckets\x18\x03 \x03(\v2\x10.NestedMapBucketR\abuckets\x1a9\n" +
+	"\vCountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1aI\n" +
+	"\vLeavesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x03R\x03key\x12$\n" +
+	"\x05value\x18\x02 \x01(\v2\x0e.NestedMapLeafR\x05value:\x028\x01\"\xeb\x01\n" +
+	"\x0fNestedMapBucket\x121\n" +
+	"\x05names\x18\x01 \x03(\v2\x1b.NestedMapBucket.NamesEntryR\x05names\x121\n" +
+	"\x05flags\x18\x02 \x03(\v2\x1b.NestedMapBucket.FlagsEntryR\x05flags\x1a8\n" +
+	"\n" +
+	"NamesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\rR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a8\n" +
+	"\n" +
+	"FlagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\bR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value:\x028\x01\"\x8e\x01\n" +
+	"\rNestedMapLeaf\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12/\n" +
+	"\x05attrs\x18\x02 \x03(\v2\x19.NestedMapLeaf.AttrsEntryR\x05attrs\x1a8\n" +
+	"\n" +
+	"AttrsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01b\x06proto3"
+
+var (
+	file_nestedmap3_proto_rawDescOnce sync.Once
+	file_nestedmap3_proto_rawDescData []byte
+)
+
+func file_nestedmap3_proto_rawDescGZIP() []byte {
+	file_nestedmap3_proto_rawDescOnce.Do(func() {
+		file_nestedmap3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nestedmap3_proto_rawDesc), len(file_nestedmap3_proto_rawDesc)))
+	})
+	return file_nestedmap3_proto_rawDescData
+}
+
+var file_nestedmap3_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_nestedmap3_proto_goTypes = []any{
+	(*NestedMaps)(nil),      // 0: NestedMaps
+	(*NestedMapLayer)(nil),  // 1: NestedMapLayer
+	(*NestedMapBucket)(nil), // 2: NestedMapBucket
+	(*NestedMapLeaf)(nil),   // 3: NestedMapLeaf
+	nil,                     // 4: NestedMaps.LayersEntry
+	nil,                     // 5: NestedMaps.ByIdEntry
+	nil,                     // 6: NestedMapLayer.CountsEntry
+	nil,                     // 7: NestedMapLayer.LeavesEntry
+	nil,                     // 8: NestedMapBucket.NamesEntry
+	nil,                     // 9: NestedMapBucket.FlagsEntry
+	nil,                     // 10: NestedMapLeaf.AttrsEntry
+}
+var file_nestedmap3_proto_depIdxs = []int32{
+	4,  // 0: NestedMaps.layers:type_name -> NestedMaps.LayersEntry
+	5,  // 1: NestedMaps.by_id:type_name -> NestedMaps.ByIdEntry
+	6,  // 2: NestedMapLayer.counts:type_name -> NestedMapLayer.CountsEntry
+	7,  // 3: NestedMapLayer.leaves:type_name -> NestedMapLayer.LeavesEntry
+	2,  // 4: NestedMapLayer.buckets:type_name -> NestedMapBucket
+	8,  // 5: NestedMapBucket.names:type_name -> NestedMapBucket.NamesEntry
+	9,  // 6: NestedMapBucket.flags:type_name -> NestedMapBucket.FlagsEntry
+	10, // 7: NestedMapLeaf.attrs:type_name -> NestedMapLeaf.AttrsEntry
+	1,  // 8: NestedMaps.LayersEntry.value:type_name -> NestedMapLayer
+	1,  // 9: NestedMaps.ByIdEntry.value:type_name -> NestedMapLayer
+	3,  // 10: NestedMapLayer.LeavesEntry.value:type_name -> NestedMapLeaf
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_nestedmap3_proto_init() }
+func file_nestedmap3_proto_init() {
+	if File_nestedmap3_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nestedmap3_proto_rawDesc), len(file_nestedmap3_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_nestedmap3_proto_goTypes,
+		DependencyIndexes: file_nestedmap3_proto_depIdxs,
+		MessageInfos:      file_nestedmap3_proto_msgTypes,
+	}.Build()
+	File_nestedmap3_proto = out.File
+	file_nestedmap3_proto_goTypes = nil
+	file_nestedmap3_proto_depIdxs = nil
+}
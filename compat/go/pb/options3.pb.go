@@ -0,0 +1,202 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: options3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Record struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Record) Reset() {
+	*x = Record{}
+	mi := &file_options3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Record) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Record) ProtoMessage() {}
+
+func (x *Record) ProtoReflect() protoreflect.Message {
+	mi := &file_options3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Record.ProtoReflect.Descriptor instead.
+func (*Record) Descriptor() ([]byte, []int) {
+	return file_options3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Record) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Record) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+var file_options3_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50001,
+		Name:          "compat.options.schema_owner",
+		Tag:           "bytes,50001,opt,name=schema_owner",
+		Filename:      "options3.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50002,
+		Name:          "compat.options.table_name",
+		Tag:           "bytes,50002,opt,name=table_name",
+		Filename:      "options3.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50003,
+		Name:          "compat.options.indexed",
+		Tag:           "varint,50003,opt,name=indexed",
+		Filename:      "options3.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*int32)(nil),
+		Field:         50004,
+		Name:          "compat.options.max_length",
+		Tag:           "varint,50004,opt,name=max_length",
+		Filename:      "options3.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FileOptions.
+var (
+	// optional string schema_owner = 50001;
+	E_SchemaOwner = &file_options3_proto_extTypes[0]
+)
+
+// Extension fields to descriptorpb.MessageOptions.
+var (
+	// optional string table_name = 50002;
+	E_TableName = &file_options3_proto_extTypes[1]
+)
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// optional bool indexed = 50003;
+	E_Indexed = &file_options3_proto_extTypes[2]
+	// optional int32 max_length = 50004;
+	E_MaxLength = &file_options3_proto_extTypes[3]
+)
+
+var File_options3_proto protoreflect.FileDescriptor
+
+const file_options3_proto_rawDesc = "" +
+	"\n" +
+	"\x0eoptions3.proto\x12\x0ecompat.options\x1a google/protobuf/descriptor.proto\"G\n" +
+	"\x06Record\x12\x14\n" +
+	"\x02id\x18\x01 \x01(\x03B\x04\x98\xb5\x18\x01R\x02id\x12\x1a\n" +
+	"\x05title\x18\x02 \x01(\tB\x04\xa0\xb5\x18PR\x05title:\v\x92\xb5\x18\arecords:A\n" +
+	"\fschema_owner\x12\x1c.google.protobuf.FileOptions\x18ц\x03 \x01(\tR\vschemaOwner:@\n" +
+	"\n" +
+	"table_name\x12\x1f.google.protobuf.MessageOptions\x18҆\x03 \x01(\tR\ttableName:9\n" +
+	"\aindexed\x12\x1d.google.protobuf.FieldOptions\x18ӆ\x03 \x01(\bR\aindexed:>\n" +
+	"\n" +
+	"max_length\x12\x1d.google.protobuf.FieldOptions\x18Ԇ\x03 \x01(\x05R\tmaxLengthB\n" +
+	"\x8a\xb5\x18\x06compatb\x06proto3"
+
+var (
+	file_options3_proto_rawDescOnce sync.Once
+	file_options3_proto_rawDescData []byte
+)
+
+func file_options3_proto_rawDescGZIP() []byte {
+	file_options3_proto_rawDescOnce.Do(func() {
+		file_options3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_options3_proto_rawDesc), len(file_options3_proto_rawDesc)))
+	})
+	return file_options3_proto_rawDescData
+}
+
+var file_options3_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_options3_proto_goTypes = []any{
+	(*Record)(nil),                      // 0: compat.options.Record
+	(*descriptorpb.FileOptions)(nil),    // 1: google.protobuf.FileOptions
+	(*descriptorpb.MessageOptions)(nil), // 2: google.protobuf.MessageOptions
+	(*descriptorpb.FieldOptions)(nil),   // 3: google.protobuf.FieldOptions
+}
+var file_options3_proto_depIdxs = []int32{
+	1, // 0: compat.options.schema_owner:extendee -> google.protobuf.FileOptions
+	2, // 1: compat.options.table_name:extendee -> google.protobuf.MessageOptions
+	3, // 2: compat.options.indexed:extendee -> google.protobuf.FieldOptions
+	3, // 3: compat.options.max_length:extendee -> google.protobuf.FieldOptions
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	0, // [0:4] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_options3_proto_init() }
+func file_options3_proto_init() {
+	if File_options3_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_options3_proto_rawDesc), len(file_options3_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 4,
+			NumServices:   0,
+		},
+		GoTypes:           file_options3_proto_goTypes,
+		DependencyIndexes: file_options3_proto_depIdxs,
+		MessageInfos:      file_options3_proto_msgTypes,
+		ExtensionInfos:    file_options3_proto_extTypes,
+	}.Build()
+	File_options3_proto = out.File
+	file_options3_proto_goTypes = nil
+	file_options3_proto_depIdxs = nil
+}
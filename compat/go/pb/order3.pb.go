@@ -0,0 +1,309 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: order3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A long repeated message field whose elements are often byte-identical to
+// their neighbours and otherwise differ in one field two levels down. A
+// decoder that deduplicates, pools or reorders elements, or shares one
+// element's storage with another, loses elements or their order here.
+type OrderList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*OrderItem           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderList) Reset() {
+	*x = OrderList{}
+	mi := &file_order3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderList) ProtoMessage() {}
+
+func (x *OrderList) ProtoReflect() protoreflect.Message {
+	mi := &file_order3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderList.ProtoReflect.Descriptor instead.
+func (*OrderList) Descriptor() ([]byte, []int) {
+	return file_order3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OrderList) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type OrderItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Values        []int32                `protobuf:"varint,2,rep,packed,name=values,proto3" json:"values,omitempty"`
+	Branch        *OrderBranch           `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderItem) Reset() {
+	*x = OrderItem{}
+	mi := &file_order3_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItem) ProtoMessage() {}
+
+func (x *OrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_order3_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
+func (*OrderItem) Descriptor() ([]byte, []int) {
+	return file_order3_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrderItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OrderItem) GetValues() []int32 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *OrderItem) GetBranch() *OrderBranch {
+	if x != nil {
+		return x.Branch
+	}
+	return nil
+}
+
+type OrderBranch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Blob          []byte                 `protobuf:"bytes,1,opt,name=blob,proto3" json:"blob,omitempty"`
+	Leaf          *OrderLeaf             `protobuf:"bytes,2,opt,name=leaf,proto3" json:"leaf,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderBranch) Reset() {
+	*x = OrderBranch{}
+	mi := &file_order3_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderBranch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderBranch) ProtoMessage() {}
+
+func (x *OrderBranch) ProtoReflect() protoreflect.Message {
+	mi := &file_order3_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderBranch.ProtoReflect.Descriptor instead.
+func (*OrderBranch) Descriptor() ([]byte, []int) {
+	return file_order3_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OrderBranch) GetBlob() []byte {
+	if x != nil {
+		return x.Blob
+	}
+	return nil
+}
+
+func (x *OrderBranch) GetLeaf() *OrderLeaf {
+	if x != nil {
+		return x.Leaf
+	}
+	return nil
+}
+
+type OrderLeaf struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Label string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	// The only field that varies between elements that are not identical.
+	Mark          int64 `protobuf:"varint,2,opt,name=mark,proto3" json:"mark,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderLeaf) Reset() {
+	*x = OrderLeaf{}
+	mi := &file_order3_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderLeaf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderLeaf) ProtoMessage() {}
+
+func (x *OrderLeaf) ProtoReflect() protoreflect.Message {
+	mi := &file_order3_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderLeaf.ProtoReflect.Descriptor instead.
+func (*OrderLeaf) Descriptor() ([]byte, []int) {
+	return file_order3_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OrderLeaf) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *OrderLeaf) GetMark() int64 {
+	if x != nil {
+		return x.Mark
+	}
+	return 0
+}
+
+var File_order3_proto protoreflect.FileDescriptor
+
+const file_order3_proto_rawDesc = "" +
+	"\n" +
+	"\forder3.proto\"-\n" +
+	"\tOrderList\x12 \n" +
+	"\x05items\x18\x01 \x03(\v2\n" +
+	".OrderItemR\x05items\"]\n" +
+	"\tOrderItem\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06values\x18\x02 \x03(\x05R\x06values\x12$\n" +
+	"\x06branch\x18\x03 \x01(\v2\f.OrderBranchR\x06branch\"A\n" +
+	"\vOrderBranch\x12\x12\n" +
+	"\x04blob\x18\x01 \x01(\fR\x04blob\x12\x1e\n" +
+	"\x04leaf\x18\x02 \x01(\v2\n" +
+	".OrderLeafR\x04leaf\"5\n" +
+	"\tOrderLeaf\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x12\x12\n" +
+	"\x04mark\x18\x02 \x01(\x03R\x04markb\x06proto3"
+
+var (
+	file_order3_proto_rawDescOnce sync.Once
+	file_order3_proto_rawDescData []byte
+)
+
+func file_order3_proto_rawDescGZIP() []byte {
+	file_order3_proto_rawDescOnce.Do(func() {
+		file_order3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_order3_proto_rawDesc), len(file_order3_proto_rawDesc)))
+	})
+	return file_order3_proto_rawDescData
+}
+
+var file_order3_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_order3_proto_goTypes = []any{
+	(*OrderList)(nil),   // 0: OrderList
+	(*OrderItem)(nil),   // 1: OrderItem
+	(*OrderBranch)(nil), // 2: OrderBranch
+	(*OrderLeaf)(nil),   // 3: OrderLeaf
+}
+var file_order3_proto_depIdxs = []int32{
+	1, // 0: OrderList.items:type_name -> OrderItem
+	2, // 1: OrderItem.branch:type_name -> OrderBranch
+	3, // 2: OrderBranch.leaf:type_name -> OrderLeaf
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_order3_proto_init() }
+func file_order3_proto_init() {
+	if File_order3_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_order3_proto_rawDesc), len(file_order3_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_order3_proto_goTypes,
+		DependencyIndexes: file_order3_proto_depIdxs,
+		MessageInfos:      file_order3_proto_msgTypes,
+	}.Build()
+	File_order3_proto = out.File
+	file_order3_proto_goTypes = nil
+	file_order3_proto_depIdxs = nil
+}
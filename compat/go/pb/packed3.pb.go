@@ -0,0 +1,215 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: packed3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PackedScalars struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FFixed32      []uint32               `protobuf:"fixed32,1,rep,packed,name=f_fixed32,json=fFixed32,proto3" json:"f_fixed32,omitempty"`
+	FFixed64      []uint64               `protobuf:"fixed64,2,rep,packed,name=f_fixed64,json=fFixed64,proto3" json:"f_fixed64,omitempty"`
+	FSfixed32     []int32                `protobuf:"fixed32,3,rep,packed,name=f_sfixed32,json=fSfixed32,proto3" json:"f_sfixed32,omitempty"`
+	FSfixed64     []int64                `protobuf:"fixed64,4,rep,packed,name=f_sfixed64,json=fSfixed64,proto3" json:"f_sfixed64,omitempty"`
+	FUint32       []uint32               `protobuf:"varint,5,rep,packed,name=f_uint32,json=fUint32,proto3" json:"f_uint32,omitempty"`
+	FUint64       []uint64               `protobuf:"varint,6,rep,packed,name=f_uint64,json=fUint64,proto3" json:"f_uint64,omitempty"`
+	FSint32       []int32                `protobuf:"zigzag32,7,rep,packed,name=f_sint32,json=fSint32,proto3" json:"f_sint32,omitempty"`
+	FSint64       []int64                `protobuf:"zigzag64,8,rep,packed,name=f_sint64,json=fSint64,proto3" json:"f_sint64,omitempty"`
+	FFloat        []float32              `protobuf:"fixed32,9,rep,packed,name=f_float,json=fFloat,proto3" json:"f_float,omitempty"`
+	FInt32        []int32                `protobuf:"varint,10,rep,packed,name=f_int32,json=fInt32,proto3" json:"f_int32,omitempty"`
+	FInt64        []int64                `protobuf:"varint,11,rep,packed,name=f_int64,json=fInt64,proto3" json:"f_int64,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PackedScalars) Reset() {
+	*x = PackedScalars{}
+	mi := &file_packed3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PackedScalars) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackedScalars) ProtoMessage() {}
+
+func (x *PackedScalars) ProtoReflect() protoreflect.Message {
+	mi := &file_packed3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackedScalars.ProtoReflect.Descriptor instead.
+func (*PackedScalars) Descriptor() ([]byte, []int) {
+	return file_packed3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PackedScalars) GetFFixed32() []uint32 {
+	if x != nil {
+		return x.FFixed32
+	}
+	return nil
+}
+
+func (x *PackedScalars) GetFFixed64() []uint64 {
+	if x != nil {
+		return x.FFixed64
+	}
+	return nil
+}
+
+func (x *PackedScalars) GetFSfixed32() []int32 {
+	if x != nil {
+		return x.FSfixed32
+	}
+	return nil
+}
+
+func (x *PackedScalars) GetFSfixed64() []int64 {
+	if x != nil {
+		return x.FSfixed64
+	}
+	return nil
+}
+
+func (x *PackedScalars) GetFUint32() []uint32 {
+	if x != nil {
+		return x.FUint32
+	}
+	return nil
+}
+
+func (x *PackedScalars) GetFUint64() []uint64 {
+	if x != nil {
+		return x.FUint64
+	}
+	return nil
+}
+
+func (x *PackedScalars) GetFSint32() []int32 {
+	if x != nil {
+		return x.FSint32
+	}
+	return nil
+}
+
+func (x *PackedScalars) GetFSint64() []int64 {
+	if x != nil {
+		return x.FSint64
+	}
+	return nil
+}
+
+func (x *PackedScalars) GetFFloat() []float32 {
+	if x != nil {
+		return x.FFloat
+	}
+	return nil
+}
+
+func (x *PackedScalars) GetFInt32() []int32 {
+	if x != nil {
+		return x.FInt32
+	}
+	return nil
+}
+
+func (x *PackedScalars) GetFInt64() []int64 {
+	if x != nil {
+		return x.FInt64
+	}
+	return nil
+}
+
+var File_packed3_proto protoreflect.FileDescriptor
+
+const file_packed3_proto_rawDesc = "" +
+	"\n" +
+	"\rpacked3.proto\"\xbe\x02\n" +
+	"\rPackedScalars\x12\x1b\n" +
+	"\tf_fixed32\x18\x01 \x03(\aR\bfFixed32\x12\x1b\n" +
+	"\tf_fixed64\x18\x02 \x03(\x06R\bfFixed64\x12\x1d\n" +
+	"\n" +
+	"f_sfixed32\x18\x03 \x03(\x0fR\tfSfixed32\x12\x1d\n" +
+	"\n" +
+	"f_sfixed64\x18\x04 \x03(\x10R\tfSfixed64\x12\x19\n" +
+	"\bf_uint32\x18\x05 \x03(\rR\afUint32\x12\x19\n" +
+	"\bf_uint64\x18\x06 \x03(\x04R\afUint64\x12\x19\n" +
+	"\bf_sint32\x18\a \x03(\x11R\afSint32\x12\x19\n" +
+	"\bf_sint64\x18\b \x03(\x12R\afSint64\x12\x17\n" +
+	"\af_float\x18\t \x03(\x02R\x06fFloat\x12\x17\n" +
+	"\af_int32\x18\n" +
+	" \x03(\x05R\x06fInt32\x12\x17\n" +
+	"\af_int64\x18\v \x03(\x03R\x06fInt64b\x06proto3"
+
+var (
+	file_packed3_proto_rawDescOnce sync.Once
+	file_packed3_proto_rawDescData []byte
+)
+
+func file_packed3_proto_rawDescGZIP() []byte {
+	file_packed3_proto_rawDescOnce.Do(func() {
+		file_packed3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_packed3_proto_rawDesc), len(file_packed3_proto_rawDesc)))
+	})
+	return file_packed3_proto_rawDescData
+}
+
+var file_packed3_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_packed3_proto_goTypes = []any{
+	(*PackedScalars)(nil), // 0: PackedScalars
+}
+var file_packed3_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_packed3_proto_init() }
+func file_packed3_proto_init() {
+	if File_packed3_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_packed3_proto_rawDesc), len(file_packed3_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_packed3_proto_goTypes,
+		DependencyIndexes: file_packed3_proto_depIdxs,
+		MessageInfos:      file_packed3_proto_msgTypes,
+	}.Build()
+	File_packed3_proto = out.File
+	file_packed3_proto_goTypes = nil
+	file_packed3_proto_depIdxs = nil
+}
@@ -0,0 +1,702 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: presence3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PresenceEnum int32
+
+const (
+	PresenceEnum_PRESENCE_ENUM_ZERO PresenceEnum = 0
+	PresenceEnum_PRESENCE_ENUM_ONE  PresenceEnum = 1
+)
+
+// Enum value maps for PresenceEnum.
+var (
+	PresenceEnum_name = map[int32]string{
+		0: "PRESENCE_ENUM_ZERO",
+		1: "PRESENCE_ENUM_ONE",
+	}
+	PresenceEnum_value = map[string]int32{
+		"PRESENCE_ENUM_ZERO": 0,
+		"PRESENCE_ENUM_ONE":  1,
+	}
+)
+
+func (x PresenceEnum) Enum() *PresenceEnum {
+	p := new(PresenceEnum)
+	*p = x
+	return p
+}
+
+func (x PresenceEnum) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PresenceEnum) Descriptor() protoreflect.EnumDescriptor {
+	return file_presence3_proto_enumTypes[0].Descriptor()
+}
+
+func (PresenceEnum) Type() protoreflect.EnumType {
+	return &file_presence3_proto_enumTypes[0]
+}
+
+func (x PresenceEnum) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PresenceEnum.Descriptor instead.
+func (PresenceEnum) EnumDescriptor() ([]byte, []int) {
+	return file_presence3_proto_rawDescGZIP(), []int{0}
+}
+
+// The same field shapes three times: with implicit presence, where a scalar
+// holding its zero value is not written; as explicit optional fields; and as
+// the only member of a oneof. In the last two a zero value that was set is
+// written, and reads back as set. A message field has presence either way.
+type PresenceMatrix struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	IInt32   int32                  `protobuf:"varint,1,opt,name=i_int32,json=iInt32,proto3" json:"i_int32,omitempty"`
+	ISint64  int64                  `protobuf:"zigzag64,2,opt,name=i_sint64,json=iSint64,proto3" json:"i_sint64,omitempty"`
+	IFixed32 uint32                 `protobuf:"fixed32,3,opt,name=i_fixed32,json=iFixed32,proto3" json:"i_fixed32,omitempty"`
+	IDouble  float64                `protobuf:"fixed64,4,opt,name=i_double,json=iDouble,proto3" json:"i_double,omitempty"`
+	IBool    bool                   `protobuf:"varint,5,opt,name=i_bool,json=iBool,proto3" json:"i_bool,omitempty"`
+	IString  string                 `protobuf:"bytes,6,opt,name=i_string,json=iString,proto3" json:"i_string,omitempty"`
+	IBytes   []byte                 `protobuf:"bytes,7,opt,name=i_bytes,json=iBytes,proto3" json:"i_bytes,omitempty"`
+	IEnum    PresenceEnum           `protobuf:"varint,8,opt,name=i_enum,json=iEnum,proto3,enum=PresenceEnum" json:"i_enum,omitempty"`
+	IMessage *PresenceChild         `protobuf:"bytes,9,opt,name=i_message,json=iMessage,proto3" json:"i_message,omitempty"`
+	OInt32   *int32                 `protobuf:"varint,11,opt,name=o_int32,json=oInt32,proto3,oneof" json:"o_int32,omitempty"`
+	OSint64  *int64                 `protobuf:"zigzag64,12,opt,name=o_sint64,json=oSint64,proto3,oneof" json:"o_sint64,omitempty"`
+	OFixed32 *uint32                `protobuf:"fixed32,13,opt,name=o_fixed32,json=oFixed32,proto3,oneof" json:"o_fixed32,omitempty"`
+	ODouble  *float64               `protobuf:"fixed64,14,opt,name=o_double,json=oDouble,proto3,oneof" json:"o_double,omitempty"`
+	OBool    *bool                  `protobuf:"varint,15,opt,name=o_bool,json=oBool,proto3,oneof" json:"o_bool,omitempty"`
+	OString  *string                `protobuf:"bytes,16,opt,name=o_string,json=oString,proto3,oneof" json:"o_string,omitempty"`
+	OBytes   []byte                 `protobuf:"bytes,17,opt,name=o_bytes,json=oBytes,proto3,oneof" json:"o_bytes,omitempty"`
+	OEnum    *PresenceEnum          `protobuf:"varint,18,opt,name=o_enum,json=oEnum,proto3,enum=PresenceEnum,oneof" json:"o_enum,omitempty"`
+	OMessage *PresenceChild         `protobuf:"bytes,19,opt,name=o_message,json=oMessage,proto3,oneof" json:"o_message,omitempty"`
+	// Types that are valid to be assigned to WInt32:
+	//
+	//	*PresenceMatrix_WInt32Value
+	WInt32 isPresenceMatrix_WInt32 `protobuf_oneof:"w_int32"`
+	// Types that are valid to be assigned to WSint64:
+	//
+	//	*PresenceMatrix_WSint64Value
+	WSint64 isPresenceMatrix_WSint64 `protobuf_oneof:"w_sint64"`
+	// Types that are valid to be assigned to WFixed32:
+	//
+	//	*PresenceMatrix_WFixed32Value
+	WFixed32 isPresenceMatrix_WFixed32 `protobuf_oneof:"w_fixed32"`
+	// Types that are valid to be assigned to WDouble:
+	//
+	//	*PresenceMatrix_WDoubleValue
+	WDouble isPresenceMatrix_WDouble `protobuf_oneof:"w_double"`
+	// Types that are valid to be assigned to WBool:
+	//
+	//	*PresenceMatrix_WBoolValue
+	WBool isPresenceMatrix_WBool `protobuf_oneof:"w_bool"`
+	// Types that are valid to be assigned to WString:
+	//
+	//	*PresenceMatrix_WStringValue
+	WString isPresenceMatrix_WString `protobuf_oneof:"w_string"`
+	// Types that are valid to be assigned to WBytes:
+	//
+	//	*PresenceMatrix_WBytesValue
+	WBytes isPresenceMatrix_WBytes `protobuf_oneof:"w_bytes"`
+	// Types that are valid to be assigned to WEnum:
+	//
+	//	*PresenceMatrix_WEnumValue
+	WEnum isPresenceMatrix_WEnum `protobuf_oneof:"w_enum"`
+	// Types that are valid to be assigned to WMessage:
+	//
+	//	*PresenceMatrix_WMessageValue
+	WMessage      isPresenceMatrix_WMessage `protobuf_oneof:"w_message"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PresenceMatrix) Reset() {
+	*x = PresenceMatrix{}
+	mi := &file_presence3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PresenceMatrix) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PresenceMatrix) ProtoMessage() {}
+
+func (x *PresenceMatrix) ProtoReflect() protoreflect.Message {
+	mi := &file_presence3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PresenceMatrix.ProtoReflect.Descriptor instead.
+func (*PresenceMatrix) Descriptor() ([]byte, []int) {
+	return file_presence3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PresenceMatrix) GetIInt32() int32 {
+	if x != nil {
+		return x.IInt32
+	}
+	return 0
+}
+
+func (x *PresenceMatrix) GetISint64() int64 {
+	if x != nil {
+		return x.ISint64
+	}
+	return 0
+}
+
+func (x *PresenceMatrix) GetIFixed32() uint32 {
+	if x != nil {
+		return x.IFixed32
+	}
+	return 0
+}
+
+func (x *PresenceMatrix) GetIDouble() float64 {
+	if x != nil {
+		return x.IDouble
+	}
+	return 0
+}
+
+func (x *PresenceMatrix) GetIBool() bool {
+	if x != nil {
+		return x.IBool
+	}
+	return false
+}
+
+func (x *PresenceMatrix) GetIString() string {
+	if x != nil {
+		return x.IString
+	}
+	return ""
+}
+
+func (x *PresenceMatrix) GetIBytes() []byte {
+	if x != nil {
+		return x.IBytes
+	}
+	return nil
+}
+
+func (x *PresenceMatrix) GetIEnum() PresenceEnum {
+	if x != nil {
+		return x.IEnum
+	}
+	return PresenceEnum_PRESENCE_ENUM_ZERO
+}
+
+func (x *PresenceMatrix) GetIMessage() *PresenceChild {
+	if x != nil {
+		return x.IMessage
+	}
+	return nil
+}
+
+func (x *PresenceMatrix) GetOInt32() int32 {
+	if x != nil && x.OInt32 != nil {
+		return *x.OInt32
+	}
+	return 0
+}
+
+func (x *PresenceMatrix) GetOSint64() int64 {
+	if x != nil && x.OSint64 != nil {
+		return *x.OSint64
+	}
+	return 0
+}
+
+func (x *PresenceMatrix) GetOFixed32() uint32 {
+	if x != nil && x.OFixed32 != nil {
+		return *x.OFixed32
+	}
+	return 0
+}
+
+func (x *PresenceMatrix) GetODouble() float64 {
+	if x != nil && x.ODouble != nil {
+		return *x.ODouble
+	}
+	return 0
+}
+
+func (x *PresenceMatrix) GetOBool() bool {
+	if x != nil && x.OBool != nil {
+		return *x.OBool
+	}
+	return false
+}
+
+func (x *PresenceMatrix) GetOString() string {
+	if x != nil && x.OString != nil {
+		return *x.OString
+	}
+	return ""
+}
+
+func (x *PresenceMatrix) GetOBytes() []byte {
+	if x != nil {
+		return x.OBytes
+	}
+	return nil
+}
+
+func (x *PresenceMatrix) GetOEnum() PresenceEnum {
+	if x != nil && x.OEnum != nil {
+		return *x.OEnum
+	}
+	return PresenceEnum_PRESENCE_ENUM_ZERO
+}
+
+func (x *PresenceMatrix) GetOMessage() *PresenceChild {
+	if x != nil {
+		return x.OMessage
+	}
+	return nil
+}
+
+func (x *PresenceMatrix) GetWInt32() isPresenceMatrix_WInt32 {
+	if x != nil {
+		return x.WInt32
+	}
+	return nil
+}
+
+func (x *PresenceMatrix) GetWInt32Value() int32 {
+	if x != nil {
+		if x, ok := x.WInt32.(*PresenceMatrix_WInt32Value); ok {
+			return x.WInt32Value
+		}
+	}
+	return 0
+}
+
+func (x *PresenceMatrix) GetWSint64() isPresenceMatrix_WSint64 {
+	if x != nil {
+		return x.WSint64
+	}
+	return nil
+}
+
+func (x *PresenceMatrix) GetWSint64Value() int64 {
+	if x != nil {
+		if x, ok := x.WSint64.(*PresenceMatrix_WSint64Value); ok {
+			return x.WSint64Value
+		}
+	}
+	return 0
+}
+
+func (x *PresenceMatrix) GetWFixed32() isPresenceMatrix_WFixed32 {
+	if x != nil {
+		return x.WFixed32
+	}
+	return nil
+}
+
+func (x *PresenceMatrix) GetWFixed32Value() uint32 {
+	if x != nil {
+		if x, ok := x.WFixed32.(*PresenceMatrix_WFixed32Value); ok {
+			return x.WFixed32Value
+		}
+	}
+	return 0
+}
+
+func (x *PresenceMatrix) GetWDouble() isPresenceMatrix_WDouble {
+	if x != nil {
+		return x.WDouble
+	}
+	return nil
+}
+
+func (x *PresenceMatrix) GetWDoubleValue() float64 {
+	if x != nil {
+		if x, ok := x.WDouble.(*PresenceMatrix_WDoubleValue); ok {
+			return x.WDoubleValue
+		}
+	}
+	return 0
+}
+
+func (x *PresenceMatrix) GetWBool() isPresenceMatrix_WBool {
+	if x != nil {
+		return x.WBool
+	}
+	return nil
+}
+
+func (x *PresenceMatrix) GetWBoolValue() bool {
+	if x != nil {
+		if x, ok := x.WBool.(*PresenceMatrix_WBoolValue); ok {
+			return x.WBoolValue
+		}
+	}
+	return false
+}
+
+func (x *PresenceMatrix) GetWString() isPresenceMatrix_WString {
+	if x != nil {
+		return x.WString
+	}
+	return nil
+}
+
+func (x *PresenceMatrix) GetWStringValue() string {
+	if x != nil {
+		if x, ok := x.WString.(*PresenceMatrix_WStringValue); ok {
+			return x.WStringValue
+		}
+	}
+	return ""
+}
+
+func (x *PresenceMatrix) GetWBytes() isPresenceMatrix_WBytes {
+	if x != nil {
+		return x.WBytes
+	}
+	return nil
+}
+
+func (x *PresenceMatrix) GetWBytesValue() []byte {
+	if x != nil {
+		if x, ok := x.WBytes.(*PresenceMatrix_WBytesValue); ok {
+			return x.WBytesValue
+		}
+	}
+	return nil
+}
+
+func (x *PresenceMatrix) GetWEnum() isPresenceMatrix_WEnum {
+	if x != nil {
+		return x.WEnum
+	}
+	return nil
+}
+
+func (x *PresenceMatrix) GetWEnumValue() PresenceEnum {
+	if x != nil {
+		if x, ok := x.WEnum.(*PresenceMatrix_WEnumValue); ok {
+			return x.WEnumValue
+		}
+	}
+	return PresenceEnum_PRESENCE_ENUM_ZERO
+}
+
+func (x *PresenceMatrix) GetWMessage() isPresenceMatrix_WMessage {
+	if x != nil {
+		return x.WMessage
+	}
+	return nil
+}
+
+func (x *PresenceMatrix) GetWMessageValue() *PresenceChild {
+	if x != nil {
+		if x, ok := x.WMessage.(*PresenceMatrix_WMessageValue); ok {
+			return x.WMessageValue
+		}
+	}
+	return nil
+}
+
+type isPresenceMatrix_WInt32 interface {
+	isPresenceMatrix_WInt32()
+}
+
+type PresenceMatrix_WInt32Value struct {
+	WInt32Value int32 `protobuf:"varint,21,opt,name=w_int32_value,json=wInt32Value,proto3,oneof"`
+}
+
+func (*PresenceMatrix_WInt32Value) isPresenceMatrix_WInt32() {}
+
+type isPresenceMatrix_WSint64 interface {
+	isPresenceMatrix_WSint64()
+}
+
+type PresenceMatrix_WSint64Value struct {
+	WSint64Value int64 `protobuf:"zigzag64,22,opt,name=w_sint64_value,json=wSint64Value,proto3,oneof"`
+}
+
+func (*PresenceMatrix_WSint64Value) isPresenceMatrix_WSint64() {}
+
+type isPresenceMatrix_WFixed32 interface {
+	isPresenceMatrix_WFixed32()
+}
+
+type PresenceMatrix_WFixed32Value struct {
+	WFixed32Value uint32 `protobuf:"fixed32,23,opt,name=w_fixed32_value,json=wFixed32Value,proto3,oneof"`
+}
+
+func (*PresenceMatrix_WFixed32Value) isPresenceMatrix_WFixed32() {}
+
+type isPresenceMatrix_WDouble interface {
+	isPresenceMatrix_WDouble()
+}
+
+type PresenceMatrix_WDoubleValue struct {
+	WDoubleValue float64 `protobuf:"fixed64,24,opt,name=w_double_value,json=wDoubleValue,proto3,oneof"`
+}
+
+func (*PresenceMatrix_WDoubleValue) isPresenceMatrix_WDouble() {}
+
+type isPresenceMatrix_WBool interface {
+	isPresenceMatrix_WBool()
+}
+
+type PresenceMatrix_WBoolValue struct {
+	WBoolValue bool `protobuf:"varint,25,opt,name=w_bool_value,json=wBoolValue,proto3,oneof"`
+}
+
+func (*PresenceMatrix_WBoolValue) isPresenceMatrix_WBool() {}
+
+type isPresenceMatrix_WString interface {
+	isPresenceMatrix_WString()
+}
+
+type PresenceMatrix_WStringValue struct {
+	WStringValue string `protobuf:"bytes,26,opt,name=w_string_value,json=wStringValue,proto3,oneof"`
+}
+
+func (*PresenceMatrix_WStringValue) isPresenceMatrix_WString() {}
+
+type isPresenceMatrix_WBytes interface {
+	isPresenceMatrix_WBytes()
+}
+
+type PresenceMatrix_WBytesValue struct {
+	WBytesValue []byte `protobuf:"bytes,27,opt,name=w_bytes_value,json=wBytesValue,proto3,oneof"`
+}
+
+func (*PresenceMatrix_WBytesValue) isPresenceMatrix_WBytes() {}
+
+type isPresenceMatrix_WEnum interface {
+	isPresenceMatrix_WEnum()
+}
+
+type PresenceMatrix_WEnumValue struct {
+	WEnumValue PresenceEnum `protobuf:"varint,28,opt,name=w_enum_value,json=wEnumValue,proto3,enum=PresenceEnum,oneof"`
+}
+
+func (*PresenceMatrix_WEnumValue) isPresenceMatrix_WEnum() {}
+
+type isPresenceMatrix_WMessage interface {
+	isPresenceMatrix_WMessage()
+}
+
+type PresenceMatrix_WMessageValue struct {
+	WMessageValue *PresenceChild `protobuf:"bytes,29,opt,name=w_message_value,json=wMessageValue,proto3,oneof"`
+}
+
+func (*PresenceMatrix_WMessageValue) isPresenceMatrix_WMessage() {}
+
+type PresenceChild struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         int32                  `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PresenceChild) Reset() {
+	*x = PresenceChild{}
+	mi := &file_presence3_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PresenceChild) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PresenceChild) ProtoMessage() {}
+
+func (x *PresenceChild) ProtoReflect() protoreflect.Message {
+	mi := &file_presence3_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PresenceChild.ProtoReflect.Descriptor instead.
+func (*PresenceChild) Descriptor() ([]byte, []int) {
+	return file_presence3_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PresenceChild) GetValue() int32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+var File_presence3_proto protoreflect.FileDescriptor
+
+const file_presence3_proto_rawDesc = "" +
+	"\n" +
+	"\x0fpresence3.proto\"\xa9\t\n" +
+	"\x0ePresenceMatrix\x12\x17\n" +
+	"\ai_int32\x18\x01 \x01(\x05R\x06iInt32\x12\x19\n" +
+	"\bi_sint64\x18\x02 \x01(\x12R\aiSint64\x12\x1b\n" +
+	"\ti_fixed32\x18\x03 \x01(\aR\biFixed32\x12\x19\n" +
+	"\bi_double\x18\x04 \x01(\x01R\aiDouble\x12\x15\n" +
+	"\x06i_bool\x18\x05 \x01(\bR\x05iBool\x12\x19\n" +
+	"\bi_string\x18\x06 \x01(\tR\aiString\x12\x17\n" +
+	"\ai_bytes\x18\a \x01(\fR\x06iBytes\x12$\n" +
+	"\x06i_enum\x18\b \x01(\x0e2\r.PresenceEnumR\x05iEnum\x12+\n" +
+	"\ti_message\x18\t \x01(\v2\x0e.PresenceChildR\biMessage\x12\x1c\n" +
+	"\ao_int32\x18\v \x01(\x05H\tR\x06oInt32\x88\x01\x01\x12\x1e\n" +
+	"\bo_sint64\x18\f \x01(\x12H\n" +
+	"R\aoSint64\x88\x01\x01\x12 \n" +
+	"\to_fixed32\x18\r \x01(\aH\vR\boFixed32\x88\x01\x01\x12\x1e\n" +
+	"\bo_double\x18\x0e \x01(\x01H\fR\aoDouble\x88\x01\x01\x12\x1a\n" +
+	"\x06o_bool\x18\x0f \x01(\bH\rR\x05oBool\x88\x01\x01\x12\x1e\n" +
+	"\bo_string\x18\x10 \x01(\tH\x0eR\aoString\x88\x01\x01\x12\x1c\n" +
+	"\ao_bytes\x18\x11 \x01(\fH\x0fR\x06oBytes\x88\x01\x01\x12)\n" +
+	"\x06o_enum\x18\x12 \x01(\x0e2\r.PresenceEnumH\x10R\x05oEnum\x88\x01\x01\x120\n" +
+	"\to_message\x18\x13 \x01(\v2\x0e.PresenceChildH\x11R\boMessage\x88\x01\x01\x12$\n" +
+	"\rw_int32_value\x18\x15 \x01(\x05H\x00R\vwInt32Value\x12&\n" +
+	"\x0ew_sint64_value\x18\x16 \x01(\x12H\x01R\fwSint64Value\x12(\n" +
+	"\x0fw_fixed32_value\x18\x17 \x01(\aH\x02R\rwFixed32Value\x12&\n" +
+	"\x0ew_double_value\x18\x18 \x01(\x01H\x03R\fwDoubleValue\x12\"\n" +
+	"\fw_bool_value\x18\x19 \x01(\bH\x04R\n" +
+	"wBoolValue\x12&\n" +
+	"\x0ew_string_value\x18\x1a \x01(\tH\x05R\fwStringValue\x12$\n" +
+	"\rw_bytes_value\x18\x1b \x01(\fH\x06R\vwBytesValue\x121\n" +
+	"\fw_enum_value\x18\x1c \x01(\x0e2\r.PresenceEnumH\aR\n" +
+	"wEnumValue\x128\n" +
+	"\x0fw_message_value\x18\x1d \x01(\v2\x0e.PresenceChildH\bR\rwMessageValueB\t\n" +
+	"\aw_int32B\n" +
+	"\n" +
+	"\bw_sint64B\v\n" +
+	"\tw_fixed32B\n" +
+	"\n" +
+	"\bw_doubleB\b\n" +
+	"\x06w_boolB\n" +
+	"\n" +
+	"\bw_stringB\t\n" +
+	"\aw_bytesB\b\n" +
+	"\x06w_enumB\v\n" +
+	"\tw_messageB\n" +
+	"\n" +
+	"\b_o_int32B\v\n" +
+	"\t_o_sint64B\f\n" +
+	"\n" +
+	"_o_fixed32B\v\n" +
+	"\t_o_doubleB\t\n" +
+	"\a_o_boolB\v\n" +
+	"\t_o_stringB\n" +
+	"\n" +
+	"\b_o_bytesB\t\n" +
+	"\a_o_enumB\f\n" +
+	"\n" +
+	"_o_message\"%\n" +
+	"\rPresenceChild\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x05R\x05value*=\n" +
+	"\fPresenceEnum\x12\x16\n" +
+	"\x12PRESENCE_ENUM_ZERO\x10\x00\x12\x15\n" +
+	"\x11PRESENCE_ENUM_ONE\x10\x01b\x06proto3"
+
+var (
+	file_presence3_proto_rawDescOnce sync.Once
+	file_presence3_proto_rawDescData []byte
+)
+
+func file_presence3_proto_rawDescGZIP() []byte {
+	file_presence3_proto_rawDescOnce.Do(func() {
+		file_presence3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_presence3_proto_rawDesc), len(file_presence3_proto_rawDesc)))
+	})
+	return file_presence3_proto_rawDescData
+}
+
+var file_presence3_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_presence3_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_presence3_proto_goTypes = []any{
+	(PresenceEnum)(0),      // 0: PresenceEnum
+	(*PresenceMatrix)(nil), // 1: PresenceMatrix
+	(*PresenceChild)(nil),  // 2: PresenceChild
+}
+var file_presence3_proto_depIdxs = []int32{
+	0, // 0: PresenceMatrix.i_enum:type_name -> PresenceEnum
+	2, // 1: PresenceMatrix.i_message:type_name -> PresenceChild
+	0, // 2: PresenceMatrix.o_enum:type_name -> PresenceEnum
+	2, // 3: PresenceMatrix.o_message:type_name -> PresenceChild
+	0, // 4: PresenceMatrix.w_enum_value:type_name -> PresenceEnum
+	2, // 5: PresenceMatrix.w_message_value:type_name -> PresenceChild
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_presence3_proto_init() }
+func file_presence3_proto_init() {
+	if File_presence3_proto != nil {
+		return
+	}
+	file_presence3_proto_msgTypes[0].OneofWrappers = []any{
+		(*PresenceMatrix_WInt32Value)(nil),
+		(*PresenceMatrix_WSint64Value)(nil),
+		(*PresenceMatrix_WFixed32Value)(nil),
+		(*PresenceMatrix_WDoubleValue)(nil),
+		(*PresenceMatrix_WBoolValue)(nil),
+		(*PresenceMatrix_WStringValue)(nil),
+		(*PresenceMatrix_WBytesValue)(nil),
+		(*PresenceMatrix_WEnumValue)(nil),
+		(*PresenceMatrix_WMessageValue)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_presence3_proto_rawDesc), len(file_presence3_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_presence3_proto_goTypes,
+		DependencyIndexes: file_presence3_proto_depIdxs,
+		EnumInfos:         file_presence3_proto_enumTypes,
+		MessageInfos:      file_presence3_proto_msgTypes,
+	}.Build()
+	File_presence3_proto = out.File
+	file_presence3_proto_goTypes = nil
+	file_presence3_proto_depIdxs = nil
+}
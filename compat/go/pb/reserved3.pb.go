@@ -0,0 +1,143 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: reserved3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A message some fields were deleted from. Their numbers and names are
+// reserved so they cannot be reused, but old writers may still send data on
+// those numbers, which readers must keep as unknown fields.
+type ReservedMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Label         string                 `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	Values        []int32                `protobuf:"varint,4,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReservedMessage) Reset() {
+	*x = ReservedMessage{}
+	mi := &file_reserved3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReservedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReservedMessage) ProtoMessage() {}
+
+func (x *ReservedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_reserved3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReservedMessage.ProtoReflect.Descriptor instead.
+func (*ReservedMessage) Descriptor() ([]byte, []int) {
+	return file_reserved3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ReservedMessage) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReservedMessage) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *ReservedMessage) GetValues() []int32 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+var File_reserved3_proto protoreflect.FileDescriptor
+
+const file_reserved3_proto_rawDesc = "" +
+	"\n" +
+	"\x0freserved3.proto\"~\n" +
+	"\x0fReservedMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x05label\x18\x03 \x01(\tR\x05label\x12\x16\n" +
+	"\x06values\x18\x04 \x03(\x05R\x06valuesJ\x04\b\x02\x10\x03J\x04\b\x05\x10\bJ\t\b\xe8\a\x10\x80\x80\x80\x80\x02R\vlegacy_nameR\told_countb\x06proto3"
+
+var (
+	file_reserved3_proto_rawDescOnce sync.Once
+	file_reserved3_proto_rawDescData []byte
+)
+
+func file_reserved3_proto_rawDescGZIP() []byte {
+	file_reserved3_proto_rawDescOnce.Do(func() {
+		file_reserved3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_reserved3_proto_rawDesc), len(file_reserved3_proto_rawDesc)))
+	})
+	return file_reserved3_proto_rawDescData
+}
+
+var file_reserved3_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_reserved3_proto_goTypes = []any{
+	(*ReservedMessage)(nil), // 0: ReservedMessage
+}
+var file_reserved3_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_reserved3_proto_init() }
+func file_reserved3_proto_init() {
+	if File_reserved3_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reserved3_proto_rawDesc), len(file_reserved3_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_reserved3_proto_goTypes,
+		DependencyIndexes: file_reserved3_proto_depIdxs,
+		MessageInfos:      file_reserved3_proto_msgTypes,
+	}.Build()
+	File_reserved3_proto = out.File
+	file_reserved3_proto_goTypes = nil
+	file_reserved3_proto_depIdxs = nil
+}
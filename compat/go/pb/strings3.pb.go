@@ -0,0 +1,145 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: strings3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Strings whose content a C-string or text-handling API would mangle: NULs,
+// control characters, four-byte and combining sequences and megabyte-long
+// runs. Each case holds the same content as text and as raw bytes, so a
+// decoder treating the two differently is caught as well.
+type StringEdges struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Text  string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Raw   []byte                 `protobuf:"bytes,2,opt,name=raw,proto3" json:"raw,omitempty"`
+	// The content split into pieces, one per element.
+	Pieces        []string `protobuf:"bytes,3,rep,name=pieces,proto3" json:"pieces,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StringEdges) Reset() {
+	*x = StringEdges{}
+	mi := &file_strings3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StringEdges) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringEdges) ProtoMessage() {}
+
+func (x *StringEdges) ProtoReflect() protoreflect.Message {
+	mi := &file_strings3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringEdges.ProtoReflect.Descriptor instead.
+func (*StringEdges) Descriptor() ([]byte, []int) {
+	return file_strings3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StringEdges) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *StringEdges) GetRaw() []byte {
+	if x != nil {
+		return x.Raw
+	}
+	return nil
+}
+
+func (x *StringEdges) GetPieces() []string {
+	if x != nil {
+		return x.Pieces
+	}
+	return nil
+}
+
+var File_strings3_proto protoreflect.FileDescriptor
+
+const file_strings3_proto_rawDesc = "" +
+	"\n" +
+	"\x0estrings3.proto\"K\n" +
+	"\vStringEdges\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x10\n" +
+	"\x03raw\x18\x02 \x01(\fR\x03raw\x12\x16\n" +
+	"\x06pieces\x18\x03 \x03(\tR\x06piecesb\x06proto3"
+
+var (
+	file_strings3_proto_rawDescOnce sync.Once
+	file_strings3_proto_rawDescData []byte
+)
+
+func file_strings3_proto_rawDescGZIP() []byte {
+	file_strings3_proto_rawDescOnce.Do(func() {
+		file_strings3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_strings3_proto_rawDesc), len(file_strings3_proto_rawDesc)))
+	})
+	return file_strings3_proto_rawDescData
+}
+
+var file_strings3_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_strings3_proto_goTypes = []any{
+	(*StringEdges)(nil), // 0: StringEdges
+}
+var file_strings3_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_strings3_proto_init() }
+func file_strings3_proto_init() {
+	if File_strings3_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_strings3_proto_rawDesc), len(file_strings3_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_strings3_proto_goTypes,
+		DependencyIndexes: file_strings3_proto_depIdxs,
+		MessageInfos:      file_strings3_proto_msgTypes,
+	}.Build()
+	File_strings3_proto = out.File
+	file_strings3_proto_goTypes = nil
+	file_strings3_proto_depIdxs = nil
+}
@@ -0,0 +1,331 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: tags3.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Fields on both sides of every change in tag width. A tag takes one byte up
+// to field 15, two up to 2047, three up to 262143, four up to 33554431 and
+// five up to the largest field number, 536870911.
+type TagWidths struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The same field numbers again, as length-delimited fields.
+	Strings       *TagWidthStrings `protobuf:"bytes,1,opt,name=strings,proto3" json:"strings,omitempty"`
+	F15           uint64           `protobuf:"varint,15,opt,name=f15,proto3" json:"f15,omitempty"`
+	F16           uint64           `protobuf:"varint,16,opt,name=f16,proto3" json:"f16,omitempty"`
+	F2047         uint64           `protobuf:"varint,2047,opt,name=f2047,proto3" json:"f2047,omitempty"`
+	F2048         uint64           `protobuf:"varint,2048,opt,name=f2048,proto3" json:"f2048,omitempty"`
+	F262143       uint64           `protobuf:"varint,262143,opt,name=f262143,proto3" json:"f262143,omitempty"`
+	F262144       uint64           `protobuf:"varint,262144,opt,name=f262144,proto3" json:"f262144,omitempty"`
+	F33554431     uint64           `protobuf:"varint,33554431,opt,name=f33554431,proto3" json:"f33554431,omitempty"`
+	F33554432     uint64           `protobuf:"varint,33554432,opt,name=f33554432,proto3" json:"f33554432,omitempty"`
+	F536870911    uint64           `protobuf:"varint,536870911,opt,name=f536870911,proto3" json:"f536870911,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TagWidths) Reset() {
+	*x = TagWidths{}
+	mi := &file_tags3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TagWidths) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagWidths) ProtoMessage() {}
+
+func (x *TagWidths) ProtoReflect() protoreflect.Message {
+	mi := &file_tags3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagWidths.ProtoReflect.Descriptor instead.
+func (*TagWidths) Descriptor() ([]byte, []int) {
+	return file_tags3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TagWidths) GetStrings() *TagWidthStrings {
+	if x != nil {
+		return x.Strings
+	}
+	return nil
+}
+
+func (x *TagWidths) GetF15() uint64 {
+	if x != nil {
+		return x.F15
+	}
+	return 0
+}
+
+func (x *TagWidths) GetF16() uint64 {
+	if x != nil {
+		return x.F16
+	}
+	return 0
+}
+
+func (x *TagWidths) GetF2047() uint64 {
+	if x != nil {
+		return x.F2047
+	}
+	return 0
+}
+
+func (x *TagWidths) GetF2048() uint64 {
+	if x != nil {
+		return x.F2048
+	}
+	return 0
+}
+
+func (x *TagWidths) GetF262143() uint64 {
+	if x != nil {
+		return x.F262143
+	}
+	return 0
+}
+
+func (x *TagWidths) GetF262144() uint64 {
+	if x != nil {
+		return x.F262144
+	}
+	return 0
+}
+
+func (x *TagWidths) GetF33554431() uint64 {
+	if x != nil {
+		return x.F33554431
+	}
+	return 0
+}
+
+func (x *TagWidths) GetF33554432() uint64 {
+	if x != nil {
+		return x.F33554432
+	}
+	return 0
+}
+
+func (x *TagWidths) GetF536870911() uint64 {
+	if x != nil {
+		return x.F536870911
+	}
+	return 0
+}
+
+type TagWidthStrings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	F15           string                 `protobuf:"bytes,15,opt,name=f15,proto3" json:"f15,omitempty"`
+	F16           string                 `protobuf:"bytes,16,opt,name=f16,proto3" json:"f16,omitempty"`
+	F2047         string                 `protobuf:"bytes,2047,opt,name=f2047,proto3" json:"f2047,omitempty"`
+	F2048         string                 `protobuf:"bytes,2048,opt,name=f2048,proto3" json:"f2048,omitempty"`
+	F262143       string                 `protobuf:"bytes,262143,opt,name=f262143,proto3" json:"f262143,omitempty"`
+	F262144       string                 `protobuf:"bytes,262144,opt,name=f262144,proto3" json:"f262144,omitempty"`
+	F33554431     string                 `protobuf:"bytes,33554431,opt,name=f33554431,proto3" json:"f33554431,omitempty"`
+	F33554432     string                 `protobuf:"bytes,33554432,opt,name=f33554432,proto3" json:"f33554432,omitempty"`
+	F536870911    string                 `protobuf:"bytes,536870911,opt,name=f536870911,proto3" json:"f536870911,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TagWidthStrings) Reset() {
+	*x = TagWidthStrings{}
+	mi := &file_tags3_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TagWidthStrings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagWidthStrings) ProtoMessage() {}
+
+func (x *TagWidthStrings) ProtoReflect() protoreflect.Message {
+	mi := &file_tags3_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagWidthStrings.ProtoReflect.Descriptor instead.
+func (*TagWidthStrings) Descriptor() ([]byte, []int) {
+	return file_tags3_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TagWidthStrings) GetF15() string {
+	if x != nil {
+		return x.F15
+	}
+	return ""
+}
+
+func (x *TagWidthStrings) GetF16() string {
+	if x != nil {
+		return x.F16
+	}
+	return ""
+}
+
+func (x *TagWidthStrings) GetF2047() string {
+	if x != nil {
+		return x.F2047
+	}
+	return ""
+}
+
+func (x *TagWidthStrings) GetF2048() string {
+	if x != nil {
+		return x.F2048
+	}
+	return ""
+}
+
+func (x *TagWidthStrings) GetF262143() string {
+	if x != nil {
+		return x.F262143
+	}
+	return ""
+}
+
+func (x *TagWidthStrings) GetF262144() string {
+	if x != nil {
+		return x.F262144
+	}
+	return ""
+}
+
+func (x *TagWidthStrings) GetF33554431() string {
+	if x != nil {
+		return x.F33554431
+	}
+	return ""
+}
+
+func (x *TagWidthStrings) GetF33554432() string {
+	if x != nil {
+		return x.F33554432
+	}
+	return ""
+}
+
+func (x *TagWidthStrings) GetF536870911() string {
+	if x != nil {
+		return x.F536870911
+	}
+	return ""
+}
+
+var File_tags3_proto protoreflect.FileDescriptor
+
+const file_tags3_proto_rawDesc = "" +
+	"\n" +
+	"\vtags3.proto\"\xa7\x02\n" +
+	"\tTagWidths\x12*\n" +
+	"\astrings\x18\x01 \x01(\v2\x10.TagWidthStringsR\astrings\x12\x10\n" +
+	"\x03f15\x18\x0f \x01(\x04R\x03f15\x12\x10\n" +
+	"\x03f16\x18\x10 \x01(\x04R\x03f16\x12\x15\n" +
+	"\x05f2047\x18\xff\x0f \x01(\x04R\x05f2047\x12\x15\n" +
+	"\x05f2048\x18\x80\x10 \x01(\x04R\x05f2048\x12\x1a\n" +
+	"\af262143\x18\xff\xff\x0f \x01(\x04R\af262143\x12\x1a\n" +
+	"\af262144\x18\x80\x80\x10 \x01(\x04R\af262144\x12\x1f\n" +
+	"\tf33554431\x18\xff\xff\xff\x0f \x01(\x04R\tf33554431\x12\x1f\n" +
+	"\tf33554432\x18\x80\x80\x80\x10 \x01(\x04R\tf33554432\x12\"\n" +
+	"\n" +
+	"f536870911\x18\xff\xff\xff\xff\x01 \x01(\x04R\n" +
+	"f536870911\"\x81\x02\n" +
+	"\x0fTagWidthStrings\x12\x10\n" +
+	"\x03f15\x18\x0f \x01(\tR\x03f15\x12\x10\n" +
+	"\x03f16\x18\x10 \x01(\tR\x03f16\x12\x15\n" +
+	"\x05f2047\x18\xff\x0f \x01(\tR\x05f2047\x12\x15\n" +
+	"\x05f2048\x18\x80\x10 \x01(\tR\x05f2048\x12\x1a\n" +
+	"\af262143\x18\xff\xff\x0f \x01(\tR\af262143\x12\x1a\n" +
+	"\af262144\x18\x80\x80\x10 \x01(\tR\af262144\x12\x1f\n" +
+	"\tf33554431\x18\xff\xff\xff\x0f \x01(\tR\tf33554431\x12\x1f\n" +
+	"\tf33554432\x18\x80\x80\x80\x10 \x01(\tR\tf33554432\x12\"\n" +
+	"\n" +
+	"f536870911\x18\xff\xff\xff\xff\x01 \x01(\tR\n" +
+	"f536870911b\x06proto3"
+
+var (
+	file_tags3_proto_rawDescOnce sync.Once
+	file_tags3_proto_rawDescData []byte
+)
+
+func file_tags3_proto_rawDescGZIP() []byte {
+	file_tags3_proto_rawDescOnce.Do(func() {
+		file_tags3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_tags3_proto_rawDesc), len(file_tags3_proto_rawDesc)))
+	})
+	return file_tags3_proto_rawDescData
+}
+
+var file_tags3_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_tags3_proto_goTypes = []any{
+	(*TagWidths)(nil),       // 0: TagWidths
+	(*TagWidthStrings)(nil), // 1: TagWidthStrings
+}
+var file_tags3_proto_depIdxs = []int32{
+	1, // 0: TagWidths.strings:type_name -> TagWidthStrings
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_tags3_proto_init() }
+func file_tags3_proto_init() {
+	if File_tags3_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_tags3_proto_rawDesc), len(file_tags3_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_tags3_proto_goTypes,
+		DependencyIndexes: file_tags3_proto_depIdxs,
+		MessageInfos:      file_tags3_proto_msgTypes,
+	}.Build()
+	File_tags3_proto = out.File
+	file_tags3_proto_goTypes = nil
+	file_tags3_proto_depIdxs = nil
+}
@@ -0,0 +1,9 @@
+// Package pbrpc holds the Go server interfaces and client stubs of every
+// service in the compat schemas, generated from their descriptors by
+// cmd/rpcgen. For each service Foo it declares the paths of Foo's methods,
+// a FooServer interface with RegisterFooServer to serve it from an
+// rpcproto.Registrar, and a FooClient making calls on an
+// rpcproto.StreamConn.
+package pbrpc
+
+//go:generate go run ../cmd/rpcgen -o services.go
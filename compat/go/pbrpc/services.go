@@ -0,0 +1,672 @@
+// Code generated by rpcgen. DO NOT EDIT.
+
+package pbrpc
+
+import (
+	"context"
+
+	"compat/pb"
+	"compat/rpcproto"
+)
+
+// Method paths of acp.AcpService, declared in acp.proto.
+const (
+	AcpServiceVerifyPath = "/acp.AcpService/Verify"
+)
+
+// AcpServiceServer is the server side of acp.AcpService.
+type AcpServiceServer interface {
+	Verify(context.Context, *rpcproto.RecvStream[*pb.AcpMessage]) (*pb.AcpMessage, error)
+}
+
+// RegisterAcpServiceServer registers the methods of srv with r.
+func RegisterAcpServiceServer(r rpcproto.Registrar, srv AcpServiceServer) {
+	r.HandleMethod(rpcproto.MustResolveMethod(AcpServiceVerifyPath), rpcproto.HandleClientStream(srv.Verify))
+}
+
+// AcpServiceClient calls the methods of acp.AcpService, one call at a time.
+type AcpServiceClient struct {
+	s *rpcproto.StreamConn
+}
+
+// NewAcpServiceClient returns a client making calls on s.
+func NewAcpServiceClient(s *rpcproto.StreamConn) *AcpServiceClient {
+	return &AcpServiceClient{s: s}
+}
+
+// Verify calls /acp.AcpService/Verify.
+func (c *AcpServiceClient) Verify(ctx context.Context) (*rpcproto.ClientStream[*pb.AcpMessage, *pb.AcpMessage], error) {
+	return rpcproto.CallClientStream[*pb.AcpMessage, *pb.AcpMessage](ctx, c.s, AcpServiceVerifyPath)
+}
+
+// Method paths of compat.gateway.UnaryService, declared in gateway3.proto.
+const (
+	CompatGatewayUnaryServicePingPath    = "/compat.gateway.UnaryService/Ping"
+	CompatGatewayUnaryServiceGetItemPath = "/compat.gateway.UnaryService/GetItem"
+	CompatGatewayUnaryServiceHealthPath  = "/compat.gateway.UnaryService/Health"
+	CompatGatewayUnaryServiceEchoPath    = "/compat.gateway.UnaryService/Echo"
+)
+
+// CompatGatewayUnaryServiceServer is the server side of compat.gateway.UnaryService.
+type CompatGatewayUnaryServiceServer interface {
+	Ping(context.Context, *pb.PingRequest) (*pb.PingResponse, error)
+	GetItem(context.Context, *pb.GetItemRequest) (*pb.GetItemResponse, error)
+	Health(context.Context, *pb.HealthRequest) (*pb.HealthResponse, error)
+	Echo(context.Context, *pb.EchoMessage) (*pb.EchoMessage, error)
+}
+
+// RegisterCompatGatewayUnaryServiceServer registers the methods of srv with r.
+func RegisterCompatGatewayUnaryServiceServer(r rpcproto.Registrar, srv CompatGatewayUnaryServiceServer) {
+	r.HandleMethod(rpcproto.MustResolveMethod(CompatGatewayUnaryServicePingPath), rpcproto.HandleUnary(srv.Ping))
+	r.HandleMethod(rpcproto.MustResolveMethod(CompatGatewayUnaryServiceGetItemPath), rpcproto.HandleUnary(srv.GetItem))
+	r.HandleMethod(rpcproto.MustResolveMethod(CompatGatewayUnaryServiceHealthPath), rpcproto.HandleUnary(srv.Health))
+	r.HandleMethod(rpcproto.MustResolveMethod(CompatGatewayUnaryServiceEchoPath), rpcproto.HandleUnary(srv.Echo))
+}
+
+// CompatGatewayUnaryServiceClient calls the methods of compat.gateway.UnaryService, one call at a time.
+type CompatGatewayUnaryServiceClient struct {
+	s *rpcproto.StreamConn
+}
+
+// NewCompatGatewayUnaryServiceClient returns a client making calls on s.
+func NewCompatGatewayUnaryServiceClient(s *rpcproto.StreamConn) *CompatGatewayUnaryServiceClient {
+	return &CompatGatewayUnaryServiceClient{s: s}
+}
+
+// Ping calls /compat.gateway.UnaryService/Ping.
+func (c *CompatGatewayUnaryServiceClient) Ping(ctx context.Context, req *pb.PingRequest) (*pb.PingResponse, error) {
+	return rpcproto.CallUnary[*pb.PingRequest, *pb.PingResponse](ctx, c.s, CompatGatewayUnaryServicePingPath, req)
+}
+
+// GetItem calls /compat.gateway.UnaryService/GetItem.
+func (c *CompatGatewayUnaryServiceClient) GetItem(ctx context.Context, req *pb.GetItemRequest) (*pb.GetItemResponse, error) {
+	return rpcproto.CallUnary[*pb.GetItemRequest, *pb.GetItemResponse](ctx, c.s, CompatGatewayUnaryServiceGetItemPath, req)
+}
+
+// Health calls /compat.gateway.UnaryService/Health.
+func (c *CompatGatewayUnaryServiceClient) Health(ctx context.Context, req *pb.HealthRequest) (*pb.HealthResponse, error) {
+	return rpcproto.CallUnary[*pb.HealthRequest, *pb.HealthResponse](ctx, c.s, CompatGatewayUnaryServiceHealthPath, req)
+}
+
+// Echo calls /compat.gateway.UnaryService/Echo.
+func (c *CompatGatewayUnaryServiceClient) Echo(ctx context.Context, req *pb.EchoMessage) (*pb.EchoMessage, error) {
+	return rpcproto.CallUnary[*pb.EchoMessage, *pb.EchoMessage](ctx, c.s, CompatGatewayUnaryServiceEchoPath, req)
+}
+
+// Method paths of HarnessService, declared in harness.proto.
+const (
+	HarnessServiceEchoScalarPath         = "/HarnessService/EchoScalar"
+	HarnessServiceEchoAnyPath            = "/HarnessService/EchoAny"
+	HarnessServiceBlobPath               = "/HarnessService/Blob"
+	HarnessServiceUnknownFieldsPath      = "/HarnessService/UnknownFields"
+	HarnessServiceCheckUnknownFieldsPath = "/HarnessService/CheckUnknownFields"
+	HarnessServiceFirehosePath           = "/HarnessService/Firehose"
+	HarnessServiceFailAfterNPath         = "/HarnessService/FailAfterN"
+	HarnessServiceUploadBlobPath         = "/HarnessService/UploadBlob"
+	HarnessServiceAcpSessionPath         = "/HarnessService/AcpSession"
+)
+
+// HarnessServiceServer is the server side of HarnessService.
+type HarnessServiceServer interface {
+	EchoScalar(context.Context, *pb.ScalarMessage) (*pb.ScalarMessage, error)
+	EchoAny(context.Context, *pb.AnyMessage) (*pb.AnyMessage, error)
+	Blob(context.Context, *pb.BlobRequest) (*pb.BlobResponse, error)
+	UnknownFields(context.Context, *pb.EchoMessage) (*pb.EchoMessage, error)
+	CheckUnknownFields(context.Context, *pb.EchoMessage) (*pb.EchoMessage, error)
+	Firehose(context.Context, *pb.FirehoseRequest, *rpcproto.SendStream[*pb.FirehoseChunk]) error
+	FailAfterN(context.Context, *pb.FailAfterNRequest, *rpcproto.SendStream[*pb.StreamResponse]) error
+	UploadBlob(context.Context, *rpcproto.RecvStream[*pb.UploadChunk]) (*pb.UploadResult, error)
+	AcpSession(context.Context, *rpcproto.BidiStream[*pb.AcpMessage, *pb.AcpMessage]) error
+}
+
+// RegisterHarnessServiceServer registers the methods of srv with r.
+func RegisterHarnessServiceServer(r rpcproto.Registrar, srv HarnessServiceServer) {
+	r.HandleMethod(rpcproto.MustResolveMethod(HarnessServiceEchoScalarPath), rpcproto.HandleUnary(srv.EchoScalar))
+	r.HandleMethod(rpcproto.MustResolveMethod(HarnessServiceEchoAnyPath), rpcproto.HandleUnary(srv.EchoAny))
+	r.HandleMethod(rpcproto.MustResolveMethod(HarnessServiceBlobPath), rpcproto.HandleUnary(srv.Blob))
+	r.HandleMethod(rpcproto.MustResolveMethod(HarnessServiceUnknownFieldsPath), rpcproto.HandleUnary(srv.UnknownFields))
+	r.HandleMethod(rpcproto.MustResolveMethod(HarnessServiceCheckUnknownFieldsPath), rpcproto.HandleUnary(srv.CheckUnknownFields))
+	r.HandleMethod(rpcproto.MustResolveMethod(HarnessServiceFirehosePath), rpcproto.HandleServerStream(srv.Firehose))
+	r.HandleMethod(rpcproto.MustResolveMethod(HarnessServiceFailAfterNPath), rpcproto.HandleServerStream(srv.FailAfterN))
+	r.HandleMethod(rpcproto.MustResolveMethod(HarnessServiceUploadBlobPath), rpcproto.HandleClientStream(srv.UploadBlob))
+	r.HandleMethod(rpcproto.MustResolveMethod(HarnessServiceAcpSessionPath), rpcproto.HandleBidiStream(srv.AcpSession))
+}
+
+// HarnessServiceClient calls the methods of HarnessService, one call at a time.
+type HarnessServiceClient struct {
+	s *rpcproto.StreamConn
+}
+
+// NewHarnessServiceClient returns a client making calls on s.
+func NewHarnessServiceClient(s *rpcproto.StreamConn) *HarnessServiceClient {
+	return &HarnessServiceClient{s: s}
+}
+
+// EchoScalar calls /HarnessService/EchoScalar.
+func (c *HarnessServiceClient) EchoScalar(ctx context.Context, req *pb.ScalarMessage) (*pb.ScalarMessage, error) {
+	return rpcproto.CallUnary[*pb.ScalarMessage, *pb.ScalarMessage](ctx, c.s, HarnessServiceEchoScalarPath, req)
+}
+
+// EchoAny calls /HarnessService/EchoAny.
+func (c *HarnessServiceClient) EchoAny(ctx context.Context, req *pb.AnyMessage) (*pb.AnyMessage, error) {
+	return rpcproto.CallUnary[*pb.AnyMessage, *pb.AnyMessage](ctx, c.s, HarnessServiceEchoAnyPath, req)
+}
+
+// Blob calls /HarnessService/Blob.
+func (c *HarnessServiceClient) Blob(ctx context.Context, req *pb.BlobRequest) (*pb.BlobResponse, error) {
+	return rpcproto.CallUnary[*pb.BlobRequest, *pb.BlobResponse](ctx, c.s, HarnessServiceBlobPath, req)
+}
+
+// UnknownFields calls /HarnessService/UnknownFields.
+func (c *HarnessServiceClient) UnknownFields(ctx context.Context, req *pb.EchoMessage) (*pb.EchoMessage, error) {
+	return rpcproto.CallUnary[*pb.EchoMessage, *pb.EchoMessage](ctx, c.s, HarnessServiceUnknownFieldsPath, req)
+}
+
+// CheckUnknownFields calls /HarnessService/CheckUnknownFields.
+func (c *HarnessServiceClient) CheckUnknownFields(ctx context.Context, req *pb.EchoMessage) (*pb.EchoMessage, error) {
+	return rpcproto.CallUnary[*pb.EchoMessage, *pb.EchoMessage](ctx, c.s, HarnessServiceCheckUnknownFieldsPath, req)
+}
+
+// Firehose calls /HarnessService/Firehose.
+func (c *HarnessServiceClient) Firehose(ctx context.Context, req *pb.FirehoseRequest) (*rpcproto.RecvStream[*pb.FirehoseChunk], error) {
+	return rpcproto.CallServerStream[*pb.FirehoseRequest, *pb.FirehoseChunk](ctx, c.s, HarnessServiceFirehosePath, req)
+}
+
+// FailAfterN calls /HarnessService/FailAfterN.
+func (c *HarnessServiceClient) FailAfterN(ctx context.Context, req *pb.FailAfterNRequest) (*rpcproto.RecvStream[*pb.StreamResponse], error) {
+	return rpcproto.CallServerStream[*pb.FailAfterNRequest, *pb.StreamResponse](ctx, c.s, HarnessServiceFailAfterNPath, req)
+}
+
+// UploadBlob calls /HarnessService/UploadBlob.
+func (c *HarnessServiceClient) UploadBlob(ctx context.Context) (*rpcproto.ClientStream[*pb.UploadChunk, *pb.UploadResult], error) {
+	return rpcproto.CallClientStream[*pb.UploadChunk, *pb.UploadResult](ctx, c.s, HarnessServiceUploadBlobPath)
+}
+
+// AcpSession calls /HarnessService/AcpSession.
+func (c *HarnessServiceClient) AcpSession(ctx context.Context) (*rpcproto.BidiStream[*pb.AcpMessage, *pb.AcpMessage], error) {
+	return rpcproto.CallBidiStream[*pb.AcpMessage, *pb.AcpMessage](ctx, c.s, HarnessServiceAcpSessionPath)
+}
+
+// Method paths of SingleMethodService, declared in service_edge.proto.
+const (
+	SingleMethodServiceOnlyMethodPath = "/SingleMethodService/OnlyMethod"
+)
+
+// SingleMethodServiceServer is the server side of SingleMethodService.
+type SingleMethodServiceServer interface {
+	OnlyMethod(context.Context, *pb.EdgeRequest) (*pb.EdgeResponse, error)
+}
+
+// RegisterSingleMethodServiceServer registers the methods of srv with r.
+func RegisterSingleMethodServiceServer(r rpcproto.Registrar, srv SingleMethodServiceServer) {
+	r.HandleMethod(rpcproto.MustResolveMethod(SingleMethodServiceOnlyMethodPath), rpcproto.HandleUnary(srv.OnlyMethod))
+}
+
+// SingleMethodServiceClient calls the methods of SingleMethodService, one call at a time.
+type SingleMethodServiceClient struct {
+	s *rpcproto.StreamConn
+}
+
+// NewSingleMethodServiceClient returns a client making calls on s.
+func NewSingleMethodServiceClient(s *rpcproto.StreamConn) *SingleMethodServiceClient {
+	return &SingleMethodServiceClient{s: s}
+}
+
+// OnlyMethod calls /SingleMethodService/OnlyMethod.
+func (c *SingleMethodServiceClient) OnlyMethod(ctx context.Context, req *pb.EdgeRequest) (*pb.EdgeResponse, error) {
+	return rpcproto.CallUnary[*pb.EdgeRequest, *pb.EdgeResponse](ctx, c.s, SingleMethodServiceOnlyMethodPath, req)
+}
+
+// Method paths of ManyMethodsService, declared in service_edge.proto.
+const (
+	ManyMethodsServiceCreatePath   = "/ManyMethodsService/Create"
+	ManyMethodsServiceReadPath     = "/ManyMethodsService/Read"
+	ManyMethodsServiceUpdatePath   = "/ManyMethodsService/Update"
+	ManyMethodsServiceDeletePath   = "/ManyMethodsService/Delete"
+	ManyMethodsServiceListPath     = "/ManyMethodsService/List"
+	ManyMethodsServiceCountPath    = "/ManyMethodsService/Count"
+	ManyMethodsServiceExistsPath   = "/ManyMethodsService/Exists"
+	ManyMethodsServiceValidatePath = "/ManyMethodsService/Validate"
+)
+
+// ManyMethodsServiceServer is the server side of ManyMethodsService.
+type ManyMethodsServiceServer interface {
+	Create(context.Context, *pb.EdgeRequest) (*pb.EdgeResponse, error)
+	Read(context.Context, *pb.EdgeRequest) (*pb.EdgeResponse, error)
+	Update(context.Context, *pb.EdgeRequest) (*pb.EdgeResponse, error)
+	Delete(context.Context, *pb.EdgeRequest) (*pb.EdgeResponse, error)
+	List(context.Context, *pb.EdgeRequest) (*pb.EdgeResponse, error)
+	Count(context.Context, *pb.EdgeRequest) (*pb.EdgeResponse, error)
+	Exists(context.Context, *pb.EdgeRequest) (*pb.EdgeResponse, error)
+	Validate(context.Context, *pb.EdgeRequest) (*pb.EdgeResponse, error)
+}
+
+// RegisterManyMethodsServiceServer registers the methods of srv with r.
+func RegisterManyMethodsServiceServer(r rpcproto.Registrar, srv ManyMethodsServiceServer) {
+	r.HandleMethod(rpcproto.MustResolveMethod(ManyMethodsServiceCreatePath), rpcproto.HandleUnary(srv.Create))
+	r.HandleMethod(rpcproto.MustResolveMethod(ManyMethodsServiceReadPath), rpcproto.HandleUnary(srv.Read))
+	r.HandleMethod(rpcproto.MustResolveMethod(ManyMethodsServiceUpdatePath), rpcproto.HandleUnary(srv.Update))
+	r.HandleMethod(rpcproto.MustResolveMethod(ManyMethodsServiceDeletePath), rpcproto.HandleUnary(srv.Delete))
+	r.HandleMethod(rpcproto.MustResolveMethod(ManyMethodsServiceListPath), rpcproto.HandleUnary(srv.List))
+	r.HandleMethod(rpcproto.MustResolveMethod(ManyMethodsServiceCountPath), rpcproto.HandleUnary(srv.Count))
+	r.HandleMethod(rpcproto.MustResolveMethod(ManyMethodsServiceExistsPath), rpcproto.HandleUnary(srv.Exists))
+	r.HandleMethod(rpcproto.MustResolveMethod(ManyMethodsServiceValidatePath), rpcproto.HandleUnary(srv.Validate))
+}
+
+// ManyMethodsServiceClient calls the methods of ManyMethodsService, one call at a time.
+type ManyMethodsServiceClient struct {
+	s *rpcproto.StreamConn
+}
+
+// NewManyMethodsServiceClient returns a client making calls on s.
+func NewManyMethodsServiceClient(s *rpcproto.StreamConn) *ManyMethodsServiceClient {
+	return &ManyMethodsServiceClient{s: s}
+}
+
+// Create calls /ManyMethodsService/Create.
+func (c *ManyMethodsServiceClient) Create(ctx context.Context, req *pb.EdgeRequest) (*pb.EdgeResponse, error) {
+	return rpcproto.CallUnary[*pb.EdgeRequest, *pb.EdgeResponse](ctx, c.s, ManyMethodsServiceCreatePath, req)
+}
+
+// Read calls /ManyMethodsService/Read.
+func (c *ManyMethodsServiceClient) Read(ctx context.Context, req *pb.EdgeRequest) (*pb.EdgeResponse, error) {
+	return rpcproto.CallUnary[*pb.EdgeRequest, *pb.EdgeResponse](ctx, c.s, ManyMethodsServiceReadPath, req)
+}
+
+// Update calls /ManyMethodsService/Update.
+func (c *ManyMethodsServiceClient) Update(ctx context.Context, req *pb.EdgeRequest) (*pb.EdgeResponse, error) {
+	return rpcproto.CallUnary[*pb.EdgeRequest, *pb.EdgeResponse](ctx, c.s, ManyMethodsServiceUpdatePath, req)
+}
+
+// Delete calls /ManyMethodsService/Delete.
+func (c *ManyMethodsServiceClient) Delete(ctx context.Context, req *pb.EdgeRequest) (*pb.EdgeResponse, error) {
+	return rpcproto.CallUnary[*pb.EdgeRequest, *pb.EdgeResponse](ctx, c.s, ManyMethodsServiceDeletePath, req)
+}
+
+// List calls /ManyMethodsService/List.
+func (c *ManyMethodsServiceClient) List(ctx context.Context, req *pb.EdgeRequest) (*pb.EdgeResponse, error) {
+	return rpcproto.CallUnary[*pb.EdgeRequest, *pb.EdgeResponse](ctx, c.s, ManyMethodsServiceListPath, req)
+}
+
+// Count calls /ManyMethodsService/Count.
+func (c *ManyMethodsServiceClient) Count(ctx context.Context, req *pb.EdgeRequest) (*pb.EdgeResponse, error) {
+	return rpcproto.CallUnary[*pb.EdgeRequest, *pb.EdgeResponse](ctx, c.s, ManyMethodsServiceCountPath, req)
+}
+
+// Exists calls /ManyMethodsService/Exists.
+func (c *ManyMethodsServiceClient) Exists(ctx context.Context, req *pb.EdgeRequest) (*pb.EdgeResponse, error) {
+	return rpcproto.CallUnary[*pb.EdgeRequest, *pb.EdgeResponse](ctx, c.s, ManyMethodsServiceExistsPath, req)
+}
+
+// Validate calls /ManyMethodsService/Validate.
+func (c *ManyMethodsServiceClient) Validate(ctx context.Context, req *pb.EdgeRequest) (*pb.EdgeResponse, error) {
+	return rpcproto.CallUnary[*pb.EdgeRequest, *pb.EdgeResponse](ctx, c.s, ManyMethodsServiceValidatePath, req)
+}
+
+// Method paths of FirstService, declared in service_multi.proto.
+const (
+	FirstServiceMethodOnePath = "/FirstService/MethodOne"
+	FirstServiceMethodTwoPath = "/FirstService/MethodTwo"
+)
+
+// FirstServiceServer is the server side of FirstService.
+type FirstServiceServer interface {
+	MethodOne(context.Context, *pb.RequestA) (*pb.ResponseA, error)
+	MethodTwo(context.Context, *pb.RequestB) (*pb.ResponseB, error)
+}
+
+// RegisterFirstServiceServer registers the methods of srv with r.
+func RegisterFirstServiceServer(r rpcproto.Registrar, srv FirstServiceServer) {
+	r.HandleMethod(rpcproto.MustResolveMethod(FirstServiceMethodOnePath), rpcproto.HandleUnary(srv.MethodOne))
+	r.HandleMethod(rpcproto.MustResolveMethod(FirstServiceMethodTwoPath), rpcproto.HandleUnary(srv.MethodTwo))
+}
+
+// FirstServiceClient calls the methods of FirstService, one call at a time.
+type FirstServiceClient struct {
+	s *rpcproto.StreamConn
+}
+
+// NewFirstServiceClient returns a client making calls on s.
+func NewFirstServiceClient(s *rpcproto.StreamConn) *FirstServiceClient {
+	return &FirstServiceClient{s: s}
+}
+
+// MethodOne calls /FirstService/MethodOne.
+func (c *FirstServiceClient) MethodOne(ctx context.Context, req *pb.RequestA) (*pb.ResponseA, error) {
+	return rpcproto.CallUnary[*pb.RequestA, *pb.ResponseA](ctx, c.s, FirstServiceMethodOnePath, req)
+}
+
+// MethodTwo calls /FirstService/MethodTwo.
+func (c *FirstServiceClient) MethodTwo(ctx context.Context, req *pb.RequestB) (*pb.ResponseB, error) {
+	return rpcproto.CallUnary[*pb.RequestB, *pb.ResponseB](ctx, c.s, FirstServiceMethodTwoPath, req)
+}
+
+// Method paths of SecondService, declared in service_multi.proto.
+const (
+	SecondServiceReversePath   = "/SecondService/Reverse"
+	SecondServiceTransformPath = "/SecondService/Transform"
+)
+
+// SecondServiceServer is the server side of SecondService.
+type SecondServiceServer interface {
+	Reverse(context.Context, *pb.ResponseA) (*pb.RequestA, error)
+	Transform(context.Context, *pb.RequestB) (*pb.ResponseB, error)
+}
+
+// RegisterSecondServiceServer registers the methods of srv with r.
+func RegisterSecondServiceServer(r rpcproto.Registrar, srv SecondServiceServer) {
+	r.HandleMethod(rpcproto.MustResolveMethod(SecondServiceReversePath), rpcproto.HandleUnary(srv.Reverse))
+	r.HandleMethod(rpcproto.MustResolveMethod(SecondServiceTransformPath), rpcproto.HandleUnary(srv.Transform))
+}
+
+// SecondServiceClient calls the methods of SecondService, one call at a time.
+type SecondServiceClient struct {
+	s *rpcproto.StreamConn
+}
+
+// NewSecondServiceClient returns a client making calls on s.
+func NewSecondServiceClient(s *rpcproto.StreamConn) *SecondServiceClient {
+	return &SecondServiceClient{s: s}
+}
+
+// Reverse calls /SecondService/Reverse.
+func (c *SecondServiceClient) Reverse(ctx context.Context, req *pb.ResponseA) (*pb.RequestA, error) {
+	return rpcproto.CallUnary[*pb.ResponseA, *pb.RequestA](ctx, c.s, SecondServiceReversePath, req)
+}
+
+// Transform calls /SecondService/Transform.
+func (c *SecondServiceClient) Transform(ctx context.Context, req *pb.RequestB) (*pb.ResponseB, error) {
+	return rpcproto.CallUnary[*pb.RequestB, *pb.ResponseB](ctx, c.s, SecondServiceTransformPath, req)
+}
+
+// Method paths of ThirdService, declared in service_multi.proto.
+const (
+	ThirdServiceStreamExchangePath = "/ThirdService/StreamExchange"
+)
+
+// ThirdServiceServer is the server side of ThirdService.
+type ThirdServiceServer interface {
+	StreamExchange(context.Context, *rpcproto.BidiStream[*pb.RequestA, *pb.ResponseA]) error
+}
+
+// RegisterThirdServiceServer registers the methods of srv with r.
+func RegisterThirdServiceServer(r rpcproto.Registrar, srv ThirdServiceServer) {
+	r.HandleMethod(rpcproto.MustResolveMethod(ThirdServiceStreamExchangePath), rpcproto.HandleBidiStream(srv.StreamExchange))
+}
+
+// ThirdServiceClient calls the methods of ThirdService, one call at a time.
+type ThirdServiceClient struct {
+	s *rpcproto.StreamConn
+}
+
+// NewThirdServiceClient returns a client making calls on s.
+func NewThirdServiceClient(s *rpcproto.StreamConn) *ThirdServiceClient {
+	return &ThirdServiceClient{s: s}
+}
+
+// StreamExchange calls /ThirdService/StreamExchange.
+func (c *ThirdServiceClient) StreamExchange(ctx context.Context) (*rpcproto.BidiStream[*pb.ResponseA, *pb.RequestA], error) {
+	return rpcproto.CallBidiStream[*pb.RequestA, *pb.ResponseA](ctx, c.s, ThirdServiceStreamExchangePath)
+}
+
+// Method paths of NamingService, declared in service_names.proto.
+const (
+	NamingServiceAPath               = "/NamingService/A"
+	NamingServiceABPath              = "/NamingService/AB"
+	NamingServiceGetHTTPResponsePath = "/NamingService/GetHTTPResponse"
+	NamingServiceDoXMLParsingPath    = "/NamingService/DoXMLParsing"
+	NamingServiceSimpleCallPath      = "/NamingService/SimpleCall"
+	NamingServiceXPath               = "/NamingService/X"
+	NamingServiceGetUserByIDPath     = "/NamingService/GetUserByID"
+)
+
+// NamingServiceServer is the server side of NamingService.
+type NamingServiceServer interface {
+	A(context.Context, *pb.NameRequest) (*pb.NameResponse, error)
+	AB(context.Context, *pb.NameRequest) (*pb.NameResponse, error)
+	GetHTTPResponse(context.Context, *pb.NameRequest) (*pb.NameResponse, error)
+	DoXMLParsing(context.Context, *pb.NameRequest) (*pb.NameResponse, error)
+	SimpleCall(context.Context, *pb.NameRequest) (*pb.NameResponse, error)
+	X(context.Context, *pb.NameRequest) (*pb.NameResponse, error)
+	GetUserByID(context.Context, *pb.NameRequest) (*pb.NameResponse, error)
+}
+
+// RegisterNamingServiceServer registers the methods of srv with r.
+func RegisterNamingServiceServer(r rpcproto.Registrar, srv NamingServiceServer) {
+	r.HandleMethod(rpcproto.MustResolveMethod(NamingServiceAPath), rpcproto.HandleUnary(srv.A))
+	r.HandleMethod(rpcproto.MustResolveMethod(NamingServiceABPath), rpcproto.HandleUnary(srv.AB))
+	r.HandleMethod(rpcproto.MustResolveMethod(NamingServiceGetHTTPResponsePath), rpcproto.HandleUnary(srv.GetHTTPResponse))
+	r.HandleMethod(rpcproto.MustResolveMethod(NamingServiceDoXMLParsingPath), rpcproto.HandleUnary(srv.DoXMLParsing))
+	r.HandleMethod(rpcproto.MustResolveMethod(NamingServiceSimpleCallPath), rpcproto.HandleUnary(srv.SimpleCall))
+	r.HandleMethod(rpcproto.MustResolveMethod(NamingServiceXPath), rpcproto.HandleUnary(srv.X))
+	r.HandleMethod(rpcproto.MustResolveMethod(NamingServiceGetUserByIDPath), rpcproto.HandleUnary(srv.GetUserByID))
+}
+
+// NamingServiceClient calls the methods of NamingService, one call at a time.
+type NamingServiceClient struct {
+	s *rpcproto.StreamConn
+}
+
+// NewNamingServiceClient returns a client making calls on s.
+func NewNamingServiceClient(s *rpcproto.StreamConn) *NamingServiceClient {
+	return &NamingServiceClient{s: s}
+}
+
+// A calls /NamingService/A.
+func (c *NamingServiceClient) A(ctx context.Context, req *pb.NameRequest) (*pb.NameResponse, error) {
+	return rpcproto.CallUnary[*pb.NameRequest, *pb.NameResponse](ctx, c.s, NamingServiceAPath, req)
+}
+
+// AB calls /NamingService/AB.
+func (c *NamingServiceClient) AB(ctx context.Context, req *pb.NameRequest) (*pb.NameResponse, error) {
+	return rpcproto.CallUnary[*pb.NameRequest, *pb.NameResponse](ctx, c.s, NamingServiceABPath, req)
+}
+
+// GetHTTPResponse calls /NamingService/GetHTTPResponse.
+func (c *NamingServiceClient) GetHTTPResponse(ctx context.Context, req *pb.NameRequest) (*pb.NameResponse, error) {
+	return rpcproto.CallUnary[*pb.NameRequest, *pb.NameResponse](ctx, c.s, NamingServiceGetHTTPResponsePath, req)
+}
+
+// DoXMLParsing calls /NamingService/DoXMLParsing.
+func (c *NamingServiceClient) DoXMLParsing(ctx context.Context, req *pb.NameRequest) (*pb.NameResponse, error) {
+	return rpcproto.CallUnary[*pb.NameRequest, *pb.NameResponse](ctx, c.s, NamingServiceDoXMLParsingPath, req)
+}
+
+// SimpleCall calls /NamingService/SimpleCall.
+func (c *NamingServiceClient) SimpleCall(ctx context.Context, req *pb.NameRequest) (*pb.NameResponse, error) {
+	return rpcproto.CallUnary[*pb.NameRequest, *pb.NameResponse](ctx, c.s, NamingServiceSimpleCallPath, req)
+}
+
+// X calls /NamingService/X.
+func (c *NamingServiceClient) X(ctx context.Context, req *pb.NameRequest) (*pb.NameResponse, error) {
+	return rpcproto.CallUnary[*pb.NameRequest, *pb.NameResponse](ctx, c.s, NamingServiceXPath, req)
+}
+
+// GetUserByID calls /NamingService/GetUserByID.
+func (c *NamingServiceClient) GetUserByID(ctx context.Context, req *pb.NameRequest) (*pb.NameResponse, error) {
+	return rpcproto.CallUnary[*pb.NameRequest, *pb.NameResponse](ctx, c.s, NamingServiceGetUserByIDPath, req)
+}
+
+// Method paths of myapp.services.v1.PackagedService, declared in service_package.proto.
+const (
+	PackagedServiceLookupPath        = "/myapp.services.v1.PackagedService/Lookup"
+	PackagedServiceReverseLookupPath = "/myapp.services.v1.PackagedService/ReverseLookup"
+)
+
+// PackagedServiceServer is the server side of myapp.services.v1.PackagedService.
+type PackagedServiceServer interface {
+	Lookup(context.Context, *pb.LookupRequest) (*pb.LookupResponse, error)
+	ReverseLookup(context.Context, *pb.LookupResponse) (*pb.LookupRequest, error)
+}
+
+// RegisterPackagedServiceServer registers the methods of srv with r.
+func RegisterPackagedServiceServer(r rpcproto.Registrar, srv PackagedServiceServer) {
+	r.HandleMethod(rpcproto.MustResolveMethod(PackagedServiceLookupPath), rpcproto.HandleUnary(srv.Lookup))
+	r.HandleMethod(rpcproto.MustResolveMethod(PackagedServiceReverseLookupPath), rpcproto.HandleUnary(srv.ReverseLookup))
+}
+
+// PackagedServiceClient calls the methods of myapp.services.v1.PackagedService, one call at a time.
+type PackagedServiceClient struct {
+	s *rpcproto.StreamConn
+}
+
+// NewPackagedServiceClient returns a client making calls on s.
+func NewPackagedServiceClient(s *rpcproto.StreamConn) *PackagedServiceClient {
+	return &PackagedServiceClient{s: s}
+}
+
+// Lookup calls /myapp.services.v1.PackagedService/Lookup.
+func (c *PackagedServiceClient) Lookup(ctx context.Context, req *pb.LookupRequest) (*pb.LookupResponse, error) {
+	return rpcproto.CallUnary[*pb.LookupRequest, *pb.LookupResponse](ctx, c.s, PackagedServiceLookupPath, req)
+}
+
+// ReverseLookup calls /myapp.services.v1.PackagedService/ReverseLookup.
+func (c *PackagedServiceClient) ReverseLookup(ctx context.Context, req *pb.LookupResponse) (*pb.LookupRequest, error) {
+	return rpcproto.CallUnary[*pb.LookupResponse, *pb.LookupRequest](ctx, c.s, PackagedServiceReverseLookupPath, req)
+}
+
+// Method paths of StreamingService, declared in service_streaming.proto.
+const (
+	StreamingServiceUnaryCallPath     = "/StreamingService/UnaryCall"
+	StreamingServiceServerSidePath    = "/StreamingService/ServerSide"
+	StreamingServiceClientSidePath    = "/StreamingService/ClientSide"
+	StreamingServiceBidirectionalPath = "/StreamingService/Bidirectional"
+)
+
+// StreamingServiceServer is the server side of StreamingService.
+type StreamingServiceServer interface {
+	UnaryCall(context.Context, *pb.StreamRequest) (*pb.StreamResponse, error)
+	ServerSide(context.Context, *pb.StreamRequest, *rpcproto.SendStream[*pb.StreamResponse]) error
+	ClientSide(context.Context, *rpcproto.RecvStream[*pb.UploadChunk]) (*pb.UploadResult, error)
+	Bidirectional(context.Context, *rpcproto.BidiStream[*pb.ChatMessage, *pb.ChatMessage]) error
+}
+
+// RegisterStreamingServiceServer registers the methods of srv with r.
+func RegisterStreamingServiceServer(r rpcproto.Registrar, srv StreamingServiceServer) {
+	r.HandleMethod(rpcproto.MustResolveMethod(StreamingServiceUnaryCallPath), rpcproto.HandleUnary(srv.UnaryCall))
+	r.HandleMethod(rpcproto.MustResolveMethod(StreamingServiceServerSidePath), rpcproto.HandleServerStream(srv.ServerSide))
+	r.HandleMethod(rpcproto.MustResolveMethod(StreamingServiceClientSidePath), rpcproto.HandleClientStream(srv.ClientSide))
+	r.HandleMethod(rpcproto.MustResolveMethod(StreamingServiceBidirectionalPath), rpcproto.HandleBidiStream(srv.Bidirectional))
+}
+
+// StreamingServiceClient calls the methods of StreamingService, one call at a time.
+type StreamingServiceClient struct {
+	s *rpcproto.StreamConn
+}
+
+// NewStreamingServiceClient returns a client making calls on s.
+func NewStreamingServiceClient(s *rpcproto.StreamConn) *StreamingServiceClient {
+	return &StreamingServiceClient{s: s}
+}
+
+// UnaryCall calls /StreamingService/UnaryCall.
+func (c *StreamingServiceClient) UnaryCall(ctx context.Context, req *pb.StreamRequest) (*pb.StreamResponse, error) {
+	return rpcproto.CallUnary[*pb.StreamRequest, *pb.StreamResponse](ctx, c.s, StreamingServiceUnaryCallPath, req)
+}
+
+// ServerSide calls /StreamingService/ServerSide.
+func (c *StreamingServiceClient) ServerSide(ctx context.Context, req *pb.StreamRequest) (*rpcproto.RecvStream[*pb.StreamResponse], error) {
+	return rpcproto.CallServerStream[*pb.StreamRequest, *pb.StreamResponse](ctx, c.s, StreamingServiceServerSidePath, req)
+}
+
+// ClientSide calls /StreamingService/ClientSide.
+func (c *StreamingServiceClient) ClientSide(ctx context.Context) (*rpcproto.ClientStream[*pb.UploadChunk, *pb.UploadResult], error) {
+	return rpcproto.CallClientStream[*pb.UploadChunk, *pb.UploadResult](ctx, c.s, StreamingServiceClientSidePath)
+}
+
+// Bidirectional calls /StreamingService/Bidirectional.
+func (c *StreamingServiceClient) Bidirectional(ctx context.Context) (*rpcproto.BidiStream[*pb.ChatMessage, *pb.ChatMessage], error) {
+	return rpcproto.CallBidiStream[*pb.ChatMessage, *pb.ChatMessage](ctx, c.s, StreamingServiceBidirectionalPath)
+}
+
+// Method paths of TypesService, declared in service_types.proto.
+const (
+	TypesServiceProcessComplexPath = "/TypesService/ProcessComplex"
+	TypesServiceStreamComplexPath  = "/TypesService/StreamComplex"
+)
+
+// TypesServiceServer is the server side of TypesService.
+type TypesServiceServer interface {
+	ProcessComplex(context.Context, *pb.ComplexRequest) (*pb.ComplexResponse, error)
+	StreamComplex(context.Context, *pb.ComplexRequest, *rpcproto.SendStream[*pb.ComplexResponse]) error
+}
+
+// RegisterTypesServiceServer registers the methods of srv with r.
+func RegisterTypesServiceServer(r rpcproto.Registrar, srv TypesServiceServer) {
+	r.HandleMethod(rpcproto.MustResolveMethod(TypesServiceProcessComplexPath), rpcproto.HandleUnary(srv.ProcessComplex))
+	r.HandleMethod(rpcproto.MustResolveMethod(TypesServiceStreamComplexPath), rpcproto.HandleServerStream(srv.StreamComplex))
+}
+
+// TypesServiceClient calls the methods of TypesService, one call at a time.
+type TypesServiceClient struct {
+	s *rpcproto.StreamConn
+}
+
+// NewTypesServiceClient returns a client making calls on s.
+func NewTypesServiceClient(s *rpcproto.StreamConn) *TypesServiceClient {
+	return &TypesServiceClient{s: s}
+}
+
+// ProcessComplex calls /TypesService/ProcessComplex.
+func (c *TypesServiceClient) ProcessComplex(ctx context.Context, req *pb.ComplexRequest) (*pb.ComplexResponse, error) {
+	return rpcproto.CallUnary[*pb.ComplexRequest, *pb.ComplexResponse](ctx, c.s, TypesServiceProcessComplexPath, req)
+}
+
+// StreamComplex calls /TypesService/StreamComplex.
+func (c *TypesServiceClient) StreamComplex(ctx context.Context, req *pb.ComplexRequest) (*rpcproto.RecvStream[*pb.ComplexResponse], error) {
+	return rpcproto.CallServerStream[*pb.ComplexRequest, *pb.ComplexResponse](ctx, c.s, TypesServiceStreamComplexPath, req)
+}
+
+// Method paths of UnaryService, declared in service_unary.proto.
+const (
+	UnaryServicePingPath    = "/UnaryService/Ping"
+	UnaryServiceGetItemPath = "/UnaryService/GetItem"
+	UnaryServiceHealthPath  = "/UnaryService/Health"
+	UnaryServiceEchoPath    = "/UnaryService/Echo"
+)
+
+// UnaryServiceServer is the server side of UnaryService.
+type UnaryServiceServer interface {
+	Ping(context.Context, *pb.PingRequest) (*pb.PingResponse, error)
+	GetItem(context.Context, *pb.GetItemRequest) (*pb.GetItemResponse, error)
+	Health(context.Context, *pb.HealthRequest) (*pb.HealthResponse, error)
+	Echo(context.Context, *pb.EchoMessage) (*pb.EchoMessage, error)
+}
+
+// RegisterUnaryServiceServer registers the methods of srv with r.
+func RegisterUnaryServiceServer(r rpcproto.Registrar, srv UnaryServiceServer) {
+	r.HandleMethod(rpcproto.MustResolveMethod(UnaryServicePingPath), rpcproto.HandleUnary(srv.Ping))
+	r.HandleMethod(rpcproto.MustResolveMethod(UnaryServiceGetItemPath), rpcproto.HandleUnary(srv.GetItem))
+	r.HandleMethod(rpcproto.MustResolveMethod(UnaryServiceHealthPath), rpcproto.HandleUnary(srv.Health))
+	r.HandleMethod(rpcproto.MustResolveMethod(UnaryServiceEchoPath), rpcproto.HandleUnary(srv.Echo))
+}
+
+// UnaryServiceClient calls the methods of UnaryService, one call at a time.
+type UnaryServiceClient struct {
+	s *rpcproto.StreamConn
+}
+
+// NewUnaryServiceClient returns a client making calls on s.
+func NewUnaryServiceClient(s *rpcproto.StreamConn) *UnaryServiceClient {
+	return &UnaryServiceClient{s: s}
+}
+
+// Ping calls /UnaryService/Ping.
+func (c *UnaryServiceClient) Ping(ctx context.Context, req *pb.PingRequest) (*pb.PingResponse, error) {
+	return rpcproto.CallUnary[*pb.PingRequest, *pb.PingResponse](ctx, c.s, UnaryServicePingPath, req)
+}
+
+// GetItem calls /UnaryService/GetItem.
+func (c *UnaryServiceClient) GetItem(ctx context.Context, req *pb.GetItemRequest) (*pb.GetItemResponse, error) {
+	return rpcproto.CallUnary[*pb.GetItemRequest, *pb.GetItemResponse](ctx, c.s, UnaryServiceGetItemPath, req)
+}
+
+// Health calls /UnaryService/Health.
+func (c *UnaryServiceClient) Health(ctx context.Context, req *pb.HealthRequest) (*pb.HealthResponse, error) {
+	return rpcproto.CallUnary[*pb.HealthRequest, *pb.HealthResponse](ctx, c.s, UnaryServiceHealthPath, req)
+}
+
+// Echo calls /UnaryService/Echo.
+func (c *UnaryServiceClient) Echo(ctx context.Context, req *pb.EchoMessage) (*pb.EchoMessage, error) {
+	return rpcproto.CallUnary[*pb.EchoMessage, *pb.EchoMessage](ctx, c.s, UnaryServiceEchoPath, req)
+}
@@ -0,0 +1,57 @@
+// Package pbutil holds protobuf helpers shared by the corpus generator and
+// the RPC harness binaries.
+package pbutil
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// marshalOptions sorts map entries so that encoding the same message twice
+// always yields the same bytes.
+var marshalOptions = proto.MarshalOptions{Deterministic: true}
+
+// partialOptions are marshalOptions that also encode proto2 messages with
+// required fields unset, as some required2 cases deliberately leave them.
+var partialOptions = proto.MarshalOptions{Deterministic: true, AllowPartial: true}
+
+// Marshal encodes m deterministically. All Go-side encoding in the harness
+// goes through it so map ordering never shows up as a cross-run diff.
+func Marshal(m proto.Message) ([]byte, error) {
+	return marshalOptions.Marshal(m)
+}
+
+// MustMarshal is Marshal for messages built into fixed test vectors, which
+// always encode. It panics if m does not.
+func MustMarshal(m proto.Message) []byte {
+	b, err := Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// MarshalPartial is Marshal for messages that may lack required fields.
+func MarshalPartial(m proto.Message) ([]byte, error) {
+	return partialOptions.Marshal(m)
+}
+
+// MarshalAny packs m into a new Any, encoded as MarshalPartial does, so
+// that any corpus message can be packed.
+func MarshalAny(m proto.Message) (*anypb.Any, error) {
+	a := &anypb.Any{}
+	if err := anypb.MarshalFrom(a, m, partialOptions); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// MarshalAppend appends the deterministic encoding of m to b.
+func MarshalAppend(b []byte, m proto.Message) ([]byte, error) {
+	return marshalOptions.MarshalAppend(b, m)
+}
+
+// Size returns the length of m's encoding.
+func Size(m proto.Message) int {
+	return marshalOptions.Size(m)
+}
@@ -0,0 +1,24 @@
+package pbutil
+
+import "google.golang.org/protobuf/encoding/protowire"
+
+// UnknownFields returns encoded fields that no harness schema defines: one of
+// each wire type, including a group, numbered well above the declared
+// fields. Peers that preserve unknown fields must carry these bytes through a
+// decode and re-encode unchanged.
+func UnknownFields() []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1001, protowire.VarintType)
+	b = protowire.AppendVarint(b, 150)
+	b = protowire.AppendTag(b, 1002, protowire.Fixed32Type)
+	b = protowire.AppendFixed32(b, 0xdeadbeef)
+	b = protowire.AppendTag(b, 1003, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, 0x0123456789abcdef)
+	b = protowire.AppendTag(b, 1004, protowire.BytesType)
+	b = protowire.AppendString(b, "unknown")
+	b = protowire.AppendTag(b, 1005, protowire.StartGroupType)
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, 1)
+	b = protowire.AppendTag(b, 1005, protowire.EndGroupType)
+	return b
+}
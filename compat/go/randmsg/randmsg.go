@@ -0,0 +1,154 @@
+// Package randmsg builds random messages of any type from its descriptor.
+// Scalars are biased toward the boundary values where encoders most often
+// disagree. The same rng state always produces the same message, so a seed is
+// enough to reproduce a failure.
+package randmsg
+
+import (
+	"math"
+	"math/rand/v2"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Options controls the shape of generated messages.
+type Options struct {
+	// MaxDepth is how many levels of message fields are filled below the
+	// top-level message. Required message fields are filled regardless.
+	MaxDepth int
+	// MaxRepeat bounds the number of elements in a repeated field or map.
+	MaxRepeat int
+	// MaxString bounds the runes in a string and the bytes in a bytes field.
+	MaxString int
+	// Presence is the probability that a non-required field, or a oneof,
+	// is set. Required fields are always set.
+	Presence float64
+	// UnknownEnums is the probability that a value of an open enum is a
+	// number the enum does not declare.
+	UnknownEnums float64
+}
+
+// Default is a shallow, mostly populated message.
+var Default = Options{MaxDepth: 1, MaxRepeat: 3, MaxString: 24, Presence: 0.75}
+
+// New returns a random message of type md.
+func New(rng *rand.Rand, md protoreflect.MessageDescriptor, o Options) *dynamicpb.Message {
+	m := dynamicpb.NewMessage(md)
+	Fill(rng, m, o)
+	return m
+}
+
+// Fill populates m, which may be a generated or a dynamic message.
+func Fill(rng *rand.Rand, m protoreflect.Message, o Options) {
+	fill(rng, m, o, o.MaxDepth)
+}
+
+func fill(rng *rand.Rand, m protoreflect.Message, o Options, depth int) {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if oneof := fd.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+			// Decide once per oneof, at its first field.
+			if oneof.Fields().Get(0) != fd || rng.Float64() >= o.Presence {
+				continue
+			}
+			fd = oneof.Fields().Get(rng.IntN(oneof.Fields().Len()))
+		} else if fd.Cardinality() != protoreflect.Required && rng.Float64() >= o.Presence {
+			continue
+		}
+		if fd.Message() != nil && depth <= 0 && fd.Cardinality() != protoreflect.Required {
+			continue
+		}
+
+		switch {
+		case fd.IsMap():
+			mp := m.Mutable(fd).Map()
+			for n := rng.IntN(o.MaxRepeat + 1); n > 0; n-- {
+				key := scalar(rng, fd.MapKey(), o).MapKey()
+				mp.Set(key, value(rng, fd.MapValue(), mp.NewValue, o, depth))
+			}
+		case fd.IsList():
+			list := m.Mutable(fd).List()
+			for n := rng.IntN(o.MaxRepeat + 1); n > 0; n-- {
+				list.Append(value(rng, fd, list.NewElement, o, depth))
+			}
+		default:
+			m.Set(fd, value(rng, fd, func() protoreflect.Value { return m.NewField(fd) }, o, depth))
+		}
+	}
+}
+
+func value(rng *rand.Rand, fd protoreflect.FieldDescriptor, newValue func() protoreflect.Value, o Options, depth int) protoreflect.Value {
+	if fd.Message() == nil {
+		return scalar(rng, fd, o)
+	}
+	v := newValue()
+	fill(rng, v.Message(), o, depth-1)
+	return v
+}
+
+var (
+	edgeInt32  = []int32{0, 1, -1, 127, 128, math.MinInt32, math.MaxInt32}
+	edgeInt64  = []int64{0, 1, -1, 1 << 31, -1 << 31, math.MinInt64, math.MaxInt64}
+	edgeUint32 = []uint32{0, 1, 127, 128, 1<<31 - 1, math.MaxUint32}
+	edgeUint64 = []uint64{0, 1, 127, 128, 1<<63 - 1, math.MaxUint64}
+	edgeFloat  = []float64{0, math.Copysign(0, -1), 1, -1, math.Inf(1), math.Inf(-1), math.NaN(),
+		math.MaxFloat32, math.SmallestNonzeroFloat32, math.MaxFloat64, math.SmallestNonzeroFloat64}
+	edgeRunes = []rune{'a', 'Z', '0', ' ', '\n', 0, 0x7f, 0x80, 0x7ff, 0x800, 0xfffd, 0x10000, 0x1f600, 0x10ffff}
+)
+
+func pick[T any](rng *rand.Rand, edge []T, random func() T) T {
+	if rng.IntN(2) == 0 {
+		return edge[rng.IntN(len(edge))]
+	}
+	return random()
+}
+
+func scalar(rng *rand.Rand, fd protoreflect.FieldDescriptor, o Options) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(rng.IntN(2) == 1)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(pick(rng, edgeInt32, func() int32 { return int32(rng.Uint32()) }))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(pick(rng, edgeInt64, func() int64 { return int64(rng.Uint64()) }))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(pick(rng, edgeUint32, rng.Uint32))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(pick(rng, edgeUint64, rng.Uint64))
+	case protoreflect.FloatKind:
+		f := pick(rng, edgeFloat, func() float64 { return float64(math.Float32frombits(rng.Uint32())) })
+		return protoreflect.ValueOfFloat32(float32(f))
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(pick(rng, edgeFloat, func() float64 { return math.Float64frombits(rng.Uint64()) }))
+	case protoreflect.StringKind:
+		var sb strings.Builder
+		for n := rng.IntN(o.MaxString + 1); n > 0; n-- {
+			sb.WriteRune(pick(rng, edgeRunes, func() rune {
+				// Any scalar value: skip the surrogate range.
+				r := rune(rng.IntN(0x10ffff - 0x800))
+				if r >= 0xd800 {
+					r += 0x800
+				}
+				return r
+			}))
+		}
+		return protoreflect.ValueOfString(sb.String())
+	case protoreflect.BytesKind:
+		b := make([]byte, rng.IntN(o.MaxString+1))
+		for i := range b {
+			b[i] = byte(rng.Uint32())
+		}
+		return protoreflect.ValueOfBytes(b)
+	case protoreflect.EnumKind:
+		if !fd.Enum().IsClosed() && rng.Float64() < o.UnknownEnums {
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(1000 + rng.IntN(1000)))
+		}
+		values := fd.Enum().Values()
+		return protoreflect.ValueOfEnum(values.Get(rng.IntN(values.Len())).Number())
+	default:
+		panic("randmsg: unexpected kind " + fd.Kind().String())
+	}
+}
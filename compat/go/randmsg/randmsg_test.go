@@ -0,0 +1,49 @@
+package randmsg_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"compat/pb"
+	"compat/randmsg"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestNewDeterministic(t *testing.T) {
+	md := (&pb.Outer{}).ProtoReflect().Descriptor()
+	for seed := uint64(0); seed < 50; seed++ {
+		a := randmsg.New(rand.New(rand.NewPCG(seed, 0)), md, randmsg.Default)
+		b := randmsg.New(rand.New(rand.NewPCG(seed, 0)), md, randmsg.Default)
+		if !proto.Equal(a, b) {
+			t.Fatalf("seed %d: two messages from the same seed differ", seed)
+		}
+	}
+}
+
+func TestFillLimits(t *testing.T) {
+	o := randmsg.Options{MaxDepth: 1, MaxRepeat: 2, MaxString: 3, Presence: 1}
+	for seed := uint64(0); seed < 50; seed++ {
+		msg := &pb.MapMessage{}
+		randmsg.Fill(rand.New(rand.NewPCG(seed, 0)), msg.ProtoReflect(), o)
+		msg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			switch {
+			case fd.IsMap() && v.Map().Len() > o.MaxRepeat:
+				t.Errorf("seed %d: %s has %d entries", seed, fd.Name(), v.Map().Len())
+			case fd.IsList() && v.List().Len() > o.MaxRepeat:
+				t.Errorf("seed %d: %s has %d elements", seed, fd.Name(), v.List().Len())
+			case fd.Kind() == protoreflect.BytesKind && !fd.IsList() && len(v.Bytes()) > o.MaxString:
+				t.Errorf("seed %d: %s has %d bytes", seed, fd.Name(), len(v.Bytes()))
+			}
+			return true
+		})
+	}
+}
+
+func TestNoPresence(t *testing.T) {
+	msg := randmsg.New(rand.New(rand.NewPCG(1, 0)), (&pb.Outer{}).ProtoReflect().Descriptor(), randmsg.Options{MaxDepth: 3})
+	if proto.Size(msg) != 0 {
+		t.Fatalf("got %v, want an empty message", msg)
+	}
+}
@@ -0,0 +1,413 @@
+package rpcclientlib
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+
+	"compat/acphash"
+	"compat/pb"
+	"compat/pbrpc"
+	"compat/rpcproto"
+	"compat/scenario"
+	"compat/testcases"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// acpHello opens every session that gets past the handshake.
+var acpHello = []scenario.Step{{
+	Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_HELLO, Version: proto.Uint32(1)},
+	Expect: []scenario.Predicate{
+		scenario.Equal("kind", pb.AcpMessageKind_HELLO_STATUS),
+		scenario.Equal("status", pb.AcpStatusCode_OK),
+		scenario.Equal("version", uint32(1)),
+	},
+}}
+
+// acpScenarios returns the ACP flows the session method must follow. They
+// keep state between steps, so each run needs a fresh set.
+func acpScenarios() []*scenario.Scenario {
+	rock := "asset://meshes/rock.bin"
+	return []*scenario.Scenario{
+		{
+			Name:  "handshake",
+			Steps: acpHello,
+		},
+		{
+			Name: "handshake_bad_version",
+			Steps: []scenario.Step{{
+				Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_HELLO, Version: proto.Uint32(99)},
+				Expect: []scenario.Predicate{
+					scenario.Equal("kind", pb.AcpMessageKind_HELLO_STATUS),
+					scenario.Equal("status", pb.AcpStatusCode_BAD_REQUEST),
+				},
+				ExpectEnd: true,
+			}},
+		},
+		{
+			Name: "request_before_hello",
+			Steps: []scenario.Step{{
+				Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_REQUEST, RequestId: 1, Uri: proto.String(rock)},
+				Expect: []scenario.Predicate{
+					scenario.Equal("kind", pb.AcpMessageKind_STATUS),
+					scenario.Equal("status", pb.AcpStatusCode_BAD_REQUEST),
+				},
+				ExpectEnd: true,
+			}},
+		},
+		{
+			Name: "discover",
+			Steps: append(slices.Clone(acpHello), scenario.Step{
+				Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_DISCOVER_REQUEST, RequestId: 2},
+				Expect: []scenario.Predicate{
+					scenario.Equal("kind", pb.AcpMessageKind_DISCOVER),
+					scenario.Equal("request_id", uint64(2)),
+					scenario.Func("uris lists "+rock, func(got proto.Message) bool {
+						return slices.Contains(got.(*pb.AcpMessage).Uris, rock)
+					}),
+				},
+			}),
+		},
+		{
+			Name: "request_not_found",
+			Steps: append(slices.Clone(acpHello), scenario.Step{
+				Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_REQUEST, RequestId: 3, Uri: proto.String("asset://missing")},
+				Expect: []scenario.Predicate{
+					scenario.Equal("kind", pb.AcpMessageKind_STATUS),
+					scenario.Equal("status", pb.AcpStatusCode_NOT_FOUND),
+					scenario.Equal("uri", "asset://missing"),
+				},
+			}),
+		},
+		acpFetch("stream_gzip_asset", rock, 4),
+		acpFetch("stream_chunked_asset", "asset://textures/noise.bin", 5),
+		acpRegistryScenario(),
+		acpBadUploadScenario(),
+		{
+			Name: "deload",
+			Steps: append(slices.Clone(acpHello), scenario.Step{
+				Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_DELOAD, RequestId: 6, Uris: []string{rock}},
+				Expect: []scenario.Predicate{
+					scenario.Equal("kind", pb.AcpMessageKind_DELETED),
+					scenario.Func("uris == ["+rock+"]", func(got proto.Message) bool {
+						return slices.Equal(got.(*pb.AcpMessage).Uris, []string{rock})
+					}),
+				},
+			}),
+		},
+	}
+}
+
+// acpFetch asks for the status of uri and, if the asset exists, streams it:
+// UPDATED chunks in order, then READY, after which the reassembled payload
+// must match the metadata's hash and length.
+func acpFetch(name, uri string, id uint64) *scenario.Scenario {
+	var (
+		meta  *pb.AcpAssetMetadata
+		chunk bytes.Buffer
+		next  uint32
+	)
+	steps := append(slices.Clone(acpHello),
+		scenario.Step{
+			Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_STATUS_REQUEST, RequestId: id, Uri: proto.String(uri)},
+			Expect: []scenario.Predicate{
+				scenario.Equal("kind", pb.AcpMessageKind_STATUS),
+				scenario.Equal("request_id", id),
+				scenario.OneOf("status", pb.AcpStatusCode_OK, pb.AcpStatusCode_NOT_FOUND),
+			},
+			Branch: scenario.Switch("status", map[any]string{pb.AcpStatusCode_NOT_FOUND: "done"}),
+		},
+		scenario.Step{
+			Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_REQUEST, RequestId: id, Uri: proto.String(uri)},
+		},
+		scenario.Step{
+			Label: "chunk",
+			Expect: []scenario.Predicate{
+				scenario.Equal("request_id", id),
+				scenario.OneOf("kind", pb.AcpMessageKind_UPDATED, pb.AcpMessageKind_READY),
+				scenario.Func("chunks arrive in order", func(got proto.Message) bool {
+					msg := got.(*pb.AcpMessage)
+					if msg.Kind != pb.AcpMessageKind_UPDATED {
+						return true
+					}
+					if msg.ChunkIndex != next || msg.ChunkIndex >= msg.ChunkTotal {
+						return false
+					}
+					if msg.Metadata != nil {
+						meta = msg.Metadata
+					}
+					chunk.Write(msg.PayloadChunk)
+					next++
+					return true
+				}),
+				scenario.Func("READY metadata matches the reassembled payload", func(got proto.Message) bool {
+					msg := got.(*pb.AcpMessage)
+					if msg.Kind != pb.AcpMessageKind_READY {
+						return true
+					}
+					if meta == nil || !proto.Equal(meta, msg.Metadata) {
+						return false
+					}
+					_, err := testcases.VerifyAcpPayload(&pb.AcpMessage{Metadata: meta, PayloadChunk: chunk.Bytes()})
+					return err == nil
+				}),
+			},
+			Branch: scenario.Switch("kind", map[any]string{pb.AcpMessageKind_UPDATED: "chunk"}),
+		},
+		scenario.Step{Label: "done"},
+	)
+	return &scenario.Scenario{Name: name, Steps: steps}
+}
+
+// acpUploadSteps sends data as UPDATED chunks of 256 bytes for request id.
+// The last step reads the server's verdict, which must pass expect.
+func acpUploadSteps(id uint64, meta *pb.AcpAssetMetadata, data []byte, expect ...scenario.Predicate) []scenario.Step {
+	msgs, err := acpUpload(id, meta, data, 256)
+	if err != nil {
+		panic(fmt.Sprintf("rpcclientlib: upload %s: %v", meta.Uri, err))
+	}
+	steps := make([]scenario.Step, len(msgs))
+	for i, msg := range msgs {
+		steps[i].Send = msg
+	}
+	steps[len(steps)-1].Expect = append([]scenario.Predicate{scenario.Equal("request_id", id)}, expect...)
+	return steps
+}
+
+// acpRegistryScenario walks one asset through the session's registry: not
+// found, uploaded, fetched, replaced, recooked and evicted, checking that
+// each step's metadata reflects the ones before.
+func acpRegistryScenario() *scenario.Scenario {
+	const uri = "asset://sounds/chime.raw"
+	v1 := bytes.Repeat([]byte("chime "), 100)
+	v2 := bytes.Repeat([]byte("CHIME!"), 200)
+	meta := func(data []byte, c pb.AcpCompression) *pb.AcpAssetMetadata {
+		return &pb.AcpAssetMetadata{Uri: uri, PayloadHash: acphash.Sum(data), FileLength: int64(len(data)), Compression: c}
+	}
+	// updatedAt remembers the last updated_at_ns seen, which every change
+	// must advance.
+	var updatedAt int64
+	advances := scenario.Func("updated_at_ns advances", func(got proto.Message) bool {
+		t := got.(*pb.AcpMessage).GetMetadata().GetUpdatedAtNs()
+		ok := t > updatedAt
+		updatedAt = t
+		return ok
+	})
+
+	steps := slices.Clone(acpHello)
+	steps = append(steps, scenario.Step{
+		Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_REQUEST, RequestId: 20, Uri: proto.String(uri)},
+		Expect: []scenario.Predicate{
+			scenario.Equal("kind", pb.AcpMessageKind_STATUS),
+			scenario.Equal("status", pb.AcpStatusCode_NOT_FOUND),
+		},
+	})
+	steps = append(steps, acpUploadSteps(21, meta(v1, pb.AcpCompression_UNCOMPRESSED), v1,
+		scenario.Equal("kind", pb.AcpMessageKind_READY),
+		scenario.Equal("metadata.uri_version", int64(1)),
+		scenario.Equal("metadata.payload_hash", acphash.Sum(v1)),
+		advances,
+	)...)
+	steps = append(steps,
+		scenario.Step{
+			Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_REQUEST, RequestId: 22, Uri: proto.String(uri)},
+			Expect: []scenario.Predicate{
+				scenario.Equal("kind", pb.AcpMessageKind_UPDATED),
+				scenario.Equal("chunk_total", uint32(1)),
+				scenario.Func("payload_chunk is the upload", func(got proto.Message) bool {
+					return bytes.Equal(got.(*pb.AcpMessage).PayloadChunk, v1)
+				}),
+			},
+		},
+		scenario.Step{
+			Expect: []scenario.Predicate{
+				scenario.Equal("kind", pb.AcpMessageKind_READY),
+				scenario.Equal("metadata.uri_version", int64(1)),
+			},
+		},
+	)
+	steps = append(steps, acpUploadSteps(23, meta(v2, pb.AcpCompression_GZIP), v2,
+		scenario.Equal("kind", pb.AcpMessageKind_READY),
+		scenario.Equal("metadata.uri_version", int64(2)),
+		scenario.Equal("metadata.payload_hash", acphash.Sum(v2)),
+		scenario.Equal("metadata.file_length", int64(len(v2))),
+		advances,
+	)...)
+	steps = append(steps,
+		scenario.Step{
+			Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_REQUEST, RequestId: 24, Uri: proto.String(uri), ForceRecook: proto.Bool(true)},
+			Expect: []scenario.Predicate{
+				scenario.Equal("kind", pb.AcpMessageKind_UPDATED),
+				scenario.Equal("metadata.uri_version", int64(3)),
+				advances,
+			},
+		},
+		scenario.Step{
+			Expect: []scenario.Predicate{
+				scenario.Equal("kind", pb.AcpMessageKind_READY),
+				scenario.Equal("metadata.uri_version", int64(3)),
+				scenario.Equal("metadata.payload_hash", acphash.Sum(v2)),
+			},
+		},
+		scenario.Step{
+			Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_DISCOVER_REQUEST, RequestId: 25},
+			Expect: []scenario.Predicate{
+				scenario.Func("uris lists "+uri, func(got proto.Message) bool {
+					return slices.Contains(got.(*pb.AcpMessage).Uris, uri)
+				}),
+			},
+		},
+		scenario.Step{
+			Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_DELOAD, RequestId: 26, Uris: []string{uri, "asset://missing"}},
+			Expect: []scenario.Predicate{
+				scenario.Equal("kind", pb.AcpMessageKind_DELETED),
+				scenario.Func("uris == ["+uri+"]", func(got proto.Message) bool {
+					return slices.Equal(got.(*pb.AcpMessage).Uris, []string{uri})
+				}),
+			},
+		},
+		scenario.Step{
+			Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_STATUS_REQUEST, RequestId: 27, Uri: proto.String(uri)},
+			Expect: []scenario.Predicate{
+				scenario.Equal("status", pb.AcpStatusCode_NOT_FOUND),
+			},
+		},
+	)
+	return &scenario.Scenario{Name: "registry_lifecycle", Steps: steps}
+}
+
+// acpBadUploadScenario uploads an asset whose payload_hash is wrong, which
+// the session must refuse without registering it.
+func acpBadUploadScenario() *scenario.Scenario {
+	const uri = "asset://sounds/broken.raw"
+	data := bytes.Repeat([]byte{0x5a}, 600)
+	meta := &pb.AcpAssetMetadata{Uri: uri, PayloadHash: acphash.Sum(data[1:]), FileLength: int64(len(data))}
+	steps := append(slices.Clone(acpHello), acpUploadSteps(30, meta, data,
+		scenario.Equal("kind", pb.AcpMessageKind_STATUS),
+		scenario.Equal("status", pb.AcpStatusCode_BAD_REQUEST),
+	)...)
+	steps = append(steps, scenario.Step{
+		Send: &pb.AcpMessage{Kind: pb.AcpMessageKind_STATUS_REQUEST, RequestId: 31, Uri: proto.String(uri)},
+		Expect: []scenario.Predicate{
+			scenario.Equal("status", pb.AcpStatusCode_NOT_FOUND),
+		},
+	})
+	return &scenario.Scenario{Name: "upload_bad_hash", Steps: steps}
+}
+
+// testAcpScenarios plays every ACP scenario on its own session.
+func testAcpScenarios(ctx context.Context, c *Client) int {
+	failures := 0
+	for _, sc := range acpScenarios() {
+		sc.Method, sc.Recv = pbrpc.HarnessServiceAcpSessionPath, &pb.AcpMessage{}
+		if err := scenario.Run(ctx, c.StreamConn, sc); err != nil {
+			fmt.Fprintf(c.Log, "FAIL AcpSession %v\n", err)
+			failures++
+			if c.State() != rpcproto.StreamClosed || ctx.Err() != nil {
+				return failures
+			}
+		}
+	}
+	return failures
+}
+
+// acpUpload splits data, compressed as meta says, into UPDATED chunks of at
+// most size bytes for request id, with meta on the first.
+func acpUpload(id uint64, meta *pb.AcpAssetMetadata, data []byte, size int) ([]*pb.AcpMessage, error) {
+	chunk, err := testcases.CompressAcpPayload(meta.Compression, data)
+	if err != nil {
+		return nil, err
+	}
+	total := max((len(chunk)+size-1)/size, 1)
+	msgs := make([]*pb.AcpMessage, total)
+	for i := range msgs {
+		msgs[i] = &pb.AcpMessage{
+			Kind:         pb.AcpMessageKind_UPDATED,
+			RequestId:    id,
+			Uri:          proto.String(meta.Uri),
+			ChunkIndex:   uint32(i),
+			ChunkTotal:   uint32(total),
+			PayloadChunk: chunk[i*size : min((i+1)*size, len(chunk))],
+		}
+	}
+	msgs[0].Metadata = meta
+	return msgs, nil
+}
+
+// testAcpVerify uploads assets to Verify, intact and damaged in each way it
+// must notice, and checks its verdicts.
+func testAcpVerify(ctx context.Context, c *Client) int {
+	asset := testcases.AcpAsset()
+	meta := func(comp pb.AcpCompression) *pb.AcpAssetMetadata {
+		return &pb.AcpAssetMetadata{
+			Uri:         "asset://meshes/rock.bin",
+			PayloadHash: acphash.Sum(asset),
+			FileLength:  int64(len(asset)),
+			Compression: comp,
+		}
+	}
+	tests := []struct {
+		name   string
+		meta   *pb.AcpAssetMetadata
+		damage func([]*pb.AcpMessage)
+		want   pb.AcpStatusCode
+	}{
+		{"uncompressed", meta(pb.AcpCompression_UNCOMPRESSED), nil, pb.AcpStatusCode_OK},
+		{"gzip", meta(pb.AcpCompression_GZIP), nil, pb.AcpStatusCode_OK},
+		{"zstd", meta(pb.AcpCompression_ZSTD), nil, pb.AcpStatusCode_OK},
+		{"flipped byte", meta(pb.AcpCompression_UNCOMPRESSED), func(m []*pb.AcpMessage) {
+			m[1].PayloadChunk = append([]byte{m[1].PayloadChunk[0] ^ 1}, m[1].PayloadChunk[1:]...)
+		}, pb.AcpStatusCode_BAD_REQUEST},
+		{"wrong hash", meta(pb.AcpCompression_GZIP), func(m []*pb.AcpMessage) {
+			m[0].Metadata = proto.Clone(m[0].Metadata).(*pb.AcpAssetMetadata)
+			m[0].Metadata.PayloadHash = acphash.Sum(nil)
+		}, pb.AcpStatusCode_BAD_REQUEST},
+		{"malformed hash", meta(pb.AcpCompression_UNCOMPRESSED), func(m []*pb.AcpMessage) {
+			m[0].Metadata = proto.Clone(m[0].Metadata).(*pb.AcpAssetMetadata)
+			m[0].Metadata.PayloadHash = "sha256:deadbeef"
+		}, pb.AcpStatusCode_BAD_REQUEST},
+		{"wrong length", meta(pb.AcpCompression_UNCOMPRESSED), func(m []*pb.AcpMessage) {
+			m[0].Metadata = proto.Clone(m[0].Metadata).(*pb.AcpAssetMetadata)
+			m[0].Metadata.FileLength++
+		}, pb.AcpStatusCode_BAD_REQUEST},
+		{"chunks swapped", meta(pb.AcpCompression_UNCOMPRESSED), func(m []*pb.AcpMessage) {
+			m[1], m[2] = m[2], m[1]
+		}, pb.AcpStatusCode_BAD_REQUEST},
+	}
+	failures := 0
+	for _, tt := range tests {
+		msgs, err := acpUpload(7, tt.meta, asset, 1024)
+		if err != nil {
+			fmt.Fprintf(c.Log, "FAIL AcpVerify %s: %v\n", tt.name, err)
+			return failures + 1
+		}
+		if tt.damage != nil {
+			tt.damage(msgs)
+		}
+		reply, err := acpVerifyCall(ctx, c, msgs)
+		if err != nil {
+			fmt.Fprintf(c.Log, "FAIL AcpVerify %s: %v\n", tt.name, err)
+			return failures + 1
+		}
+		if reply.GetStatus() != tt.want {
+			fmt.Fprintf(c.Log, "FAIL AcpVerify %s: status %v (%s), want %v\n", tt.name, reply.GetStatus(), reply.GetDetail(), tt.want)
+			failures++
+		}
+	}
+	return min(failures, 1)
+}
+
+func acpVerifyCall(ctx context.Context, c *Client, msgs []*pb.AcpMessage) (*pb.AcpMessage, error) {
+	stream, err := pbrpc.NewAcpServiceClient(c.StreamConn).Verify(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, msg := range msgs {
+		if err := stream.Send(msg); err != nil {
+			return nil, fmt.Errorf("write chunk: %w", err)
+		}
+	}
+	return stream.CloseAndRecv()
+}
@@ -0,0 +1,174 @@
+// Package rpcclientlib implements the reference pipe RPC client and its
+// conformance tests, so that Go tests and tools can drive a server
+// in-process as well as through cmd/rpcclient.
+package rpcclientlib
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"compat/pb"
+	"compat/rpcproto"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Test runs one conformance check against the server and returns the number
+// of failures, having described each on the client's Log.
+type Test func(context.Context, *Client) int
+
+// Suite is a group of tests that can be selected by name.
+type Suite struct {
+	Tests []Test
+	// Handshake means the suite relies on settings negotiated by HELLO,
+	// which peers that predate it do not understand.
+	Handshake bool
+	// BinaryOnly means the suite checks details of the binary encoding, so
+	// it cannot run over a connection that negotiated another codec.
+	BinaryOnly bool
+}
+
+// Suites maps suite names to tests. "core" uses only the methods of
+// UnaryService and StreamingService; the others add those of HarnessService,
+// which only the reference servers implement. "validate" passes only against a server enforcing the
+// reference validation rules (rpcserver -validate).
+var Suites = map[string]Suite{
+	"core": {Tests: []Test{
+		testPing,
+		testGetItem,
+		testHealth,
+		testEcho,
+		testServerSide,
+		testClientSide,
+		testBidirectional,
+	}},
+	"flow": {Tests: []Test{
+		testFirehose,
+	}, Handshake: true},
+	"errors": {Tests: []Test{
+		testFailAfterN,
+	}},
+	"limits": {Tests: []Test{
+		testFrameLimits,
+		testChunkedUpload,
+	}, Handshake: true, BinaryOnly: true},
+	"property": {Tests: []Test{
+		testPropertyEchoScalar,
+		testPropertyEcho,
+	}},
+	"stats": {Tests: []Test{
+		testStats,
+	}, BinaryOnly: true},
+	"any": {Tests: []Test{
+		testEchoAny,
+	}},
+	"unknown": {Tests: []Test{
+		testUnknownFields,
+	}, BinaryOnly: true},
+	"acp": {Tests: []Test{
+		testAcpScenarios,
+		testAcpVerify,
+	}},
+	"validate": {Tests: []Test{
+		testValidation,
+	}},
+}
+
+// DefaultSettings are the settings the client advertises in its HELLO unless
+// told otherwise.
+var DefaultSettings = rpcproto.Settings{InitialWindow: rpcproto.DefaultWindow, MaxFrameSize: 4 << 20}
+
+// Client is one end of a pipe RPC connection driven by the conformance tests.
+type Client struct {
+	*rpcproto.StreamConn
+
+	// Log receives a line for each failed check. NewClient sets it to
+	// os.Stderr.
+	Log io.Writer
+	// ReadDelay is the pause before handling each Firehose chunk, to
+	// simulate a slow reader.
+	ReadDelay time.Duration
+	// Seed is the first seed for the property suite; zero derives one from
+	// the clock and logs it.
+	Seed int64
+	// Iterations is the number of random payloads per property test.
+	Iterations int
+
+	counters *clientCounters
+}
+
+// NewClient returns a Client speaking the protocol over rw.
+func NewClient(rw io.ReadWriter) *Client {
+	conn := rpcproto.NewConn(rw, rw)
+	return &Client{
+		StreamConn: rpcproto.NewStreamConn(conn),
+		Log:        os.Stderr,
+		ReadDelay:  time.Millisecond,
+		Iterations: 200,
+		counters:   newClientCounters(conn),
+	}
+}
+
+// Unary makes a unary call and returns the RESPONSE payload.
+func (c *Client) Unary(ctx context.Context, method string, req proto.Message) ([]byte, error) {
+	reqBytes, err := c.Codec().Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	if err := c.Call(ctx, method, reqBytes, false); err != nil {
+		return nil, fmt.Errorf("write call: %w", err)
+	}
+	respBytes, err := c.RecvResponse(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	return respBytes, nil
+}
+
+// Run runs tests in order and returns the total number of failures. It stops
+// early if ctx is done or a test leaves a call unfinished.
+func (c *Client) Run(ctx context.Context, tests []Test) int {
+	failures := 0
+	for _, test := range tests {
+		if ctx.Err() != nil {
+			break
+		}
+		failures += test(ctx, c)
+		if st := c.State(); st != rpcproto.StreamClosed && ctx.Err() == nil {
+			// A test gave up mid-call; the frames still in flight would be
+			// misread by the next one.
+			fmt.Fprintf(c.Log, "connection left %s, skipping remaining tests\n", st)
+			failures++
+			break
+		}
+		if c.GoingAway() {
+			fmt.Fprintf(c.Log, "server sent GOAWAY, skipping remaining tests\n")
+			failures++
+			break
+		}
+	}
+	return failures
+}
+
+// Shutdown sends SHUTDOWN. A server that handshook answers with its final
+// report, which Shutdown returns; otherwise the report is nil. After GOAWAY
+// the server closes the connection itself, so Shutdown does nothing.
+func (c *Client) Shutdown(ctx context.Context) (*pb.StatsReport, error) {
+	if c.GoingAway() {
+		return nil, nil
+	}
+	if err := c.W.WriteShutdown(); err != nil {
+		return nil, fmt.Errorf("write shutdown: %w", err)
+	}
+	if !c.Negotiated() {
+		return nil, nil
+	}
+	frame, err := c.R.ReadFrameContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read final stats: %w", err)
+	}
+	return parseStats(frame)
+}
@@ -0,0 +1,508 @@
+package rpcclientlib_test
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"compat/pb"
+	"compat/pbrpc"
+	"compat/pbutil"
+	"compat/rpcclientlib"
+	"compat/rpcproto"
+	"compat/rpcserverlib"
+	"compat/testca"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// testLog sends client failure lines to the test log.
+type testLog struct{ t *testing.T }
+
+func (l testLog) Write(b []byte) (int, error) {
+	l.t.Log(strings.TrimSuffix(string(b), "\n"))
+	return len(b), nil
+}
+
+// runSuite runs one named suite against an in-process reference server over
+// a pipe shaped by opts. The client sends HELLO with settings if the suite
+// needs a handshake or settings differ from the defaults. The server applies
+// the default validation rules, which the other suites' requests must pass.
+func runSuite(t *testing.T, name string, opts rpcproto.PipeOptions, settings rpcproto.Settings) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	clientEnd, serverEnd := rpcproto.ShapedPipe(opts)
+	served := make(chan error, 1)
+	go func() {
+		srv := &rpcserverlib.Server{
+			Registry:  rpcserverlib.DefaultRegistry(),
+			Settings:  rpcserverlib.DefaultSettings,
+			Log:       io.Discard,
+			Validator: rpcserverlib.DefaultValidator(),
+		}
+		served <- srv.Serve(ctx, serverEnd)
+		serverEnd.Close()
+	}()
+
+	suite := rpcclientlib.Suites[name]
+	c := rpcclientlib.NewClient(clientEnd)
+	c.Log = testLog{t}
+	c.Seed = 1
+	c.Iterations = 20
+	c.ReadDelay = 0
+	if suite.Handshake || settings != rpcclientlib.DefaultSettings {
+		if err := c.Hello(ctx, settings); err != nil {
+			t.Fatalf("handshake: %v", err)
+		}
+		if settings.Features&rpcproto.FeatureSequence != 0 && !c.Sequenced() {
+			t.Fatal("server did not take up sequence numbers")
+		}
+	}
+	if failures := c.Run(ctx, suite.Tests); failures > 0 {
+		t.Errorf("%d failure(s)", failures)
+	}
+	if _, err := c.Shutdown(ctx); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("server: %v", err)
+	}
+	clientEnd.Close()
+}
+
+func suiteNames() []string {
+	var names []string
+	for name := range rpcclientlib.Suites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSuites(t *testing.T) {
+	for _, name := range suiteNames() {
+		t.Run(name, func(t *testing.T) {
+			runSuite(t, name, rpcproto.PipeOptions{}, rpcclientlib.DefaultSettings)
+		})
+	}
+}
+
+// TestSuitesSequenced runs every suite with numbered frames, which must not
+// change any result.
+func TestSuitesSequenced(t *testing.T) {
+	settings := rpcclientlib.DefaultSettings
+	settings.Features = rpcproto.FeatureSequence
+	for _, name := range suiteNames() {
+		t.Run(name, func(t *testing.T) {
+			runSuite(t, name, rpcproto.PipeOptions{}, settings)
+		})
+	}
+}
+
+// TestSuitesCodecs runs every suite that does not depend on the binary
+// encoding with JSON and text payloads.
+func TestSuitesCodecs(t *testing.T) {
+	for _, codecName := range []string{"json", "text"} {
+		codec, _ := rpcproto.CodecByName(codecName)
+		settings := rpcclientlib.DefaultSettings
+		settings.Codec = codec.ID()
+		for _, name := range suiteNames() {
+			if rpcclientlib.Suites[name].BinaryOnly {
+				continue
+			}
+			t.Run(codecName+"/"+name, func(t *testing.T) {
+				runSuite(t, name, rpcproto.PipeOptions{}, settings)
+			})
+		}
+	}
+}
+
+func TestSuitesShaped(t *testing.T) {
+	if testing.Short() {
+		t.Skip("slow link")
+	}
+	// A slow, high-latency link changes how frames interleave without
+	// changing any result.
+	opts := rpcproto.PipeOptions{Latency: 2 * time.Millisecond, Bandwidth: 64 << 20}
+	for _, name := range []string{"core", "flow", "errors"} {
+		t.Run(name, func(t *testing.T) {
+			runSuite(t, name, opts, rpcclientlib.DefaultSettings)
+		})
+	}
+}
+
+// TestConcurrentClients runs many clients at once against one listening
+// server, each on its own connection with its own call state.
+func TestConcurrentClients(t *testing.T) {
+	const clients = 32
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	ln, err := rpcproto.Listen("tcp:127.0.0.1:0")
+	if err != nil {
+		t.Skipf("no loopback: %v", err)
+	}
+	srv := &rpcserverlib.Server{
+		Registry: rpcserverlib.DefaultRegistry(),
+		Settings: rpcserverlib.DefaultSettings,
+		Stats:    rpcserverlib.NewStats(),
+	}
+	srvCtx, stopServer := context.WithCancel(ctx)
+	served := make(chan error, 1)
+	go func() { served <- srv.ServeListener(srvCtx, ln) }()
+
+	var tests []rpcclientlib.Test
+	for _, name := range []string{"core", "errors", "flow"} {
+		tests = append(tests, rpcclientlib.Suites[name].Tests...)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, err := rpcproto.Dial(ctx, ln.Addr().String())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer conn.Close()
+			c := rpcclientlib.NewClient(conn)
+			c.Log = testLog{t}
+			c.ReadDelay = 0
+			if err := c.Hello(ctx, rpcclientlib.DefaultSettings); err != nil {
+				t.Errorf("client %d handshake: %v", i, err)
+				return
+			}
+			if failures := c.Run(ctx, tests); failures > 0 {
+				t.Errorf("client %d: %d failure(s)", i, failures)
+			}
+			if _, err := c.Shutdown(ctx); err != nil {
+				t.Errorf("client %d shutdown: %v", i, err)
+			}
+		}()
+	}
+	wg.Wait()
+	stopServer()
+	<-served
+
+	report := srv.Stats.Report()
+	var calls uint64
+	for _, m := range report.Methods {
+		calls += m.Calls
+	}
+	// core makes 7 calls, FailAfterN 3 and Firehose 1.
+	if want := uint64(clients * 11); calls != want {
+		t.Errorf("server saw %d calls, want %d", calls, want)
+	}
+	if report.MaxConcurrentStreams < 2 {
+		t.Logf("max concurrent streams %d; clients did not overlap", report.MaxConcurrentStreams)
+	}
+}
+
+// TestClientStats checks the client's counters against the server's final
+// report for the same connection: each side must count the other's frames.
+func TestClientStats(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	clientEnd, serverEnd := rpcproto.Pipe()
+	defer clientEnd.Close()
+	go func() {
+		rpcserverlib.Serve(ctx, serverEnd, rpcserverlib.DefaultRegistry())
+		serverEnd.Close()
+	}()
+
+	c := rpcclientlib.NewClient(clientEnd)
+	c.Log = testLog{t}
+	if err := c.Hello(ctx, rpcclientlib.DefaultSettings); err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+	var tests []rpcclientlib.Test
+	for _, name := range []string{"core", "errors"} {
+		tests = append(tests, rpcclientlib.Suites[name].Tests...)
+	}
+	if failures := c.Run(ctx, tests); failures > 0 {
+		t.Fatalf("%d failure(s)", failures)
+	}
+	report, err := c.Shutdown(ctx)
+	if err != nil || report == nil {
+		t.Fatalf("shutdown: %v, report %v", err, report)
+	}
+	st := c.ClientStats()
+
+	sum := func(m map[string]uint64) (n uint64) {
+		for _, v := range m {
+			n += v
+		}
+		return n
+	}
+	// The final report was taken before the server sent it, so it is the
+	// one frame the client has that the server has not counted.
+	finalSize := uint64(5 + proto.Size(report))
+	if got := sum(st.FramesSent); got != report.FramesIn {
+		t.Errorf("client sent %d frames, server read %d", got, report.FramesIn)
+	}
+	if got := sum(st.FramesReceived) - 1; got != report.FramesOut {
+		t.Errorf("client read %d frames before the report, server wrote %d", got, report.FramesOut)
+	}
+	if st.BytesSent != report.BytesIn {
+		t.Errorf("client sent %d bytes, server read %d", st.BytesSent, report.BytesIn)
+	}
+	if got := st.BytesReceived - finalSize; got != report.BytesOut {
+		t.Errorf("client read %d bytes before the report, server wrote %d", got, report.BytesOut)
+	}
+	if st.FramesSent["SHUTDOWN"] != 1 || st.FramesReceived["STATS"] != 1 {
+		t.Errorf("frames sent %v, received %v", st.FramesSent, st.FramesReceived)
+	}
+
+	for _, m := range report.Methods {
+		var got *rpcclientlib.MethodLatency
+		for i := range st.Methods {
+			if st.Methods[i].Method == m.Method {
+				got = &st.Methods[i]
+			}
+		}
+		if got == nil {
+			t.Errorf("%s: no client latency", m.Method)
+			continue
+		}
+		if got.Calls != m.Calls || got.Errors != m.Errors {
+			t.Errorf("%s: client counted %d calls, %d errors; server %d, %d", m.Method, got.Calls, got.Errors, m.Calls, m.Errors)
+		}
+		if got.Min < 0 || got.Min > got.Mean() || got.Mean() > got.Max {
+			t.Errorf("%s: latency min %v mean %v max %v", m.Method, got.Min, got.Mean(), got.Max)
+		}
+	}
+}
+
+// TestWebSocket runs suites over the WebSocket transport, whose messages
+// must carry the frame stream unchanged through flow control and errors.
+func TestWebSocket(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ln, err := rpcproto.Listen("ws:127.0.0.1:0/rpc")
+	if err != nil {
+		t.Skipf("no loopback: %v", err)
+	}
+	srv := &rpcserverlib.Server{Registry: rpcserverlib.DefaultRegistry(), Settings: rpcserverlib.DefaultSettings}
+	srvCtx, stopServer := context.WithCancel(ctx)
+	served := make(chan error, 1)
+	go func() { served <- srv.ServeListener(srvCtx, ln) }()
+
+	conn, err := rpcproto.Dial(ctx, "ws:"+ln.Addr().String()+"/rpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := rpcclientlib.NewClient(conn)
+	c.Log = testLog{t}
+	c.ReadDelay = 0
+	if err := c.Hello(ctx, rpcclientlib.DefaultSettings); err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+	var tests []rpcclientlib.Test
+	for _, name := range []string{"core", "flow", "errors", "limits"} {
+		tests = append(tests, rpcclientlib.Suites[name].Tests...)
+	}
+	if failures := c.Run(ctx, tests); failures > 0 {
+		t.Errorf("%d failure(s)", failures)
+	}
+	if _, err := c.Shutdown(ctx); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+	stopServer()
+	<-served
+}
+
+// TestMutualTLS runs suites over TCP under mutual TLS. The limits suite
+// sends frames far larger than a TLS record, so framing must survive frames
+// split and joined across records. A client without a certificate is
+// turned away.
+func TestMutualTLS(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	dir := t.TempDir()
+	bundle, err := testca.New("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bundle.WriteDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	file := func(name string) string { return filepath.Join(dir, name) }
+	serverCfg, err := rpcproto.ServerTLS(file(testca.Files.ServerCert), file(testca.Files.ServerKey), file(testca.Files.CACert))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := rpcproto.Listen("tcp:127.0.0.1:0")
+	if err != nil {
+		t.Skipf("no loopback: %v", err)
+	}
+	srv := &rpcserverlib.Server{Registry: rpcserverlib.DefaultRegistry(), Settings: rpcserverlib.DefaultSettings, Log: io.Discard}
+	srvCtx, stopServer := context.WithCancel(ctx)
+	served := make(chan error, 1)
+	go func() { served <- srv.ServeListener(srvCtx, tls.NewListener(ln, serverCfg)) }()
+	defer func() {
+		stopServer()
+		<-served
+	}()
+	addr := "tcp:" + ln.Addr().String()
+
+	anonCfg, err := rpcproto.ClientTLS("", "", file(testca.Files.CACert))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn, err := rpcproto.DialTLS(ctx, addr, anonCfg); err == nil {
+		// TLS 1.3 reports the missing certificate on the first read.
+		c := rpcclientlib.NewClient(conn)
+		if err := c.Hello(ctx, rpcclientlib.DefaultSettings); err == nil {
+			t.Error("server accepted a client without a certificate")
+		}
+		conn.Close()
+	}
+
+	clientCfg, err := rpcproto.ClientTLS(file(testca.Files.ClientCert), file(testca.Files.ClientKey), file(testca.Files.CACert))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := rpcproto.DialTLS(ctx, addr, clientCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := rpcclientlib.NewClient(conn)
+	c.Log = testLog{t}
+	c.ReadDelay = 0
+	if err := c.Hello(ctx, rpcclientlib.DefaultSettings); err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+	var tests []rpcclientlib.Test
+	for _, name := range []string{"core", "flow", "limits"} {
+		tests = append(tests, rpcclientlib.Suites[name].Tests...)
+	}
+	if failures := c.Run(ctx, tests); failures > 0 {
+		t.Errorf("%d failure(s)", failures)
+	}
+	if _, err := c.Shutdown(ctx); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+}
+
+// TestGoAwayMidStream drains the server while a Firehose is in flight. The
+// stream must still run to completion, after which new calls are refused and
+// the server closes the connection.
+func TestGoAwayMidStream(t *testing.T) {
+	const count, chunkSize = 256, 1024
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	clientEnd, serverEnd := rpcproto.Pipe()
+	srv := &rpcserverlib.Server{Registry: rpcserverlib.DefaultRegistry(), Settings: rpcserverlib.DefaultSettings}
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(ctx, serverEnd)
+		serverEnd.Close()
+	}()
+
+	c := rpcclientlib.NewClient(clientEnd)
+	if err := c.Hello(ctx, rpcclientlib.DefaultSettings); err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+	reqBytes, err := pbutil.Marshal(&pb.FirehoseRequest{Count: count, ChunkSize: chunkSize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Call(ctx, pbrpc.HarnessServiceFirehosePath, reqBytes, false); err != nil {
+		t.Fatal(err)
+	}
+	// The window holds the server back well short of the end of the stream,
+	// so the GOAWAY lands in the middle of it.
+	for i := 0; i < count; i++ {
+		payload, err := c.RecvMsg(ctx)
+		if err != nil {
+			t.Fatalf("chunk %d: %v", i, err)
+		}
+		if i == 0 {
+			srv.Drain()
+		}
+		if err := c.Consume(len(payload)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.RecvMsg(ctx); err != io.EOF {
+		t.Fatalf("end of stream: err = %v, want io.EOF", err)
+	}
+	if !c.GoingAway() {
+		t.Fatal("no GOAWAY received")
+	}
+	if _, err := c.Unary(ctx, "/UnaryService/Ping", &pb.PingRequest{}); !errors.Is(err, rpcproto.ErrGoAway) {
+		t.Fatalf("call after GOAWAY: err = %v, want ErrGoAway", err)
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("server: %v", err)
+	}
+	if _, err := c.R.ReadFrame(); err != io.EOF {
+		t.Fatalf("after drain: err = %v, want io.EOF", err)
+	}
+}
+
+// TestLegacyMethodPath calls methods on paths from before paths named the
+// service's package, which the server must route as the paths they stand
+// for.
+func TestLegacyMethodPath(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	clientEnd, serverEnd := rpcproto.Pipe()
+	reg := rpcserverlib.DefaultRegistry()
+	reg.Alias("/Old/Ping", "/UnaryService/Ping")
+	srv := &rpcserverlib.Server{Registry: reg, Settings: rpcserverlib.DefaultSettings, Log: io.Discard}
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(ctx, serverEnd)
+		serverEnd.Close()
+	}()
+
+	c := rpcclientlib.NewClient(clientEnd)
+	respBytes, err := c.Unary(ctx, "/Old/Ping", &pb.PingRequest{Payload: "old"})
+	if err != nil {
+		t.Fatalf("/Old/Ping: %v", err)
+	}
+	resp := &pb.PingResponse{}
+	if err := proto.Unmarshal(respBytes, resp); err != nil || resp.Payload != "old" {
+		t.Fatalf("/Old/Ping: got %v, %v", resp, err)
+	}
+
+	// Verify with no upload at all: the answer is BAD_REQUEST, not an
+	// unknown method.
+	if err := c.Call(ctx, "/AcpService/Verify", nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CloseSend(ctx); err != nil {
+		t.Fatal(err)
+	}
+	respBytes, err = c.RecvResponse(ctx)
+	if err != nil {
+		t.Fatalf("/AcpService/Verify: %v", err)
+	}
+	reply := &pb.AcpMessage{}
+	if err := proto.Unmarshal(respBytes, reply); err != nil || reply.GetStatus() != pb.AcpStatusCode_BAD_REQUEST {
+		t.Fatalf("/AcpService/Verify: got %v, %v", reply, err)
+	}
+
+	if _, err := c.Shutdown(ctx); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("server: %v", err)
+	}
+	clientEnd.Close()
+}
@@ -0,0 +1,141 @@
+package rpcclientlib
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"compat/rpcproto"
+)
+
+// ClientStats is a snapshot of the traffic a client has seen, the client's
+// side of a server's StatsReport. Frame and byte counts cover every frame on
+// the connection, headers included, so that they can be checked against the
+// server's totals.
+type ClientStats struct {
+	// FramesSent and FramesReceived count frames by type name ("CALL",
+	// "RESPONSE", ...).
+	FramesSent     map[string]uint64 `json:"frames_sent"`
+	FramesReceived map[string]uint64 `json:"frames_received"`
+	BytesSent      uint64            `json:"bytes_sent"`
+	BytesReceived  uint64            `json:"bytes_received"`
+	// Methods are sorted by name.
+	Methods []MethodLatency `json:"methods,omitempty"`
+}
+
+// MethodLatency summarizes the finished calls to one method. A call's
+// latency runs from writing its CALL to reading the RESPONSE, ERROR or
+// STREAM_END that ends it.
+type MethodLatency struct {
+	Method string        `json:"method"`
+	Calls  uint64        `json:"calls"`
+	Errors uint64        `json:"errors"`
+	Total  time.Duration `json:"total_ns"`
+	Min    time.Duration `json:"min_ns"`
+	Max    time.Duration `json:"max_ns"`
+}
+
+// Mean returns the average latency, or zero before the first call.
+func (m MethodLatency) Mean() time.Duration {
+	if m.Calls == 0 {
+		return 0
+	}
+	return m.Total / time.Duration(m.Calls)
+}
+
+// clientCounters observes a client connection's frames.
+type clientCounters struct {
+	mu        sync.Mutex
+	sent      map[string]uint64
+	received  map[string]uint64
+	bytesSent uint64
+	bytesRecv uint64
+	methods   map[string]*MethodLatency
+	method    string // "" between calls
+	start     time.Time
+}
+
+func newClientCounters(c *rpcproto.Conn) *clientCounters {
+	cc := &clientCounters{
+		sent:     map[string]uint64{},
+		received: map[string]uint64{},
+		methods:  map[string]*MethodLatency{},
+	}
+	c.R.AddObserver(func(f *rpcproto.Frame) { cc.count(f, true) })
+	c.W.AddObserver(func(f *rpcproto.Frame) { cc.count(f, false) })
+	return cc
+}
+
+func (cc *clientCounters) count(f *rpcproto.Frame, in bool) {
+	now := time.Now()
+	n := uint64(5 + len(f.Payload))
+	if f.Seq != 0 {
+		n += 4
+	}
+	name := rpcproto.FrameTypeName(f.Type)
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if !in {
+		cc.sent[name]++
+		cc.bytesSent += n
+		if f.Type == rpcproto.FrameCall {
+			if method, _, err := rpcproto.ParseCallPayload(f.Payload); err == nil {
+				cc.method, cc.start = method, now
+			}
+		}
+		return
+	}
+	cc.received[name]++
+	cc.bytesRecv += n
+	switch f.Type {
+	case rpcproto.FrameResponse, rpcproto.FrameError, rpcproto.FrameStreamEnd:
+		if cc.method != "" {
+			cc.finish(now.Sub(cc.start), f.Type == rpcproto.FrameError)
+		}
+	}
+}
+
+// finish records the call in progress as taking d. Called with mu held.
+func (cc *clientCounters) finish(d time.Duration, failed bool) {
+	m := cc.methods[cc.method]
+	if m == nil {
+		m = &MethodLatency{Method: cc.method, Min: d}
+		cc.methods[cc.method] = m
+	}
+	m.Calls++
+	if failed {
+		m.Errors++
+	}
+	m.Total += d
+	m.Min = min(m.Min, d)
+	m.Max = max(m.Max, d)
+	cc.method = ""
+}
+
+func (cc *clientCounters) snapshot() *ClientStats {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	st := &ClientStats{
+		FramesSent:     make(map[string]uint64, len(cc.sent)),
+		FramesReceived: make(map[string]uint64, len(cc.received)),
+		BytesSent:      cc.bytesSent,
+		BytesReceived:  cc.bytesRecv,
+	}
+	for k, v := range cc.sent {
+		st.FramesSent[k] = v
+	}
+	for k, v := range cc.received {
+		st.FramesReceived[k] = v
+	}
+	for _, m := range cc.methods {
+		st.Methods = append(st.Methods, *m)
+	}
+	sort.Slice(st.Methods, func(i, j int) bool { return st.Methods[i].Method < st.Methods[j].Method })
+	return st
+}
+
+// ClientStats returns a snapshot of the client's own counters, for comparing
+// with the server's report.
+func (c *Client) ClientStats() *ClientStats {
+	return c.counters.snapshot()
+}
@@ -0,0 +1,217 @@
+package rpcclientlib
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"compat/pb"
+	"compat/pbrpc"
+)
+
+// expectStreamEnd reads the STREAM_END that should close the peer's side of
+// the call.
+func expectStreamEnd(ctx context.Context, c *Client, name string) int {
+	payload, err := c.RecvMsg(ctx)
+	switch {
+	case err == io.EOF:
+		return 0
+	case err != nil:
+		fmt.Fprintf(c.Log, "FAIL %s read end: %v\n", name, err)
+	default:
+		fmt.Fprintf(c.Log, "FAIL %s: expected STREAM_END, got a %d-byte STREAM_MSG\n", name, len(payload))
+	}
+	return 1
+}
+
+func testPing(ctx context.Context, c *Client) int {
+	resp, err := pbrpc.NewUnaryServiceClient(c.StreamConn).Ping(ctx, &pb.PingRequest{Payload: "hello"})
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL Ping: %v\n", err)
+		return 1
+	}
+	if resp.Payload != "hello" {
+		fmt.Fprintf(c.Log, "FAIL Ping: payload=%q want %q\n", resp.Payload, "hello")
+		return 1
+	}
+	return 0
+}
+
+func testGetItem(ctx context.Context, c *Client) int {
+	resp, err := pbrpc.NewUnaryServiceClient(c.StreamConn).GetItem(ctx, &pb.GetItemRequest{Id: 42, Query: "test"})
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL GetItem: %v\n", err)
+		return 1
+	}
+	if resp.Id != 42 {
+		fmt.Fprintf(c.Log, "FAIL GetItem: id=%d want 42\n", resp.Id)
+		return 1
+	}
+	if resp.Name != "item_42" {
+		fmt.Fprintf(c.Log, "FAIL GetItem: name=%q want %q\n", resp.Name, "item_42")
+		return 1
+	}
+	return 0
+}
+
+func testHealth(ctx context.Context, c *Client) int {
+	resp, err := pbrpc.NewUnaryServiceClient(c.StreamConn).Health(ctx, &pb.HealthRequest{ServiceName: "svc"})
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL Health: %v\n", err)
+		return 1
+	}
+	if resp.Status != "serving" {
+		fmt.Fprintf(c.Log, "FAIL Health: status=%q want %q\n", resp.Status, "serving")
+		return 1
+	}
+	return 0
+}
+
+func testEcho(ctx context.Context, c *Client) int {
+	resp, err := pbrpc.NewUnaryServiceClient(c.StreamConn).Echo(ctx, &pb.EchoMessage{Text: "hi", Code: 10})
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL Echo: %v\n", err)
+		return 1
+	}
+	if resp.Text != "hi" {
+		fmt.Fprintf(c.Log, "FAIL Echo: text=%q want %q\n", resp.Text, "hi")
+		return 1
+	}
+	if resp.Code != 11 {
+		fmt.Fprintf(c.Log, "FAIL Echo: code=%d want 11\n", resp.Code)
+		return 1
+	}
+	return 0
+}
+
+// recvStreamEnd reads what should be the end of the peer's stream.
+func recvStreamEnd[M any](c *Client, name string, recv func() (M, error)) int {
+	_, err := recv()
+	switch {
+	case err == io.EOF:
+		return 0
+	case err != nil:
+		fmt.Fprintf(c.Log, "FAIL %s read end: %v\n", name, err)
+	default:
+		fmt.Fprintf(c.Log, "FAIL %s: expected STREAM_END, got another message\n", name)
+	}
+	return 1
+}
+
+func testServerSide(ctx context.Context, c *Client) int {
+	stream, err := pbrpc.NewStreamingServiceClient(c.StreamConn).ServerSide(ctx, &pb.StreamRequest{Query: "q"})
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL ServerSide %v\n", err)
+		return 1
+	}
+
+	// Read 3 STREAM_MSG + STREAM_END
+	for i := int32(0); i < 3; i++ {
+		resp, err := stream.Recv()
+		if err != nil {
+			fmt.Fprintf(c.Log, "FAIL ServerSide read msg %d: %v\n", i, err)
+			return 1
+		}
+		expected := fmt.Sprintf("q_%d", i)
+		if resp.Result != expected {
+			fmt.Fprintf(c.Log, "FAIL ServerSide: result=%q want %q\n", resp.Result, expected)
+			return 1
+		}
+		if resp.Index != i {
+			fmt.Fprintf(c.Log, "FAIL ServerSide: index=%d want %d\n", resp.Index, i)
+			return 1
+		}
+	}
+	return recvStreamEnd(c, "ServerSide", stream.Recv)
+}
+
+func testClientSide(ctx context.Context, c *Client) int {
+	stream, err := pbrpc.NewStreamingServiceClient(c.StreamConn).ClientSide(ctx)
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL ClientSide %v\n", err)
+		return 1
+	}
+
+	// Send 3 chunks
+	chunks := []string{"a", "bb", "ccc"}
+	for _, data := range chunks {
+		if err := stream.Send(&pb.UploadChunk{Data: []byte(data)}); err != nil {
+			fmt.Fprintf(c.Log, "FAIL ClientSide write chunk: %v\n", err)
+			return 1
+		}
+	}
+
+	// Send STREAM_END and read RESPONSE
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL ClientSide %v\n", err)
+		return 1
+	}
+	if resp.TotalChunks != 3 {
+		fmt.Fprintf(c.Log, "FAIL ClientSide: total_chunks=%d want 3\n", resp.TotalChunks)
+		return 1
+	}
+	if resp.Summary != "received_3_chunks" {
+		fmt.Fprintf(c.Log, "FAIL ClientSide: summary=%q want %q\n", resp.Summary, "received_3_chunks")
+		return 1
+	}
+	return 0
+}
+
+func testBidirectional(ctx context.Context, c *Client) int {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := pbrpc.NewStreamingServiceClient(c.StreamConn).Bidirectional(ctx)
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL Bidirectional %v\n", err)
+		return 1
+	}
+
+	// Send from a separate goroutine so echoes are read while messages are
+	// still going out, as a full-duplex server produces them.
+	msgs := []struct{ sender, text string }{
+		{"test", "hi"},
+		{"test", "bye"},
+	}
+	sendErr := make(chan error, 1)
+	go func() {
+		for _, m := range msgs {
+			if err := stream.Send(&pb.ChatMessage{Sender: m.sender, Text: m.text}); err != nil {
+				sendErr <- fmt.Errorf("write msg: %w", err)
+				return
+			}
+		}
+		if err := stream.CloseSend(); err != nil {
+			sendErr <- fmt.Errorf("write end: %w", err)
+			return
+		}
+		sendErr <- nil
+	}()
+
+	// Read 2 echoed messages + STREAM_END
+	expectedTexts := []string{"hi", "bye"}
+	for i, expectedText := range expectedTexts {
+		resp, err := stream.Recv()
+		if err != nil {
+			fmt.Fprintf(c.Log, "FAIL Bidirectional read msg %d: %v\n", i, err)
+			return 1
+		}
+		if resp.Sender != "echo" {
+			fmt.Fprintf(c.Log, "FAIL Bidirectional: sender=%q want %q\n", resp.Sender, "echo")
+			return 1
+		}
+		if resp.Text != expectedText {
+			fmt.Fprintf(c.Log, "FAIL Bidirectional: text=%q want %q\n", resp.Text, expectedText)
+			return 1
+		}
+	}
+
+	if recvStreamEnd(c, "Bidirectional", stream.Recv) != 0 {
+		return 1
+	}
+	if err := <-sendErr; err != nil {
+		fmt.Fprintf(c.Log, "FAIL Bidirectional %v\n", err)
+		return 1
+	}
+	return 0
+}
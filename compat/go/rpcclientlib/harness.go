@@ -0,0 +1,297 @@
+package rpcclientlib
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"compat/pb"
+	"compat/pbrpc"
+	"compat/rpcproto"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func testFirehose(ctx context.Context, c *Client) int {
+	const count, chunkSize = 256, 1024
+	reqBytes, err := c.Codec().Marshal(&pb.FirehoseRequest{Count: count, ChunkSize: chunkSize})
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL Firehose marshal: %v\n", err)
+		return 1
+	}
+	if err := c.Call(ctx, pbrpc.HarnessServiceFirehosePath, reqBytes, false); err != nil {
+		fmt.Fprintf(c.Log, "FAIL Firehose write call: %v\n", err)
+		return 1
+	}
+
+	// Read slowly. The server can only run ahead by the advertised window and
+	// then has to wait for the WINDOW_UPDATEs that Consume sends, so this
+	// also checks that neither side deadlocks on a full pipe.
+	for i := int32(0); i < count; i++ {
+		payload, err := c.RecvMsg(ctx)
+		if err != nil {
+			fmt.Fprintf(c.Log, "FAIL Firehose read chunk %d: %v\n", i, err)
+			return 1
+		}
+		time.Sleep(c.ReadDelay)
+		chunk := &pb.FirehoseChunk{}
+		if err := c.Codec().Unmarshal(payload, chunk); err != nil {
+			fmt.Fprintf(c.Log, "FAIL Firehose unmarshal %d: %v\n", i, err)
+			return 1
+		}
+		if chunk.Seq != i {
+			fmt.Fprintf(c.Log, "FAIL Firehose: seq=%d want %d\n", chunk.Seq, i)
+			return 1
+		}
+		if !bytes.Equal(chunk.Data, bytes.Repeat([]byte{byte(i)}, chunkSize)) {
+			fmt.Fprintf(c.Log, "FAIL Firehose: chunk %d data corrupted\n", i)
+			return 1
+		}
+		if err := c.Consume(len(payload)); err != nil {
+			fmt.Fprintf(c.Log, "FAIL Firehose window update: %v\n", err)
+			return 1
+		}
+	}
+
+	if expectStreamEnd(ctx, c, "Firehose") != 0 {
+		return 1
+	}
+	return 0
+}
+
+func testFailAfterN(ctx context.Context, c *Client) int {
+	// n=0 fails before any data; the others fail after a partial stream.
+	for _, n := range []int32{0, 1, 3} {
+		message := fmt.Sprintf("failed_after_%d", n)
+		reqBytes, err := c.Codec().Marshal(&pb.FailAfterNRequest{N: n, Message: message})
+		if err != nil {
+			fmt.Fprintf(c.Log, "FAIL FailAfterN marshal: %v\n", err)
+			return 1
+		}
+		if err := c.Call(ctx, pbrpc.HarnessServiceFailAfterNPath, reqBytes, false); err != nil {
+			fmt.Fprintf(c.Log, "FAIL FailAfterN(%d) write call: %v\n", n, err)
+			return 1
+		}
+
+		var received int32
+		var remote *rpcproto.RemoteError
+		for {
+			payload, err := c.RecvMsg(ctx)
+			if errors.As(err, &remote) {
+				break
+			}
+			if err == io.EOF {
+				fmt.Fprintf(c.Log, "FAIL FailAfterN(%d): stream ended cleanly after %d messages, want ERROR\n", n, received)
+				return 1
+			}
+			if err != nil {
+				fmt.Fprintf(c.Log, "FAIL FailAfterN(%d) read msg %d: %v\n", n, received, err)
+				return 1
+			}
+			resp := &pb.StreamResponse{}
+			if err := c.Codec().Unmarshal(payload, resp); err != nil {
+				fmt.Fprintf(c.Log, "FAIL FailAfterN(%d) unmarshal %d: %v\n", n, received, err)
+				return 1
+			}
+			if resp.Index != received {
+				fmt.Fprintf(c.Log, "FAIL FailAfterN(%d): index=%d want %d\n", n, resp.Index, received)
+				return 1
+			}
+			if err := c.Consume(len(payload)); err != nil {
+				fmt.Fprintf(c.Log, "FAIL FailAfterN(%d) window update: %v\n", n, err)
+				return 1
+			}
+			received++
+		}
+
+		if received != n {
+			fmt.Fprintf(c.Log, "FAIL FailAfterN(%d): got %d partial results before the error\n", n, received)
+			return 1
+		}
+		if remote.Message != message {
+			fmt.Fprintf(c.Log, "FAIL FailAfterN(%d): error=%q want %q\n", n, remote.Message, message)
+			return 1
+		}
+		if st := c.State(); st != rpcproto.StreamClosed {
+			fmt.Fprintf(c.Log, "FAIL FailAfterN(%d): stream %s after ERROR, want closed\n", n, st)
+			return 1
+		}
+	}
+	return 0
+}
+
+// sized returns build(n) for the n at which the message encodes to exactly
+// size bytes.
+func sized(size int, build func(n int) proto.Message) (proto.Message, error) {
+	for n := size; n >= 0; n-- {
+		m := build(n)
+		if got := proto.Size(m); got == size {
+			return m, nil
+		} else if got < size {
+			break
+		}
+	}
+	return nil, fmt.Errorf("no message encodes to exactly %d bytes", size)
+}
+
+func testFrameLimits(ctx context.Context, c *Client) int {
+	const method = pbrpc.HarnessServiceBlobPath
+	serverMax := int(c.Peer.FrameLimit())
+	clientMax := int(c.Local.FrameLimit())
+	failures := 0
+
+	// Request direction: the CALL payload is [4B method len][method][request].
+	for _, size := range []int{serverMax - 1, serverMax, serverMax + 1} {
+		name := fmt.Sprintf("FrameLimits request %d/%d", size, serverMax)
+		req, err := sized(size-4-len(method), func(n int) proto.Message {
+			return &pb.BlobRequest{Data: make([]byte, n)}
+		})
+		if err != nil {
+			fmt.Fprintf(c.Log, "FAIL %s: %v\n", name, err)
+			failures++
+			continue
+		}
+		reqBytes, err := c.Codec().Marshal(req)
+		if err != nil {
+			fmt.Fprintf(c.Log, "FAIL %s marshal: %v\n", name, err)
+			return failures + 1
+		}
+		over := size > serverMax
+		if over {
+			// Our own writer refuses oversized frames; lift the limit so the
+			// server's handling of one is what gets tested.
+			c.W.SetMaxPayload(0)
+		}
+		err = c.Call(ctx, method, reqBytes, false)
+		c.W.SetMaxPayload(c.Peer.MaxFrameSize)
+		if err != nil {
+			fmt.Fprintf(c.Log, "FAIL %s write call: %v\n", name, err)
+			return failures + 1
+		}
+		failures += expectBlob(ctx, c, name, 0, over)
+	}
+
+	// Response direction: the RESPONSE payload is the BlobResponse alone.
+	for _, size := range []int{clientMax - 1, clientMax, clientMax + 1} {
+		name := fmt.Sprintf("FrameLimits response %d/%d", size, clientMax)
+		resp, err := sized(size, func(n int) proto.Message {
+			return &pb.BlobResponse{Data: make([]byte, n)}
+		})
+		if err != nil {
+			fmt.Fprintf(c.Log, "FAIL %s: %v\n", name, err)
+			failures++
+			continue
+		}
+		n := len(resp.(*pb.BlobResponse).Data)
+		reqBytes, err := c.Codec().Marshal(&pb.BlobRequest{ResponseSize: int32(n)})
+		if err != nil {
+			fmt.Fprintf(c.Log, "FAIL %s marshal: %v\n", name, err)
+			return failures + 1
+		}
+		if err := c.Call(ctx, method, reqBytes, false); err != nil {
+			fmt.Fprintf(c.Log, "FAIL %s write call: %v\n", name, err)
+			return failures + 1
+		}
+		failures += expectBlob(ctx, c, name, n, size > clientMax)
+	}
+	return failures
+}
+
+// expectBlob reads the answer to a Blob call: a BlobResponse with n bytes,
+// or, when the call crossed a frame limit, an ERROR saying so.
+func expectBlob(ctx context.Context, c *Client, name string, n int, wantErr bool) int {
+	respBytes, err := c.RecvResponse(ctx)
+	var remote *rpcproto.RemoteError
+	switch {
+	case wantErr && errors.As(err, &remote):
+		if !strings.Contains(remote.Message, rpcproto.ErrFrameTooLarge.Error()) {
+			fmt.Fprintf(c.Log, "FAIL %s: error=%q, want a frame size error\n", name, remote.Message)
+			return 1
+		}
+		return 0
+	case wantErr && err == nil:
+		fmt.Fprintf(c.Log, "FAIL %s: got a %d-byte RESPONSE past the limit\n", name, len(respBytes))
+		return 1
+	case err != nil:
+		fmt.Fprintf(c.Log, "FAIL %s read response: %v\n", name, err)
+		return 1
+	}
+	resp := &pb.BlobResponse{}
+	if err := c.Codec().Unmarshal(respBytes, resp); err != nil {
+		fmt.Fprintf(c.Log, "FAIL %s unmarshal: %v\n", name, err)
+		return 1
+	}
+	if len(resp.Data) != n {
+		fmt.Fprintf(c.Log, "FAIL %s: %d bytes of data, want %d\n", name, len(resp.Data), n)
+		return 1
+	}
+	return 0
+}
+
+func testChunkedUpload(ctx context.Context, c *Client) int {
+	serverMax := int(c.Peer.FrameLimit())
+	blob := make([]byte, 2*serverMax+12345)
+	for i := range blob {
+		blob[i] = byte(i * 31 % 251)
+	}
+	sum := sha256.Sum256(blob)
+
+	// Chunks fill STREAM_MSG frames exactly to the server's limit.
+	full, err := sized(serverMax, func(n int) proto.Message {
+		return &pb.UploadChunk{Data: make([]byte, n)}
+	})
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL ChunkedUpload: %v\n", err)
+		return 1
+	}
+	chunkSize := len(full.(*pb.UploadChunk).Data)
+
+	if err := c.Call(ctx, pbrpc.HarnessServiceUploadBlobPath, nil, true); err != nil {
+		fmt.Fprintf(c.Log, "FAIL ChunkedUpload write call: %v\n", err)
+		return 1
+	}
+	chunks := int32(0)
+	for rest := blob; len(rest) > 0; chunks++ {
+		n := min(chunkSize, len(rest))
+		chunkBytes, err := c.Codec().Marshal(&pb.UploadChunk{Data: rest[:n]})
+		if err != nil {
+			fmt.Fprintf(c.Log, "FAIL ChunkedUpload marshal: %v\n", err)
+			return 1
+		}
+		if err := c.Send(ctx, chunkBytes); err != nil {
+			fmt.Fprintf(c.Log, "FAIL ChunkedUpload write chunk %d: %v\n", chunks, err)
+			return 1
+		}
+		rest = rest[n:]
+	}
+	if err := c.CloseSend(ctx); err != nil {
+		fmt.Fprintf(c.Log, "FAIL ChunkedUpload write end: %v\n", err)
+		return 1
+	}
+
+	payload, err := c.RecvResponse(ctx)
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL ChunkedUpload read response: %v\n", err)
+		return 1
+	}
+	resp := &pb.UploadResult{}
+	if err := c.Codec().Unmarshal(payload, resp); err != nil {
+		fmt.Fprintf(c.Log, "FAIL ChunkedUpload unmarshal: %v\n", err)
+		return 1
+	}
+	if resp.TotalChunks != chunks {
+		fmt.Fprintf(c.Log, "FAIL ChunkedUpload: total_chunks=%d want %d\n", resp.TotalChunks, chunks)
+		return 1
+	}
+	if want := hex.EncodeToString(sum[:]); resp.Summary != want {
+		fmt.Fprintf(c.Log, "FAIL ChunkedUpload: sha256=%s want %s\n", resp.Summary, want)
+		return 1
+	}
+	return 0
+}
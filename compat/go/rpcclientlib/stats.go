@@ -0,0 +1,105 @@
+package rpcclientlib
+
+import (
+	"context"
+	"fmt"
+
+	"compat/pb"
+	"compat/pbutil"
+	"compat/rpcproto"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Stats asks the server for a StatsReport between calls.
+func (c *Client) Stats(ctx context.Context) (*pb.StatsReport, error) {
+	if err := c.W.WriteFrameContext(ctx, rpcproto.FrameStats, nil); err != nil {
+		return nil, fmt.Errorf("write stats request: %w", err)
+	}
+	frame, err := c.Recv(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read stats: %w", err)
+	}
+	return parseStats(frame)
+}
+
+func parseStats(frame *rpcproto.Frame) (*pb.StatsReport, error) {
+	switch frame.Type {
+	case rpcproto.FrameStats:
+	case rpcproto.FrameError:
+		return nil, &rpcproto.RemoteError{Message: string(frame.Payload)}
+	default:
+		return nil, fmt.Errorf("expected STATS, got frame type 0x%02x", frame.Type)
+	}
+	report := &pb.StatsReport{}
+	if err := proto.Unmarshal(frame.Payload, report); err != nil {
+		return nil, fmt.Errorf("unmarshal stats: %w", err)
+	}
+	return report, nil
+}
+
+// methodStats returns the entry for method, or an empty one if the server
+// has not seen it.
+func methodStats(r *pb.StatsReport, method string) *pb.MethodStats {
+	for _, m := range r.Methods {
+		if m.Method == method {
+			return m
+		}
+	}
+	return &pb.MethodStats{Method: method}
+}
+
+// testStats checks the counters around one Ping. The totals it compares are
+// server-wide, so it assumes no other connection is active meanwhile.
+func testStats(ctx context.Context, c *Client) int {
+	const method = "/UnaryService/Ping"
+	before, err := c.Stats(ctx)
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL Stats before: %v\n", err)
+		return 1
+	}
+	req := &pb.PingRequest{Payload: "stats"}
+	respBytes, err := c.Unary(ctx, method, req)
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL Stats Ping: %v\n", err)
+		return 1
+	}
+	after, err := c.Stats(ctx)
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL Stats after: %v\n", err)
+		return 1
+	}
+
+	// Byte counts include the frame headers, 5 bytes or 9 with sequence
+	// numbers; the CALL payload adds the 4-byte method length and the
+	// method path.
+	header := 5
+	if c.Sequenced() {
+		header = 9
+	}
+	reqBytes, _ := pbutil.Marshal(req)
+	wantIn := uint64(header + 4 + len(method) + len(reqBytes))
+	wantOut := uint64(header + len(respBytes))
+
+	b, a := methodStats(before, method), methodStats(after, method)
+	failures := 0
+	check := func(what string, got, want uint64) {
+		if got != want {
+			fmt.Fprintf(c.Log, "FAIL Stats: %s %s grew by %d, want %d\n", method, what, got, want)
+			failures++
+		}
+	}
+	check("calls", a.Calls-b.Calls, 1)
+	check("errors", a.Errors-b.Errors, 0)
+	check("bytes_in", a.BytesIn-b.BytesIn, wantIn)
+	check("bytes_out", a.BytesOut-b.BytesOut, wantOut)
+	// In between: the CALL and the second STATS request in, the first
+	// report and the RESPONSE out.
+	check("frames_in", after.FramesIn-before.FramesIn, 2)
+	check("frames_out", after.FramesOut-before.FramesOut, 2)
+	if after.MaxConcurrentStreams < 1 {
+		fmt.Fprintf(c.Log, "FAIL Stats: max_concurrent_streams=%d, want >= 1\n", after.MaxConcurrentStreams)
+		failures++
+	}
+	return min(failures, 1)
+}
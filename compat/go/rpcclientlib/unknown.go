@@ -0,0 +1,35 @@
+package rpcclientlib
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"compat/pb"
+	"compat/pbrpc"
+	"compat/pbutil"
+)
+
+// testUnknownFields sends a ScalarMessage carrying fields it does not define
+// through EchoScalar, which decodes and re-encodes it, and checks that the
+// server's runtime handed them back unchanged.
+func testUnknownFields(ctx context.Context, c *Client) int {
+	req := &pb.ScalarMessage{}
+	req.ProtoReflect().SetUnknown(pbutil.UnknownFields())
+	req.FString = "known"
+	req.FInt32 = 42
+	resp, err := pbrpc.NewHarnessServiceClient(c.StreamConn).EchoScalar(ctx, req)
+	if err != nil {
+		fmt.Fprintf(c.Log, "FAIL UnknownFields: %v\n", err)
+		return 1
+	}
+	if resp.FString != req.FString || resp.FInt32 != req.FInt32 {
+		fmt.Fprintf(c.Log, "FAIL UnknownFields: known fields changed: %s\n", compact.Format(resp))
+		return 1
+	}
+	if got, want := []byte(resp.ProtoReflect().GetUnknown()), pbutil.UnknownFields(); !bytes.Equal(got, want) {
+		fmt.Fprintf(c.Log, "FAIL UnknownFields: unknown fields changed\n  got:  %x\n  want: %x\n", got, want)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+package rpcclientlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"compat/pb"
+	"compat/rpcproto"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// testValidation sends requests that break the reference server's
+// validation rules (rpcserver -validate) and checks that each fails with
+// INVALID_ARGUMENT and leaves the connection usable for a valid call.
+func testValidation(ctx context.Context, c *Client) int {
+	invalid := []struct {
+		name, method string
+		req          proto.Message
+	}{
+		{"empty payload", "/UnaryService/Ping", &pb.PingRequest{}},
+		{"zero id", "/UnaryService/GetItem", &pb.GetItemRequest{Id: 0, Query: "q"}},
+		{"negative id", "/UnaryService/GetItem", &pb.GetItemRequest{Id: -1}},
+		{"empty service name", "/UnaryService/Health", &pb.HealthRequest{}},
+		{"code overflows", "/UnaryService/Echo", &pb.EchoMessage{Text: "x", Code: math.MaxInt32}},
+	}
+	failures := 0
+	for _, tt := range invalid {
+		_, err := c.Unary(ctx, tt.method, tt.req)
+		var remote *rpcproto.RemoteError
+		switch {
+		case err == nil:
+			fmt.Fprintf(c.Log, "FAIL Validation %s: call succeeded\n", tt.name)
+			failures++
+		case !errors.As(err, &remote):
+			fmt.Fprintf(c.Log, "FAIL Validation %s: %v\n", tt.name, err)
+			return failures + 1
+		case !errors.Is(err, rpcproto.ErrInvalidArgument):
+			fmt.Fprintf(c.Log, "FAIL Validation %s: error %q is not INVALID_ARGUMENT\n", tt.name, remote.Message)
+			failures++
+		}
+	}
+	if _, err := c.Unary(ctx, "/UnaryService/Ping", &pb.PingRequest{Payload: "valid"}); err != nil {
+		fmt.Fprintf(c.Log, "FAIL Validation valid call: %v\n", err)
+		failures++
+	}
+	return min(failures, 1)
+}
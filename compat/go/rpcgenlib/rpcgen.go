@@ -0,0 +1,325 @@
+// Package rpcgenlib generates Go server interfaces and client stubs for the
+// services of a FileDescriptorSet, over the rpcproto transport, so that the
+// Go method surface follows the .proto files as the Zig one does. cmd/rpcgen
+// runs it to write compat/pbrpc.
+package rpcgenlib
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// LinkedSet returns a FileDescriptorSet of every file linked into the
+// program that declares a service, which for cmd/rpcgen means those of
+// compat/pb, together with the files they import.
+func LinkedSet() *descriptorpb.FileDescriptorSet {
+	seen := map[string]bool{}
+	set := &descriptorpb.FileDescriptorSet{}
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		for i := range fd.Imports().Len() {
+			add(fd.Imports().Get(i).FileDescriptor)
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+	protoregistry.GlobalFiles.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		if fd.Services().Len() > 0 {
+			add(fd)
+		}
+		return true
+	})
+	sort.Slice(set.File, func(i, j int) bool { return set.File[i].GetName() < set.File[j].GetName() })
+	return set
+}
+
+// Generate returns the formatted source of package pkg, holding the stubs
+// of every service in set, in file path and then declaration order. Every
+// message a method takes or returns must have a Go type linked into the
+// program, which is where the stubs import it from.
+func Generate(set *descriptorpb.FileDescriptorSet, pkg string) ([]byte, error) {
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("descriptor set: %w", err)
+	}
+	var sds []protoreflect.ServiceDescriptor
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		for i := range fd.Services().Len() {
+			sds = append(sds, fd.Services().Get(i))
+		}
+		return true
+	})
+	sort.SliceStable(sds, func(i, j int) bool {
+		return sds[i].ParentFile().Path() < sds[j].ParentFile().Path()
+	})
+
+	g := &generator{Package: pkg, imports: map[string]string{
+		"context":         "context",
+		"compat/rpcproto": "rpcproto",
+	}}
+	for _, sd := range sds {
+		s, err := g.service(sd, goNames(sds))
+		if err != nil {
+			return nil, err
+		}
+		g.Services = append(g.Services, s)
+	}
+	// Imports are grouped as in the rest of the module: the standard
+	// library, then compat's packages, then other modules'.
+	g.Imports = make([][]goImport, 3)
+	for p, name := range g.imports {
+		group := 2
+		if first, _, _ := strings.Cut(p, "/"); !strings.Contains(first, ".") {
+			group = 1
+			if !strings.Contains(p, "/") {
+				group = 0
+			}
+		}
+		g.Imports[group] = append(g.Imports[group], goImport{Path: p, Name: name})
+	}
+	for _, group := range g.Imports {
+		sort.Slice(group, func(i, j int) bool { return group[i].Path < group[j].Path })
+	}
+
+	var buf bytes.Buffer
+	if err := stubs.Execute(&buf, g); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated code does not parse: %w", err)
+	}
+	return src, nil
+}
+
+type generator struct {
+	Package  string
+	Imports  [][]goImport
+	Services []service
+
+	// imports maps the import paths the stubs need to their package names.
+	imports map[string]string
+}
+
+type goImport struct {
+	Path, Name string
+}
+
+type service struct {
+	GoName   string
+	FullName protoreflect.FullName
+	File     string
+	Methods  []method
+}
+
+type method struct {
+	GoName string
+	Path   string
+	Req    string
+	Resp   string
+	Shape  string
+}
+
+// Shapes of a method, by which way its messages stream.
+const (
+	unary        = "Unary"
+	serverStream = "ServerStream"
+	clientStream = "ClientStream"
+	bidiStream   = "BidiStream"
+)
+
+func (g *generator) service(sd protoreflect.ServiceDescriptor, names map[protoreflect.FullName]string) (service, error) {
+	s := service{GoName: names[sd.FullName()], FullName: sd.FullName(), File: sd.ParentFile().Path()}
+	seen := map[string]protoreflect.Name{}
+	for i := range sd.Methods().Len() {
+		md := sd.Methods().Get(i)
+		m := method{GoName: goCamelCase(string(md.Name())), Path: "/" + string(sd.FullName()) + "/" + string(md.Name())}
+		if other, ok := seen[m.GoName]; ok {
+			return s, fmt.Errorf("%s: methods %s and %s are both %s in Go", sd.FullName(), other, md.Name(), m.GoName)
+		}
+		seen[m.GoName] = md.Name()
+		var err error
+		if m.Req, err = g.goType(md.Input()); err != nil {
+			return s, fmt.Errorf("%s: %w", md.FullName(), err)
+		}
+		if m.Resp, err = g.goType(md.Output()); err != nil {
+			return s, fmt.Errorf("%s: %w", md.FullName(), err)
+		}
+		switch {
+		case md.IsStreamingClient() && md.IsStreamingServer():
+			m.Shape = bidiStream
+		case md.IsStreamingClient():
+			m.Shape = clientStream
+		case md.IsStreamingServer():
+			m.Shape = serverStream
+		default:
+			m.Shape = unary
+		}
+		s.Methods = append(s.Methods, m)
+	}
+	return s, nil
+}
+
+// goType returns the Go type of md's messages as the stubs write it, such as
+// "*pb.PingRequest", and imports its package.
+func (g *generator) goType(md protoreflect.MessageDescriptor) (string, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName())
+	if err != nil {
+		return "", fmt.Errorf("message %s has no Go type linked into the generator", md.FullName())
+	}
+	t := reflect.TypeOf(mt.Zero().Interface())
+	if t.Kind() != reflect.Pointer || t.Elem().PkgPath() == "" {
+		return "", fmt.Errorf("message %s has Go type %v, not a pointer to a generated struct", md.FullName(), t)
+	}
+	p := t.Elem().PkgPath()
+	name, ok := g.imports[p]
+	if !ok {
+		name = path.Base(p)
+		for _, other := range g.imports {
+			if other == name {
+				return "", fmt.Errorf("message %s: packages %s and another are both named %s", md.FullName(), p, name)
+			}
+		}
+		g.imports[p] = name
+	}
+	return "*" + name + "." + t.Elem().Name(), nil
+}
+
+// goNames gives each service its name in Go: its own name, unless another
+// service has that name too, in which case a service in a package is
+// prefixed with the package, as CompatGatewayUnaryService for
+// compat.gateway.UnaryService. A service in no package keeps its name.
+func goNames(sds []protoreflect.ServiceDescriptor) map[protoreflect.FullName]string {
+	count := map[protoreflect.Name]int{}
+	for _, sd := range sds {
+		count[sd.Name()]++
+	}
+	names := map[protoreflect.FullName]string{}
+	for _, sd := range sds {
+		name := goCamelCase(string(sd.Name()))
+		if count[sd.Name()] > 1 && sd.ParentFile().Package() != "" {
+			name = goCamelCase(strings.ReplaceAll(string(sd.FullName()), ".", "_"))
+		}
+		names[sd.FullName()] = name
+	}
+	return names
+}
+
+// goCamelCase turns a proto name into an exported Go name as protoc-gen-go
+// does for the names in these schemas: each underscore is dropped and the
+// letter after it, like the first, upper-cased.
+func goCamelCase(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		switch {
+		case r == '_':
+			upper = true
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ParseSet decodes a serialized FileDescriptorSet, such as protoc writes
+// with --descriptor_set_out. It must hold the imports of its files too.
+func ParseSet(data []byte) (*descriptorpb.FileDescriptorSet, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("descriptor set: %w", err)
+	}
+	return set, nil
+}
+
+var stubs = template.Must(template.New("stubs").Funcs(template.FuncMap{"base": path.Base}).Parse(`// Code generated by rpcgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range $i, $group := .Imports}}{{if and $i $group}}
+{{end}}
+{{- range $group}}
+	{{if ne .Name (base .Path)}}{{.Name}} {{end}}"{{.Path}}"
+{{- end}}
+{{- end}}
+)
+{{range $s := .Services}}
+// Method paths of {{$s.FullName}}, declared in {{$s.File}}.
+const (
+{{- range .Methods}}
+	{{$s.GoName}}{{.GoName}}Path = "{{.Path}}"
+{{- end}}
+)
+
+// {{.GoName}}Server is the server side of {{.FullName}}.
+type {{.GoName}}Server interface {
+{{- range .Methods}}
+{{- if eq .Shape "Unary"}}
+	{{.GoName}}(context.Context, {{.Req}}) ({{.Resp}}, error)
+{{- else if eq .Shape "ServerStream"}}
+	{{.GoName}}(context.Context, {{.Req}}, *rpcproto.SendStream[{{.Resp}}]) error
+{{- else if eq .Shape "ClientStream"}}
+	{{.GoName}}(context.Context, *rpcproto.RecvStream[{{.Req}}]) ({{.Resp}}, error)
+{{- else}}
+	{{.GoName}}(context.Context, *rpcproto.BidiStream[{{.Req}}, {{.Resp}}]) error
+{{- end}}
+{{- end}}
+}
+
+// Register{{.GoName}}Server registers the methods of srv with r.
+func Register{{.GoName}}Server(r rpcproto.Registrar, srv {{.GoName}}Server) {
+{{- range .Methods}}
+	r.HandleMethod(rpcproto.MustResolveMethod({{$s.GoName}}{{.GoName}}Path), rpcproto.Handle{{.Shape}}(srv.{{.GoName}}))
+{{- end}}
+}
+
+// {{.GoName}}Client calls the methods of {{.FullName}}, one call at a time.
+type {{.GoName}}Client struct {
+	s *rpcproto.StreamConn
+}
+
+// New{{.GoName}}Client returns a client making calls on s.
+func New{{.GoName}}Client(s *rpcproto.StreamConn) *{{.GoName}}Client {
+	return &{{.GoName}}Client{s: s}
+}
+{{range .Methods}}
+// {{.GoName}} calls {{.Path}}.
+{{- if eq .Shape "Unary"}}
+func (c *{{$s.GoName}}Client) {{.GoName}}(ctx context.Context, req {{.Req}}) ({{.Resp}}, error) {
+	return rpcproto.CallUnary[{{.Req}}, {{.Resp}}](ctx, c.s, {{$s.GoName}}{{.GoName}}Path, req)
+}
+{{- else if eq .Shape "ServerStream"}}
+func (c *{{$s.GoName}}Client) {{.GoName}}(ctx context.Context, req {{.Req}}) (*rpcproto.RecvStream[{{.Resp}}], error) {
+	return rpcproto.CallServerStream[{{.Req}}, {{.Resp}}](ctx, c.s, {{$s.GoName}}{{.GoName}}Path, req)
+}
+{{- else if eq .Shape "ClientStream"}}
+func (c *{{$s.GoName}}Client) {{.GoName}}(ctx context.Context) (*rpcproto.ClientStream[{{.Req}}, {{.Resp}}], error) {
+	return rpcproto.CallClientStream[{{.Req}}, {{.Resp}}](ctx, c.s, {{$s.GoName}}{{.GoName}}Path)
+}
+{{- else}}
+func (c *{{$s.GoName}}Client) {{.GoName}}(ctx context.Context) (*rpcproto.BidiStream[{{.Resp}}, {{.Req}}], error) {
+	return rpcproto.CallBidiStream[{{.Req}}, {{.Resp}}](ctx, c.s, {{$s.GoName}}{{.GoName}}Path)
+}
+{{- end}}
+{{end}}
+{{- end}}`))
@@ -0,0 +1,38 @@
+package rpcgenlib
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	_ "compat/pb"
+)
+
+// TestGeneratedUpToDate fails when compat/pbrpc no longer matches the
+// services of the .proto files, so that a changed service is regenerated
+// with go generate ./pbrpc.
+func TestGeneratedUpToDate(t *testing.T) {
+	want, err := Generate(LinkedSet(), "pbrpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("../pbrpc/services.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("pbrpc/services.go is out of date; run go generate ./pbrpc")
+	}
+}
+
+func TestGoCamelCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"Ping":                        "Ping",
+		"get_item":                    "GetItem",
+		"compat_gateway_UnaryService": "CompatGatewayUnaryService",
+	} {
+		if got := goCamelCase(in); got != want {
+			t.Errorf("goCamelCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
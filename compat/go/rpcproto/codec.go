@@ -0,0 +1,75 @@
+package rpcproto
+
+import (
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec ids carried by the codec setting of a HELLO payload.
+const (
+	CodecBinary uint32 = 0
+	CodecJSON   uint32 = 1
+	CodecText   uint32 = 2
+)
+
+// Codec encodes the messages carried in CALL, RESPONSE and STREAM_MSG
+// payloads. The binary codec is used unless a HELLO exchange agrees on
+// another; control payloads such as STATS reports are always binary.
+type Codec interface {
+	// ID is the codec's id in the HELLO codec setting.
+	ID() uint32
+	// Name is the codec's name on command lines.
+	Name() string
+	Marshal(m proto.Message) ([]byte, error)
+	Unmarshal(b []byte, m proto.Message) error
+}
+
+var codecs = []Codec{binaryCodec{}, jsonCodec{}, textCodec{}}
+
+// CodecByID returns the codec with the given HELLO id.
+func CodecByID(id uint32) (Codec, bool) {
+	for _, c := range codecs {
+		if c.ID() == id {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// CodecByName returns the codec called name: binary, json or text.
+func CodecByName(name string) (Codec, bool) {
+	for _, c := range codecs {
+		if c.Name() == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+type binaryCodec struct{}
+
+func (binaryCodec) ID() uint32                                { return CodecBinary }
+func (binaryCodec) Name() string                              { return "binary" }
+func (binaryCodec) Marshal(m proto.Message) ([]byte, error)   { return pbutil.Marshal(m) }
+func (binaryCodec) Unmarshal(b []byte, m proto.Message) error { return proto.Unmarshal(b, m) }
+
+// jsonCodec carries proto3 JSON, as printed by protojson with default
+// options.
+type jsonCodec struct{}
+
+func (jsonCodec) ID() uint32                                { return CodecJSON }
+func (jsonCodec) Name() string                              { return "json" }
+func (jsonCodec) Marshal(m proto.Message) ([]byte, error)   { return protojson.Marshal(m) }
+func (jsonCodec) Unmarshal(b []byte, m proto.Message) error { return protojson.Unmarshal(b, m) }
+
+// textCodec carries the text format, as printed by prototext with default
+// options.
+type textCodec struct{}
+
+func (textCodec) ID() uint32                                { return CodecText }
+func (textCodec) Name() string                              { return "text" }
+func (textCodec) Marshal(m proto.Message) ([]byte, error)   { return prototext.Marshal(m) }
+func (textCodec) Unmarshal(b []byte, m proto.Message) error { return prototext.Unmarshal(b, m) }
@@ -0,0 +1,65 @@
+package rpcproto
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// helloWith runs a HELLO exchange in which the client asks for codec and
+// returns both ends' results.
+func helloWith(t *testing.T, codec uint32) (client, server *Conn, clientErr error) {
+	t.Helper()
+	a, b := Pipe()
+	t.Cleanup(func() { a.Close(); b.Close() })
+	client, server = NewConn(a, a), NewConn(b, b)
+	done := make(chan error, 1)
+	go func() {
+		frame, err := server.R.ReadFrame()
+		if err == nil {
+			err = server.AcceptHello(frame.Payload, Settings{})
+		}
+		done <- err
+	}()
+	clientErr = client.Hello(context.Background(), Settings{Codec: codec})
+	if err := <-done; err != nil {
+		t.Fatalf("server: %v", err)
+	}
+	return client, server, clientErr
+}
+
+func TestCodecNegotiation(t *testing.T) {
+	for _, name := range []string{"binary", "json", "text"} {
+		want, _ := CodecByName(name)
+		client, server, err := helloWith(t, want.ID())
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if client.Codec() != want || server.Codec() != want {
+			t.Errorf("%s: client uses %s, server %s", name, client.Codec().Name(), server.Codec().Name())
+		}
+	}
+}
+
+func TestCodecUnknown(t *testing.T) {
+	// The server answers with binary; the client must not carry on as if it
+	// had its way.
+	_, server, err := helloWith(t, 99)
+	if !errors.Is(err, ErrBadSettings) {
+		t.Errorf("client err = %v, want ErrBadSettings", err)
+	}
+	if server.Codec().ID() != CodecBinary {
+		t.Errorf("server uses %s, want binary", server.Codec().Name())
+	}
+}
+
+func TestConnDefaultCodec(t *testing.T) {
+	// Without a handshake, or after one that leaves the codec out, payloads
+	// stay binary and HELLO bytes stay as they were before the setting.
+	if got := NewConn(nil, nil).Codec().ID(); got != CodecBinary {
+		t.Errorf("codec without HELLO = %d", got)
+	}
+	if n := len(Settings{InitialWindow: 1, MaxFrameSize: 2}.Encode()); n != 12 {
+		t.Errorf("binary HELLO is %d bytes, want 12", n)
+	}
+}
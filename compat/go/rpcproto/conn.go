@@ -0,0 +1,186 @@
+package rpcproto
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Conn is one end of a pipe RPC connection. It pairs a FrameReader and a
+// FrameWriter with the flow-control state negotiated by a HELLO exchange;
+// without a handshake Flow is nil and Conn adds nothing to the raw frames.
+type Conn struct {
+	R    *FrameReader
+	W    *FrameWriter
+	Flow *Flow
+
+	// Local and Peer are the settings exchanged by the handshake, if any.
+	Local, Peer Settings
+
+	// ReadTimeout, if nonzero, bounds each wait for a frame in Hello and
+	// ReadFrame, so that a wedged peer fails the read with a
+	// *FrameWaitError instead of hanging it.
+	ReadTimeout time.Duration
+
+	codec Codec
+}
+
+// NewConn returns a Conn reading frames from r and writing them to w.
+func NewConn(r io.Reader, w io.Writer) *Conn {
+	return &Conn{R: NewFrameReader(r), W: NewFrameWriter(w)}
+}
+
+// Hello performs the client side of the handshake: it sends local in a HELLO
+// frame and waits for the peer's HELLO. The server's answer decides the
+// codec; it is an error if the server does not take the one local asks for.
+func (c *Conn) Hello(ctx context.Context, local Settings) error {
+	if err := c.W.WriteFrameContext(ctx, FrameHello, local.Encode()); err != nil {
+		return err
+	}
+	frame, err := c.readFrame(ctx)
+	if err != nil {
+		return err
+	}
+	if frame.Type == FrameError {
+		return fmt.Errorf("handshake rejected: %s", frame.Payload)
+	}
+	if frame.Type != FrameHello {
+		return fmt.Errorf("expected HELLO, got 0x%02x", frame.Type)
+	}
+	peer, err := ParseSettings(frame.Payload)
+	if err != nil {
+		return err
+	}
+	if peer.Codec != local.Codec {
+		return fmt.Errorf("%w: asked for codec %d, server chose %d", ErrBadSettings, local.Codec, peer.Codec)
+	}
+	c.negotiated(local, peer, peer.Codec)
+	return nil
+}
+
+// AcceptHello performs the server side of the handshake for a HELLO frame
+// already read from the peer, answering with local. The answer carries the
+// codec the client asked for if this package has it, and binary otherwise.
+func (c *Conn) AcceptHello(payload []byte, local Settings) error {
+	peer, err := ParseSettings(payload)
+	if err != nil {
+		return err
+	}
+	local.Codec = CodecBinary
+	if _, ok := CodecByID(peer.Codec); ok {
+		local.Codec = peer.Codec
+	}
+	if err := c.W.WriteFrame(FrameHello, local.Encode()); err != nil {
+		return err
+	}
+	c.negotiated(local, peer, local.Codec)
+	return nil
+}
+
+// Negotiated reports whether a HELLO exchange has completed, so the peer
+// understands the optional frames.
+func (c *Conn) Negotiated() bool {
+	return c.Flow != nil
+}
+
+// Codec returns the codec for message payloads: the one agreed by HELLO, or
+// binary.
+func (c *Conn) Codec() Codec {
+	if c.codec == nil {
+		return binaryCodec{}
+	}
+	return c.codec
+}
+
+// Sequenced reports whether the handshake turned on FeatureSequence, so
+// every later frame is numbered and checked.
+func (c *Conn) Sequenced() bool {
+	return c.Local.Features&c.Peer.Features&FeatureSequence != 0
+}
+
+func (c *Conn) negotiated(local, peer Settings, codec uint32) {
+	c.Local, c.Peer = local, peer
+	c.codec, _ = CodecByID(codec)
+	if c.Sequenced() {
+		c.R.EnableSequence()
+		c.W.EnableSequence()
+	}
+	c.Flow = NewFlow(local, peer)
+	c.R.SetMaxPayload(local.MaxFrameSize)
+	c.W.SetMaxPayload(peer.MaxFrameSize)
+}
+
+// ReadFrame reads the next frame, applying any WINDOW_UPDATE frames on the
+// way and charging STREAM_MSG payloads against the receive window. Callers
+// must pass the size of each STREAM_MSG to Consume once they have handled it.
+func (c *Conn) ReadFrame(ctx context.Context) (*Frame, error) {
+	for {
+		frame, err := c.readFrame(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch frame.Type {
+		case FrameWindowUpdate:
+			if err := c.Flow.Grant(frame.Payload); err != nil {
+				return nil, err
+			}
+			continue
+		case FrameStreamMsg:
+			if err := c.Flow.Received(len(frame.Payload)); err != nil {
+				return nil, err
+			}
+		}
+		return frame, nil
+	}
+}
+
+// readFrame reads the next frame within ReadTimeout.
+func (c *Conn) readFrame(ctx context.Context) (*Frame, error) {
+	if c.ReadTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.ReadTimeout)
+		defer cancel()
+	}
+	return c.R.ReadFrameContext(ctx)
+}
+
+// Consume returns n bytes of handled STREAM_MSG payload to the peer's window.
+func (c *Conn) Consume(n int) error {
+	return c.Flow.Consume(c.W, n)
+}
+
+// WriteStreamMsg writes a STREAM_MSG frame, first waiting for send credit if
+// the window is exhausted.
+func (c *Conn) WriteStreamMsg(ctx context.Context, msgBytes []byte) error {
+	if err := c.Flow.AwaitCredit(ctx, c.R); err != nil {
+		return err
+	}
+	if err := c.W.WriteFrameContext(ctx, FrameStreamMsg, msgBytes); err != nil {
+		return err
+	}
+	c.Flow.Sent(len(msgBytes))
+	return nil
+}
+
+// WriteStreamMsgDuplex is WriteStreamMsg for a writer running alongside a
+// goroutine that reads from c with ReadFrame. It waits for that reader to
+// apply WINDOW_UPDATEs rather than reading them itself; once readerDone is
+// closed nobody else is reading, and it falls back to WriteStreamMsg. A nil
+// readerDone means the reader is known to outlive the writer.
+func (c *Conn) WriteStreamMsgDuplex(ctx context.Context, msgBytes []byte, readerDone <-chan struct{}) error {
+	for !c.Flow.CanSend() {
+		select {
+		case <-c.Flow.granted:
+		case <-readerDone:
+			return c.WriteStreamMsg(ctx, msgBytes)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := c.W.WriteFrameContext(ctx, FrameStreamMsg, msgBytes); err != nil {
+		return err
+	}
+	c.Flow.Sent(len(msgBytes))
+	return nil
+}
@@ -0,0 +1,35 @@
+package rpcproto
+
+import "errors"
+
+// MaxPayloadSize bounds the payload length accepted by ReadFrame. Anything
+// larger is treated as a corrupt header rather than allocated.
+const MaxPayloadSize = 64 << 20
+
+// Errors returned (wrapped) by the frame readers and payload parsers. A clean
+// end of stream between frames is reported as io.EOF, never as one of these.
+var (
+	// ErrTruncatedFrame means the stream ended inside a frame header or payload.
+	ErrTruncatedFrame = errors.New("truncated frame")
+	// ErrFrameTooLarge means a header declared a payload over MaxPayloadSize.
+	ErrFrameTooLarge = errors.New("frame too large")
+	// ErrBadCallPayload means a CALL payload could not be split into method and request.
+	ErrBadCallPayload = errors.New("bad CALL payload")
+	// ErrBadSettings means a HELLO payload could not be decoded.
+	ErrBadSettings = errors.New("bad HELLO settings")
+	// ErrFlowControl means a peer violated the negotiated flow-control window.
+	ErrFlowControl = errors.New("flow control violation")
+	// ErrProtocol means a frame was sent or received out of sequence for the
+	// state of the current call.
+	ErrProtocol = errors.New("protocol violation")
+	// ErrSequence means a frame arrived with a sequence number other than
+	// the next expected one: a frame was lost, duplicated or reordered.
+	ErrSequence = errors.New("frame out of sequence")
+	// ErrGoAway means the peer sent GOAWAY, so no new call may start on the
+	// connection.
+	ErrGoAway = errors.New("peer is going away")
+	// ErrInvalidArgument means a request failed the server's validation
+	// rules. The ERROR frame's message starts with its text and a colon, so
+	// a *RemoteError for such a frame matches it with errors.Is.
+	ErrInvalidArgument = errors.New("INVALID_ARGUMENT")
+)
@@ -0,0 +1,136 @@
+package rpcproto
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+)
+
+// DefaultWindow is the receive window advertised when none is configured.
+const DefaultWindow = 64 << 10
+
+// Flow tracks connection-level flow control for STREAM_MSG payloads once a
+// handshake has agreed on windows. A nil *Flow means no handshake took place;
+// all of its methods are then no-ops and sending is never blocked.
+//
+// A sender may start a message whenever its credit is positive, so credit
+// can go negative by up to one message. The receiver returns credit with a
+// WINDOW_UPDATE once half of its window has been consumed.
+//
+// A Flow is safe for concurrent use by one reading and one writing goroutine.
+type Flow struct {
+	mu      sync.Mutex
+	granted chan struct{} // signalled when Grant adds send credit
+
+	sendCredit int64 // bytes we may still send; < 0 once overdrawn
+	sendLimit  bool  // false when the peer's window is unlimited
+
+	window     uint32 // our advertised receive window; 0 is unlimited
+	recvCredit int64  // bytes the peer may still send us
+	pending    uint32 // bytes consumed but not yet returned to the peer
+}
+
+// NewFlow returns the flow state for a connection where this side advertised
+// local and the peer advertised peer.
+func NewFlow(local, peer Settings) *Flow {
+	return &Flow{
+		granted:    make(chan struct{}, 1),
+		sendCredit: int64(peer.InitialWindow),
+		sendLimit:  peer.InitialWindow != 0,
+		window:     local.InitialWindow,
+		recvCredit: int64(local.InitialWindow),
+	}
+}
+
+// CanSend reports whether a STREAM_MSG may be started now.
+func (f *Flow) CanSend() bool {
+	if f == nil {
+		return true
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !f.sendLimit || f.sendCredit > 0
+}
+
+// Sent charges n payload bytes against the send window.
+func (f *Flow) Sent(n int) {
+	if f != nil {
+		f.mu.Lock()
+		f.sendCredit -= int64(n)
+		f.mu.Unlock()
+	}
+}
+
+// Grant applies a WINDOW_UPDATE payload received from the peer.
+func (f *Flow) Grant(payload []byte) error {
+	if len(payload) != 4 {
+		return fmt.Errorf("%w: WINDOW_UPDATE payload is %d bytes, want 4", ErrFlowControl, len(payload))
+	}
+	if f != nil {
+		f.mu.Lock()
+		f.sendCredit += int64(binary.BigEndian.Uint32(payload))
+		f.mu.Unlock()
+		select {
+		case f.granted <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// AwaitCredit reads frames from r until the send window has credit. Only
+// WINDOW_UPDATE frames may arrive while waiting; anything else is an error.
+func (f *Flow) AwaitCredit(ctx context.Context, r *FrameReader) error {
+	for !f.CanSend() {
+		frame, err := r.ReadFrameContext(ctx)
+		if err != nil {
+			return err
+		}
+		if frame.Type != FrameWindowUpdate {
+			return fmt.Errorf("%w: got frame type 0x%02x while waiting for WINDOW_UPDATE", ErrFlowControl, frame.Type)
+		}
+		if err := f.Grant(frame.Payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Received charges an incoming STREAM_MSG of n bytes against the receive
+// window. It fails if the peer started the message without credit.
+func (f *Flow) Received(n int) error {
+	if f == nil || f.window == 0 {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.recvCredit <= 0 {
+		return fmt.Errorf("%w: peer sent %d bytes with %d bytes of credit", ErrFlowControl, n, f.recvCredit)
+	}
+	f.recvCredit -= int64(n)
+	return nil
+}
+
+// Consume marks n received bytes as processed and, once half the window has
+// been consumed, returns the credit to the peer with a WINDOW_UPDATE. Readers
+// call it after handling a message, so a slow reader holds back the sender.
+func (f *Flow) Consume(w *FrameWriter, n int) error {
+	if f == nil || f.window == 0 {
+		return nil
+	}
+	f.mu.Lock()
+	f.pending += uint32(n)
+	if f.pending < f.window/2 {
+		f.mu.Unlock()
+		return nil
+	}
+	increment := f.pending
+	f.recvCredit += int64(increment)
+	f.pending = 0
+	f.mu.Unlock()
+
+	var payload [4]byte
+	binary.BigEndian.PutUint32(payload[:], increment)
+	return w.WriteFrame(FrameWindowUpdate, payload[:])
+}
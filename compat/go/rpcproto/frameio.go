@@ -0,0 +1,414 @@
+package rpcproto
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FrameReader reads frames from a buffered stream, reusing a single payload
+// buffer between reads so steady-state streaming does not allocate.
+type FrameReader struct {
+	r         *bufio.Reader
+	header    [9]byte
+	buf       []byte
+	frame     Frame
+	max       uint32
+	observers []func(*Frame)
+	// seq is the sequence number the next frame must carry, or zero if
+	// frames are not numbered.
+	seq uint32
+	// dl is r's read deadline, if it has one.
+	dl readDeadliner
+	// frames counts the frames read whole, last is the type of the latest
+	// and partial the bytes read so far of the one after it, for reporting
+	// a read that gave up.
+	frames  uint64
+	last    byte
+	partial int
+}
+
+// NewFrameReader returns a FrameReader reading from r. If r has a read
+// deadline, as a net.Conn or a pipe's *os.File has, ReadFrameContext uses it
+// to interrupt a blocked read.
+func NewFrameReader(r io.Reader) *FrameReader {
+	dl, _ := r.(readDeadliner)
+	return &FrameReader{r: bufio.NewReaderSize(r, 64<<10), max: MaxPayloadSize, dl: dl}
+}
+
+// SetMaxPayload lowers the largest payload the reader accepts, normally to
+// the frame size advertised in this side's HELLO. Zero restores
+// MaxPayloadSize.
+func (fr *FrameReader) SetMaxPayload(n uint32) {
+	fr.max = Settings{MaxFrameSize: n}.FrameLimit()
+}
+
+// EnableSequence makes the reader expect a sequence number after every
+// frame header from now on, starting at 1; see FeatureSequence.
+func (fr *FrameReader) EnableSequence() {
+	fr.seq = 1
+}
+
+// AddObserver registers fn to be called with every frame read, before it is
+// returned. Observers run in the order they were added; the frame is only
+// valid for the duration of the call.
+func (fr *FrameReader) AddObserver(fn func(*Frame)) {
+	fr.observers = append(fr.observers, fn)
+}
+
+// ReadFrame reads the next frame. The returned Frame and its Payload are
+// owned by the reader and are only valid until the next call; callers that
+// keep payload bytes must copy them. Errors match the package-level ReadFrame,
+// except that a frame over the SetMaxPayload limit but within MaxPayloadSize
+// is skipped and reported as an error wrapping both ErrProtocol and
+// ErrFrameTooLarge; the stream stays in sync and reading may continue. Once
+// sequence numbers are enabled, a frame carrying the wrong one is skipped
+// and reported as an error wrapping ErrSequence; the count carries on from
+// the number it carried.
+func (fr *FrameReader) ReadFrame() (*Frame, error) {
+	header := fr.header[:5]
+	if fr.seq != 0 {
+		header = fr.header[:9]
+	}
+	n, err := io.ReadFull(fr.r, header)
+	fr.partial = n
+	if err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("%w: header: %w", ErrTruncatedFrame, err)
+		}
+		return nil, err
+	}
+
+	frameType := fr.header[0]
+	payloadLen := binary.BigEndian.Uint32(fr.header[1:5])
+	var seq uint32
+	var seqErr error
+	if fr.seq != 0 {
+		seq = binary.BigEndian.Uint32(fr.header[5:9])
+		if seq != fr.seq {
+			seqErr = fmt.Errorf("%w: frame type 0x%02x has sequence number %d, expected %d", ErrSequence, frameType, seq, fr.seq)
+		}
+		if fr.seq = seq + 1; fr.seq == 0 {
+			fr.seq = 1
+		}
+	}
+	if payloadLen > MaxPayloadSize {
+		return nil, fmt.Errorf("%w: frame type 0x%02x declares %d bytes, limit %d", ErrFrameTooLarge, frameType, payloadLen, MaxPayloadSize)
+	}
+	if payloadLen > fr.max {
+		skipped, err := io.CopyN(io.Discard, fr.r, int64(payloadLen))
+		fr.partial += int(skipped)
+		if err != nil {
+			return nil, fmt.Errorf("%w: payload of %d bytes: %w", ErrTruncatedFrame, payloadLen, io.ErrUnexpectedEOF)
+		}
+		fr.finish(frameType)
+		return nil, fmt.Errorf("%w: %w: frame type 0x%02x has %d bytes, negotiated limit %d", ErrProtocol, ErrFrameTooLarge, frameType, payloadLen, fr.max)
+	}
+
+	if cap(fr.buf) < int(payloadLen) {
+		fr.buf = make([]byte, payloadLen)
+	}
+	payload := fr.buf[:payloadLen]
+	if payloadLen > 0 {
+		n, err := io.ReadFull(fr.r, payload)
+		fr.partial += n
+		if err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil, fmt.Errorf("%w: payload of %d bytes: %w", ErrTruncatedFrame, payloadLen, io.ErrUnexpectedEOF)
+			}
+			return nil, err
+		}
+	}
+
+	fr.finish(frameType)
+	if seqErr != nil {
+		return nil, seqErr
+	}
+	fr.frame = Frame{Type: frameType, Payload: payload, Seq: seq}
+	for _, fn := range fr.observers {
+		fn(&fr.frame)
+	}
+	return &fr.frame, nil
+}
+
+// finish counts a frame of type t read whole.
+func (fr *FrameReader) finish(t byte) {
+	fr.frames++
+	fr.last = t
+	fr.partial = 0
+}
+
+// ReadFrameContext is ReadFrame that gives up when ctx is done, returning a
+// *FrameWaitError that names the frame it was waiting for. If the reader's
+// source has a read deadline, it follows ctx: a read that gave up before any
+// of the frame arrived leaves the reader usable, while one that gave up
+// partway leaves it out of step with the stream. Without a deadline a
+// blocked read cannot be interrupted, so as with the package-level
+// ReadFrameContext the reader must not be used again after it gives up.
+func (fr *FrameReader) ReadFrameContext(ctx context.Context) (*Frame, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Fast path: a whole frame is already buffered, so the read cannot block.
+	hdrLen := 5
+	if fr.seq != 0 {
+		hdrLen = 9
+	}
+	if n := fr.r.Buffered(); n >= hdrLen {
+		if hdr, _ := fr.r.Peek(5); n >= hdrLen+int(binary.BigEndian.Uint32(hdr[1:5])) {
+			return fr.ReadFrame()
+		}
+	}
+	wait := &FrameWaitError{Frame: fr.frames + 1, After: fr.last, Read: -1}
+	if fr.dl != nil {
+		frame, ok, err := readContext(ctx, fr.dl, fr.ReadFrame)
+		if ok {
+			var werr *FrameWaitError
+			if errors.As(err, &werr) {
+				wait.Read, wait.Err = fr.partial, werr.Err
+				return nil, wait
+			}
+			return frame, err
+		}
+	}
+	type result struct {
+		frame *Frame
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		frame, err := fr.ReadFrame()
+		done <- result{frame, err}
+	}()
+	select {
+	case res := <-done:
+		return res.frame, res.err
+	case <-ctx.Done():
+		wait.Err = ctx.Err()
+		return nil, wait
+	}
+}
+
+// FrameWaitError reports a read that gave up waiting for a frame because its
+// context was done. It unwraps to the context's error.
+type FrameWaitError struct {
+	// Frame is the position of the awaited frame among those the reader
+	// has read, counting from 1, or zero if it is not known.
+	Frame uint64
+	// After is the type of the frame before it, or zero if there was none.
+	After byte
+	// Read is how many bytes of it had arrived, or -1 if it is not known.
+	Read int
+	Err  error
+}
+
+func (e *FrameWaitError) Error() string {
+	var b strings.Builder
+	switch {
+	case e.Frame == 0:
+		b.WriteString("gave up waiting for a frame")
+	case e.After == 0:
+		fmt.Fprintf(&b, "gave up waiting for frame %d, the first", e.Frame)
+	default:
+		fmt.Fprintf(&b, "gave up waiting for frame %d, the one after %s", e.Frame, FrameTypeName(e.After))
+	}
+	if e.Read >= 0 {
+		fmt.Fprintf(&b, ", with %d bytes of it read", e.Read)
+	}
+	fmt.Fprintf(&b, ": %v", e.Err)
+	return b.String()
+}
+
+func (e *FrameWaitError) Unwrap() error { return e.Err }
+
+// readDeadliner is a reader whose blocked reads a deadline interrupts.
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// readContext runs read with dl's read deadline following ctx: set to the
+// deadline of ctx, and moved to the past once ctx is done. A read it
+// interrupts fails with a *FrameWaitError. It reports ok false without
+// reading if dl turns out to have no deadline, as an *os.File opened on a
+// regular file or a blocking descriptor does not.
+func readContext(ctx context.Context, dl readDeadliner, read func() (*Frame, error)) (frame *Frame, ok bool, err error) {
+	deadline, _ := ctx.Deadline()
+	if dl.SetReadDeadline(deadline) != nil {
+		return nil, false, nil
+	}
+	fired := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		dl.SetReadDeadline(time.Unix(1, 0))
+		close(fired)
+	})
+	defer func() {
+		if !stop() {
+			<-fired
+		}
+		dl.SetReadDeadline(time.Time{})
+	}()
+
+	frame, err = read()
+	if err != nil && (ctx.Err() != nil || errors.Is(err, os.ErrDeadlineExceeded)) {
+		cause := ctx.Err()
+		if cause == nil {
+			cause = context.DeadlineExceeded
+		}
+		return nil, true, &FrameWaitError{Read: -1, Err: cause}
+	}
+	return frame, true, err
+}
+
+// FrameWriter writes frames with a single Write call each, assembling header
+// and payload in a reusable buffer. It is safe for concurrent use, so one
+// goroutine can stream messages while another sends WINDOW_UPDATEs.
+type FrameWriter struct {
+	mu        sync.Mutex
+	w         io.Writer
+	buf       []byte
+	max       uint32
+	observers []func(*Frame)
+	// seq is the sequence number for the next frame, or zero if frames are
+	// not numbered; hdr is the header length of the frame being written.
+	seq uint32
+	hdr int
+}
+
+// NewFrameWriter returns a FrameWriter writing to w.
+func NewFrameWriter(w io.Writer) *FrameWriter {
+	return &FrameWriter{w: w, max: MaxPayloadSize}
+}
+
+// SetMaxPayload sets the largest payload the writer will send, normally to
+// the frame size the peer advertised in its HELLO. Zero restores
+// MaxPayloadSize.
+func (fw *FrameWriter) SetMaxPayload(n uint32) {
+	fw.mu.Lock()
+	fw.max = Settings{MaxFrameSize: n}.FrameLimit()
+	fw.mu.Unlock()
+}
+
+// EnableSequence makes the writer put a sequence number after every frame
+// header from now on, starting at 1; see FeatureSequence.
+func (fw *FrameWriter) EnableSequence() {
+	fw.mu.Lock()
+	fw.seq = 1
+	fw.mu.Unlock()
+}
+
+// AddObserver registers fn to be called with every frame just before it is
+// written. Calls are serialized and run in the order the observers were
+// added; the frame is only valid for their duration.
+func (fw *FrameWriter) AddObserver(fn func(*Frame)) {
+	fw.mu.Lock()
+	fw.observers = append(fw.observers, fn)
+	fw.mu.Unlock()
+}
+
+// begin locks the writer, resets the buffer to a frame header for a payload
+// of n bytes and returns the buffer with room for the payload. Every
+// successful begin must be followed by flush. A payload over the limit is
+// refused before anything is written.
+func (fw *FrameWriter) begin(frameType byte, n int) ([]byte, error) {
+	fw.mu.Lock()
+	if uint64(n) > uint64(fw.max) {
+		fw.mu.Unlock()
+		return nil, fmt.Errorf("%w: frame type 0x%02x with %d bytes exceeds peer limit %d", ErrFrameTooLarge, frameType, n, fw.max)
+	}
+	fw.hdr = 5
+	if fw.seq != 0 {
+		fw.hdr = 9
+	}
+	if cap(fw.buf) < fw.hdr+n {
+		fw.buf = make([]byte, 0, fw.hdr+n)
+	}
+	buf := fw.buf[:fw.hdr]
+	buf[0] = frameType
+	binary.BigEndian.PutUint32(buf[1:5], uint32(n))
+	if fw.seq != 0 {
+		binary.BigEndian.PutUint32(buf[5:9], fw.seq)
+	}
+	return buf, nil
+}
+
+func (fw *FrameWriter) flush(buf []byte) error {
+	defer fw.mu.Unlock()
+	if len(fw.observers) > 0 {
+		f := &Frame{Type: buf[0], Payload: buf[fw.hdr:], Seq: fw.seq}
+		for _, fn := range fw.observers {
+			fn(f)
+		}
+	}
+	if fw.seq != 0 {
+		if fw.seq++; fw.seq == 0 {
+			fw.seq = 1
+		}
+	}
+	fw.buf = buf[:0]
+	_, err := fw.w.Write(buf)
+	return err
+}
+
+// WriteFrame writes a single frame.
+func (fw *FrameWriter) WriteFrame(frameType byte, payload []byte) error {
+	buf, err := fw.begin(frameType, len(payload))
+	if err != nil {
+		return err
+	}
+	return fw.flush(append(buf, payload...))
+}
+
+// WriteFrameContext is WriteFrame that refuses to start once ctx is done.
+func (fw *FrameWriter) WriteFrameContext(ctx context.Context, frameType byte, payload []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return fw.WriteFrame(frameType, payload)
+}
+
+// WriteCall writes a CALL frame with the given method path and request bytes.
+func (fw *FrameWriter) WriteCall(method string, reqBytes []byte) error {
+	buf, err := fw.begin(FrameCall, 4+len(method)+len(reqBytes))
+	if err != nil {
+		return err
+	}
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(method)))
+	buf = append(buf, method...)
+	return fw.flush(append(buf, reqBytes...))
+}
+
+// WriteResponse writes a RESPONSE frame.
+func (fw *FrameWriter) WriteResponse(respBytes []byte) error {
+	return fw.WriteFrame(FrameResponse, respBytes)
+}
+
+// WriteStreamMsg writes a STREAM_MSG frame.
+func (fw *FrameWriter) WriteStreamMsg(msgBytes []byte) error {
+	return fw.WriteFrame(FrameStreamMsg, msgBytes)
+}
+
+// WriteStreamEnd writes a STREAM_END frame.
+func (fw *FrameWriter) WriteStreamEnd() error {
+	return fw.WriteFrame(FrameStreamEnd, nil)
+}
+
+// WriteError writes an ERROR frame with the given error message.
+func (fw *FrameWriter) WriteError(errMsg string) error {
+	buf, err := fw.begin(FrameError, len(errMsg))
+	if err != nil {
+		return err
+	}
+	return fw.flush(append(buf, errMsg...))
+}
+
+// WriteShutdown writes a SHUTDOWN frame.
+func (fw *FrameWriter) WriteShutdown() error {
+	return fw.WriteFrame(FrameShutdown, nil)
+}
@@ -0,0 +1,188 @@
+package rpcproto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var benchSizes = []int{64, 4 << 10, 1 << 20}
+
+// loopReader replays data forever so read benchmarks never hit EOF.
+type loopReader struct {
+	data []byte
+	pos  int
+}
+
+func (l *loopReader) Read(p []byte) (int, error) {
+	n := copy(p, l.data[l.pos:])
+	l.pos = (l.pos + n) % len(l.data)
+	return n, nil
+}
+
+func encodedFrame(b *testing.B, size int) []byte {
+	var buf bytes.Buffer
+	if err := WriteFrame(&buf, FrameStreamMsg, make([]byte, size)); err != nil {
+		b.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func BenchmarkReadFrame(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(fmt.Sprintf("%dB", size), func(b *testing.B) {
+			r := &loopReader{data: encodedFrame(b, size)}
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := ReadFrame(r); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFrameReader(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(fmt.Sprintf("%dB", size), func(b *testing.B) {
+			fr := NewFrameReader(&loopReader{data: encodedFrame(b, size)})
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := fr.ReadFrame(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkWriteFrame(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(fmt.Sprintf("%dB", size), func(b *testing.B) {
+			payload := make([]byte, size)
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := WriteFrame(io.Discard, FrameStreamMsg, payload); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFrameWriter(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(fmt.Sprintf("%dB", size), func(b *testing.B) {
+			fw := NewFrameWriter(io.Discard)
+			payload := make([]byte, size)
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := fw.WriteFrame(FrameStreamMsg, payload); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// readWithin reads a frame from fr, giving up after d.
+func readWithin(fr *FrameReader, d time.Duration) (*Frame, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return fr.ReadFrameContext(ctx)
+}
+
+func TestReadFrameContextDeadline(t *testing.T) {
+	local, remote := net.Pipe()
+	defer local.Close()
+	defer remote.Close()
+	fr := NewFrameReader(local)
+
+	go WriteFrame(remote, FrameCall, []byte("x"))
+	if _, err := readWithin(fr, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing arrives: the read gives up having consumed nothing, and the
+	// reader picks up the next frame as if it had not.
+	_, err := readWithin(fr, 20*time.Millisecond)
+	var wait *FrameWaitError
+	if !errors.As(err, &wait) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want a *FrameWaitError for context.DeadlineExceeded", err)
+	}
+	if wait.Frame != 2 || wait.After != FrameCall || wait.Read != 0 {
+		t.Fatalf("got frame %d after 0x%02x with %d bytes read, want frame 2 after CALL with 0", wait.Frame, wait.After, wait.Read)
+	}
+	go WriteFrame(remote, FrameStreamMsg, []byte("y"))
+	f, err := readWithin(fr, time.Second)
+	if err != nil {
+		t.Fatalf("after giving up: %v", err)
+	}
+	if f.Type != FrameStreamMsg || string(f.Payload) != "y" {
+		t.Fatalf("after giving up: got frame 0x%02x %q", f.Type, f.Payload)
+	}
+
+	// Part of a header arrives: the error says how much.
+	go remote.Write([]byte{FrameStreamMsg, 0, 0})
+	_, err = readWithin(fr, 20*time.Millisecond)
+	want := "gave up waiting for frame 3, the one after STREAM_MSG, with 3 bytes of it read: context deadline exceeded"
+	if err == nil || err.Error() != want {
+		t.Fatalf("err = %v, want %q", err, want)
+	}
+}
+
+func TestReadFrameContextNoDeadline(t *testing.T) {
+	// An in-process pipe has no read deadline, so the read is abandoned
+	// and how much of the frame it took is not known.
+	a, b := Pipe()
+	defer a.Close()
+	defer b.Close()
+	_, err := readWithin(NewFrameReader(a), 20*time.Millisecond)
+	var wait *FrameWaitError
+	if !errors.As(err, &wait) || wait.Frame != 1 || wait.Read != -1 {
+		t.Fatalf("err = %#v, want a *FrameWaitError for frame 1 with Read -1", err)
+	}
+	if !strings.HasPrefix(err.Error(), "gave up waiting for frame 1, the first: ") {
+		t.Fatalf("err = %q", err)
+	}
+}
+
+func TestReadFrameContextOSPipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	// JoinReadWriter must keep the read end's deadline, as a plain struct
+	// of the two ends would not.
+	c := NewConn(JoinReadWriter(r, io.Discard), io.Discard)
+	c.ReadTimeout = 20 * time.Millisecond
+	_, err = c.ReadFrame(context.Background())
+	var wait *FrameWaitError
+	if !errors.As(err, &wait) || wait.Read != 0 {
+		t.Fatalf("err = %v, want a *FrameWaitError with nothing read", err)
+	}
+	if err := WriteFrame(w, FrameCall, []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	f, err := c.ReadFrame(context.Background())
+	if err != nil {
+		t.Fatalf("after giving up: %v", err)
+	}
+	if f.Type != FrameCall {
+		t.Fatalf("after giving up: got frame 0x%02x", f.Type)
+	}
+}
@@ -1,6 +1,7 @@
 package rpcproto
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -14,28 +15,77 @@ const (
 	FrameStreamEnd byte = 0x04
 	FrameError     byte = 0x05
 	FrameShutdown  byte = 0x06
+
+	// Optional frames. HELLO is only sent by clients configured to
+	// handshake, and WINDOW_UPDATE only after HELLO has been exchanged, so
+	// runtimes that predate them never see either. STATS is a request from
+	// a harness client (empty payload) answered with a StatsReport. GOAWAY
+	// is sent by a draining server, only to clients that sent HELLO: no new
+	// call will be accepted, but the call in progress runs to completion.
+	FrameHello        byte = 0x07
+	FrameWindowUpdate byte = 0x08
+	FrameStats        byte = 0x09
+	FrameGoAway       byte = 0x0a
 )
 
+var frameTypeNames = map[byte]string{
+	FrameCall:         "CALL",
+	FrameResponse:     "RESPONSE",
+	FrameStreamMsg:    "STREAM_MSG",
+	FrameStreamEnd:    "STREAM_END",
+	FrameError:        "ERROR",
+	FrameShutdown:     "SHUTDOWN",
+	FrameHello:        "HELLO",
+	FrameWindowUpdate: "WINDOW_UPDATE",
+	FrameStats:        "STATS",
+	FrameGoAway:       "GOAWAY",
+}
+
+// FrameTypeName returns the protocol name of a frame type, or its hex value
+// if it is not one this package knows.
+func FrameTypeName(t byte) string {
+	if name, ok := frameTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%02x", t)
+}
+
 // Frame represents a single protocol frame.
 type Frame struct {
 	Type    byte
 	Payload []byte
+	// Seq is the frame's sequence number on a connection that negotiated
+	// FeatureSequence, counting each direction's frames from 1 after HELLO,
+	// and zero otherwise.
+	Seq uint32
 }
 
-// ReadFrame reads a single frame from the reader.
+// ReadFrame reads a single frame from the reader into a freshly allocated
+// payload. Use a FrameReader to reuse buffers across frames.
 // Format: [1B frame_type][4B BE payload_len][payload bytes]
+// It returns io.EOF if the stream ends cleanly before a frame, and an error
+// wrapping ErrTruncatedFrame if it ends inside one.
 func ReadFrame(r io.Reader) (*Frame, error) {
 	var header [5]byte
 	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("%w: header: %w", ErrTruncatedFrame, err)
+		}
 		return nil, err
 	}
 
 	frameType := header[0]
 	payloadLen := binary.BigEndian.Uint32(header[1:5])
+	if payloadLen > MaxPayloadSize {
+		return nil, fmt.Errorf("%w: frame type 0x%02x declares %d bytes, limit %d", ErrFrameTooLarge, frameType, payloadLen, MaxPayloadSize)
+	}
 
 	payload := make([]byte, payloadLen)
 	if payloadLen > 0 {
 		if _, err := io.ReadFull(r, payload); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil, fmt.Errorf("%w: payload of %d bytes: %w", ErrTruncatedFrame, payloadLen, io.ErrUnexpectedEOF)
+			}
 			return nil, err
 		}
 	}
@@ -43,20 +93,56 @@ func ReadFrame(r io.Reader) (*Frame, error) {
 	return &Frame{Type: frameType, Payload: payload}, nil
 }
 
-// WriteFrame writes a single frame to the writer.
-func WriteFrame(w io.Writer, frameType byte, payload []byte) error {
-	var header [5]byte
-	header[0] = frameType
-	binary.BigEndian.PutUint32(header[1:5], uint32(len(payload)))
-	if _, err := w.Write(header[:]); err != nil {
-		return err
+// ReadFrameContext is ReadFrame that gives up when ctx is done, returning a
+// *FrameWaitError. If r has a read deadline it follows ctx, as with
+// FrameReader.ReadFrameContext. Otherwise a blocked read cannot be
+// interrupted, so after cancellation the reader must not be used again: the
+// abandoned read may still consume part of a frame.
+func ReadFrameContext(ctx context.Context, r io.Reader) (*Frame, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	if len(payload) > 0 {
-		if _, err := w.Write(payload); err != nil {
-			return err
+	if dl, ok := r.(readDeadliner); ok {
+		if frame, ok, err := readContext(ctx, dl, func() (*Frame, error) { return ReadFrame(r) }); ok {
+			return frame, err
 		}
 	}
-	return nil
+	type result struct {
+		frame *Frame
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		frame, err := ReadFrame(r)
+		done <- result{frame, err}
+	}()
+	select {
+	case res := <-done:
+		return res.frame, res.err
+	case <-ctx.Done():
+		return nil, &FrameWaitError{Read: -1, Err: ctx.Err()}
+	}
+}
+
+// WriteFrameContext is WriteFrame that refuses to start once ctx is done. A
+// frame that has started is always written completely, so cancellation never
+// leaves a half-written frame on the wire.
+func WriteFrameContext(ctx context.Context, w io.Writer, frameType byte, payload []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return WriteFrame(w, frameType, payload)
+}
+
+// WriteFrame writes a single frame to the writer with one Write call. Use a
+// FrameWriter to avoid allocating a buffer per frame.
+func WriteFrame(w io.Writer, frameType byte, payload []byte) error {
+	buf := make([]byte, 5+len(payload))
+	buf[0] = frameType
+	binary.BigEndian.PutUint32(buf[1:5], uint32(len(payload)))
+	copy(buf[5:], payload)
+	_, err := w.Write(buf)
+	return err
 }
 
 // WriteCall writes a CALL frame with the given method path and request bytes.
@@ -96,11 +182,11 @@ func WriteShutdown(w io.Writer) error {
 // ParseCallPayload extracts the method path and request bytes from a CALL frame payload.
 func ParseCallPayload(payload []byte) (method string, reqBytes []byte, err error) {
 	if len(payload) < 4 {
-		return "", nil, fmt.Errorf("CALL payload too short: %d bytes", len(payload))
+		return "", nil, fmt.Errorf("%w: too short: %d bytes", ErrBadCallPayload, len(payload))
 	}
 	methodLen := binary.BigEndian.Uint32(payload[0:4])
-	if 4+int(methodLen) > len(payload) {
-		return "", nil, fmt.Errorf("CALL method length %d exceeds payload size %d", methodLen, len(payload))
+	if uint64(methodLen) > uint64(len(payload)-4) {
+		return "", nil, fmt.Errorf("%w: method length %d exceeds payload size %d", ErrBadCallPayload, methodLen, len(payload))
 	}
 	method = string(payload[4 : 4+methodLen])
 	reqBytes = payload[4+methodLen:]
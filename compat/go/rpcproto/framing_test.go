@@ -0,0 +1,176 @@
+package rpcproto
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// testFrames are written by the package-level writers and by a FrameWriter
+// alike, covering every frame they write and an empty payload.
+var testFrames = []struct {
+	write   func(w io.Writer) error
+	typ     byte
+	payload []byte
+}{
+	{func(w io.Writer) error { return WriteCall(w, "/UnaryService/Ping", []byte{0x0a, 0x00}) }, FrameCall,
+		append([]byte{0, 0, 0, 18}, "/UnaryService/Ping\x0a\x00"...)},
+	{func(w io.Writer) error { return WriteResponse(w, []byte("resp")) }, FrameResponse, []byte("resp")},
+	{func(w io.Writer) error { return WriteStreamMsg(w, nil) }, FrameStreamMsg, []byte{}},
+	{func(w io.Writer) error { return WriteStreamEnd(w) }, FrameStreamEnd, []byte{}},
+	{func(w io.Writer) error { return WriteError(w, "boom") }, FrameError, []byte("boom")},
+	{func(w io.Writer) error { return WriteShutdown(w) }, FrameShutdown, []byte{}},
+	{func(w io.Writer) error { return WriteFrame(w, FrameHello, Settings{MaxFrameSize: 1}.Encode()) }, FrameHello,
+		Settings{MaxFrameSize: 1}.Encode()},
+}
+
+// TestFrameRoundTrip checks that ReadFrame and FrameReader read back what
+// both writers wrote, identically, and then report a clean io.EOF.
+func TestFrameRoundTrip(t *testing.T) {
+	var plain bytes.Buffer
+	for _, f := range testFrames {
+		if err := f.write(&plain); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var viaWriter bytes.Buffer
+	fw := NewFrameWriter(&viaWriter)
+	for _, f := range testFrames {
+		if err := fw.WriteFrame(f.typ, f.payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(plain.Bytes(), viaWriter.Bytes()) {
+		t.Fatal("FrameWriter and the package-level writers disagree")
+	}
+
+	r := bytes.NewReader(plain.Bytes())
+	fr := NewFrameReader(bytes.NewReader(plain.Bytes()))
+	for i, f := range testFrames {
+		a, err := ReadFrame(r)
+		if err != nil {
+			t.Fatalf("frame %d: ReadFrame: %v", i, err)
+		}
+		b, err := fr.ReadFrame()
+		if err != nil {
+			t.Fatalf("frame %d: FrameReader: %v", i, err)
+		}
+		for _, got := range []*Frame{a, b} {
+			if got.Type != f.typ || !bytes.Equal(got.Payload, f.payload) {
+				t.Errorf("frame %d: read %s %q, want %s %q", i,
+					FrameTypeName(got.Type), got.Payload, FrameTypeName(f.typ), f.payload)
+			}
+		}
+	}
+	if _, err := ReadFrame(r); err != io.EOF {
+		t.Errorf("ReadFrame after the last frame: %v, want io.EOF", err)
+	}
+	if _, err := fr.ReadFrame(); err != io.EOF {
+		t.Errorf("FrameReader after the last frame: %v, want io.EOF", err)
+	}
+}
+
+// TestReadFrameTruncated cuts a frame at every offset and checks that both
+// readers report ErrTruncatedFrame, and never a clean end of stream.
+func TestReadFrameTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	WriteCall(&buf, "/UnaryService/Ping", []byte("request"))
+	data := buf.Bytes()
+	for cut := 1; cut < len(data); cut++ {
+		if _, err := ReadFrame(bytes.NewReader(data[:cut])); !errors.Is(err, ErrTruncatedFrame) {
+			t.Errorf("ReadFrame, cut at %d: %v", cut, err)
+		}
+		if _, err := NewFrameReader(bytes.NewReader(data[:cut])).ReadFrame(); !errors.Is(err, ErrTruncatedFrame) {
+			t.Errorf("FrameReader, cut at %d: %v", cut, err)
+		}
+	}
+}
+
+// TestReadFrameTooLarge checks that a header declaring more than
+// MaxPayloadSize is refused before anything is allocated, and that a frame
+// over a FrameReader's negotiated limit is skipped, leaving the stream in
+// sync.
+func TestReadFrameTooLarge(t *testing.T) {
+	huge := []byte{FrameCall, 0xff, 0xff, 0xff, 0xff}
+	if _, err := ReadFrame(bytes.NewReader(huge)); !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("ReadFrame: %v, want ErrFrameTooLarge", err)
+	}
+	if _, err := NewFrameReader(bytes.NewReader(huge)).ReadFrame(); !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("FrameReader: %v, want ErrFrameTooLarge", err)
+	}
+
+	var buf bytes.Buffer
+	WriteStreamMsg(&buf, []byte("too long"))
+	WriteStreamMsg(&buf, []byte("ok"))
+	fr := NewFrameReader(&buf)
+	fr.SetMaxPayload(4)
+	if _, err := fr.ReadFrame(); !errors.Is(err, ErrProtocol) || !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("over the limit: %v, want ErrProtocol and ErrFrameTooLarge", err)
+	}
+	if f, err := fr.ReadFrame(); err != nil || string(f.Payload) != "ok" {
+		t.Errorf("after a skipped frame: %v, %v", f, err)
+	}
+}
+
+// TestParseCallPayload checks the split of CALL payloads, including method
+// lengths that overrun the payload.
+func TestParseCallPayload(t *testing.T) {
+	method, req, err := ParseCallPayload(append([]byte{0, 0, 0, 2}, "/mreq"...))
+	if err != nil || method != "/m" || string(req) != "req" {
+		t.Errorf("ParseCallPayload = %q, %q, %v", method, req, err)
+	}
+	if method, req, err := ParseCallPayload([]byte{0, 0, 0, 0}); err != nil || method != "" || len(req) != 0 {
+		t.Errorf("empty method and request: %q, %q, %v", method, req, err)
+	}
+	for _, bad := range [][]byte{
+		nil,
+		{0, 0, 0},
+		{0, 0, 0, 1},
+		{0, 0, 0, 5, 'a', 'b'},
+		{0xff, 0xff, 0xff, 0xff, 'a'},
+	} {
+		if _, _, err := ParseCallPayload(bad); !errors.Is(err, ErrBadCallPayload) {
+			t.Errorf("ParseCallPayload(% x): %v, want ErrBadCallPayload", bad, err)
+		}
+	}
+}
+
+// TestSettings checks that settings survive encoding, that unknown ids are
+// skipped, and that a payload that is not whole settings is refused.
+func TestSettings(t *testing.T) {
+	for _, s := range []Settings{
+		{},
+		{InitialWindow: 1, MaxFrameSize: 2},
+		{InitialWindow: DefaultWindow, MaxFrameSize: 4 << 20, Codec: CodecJSON, Features: FeatureSequence},
+	} {
+		got, err := ParseSettings(s.Encode())
+		if err != nil || got != s {
+			t.Errorf("ParseSettings(%+v.Encode()) = %+v, %v", s, got, err)
+		}
+		withUnknown := append(s.Encode(), 0x7f, 0x7f, 1, 2, 3, 4)
+		if got, err := ParseSettings(withUnknown); err != nil || got != s {
+			t.Errorf("with an unknown setting: %+v, %v; want %+v", got, err, s)
+		}
+	}
+	if _, err := ParseSettings([]byte{0, 1, 0, 0, 0}); !errors.Is(err, ErrBadSettings) {
+		t.Errorf("partial setting: %v, want ErrBadSettings", err)
+	}
+	for _, tt := range []struct {
+		max, want uint32
+	}{{0, MaxPayloadSize}, {1, 1}, {MaxPayloadSize, MaxPayloadSize}, {MaxPayloadSize + 1, MaxPayloadSize}} {
+		if got := (Settings{MaxFrameSize: tt.max}).FrameLimit(); got != tt.want {
+			t.Errorf("FrameLimit with MaxFrameSize %d = %d, want %d", tt.max, got, tt.want)
+		}
+	}
+}
+
+// TestFrameTypeName checks names of known and unknown frame types.
+func TestFrameTypeName(t *testing.T) {
+	if got := FrameTypeName(FrameGoAway); got != "GOAWAY" {
+		t.Errorf("FrameTypeName(FrameGoAway) = %q", got)
+	}
+	if got := FrameTypeName(0x42); got != "0x42" {
+		t.Errorf("FrameTypeName(0x42) = %q", got)
+	}
+}
@@ -0,0 +1,142 @@
+package rpcproto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"compat/pbutil"
+	"compat/testcases"
+)
+
+// corpusRequests returns every generated test vector, as realistic request
+// bodies for the seeds.
+func corpusRequests(f *testing.F) [][]byte {
+	var reqs [][]byte
+	for _, c := range testcases.Categories() {
+		for _, tc := range c.Generate() {
+			b, err := pbutil.Marshal(tc.Msg)
+			if err != nil {
+				f.Fatal(err)
+			}
+			reqs = append(reqs, b)
+		}
+	}
+	return reqs
+}
+
+func fuzzSeeds(f *testing.F) {
+	var buf bytes.Buffer
+	WriteCall(&buf, "/UnaryService/Ping", []byte{0x0a, 0x02, 'h', 'i'})
+	WriteStreamMsg(&buf, []byte("x"))
+	WriteStreamEnd(&buf)
+	f.Add(buf.Bytes())
+	f.Add([]byte{})
+	f.Add([]byte{FrameCall})
+	f.Add([]byte{FrameCall, 0xff, 0xff, 0xff, 0xff})
+	f.Add([]byte{FrameStreamEnd, 0, 0, 0, 0})
+	f.Add([]byte{0x42, 0, 0, 0, 1, 0})
+	f.Add([]byte{FrameCall, 0, 0, 0, 5, 0xff, 0xff, 0xff, 0xff, 'x'})
+	for _, req := range corpusRequests(f) {
+		buf.Reset()
+		WriteCall(&buf, "/StreamingService/Bidirectional", req)
+		WriteStreamMsg(&buf, req)
+		WriteStreamEnd(&buf)
+		f.Add(bytes.Clone(buf.Bytes()))
+	}
+}
+
+// FuzzReadFrame checks that ReadFrame and FrameReader agree on every input
+// and only fail with the documented errors.
+func FuzzReadFrame(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		plain := bytes.NewReader(data)
+		fr := NewFrameReader(bytes.NewReader(data))
+		for {
+			want, wantErr := ReadFrame(plain)
+			got, gotErr := fr.ReadFrame()
+			if (wantErr == nil) != (gotErr == nil) {
+				t.Fatalf("ReadFrame err=%v, FrameReader err=%v", wantErr, gotErr)
+			}
+			if wantErr != nil {
+				if wantErr != io.EOF && !errors.Is(wantErr, ErrTruncatedFrame) && !errors.Is(wantErr, ErrFrameTooLarge) {
+					t.Fatalf("undocumented error: %v", wantErr)
+				}
+				return
+			}
+			if got.Type != want.Type || !bytes.Equal(got.Payload, want.Payload) {
+				t.Fatalf("FrameReader read %x/%x, ReadFrame %x/%x", got.Type, got.Payload, want.Type, want.Payload)
+			}
+		}
+	})
+}
+
+func FuzzParseCallPayload(f *testing.F) {
+	f.Add([]byte{0, 0, 0, 2, '/', 'm', 1, 2})
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff})
+	f.Add([]byte{0, 0})
+	for _, req := range corpusRequests(f) {
+		var buf bytes.Buffer
+		WriteCall(&buf, "/UnaryService/Echo", req)
+		f.Add(buf.Bytes()[5:])
+	}
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		method, req, err := ParseCallPayload(payload)
+		if err != nil {
+			if !errors.Is(err, ErrBadCallPayload) {
+				t.Fatalf("undocumented error: %v", err)
+			}
+			return
+		}
+		if 4+len(method)+len(req) != len(payload) {
+			t.Fatalf("method %q and %d request bytes do not add up to %d", method, len(req), len(payload))
+		}
+	})
+}
+
+func FuzzParseSettings(f *testing.F) {
+	f.Add(Settings{InitialWindow: DefaultWindow, MaxFrameSize: 4 << 20}.Encode())
+	f.Add(Settings{Codec: CodecJSON}.Encode())
+	f.Add([]byte{0, 9, 1, 2, 3, 4})
+	f.Add([]byte{0, 1, 2})
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		s, err := ParseSettings(payload)
+		if err != nil {
+			if !errors.Is(err, ErrBadSettings) {
+				t.Fatalf("undocumented error: %v", err)
+			}
+			return
+		}
+		again, err := ParseSettings(s.Encode())
+		if err != nil || again != s {
+			t.Fatalf("re-encoding %+v parsed as %+v, %v", s, again, err)
+		}
+	})
+}
+
+// FuzzStreamConnRecv drives a server-side StreamConn with arbitrary input.
+// Protocol violations must be reported, never panic, and leave the state
+// machine usable.
+func FuzzStreamConnRecv(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s := NewStreamConn(NewConn(bytes.NewReader(data), io.Discard))
+		for {
+			frame, err := s.Recv(context.Background())
+			if errors.Is(err, ErrProtocol) {
+				continue
+			}
+			if err != nil {
+				return
+			}
+			if frame.Type == FrameCall {
+				if err := s.Accept(true); err != nil {
+					t.Fatalf("Accept after CALL: %v", err)
+				}
+			}
+		}
+	})
+}
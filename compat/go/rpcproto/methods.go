@@ -0,0 +1,123 @@
+package rpcproto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// ErrUnknownMethod means a method path names no method of a known service.
+var ErrUnknownMethod = errors.New("unknown method")
+
+// MethodPath is the path a CALL for md carries: the fully qualified name of
+// its service and the method's name, as in "/acp.AcpService/Verify". A
+// service in no package has just its name, as in "/UnaryService/Ping". The
+// Zig service descriptors' full_path is the same.
+func MethodPath(md protoreflect.MethodDescriptor) string {
+	return "/" + string(md.Parent().FullName()) + "/" + string(md.Name())
+}
+
+// LegacyPaths maps the paths methods were called on before paths named the
+// service's package, when every service was called by its short name, and
+// before the harness methods were declared in HarnessService, when they
+// were served under UnaryService and StreamingService, to their paths now.
+// Servers still accept the old paths as aliases.
+var LegacyPaths = map[string]string{
+	"/AcpService/Verify": "/acp.AcpService/Verify",
+
+	"/UnaryService/EchoScalar":         "/HarnessService/EchoScalar",
+	"/UnaryService/EchoAny":            "/HarnessService/EchoAny",
+	"/UnaryService/Blob":               "/HarnessService/Blob",
+	"/UnaryService/UnknownFields":      "/HarnessService/UnknownFields",
+	"/UnaryService/CheckUnknownFields": "/HarnessService/CheckUnknownFields",
+	"/StreamingService/Firehose":       "/HarnessService/Firehose",
+	"/StreamingService/FailAfterN":     "/HarnessService/FailAfterN",
+	"/StreamingService/UploadBlob":     "/HarnessService/UploadBlob",
+	"/StreamingService/AcpSession":     "/HarnessService/AcpSession",
+}
+
+// MethodResolver maps method paths to the descriptors of the methods they
+// name, looking services up in a file registry as paths arrive, so that
+// services registered after it was made are found too.
+type MethodResolver struct {
+	files   *protoregistry.Files
+	aliases map[string]string
+}
+
+// NewMethodResolver returns a resolver over files, or over
+// protoregistry.GlobalFiles if files is nil, that knows LegacyPaths.
+func NewMethodResolver(files *protoregistry.Files) *MethodResolver {
+	if files == nil {
+		files = protoregistry.GlobalFiles
+	}
+	r := &MethodResolver{files: files, aliases: map[string]string{}}
+	for legacy, path := range LegacyPaths {
+		r.aliases[legacy] = path
+	}
+	return r
+}
+
+// Alias makes legacy another path for the method at path. It is not safe to
+// call while the resolver is in use.
+func (r *MethodResolver) Alias(legacy, path string) {
+	r.aliases[legacy] = path
+}
+
+// Canonical returns the path an alias stands for, and any other path
+// unchanged.
+func (r *MethodResolver) Canonical(path string) string {
+	if p, ok := r.aliases[path]; ok {
+		return p
+	}
+	return path
+}
+
+// Resolve returns the method path names, following an alias. The error
+// wraps ErrUnknownMethod if no service in the registry has the method.
+func (r *MethodResolver) Resolve(path string) (protoreflect.MethodDescriptor, error) {
+	service, name, ok := strings.Cut(strings.TrimPrefix(r.Canonical(path), "/"), "/")
+	if !ok || !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("%w: %q is not a /Service/Method path", ErrUnknownMethod, path)
+	}
+	d, err := r.files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: no service %s", ErrUnknownMethod, path, service)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s: %s is not a service", ErrUnknownMethod, path, service)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(name))
+	if md == nil {
+		return nil, fmt.Errorf("%w: %s: service %s has no method %s", ErrUnknownMethod, path, service, name)
+	}
+	return md, nil
+}
+
+var defaultResolver = NewMethodResolver(nil)
+
+// ResolveMethod resolves path against every service linked into the
+// program, accepting LegacyPaths.
+func ResolveMethod(path string) (protoreflect.MethodDescriptor, error) {
+	return defaultResolver.Resolve(path)
+}
+
+// CanonicalPath returns the path a legacy path in LegacyPaths stands for,
+// and any other path unchanged.
+func CanonicalPath(path string) string {
+	return defaultResolver.Canonical(path)
+}
+
+// MustResolveMethod is ResolveMethod for paths known when the program is
+// built, such as those of the generated stubs. It panics if path names no
+// linked method.
+func MustResolveMethod(path string) protoreflect.MethodDescriptor {
+	md, err := ResolveMethod(path)
+	if err != nil {
+		panic(err)
+	}
+	return md
+}
@@ -0,0 +1,87 @@
+package rpcproto
+
+import (
+	"errors"
+	"testing"
+
+	"compat/pb"
+)
+
+func TestMethodPath(t *testing.T) {
+	for _, tt := range []struct {
+		service string
+		method  string
+		path    string
+	}{
+		{"UnaryService", "Ping", "/UnaryService/Ping"},
+		{"myapp.services.v1.PackagedService", "Lookup", "/myapp.services.v1.PackagedService/Lookup"},
+		{"acp.AcpService", "Verify", "/acp.AcpService/Verify"},
+		{"HarnessService", "AcpSession", "/HarnessService/AcpSession"},
+		// Two services named UnaryService, one in no package.
+		{"compat.gateway.UnaryService", "Health", "/compat.gateway.UnaryService/Health"},
+	} {
+		md, err := ResolveMethod(tt.path)
+		if err != nil {
+			t.Errorf("%s: %v", tt.path, err)
+			continue
+		}
+		if string(md.Parent().FullName()) != tt.service || string(md.Name()) != tt.method {
+			t.Errorf("%s resolves to %s", tt.path, md.FullName())
+		}
+		if got := MethodPath(md); got != tt.path {
+			t.Errorf("MethodPath(%s) = %s, want %s", md.FullName(), got, tt.path)
+		}
+	}
+	if got := MethodPath(pb.File_service_unary_proto.Services().ByName("UnaryService").Methods().ByName("Echo")); got != "/UnaryService/Echo" {
+		t.Errorf("MethodPath(UnaryService.Echo) = %s", got)
+	}
+}
+
+func TestResolveMethodLegacy(t *testing.T) {
+	for legacy, path := range LegacyPaths {
+		md, err := ResolveMethod(legacy)
+		if err != nil {
+			t.Errorf("%s: %v", legacy, err)
+			continue
+		}
+		if got := MethodPath(md); got != path {
+			t.Errorf("%s resolves to %s, want %s", legacy, got, path)
+		}
+		if got := CanonicalPath(legacy); got != path {
+			t.Errorf("CanonicalPath(%s) = %s, want %s", legacy, got, path)
+		}
+	}
+	if got := CanonicalPath("/UnaryService/Ping"); got != "/UnaryService/Ping" {
+		t.Errorf("CanonicalPath changed a current path to %s", got)
+	}
+
+	r := NewMethodResolver(nil)
+	r.Alias("/Old/Ping", "/UnaryService/Ping")
+	if md, err := r.Resolve("/Old/Ping"); err != nil || md.FullName() != "UnaryService.Ping" {
+		t.Errorf("alias: got %v, %v", md, err)
+	}
+	if _, err := ResolveMethod("/Old/Ping"); err == nil {
+		t.Error("an alias on one resolver leaked into the default one")
+	}
+}
+
+func TestResolveMethodUnknown(t *testing.T) {
+	for _, path := range []string{
+		"",
+		"/",
+		"UnaryService/Ping",
+		"/UnaryService",
+		"/NoSuchService/Ping",
+		"/UnaryService/NoSuchMethod",
+		"/PingRequest/Ping",
+		// Legacy paths are known one by one, not by method name.
+		"/UnaryService/Firehose",
+		// The legacy path of a service in a package is an alias, not a
+		// rule: other packaged services have no short form.
+		"/PackagedService/Lookup",
+	} {
+		if _, err := ResolveMethod(path); !errors.Is(err, ErrUnknownMethod) {
+			t.Errorf("%q: err = %v, want ErrUnknownMethod", path, err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package rpcproto
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// splitAddr parses a listen or dial address: "unix:/path/to.sock",
+// "tcp:host:port", or a bare "host:port", which means TCP.
+func splitAddr(addr string) (network, address string) {
+	if network, address, ok := strings.Cut(addr, ":"); ok && (network == "unix" || network == "tcp") {
+		return network, address
+	}
+	return "tcp", addr
+}
+
+// Listen opens a listener for the server's socket mode at addr, which is
+// "unix:/path/to.sock", a TCP "[tcp:]host:port", or a WebSocket
+// "ws:host:port[/path]".
+func Listen(addr string) (net.Listener, error) {
+	if rest, ok := strings.CutPrefix(addr, "ws:"); ok {
+		return listenWebSocket(rest)
+	}
+	network, address := splitAddr(addr)
+	return net.Listen(network, address)
+}
+
+// Dial connects to a server listening at addr, in the form Listen accepts.
+func Dial(ctx context.Context, addr string) (net.Conn, error) {
+	if rest, ok := strings.CutPrefix(addr, "ws:"); ok {
+		return dialWebSocket(ctx, rest)
+	}
+	network, address := splitAddr(addr)
+	var d net.Dialer
+	return d.DialContext(ctx, network, address)
+}
+
+// JoinReadWriter pairs r and w, such as a process's stdin and stdout or a
+// child's pipes, into one io.ReadWriter for NewConn. Unlike a plain struct
+// of the two it keeps r's read deadline, if r has one, so that reads with a
+// context can be interrupted.
+func JoinReadWriter(r io.Reader, w io.Writer) io.ReadWriter {
+	if dl, ok := r.(readDeadliner); ok {
+		return deadlineReadWriter{r, w, dl}
+	}
+	return struct {
+		io.Reader
+		io.Writer
+	}{r, w}
+}
+
+type deadlineReadWriter struct {
+	io.Reader
+	io.Writer
+	dl readDeadliner
+}
+
+func (rw deadlineReadWriter) SetReadDeadline(t time.Time) error {
+	return rw.dl.SetReadDeadline(t)
+}
@@ -0,0 +1,149 @@
+package rpcproto
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// pipeBuffer is how many bytes each direction of a Pipe holds before writes
+// block, matching the default pipe capacity on Linux.
+const pipeBuffer = 64 << 10
+
+// PipeOptions shapes the traffic through a Pipe. The zero value is an
+// unshaped pipe.
+type PipeOptions struct {
+	// Latency delays every write before it becomes readable at the other end.
+	Latency time.Duration
+	// Bandwidth limits each direction to this many bytes per second; writes
+	// block for as long as their bytes take to send. Zero is unlimited.
+	Bandwidth int
+}
+
+// Pipe returns two connected in-memory endpoints: bytes written to one are
+// read from the other. Unlike net.Pipe each direction is buffered like an OS
+// pipe, so a writer only blocks once the reader falls behind. Closing an
+// endpoint ends the stream the other side reads with io.EOF and makes its
+// writes fail with io.ErrClosedPipe.
+func Pipe() (io.ReadWriteCloser, io.ReadWriteCloser) {
+	return ShapedPipe(PipeOptions{})
+}
+
+// ShapedPipe is Pipe with latency and bandwidth applied to both directions.
+func ShapedPipe(opts PipeOptions) (io.ReadWriteCloser, io.ReadWriteCloser) {
+	ab, ba := newPipeHalf(opts), newPipeHalf(opts)
+	return &pipeEnd{r: ba, w: ab}, &pipeEnd{r: ab, w: ba}
+}
+
+type pipeEnd struct {
+	r, w *pipeHalf
+}
+
+func (p *pipeEnd) Read(b []byte) (int, error)  { return p.r.read(b) }
+func (p *pipeEnd) Write(b []byte) (int, error) { return p.w.write(b) }
+
+func (p *pipeEnd) Close() error {
+	p.w.closeWrite()
+	p.r.closeRead()
+	return nil
+}
+
+type pipeChunk struct {
+	data  []byte
+	ready time.Time
+}
+
+// pipeHalf is one direction of a Pipe.
+type pipeHalf struct {
+	opts     PipeOptions
+	mu       sync.Mutex
+	cond     *sync.Cond
+	chunks   []pipeChunk
+	buffered int
+	sendDone time.Time // when the link finishes sending what was written
+	wclosed  bool
+	rclosed  bool
+}
+
+func newPipeHalf(opts PipeOptions) *pipeHalf {
+	h := &pipeHalf{opts: opts}
+	h.cond = sync.NewCond(&h.mu)
+	return h
+}
+
+func (h *pipeHalf) write(b []byte) (int, error) {
+	h.mu.Lock()
+	// A write larger than the buffer still goes through once it is empty.
+	for !h.rclosed && !h.wclosed && h.buffered > 0 && h.buffered+len(b) > pipeBuffer {
+		h.cond.Wait()
+	}
+	if h.rclosed || h.wclosed {
+		h.mu.Unlock()
+		return 0, io.ErrClosedPipe
+	}
+	now := time.Now()
+	done := now
+	if h.opts.Bandwidth > 0 {
+		start := now
+		if h.sendDone.After(now) {
+			start = h.sendDone
+		}
+		done = start.Add(time.Duration(len(b)) * time.Second / time.Duration(h.opts.Bandwidth))
+	}
+	h.sendDone = done
+	h.chunks = append(h.chunks, pipeChunk{data: append([]byte(nil), b...), ready: done.Add(h.opts.Latency)})
+	h.buffered += len(b)
+	h.cond.Broadcast()
+	h.mu.Unlock()
+
+	time.Sleep(time.Until(done))
+	return len(b), nil
+}
+
+func (h *pipeHalf) read(b []byte) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for {
+		if h.rclosed {
+			return 0, io.ErrClosedPipe
+		}
+		if len(h.chunks) == 0 {
+			if h.wclosed {
+				return 0, io.EOF
+			}
+			h.cond.Wait()
+			continue
+		}
+		if wait := time.Until(h.chunks[0].ready); wait > 0 {
+			h.mu.Unlock()
+			time.Sleep(wait)
+			h.mu.Lock()
+			continue
+		}
+		break
+	}
+	c := &h.chunks[0]
+	n := copy(b, c.data)
+	c.data = c.data[n:]
+	if len(c.data) == 0 {
+		h.chunks = h.chunks[1:]
+	}
+	h.buffered -= n
+	h.cond.Broadcast()
+	return n, nil
+}
+
+func (h *pipeHalf) closeWrite() {
+	h.mu.Lock()
+	h.wclosed = true
+	h.cond.Broadcast()
+	h.mu.Unlock()
+}
+
+func (h *pipeHalf) closeRead() {
+	h.mu.Lock()
+	h.rclosed = true
+	h.chunks, h.buffered = nil, 0
+	h.cond.Broadcast()
+	h.mu.Unlock()
+}
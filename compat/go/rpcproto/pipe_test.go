@@ -0,0 +1,58 @@
+package rpcproto
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestPipe(t *testing.T) {
+	a, b := Pipe()
+	go func() {
+		WriteFrame(a, FrameCall, []byte("hello"))
+		a.Close()
+	}()
+	f, err := ReadFrame(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Type != FrameCall || !bytes.Equal(f.Payload, []byte("hello")) {
+		t.Fatalf("got frame 0x%02x %q", f.Type, f.Payload)
+	}
+	if _, err := ReadFrame(b); err != io.EOF {
+		t.Fatalf("after close: err = %v, want io.EOF", err)
+	}
+	if _, err := b.Write([]byte("x")); !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatalf("write to closed peer: err = %v, want io.ErrClosedPipe", err)
+	}
+}
+
+func TestPipeLargeWrite(t *testing.T) {
+	// A single write bigger than the buffer must not block forever.
+	a, b := Pipe()
+	payload := bytes.Repeat([]byte{0x5a}, 4*pipeBuffer)
+	go WriteFrame(a, FrameStreamMsg, payload)
+	f, err := ReadFrame(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(f.Payload, payload) {
+		t.Fatal("payload corrupted")
+	}
+}
+
+func TestShapedPipe(t *testing.T) {
+	const latency = 20 * time.Millisecond
+	// 1000 bytes at 50 kB/s take another 20ms to send.
+	a, b := ShapedPipe(PipeOptions{Latency: latency, Bandwidth: 50_000})
+	start := time.Now()
+	go a.Write(make([]byte, 1000))
+	if _, err := io.ReadFull(b, make([]byte, 1000)); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 2*latency {
+		t.Fatalf("read after %v, want at least %v", elapsed, 2*latency)
+	}
+}
@@ -0,0 +1,78 @@
+package rpcproto
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// sequencedFrames writes n STREAM_MSG frames with sequence numbers and
+// returns each frame's encoding.
+func sequencedFrames(t *testing.T, n int) [][]byte {
+	t.Helper()
+	var buf bytes.Buffer
+	fw := NewFrameWriter(&buf)
+	fw.EnableSequence()
+	var frames [][]byte
+	for i := range n {
+		if err := fw.WriteStreamMsg([]byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+		frames = append(frames, bytes.Clone(buf.Bytes()))
+		buf.Reset()
+	}
+	return frames
+}
+
+func readSequenced(stream []byte) *FrameReader {
+	fr := NewFrameReader(bytes.NewReader(stream))
+	fr.EnableSequence()
+	return fr
+}
+
+func TestSequenceInOrder(t *testing.T) {
+	frames := sequencedFrames(t, 3)
+	fr := readSequenced(bytes.Join(frames, nil))
+	for i := range frames {
+		f, err := fr.ReadFrame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Seq != uint32(i+1) || !bytes.Equal(f.Payload, []byte{byte(i)}) {
+			t.Fatalf("frame %d: seq %d payload %x", i, f.Seq, f.Payload)
+		}
+	}
+}
+
+func TestSequenceLoss(t *testing.T) {
+	frames := sequencedFrames(t, 4)
+	// Frame 2 is lost: frame 3 is reported, then reading carries on in step.
+	fr := readSequenced(bytes.Join([][]byte{frames[0], frames[2], frames[3]}, nil))
+	if _, err := fr.ReadFrame(); err != nil {
+		t.Fatal(err)
+	}
+	_, err := fr.ReadFrame()
+	if !errors.Is(err, ErrSequence) || !strings.Contains(err.Error(), "sequence number 3, expected 2") {
+		t.Fatalf("err = %v, want ErrSequence for 3 instead of 2", err)
+	}
+	if f, err := fr.ReadFrame(); err != nil || f.Seq != 4 {
+		t.Fatalf("after the gap: %v, %v", f, err)
+	}
+}
+
+func TestSequenceReorder(t *testing.T) {
+	frames := sequencedFrames(t, 2)
+	fr := readSequenced(bytes.Join([][]byte{frames[1], frames[0]}, nil))
+	if _, err := fr.ReadFrame(); !errors.Is(err, ErrSequence) {
+		t.Fatalf("err = %v, want ErrSequence", err)
+	}
+}
+
+func TestSequenceSettings(t *testing.T) {
+	s := Settings{InitialWindow: 1, MaxFrameSize: 2, Features: FeatureSequence}
+	got, err := ParseSettings(s.Encode())
+	if err != nil || got != s {
+		t.Fatalf("round trip gave %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,91 @@
+package rpcproto
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Setting identifiers carried in a HELLO payload.
+const (
+	SettingInitialWindow uint16 = 0x01
+	SettingMaxFrameSize  uint16 = 0x02
+	SettingCodec         uint16 = 0x03
+	SettingFeatures      uint16 = 0x04
+)
+
+// Feature bits of the features setting. A feature is on once both peers
+// advertise it.
+const (
+	// FeatureSequence puts a 4-byte big-endian sequence number after every
+	// frame header that follows the HELLO exchange, [1B type][4B len][4B
+	// seq][payload], which the receiver checks.
+	FeatureSequence uint32 = 1 << 0
+)
+
+// Settings are the connection parameters a peer advertises in its HELLO
+// frame. On the wire they are a sequence of [2B BE id][4B BE value] pairs;
+// unknown ids are ignored so either side can add settings independently.
+type Settings struct {
+	// InitialWindow is how many STREAM_MSG payload bytes the advertising
+	// peer will accept before the other side must wait for a WINDOW_UPDATE.
+	// Zero means unlimited.
+	InitialWindow uint32
+	// MaxFrameSize is the largest frame payload the advertising peer will
+	// accept. Zero means MaxPayloadSize.
+	MaxFrameSize uint32
+	// Codec is the id of the payload codec a client asks for, or the one a
+	// server answers it will use. Zero, the binary codec, is not sent, so
+	// peers that predate the setting see the HELLO they always did.
+	Codec uint32
+	// Features is the set of optional protocol features the peer supports.
+	// Like Codec, it is only sent when nonzero.
+	Features uint32
+}
+
+// FrameLimit returns the effective frame payload limit of s.
+func (s Settings) FrameLimit() uint32 {
+	if s.MaxFrameSize == 0 || s.MaxFrameSize > MaxPayloadSize {
+		return MaxPayloadSize
+	}
+	return s.MaxFrameSize
+}
+
+// Encode returns the HELLO payload for s.
+func (s Settings) Encode() []byte {
+	buf := make([]byte, 0, 24)
+	buf = binary.BigEndian.AppendUint16(buf, SettingInitialWindow)
+	buf = binary.BigEndian.AppendUint32(buf, s.InitialWindow)
+	buf = binary.BigEndian.AppendUint16(buf, SettingMaxFrameSize)
+	buf = binary.BigEndian.AppendUint32(buf, s.MaxFrameSize)
+	if s.Codec != CodecBinary {
+		buf = binary.BigEndian.AppendUint16(buf, SettingCodec)
+		buf = binary.BigEndian.AppendUint32(buf, s.Codec)
+	}
+	if s.Features != 0 {
+		buf = binary.BigEndian.AppendUint16(buf, SettingFeatures)
+		buf = binary.BigEndian.AppendUint32(buf, s.Features)
+	}
+	return buf
+}
+
+// ParseSettings decodes a HELLO payload.
+func ParseSettings(payload []byte) (Settings, error) {
+	var s Settings
+	if len(payload)%6 != 0 {
+		return s, fmt.Errorf("%w: %d bytes is not a whole number of settings", ErrBadSettings, len(payload))
+	}
+	for ; len(payload) > 0; payload = payload[6:] {
+		value := binary.BigEndian.Uint32(payload[2:6])
+		switch binary.BigEndian.Uint16(payload[0:2]) {
+		case SettingInitialWindow:
+			s.InitialWindow = value
+		case SettingMaxFrameSize:
+			s.MaxFrameSize = value
+		case SettingCodec:
+			s.Codec = value
+		case SettingFeatures:
+			s.Features = value
+		}
+	}
+	return s, nil
+}
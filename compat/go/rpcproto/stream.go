@@ -0,0 +1,353 @@
+package rpcproto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// StreamState is the state of the call in progress on a StreamConn, seen from
+// this end of the connection.
+type StreamState int
+
+const (
+	// StreamClosed means no call is in progress. It is the initial state, and
+	// a call returns to it once both directions have ended.
+	StreamClosed StreamState = iota
+	// StreamOpen means both sides may still send STREAM_MSG frames.
+	StreamOpen
+	// StreamHalfClosedLocal means this side has finished sending and is only
+	// receiving.
+	StreamHalfClosedLocal
+	// StreamHalfClosedRemote means the peer has finished sending; this side
+	// may still send.
+	StreamHalfClosedRemote
+)
+
+func (s StreamState) String() string {
+	switch s {
+	case StreamClosed:
+		return "closed"
+	case StreamOpen:
+		return "open"
+	case StreamHalfClosedLocal:
+		return "half-closed (local)"
+	case StreamHalfClosedRemote:
+		return "half-closed (remote)"
+	default:
+		return fmt.Sprintf("StreamState(%d)", int(s))
+	}
+}
+
+// StreamConn is a Conn that tracks the state of the current call and rejects
+// frames that are illegal in that state, in either direction, with an error
+// wrapping ErrProtocol. A rejected frame is never written and leaves the state
+// unchanged.
+//
+// A request carried in the CALL frame itself (unary and server-streaming
+// methods) ends the client's direction immediately; client-streaming methods
+// end it with STREAM_END. A RESPONSE or ERROR ends the whole call.
+//
+// GOAWAY may arrive in any state. It leaves the current call alone but makes
+// every later Call fail with ErrGoAway.
+//
+// StreamConn is safe for one goroutine sending while another receives. During
+// a call, use its methods rather than the embedded Conn's.
+type StreamConn struct {
+	*Conn
+
+	mu     sync.Mutex
+	state  StreamState
+	goAway bool
+	// method is the method of the latest call this side made, for
+	// reporting a frame that never came.
+	method string
+}
+
+// NewStreamConn returns a StreamConn over c with no call in progress.
+func NewStreamConn(c *Conn) *StreamConn {
+	return &StreamConn{Conn: c}
+}
+
+// State returns the state of the current call.
+func (s *StreamConn) State() StreamState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// GoingAway reports whether the peer has sent GOAWAY.
+func (s *StreamConn) GoingAway() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.goAway
+}
+
+func (s *StreamConn) violation(format string, args ...any) error {
+	return fmt.Errorf("%w: %s (stream %s)", ErrProtocol, fmt.Sprintf(format, args...), s.state)
+}
+
+// transition applies fn to the state under the lock, leaving the state alone
+// if fn fails.
+func (s *StreamConn) transition(fn func(StreamState) (StreamState, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next, err := fn(s.state)
+	if err != nil {
+		return err
+	}
+	s.state = next
+	return nil
+}
+
+// Call starts a call as the client. clientStreams says whether the request
+// continues as STREAM_MSG frames after the CALL.
+func (s *StreamConn) Call(ctx context.Context, method string, reqBytes []byte, clientStreams bool) error {
+	err := s.transition(func(st StreamState) (StreamState, error) {
+		if st != StreamClosed {
+			return st, s.violation("CALL %s while another call is in progress", method)
+		}
+		if s.goAway {
+			return st, ErrGoAway
+		}
+		s.method = method
+		if clientStreams {
+			return StreamOpen, nil
+		}
+		return StreamHalfClosedLocal, nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.W.WriteCall(method, reqBytes)
+}
+
+// Accept tells a server that has just received a CALL whether the method's
+// request continues as STREAM_MSG frames. For methods whose request is the
+// CALL payload alone, the client's direction is already over.
+func (s *StreamConn) Accept(clientStreams bool) error {
+	return s.transition(func(st StreamState) (StreamState, error) {
+		if st != StreamOpen {
+			return st, s.violation("accept without a fresh CALL")
+		}
+		if clientStreams {
+			return StreamOpen, nil
+		}
+		return StreamHalfClosedRemote, nil
+	})
+}
+
+func (s *StreamConn) checkSend(st StreamState) (StreamState, error) {
+	switch st {
+	case StreamOpen, StreamHalfClosedRemote:
+		return st, nil
+	case StreamHalfClosedLocal:
+		return st, s.violation("STREAM_MSG after local STREAM_END")
+	default:
+		return st, s.violation("STREAM_MSG without a call")
+	}
+}
+
+// Send writes a STREAM_MSG, waiting for flow-control credit if needed.
+func (s *StreamConn) Send(ctx context.Context, msgBytes []byte) error {
+	if err := s.transition(s.checkSend); err != nil {
+		return err
+	}
+	return s.WriteStreamMsg(ctx, msgBytes)
+}
+
+// SendDuplex is Send for a writer running alongside a goroutine that calls
+// Recv; see Conn.WriteStreamMsgDuplex.
+func (s *StreamConn) SendDuplex(ctx context.Context, msgBytes []byte, readerDone <-chan struct{}) error {
+	if err := s.transition(s.checkSend); err != nil {
+		return err
+	}
+	return s.WriteStreamMsgDuplex(ctx, msgBytes, readerDone)
+}
+
+// CloseSend writes STREAM_END, ending this side's direction of the call.
+func (s *StreamConn) CloseSend(ctx context.Context) error {
+	err := s.transition(func(st StreamState) (StreamState, error) {
+		switch st {
+		case StreamOpen:
+			return StreamHalfClosedLocal, nil
+		case StreamHalfClosedRemote:
+			return StreamClosed, nil
+		case StreamHalfClosedLocal:
+			return st, s.violation("second STREAM_END")
+		default:
+			return st, s.violation("STREAM_END without a call")
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return s.W.WriteFrameContext(ctx, FrameStreamEnd, nil)
+}
+
+// Consume is Conn.Consume, except that after GOAWAY a WINDOW_UPDATE that
+// cannot be written is not an error: a draining peer closes the connection as
+// soon as it has sent its last frame, possibly before this side has read it.
+func (s *StreamConn) Consume(n int) error {
+	err := s.Conn.Consume(n)
+	if err != nil && s.GoingAway() {
+		return nil
+	}
+	return err
+}
+
+// Respond writes the RESPONSE that completes the current call as the server.
+func (s *StreamConn) Respond(respBytes []byte) error {
+	err := s.transition(func(st StreamState) (StreamState, error) {
+		if st == StreamClosed {
+			return st, s.violation("RESPONSE without a call")
+		}
+		return StreamClosed, nil
+	})
+	if err != nil {
+		return err
+	}
+	return s.W.WriteResponse(respBytes)
+}
+
+// Fail writes an ERROR frame. It ends the current call, if any; outside a
+// call it reports a bad frame from the peer.
+func (s *StreamConn) Fail(errMsg string) error {
+	s.transition(func(StreamState) (StreamState, error) { return StreamClosed, nil })
+	return s.W.WriteError(errMsg)
+}
+
+// Recv reads the next frame and checks it against the state of the current
+// call. WINDOW_UPDATE frames are applied and never returned. An illegal frame
+// is returned together with an error wrapping ErrProtocol.
+func (s *StreamConn) Recv(ctx context.Context) (*Frame, error) {
+	frame, err := s.ReadFrame(ctx)
+	if err != nil {
+		var wait *FrameWaitError
+		if errors.As(err, &wait) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.state != StreamClosed && s.method != "" {
+				return nil, fmt.Errorf("%w (call %s, stream %s)", err, s.method, s.state)
+			}
+			return nil, fmt.Errorf("%w (stream %s)", err, s.state)
+		}
+		return nil, err
+	}
+	err = s.transition(func(st StreamState) (StreamState, error) {
+		switch frame.Type {
+		case FrameCall:
+			if st != StreamClosed {
+				return st, s.violation("CALL while another call is in progress")
+			}
+			return StreamOpen, nil
+		case FrameStreamMsg:
+			switch st {
+			case StreamOpen, StreamHalfClosedLocal:
+				return st, nil
+			case StreamHalfClosedRemote:
+				return st, s.violation("STREAM_MSG after peer STREAM_END")
+			default:
+				return st, s.violation("STREAM_MSG without a call")
+			}
+		case FrameStreamEnd:
+			switch st {
+			case StreamOpen:
+				return StreamHalfClosedRemote, nil
+			case StreamHalfClosedLocal:
+				return StreamClosed, nil
+			case StreamHalfClosedRemote:
+				return st, s.violation("second STREAM_END from peer")
+			default:
+				return st, s.violation("STREAM_END without a call")
+			}
+		case FrameResponse:
+			if st == StreamClosed {
+				return st, s.violation("RESPONSE without a call")
+			}
+			return StreamClosed, nil
+		case FrameError:
+			return StreamClosed, nil
+		case FrameGoAway:
+			s.goAway = true
+			return st, nil
+		case FrameHello, FrameShutdown, FrameStats:
+			if st != StreamClosed {
+				return st, s.violation("frame type 0x%02x during a call", frame.Type)
+			}
+			return st, nil
+		default:
+			return st, s.violation("unknown frame type 0x%02x", frame.Type)
+		}
+	})
+	return frame, err
+}
+
+// RemoteError is an ERROR frame received from the peer.
+type RemoteError struct {
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return "remote error: " + e.Message
+}
+
+// Is reports whether the peer failed the call with ErrInvalidArgument, whose
+// text starts the message of such ERROR frames.
+func (e *RemoteError) Is(target error) bool {
+	return target == ErrInvalidArgument && strings.HasPrefix(e.Message, ErrInvalidArgument.Error()+":")
+}
+
+// recvCall is Recv for a caller waiting on the current call, which passes
+// over GOAWAY frames once they are recorded.
+func (s *StreamConn) recvCall(ctx context.Context) (*Frame, error) {
+	for {
+		frame, err := s.Recv(ctx)
+		if err != nil || frame.Type != FrameGoAway {
+			return frame, err
+		}
+	}
+}
+
+// RecvMsg receives the next STREAM_MSG of the current call and returns its
+// payload, which is valid until the next receive. It returns io.EOF once the
+// peer has sent STREAM_END and a *RemoteError if the peer failed the call.
+func (s *StreamConn) RecvMsg(ctx context.Context) ([]byte, error) {
+	frame, err := s.recvCall(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch frame.Type {
+	case FrameStreamMsg:
+		return frame.Payload, nil
+	case FrameStreamEnd:
+		return nil, io.EOF
+	case FrameError:
+		return nil, &RemoteError{Message: string(frame.Payload)}
+	default:
+		return nil, fmt.Errorf("%w: expected STREAM_MSG or STREAM_END, got 0x%02x", ErrProtocol, frame.Type)
+	}
+}
+
+// RecvResponse receives the RESPONSE that completes the current call and
+// returns its payload, or a *RemoteError if the peer failed the call.
+func (s *StreamConn) RecvResponse(ctx context.Context) ([]byte, error) {
+	frame, err := s.recvCall(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch frame.Type {
+	case FrameResponse:
+		return frame.Payload, nil
+	case FrameError:
+		return nil, &RemoteError{Message: string(frame.Payload)}
+	default:
+		return nil, fmt.Errorf("%w: expected RESPONSE, got 0x%02x", ErrProtocol, frame.Type)
+	}
+}
@@ -0,0 +1,210 @@
+package rpcproto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+// newTestStream returns a StreamConn that reads the given frames and records
+// what it writes.
+func newTestStream(t *testing.T, frames ...Frame) (*StreamConn, *bytes.Buffer) {
+	t.Helper()
+	var in bytes.Buffer
+	for _, f := range frames {
+		if err := WriteFrame(&in, f.Type, f.Payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var out bytes.Buffer
+	return NewStreamConn(NewConn(&in, &out)), &out
+}
+
+func wantState(t *testing.T, s *StreamConn, want StreamState) {
+	t.Helper()
+	if got := s.State(); got != want {
+		t.Fatalf("state = %s, want %s", got, want)
+	}
+}
+
+func wantProtocolError(t *testing.T, err error) {
+	t.Helper()
+	if !errors.Is(err, ErrProtocol) {
+		t.Fatalf("err = %v, want ErrProtocol", err)
+	}
+}
+
+func TestStreamLifecycle(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStream(t,
+		Frame{Type: FrameStreamMsg, Payload: []byte("echo")},
+		Frame{Type: FrameStreamEnd},
+	)
+	wantState(t, s, StreamClosed)
+	if err := s.Call(ctx, "/S/Bidi", nil, true); err != nil {
+		t.Fatal(err)
+	}
+	wantState(t, s, StreamOpen)
+	if err := s.Send(ctx, []byte("hi")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CloseSend(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wantState(t, s, StreamHalfClosedLocal)
+	if _, err := s.RecvMsg(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wantState(t, s, StreamHalfClosedLocal)
+	if _, err := s.Recv(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wantState(t, s, StreamClosed)
+}
+
+func TestStreamServerLifecycle(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStream(t, Frame{Type: FrameCall, Payload: []byte{0, 0, 0, 2, '/', 'm'}})
+	if _, err := s.Recv(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wantState(t, s, StreamOpen)
+	if err := s.Accept(false); err != nil {
+		t.Fatal(err)
+	}
+	wantState(t, s, StreamHalfClosedRemote)
+	if err := s.Send(ctx, []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CloseSend(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wantState(t, s, StreamClosed)
+}
+
+func TestStreamSendAfterEnd(t *testing.T) {
+	ctx := context.Background()
+	s, out := newTestStream(t)
+	if err := s.Call(ctx, "/S/Upload", nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CloseSend(ctx); err != nil {
+		t.Fatal(err)
+	}
+	written := out.Len()
+	wantProtocolError(t, s.Send(ctx, []byte("late")))
+	if out.Len() != written {
+		t.Errorf("rejected STREAM_MSG was written")
+	}
+	wantState(t, s, StreamHalfClosedLocal)
+
+	// A unary request is complete in the CALL frame.
+	s, _ = newTestStream(t)
+	if err := s.Call(ctx, "/S/Ping", nil, false); err != nil {
+		t.Fatal(err)
+	}
+	wantProtocolError(t, s.Send(ctx, []byte("late")))
+}
+
+func TestStreamRecvAfterPeerEnd(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStream(t,
+		Frame{Type: FrameCall, Payload: []byte{0, 0, 0, 2, '/', 'm'}},
+		Frame{Type: FrameStreamEnd},
+		Frame{Type: FrameStreamMsg, Payload: []byte("late")},
+	)
+	if _, err := s.Recv(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Accept(true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Recv(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_, err := s.Recv(ctx)
+	wantProtocolError(t, err)
+	wantState(t, s, StreamHalfClosedRemote)
+}
+
+func TestStreamDoubleEnd(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStream(t)
+	if err := s.Call(ctx, "/S/Upload", nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CloseSend(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wantProtocolError(t, s.CloseSend(ctx))
+
+	s, _ = newTestStream(t,
+		Frame{Type: FrameCall, Payload: []byte{0, 0, 0, 2, '/', 'm'}},
+		Frame{Type: FrameStreamEnd},
+		Frame{Type: FrameStreamEnd},
+	)
+	for i := 0; i < 2; i++ {
+		if _, err := s.Recv(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := s.Recv(ctx)
+	wantProtocolError(t, err)
+}
+
+func TestStreamEndWithoutCall(t *testing.T) {
+	ctx := context.Background()
+	s, out := newTestStream(t, Frame{Type: FrameStreamEnd})
+	wantProtocolError(t, s.CloseSend(ctx))
+	if out.Len() != 0 {
+		t.Errorf("rejected STREAM_END was written")
+	}
+	_, err := s.Recv(ctx)
+	wantProtocolError(t, err)
+	wantState(t, s, StreamClosed)
+}
+
+func TestStreamCallDuringCall(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStream(t)
+	if err := s.Call(ctx, "/S/Ping", nil, false); err != nil {
+		t.Fatal(err)
+	}
+	wantProtocolError(t, s.Call(ctx, "/S/Ping", nil, false))
+}
+
+func TestStreamGoAway(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStream(t,
+		Frame{Type: FrameStreamMsg, Payload: []byte("a")},
+		Frame{Type: FrameGoAway},
+		Frame{Type: FrameStreamMsg, Payload: []byte("b")},
+		Frame{Type: FrameStreamEnd},
+	)
+	if err := s.Call(ctx, "/S/Server", nil, false); err != nil {
+		t.Fatal(err)
+	}
+	// GOAWAY mid-stream is recorded and passed over; the call finishes.
+	for _, want := range []string{"a", "b"} {
+		payload, err := s.RecvMsg(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(payload) != want {
+			t.Fatalf("payload = %q, want %q", payload, want)
+		}
+	}
+	if _, err := s.RecvMsg(ctx); err != io.EOF {
+		t.Fatalf("err = %v, want io.EOF", err)
+	}
+	wantState(t, s, StreamClosed)
+	if !s.GoingAway() {
+		t.Fatal("GOAWAY not recorded")
+	}
+	if err := s.Call(ctx, "/S/Unary", nil, false); !errors.Is(err, ErrGoAway) {
+		t.Fatalf("call after GOAWAY: err = %v, want ErrGoAway", err)
+	}
+	wantState(t, s, StreamClosed)
+}
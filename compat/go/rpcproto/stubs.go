@@ -0,0 +1,353 @@
+package rpcproto
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Handler serves one call. The CALL has already been accepted on s; the
+// handler finishes the call with Respond, CloseSend or Fail, or returns an
+// error, which the server reports to the client as an ERROR frame.
+type Handler func(ctx context.Context, s *StreamConn, reqBytes []byte) error
+
+// Registrar takes the handlers of a service's methods. The Register
+// functions of the generated stubs in compat/pbrpc register with one, such
+// as an rpcserverlib.Registry.
+type Registrar interface {
+	HandleMethod(md protoreflect.MethodDescriptor, h Handler)
+}
+
+// The generated stubs are thin: each server method is wrapped by one of the
+// Handle functions below and each client method calls one of the Call
+// functions, which marshal with the connection's codec and keep to the
+// call's stream states and flow control.
+
+// newMessage returns an empty M, which must be a pointer to a generated
+// message type.
+func newMessage[M proto.Message]() M {
+	var zero M
+	return zero.ProtoReflect().Type().New().Interface().(M)
+}
+
+// decode unmarshals b as an M with s's codec.
+func decode[M proto.Message](s *StreamConn, b []byte) (M, error) {
+	m := newMessage[M]()
+	if err := s.Codec().Unmarshal(b, m); err != nil {
+		var zero M
+		return zero, err
+	}
+	return m, nil
+}
+
+// HandleUnary serves a method whose request and response are single
+// messages with fn.
+func HandleUnary[Req, Resp proto.Message](fn func(context.Context, Req) (Resp, error)) Handler {
+	return func(ctx context.Context, s *StreamConn, reqBytes []byte) error {
+		req, err := decode[Req](s, reqBytes)
+		if err != nil {
+			return err
+		}
+		resp, err := fn(ctx, req)
+		if err != nil {
+			return err
+		}
+		respBytes, err := s.Codec().Marshal(resp)
+		if err != nil {
+			return err
+		}
+		return s.Respond(respBytes)
+	}
+}
+
+// HandleServerStream serves a method whose responses stream with fn. The
+// stream ends when fn returns nil.
+func HandleServerStream[Req, Resp proto.Message](fn func(context.Context, Req, *SendStream[Resp]) error) Handler {
+	return func(ctx context.Context, s *StreamConn, reqBytes []byte) error {
+		req, err := decode[Req](s, reqBytes)
+		if err != nil {
+			return err
+		}
+		if err := fn(ctx, req, &SendStream[Resp]{ctx: ctx, s: s}); err != nil {
+			return err
+		}
+		return s.CloseSend(ctx)
+	}
+}
+
+// HandleClientStream serves a method whose requests stream with fn, which
+// reads them until io.EOF and returns the response.
+func HandleClientStream[Req, Resp proto.Message](fn func(context.Context, *RecvStream[Req]) (Resp, error)) Handler {
+	return func(ctx context.Context, s *StreamConn, reqBytes []byte) error {
+		resp, err := fn(ctx, &RecvStream[Req]{ctx: ctx, s: s})
+		if err != nil {
+			return err
+		}
+		respBytes, err := s.Codec().Marshal(resp)
+		if err != nil {
+			return err
+		}
+		return s.Respond(respBytes)
+	}
+}
+
+// HandleBidiStream serves a method that streams both ways with fn. Once fn
+// returns nil the server waits for the end of the client's stream, dropping
+// requests fn did not read, and ends its own unless fn already did.
+func HandleBidiStream[Req, Resp proto.Message](fn func(context.Context, *BidiStream[Req, Resp]) error) Handler {
+	return func(ctx context.Context, s *StreamConn, reqBytes []byte) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		st := newBidiStream[Req, Resp](ctx, s)
+		if err := fn(ctx, st); err != nil {
+			return err
+		}
+		if err := st.drain(); err != nil {
+			return err
+		}
+		if s.State() == StreamClosed {
+			return nil
+		}
+		return s.CloseSend(ctx)
+	}
+}
+
+// CallUnary calls a method whose request and response are single messages.
+func CallUnary[Req, Resp proto.Message](ctx context.Context, s *StreamConn, method string, req Req) (Resp, error) {
+	var zero Resp
+	reqBytes, err := s.Codec().Marshal(req)
+	if err != nil {
+		return zero, fmt.Errorf("marshal request: %w", err)
+	}
+	if err := s.Call(ctx, method, reqBytes, false); err != nil {
+		return zero, fmt.Errorf("write call: %w", err)
+	}
+	respBytes, err := s.RecvResponse(ctx)
+	if err != nil {
+		return zero, fmt.Errorf("read response: %w", err)
+	}
+	resp, err := decode[Resp](s, respBytes)
+	if err != nil {
+		return zero, fmt.Errorf("unmarshal response: %w", err)
+	}
+	return resp, nil
+}
+
+// CallServerStream calls a method whose responses stream. The caller reads
+// them until io.EOF.
+func CallServerStream[Req, Resp proto.Message](ctx context.Context, s *StreamConn, method string, req Req) (*RecvStream[Resp], error) {
+	reqBytes, err := s.Codec().Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	if err := s.Call(ctx, method, reqBytes, false); err != nil {
+		return nil, fmt.Errorf("write call: %w", err)
+	}
+	return &RecvStream[Resp]{ctx: ctx, s: s}, nil
+}
+
+// CallClientStream calls a method whose requests stream. The caller sends
+// them and collects the response with CloseAndRecv.
+func CallClientStream[Req, Resp proto.Message](ctx context.Context, s *StreamConn, method string) (*ClientStream[Req, Resp], error) {
+	if err := s.Call(ctx, method, nil, true); err != nil {
+		return nil, fmt.Errorf("write call: %w", err)
+	}
+	return &ClientStream[Req, Resp]{SendStream[Req]{ctx: ctx, s: s}}, nil
+}
+
+// CallBidiStream calls a method that streams both ways. The caller sends
+// its requests, ends them with CloseSend and reads the responses until
+// io.EOF.
+func CallBidiStream[Req, Resp proto.Message](ctx context.Context, s *StreamConn, method string) (*BidiStream[Resp, Req], error) {
+	if err := s.Call(ctx, method, nil, true); err != nil {
+		return nil, fmt.Errorf("write call: %w", err)
+	}
+	return newBidiStream[Resp, Req](ctx, s), nil
+}
+
+// SendStream sends the messages of one direction of a call.
+type SendStream[M proto.Message] struct {
+	ctx context.Context
+	s   *StreamConn
+}
+
+// Send writes m as a STREAM_MSG, waiting for flow-control credit if needed.
+func (st *SendStream[M]) Send(m M) error {
+	b, err := st.s.Codec().Marshal(m)
+	if err != nil {
+		return err
+	}
+	return st.s.Send(st.ctx, b)
+}
+
+// RecvStream receives the messages of one direction of a call.
+type RecvStream[M proto.Message] struct {
+	ctx context.Context
+	s   *StreamConn
+}
+
+// Recv returns the next message, returning its size to the sender's window.
+// It returns io.EOF once the sender has ended its stream and a *RemoteError
+// if the peer failed the call.
+func (st *RecvStream[M]) Recv() (M, error) {
+	var zero M
+	b, err := st.s.RecvMsg(st.ctx)
+	if err != nil {
+		return zero, err
+	}
+	m, err := decode[M](st.s, b)
+	if err != nil {
+		return zero, err
+	}
+	if err := st.s.Consume(len(b)); err != nil {
+		return zero, err
+	}
+	return m, nil
+}
+
+// ClientStream is the client's side of a call whose requests stream.
+type ClientStream[Req, Resp proto.Message] struct {
+	SendStream[Req]
+}
+
+// CloseAndRecv ends the requests and returns the server's response.
+func (st *ClientStream[Req, Resp]) CloseAndRecv() (Resp, error) {
+	var zero Resp
+	if err := st.s.CloseSend(st.ctx); err != nil {
+		return zero, fmt.Errorf("write end: %w", err)
+	}
+	respBytes, err := st.s.RecvResponse(st.ctx)
+	if err != nil {
+		return zero, fmt.Errorf("read response: %w", err)
+	}
+	resp, err := decode[Resp](st.s, respBytes)
+	if err != nil {
+		return zero, fmt.Errorf("unmarshal response: %w", err)
+	}
+	return resp, nil
+}
+
+// BidiStream is one side of a call that streams both ways, receiving In
+// and sending Out. A goroutine reads incoming messages into an unbounded
+// queue, so that WINDOW_UPDATEs keep being applied while Send waits for
+// credit; flow control is what bounds the queue. Recv and Send may be
+// called from different goroutines.
+type BidiStream[In, Out proto.Message] struct {
+	ctx context.Context
+	s   *StreamConn
+
+	mu     sync.Mutex
+	queue  []inbound[In]
+	err    error
+	notify chan struct{}
+	done   chan struct{}
+}
+
+type inbound[M proto.Message] struct {
+	msg  M
+	size int
+}
+
+func newBidiStream[In, Out proto.Message](ctx context.Context, s *StreamConn) *BidiStream[In, Out] {
+	st := &BidiStream[In, Out]{ctx: ctx, s: s, notify: make(chan struct{}, 1), done: make(chan struct{})}
+	go st.read()
+	return st
+}
+
+// read queues incoming messages until the peer's stream ends or fails,
+// leaving io.EOF or the failure in err.
+func (st *BidiStream[In, Out]) read() {
+	defer close(st.done)
+	for {
+		b, err := st.s.RecvMsg(st.ctx)
+		var m In
+		if err == nil {
+			m, err = decode[In](st.s, b)
+		}
+		st.mu.Lock()
+		if err != nil {
+			st.err = err
+		} else {
+			st.queue = append(st.queue, inbound[In]{m, len(b)})
+		}
+		st.mu.Unlock()
+		select {
+		case st.notify <- struct{}{}:
+		default:
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Recv returns the next message, returning its size to the sender's window.
+// It returns io.EOF once the peer has ended its stream and a *RemoteError if
+// the peer failed the call.
+func (st *BidiStream[In, Out]) Recv() (In, error) {
+	var zero In
+	for {
+		st.mu.Lock()
+		if len(st.queue) > 0 {
+			in := st.queue[0]
+			st.queue = st.queue[1:]
+			st.mu.Unlock()
+			if err := st.s.Consume(in.size); err != nil {
+				return zero, err
+			}
+			return in.msg, nil
+		}
+		err := st.err
+		st.mu.Unlock()
+		if err != nil {
+			return zero, err
+		}
+		select {
+		case <-st.notify:
+		case <-st.ctx.Done():
+			return zero, st.ctx.Err()
+		}
+	}
+}
+
+// Send writes m as a STREAM_MSG, waiting for flow-control credit if needed.
+func (st *BidiStream[In, Out]) Send(m Out) error {
+	b, err := st.s.Codec().Marshal(m)
+	if err != nil {
+		return err
+	}
+	return st.s.SendDuplex(st.ctx, b, st.done)
+}
+
+// CloseSend ends this side's messages: a client's requests or, when a
+// server has said all it will before the client is done, its responses.
+// Otherwise a server's stream is ended for it when its method returns.
+func (st *BidiStream[In, Out]) CloseSend() error {
+	return st.s.CloseSend(st.ctx)
+}
+
+// drain waits for the end of the peer's stream and returns the messages
+// nobody read to the peer's window.
+func (st *BidiStream[In, Out]) drain() error {
+	select {
+	case <-st.done:
+	case <-st.ctx.Done():
+		return st.ctx.Err()
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.err != io.EOF {
+		return st.err
+	}
+	for _, in := range st.queue {
+		if err := st.s.Consume(in.size); err != nil {
+			return err
+		}
+	}
+	st.queue = nil
+	return nil
+}
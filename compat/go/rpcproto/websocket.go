@@ -0,0 +1,354 @@
+package rpcproto
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket transport (RFC 6455). The frame protocol's byte stream is carried
+// in binary messages; FrameWriter writes each frame with one Write, so each
+// message holds exactly one frame, but readers treat the messages as a stream
+// and accept frames split or joined across them. Addresses look like
+// "ws:host:port/path", where the path defaults to "/".
+
+// WebSocketProtocol is the subprotocol offered by Dial and accepted by a
+// WebSocket listener. Peers may leave it out.
+const WebSocketProtocol = "compat-rpc"
+
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xa
+
+	wsAcceptGUID       = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	wsHandshakeTimeout = 10 * time.Second
+	// wsMaxControl is the largest control frame payload RFC 6455 allows.
+	wsMaxControl = 125
+)
+
+// ErrWebSocket means the peer broke the WebSocket protocol, in the opening
+// handshake or afterwards.
+var ErrWebSocket = errors.New("websocket protocol error")
+
+// splitWebSocketAddr parses the part of a "ws:" address after the scheme.
+func splitWebSocketAddr(addr string) (hostport, path string) {
+	if i := strings.IndexByte(addr, '/'); i >= 0 {
+		return addr[:i], addr[i:]
+	}
+	return addr, "/"
+}
+
+func wsAccept(key string) string {
+	h := sha1.Sum([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+func headerHas(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wsListener accepts TCP connections and completes the WebSocket opening
+// handshake on each before handing it out.
+type wsListener struct {
+	net.Listener
+	path string
+}
+
+func listenWebSocket(addr string) (net.Listener, error) {
+	hostport, path := splitWebSocketAddr(addr)
+	ln, err := net.Listen("tcp", hostport)
+	if err != nil {
+		return nil, err
+	}
+	return &wsListener{Listener: ln, path: path}, nil
+}
+
+// Accept returns the next connection whose handshake succeeds. Connections
+// that fail it are answered with an HTTP error and dropped.
+func (l *wsListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		ws, err := l.handshake(conn)
+		if err == nil {
+			return ws, nil
+		}
+		conn.Close()
+	}
+}
+
+func (l *wsListener) handshake(conn net.Conn) (net.Conn, error) {
+	conn.SetDeadline(time.Now().Add(wsHandshakeTimeout))
+	br := bufio.NewReader(conn)
+	req, err := http.ReadRequest(br)
+	if err != nil {
+		return nil, err
+	}
+	status := 0
+	switch {
+	case req.URL.Path != l.path:
+		status = http.StatusNotFound
+	case req.Method != http.MethodGet,
+		!headerHas(req.Header, "Connection", "upgrade"),
+		!headerHas(req.Header, "Upgrade", "websocket"),
+		req.Header.Get("Sec-WebSocket-Key") == "":
+		status = http.StatusBadRequest
+	case req.Header.Get("Sec-WebSocket-Version") != "13":
+		status = http.StatusUpgradeRequired
+	}
+	if status != 0 {
+		fmt.Fprintf(conn, "HTTP/1.1 %d %s\r\nSec-WebSocket-Version: 13\r\nContent-Length: 0\r\nConnection: close\r\n\r\n", status, http.StatusText(status))
+		return nil, fmt.Errorf("%w: handshake refused with %d", ErrWebSocket, status)
+	}
+	resp := "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + wsAccept(req.Header.Get("Sec-WebSocket-Key")) + "\r\n"
+	if headerHas(req.Header, "Sec-WebSocket-Protocol", WebSocketProtocol) {
+		resp += "Sec-WebSocket-Protocol: " + WebSocketProtocol + "\r\n"
+	}
+	if _, err := io.WriteString(conn, resp+"\r\n"); err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return &wsConn{Conn: conn, br: br}, nil
+}
+
+func dialWebSocket(ctx context.Context, addr string) (net.Conn, error) {
+	hostport, path := splitWebSocketAddr(addr)
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", hostport)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := clientHandshake(ctx, conn, hostport, path)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ws, nil
+}
+
+func clientHandshake(ctx context.Context, conn net.Conn, host, path string) (net.Conn, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(wsHandshakeTimeout))
+	}
+	var nonce [16]byte
+	rand.Read(nonce[:])
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+	req := "GET " + path + " HTTP/1.1\r\nHost: " + host + "\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Protocol: " + WebSocketProtocol + "\r\n\r\n"
+	if _, err := io.WriteString(conn, req); err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, &http.Request{Method: http.MethodGet})
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("%w: handshake answered %s", ErrWebSocket, resp.Status)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != wsAccept(key) {
+		return nil, fmt.Errorf("%w: bad Sec-WebSocket-Accept", ErrWebSocket)
+	}
+	conn.SetDeadline(time.Time{})
+	return &wsConn{Conn: conn, br: br, client: true}, nil
+}
+
+// wsConn is a net.Conn whose Read and Write carry the byte stream in
+// WebSocket binary messages. Clients mask what they send, as RFC 6455
+// requires, and servers insist on it.
+type wsConn struct {
+	net.Conn
+	br     *bufio.Reader
+	client bool
+
+	// Read state: bytes left in the current data frame and its mask.
+	remain  uint64
+	masked  bool
+	mask    [4]byte
+	maskPos int
+	closed  bool
+
+	wmu       sync.Mutex
+	wbuf      []byte
+	closeSent bool
+}
+
+func (c *wsConn) Read(p []byte) (int, error) {
+	for c.remain == 0 {
+		if c.closed {
+			return 0, io.EOF
+		}
+		if err := c.nextFrame(); err != nil {
+			return 0, err
+		}
+	}
+	if uint64(len(p)) > c.remain {
+		p = p[:c.remain]
+	}
+	n, err := c.br.Read(p)
+	if c.masked {
+		for i := range n {
+			p[i] ^= c.mask[c.maskPos&3]
+			c.maskPos++
+		}
+	}
+	c.remain -= uint64(n)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// nextFrame reads frame headers until one starts data, answering control
+// frames along the way. A close frame ends the stream.
+func (c *wsConn) nextFrame() error {
+	var hdr [2]byte
+	if _, err := io.ReadFull(c.br, hdr[:]); err != nil {
+		return err
+	}
+	opcode := hdr[0] & 0x0f
+	if hdr[0]&0x70 != 0 {
+		return fmt.Errorf("%w: reserved bits set", ErrWebSocket)
+	}
+	c.masked = hdr[1]&0x80 != 0
+	if c.masked == c.client {
+		return fmt.Errorf("%w: masking from the wrong side", ErrWebSocket)
+	}
+	length := uint64(hdr[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if c.masked {
+		if _, err := io.ReadFull(c.br, c.mask[:]); err != nil {
+			return err
+		}
+	}
+	c.maskPos = 0
+
+	switch opcode {
+	case wsBinary, wsContinuation:
+		c.remain = length
+		return nil
+	case wsText:
+		return fmt.Errorf("%w: text message", ErrWebSocket)
+	case wsClose, wsPing, wsPong:
+		if length > wsMaxControl || hdr[0]&0x80 == 0 {
+			return fmt.Errorf("%w: bad control frame", ErrWebSocket)
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(c.br, payload); err != nil {
+			return err
+		}
+		if c.masked {
+			for i := range payload {
+				payload[i] ^= c.mask[i&3]
+			}
+		}
+		switch opcode {
+		case wsClose:
+			c.closed = true
+			// Echo the status code back, as the closing handshake asks.
+			if len(payload) > 2 {
+				payload = payload[:2]
+			}
+			c.writeFrame(wsClose, payload)
+		case wsPing:
+			return c.writeFrame(wsPong, payload)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown opcode 0x%x", ErrWebSocket, opcode)
+	}
+}
+
+// Write sends p as one binary message.
+func (c *wsConn) Write(p []byte) (int, error) {
+	if err := c.writeFrame(wsBinary, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closeSent {
+		return net.ErrClosed
+	}
+	c.closeSent = opcode == wsClose
+	buf := append(c.wbuf[:0], 0x80|opcode)
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n < 126:
+		buf = append(buf, maskBit|byte(n))
+	case n <= 0xffff:
+		buf = append(buf, maskBit|126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf = append(buf, maskBit|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+	if c.client {
+		var mask [4]byte
+		rand.Read(mask[:])
+		buf = append(buf, mask[:]...)
+		start := len(buf)
+		buf = append(buf, payload...)
+		for i := range payload {
+			buf[start+i] ^= mask[i&3]
+		}
+	} else {
+		buf = append(buf, payload...)
+	}
+	c.wbuf = buf
+	_, err := c.Conn.Write(buf)
+	return err
+}
+
+// Close sends a normal-closure close frame and closes the connection.
+func (c *wsConn) Close() error {
+	c.writeFrame(wsClose, []byte{0x03, 0xe8})
+	return c.Conn.Close()
+}
@@ -0,0 +1,104 @@
+package rpcproto
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func listenWebSocketTest(t *testing.T, path string) net.Listener {
+	t.Helper()
+	ln, err := Listen("ws:127.0.0.1:0" + path)
+	if err != nil {
+		t.Skipf("no loopback: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	return ln
+}
+
+func TestWebSocketFrames(t *testing.T) {
+	ln := listenWebSocketTest(t, "/rpc")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Sizes cover the 7-bit, 16-bit and 64-bit WebSocket length forms.
+	payloads := [][]byte{nil, []byte("hello"), bytes.Repeat([]byte{0xa5}, 300), bytes.Repeat([]byte{0x5a}, 70000)}
+	echoed := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			echoed <- err
+			return
+		}
+		defer conn.Close()
+		for range payloads {
+			f, err := ReadFrame(conn)
+			if err != nil {
+				echoed <- err
+				return
+			}
+			if err := WriteFrame(conn, FrameResponse, f.Payload); err != nil {
+				echoed <- err
+				return
+			}
+		}
+		echoed <- nil
+	}()
+
+	conn, err := Dial(ctx, "ws:"+ln.Addr().String()+"/rpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	for _, p := range payloads {
+		if err := WriteFrame(conn, FrameCall, p); err != nil {
+			t.Fatal(err)
+		}
+		f, err := ReadFrame(conn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Type != FrameResponse || !bytes.Equal(f.Payload, p) {
+			t.Fatalf("%d-byte payload came back as 0x%02x with %d bytes", len(p), f.Type, len(f.Payload))
+		}
+	}
+	if err := <-echoed; err != nil {
+		t.Fatalf("server: %v", err)
+	}
+	// The server's close frame ends the stream cleanly.
+	if _, err := ReadFrame(conn); err != io.EOF {
+		t.Fatalf("after close: err = %v, want io.EOF", err)
+	}
+}
+
+func TestWebSocketWrongPath(t *testing.T) {
+	ln := listenWebSocketTest(t, "/rpc")
+	go func() {
+		if conn, err := ln.Accept(); err == nil {
+			conn.Close()
+		}
+	}()
+	_, err := Dial(context.Background(), "ws:"+ln.Addr().String()+"/other")
+	if !errors.Is(err, ErrWebSocket) {
+		t.Fatalf("err = %v, want ErrWebSocket", err)
+	}
+}
+
+func TestWebSocketRejectsText(t *testing.T) {
+	a, b := net.Pipe()
+	defer a.Close()
+	go func() {
+		// An unmasked text frame from a server.
+		b.Write([]byte{0x81, 0x02, 'h', 'i'})
+		b.Close()
+	}()
+	c := &wsConn{Conn: a, br: bufio.NewReader(a), client: true}
+	if _, err := c.Read(make([]byte, 8)); !errors.Is(err, ErrWebSocket) {
+		t.Fatalf("err = %v, want ErrWebSocket", err)
+	}
+}
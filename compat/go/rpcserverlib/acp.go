@@ -0,0 +1,243 @@
+package rpcserverlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+
+	"compat/pb"
+	"compat/rpcproto"
+	"compat/testcases"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// acpVersion is the only ACP protocol version the session accepts.
+const acpVersion = 1
+
+// acpChunkSize is the largest payload_chunk the session sends per UPDATED.
+const acpChunkSize = 1024
+
+type acpAsset struct {
+	data        []byte
+	compression pb.AcpCompression
+}
+
+// acpCatalog is the set of assets every ACP session starts with: the asset
+// of the corpus payload cases, compressed, and an incompressible one large
+// enough to take several chunks.
+var acpCatalog = map[string]acpAsset{
+	"asset://meshes/rock.bin":    {testcases.AcpAsset(), pb.AcpCompression_GZIP},
+	"asset://textures/noise.bin": {acpNoise(16 << 10), pb.AcpCompression_UNCOMPRESSED},
+}
+
+func acpNoise(n int) []byte {
+	rng := rand.New(rand.NewPCG(1, 2))
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(rng.Uint32())
+	}
+	return b
+}
+
+// AcpSession plays the asset side of an ACP session over a
+// bidirectional stream. The client must open with HELLO; after that each
+// message gets its replies before the next is read:
+//
+//   - REQUEST streams the asset as UPDATED chunks followed by READY, or
+//     answers STATUS NOT_FOUND. With force_recook the asset's uri_version
+//     is bumped first;
+//   - UPDATED chunks upload an asset, as for Verify. The last one, once
+//     checked, registers the asset as its URI's next version and answers
+//     READY with the new metadata; a bad upload answers BAD_REQUEST. The
+//     chunks before it get no reply;
+//   - DISCOVER_REQUEST answers DISCOVER with every registered URI;
+//   - STATUS_REQUEST answers STATUS with the asset's metadata;
+//   - DELOAD evicts the URIs it names and answers DELETED with those that
+//     were registered.
+//
+// Each session has its own registry, which starts as the catalog.
+// Anything else gets STATUS BAD_REQUEST. A HELLO with the wrong version, or
+// any message before HELLO, ends the session after its reply.
+func (harnessServer) AcpSession(ctx context.Context, stream *rpcproto.BidiStream[*pb.AcpMessage, *pb.AcpMessage]) error {
+	reg := newAcpRegistry()
+	uploads := map[uint64]*acpUpload{}
+	hello := false
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hello {
+			if err := acpReply(reg, uploads, req, stream.Send); err != nil {
+				return err
+			}
+			continue
+		}
+
+		reply := &pb.AcpMessage{Kind: pb.AcpMessageKind_HELLO_STATUS, RequestId: req.RequestId, Version: proto.Uint32(acpVersion)}
+		switch {
+		case req.Kind != pb.AcpMessageKind_HELLO:
+			reply = &pb.AcpMessage{
+				Kind:      pb.AcpMessageKind_STATUS,
+				RequestId: req.RequestId,
+				Status:    pb.AcpStatusCode_BAD_REQUEST.Enum(),
+				Detail:    proto.String(fmt.Sprintf("%v before HELLO", req.Kind)),
+			}
+		case req.GetVersion() != acpVersion:
+			reply.Status = pb.AcpStatusCode_BAD_REQUEST.Enum()
+			reply.Detail = proto.String(fmt.Sprintf("unsupported version %d", req.GetVersion()))
+		default:
+			reply.Status = pb.AcpStatusCode_OK.Enum()
+			hello = true
+		}
+		if err := stream.Send(reply); err != nil {
+			return err
+		}
+		if !hello {
+			// The session is over. The stub drops whatever the client
+			// sends before its STREAM_END.
+			return stream.CloseSend()
+		}
+	}
+}
+
+func acpReply(reg *acpRegistry, uploads map[uint64]*acpUpload, req *pb.AcpMessage, send func(*pb.AcpMessage) error) error {
+	switch req.Kind {
+	case pb.AcpMessageKind_REQUEST, pb.AcpMessageKind_STATUS_REQUEST:
+		asset, ok := reg.lookup(req.GetUri())
+		if !ok {
+			return send(&pb.AcpMessage{
+				Kind:      pb.AcpMessageKind_STATUS,
+				RequestId: req.RequestId,
+				Uri:       req.Uri,
+				Status:    pb.AcpStatusCode_NOT_FOUND.Enum(),
+				Detail:    proto.String("asset not found in registry"),
+			})
+		}
+		if req.Kind == pb.AcpMessageKind_REQUEST && req.GetForceRecook() {
+			reg.recook(asset)
+		}
+		meta := asset.metadata(req.GetUri())
+		if req.Kind == pb.AcpMessageKind_STATUS_REQUEST {
+			return send(&pb.AcpMessage{
+				Kind:      pb.AcpMessageKind_STATUS,
+				RequestId: req.RequestId,
+				Uri:       req.Uri,
+				Status:    pb.AcpStatusCode_OK.Enum(),
+				Metadata:  meta,
+			})
+		}
+		chunk, err := testcases.CompressAcpPayload(asset.compression, asset.data)
+		if err != nil {
+			return err
+		}
+		total := (len(chunk) + acpChunkSize - 1) / acpChunkSize
+		for i := 0; i < total; i++ {
+			part := chunk[i*acpChunkSize : min((i+1)*acpChunkSize, len(chunk))]
+			msg := &pb.AcpMessage{
+				Kind:         pb.AcpMessageKind_UPDATED,
+				RequestId:    req.RequestId,
+				Uri:          req.Uri,
+				ChunkIndex:   uint32(i),
+				ChunkTotal:   uint32(total),
+				PayloadChunk: part,
+			}
+			if i == 0 {
+				msg.Metadata = meta
+			}
+			if err := send(msg); err != nil {
+				return err
+			}
+		}
+		return send(&pb.AcpMessage{
+			Kind:      pb.AcpMessageKind_READY,
+			RequestId: req.RequestId,
+			Uri:       req.Uri,
+			Metadata:  meta,
+		})
+
+	case pb.AcpMessageKind_UPDATED:
+		u := uploads[req.RequestId]
+		if u == nil {
+			u = &acpUpload{}
+			uploads[req.RequestId] = u
+		}
+		u.add(req)
+		if !u.done() {
+			return nil
+		}
+		delete(uploads, req.RequestId)
+		uri := u.first.GetUri()
+		asset, err := u.finish()
+		if err == nil && uri == "" {
+			err = errors.New("upload has no uri")
+		}
+		if err != nil {
+			return send(&pb.AcpMessage{
+				Kind:      pb.AcpMessageKind_STATUS,
+				RequestId: req.RequestId,
+				Uri:       u.first.Uri,
+				Status:    pb.AcpStatusCode_BAD_REQUEST.Enum(),
+				Detail:    proto.String(err.Error()),
+			})
+		}
+		entry := reg.register(uri, asset)
+		return send(&pb.AcpMessage{
+			Kind:      pb.AcpMessageKind_READY,
+			RequestId: req.RequestId,
+			Uri:       u.first.Uri,
+			Metadata:  entry.metadata(uri),
+		})
+
+	case pb.AcpMessageKind_DISCOVER_REQUEST:
+		return send(&pb.AcpMessage{Kind: pb.AcpMessageKind_DISCOVER, RequestId: req.RequestId, Uris: reg.uris()})
+
+	case pb.AcpMessageKind_DELOAD:
+		return send(&pb.AcpMessage{Kind: pb.AcpMessageKind_DELETED, RequestId: req.RequestId, Uris: reg.deload(req.Uris)})
+
+	default:
+		return send(&pb.AcpMessage{
+			Kind:      pb.AcpMessageKind_STATUS,
+			RequestId: req.RequestId,
+			Status:    pb.AcpStatusCode_BAD_REQUEST.Enum(),
+			Detail:    proto.String(fmt.Sprintf("unsupported message kind %v", req.Kind)),
+		})
+	}
+}
+
+// acpServer implements AcpService.
+type acpServer struct{}
+
+// Verify checks an uploaded asset against the metadata claimed for it. The
+// client streams UPDATED messages whose payload_chunks, in chunk_index
+// order, make up the asset compressed as the first chunk's metadata says;
+// the reply is STATUS OK if the decompressed bytes match file_length and
+// payload_hash, or BAD_REQUEST with the reason.
+func (acpServer) Verify(ctx context.Context, stream *rpcproto.RecvStream[*pb.AcpMessage]) (*pb.AcpMessage, error) {
+	u := &acpUpload{}
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		u.add(msg)
+	}
+
+	reply := &pb.AcpMessage{Kind: pb.AcpMessageKind_STATUS, Status: pb.AcpStatusCode_OK.Enum()}
+	if u.first != nil {
+		reply.RequestId, reply.Uri = u.first.RequestId, u.first.Uri
+	}
+	if _, err := u.finish(); err != nil {
+		reply.Status, reply.Detail = pb.AcpStatusCode_BAD_REQUEST.Enum(), proto.String(err.Error())
+	}
+	return reply, nil
+}
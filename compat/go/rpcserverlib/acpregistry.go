@@ -0,0 +1,164 @@
+package rpcserverlib
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"compat/acphash"
+	"compat/pb"
+	"compat/testcases"
+)
+
+// acpEpoch is the updated_at_ns of the catalog's assets. Every change to a
+// registry advances its clock by a second from there, rather than reading
+// the wall clock, so that transcripts of a session are reproducible.
+const acpEpoch = 1700000000000000000
+
+type acpEntry struct {
+	acpAsset
+	version   int64
+	updatedAt int64
+}
+
+func (e *acpEntry) metadata(uri string) *pb.AcpAssetMetadata {
+	return &pb.AcpAssetMetadata{
+		Uri:         uri,
+		CachePath:   "/var/cache/acp/" + strings.TrimPrefix(uri, "asset://"),
+		PayloadHash: acphash.Sum(e.data),
+		FileLength:  int64(len(e.data)),
+		UriVersion:  e.version,
+		UpdatedAtNs: e.updatedAt,
+		Compression: e.compression,
+	}
+}
+
+// acpRegistry is the asset cache behind one ACP session. It starts as the
+// catalog at version 1; clients add and replace assets by uploading them,
+// bump versions with force_recook and evict assets with DELOAD.
+type acpRegistry struct {
+	assets map[string]*acpEntry
+	clock  int64
+}
+
+func newAcpRegistry() *acpRegistry {
+	r := &acpRegistry{assets: map[string]*acpEntry{}, clock: acpEpoch}
+	for uri, a := range acpCatalog {
+		r.assets[uri] = &acpEntry{acpAsset: a, version: 1, updatedAt: acpEpoch}
+	}
+	return r
+}
+
+func (r *acpRegistry) tick() int64 {
+	r.clock += int64(time.Second)
+	return r.clock
+}
+
+func (r *acpRegistry) lookup(uri string) (*acpEntry, bool) {
+	e, ok := r.assets[uri]
+	return e, ok
+}
+
+// register stores a as uri's next version, its first if uri is new.
+func (r *acpRegistry) register(uri string, a acpAsset) *acpEntry {
+	e, ok := r.assets[uri]
+	if !ok {
+		e = &acpEntry{}
+		r.assets[uri] = e
+	}
+	e.acpAsset = a
+	e.version++
+	e.updatedAt = r.tick()
+	return e
+}
+
+// recook bumps e's version as if the asset had been rebuilt from source.
+// The bytes do not change.
+func (r *acpRegistry) recook(e *acpEntry) {
+	e.version++
+	e.updatedAt = r.tick()
+}
+
+// deload evicts the named assets and returns those it found, in the order
+// given.
+func (r *acpRegistry) deload(uris []string) []string {
+	var gone []string
+	for _, uri := range uris {
+		if _, ok := r.assets[uri]; ok {
+			delete(r.assets, uri)
+			gone = append(gone, uri)
+		}
+	}
+	return gone
+}
+
+func (r *acpRegistry) uris() []string {
+	uris := make([]string, 0, len(r.assets))
+	for uri := range r.assets {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	return uris
+}
+
+// acpUpload reassembles an asset a client sends as UPDATED messages: chunks
+// in chunk_index order, the first carrying the metadata.
+type acpUpload struct {
+	first   *pb.AcpMessage
+	n       uint32
+	chunks  []byte
+	problem string
+}
+
+// add takes the next message. After one is out of place the upload is
+// spoiled, and later ones are only counted.
+func (u *acpUpload) add(msg *pb.AcpMessage) {
+	if u.first == nil {
+		u.first = msg
+	}
+	switch {
+	case u.problem != "":
+	case msg.Kind != pb.AcpMessageKind_UPDATED:
+		u.problem = fmt.Sprintf("chunk %d: kind %v, want UPDATED", u.n, msg.Kind)
+	case u.first.Metadata == nil:
+		u.problem = "first chunk has no metadata"
+	case msg.ChunkIndex != u.n || msg.ChunkTotal != u.first.ChunkTotal:
+		u.problem = fmt.Sprintf("chunk %d of %d arrived as chunk %d", msg.ChunkIndex, msg.ChunkTotal, u.n)
+	default:
+		u.chunks = append(u.chunks, msg.PayloadChunk...)
+	}
+	u.n++
+}
+
+// done reports whether the upload is spoiled or has every chunk the first
+// one announced.
+func (u *acpUpload) done() bool {
+	return u.problem != "" || u.first != nil && u.n >= u.first.ChunkTotal
+}
+
+// finish checks the reassembled asset against its metadata and returns it
+// decompressed.
+func (u *acpUpload) finish() (acpAsset, error) {
+	switch {
+	case u.problem != "":
+		return acpAsset{}, errors.New(u.problem)
+	case u.first == nil:
+		return acpAsset{}, errors.New("no chunks")
+	case u.n != u.first.ChunkTotal:
+		return acpAsset{}, fmt.Errorf("%d of %d chunks arrived", u.n, u.first.ChunkTotal)
+	}
+	meta := u.first.Metadata
+	data, err := testcases.DecompressAcpPayload(meta.Compression, u.chunks)
+	if err != nil {
+		return acpAsset{}, err
+	}
+	if int64(len(data)) != meta.FileLength {
+		return acpAsset{}, fmt.Errorf("payload is %d bytes, file_length %d", len(data), meta.FileLength)
+	}
+	if err := acphash.Verify(data, meta.PayloadHash); err != nil {
+		return acpAsset{}, err
+	}
+	return acpAsset{data: data, compression: meta.Compression}, nil
+}
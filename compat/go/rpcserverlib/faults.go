@@ -0,0 +1,59 @@
+package rpcserverlib
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// ErrInjected is the failure a Faults injects into a call.
+var ErrInjected = errors.New("injected failure")
+
+// Faults delays and fails calls on purpose, so clients' timeout, retry and
+// cancellation paths can be exercised. Random choices come from Seed, so a
+// run with the same calls in the same order injects the same faults.
+type Faults struct {
+	// Latency delays every call before its handler runs.
+	Latency time.Duration
+	// Jitter adds a further random delay in [0, Jitter).
+	Jitter time.Duration
+	// ErrorRate is the fraction of calls, from 0 to 1, failed with
+	// ErrInjected instead of being handled.
+	ErrorRate float64
+	// Slow adds a fixed delay to calls of the listed method paths.
+	Slow map[string]time.Duration
+	Seed uint64
+
+	once sync.Once
+	mu   sync.Mutex
+	rng  *rand.Rand
+}
+
+// inject applies the faults for one call of method. It returns ErrInjected
+// if the call should fail, or ctx's error if ctx ends during the delay.
+func (f *Faults) inject(ctx context.Context, method string) error {
+	f.once.Do(func() { f.rng = rand.New(rand.NewPCG(f.Seed, 0)) })
+	f.mu.Lock()
+	delay := f.Latency + f.Slow[method]
+	if f.Jitter > 0 {
+		delay += time.Duration(f.rng.Int64N(int64(f.Jitter)))
+	}
+	fail := f.ErrorRate > 0 && f.rng.Float64() < f.ErrorRate
+	f.mu.Unlock()
+
+	if delay > 0 {
+		t := time.NewTimer(delay)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if fail {
+		return ErrInjected
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package rpcserverlib
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"compat/pb"
+	"compat/pbrpc"
+	"compat/pbutil"
+	"compat/rpcproto"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// DefaultRegistry returns a registry with every method the reference servers
+// implement: those of UnaryService, StreamingService, AcpService and
+// HarnessService, registered through their pbrpc stubs.
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	pbrpc.RegisterUnaryServiceServer(r, unaryServer{})
+	pbrpc.RegisterStreamingServiceServer(r, streamingServer{})
+	pbrpc.RegisterAcpServiceServer(r, acpServer{})
+	pbrpc.RegisterHarnessServiceServer(r, harnessServer{})
+	return r
+}
+
+// unaryServer implements UnaryService.
+type unaryServer struct{}
+
+func (unaryServer) Ping(ctx context.Context, req *pb.PingRequest) (*pb.PingResponse, error) {
+	return &pb.PingResponse{Payload: req.Payload}, nil
+}
+
+func (unaryServer) GetItem(ctx context.Context, req *pb.GetItemRequest) (*pb.GetItemResponse, error) {
+	return &pb.GetItemResponse{
+		Id:   req.Id,
+		Name: fmt.Sprintf("item_%d", req.Id),
+	}, nil
+}
+
+func (unaryServer) Health(ctx context.Context, req *pb.HealthRequest) (*pb.HealthResponse, error) {
+	return &pb.HealthResponse{Status: "serving"}, nil
+}
+
+func (unaryServer) Echo(ctx context.Context, req *pb.EchoMessage) (*pb.EchoMessage, error) {
+	return &pb.EchoMessage{Text: req.Text, Code: req.Code + 1}, nil
+}
+
+// streamingServer implements StreamingService.
+type streamingServer struct{}
+
+func (streamingServer) UnaryCall(ctx context.Context, req *pb.StreamRequest) (*pb.StreamResponse, error) {
+	return &pb.StreamResponse{Result: req.Query, Index: 0}, nil
+}
+
+func (streamingServer) ServerSide(ctx context.Context, req *pb.StreamRequest, stream *rpcproto.SendStream[*pb.StreamResponse]) error {
+	for i := int32(0); i < 3; i++ {
+		resp := &pb.StreamResponse{
+			Result: fmt.Sprintf("%s_%d", req.Query, i),
+			Index:  i,
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (streamingServer) ClientSide(ctx context.Context, stream *rpcproto.RecvStream[*pb.UploadChunk]) (*pb.UploadResult, error) {
+	count := int32(0)
+	for {
+		// Decoded to verify it's valid, but we just count
+		_, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		count++
+	}
+	return &pb.UploadResult{
+		TotalChunks: count,
+		Summary:     fmt.Sprintf("received_%d_chunks", count),
+	}, nil
+}
+
+// Bidirectional echoes each message as soon as it arrives. The stream reads
+// on its own goroutine, so echoes go out while requests are still coming in.
+func (streamingServer) Bidirectional(ctx context.Context, stream *rpcproto.BidiStream[*pb.ChatMessage, *pb.ChatMessage]) error {
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&pb.ChatMessage{Sender: "echo", Text: msg.Text}); err != nil {
+			return err
+		}
+	}
+}
+
+// maxFirehoseChunk keeps a single FirehoseChunk frame well under
+// rpcproto.MaxPayloadSize.
+const maxFirehoseChunk = 1 << 20
+
+// harnessServer implements HarnessService.
+type harnessServer struct{}
+
+// Firehose streams count chunks as fast as the peer's window allows, so a
+// client reading slowly exercises backpressure. Chunk i carries seq i and
+// chunk_size copies of byte(i).
+func (harnessServer) Firehose(ctx context.Context, req *pb.FirehoseRequest, stream *rpcproto.SendStream[*pb.FirehoseChunk]) error {
+	if req.Count < 0 || req.ChunkSize < 0 || req.ChunkSize > maxFirehoseChunk {
+		return fmt.Errorf("firehose: count=%d chunk_size=%d out of range", req.Count, req.ChunkSize)
+	}
+	for i := int32(0); i < req.Count; i++ {
+		chunk := &pb.FirehoseChunk{Seq: i, Data: bytes.Repeat([]byte{byte(i)}, int(req.ChunkSize))}
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FailAfterN streams n responses and then fails the call with an ERROR
+// frame carrying the request's message instead of STREAM_END, so clients
+// must surface both the partial results and the error.
+func (harnessServer) FailAfterN(ctx context.Context, req *pb.FailAfterNRequest, stream *rpcproto.SendStream[*pb.StreamResponse]) error {
+	for i := int32(0); i < req.N; i++ {
+		if err := stream.Send(&pb.StreamResponse{Result: fmt.Sprintf("partial_%d", i), Index: i}); err != nil {
+			return err
+		}
+	}
+	return errors.New(req.Message)
+}
+
+// EchoScalar answers with the request, re-encoded, so random payloads make
+// a full round trip through the codec.
+func (harnessServer) EchoScalar(ctx context.Context, req *pb.ScalarMessage) (*pb.ScalarMessage, error) {
+	return req, nil
+}
+
+// Blob answers with response_size bytes regardless of the request size, so
+// clients can probe the frame limit in each direction separately. A
+// response over the client's limit is refused by the writer and reported
+// as an ERROR.
+func (harnessServer) Blob(ctx context.Context, req *pb.BlobRequest) (*pb.BlobResponse, error) {
+	if req.ResponseSize < 0 {
+		return nil, fmt.Errorf("blob: response_size=%d", req.ResponseSize)
+	}
+	return &pb.BlobResponse{Data: bytes.Repeat([]byte{0x5a}, int(req.ResponseSize))}, nil
+}
+
+// UploadBlob reassembles a blob sent as UploadChunk messages and reports the
+// chunk count and the hex SHA-256 of the whole.
+func (harnessServer) UploadBlob(ctx context.Context, stream *rpcproto.RecvStream[*pb.UploadChunk]) (*pb.UploadResult, error) {
+	h := sha256.New()
+	count := int32(0)
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		h.Write(chunk.Data)
+		count++
+	}
+	return &pb.UploadResult{TotalChunks: count, Summary: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// EchoAny unpacks an Any holding any registered message, decodes the
+// message and answers with it packed again, so every corpus type can make a
+// round trip without a method of its own. AnyMessage has the wire format
+// of google.protobuf.Any, which does the packing.
+func (harnessServer) EchoAny(ctx context.Context, req *pb.AnyMessage) (*pb.AnyMessage, error) {
+	msg, err := anypb.UnmarshalNew(&anypb.Any{TypeUrl: req.TypeUrl, Value: req.Value}, proto.UnmarshalOptions{AllowPartial: true})
+	if err != nil {
+		return nil, err
+	}
+	packed, err := pbutil.MarshalAny(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AnyMessage{TypeUrl: packed.TypeUrl, Value: packed.Value}, nil
+}
+
+// UnknownFields answers with the request's text and code plus the fields
+// from pbutil.UnknownFields, which EchoMessage does not define. A client
+// that re-encodes the response and sends it to CheckUnknownFields shows
+// that its runtime preserved them.
+func (harnessServer) UnknownFields(ctx context.Context, req *pb.EchoMessage) (*pb.EchoMessage, error) {
+	resp := &pb.EchoMessage{Text: req.Text, Code: req.Code}
+	resp.ProtoReflect().SetUnknown(pbutil.UnknownFields())
+	return resp, nil
+}
+
+// CheckUnknownFields fails the call unless the request carries exactly the
+// unknown fields that UnknownFields added, and otherwise echoes the known
+// ones.
+func (harnessServer) CheckUnknownFields(ctx context.Context, req *pb.EchoMessage) (*pb.EchoMessage, error) {
+	if got, want := []byte(req.ProtoReflect().GetUnknown()), pbutil.UnknownFields(); !bytes.Equal(got, want) {
+		return nil, fmt.Errorf("unknown fields changed: got %x, want %x", got, want)
+	}
+	return &pb.EchoMessage{Text: req.Text, Code: req.Code}, nil
+}
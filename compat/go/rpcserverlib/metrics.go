@@ -0,0 +1,104 @@
+package rpcserverlib
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the call latency
+// histogram buckets: Prometheus's defaults, which reach calls held back by
+// -slow-method, below three finer ones for loopback calls.
+var latencyBuckets = [...]float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// histogram counts call latencies into latencyBuckets. Its fields are
+// guarded by Stats.mu.
+type histogram struct {
+	counts [len(latencyBuckets)]uint64 // per bucket, not cumulative
+	count  uint64
+	sum    time.Duration
+}
+
+func (h *histogram) observe(d time.Duration) {
+	h.count++
+	h.sum += d
+	if i := sort.SearchFloat64s(latencyBuckets[:], d.Seconds()); i < len(h.counts) {
+		h.counts[i]++
+	}
+}
+
+// WriteMetrics writes the counters in the Prometheus text exposition
+// format, for monitoring a long soak run while it goes.
+func (st *Stats) WriteMetrics(w io.Writer) error {
+	r := st.Report()
+	st.mu.Lock()
+	active, conns := st.active, st.conns
+	latency := make(map[string]histogram, len(st.latency))
+	for method, h := range st.latency {
+		latency[method] = *h
+	}
+	st.mu.Unlock()
+
+	var b strings.Builder
+	metric := func(name, typ, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+	metric("rpcserver_calls_total", "counter", "Calls received, by method.")
+	for _, m := range r.Methods {
+		fmt.Fprintf(&b, "rpcserver_calls_total{method=%s} %d\n", label(m.Method), m.Calls)
+	}
+	metric("rpcserver_call_errors_total", "counter", "Calls that ended in an ERROR frame, by method.")
+	for _, m := range r.Methods {
+		fmt.Fprintf(&b, "rpcserver_call_errors_total{method=%s} %d\n", label(m.Method), m.Errors)
+	}
+	metric("rpcserver_call_duration_seconds", "histogram", "Time from a call's CALL frame until it was answered, by method.")
+	for _, m := range r.Methods {
+		h, ok := latency[m.Method]
+		if !ok {
+			continue
+		}
+		var cum uint64
+		for i, le := range latencyBuckets {
+			cum += h.counts[i]
+			fmt.Fprintf(&b, "rpcserver_call_duration_seconds_bucket{method=%s,le=\"%g\"} %d\n", label(m.Method), le, cum)
+		}
+		fmt.Fprintf(&b, "rpcserver_call_duration_seconds_bucket{method=%s,le=\"+Inf\"} %d\n", label(m.Method), h.count)
+		fmt.Fprintf(&b, "rpcserver_call_duration_seconds_sum{method=%s} %g\n", label(m.Method), h.sum.Seconds())
+		fmt.Fprintf(&b, "rpcserver_call_duration_seconds_count{method=%s} %d\n", label(m.Method), h.count)
+	}
+	metric("rpcserver_error_frames_total", "counter", "ERROR frames sent, including those outside a call.")
+	fmt.Fprintf(&b, "rpcserver_error_frames_total %d\n", r.Errors)
+	metric("rpcserver_frames_total", "counter", "Frames read and written.")
+	fmt.Fprintf(&b, "rpcserver_frames_total{direction=\"in\"} %d\n", r.FramesIn)
+	fmt.Fprintf(&b, "rpcserver_frames_total{direction=\"out\"} %d\n", r.FramesOut)
+	metric("rpcserver_bytes_total", "counter", "Bytes read and written, frame headers included.")
+	fmt.Fprintf(&b, "rpcserver_bytes_total{direction=\"in\"} %d\n", r.BytesIn)
+	fmt.Fprintf(&b, "rpcserver_bytes_total{direction=\"out\"} %d\n", r.BytesOut)
+	metric("rpcserver_open_streams", "gauge", "Calls in progress.")
+	fmt.Fprintf(&b, "rpcserver_open_streams %d\n", active)
+	metric("rpcserver_max_open_streams", "gauge", "Most calls in progress at once since the server started.")
+	fmt.Fprintf(&b, "rpcserver_max_open_streams %d\n", r.MaxConcurrentStreams)
+	metric("rpcserver_open_connections", "gauge", "Connections being served.")
+	fmt.Fprintf(&b, "rpcserver_open_connections %d\n", conns)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// label quotes a label value, escaping as the exposition format requires.
+func label(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v) + `"`
+}
+
+// MetricsHandler serves WriteMetrics, for a Prometheus scrape of /metrics.
+func (st *Stats) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := st.WriteMetrics(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,278 @@
+// Package rpcserverlib implements the reference pipe RPC server, so that Go
+// tests and tools can run it in-process as well as through cmd/rpcserver.
+package rpcserverlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"compat/rpcproto"
+	"compat/rpctrace"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DefaultSettings are the settings the server advertises in its HELLO unless
+// told otherwise. Sequence numbers are offered, and used with clients that
+// ask for them too.
+var DefaultSettings = rpcproto.Settings{InitialWindow: rpcproto.DefaultWindow, MaxFrameSize: 4 << 20, Features: rpcproto.FeatureSequence}
+
+// Handler serves one call; see rpcproto.Handler.
+type Handler = rpcproto.Handler
+
+type method struct {
+	handler         Handler
+	clientStreaming bool
+}
+
+// Registry maps method paths to handlers. Calls on a legacy path, one of
+// rpcproto.LegacyPaths or added with Alias, reach the handler of the path it
+// stands for, which is also the path validators and faults see.
+type Registry struct {
+	methods map[string]method
+	paths   *rpcproto.MethodResolver
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{methods: map[string]method{}, paths: rpcproto.NewMethodResolver(nil)}
+}
+
+// Handle registers h for the method path, replacing any earlier handler.
+// clientStreaming marks methods whose request continues as STREAM_MSG frames
+// after the CALL.
+func (r *Registry) Handle(path string, clientStreaming bool, h Handler) {
+	r.methods[path] = method{handler: h, clientStreaming: clientStreaming}
+}
+
+// HandleMethod registers h for md, at the path and with the streaming its
+// descriptor gives. It makes a Registry an rpcproto.Registrar, which the
+// generated Register functions of compat/pbrpc take.
+func (r *Registry) HandleMethod(md protoreflect.MethodDescriptor, h Handler) {
+	r.Handle(rpcproto.MethodPath(md), md.IsStreamingClient(), h)
+}
+
+// Alias routes calls on legacy to the handler for path.
+func (r *Registry) Alias(legacy, path string) {
+	r.paths.Alias(legacy, path)
+}
+
+// Server serves pipe RPC connections.
+type Server struct {
+	Registry *Registry
+	// Settings are advertised to clients that send HELLO.
+	Settings rpcproto.Settings
+	// Stats collects traffic counters and answers STATS frames. It may be
+	// shared between connections; if nil, each connection counts alone.
+	Stats *Stats
+	// Trace, if set, receives a JSON-lines log of every frame.
+	Trace io.Writer
+	// Log, if set, receives a line for each call that fails.
+	Log io.Writer
+	// Faults, if set, delays or fails calls before they are handled.
+	Faults *Faults
+	// Validator, if set, fails calls whose requests break its rules before
+	// they are handled.
+	Validator *Validator
+
+	drainOnce sync.Once
+	drainCh   chan struct{}
+}
+
+var errDrained = errors.New("server drained")
+
+func (srv *Server) draining() chan struct{} {
+	srv.drainOnce.Do(func() { srv.drainCh = make(chan struct{}) })
+	return srv.drainCh
+}
+
+// Drain stops the server taking new calls. ServeListener stops accepting,
+// and each connection sends GOAWAY (if its client handshook), finishes the
+// call in progress and closes, at which point its Serve returns nil. Drain
+// does not wait for that; it is safe to call more than once.
+func (srv *Server) Drain() {
+	ch := srv.draining()
+	select {
+	case <-ch:
+	default:
+		close(ch)
+	}
+}
+
+// Serve serves rw with the methods in reg and DefaultSettings.
+func Serve(ctx context.Context, rw io.ReadWriter, reg *Registry) error {
+	srv := &Server{Registry: reg, Settings: DefaultSettings}
+	return srv.Serve(ctx, rw)
+}
+
+// Serve handles calls on rw until the client sends SHUTDOWN or closes the
+// connection, which return nil. If ctx is done between frames it returns
+// ctx's error; every response written before then is complete. Any other
+// read error ends the connection and is returned.
+func (srv *Server) Serve(ctx context.Context, rw io.ReadWriter) error {
+	s := rpcproto.NewStreamConn(rpcproto.NewConn(rw, rw))
+	stats := srv.Stats
+	if stats == nil {
+		stats = NewStats()
+	}
+	cs := &connStats{Stats: stats}
+	cs.observe(s.Conn)
+	cs.open()
+	defer cs.close()
+	if srv.Trace != nil {
+		rpctrace.New(srv.Trace, "server").Attach(s.Conn)
+	}
+
+	// Draining interrupts the wait for the next frame but not a call in
+	// progress, whose handler reads with ctx.
+	readCtx, stopRead := context.WithCancelCause(ctx)
+	defer stopRead(nil)
+	var negotiated atomic.Bool
+	go func() {
+		select {
+		case <-srv.draining():
+			if negotiated.Load() {
+				if err := s.W.WriteFrame(rpcproto.FrameGoAway, nil); err != nil {
+					srv.logf("goaway: %v", err)
+				}
+			}
+			stopRead(errDrained)
+		case <-readCtx.Done():
+		}
+	}()
+
+	for {
+		frame, err := s.Recv(readCtx)
+		if errors.Is(err, rpcproto.ErrProtocol) {
+			s.Fail(err.Error())
+			continue
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(context.Cause(readCtx), errDrained) {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return context.Cause(ctx)
+			}
+			return fmt.Errorf("read frame: %w", err)
+		}
+
+		switch frame.Type {
+		case rpcproto.FrameShutdown:
+			// Clients that handshook expect a final report before EOF.
+			if s.Negotiated() {
+				if err := stats.send(s.W); err != nil {
+					srv.logf("stats: %v", err)
+				}
+			}
+			return nil
+
+		case rpcproto.FrameStats:
+			if err := stats.send(s.W); err != nil {
+				srv.logf("stats: %v", err)
+			}
+
+		case rpcproto.FrameHello:
+			if err := s.AcceptHello(frame.Payload, srv.Settings); err != nil {
+				s.Fail(err.Error())
+				continue
+			}
+			negotiated.Store(true)
+
+		case rpcproto.FrameCall:
+			method, reqBytes, err := rpcproto.ParseCallPayload(frame.Payload)
+			if err != nil {
+				s.Fail(err.Error())
+				continue
+			}
+			if err := srv.handleCall(ctx, s, method, reqBytes); err != nil {
+				srv.logf("%s: %v", method, err)
+				s.Fail(err.Error())
+			}
+			cs.end()
+
+		default:
+			s.Fail(fmt.Sprintf("unexpected frame type: 0x%02x", frame.Type))
+		}
+	}
+}
+
+func (srv *Server) handleCall(ctx context.Context, s *rpcproto.StreamConn, path string, reqBytes []byte) error {
+	path = srv.Registry.paths.Canonical(path)
+	m, ok := srv.Registry.methods[path]
+	if err := s.Accept(m.clientStreaming); err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("unknown method: %s", path)
+	}
+	if srv.Validator != nil {
+		if err := srv.Validator.check(s.Codec(), path, reqBytes); err != nil {
+			return err
+		}
+	}
+	if srv.Faults != nil {
+		if err := srv.Faults.inject(ctx, path); err != nil {
+			return err
+		}
+	}
+	return m.handler(ctx, s, reqBytes)
+}
+
+func (srv *Server) logf(format string, args ...any) {
+	if srv.Log != nil {
+		fmt.Fprintf(srv.Log, format+"\n", args...)
+	}
+}
+
+// ServeListener accepts connections from ln and serves each on its own
+// goroutine with its own call state; only Stats is shared. It returns when
+// ctx is done, after closing ln and every open connection, or when Accept
+// fails.
+func (srv *Server) ServeListener(ctx context.Context, ln net.Listener) error {
+	if srv.Stats == nil {
+		srv.Stats = NewStats()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-srv.draining():
+		}
+		ln.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for id := 1; ; id++ {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return context.Cause(ctx)
+			}
+			select {
+			case <-srv.draining():
+				return nil
+			default:
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+			// Closing the connection unblocks a Serve waiting on a read.
+			stop := context.AfterFunc(ctx, func() { conn.Close() })
+			defer stop()
+			if err := srv.Serve(ctx, conn); err != nil && ctx.Err() == nil {
+				srv.logf("conn %d: %v", id, err)
+			}
+		}()
+	}
+}
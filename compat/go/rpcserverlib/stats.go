@@ -0,0 +1,152 @@
+package rpcserverlib
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"compat/pb"
+	"compat/pbutil"
+	"compat/rpcproto"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Stats counts the traffic a server has seen, for STATS frames. One Stats
+// may be shared by several connections.
+type Stats struct {
+	mu        sync.Mutex
+	methods   map[string]*pb.MethodStats
+	framesIn  uint64
+	framesOut uint64
+	bytesIn   uint64
+	bytesOut  uint64
+	errors    uint64
+	active    uint32
+	maxActive uint32
+	conns     int
+	latency   map[string]*histogram
+}
+
+// NewStats returns an empty Stats.
+func NewStats() *Stats {
+	return &Stats{methods: map[string]*pb.MethodStats{}, latency: map[string]*histogram{}}
+}
+
+// connStats attributes one connection's frames to its call in progress.
+type connStats struct {
+	*Stats
+	method string    // guarded by Stats.mu; "" between calls
+	start  time.Time // when the call in progress began
+}
+
+// open counts the connection as open until close is called.
+func (cs *connStats) open() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.conns++
+}
+
+func (cs *connStats) close() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.conns--
+}
+
+// observe installs frame counters on c.
+func (cs *connStats) observe(c *rpcproto.Conn) {
+	c.R.AddObserver(func(f *rpcproto.Frame) { cs.count(f, true) })
+	c.W.AddObserver(func(f *rpcproto.Frame) { cs.count(f, false) })
+}
+
+func (cs *connStats) count(f *rpcproto.Frame, in bool) {
+	n := uint64(5 + len(f.Payload))
+	if f.Seq != 0 {
+		n += 4
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if in && f.Type == rpcproto.FrameCall {
+		if method, _, err := rpcproto.ParseCallPayload(f.Payload); err == nil {
+			cs.begin(method)
+		}
+	}
+	m := cs.methods[cs.method]
+	if in {
+		cs.framesIn++
+		cs.bytesIn += n
+		if m != nil {
+			m.BytesIn += n
+		}
+		return
+	}
+	cs.framesOut++
+	cs.bytesOut += n
+	if m != nil {
+		m.BytesOut += n
+	}
+	if f.Type == rpcproto.FrameError {
+		cs.errors++
+		if m != nil {
+			m.Errors++
+		}
+	}
+}
+
+// begin starts attributing traffic to method. Called with mu held.
+func (cs *connStats) begin(method string) {
+	cs.method = method
+	m := cs.methods[method]
+	if m == nil {
+		m = &pb.MethodStats{Method: method}
+		cs.methods[method] = m
+	}
+	m.Calls++
+	cs.start = time.Now()
+	cs.active++
+	cs.maxActive = max(cs.maxActive, cs.active)
+}
+
+// end marks the connection's call, if any, as finished.
+func (cs *connStats) end() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.method != "" {
+		h := cs.latency[cs.method]
+		if h == nil {
+			h = &histogram{}
+			cs.latency[cs.method] = h
+		}
+		h.observe(time.Since(cs.start))
+		cs.method = ""
+		cs.active--
+	}
+}
+
+// Report returns a snapshot of the counters with methods sorted by name.
+func (st *Stats) Report() *pb.StatsReport {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	r := &pb.StatsReport{
+		FramesIn:             st.framesIn,
+		FramesOut:            st.framesOut,
+		BytesIn:              st.bytesIn,
+		BytesOut:             st.bytesOut,
+		Errors:               st.errors,
+		MaxConcurrentStreams: st.maxActive,
+	}
+	for _, m := range st.methods {
+		r.Methods = append(r.Methods, proto.Clone(m).(*pb.MethodStats))
+	}
+	sort.Slice(r.Methods, func(i, j int) bool { return r.Methods[i].Method < r.Methods[j].Method })
+	return r
+}
+
+// send writes a snapshot as a STATS frame.
+func (st *Stats) send(w *rpcproto.FrameWriter) error {
+	b, err := pbutil.Marshal(st.Report())
+	if err != nil {
+		return err
+	}
+	return w.WriteFrame(rpcproto.FrameStats, b)
+}
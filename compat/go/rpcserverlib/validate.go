@@ -0,0 +1,150 @@
+package rpcserverlib
+
+import (
+	"fmt"
+	"math"
+
+	"compat/pb"
+	"compat/rpcproto"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Rule constrains one field of a request, in the manner of protovalidate's
+// field rules but declared in Go. Build rules with NonEmpty and InRange.
+type Rule struct {
+	Field string
+	// nonEmpty requires a string or bytes field to be non-empty.
+	nonEmpty bool
+	// bounded requires an integer field to lie in [lo, hi].
+	bounded bool
+	lo, hi  int64
+}
+
+// NonEmpty requires the string or bytes field to be non-empty.
+func NonEmpty(field string) Rule {
+	return Rule{Field: field, nonEmpty: true}
+}
+
+// InRange requires the integer field to lie between lo and hi inclusive.
+func InRange(field string, lo, hi int64) Rule {
+	return Rule{Field: field, bounded: true, lo: lo, hi: hi}
+}
+
+// check applies r to fd's value in m. It returns nil or an error wrapping
+// rpcproto.ErrInvalidArgument.
+func (r Rule) check(m protoreflect.Message, fd protoreflect.FieldDescriptor) error {
+	v := m.Get(fd)
+	if r.nonEmpty {
+		var n int
+		if fd.Kind() == protoreflect.StringKind {
+			n = len(v.String())
+		} else {
+			n = len(v.Bytes())
+		}
+		if n == 0 {
+			return fmt.Errorf("%w: %s: must not be empty", rpcproto.ErrInvalidArgument, r.Field)
+		}
+	}
+	if r.bounded {
+		var n int64
+		switch fd.Kind() {
+		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			u := v.Uint()
+			if u > math.MaxInt64 {
+				return fmt.Errorf("%w: %s: %d out of range [%d, %d]", rpcproto.ErrInvalidArgument, r.Field, u, r.lo, r.hi)
+			}
+			n = int64(u)
+		default:
+			n = v.Int()
+		}
+		if n < r.lo || n > r.hi {
+			return fmt.Errorf("%w: %s: %d out of range [%d, %d]", rpcproto.ErrInvalidArgument, r.Field, n, r.lo, r.hi)
+		}
+	}
+	return nil
+}
+
+// fits reports whether r can apply to fields like fd.
+func (r Rule) fits(fd protoreflect.FieldDescriptor) bool {
+	if fd.IsList() || fd.IsMap() {
+		return false
+	}
+	switch fd.Kind() {
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return r.nonEmpty && !r.bounded
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return r.bounded && !r.nonEmpty
+	}
+	return false
+}
+
+type methodRules struct {
+	req    protoreflect.MessageType
+	fields []protoreflect.FieldDescriptor
+	rules  []Rule
+}
+
+// Validator checks requests against per-method rules before their handlers
+// run, failing those that break a rule with an ERROR whose message starts
+// "INVALID_ARGUMENT:". Only the request carried by the CALL is checked; the
+// messages of a client stream are not.
+type Validator struct {
+	methods map[string]methodRules
+}
+
+// NewValidator returns a Validator with no rules.
+func NewValidator() *Validator {
+	return &Validator{methods: map[string]methodRules{}}
+}
+
+// Require sets the rules for method, whose requests are messages like req,
+// replacing any set before. It panics if a rule names a field req does not
+// have or cannot apply to its field's type.
+func (v *Validator) Require(method string, req proto.Message, rules ...Rule) {
+	mr := methodRules{req: req.ProtoReflect().Type(), rules: rules}
+	fields := mr.req.Descriptor().Fields()
+	for _, r := range rules {
+		fd := fields.ByName(protoreflect.Name(r.Field))
+		if fd == nil || !r.fits(fd) {
+			panic(fmt.Sprintf("rpcserverlib: rule for %s field %q does not fit %s", method, r.Field, mr.req.Descriptor().FullName()))
+		}
+		mr.fields = append(mr.fields, fd)
+	}
+	v.methods[method] = mr
+}
+
+// DefaultValidator returns the rules cmd/rpcserver applies with -validate.
+// Every request the conformance suites send passes them.
+func DefaultValidator() *Validator {
+	v := NewValidator()
+	unary := pb.File_service_unary_proto.Services().ByName("UnaryService").Methods()
+	v.Require(rpcproto.MethodPath(unary.ByName("Ping")), &pb.PingRequest{}, NonEmpty("payload"))
+	v.Require(rpcproto.MethodPath(unary.ByName("GetItem")), &pb.GetItemRequest{}, InRange("id", 1, math.MaxInt32))
+	v.Require(rpcproto.MethodPath(unary.ByName("Health")), &pb.HealthRequest{}, NonEmpty("service_name"))
+	// Echo answers code+1, so the largest int32 has no answer.
+	v.Require(rpcproto.MethodPath(unary.ByName("Echo")), &pb.EchoMessage{}, InRange("code", math.MinInt32, math.MaxInt32-1))
+	return v
+}
+
+// check decodes reqBytes with codec and applies method's rules, returning
+// the first broken rule's error. Methods without rules always pass.
+func (v *Validator) check(codec rpcproto.Codec, method string, reqBytes []byte) error {
+	mr, ok := v.methods[method]
+	if !ok {
+		return nil
+	}
+	m := mr.req.New()
+	if err := codec.Unmarshal(reqBytes, m.Interface()); err != nil {
+		return fmt.Errorf("%w: %v", rpcproto.ErrInvalidArgument, err)
+	}
+	for i, r := range mr.rules {
+		if err := r.check(m, mr.fields[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
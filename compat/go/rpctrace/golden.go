@@ -0,0 +1,180 @@
+package rpctrace
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"compat/rpcproto"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// GoldenFrame is one frame of a golden transcript. Unlike a trace Record it
+// keeps the whole payload, so the client's side of the conversation can be
+// replayed against another server.
+type GoldenFrame struct {
+	Dir     string `json:"dir"` // "out" (client to server) or "in"
+	Type    byte   `json:"type"`
+	Name    string `json:"name"` // frame type name, for readers
+	Method  string `json:"method,omitempty"`
+	Payload []byte `json:"payload,omitempty"`
+}
+
+// Recorder writes every frame of a client connection to a golden transcript,
+// as JSON lines. It is safe for concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	method string
+	err    error
+}
+
+// NewRecorder returns a Recorder writing to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w)}
+}
+
+// Attach records every frame c reads and writes.
+func (r *Recorder) Attach(c *rpcproto.Conn) {
+	c.R.AddObserver(func(f *rpcproto.Frame) { r.record(f, "in") })
+	c.W.AddObserver(func(f *rpcproto.Frame) { r.record(f, "out") })
+}
+
+// Err returns the first error writing a frame. Recording stops after it.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) record(f *rpcproto.Frame, dir string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	if f.Type == rpcproto.FrameCall {
+		if method, _, err := rpcproto.ParseCallPayload(f.Payload); err == nil {
+			r.method = method
+		}
+	}
+	r.err = r.enc.Encode(&GoldenFrame{
+		Dir:     dir,
+		Type:    f.Type,
+		Name:    rpcproto.FrameTypeName(f.Type),
+		Method:  r.method,
+		Payload: f.Payload,
+	})
+}
+
+// ReadGolden reads a transcript written by a Recorder.
+func ReadGolden(r io.Reader) ([]GoldenFrame, error) {
+	var frames []GoldenFrame
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 2*rpcproto.MaxPayloadSize)
+	for line := 1; sc.Scan(); line++ {
+		var f GoldenFrame
+		if err := json.Unmarshal(sc.Bytes(), &f); err != nil {
+			return nil, fmt.Errorf("golden transcript line %d: %w", line, err)
+		}
+		frames = append(frames, f)
+	}
+	return frames, sc.Err()
+}
+
+// replayTimeout bounds the wait for each frame the transcript expects.
+const replayTimeout = 10 * time.Second
+
+// Replay sends the client frames of a transcript to the server on c, in their
+// recorded order, and compares each frame the server sends with the recorded
+// one. Payloads that differ in bytes but decode to equal messages match, so
+// servers may encode differently. WINDOW_UPDATE frames from the server are
+// ignored, since they depend on timing, and so are STATS payloads. Every
+// difference is written to log; Replay returns how many there were, or an
+// error if the connection failed. If the recorded HELLO asked for sequence
+// numbers and the server grants them, the rest of the replay uses them.
+func Replay(ctx context.Context, c *rpcproto.Conn, frames []GoldenFrame, log io.Writer) (int, error) {
+	diffs := 0
+	var hello rpcproto.Settings
+	for i, want := range frames {
+		if want.Dir == "out" {
+			if err := c.W.WriteFrameContext(ctx, want.Type, want.Payload); err != nil {
+				return diffs, fmt.Errorf("frame %d: send %s: %w", i, want.Name, err)
+			}
+			if want.Type == rpcproto.FrameHello {
+				hello, _ = rpcproto.ParseSettings(want.Payload)
+			}
+			continue
+		}
+		if want.Type == rpcproto.FrameWindowUpdate {
+			continue
+		}
+		got, err := readIgnoringWindow(ctx, c)
+		if err != nil {
+			return diffs, fmt.Errorf("frame %d: waiting for %s: %w", i, want.Name, err)
+		}
+		if diff := compareFrame(want, got); diff != "" {
+			diffs++
+			fmt.Fprintf(log, "DRIFT frame %d (%s): %s\n", i, want.Method, diff)
+		}
+		if got.Type == rpcproto.FrameHello {
+			if peer, err := rpcproto.ParseSettings(got.Payload); err == nil && hello.Features&peer.Features&rpcproto.FeatureSequence != 0 {
+				c.R.EnableSequence()
+				c.W.EnableSequence()
+			}
+		}
+	}
+	return diffs, nil
+}
+
+func readIgnoringWindow(ctx context.Context, c *rpcproto.Conn) (*rpcproto.Frame, error) {
+	ctx, cancel := context.WithTimeout(ctx, replayTimeout)
+	defer cancel()
+	for {
+		f, err := c.R.ReadFrameContext(ctx)
+		if err != nil || f.Type != rpcproto.FrameWindowUpdate {
+			return f, err
+		}
+	}
+}
+
+// compareFrame describes how got differs from the recorded frame, or returns
+// "" if it matches.
+func compareFrame(want GoldenFrame, got *rpcproto.Frame) string {
+	if got.Type != want.Type {
+		return fmt.Sprintf("want %s %s, got %s %s",
+			want.Name, summarizeFrame(want.Method, want.Type, want.Payload),
+			rpcproto.FrameTypeName(got.Type), summarizeFrame(want.Method, got.Type, got.Payload))
+	}
+	if bytes.Equal(got.Payload, want.Payload) || got.Type == rpcproto.FrameStats {
+		return ""
+	}
+	if got.Type == rpcproto.FrameResponse || got.Type == rpcproto.FrameStreamMsg {
+		if m := messageType(want.Method, false); m != nil {
+			a, b := m, proto.Clone(m)
+			if proto.Unmarshal(want.Payload, a) == nil && proto.Unmarshal(got.Payload, b) == nil && proto.Equal(a, b) {
+				return ""
+			}
+		}
+	}
+	return fmt.Sprintf("%s payload differs: want %s, got %s", want.Name,
+		summarizeFrame(want.Method, want.Type, want.Payload), summarizeFrame(want.Method, got.Type, got.Payload))
+}
+
+func summarizeFrame(method string, t byte, payload []byte) string {
+	switch t {
+	case rpcproto.FrameResponse, rpcproto.FrameStreamMsg:
+		if s := summarize(method, false, payload); s != "" {
+			return s
+		}
+	case rpcproto.FrameError:
+		return truncate(fmt.Sprintf("%q", payload))
+	}
+	return truncate(fmt.Sprintf("%x", payload))
+}
@@ -0,0 +1,182 @@
+// Package rpctrace writes a JSON-lines log of every frame on a pipe RPC
+// connection, one object per frame, so that transcripts from different
+// runtimes can be diffed or filtered with standard tools.
+package rpctrace
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"compat/pb"
+	"compat/rpcproto"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// maxSummary bounds the decoded message text in each record.
+const maxSummary = 256
+
+// Record is one traced frame.
+type Record struct {
+	Time time.Time `json:"ts"`
+	Side string    `json:"side"`
+	Dir  string    `json:"dir"` // "in" or "out"
+	Type string    `json:"type"`
+	// Size is the payload length; the frame on the wire is 5 bytes longer,
+	// or 9 with a sequence number.
+	Size int `json:"size"`
+	// Call numbers the CALLs on the connection from 1. Frames that belong to
+	// no call (HELLO, STATS, SHUTDOWN) leave it and Method unset.
+	Call   uint64 `json:"call,omitempty"`
+	Method string `json:"method,omitempty"`
+	// Seq is the frame's sequence number, if the connection numbers frames.
+	Seq     uint32 `json:"seq,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// Tracer logs the frames of one connection. It is safe for concurrent use.
+type Tracer struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	side   string
+	calls  uint64
+	method string
+	err    error
+}
+
+// New returns a Tracer writing records to w. side ("client" or "server")
+// names the end of the connection being traced; it decides which message
+// type a STREAM_MSG is decoded as.
+func New(w io.Writer, side string) *Tracer {
+	return &Tracer{enc: json.NewEncoder(w), side: side}
+}
+
+// Attach traces every frame c reads and writes.
+func (t *Tracer) Attach(c *rpcproto.Conn) {
+	c.R.AddObserver(func(f *rpcproto.Frame) { t.trace(f, "in") })
+	c.W.AddObserver(func(f *rpcproto.Frame) { t.trace(f, "out") })
+}
+
+// Err returns the first error writing a record. Tracing stops after it.
+func (t *Tracer) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *Tracer) trace(f *rpcproto.Frame, dir string) {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		return
+	}
+	rec := Record{Time: now, Side: t.side, Dir: dir, Type: rpcproto.FrameTypeName(f.Type), Size: len(f.Payload), Seq: f.Seq}
+	fromClient := (t.side == "client") == (dir == "out")
+
+	switch f.Type {
+	case rpcproto.FrameCall:
+		method, reqBytes, err := rpcproto.ParseCallPayload(f.Payload)
+		if err != nil {
+			rec.Summary = err.Error()
+			break
+		}
+		t.calls++
+		t.method = method
+		rec.Summary = summarize(method, true, reqBytes)
+	case rpcproto.FrameStreamMsg:
+		rec.Summary = summarize(t.method, fromClient, f.Payload)
+	case rpcproto.FrameResponse:
+		rec.Summary = summarize(t.method, false, f.Payload)
+	case rpcproto.FrameError:
+		rec.Summary = truncate(string(f.Payload))
+	case rpcproto.FrameWindowUpdate:
+		if len(f.Payload) == 4 {
+			rec.Summary = fmt.Sprintf("increment %d", binary.BigEndian.Uint32(f.Payload))
+		}
+	case rpcproto.FrameHello:
+		if s, err := rpcproto.ParseSettings(f.Payload); err == nil {
+			rec.Summary = fmt.Sprintf("window %d, max frame %d", s.InitialWindow, s.MaxFrameSize)
+			if s.Codec != rpcproto.CodecBinary {
+				rec.Summary += fmt.Sprintf(", codec %d", s.Codec)
+			}
+			if s.Features != 0 {
+				rec.Summary += fmt.Sprintf(", features %#x", s.Features)
+			}
+		}
+	case rpcproto.FrameStats:
+		if len(f.Payload) > 0 {
+			rec.Summary = decode(&pb.StatsReport{}, f.Payload)
+		}
+	}
+	switch f.Type {
+	case rpcproto.FrameHello, rpcproto.FrameStats, rpcproto.FrameShutdown:
+	default:
+		if t.calls > 0 {
+			rec.Call, rec.Method = t.calls, t.method
+		}
+	}
+	t.err = t.enc.Encode(&rec)
+}
+
+// summarize decodes a message of method's request or response type.
+func summarize(method string, request bool, b []byte) string {
+	m := messageType(method, request)
+	if m == nil {
+		return ""
+	}
+	return decode(m, b)
+}
+
+// messageType returns an empty message of method's request or response
+// type, or nil if the method is unknown.
+func messageType(method string, request bool) proto.Message {
+	md, err := rpcproto.ResolveMethod(method)
+	if err != nil {
+		return nil
+	}
+	desc := md.Output()
+	if request {
+		desc = md.Input()
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	if err != nil {
+		return nil
+	}
+	return mt.New().Interface()
+}
+
+func decode(m proto.Message, b []byte) string {
+	m = m.ProtoReflect().Type().New().Interface()
+	if err := proto.Unmarshal(b, m); err != nil {
+		return "undecodable: " + err.Error()
+	}
+	return truncate(string(m.ProtoReflect().Descriptor().Name()) + "{" + prototext.MarshalOptions{}.Format(m) + "}")
+}
+
+func truncate(s string) string {
+	if len(s) <= maxSummary {
+		return s
+	}
+	return s[:maxSummary] + "..."
+}
+
+// Open returns the trace destination for a -trace flag value: "-" is
+// standard error, anything else a file created or truncated at that path.
+func Open(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopCloser{os.Stderr}, nil
+	}
+	return os.Create(path)
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
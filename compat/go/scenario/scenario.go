@@ -0,0 +1,288 @@
+// Package scenario describes multi-message protocol exchanges as data and
+// plays them over one bidirectional pipe RPC call: send X, expect Y matching
+// some predicates, branch on what came back. Protocols built from ordered
+// message kinds, like ACP, are written as a list of Steps instead of a
+// hand-rolled loop of RecvMsg calls and field checks per flow.
+package scenario
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"compat/rpcproto"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Scenario is an ordered exchange of messages on one call.
+type Scenario struct {
+	Name string
+	// Method is the path of a bidirectional streaming method.
+	Method string
+	// Recv is a message of the type the peer sends; Expect steps decode
+	// into new messages of its type.
+	Recv proto.Message
+	// Steps run in order unless a Branch says otherwise.
+	Steps []Step
+	// MaxSteps bounds how many steps run in total, so a Branch loop that
+	// never exits fails instead of hanging. Zero means 10000.
+	MaxSteps int
+}
+
+// Step is one action of a Scenario. A step may both send and expect; the
+// send happens first. Exchanges are strictly alternating: the runner never
+// reads while it sends.
+type Step struct {
+	// Label names the step as a target for Branch.
+	Label string
+	// Send, if set, is written to the peer as a STREAM_MSG.
+	Send proto.Message
+	// Expect, if set, reads the peer's next message and checks each
+	// predicate in order. An empty, non-nil slice accepts any message.
+	Expect []Predicate
+	// Branch, if set, is called with the message Expect read and returns
+	// the label of the step to run next; "" continues with the next step.
+	Branch func(got proto.Message) string
+	// Close ends this side of the stream (STREAM_END) after Send.
+	Close bool
+	// ExpectEnd requires the peer to end its side of the stream next.
+	ExpectEnd bool
+}
+
+// Predicate is a named check on a received message.
+type Predicate struct {
+	Desc  string
+	Check func(got proto.Message) bool
+}
+
+// Func returns a predicate checked by fn.
+func Func(desc string, fn func(got proto.Message) bool) Predicate {
+	return Predicate{Desc: desc, Check: fn}
+}
+
+// Equal checks that the field at path, a dot-separated list of field names
+// through singular message fields, is set to want. Enum fields compare by
+// number, so want may be the generated enum constant.
+func Equal(path string, want any) Predicate {
+	want = normalize(want)
+	return Predicate{
+		Desc: fmt.Sprintf("%s == %v", path, want),
+		Check: func(got proto.Message) bool {
+			v, ok := Get(got, path)
+			return ok && reflect.DeepEqual(v, want)
+		},
+	}
+}
+
+// OneOf checks that the field at path is set to one of want.
+func OneOf(path string, want ...any) Predicate {
+	for i := range want {
+		want[i] = normalize(want[i])
+	}
+	return Predicate{
+		Desc: fmt.Sprintf("%s in %v", path, want),
+		Check: func(got proto.Message) bool {
+			v, ok := Get(got, path)
+			if !ok {
+				return false
+			}
+			for _, w := range want {
+				if reflect.DeepEqual(v, w) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// Has checks that the field at path is populated.
+func Has(path string) Predicate {
+	return Predicate{
+		Desc: "has " + path,
+		Check: func(got proto.Message) bool {
+			_, ok := Get(got, path)
+			return ok
+		},
+	}
+}
+
+// Switch returns a Branch that jumps to cases[v] when the field at path
+// holds v, and continues with the next step for any other value or when the
+// field is unset.
+func Switch(path string, cases map[any]string) func(proto.Message) string {
+	labels := make(map[any]string, len(cases))
+	for v, label := range cases {
+		labels[normalize(v)] = label
+	}
+	return func(got proto.Message) string {
+		v, ok := Get(got, path)
+		if !ok {
+			return ""
+		}
+		return labels[v]
+	}
+}
+
+// Get returns the value of the populated field at path in msg as a Go value:
+// scalars as their Go type, enums as protoreflect.EnumNumber and messages as
+// protoreflect.Message. It reports false if any field on the path is unset or
+// does not exist.
+func Get(msg proto.Message, path string) (any, bool) {
+	m := msg.ProtoReflect()
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		fd := m.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil || !m.Has(fd) {
+			return nil, false
+		}
+		v := m.Get(fd)
+		if i == len(names)-1 {
+			return v.Interface(), true
+		}
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return nil, false
+		}
+		m = v.Message()
+	}
+	return nil, false
+}
+
+func normalize(v any) any {
+	if e, ok := v.(protoreflect.Enum); ok {
+		return e.Number()
+	}
+	return v
+}
+
+// Run plays sc over s as the client. It starts the call, runs the steps,
+// ends this side of the stream if no step did, and then requires the peer to
+// end the call without sending anything more. The first step that fails
+// stops the run and is returned as an error naming it.
+func Run(ctx context.Context, s *rpcproto.StreamConn, sc *Scenario) error {
+	if err := s.Call(ctx, sc.Method, nil, true); err != nil {
+		return fmt.Errorf("%s: call %s: %w", sc.Name, sc.Method, err)
+	}
+	labels := map[string]int{}
+	for i, step := range sc.Steps {
+		if step.Label != "" {
+			labels[step.Label] = i
+		}
+	}
+	maxSteps := sc.MaxSteps
+	if maxSteps == 0 {
+		maxSteps = 10000
+	}
+
+	closed, ended := false, false
+	for pc, n := 0, 0; pc < len(sc.Steps); n++ {
+		if n == maxSteps {
+			return fmt.Errorf("%s: ran %d steps without finishing", sc.Name, n)
+		}
+		step := sc.Steps[pc]
+		fail := func(format string, args ...any) error {
+			name := fmt.Sprintf("step %d", pc)
+			if step.Label != "" {
+				name += " (" + step.Label + ")"
+			}
+			return fmt.Errorf("%s: %s: %s", sc.Name, name, fmt.Sprintf(format, args...))
+		}
+
+		if step.Send != nil {
+			b, err := s.Codec().Marshal(step.Send)
+			if err != nil {
+				return fail("marshal: %v", err)
+			}
+			if err := s.Send(ctx, b); err != nil {
+				return fail("send: %v", err)
+			}
+		}
+		if step.Close {
+			if err := s.CloseSend(ctx); err != nil {
+				return fail("close: %v", err)
+			}
+			closed = true
+		}
+
+		next := pc + 1
+		if step.Expect != nil {
+			got, err := recv(ctx, s, sc.Recv)
+			if err == io.EOF {
+				return fail("peer ended the stream, expected a message")
+			}
+			if err != nil {
+				return fail("%v", err)
+			}
+			for _, p := range step.Expect {
+				if !p.Check(got) {
+					return fail("expected %s, got {%s}", p.Desc, format(got))
+				}
+			}
+			if step.Branch != nil {
+				if label := step.Branch(got); label != "" {
+					target, ok := labels[label]
+					if !ok {
+						return fail("branch to unknown label %q", label)
+					}
+					next = target
+				}
+			}
+		}
+		if step.ExpectEnd {
+			if err := expectEnd(ctx, s, sc.Recv); err != nil {
+				return fail("%v", err)
+			}
+			ended = true
+		}
+		pc = next
+	}
+
+	if !closed {
+		if err := s.CloseSend(ctx); err != nil {
+			return fmt.Errorf("%s: close: %w", sc.Name, err)
+		}
+	}
+	if !ended {
+		if err := expectEnd(ctx, s, sc.Recv); err != nil {
+			return fmt.Errorf("%s: %w", sc.Name, err)
+		}
+	}
+	return nil
+}
+
+// recv reads the next message of the call into a new message of typ's type.
+func recv(ctx context.Context, s *rpcproto.StreamConn, typ proto.Message) (proto.Message, error) {
+	payload, err := s.RecvMsg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	msg := typ.ProtoReflect().New().Interface()
+	if err := s.Codec().Unmarshal(payload, msg); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+	if err := s.Consume(len(payload)); err != nil {
+		return nil, fmt.Errorf("window update: %w", err)
+	}
+	return msg, nil
+}
+
+func format(msg proto.Message) string {
+	return prototext.MarshalOptions{}.Format(msg)
+}
+
+func expectEnd(ctx context.Context, s *rpcproto.StreamConn, typ proto.Message) error {
+	msg, err := recv(ctx, s, typ)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("expected end of stream: %w", err)
+	}
+	return fmt.Errorf("expected end of stream, got {%s}", format(msg))
+}
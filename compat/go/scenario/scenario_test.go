@@ -0,0 +1,87 @@
+package scenario_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"compat/pb"
+	"compat/rpcproto"
+	"compat/rpcserverlib"
+	"compat/scenario"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// run plays sc against the reference server's Bidirectional echo method on
+// a fresh connection.
+func run(t *testing.T, sc *scenario.Scenario) error {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	clientEnd, serverEnd := rpcproto.Pipe()
+	defer clientEnd.Close()
+	go func() {
+		rpcserverlib.Serve(ctx, serverEnd, rpcserverlib.DefaultRegistry())
+		serverEnd.Close()
+	}()
+	sc.Method, sc.Recv = "/StreamingService/Bidirectional", &pb.ChatMessage{}
+	return scenario.Run(ctx, rpcproto.NewStreamConn(rpcproto.NewConn(clientEnd, clientEnd)), sc)
+}
+
+func TestRun(t *testing.T) {
+	err := run(t, &scenario.Scenario{
+		Name: "echo",
+		Steps: []scenario.Step{
+			{
+				Send:   &pb.ChatMessage{Sender: "a", Text: "hi"},
+				Expect: []scenario.Predicate{scenario.Equal("text", "hi"), scenario.Has("sender")},
+				Branch: scenario.Switch("text", map[any]string{"hi": "second"}),
+			},
+			{
+				Send:   &pb.ChatMessage{Text: "skipped"},
+				Expect: []scenario.Predicate{},
+			},
+			{
+				Label:  "second",
+				Send:   &pb.ChatMessage{Text: "bye"},
+				Expect: []scenario.Predicate{scenario.OneOf("text", "hello", "bye")},
+				Close:  true,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	err := run(t, &scenario.Scenario{
+		Name: "mismatch",
+		Steps: []scenario.Step{{
+			Label:  "greet",
+			Send:   &pb.ChatMessage{Text: "hi"},
+			Expect: []scenario.Predicate{scenario.Equal("text", "bye")},
+		}},
+	})
+	if err == nil || !strings.Contains(err.Error(), `mismatch: step 0 (greet): expected text == bye`) {
+		t.Fatalf("got %v, want a failure naming the step and predicate", err)
+	}
+}
+
+func TestRunMaxSteps(t *testing.T) {
+	err := run(t, &scenario.Scenario{
+		Name: "loop",
+		Steps: []scenario.Step{{
+			Label:  "again",
+			Send:   &pb.ChatMessage{Text: "hi"},
+			Expect: []scenario.Predicate{},
+			Branch: func(got proto.Message) string { return "again" },
+		}},
+		MaxSteps: 5,
+	})
+	if err == nil || !strings.Contains(err.Error(), "ran 5 steps") {
+		t.Fatalf("got %v, want the step limit", err)
+	}
+}
@@ -0,0 +1,137 @@
+// Package testca issues a throwaway certificate authority and server and
+// client certificates signed by it, so the RPC socket transports can be run
+// under TLS and mutual TLS without any outside PKI.
+package testca
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Validity is how long issued certificates last.
+const Validity = 30 * 24 * time.Hour
+
+// Bundle holds PEM-encoded certificates and keys.
+type Bundle struct {
+	CACert                []byte
+	ServerCert, ServerKey []byte
+	ClientCert, ClientKey []byte
+}
+
+// Files are the names WriteDir gives each part of a Bundle.
+var Files = struct {
+	CACert, ServerCert, ServerKey, ClientCert, ClientKey string
+}{"ca.pem", "server.pem", "server-key.pem", "client.pem", "client-key.pem"}
+
+// New issues a CA, a server certificate valid for hosts (names or IP
+// addresses) and a client certificate.
+func New(hosts ...string) (*Bundle, error) {
+	now := time.Now()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	caTmpl := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "compat test CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(Validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := sign(caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{CACert: encode("CERTIFICATE", caDER)}
+	serverTmpl := leaf("compat test server", x509.ExtKeyUsageServerAuth, now)
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			serverTmpl.IPAddresses = append(serverTmpl.IPAddresses, ip)
+		} else {
+			serverTmpl.DNSNames = append(serverTmpl.DNSNames, h)
+		}
+	}
+	if b.ServerCert, b.ServerKey, err = issue(serverTmpl, ca, caKey); err != nil {
+		return nil, err
+	}
+	clientTmpl := leaf("compat test client", x509.ExtKeyUsageClientAuth, now)
+	if b.ClientCert, b.ClientKey, err = issue(clientTmpl, ca, caKey); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// WriteDir writes the bundle to dir under the names in Files.
+func (b *Bundle) WriteDir(dir string) error {
+	for _, f := range []struct {
+		name string
+		data []byte
+		perm os.FileMode
+	}{
+		{Files.CACert, b.CACert, 0o644},
+		{Files.ServerCert, b.ServerCert, 0o644},
+		{Files.ServerKey, b.ServerKey, 0o600},
+		{Files.ClientCert, b.ClientCert, 0o644},
+		{Files.ClientKey, b.ClientKey, 0o600},
+	} {
+		if err := os.WriteFile(filepath.Join(dir, f.name), f.data, f.perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func leaf(name string, usage x509.ExtKeyUsage, now time.Time) *x509.Certificate {
+	return &x509.Certificate{
+		Subject:     pkix.Name{CommonName: name},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(Validity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{usage},
+	}
+}
+
+// issue signs a new key for tmpl with the CA and returns both as PEM.
+func issue(tmpl, ca *x509.Certificate, caKey *ecdsa.PrivateKey) (cert, key []byte, err error) {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := sign(tmpl, ca, &k.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(k)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encode("CERTIFICATE", der), encode("PRIVATE KEY", keyDER), nil
+}
+
+func sign(tmpl, parent *x509.Certificate, pub *ecdsa.PublicKey, priv *ecdsa.PrivateKey) ([]byte, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	tmpl.SerialNumber = serial
+	return x509.CreateCertificate(rand.Reader, tmpl, parent, pub, priv)
+}
+
+func encode(typ string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+}
@@ -1,6 +1,21 @@
 package testcases
 
-import "compat/pb"
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"compat/acphash"
+	"compat/pb"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("acp", GenerateAcp, validateAcp)
+}
 
 func GenerateAcp() []TestCase {
 	return []TestCase{
@@ -104,7 +119,218 @@ func GenerateAcp() []TestCase {
 				Uris: []string{"asset://textures/old.png"},
 			},
 		},
+		acpPayloadCase("payload_uncompressed", pb.AcpCompression_UNCOMPRESSED),
+		acpPayloadCase("payload_gzip", pb.AcpCompression_GZIP),
+		acpPayloadCase("payload_zstd", pb.AcpCompression_ZSTD),
+	}
+}
+
+// acpAssetLen is the size of the asset carried by the payload cases. It stays
+// well under the Zig writer's 8 KiB message buffer even uncompressed.
+const acpAssetLen = 4096
+
+// AcpAsset returns the asset the payload cases transfer. It is simple enough
+// to rebuild byte for byte on the Zig side: byte i is
+// 'a' + (i + i/64) % 26.
+func AcpAsset() []byte {
+	b := make([]byte, acpAssetLen)
+	for i := range b {
+		b[i] = byte('a' + (i+i/64)%26)
+	}
+	return b
+}
+
+func acpPayloadCase(name string, c pb.AcpCompression) TestCase {
+	asset := AcpAsset()
+	chunk, err := CompressAcpPayload(c, asset)
+	if err != nil {
+		panic(fmt.Sprintf("testcases: %s: %v", name, err))
+	}
+	return TestCase{
+		Name: name,
+		Msg: &pb.AcpMessage{
+			Kind:       pb.AcpMessageKind_UPDATED,
+			RequestId:  300 + uint64(c),
+			Uri:        proto_string("asset://meshes/rock.bin"),
+			ChunkIndex: 0,
+			ChunkTotal: 1,
+			Metadata: &pb.AcpAssetMetadata{
+				Uri:         "asset://meshes/rock.bin",
+				CachePath:   "/var/cache/acp/rock",
+				PayloadHash: acphash.Sum(asset),
+				FileLength:  int64(len(asset)),
+				UriVersion:  1,
+				Compression: c,
+			},
+			PayloadChunk: chunk,
+		},
+		// Zig's std can decompress gzip and zstd but not produce them.
+		GoOnly: c != pb.AcpCompression_UNCOMPRESSED,
+	}
+}
+
+// CompressAcpPayload encodes data as a payload_chunk with compression c. The
+// output depends only on its inputs, so corpora stay reproducible.
+func CompressAcpPayload(c pb.AcpCompression, data []byte) ([]byte, error) {
+	switch c {
+	case pb.AcpCompression_UNCOMPRESSED:
+		return bytes.Clone(data), nil
+	case pb.AcpCompression_GZIP:
+		var buf bytes.Buffer
+		zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case pb.AcpCompression_ZSTD:
+		zw, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer zw.Close()
+		return zw.EncodeAll(data, nil), nil
+	}
+	return nil, fmt.Errorf("unknown compression %v", c)
+}
+
+// DecompressAcpPayload reverses CompressAcpPayload.
+func DecompressAcpPayload(c pb.AcpCompression, chunk []byte) ([]byte, error) {
+	switch c {
+	case pb.AcpCompression_UNCOMPRESSED:
+		return chunk, nil
+	case pb.AcpCompression_GZIP:
+		zr, err := gzip.NewReader(bytes.NewReader(chunk))
+		if err != nil {
+			return nil, fmt.Errorf("gzip payload: %w", err)
+		}
+		data, err := io.ReadAll(zr)
+		if err != nil {
+			return nil, fmt.Errorf("gzip payload: %w", err)
+		}
+		return data, nil
+	case pb.AcpCompression_ZSTD:
+		zr, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		data, err := zr.DecodeAll(chunk, nil)
+		if err != nil {
+			return nil, fmt.Errorf("zstd payload: %w", err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("unknown compression %v", c)
+}
+
+// VerifyAcpPayload decompresses the payload_chunk of a single-chunk transfer
+// as its metadata describes and checks the result against payload_hash and
+// file_length. It returns the decompressed asset.
+func VerifyAcpPayload(msg *pb.AcpMessage) ([]byte, error) {
+	meta := msg.GetMetadata()
+	if meta == nil {
+		return nil, fmt.Errorf("payload without metadata")
+	}
+	data, err := DecompressAcpPayload(meta.Compression, msg.PayloadChunk)
+	if err != nil {
+		return nil, err
 	}
+	if int64(len(data)) != meta.FileLength {
+		return nil, fmt.Errorf("decompressed %d bytes, file_length %d", len(data), meta.FileLength)
+	}
+	if err := acphash.Verify(data, meta.PayloadHash); err != nil {
+		return nil, fmt.Errorf("decompressed payload: %w", err)
+	}
+	return data, nil
 }
 
 func acpStatus(s pb.AcpStatusCode) *pb.AcpStatusCode { return &s }
+
+func validateAcp(tc RawTestCase) int {
+	msg := &pb.AcpMessage{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	// Every vector that carries a whole asset must hash to its metadata,
+	// whatever its name, so a hand-edited hash or payload is caught as soon
+	// as the corpus is validated.
+	if msg.Metadata != nil && msg.ChunkTotal == 1 && len(msg.PayloadChunk) > 0 {
+		_, err := VerifyAcpPayload(msg)
+		if err != nil {
+			fmt.Printf("  FAIL %s: %v\n", tc.Name, err)
+		}
+		failures += check(tc.Name, "metadata.payload_hash", err == nil)
+	}
+	switch tc.Name {
+	case "empty":
+		failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_HELLO)
+		failures += check(tc.Name, "request_id", msg.RequestId == 0)
+	case "hello":
+		failures += check(tc.Name, "version", msg.Version != nil && *msg.Version == 1)
+		failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_HELLO)
+	case "request_with_uri":
+		failures += check(tc.Name, "version", msg.Version != nil && *msg.Version == 1)
+		failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_REQUEST)
+		failures += check(tc.Name, "request_id", msg.RequestId == 42)
+		failures += check(tc.Name, "uri", msg.Uri != nil && *msg.Uri == "asset://textures/wood.png")
+	case "discover_with_uris":
+		failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_DISCOVER)
+		failures += check(tc.Name, "request_id", msg.RequestId == 100)
+		failures += check(tc.Name, "uris.len", len(msg.Uris) == 3)
+		if len(msg.Uris) == 3 {
+			failures += check(tc.Name, "uris[0]", msg.Uris[0] == "asset://models/tree.glb")
+			failures += check(tc.Name, "uris[1]", msg.Uris[1] == "asset://textures/bark.png")
+			failures += check(tc.Name, "uris[2]", msg.Uris[2] == "asset://shaders/pbr.wgsl")
+		}
+	case "status_ok_with_metadata":
+		failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_STATUS)
+		failures += check(tc.Name, "status", msg.Status != nil && *msg.Status == pb.AcpStatusCode_OK)
+		failures += check(tc.Name, "metadata", msg.Metadata != nil)
+		if msg.Metadata != nil {
+			failures += check(tc.Name, "metadata.uri", msg.Metadata.Uri == "asset://textures/wood.png")
+			failures += check(tc.Name, "metadata.cache_path", msg.Metadata.CachePath == "/tmp/cache/abc123")
+			failures += check(tc.Name, "metadata.payload_hash", msg.Metadata.PayloadHash == "sha256:deadbeef")
+			failures += check(tc.Name, "metadata.file_length", msg.Metadata.FileLength == 1048576)
+			failures += check(tc.Name, "metadata.uri_version", msg.Metadata.UriVersion == 3)
+			failures += check(tc.Name, "metadata.updated_at_ns", msg.Metadata.UpdatedAtNs == 1700000000000000000)
+		}
+	case "status_not_found":
+		failures += check(tc.Name, "status", msg.Status != nil && *msg.Status == pb.AcpStatusCode_NOT_FOUND)
+		failures += check(tc.Name, "detail", msg.Detail != nil && *msg.Detail == "asset not found in registry")
+	case "updated_with_chunks":
+		failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_UPDATED)
+		failures += check(tc.Name, "chunk_index", msg.ChunkIndex == 3)
+		failures += check(tc.Name, "chunk_total", msg.ChunkTotal == 10)
+		failures += check(tc.Name, "metadata", msg.Metadata != nil)
+	case "force_recook":
+		failures += check(tc.Name, "version", msg.Version != nil && *msg.Version == 2)
+		failures += check(tc.Name, "force_recook", msg.ForceRecook != nil && *msg.ForceRecook == true)
+		failures += check(tc.Name, "uri", msg.Uri != nil && *msg.Uri == "asset://textures/grass.png")
+	case "all_status_codes":
+		failures += check(tc.Name, "status", msg.Status != nil && *msg.Status == pb.AcpStatusCode_INTERNAL_ERROR)
+		failures += check(tc.Name, "detail", msg.Detail != nil && *msg.Detail == "unexpected codec failure")
+	case "deload":
+		failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_DELOAD)
+		failures += check(tc.Name, "uris.len", len(msg.Uris) == 1)
+	case "payload_uncompressed", "payload_gzip", "payload_zstd":
+		want := map[string]pb.AcpCompression{
+			"payload_uncompressed": pb.AcpCompression_UNCOMPRESSED,
+			"payload_gzip":         pb.AcpCompression_GZIP,
+			"payload_zstd":         pb.AcpCompression_ZSTD,
+		}[tc.Name]
+		failures += check(tc.Name, "kind", msg.Kind == pb.AcpMessageKind_UPDATED)
+		failures += check(tc.Name, "chunk_total", msg.ChunkTotal == 1)
+		failures += check(tc.Name, "metadata.compression", msg.GetMetadata().GetCompression() == want)
+		failures += check(tc.Name, "payload_chunk", len(msg.PayloadChunk) > 0)
+	}
+	return failures
+}
@@ -0,0 +1,38 @@
+package testcases
+
+import (
+	"testing"
+
+	"compat/pb"
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// TestAcpHashes checks that the generated ACP vectors pass validation and
+// that a vector whose payload no longer matches its payload_hash does not.
+func TestAcpHashes(t *testing.T) {
+	for _, tc := range GenerateAcp() {
+		data, err := tc.Data()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := validateAcp(RawTestCase{Name: tc.Name, Data: data}); n != 0 {
+			t.Errorf("%s: %d failure(s)", tc.Name, n)
+		}
+
+		msg := tc.Msg.(*pb.AcpMessage)
+		if len(msg.PayloadChunk) == 0 {
+			continue
+		}
+		bad := proto.Clone(msg).(*pb.AcpMessage)
+		bad.Metadata.PayloadHash = "sha256:" + bad.Metadata.PayloadHash[len("sha256:")+1:] + "0"
+		data, err = pbutil.Marshal(bad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := validateAcp(RawTestCase{Name: tc.Name, Data: data}); n == 0 {
+			t.Errorf("%s: wrong payload_hash passed validation", tc.Name)
+		}
+	}
+}
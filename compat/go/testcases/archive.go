@@ -0,0 +1,96 @@
+package testcases
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Archived corpora are pinned copies of a generated testdata directory, kept
+// in its v<N> subdirectories so that today's decoders can be checked against
+// encodings that were valid when they were written. An archive holds the
+// corpus files in its manifest, their JSON, reject and over-limit corpora,
+// the descriptor sets and the manifest itself; mutants and delimited streams
+// are derived and are not kept. Archives are never rewritten.
+
+// ArchiveDir returns the directory of archive version v under root.
+func ArchiveDir(root string, v int) string {
+	return filepath.Join(root, "v"+strconv.Itoa(v))
+}
+
+// Archives returns the archive versions present under root in ascending
+// order. A missing root has none.
+func Archives(root string) ([]int, error) {
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var versions []int
+	for _, e := range entries {
+		n, ok := strings.CutPrefix(e.Name(), "v")
+		if !ok || !e.IsDir() {
+			continue
+		}
+		if v, err := strconv.Atoi(n); err == nil && v > 0 && e.Name() == "v"+strconv.Itoa(v) {
+			versions = append(versions, v)
+		}
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+// WriteArchive copies the corpora m describes from root into archive version
+// v, which must not exist yet.
+func WriteArchive(root string, v int, m *Manifest) error {
+	if v <= 0 {
+		return fmt.Errorf("archive version %d: must be positive", v)
+	}
+	dst := ArchiveDir(root, v)
+	if err := os.Mkdir(dst, 0o755); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("archive v%d already exists; archived corpora are never rewritten", v)
+		}
+		return err
+	}
+	// Manifest files must exist; the rest are copied if they do.
+	type file struct {
+		path     string
+		required bool
+	}
+	var files []file
+	for _, ds := range DescriptorSets {
+		files = append(files, file{path: ds.Name})
+	}
+	for _, mf := range m.Files {
+		files = append(files,
+			file{path: mf.Path, required: true},
+			file{path: filepath.Join(JSONDir, mf.Name+".bin")},
+			file{path: filepath.Join(RejectDir, mf.Name+".bin")},
+			file{path: filepath.Join(LimitsDir, mf.Name+".bin")})
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(root, f.path))
+		if errors.Is(err, fs.ErrNotExist) && !f.required {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dst, f.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return WriteManifest(dst, m)
+}
@@ -0,0 +1,88 @@
+package testcases_test
+
+import (
+	"fmt"
+	"testing"
+
+	"compat/pb"
+	"compat/pbutil"
+	"compat/testcases"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type benchCase struct {
+	name string
+	msg  proto.Message
+}
+
+// largeCases is the large tier: messages far bigger than any generated case,
+// where per-element and per-byte costs dominate.
+func largeCases() []benchCase {
+	rep := &pb.RepeatedMessage{}
+	for i := 0; i < 10000; i++ {
+		rep.Ints = append(rep.Ints, int32(i*7919-1<<24))
+		rep.Doubles = append(rep.Doubles, float64(i)/3)
+		rep.Strings = append(rep.Strings, fmt.Sprintf("item-%d", i))
+		rep.Items = append(rep.Items, &pb.RepItem{Id: int32(i), Name: fmt.Sprintf("name-%d", i)})
+	}
+	maps := &pb.MapMessage{StrStr: map[string]string{}, IntStr: map[int32]string{}, StrMsg: map[string]*pb.MapSubMsg{}}
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		maps.StrStr[key] = key + "-value"
+		maps.IntStr[int32(i)] = key
+		maps.StrMsg[key] = &pb.MapSubMsg{Id: int32(i), Text: key}
+	}
+	payload := &pb.AcpMessage{PayloadChunk: make([]byte, 1<<20)}
+	for i := range payload.PayloadChunk {
+		payload.PayloadChunk[i] = byte(i * 31)
+	}
+	return []benchCase{
+		{"large/repeated_10k", rep},
+		{"large/map_2k", maps},
+		{"large/payload_1MiB", payload},
+	}
+}
+
+// benchCases returns every registered case followed by the large tier.
+func benchCases() []benchCase {
+	var cases []benchCase
+	for _, c := range testcases.Categories() {
+		for _, tc := range c.Generate() {
+			cases = append(cases, benchCase{c.Name + "/" + tc.Name, tc.Msg})
+		}
+	}
+	return append(cases, largeCases()...)
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	for _, bc := range benchCases() {
+		b.Run(bc.name, func(b *testing.B) {
+			b.SetBytes(int64(proto.Size(bc.msg)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := pbutil.Marshal(bc.msg); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	for _, bc := range benchCases() {
+		b.Run(bc.name, func(b *testing.B) {
+			data, err := pbutil.Marshal(bc.msg)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := unmarshal.Unmarshal(data, bc.msg.ProtoReflect().New().Interface()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package testcases
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// CaseJSON is one corpus case in the reviewable form cmd/corpusconv writes:
+// the decoded message as proto3 JSON, under the full name of its type. A
+// case whose bytes the message does not re-encode to exactly (unknown
+// fields, non-canonical varints, fields out of order, bytes that are not a
+// message at all) keeps them in Hex, and Message, if present, only shows
+// what they decode to.
+type CaseJSON struct {
+	Name    string          `json:"name"`
+	Type    string          `json:"type,omitempty"`
+	Message json.RawMessage `json:"message,omitempty"`
+	Hex     string          `json:"hex,omitempty"`
+	Note    string          `json:"note,omitempty"`
+}
+
+// MarshalCaseJSON converts tc to its CaseJSON form, decoding it as mt, and
+// returns it indented. A nil mt keeps the bytes as hex only.
+func MarshalCaseJSON(tc RawTestCase, mt protoreflect.MessageType) ([]byte, error) {
+	cj := CaseJSON{Name: tc.Name}
+	if mt == nil {
+		cj.Hex = hex.EncodeToString(tc.Data)
+		cj.Note = "no message type known for this case"
+		return marshalIndent(cj)
+	}
+	cj.Type = string(mt.Descriptor().FullName())
+	msg := mt.New().Interface()
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		cj.Hex = hex.EncodeToString(tc.Data)
+		cj.Note = fmt.Sprintf("does not decode: %v", err)
+		return marshalIndent(cj)
+	}
+	js, err := protojson.Marshal(msg)
+	if err != nil {
+		cj.Hex = hex.EncodeToString(tc.Data)
+		cj.Note = fmt.Sprintf("has no JSON form: %v", err)
+		return marshalIndent(cj)
+	}
+	cj.Message = js
+
+	// Only a message that survives JSON and re-encodes to the same bytes
+	// can stand for the case on its own.
+	back := mt.New().Interface()
+	if err := protojson.Unmarshal(js, back); err != nil {
+		return nil, fmt.Errorf("%s: %w", tc.Name, err)
+	}
+	data, err := pbutil.Marshal(back)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", tc.Name, err)
+	}
+	if !bytes.Equal(data, tc.Data) {
+		cj.Hex = hex.EncodeToString(tc.Data)
+		cj.Note = "message does not re-encode to these bytes; hex is the case, message is for reading"
+	}
+	return marshalIndent(cj)
+}
+
+// UnmarshalCaseJSON converts the CaseJSON form back to a case, resolving its
+// type among the registered Go types. Hex, if present, is the case; a
+// message beside it must still match what the hex decodes to, so that an
+// edit to a message that is only for reading is not silently dropped.
+func UnmarshalCaseJSON(data []byte) (RawTestCase, error) {
+	var cj CaseJSON
+	if err := json.Unmarshal(data, &cj); err != nil {
+		return RawTestCase{}, err
+	}
+	if cj.Name == "" {
+		return RawTestCase{}, fmt.Errorf("case has no name")
+	}
+	var msg proto.Message
+	if cj.Message != nil {
+		if cj.Type == "" {
+			return RawTestCase{}, fmt.Errorf("%s: message has no type", cj.Name)
+		}
+		mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(cj.Type))
+		if err != nil {
+			return RawTestCase{}, fmt.Errorf("%s: %w", cj.Name, err)
+		}
+		msg = mt.New().Interface()
+		if err := protojson.Unmarshal(cj.Message, msg); err != nil {
+			return RawTestCase{}, fmt.Errorf("%s: %w", cj.Name, err)
+		}
+	}
+
+	if cj.Hex == "" {
+		if msg == nil {
+			// Empty hex is left out, so a case with neither is empty.
+			return RawTestCase{Name: cj.Name, Data: []byte{}}, nil
+		}
+		b, err := pbutil.Marshal(msg)
+		if err != nil {
+			return RawTestCase{}, fmt.Errorf("%s: %w", cj.Name, err)
+		}
+		return RawTestCase{Name: cj.Name, Data: b}, nil
+	}
+	b, err := hex.DecodeString(cj.Hex)
+	if err != nil {
+		return RawTestCase{}, fmt.Errorf("%s: hex: %w", cj.Name, err)
+	}
+	if msg != nil {
+		// Compare as JSON sees it: unknown fields and encoding details
+		// are what the hex is kept for.
+		if shown, ok := viaJSON(msg.ProtoReflect().Type(), b); ok && !proto.Equal(shown, msg) {
+			return RawTestCase{}, fmt.Errorf("%s: message was edited but the case is kept as hex; edit the hex, or delete it to encode the message", cj.Name)
+		}
+	}
+	return RawTestCase{Name: cj.Name, Data: b}, nil
+}
+
+// viaJSON decodes data as mt and passes the message through JSON, as
+// MarshalCaseJSON shows it.
+func viaJSON(mt protoreflect.MessageType, data []byte) (proto.Message, bool) {
+	msg := mt.New().Interface()
+	if proto.Unmarshal(data, msg) != nil {
+		return nil, false
+	}
+	js, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, false
+	}
+	back := mt.New().Interface()
+	if protojson.Unmarshal(js, back) != nil {
+		return nil, false
+	}
+	return back, true
+}
+
+// marshalIndent writes cj indented, without the HTML escaping that would
+// turn < and > in strings into \u003c and \u003e.
+func marshalIndent(cj CaseJSON) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(cj); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,473 @@
+package testcases_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"compat/pbutil"
+	"compat/testcases"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// unmarshal decodes like the corpus writers encode: some required2 cases
+// deliberately leave required fields unset.
+var unmarshal = proto.UnmarshalOptions{AllowPartial: true}
+
+// TestMutants checks that every mutant tagged must-decode is still a valid
+// encoding of its case's type.
+func TestMutants(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 0))
+	for _, c := range testcases.Categories() {
+		for _, tc := range c.Generate() {
+			mutants, err := testcases.Mutate(rng, tc, 32)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", c.Name, tc.Name, err)
+			}
+			for _, m := range mutants {
+				if !testcases.MustDecode(m.Name) {
+					continue
+				}
+				got := tc.Msg.ProtoReflect().New().Interface()
+				if err := unmarshal.Unmarshal(m.Data, got); err != nil {
+					t.Errorf("%s/%s: %v", c.Name, m.Name, err)
+				}
+			}
+		}
+	}
+}
+
+// TestExpected checks that every generated case is reachable through
+// Expected and survives an encode/decode round trip unchanged.
+func TestExpected(t *testing.T) {
+	for _, c := range testcases.Categories() {
+		for _, tc := range c.Generate() {
+			name := c.Name + "/" + tc.Name
+			want, ok := testcases.Expected(name)
+			if !ok {
+				t.Errorf("%s: no expectation", name)
+				continue
+			}
+			b, err := pbutil.Marshal(tc.Msg)
+			if err != nil {
+				t.Errorf("%s: marshal: %v", name, err)
+				continue
+			}
+			got := want.ProtoReflect().New().Interface()
+			if err := unmarshal.Unmarshal(b, got); err != nil {
+				t.Errorf("%s: unmarshal: %v", name, err)
+				continue
+			}
+			if !proto.Equal(got, want) {
+				t.Errorf("%s: round trip changed the message", name)
+			}
+		}
+	}
+	for _, name := range []string{"acp", "acp/no_such_case", "no_such_category/empty"} {
+		if _, ok := testcases.Expected(name); ok {
+			t.Errorf("Expected(%q) found a case", name)
+		}
+	}
+}
+
+// TestZigCorpus decodes every case the Zig side wrote to testdata/zig and
+// compares it with the Go expectation. Categories without a Zig corpus file
+// are skipped, so the test only has teeth after the Zig tests have run.
+func TestZigCorpus(t *testing.T) {
+	dir := filepath.Join("..", "..", "testdata", "zig")
+	for _, c := range testcases.Categories() {
+		t.Run(c.Name, func(t *testing.T) {
+			path := filepath.Join(dir, c.Name+".bin")
+			data, err := os.ReadFile(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				data, err = os.ReadFile(path + ".gz")
+			}
+			if errors.Is(err, fs.ErrNotExist) || err == nil && len(data) == 0 {
+				t.Skipf("no Zig corpus in %s", dir)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			cases, err := testcases.ReadTestCases(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, tc := range cases {
+				want, ok := testcases.Expected(c.Name + "/" + tc.Name)
+				if !ok {
+					t.Errorf("%s: no expectation for this case name", tc.Name)
+					continue
+				}
+				got := want.ProtoReflect().New().Interface()
+				if err := unmarshal.Unmarshal(tc.Data, got); err != nil {
+					t.Errorf("%s: unmarshal: %v", tc.Name, err)
+					continue
+				}
+				if !proto.Equal(got, want) {
+					t.Errorf("%s: Zig vector decodes to\n  %v\nwant\n  %v", tc.Name, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestRejects checks that the Go decoder rejects every vector meant to be
+// malformed, and that its framed name carries its category.
+func TestRejects(t *testing.T) {
+	for _, c := range testcases.Categories() {
+		for _, tc := range testcases.Rejects(c.Name) {
+			if n := testcases.CheckReject(c, tc); n != 0 {
+				t.Errorf("%s/%s: decoded without error", c.Name, tc.Name)
+			}
+			if cat, name, ok := testcases.ParseRejectName(tc.TaggedName()); !ok || cat != tc.Error || name != tc.Name {
+				t.Errorf("%s/%s: tagged name %q parses as %q, %q", c.Name, tc.Name, tc.TaggedName(), cat, name)
+			}
+		}
+	}
+}
+
+// TestRejectResults feeds CheckRejectResults results that get every vector
+// right, and then results that accept one, misclassify one and leave one
+// out.
+func TestRejectResults(t *testing.T) {
+	var results []testcases.RejectResult
+	for _, c := range testcases.Categories() {
+		for _, tc := range testcases.Rejects(c.Name) {
+			results = append(results, testcases.RejectResult{Corpus: c.Name, Case: tc.TaggedName(), Error: tc.Error})
+		}
+	}
+	if len(results) < 3 {
+		t.Fatalf("%d reject vectors, want at least 3", len(results))
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, res := range results {
+		if err := enc.Encode(res); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read, err := testcases.ReadRejectResults(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := testcases.CheckRejectResults(read, nil); n != 0 {
+		t.Errorf("correct results: %d failure(s)", n)
+	}
+
+	read[0].Error = ""
+	if read[1].Error == testcases.ErrTruncated {
+		read[1].Error = testcases.ErrOverflow
+	} else {
+		read[1].Error = testcases.ErrTruncated
+	}
+	if n := testcases.CheckRejectResults(read[:len(read)-1], nil); n != 3 {
+		t.Errorf("broken results: %d failure(s), want 3", n)
+	}
+}
+
+// TestOverLimit checks that every over-limit vector breaks DefaultLimits
+// and nothing else.
+func TestOverLimit(t *testing.T) {
+	for _, c := range testcases.Categories() {
+		for _, tc := range testcases.OverLimit(c.Name) {
+			if n := testcases.CheckOverLimit(c, tc, testcases.DefaultLimits); n != 0 {
+				t.Errorf("%s/%s: does not cross the limits alone", c.Name, tc.Name)
+			}
+		}
+	}
+}
+
+// TestOwnership checks that messages decoded from the Go corpus do not
+// change when the buffer they were decoded from is overwritten.
+func TestOwnership(t *testing.T) {
+	for _, c := range testcases.Categories() {
+		data, _, err := testcases.BuildCorpus(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cases, err := testcases.ReadTestCases(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tc := range cases {
+			if n := testcases.CheckOwnership(c, tc); n != 0 {
+				t.Errorf("%s/%s: decoded message aliases its input", c.Name, tc.Name)
+			}
+		}
+	}
+}
+
+// TestProfiles checks that every category a profile names is registered,
+// so a renamed category cannot silently drop out of a profile.
+func TestProfiles(t *testing.T) {
+	if _, err := testcases.LookupProfile(testcases.DefaultProfile); err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for _, p := range testcases.Profiles {
+		if seen[p.Name] {
+			t.Errorf("profile %q listed twice", p.Name)
+		}
+		seen[p.Name] = true
+		for _, name := range p.Categories {
+			if _, ok := testcases.Lookup(name); !ok {
+				t.Errorf("profile %s: no category %q", p.Name, name)
+			}
+		}
+	}
+	if _, err := testcases.LookupProfile("no-such-profile"); err == nil {
+		t.Error("LookupProfile found an unknown profile")
+	}
+}
+
+// TestArchive checks that an archive holds a verifiable copy of a corpus, is
+// found by Archives, and is never rewritten.
+func TestArchive(t *testing.T) {
+	root := t.TempDir()
+	c, ok := testcases.Lookup("scalar3")
+	if !ok {
+		t.Fatal("no scalar3 category")
+	}
+	data, _, err := testcases.BuildCorpus(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "scalar3.bin"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mf, err := testcases.NewManifestFile("scalar3", "scalar3.bin", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &testcases.Manifest{Files: []testcases.ManifestFile{mf}}
+
+	if err := testcases.WriteArchive(root, 2, m); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(root, "v01"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	versions, err := testcases.Archives(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0] != 2 {
+		t.Fatalf("Archives = %v, want [2]", versions)
+	}
+
+	dir := testcases.ArchiveDir(root, 2)
+	got, err := testcases.ReadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := got.Verify(dir); len(errs) > 0 {
+		t.Errorf("archive does not verify: %v", errs)
+	}
+	if err := testcases.WriteArchive(root, 2, m); err == nil {
+		t.Error("WriteArchive rewrote an existing archive")
+	}
+}
+
+// TestWriteCorpusFile checks that a streamed corpus file, raw or gzipped,
+// holds what BuildCorpus builds and that its manifest entry verifies.
+func TestWriteCorpusFile(t *testing.T) {
+	dir := t.TempDir()
+	m := &testcases.Manifest{}
+	for _, c := range testcases.Categories() {
+		want, n, err := testcases.BuildCorpus(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range []string{c.Name + ".bin", c.Name + ".bin.gz"} {
+			mf, err := testcases.WriteCorpusFile(dir, file, c, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(mf.Cases) != n {
+				t.Errorf("%s: %d cases in manifest, want %d", file, len(mf.Cases), n)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if testcases.IsCompressed(data) {
+				if data, err = testcases.Decompress(data); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !bytes.Equal(data, want) {
+				t.Errorf("%s differs from BuildCorpus", file)
+			}
+			m.Files = append(m.Files, mf)
+		}
+	}
+	if errs := m.Verify(dir); len(errs) > 0 {
+		t.Errorf("streamed corpus does not verify: %v", errs)
+	}
+}
+
+// TestOpenCorpus checks that a corpus reads the same whether it is mapped,
+// read, or gzipped and so never mapped.
+func TestOpenCorpus(t *testing.T) {
+	dir := t.TempDir()
+	c, ok := testcases.Lookup("scalar3")
+	if !ok {
+		t.Fatal("no scalar3 category")
+	}
+	data, _, err := testcases.BuildCorpus(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := testcases.ReadTestCases(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"scalar3.bin", "scalar3.bin.gz"} {
+		if _, err := testcases.WriteCorpusFile(dir, file, c, false); err != nil {
+			t.Fatal(err)
+		}
+		for _, mmapMin := range []int64{0, 1} {
+			cf, err := testcases.OpenCorpus(filepath.Join(dir, file), mmapMin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cf.Mapped && (mmapMin == 0 || filepath.Ext(file) == ".gz") {
+				t.Errorf("%s, mmapMin %d: mapped", file, mmapMin)
+			}
+			if len(cf.Cases) != len(want) {
+				t.Fatalf("%s, mmapMin %d: %d cases, want %d", file, mmapMin, len(cf.Cases), len(want))
+			}
+			for i, tc := range cf.Cases {
+				if tc.Name != want[i].Name || !bytes.Equal(tc.Data, want[i].Data) {
+					t.Errorf("%s, mmapMin %d: case %d is %s, want %s", file, mmapMin, i, tc.Name, want[i].Name)
+				}
+			}
+			if err := cf.Close(); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+}
+
+// TestFindTestCase looks up every case of a corpus, and one it lacks, with
+// and without an index, and checks that an indexed file reads the same as a
+// bare one.
+func TestFindTestCase(t *testing.T) {
+	dir := t.TempDir()
+	c, ok := testcases.Lookup("map3")
+	if !ok {
+		t.Fatal("no map3 category")
+	}
+	bare, _, err := testcases.BuildCorpus(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := testcases.ReadTestCases(bare)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"map3.bin", "map3.bin.gz"} {
+		if _, err := testcases.WriteCorpusFile(dir, file, c, true); err != nil {
+			t.Fatal(err)
+		}
+		indexed, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := testcases.ReadTestCases(indexed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%s: %d cases, want %d", file, len(got), len(want))
+		}
+		for i := range got {
+			if got[i].Name != want[i].Name || !bytes.Equal(got[i].Data, want[i].Data) {
+				t.Errorf("%s: case %d is %s, want %s", file, i, got[i].Name, want[i].Name)
+			}
+		}
+
+		for _, data := range [][]byte{bare, indexed} {
+			for _, tc := range want {
+				found, ok, err := testcases.FindTestCase(data, tc.Name)
+				if err != nil || !ok || !bytes.Equal(found.Data, tc.Data) {
+					t.Errorf("%s: FindTestCase(%s) = %v, %v, %v", file, tc.Name, found.Name, ok, err)
+				}
+			}
+			if _, ok, err := testcases.FindTestCase(data, "no_such_case"); ok || err != nil {
+				t.Errorf("%s: found no_such_case: %v, %v", file, ok, err)
+			}
+		}
+
+		cf, err := testcases.OpenCorpusCase(filepath.Join(dir, file), want[1].Name, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cf.Cases) != 1 || cf.Cases[0].Name != want[1].Name {
+			t.Errorf("%s: OpenCorpusCase got %d cases", file, len(cf.Cases))
+		}
+		cf.Close()
+	}
+
+	future := append([]byte("\xffPBC\x02\x00\x00\x00"), bare...)
+	if _, err := testcases.ReadTestCases(future); err == nil {
+		t.Error("read a corpus of an unknown format version")
+	}
+}
+
+// TestManifestVerifyTampered checks that Verify catches a corpus file
+// changed in place, truncated, or removed after its manifest was written.
+func TestManifestVerifyTampered(t *testing.T) {
+	c, ok := testcases.Lookup("scalar3")
+	if !ok {
+		t.Fatal("no scalar3 category")
+	}
+	for _, tt := range []struct {
+		name   string
+		tamper func(path string, data []byte) error
+	}{
+		{"flipped byte", func(path string, data []byte) error {
+			data[len(data)-1] ^= 0xff
+			return os.WriteFile(path, data, 0o644)
+		}},
+		{"truncated", func(path string, data []byte) error {
+			return os.WriteFile(path, data[:len(data)/2], 0o644)
+		}},
+		{"removed", func(path string, data []byte) error {
+			return os.Remove(path)
+		}},
+	} {
+		dir := t.TempDir()
+		mf, err := testcases.WriteCorpusFile(dir, "scalar3.bin", c, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := testcases.WriteManifest(dir, &testcases.Manifest{Files: []testcases.ManifestFile{mf}}); err != nil {
+			t.Fatal(err)
+		}
+		m, err := testcases.ReadManifest(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if errs := m.Verify(dir); len(errs) > 0 {
+			t.Fatalf("untouched corpus does not verify: %v", errs)
+		}
+		path := filepath.Join(dir, "scalar3.bin")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tt.tamper(path, data); err != nil {
+			t.Fatal(err)
+		}
+		if errs := m.Verify(dir); len(errs) == 0 {
+			t.Errorf("%s: Verify found nothing wrong", tt.name)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package testcases
+
+import (
+	"io"
+	"os"
+)
+
+// DefaultMmapMin is the size from which OpenCorpus maps a raw corpus file
+// instead of reading it.
+const DefaultMmapMin = 64 << 20
+
+// CorpusFile is a corpus file opened by OpenCorpus. Its cases are valid
+// until Close.
+type CorpusFile struct {
+	Cases []RawTestCase
+	// Mapped reports whether the cases point into a memory mapping of the
+	// file rather than a copy of it.
+	Mapped bool
+
+	unmap func() error
+}
+
+// OpenCorpus reads and unframes the corpus file at path. A raw file of at
+// least mmapMin bytes is memory-mapped, so that a multi-GB stress corpus is
+// paged in as its cases are used rather than read in whole up front;
+// mmapMin <= 0 never maps. Gzipped files, and files that cannot be mapped on
+// this platform, are read as ReadTestCases would.
+func OpenCorpus(path string, mmapMin int64) (*CorpusFile, error) {
+	return openCorpus(path, mmapMin, ReadTestCases)
+}
+
+// OpenCorpusCase opens the corpus file at path as OpenCorpus does, but
+// unframes only the case called name, found with FindTestCase. The file's
+// Cases hold that case, or nothing if the corpus has no such case.
+func OpenCorpusCase(path, name string, mmapMin int64) (*CorpusFile, error) {
+	return openCorpus(path, mmapMin, func(data []byte) ([]RawTestCase, error) {
+		tc, ok, err := FindTestCase(data, name)
+		if !ok {
+			return nil, err
+		}
+		return []RawTestCase{tc}, nil
+	})
+}
+
+func openCorpus(path string, mmapMin int64, unframe func([]byte) ([]RawTestCase, error)) (*CorpusFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	cf := &CorpusFile{}
+	var data []byte
+	if mmapMin > 0 && info.Size() >= mmapMin {
+		// A file that cannot be mapped, on this platform or this file
+		// system, is read instead.
+		if mapped, unmap, err := mmapFile(f, info.Size()); err == nil {
+			if IsCompressed(mapped) {
+				// Inflating a gzipped corpus needs a copy anyway.
+				unmap()
+			} else {
+				data, cf.unmap, cf.Mapped = mapped, unmap, true
+			}
+		}
+	}
+	if data == nil {
+		if data, err = io.ReadAll(f); err != nil {
+			return nil, err
+		}
+	}
+	if cf.Cases, err = unframe(data); err != nil {
+		cf.Close()
+		return nil, err
+	}
+	return cf, nil
+}
+
+// Close releases the file's mapping, if it has one. The cases must not be
+// used afterwards.
+func (cf *CorpusFile) Close() error {
+	if cf == nil || cf.unmap == nil {
+		return nil
+	}
+	err := cf.unmap()
+	cf.unmap, cf.Cases = nil, nil
+	return err
+}
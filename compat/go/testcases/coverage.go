@@ -0,0 +1,158 @@
+package testcases
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// assertions collects the field paths passed to check since the last call to
+// TakeAssertions.
+var assertions []string
+
+// TakeAssertions returns the field paths asserted by validators since the
+// previous call and resets the list.
+func TakeAssertions() []string {
+	paths := assertions
+	assertions = nil
+	return paths
+}
+
+// Coverage accumulates, per message field, whether it was populated in any
+// decoded payload and whether any validator asserted on it.
+type Coverage struct {
+	messages  map[protoreflect.FullName]protoreflect.MessageDescriptor
+	populated map[protoreflect.FullName]bool
+	asserted  map[protoreflect.FullName]bool
+}
+
+// NewCoverage returns an empty coverage tracker.
+func NewCoverage() *Coverage {
+	return &Coverage{
+		messages:  map[protoreflect.FullName]protoreflect.MessageDescriptor{},
+		populated: map[protoreflect.FullName]bool{},
+		asserted:  map[protoreflect.FullName]bool{},
+	}
+}
+
+// AddMessage registers md and every message type reachable from it so that
+// fields never seen at all still show up in the report.
+func (c *Coverage) AddMessage(md protoreflect.MessageDescriptor) {
+	if md.IsMapEntry() {
+		if v := md.Fields().ByNumber(2); v != nil && v.Message() != nil {
+			c.AddMessage(v.Message())
+		}
+		return
+	}
+	if _, ok := c.messages[md.FullName()]; ok {
+		return
+	}
+	c.messages[md.FullName()] = md
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if sub := fields.Get(i).Message(); sub != nil {
+			c.AddMessage(sub)
+		}
+	}
+}
+
+// AddPopulated records every field set in m, recursing into submessages,
+// repeated messages, and message-valued maps.
+func (c *Coverage) AddPopulated(m protoreflect.Message) {
+	c.AddMessage(m.Descriptor())
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		c.populated[fd.FullName()] = true
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					c.AddPopulated(mv.Message())
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					c.AddPopulated(list.Get(i).Message())
+				}
+			}
+		case fd.Message() != nil:
+			c.AddPopulated(v.Message())
+		}
+		return true
+	})
+}
+
+// AddAssertions resolves validator field paths such as "metadata.uri",
+// "items[0].name", or "str_msg[a].id" against md and records the fields they
+// name. Path segments that are not fields (".len", "value_type") are ignored.
+func (c *Coverage) AddAssertions(md protoreflect.MessageDescriptor, paths []string) {
+	c.AddMessage(md)
+	for _, path := range paths {
+		cur := md
+		for _, seg := range strings.Split(path, ".") {
+			if i := strings.IndexByte(seg, '['); i >= 0 {
+				seg = seg[:i]
+			}
+			if cur == nil {
+				break
+			}
+			fd := cur.Fields().ByName(protoreflect.Name(seg))
+			if fd == nil {
+				break
+			}
+			c.asserted[fd.FullName()] = true
+			switch {
+			case fd.IsMap():
+				cur = fd.MapValue().Message()
+			default:
+				cur = fd.Message()
+			}
+		}
+	}
+}
+
+// Report writes a summary line followed by every field that was never
+// populated or never asserted.
+func (c *Coverage) Report(w io.Writer) {
+	names := make([]string, 0, len(c.messages))
+	for name := range c.messages {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	total, populated, asserted := 0, 0, 0
+	var gaps []string
+	for _, name := range names {
+		fields := c.messages[protoreflect.FullName(name)].Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			total++
+			p, a := c.populated[fd.FullName()], c.asserted[fd.FullName()]
+			if p {
+				populated++
+			}
+			if a {
+				asserted++
+			}
+			switch {
+			case !p && !a:
+				gaps = append(gaps, fmt.Sprintf("%s: never populated, never asserted", fd.FullName()))
+			case !p:
+				gaps = append(gaps, fmt.Sprintf("%s: never populated", fd.FullName()))
+			case !a:
+				gaps = append(gaps, fmt.Sprintf("%s: never asserted", fd.FullName()))
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "field coverage: %d messages, %d fields, %d populated, %d asserted\n",
+		len(names), total, populated, asserted)
+	for _, gap := range gaps {
+		fmt.Fprintf(w, "  %s\n", gap)
+	}
+}
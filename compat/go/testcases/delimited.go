@@ -0,0 +1,78 @@
+package testcases
+
+import (
+	"fmt"
+	"io"
+
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// DelimitedExt is the file extension used for varint-delimited corpora.
+const DelimitedExt = ".delim"
+
+// WriteDelimited writes msg in the standard varint-delimited stream format
+// (protodelim): [varint msg_len][msg bytes]. Unlike the corpus framing, the
+// stream carries no case names.
+func WriteDelimited(w io.Writer, msg proto.Message) error {
+	data, err := pbutil.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return WriteDelimitedRaw(w, data)
+}
+
+// WriteDelimitedRaw writes already-encoded message bytes with a varint
+// length prefix.
+func WriteDelimitedRaw(w io.Writer, data []byte) error {
+	if _, err := w.Write(protowire.AppendVarint(nil, uint64(len(data)))); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	return nil
+}
+
+// WriteDelimitedCorpus writes the category's cases, in order, as a
+// varint-delimited stream and returns how many it wrote. Each message is
+// the bytes the case is framed as in the corpus, so hand-crafted Wire
+// cases keep their exact encoding.
+func WriteDelimitedCorpus(w io.Writer, c Category) (int, error) {
+	cases := c.Generate()
+	for _, tc := range cases {
+		data, err := tc.Data()
+		if err == nil {
+			err = WriteDelimitedRaw(w, data)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", tc.Name, err)
+		}
+	}
+	return len(cases), nil
+}
+
+// ReadDelimited splits a varint-delimited stream into its message payloads.
+// The returned slices alias data.
+func ReadDelimited(data []byte) ([][]byte, error) {
+	var msgs [][]byte
+	pos := 0
+	for pos < len(data) {
+		msgLen, n := protowire.ConsumeVarint(data[pos:])
+		if n < 0 {
+			if err := protowire.ParseError(n); err != io.ErrUnexpectedEOF {
+				return nil, fmt.Errorf("bad length prefix at offset %d: %w", pos, err)
+			}
+			return nil, &ErrTruncatedCorpus{Offset: pos, What: "length prefix"}
+		}
+		pos += n
+		if msgLen > uint64(len(data)-pos) {
+			return nil, &ErrTruncatedCorpus{Offset: pos, What: "message data"}
+		}
+		msgs = append(msgs, data[pos:pos+int(msgLen)])
+		pos += int(msgLen)
+	}
+	return msgs, nil
+}
@@ -1,11 +1,18 @@
 package testcases
 
 import (
+	"fmt"
 	"math"
 
 	"compat/pb"
+
+	"google.golang.org/protobuf/proto"
 )
 
+func init() {
+	Register("edge3", GenerateEdge3, validateEdge3)
+}
+
 func GenerateEdge3() []TestCase {
 	return []TestCase{
 		{
@@ -36,3 +43,33 @@ func GenerateEdge3() []TestCase {
 		},
 	}
 }
+
+func validateEdge3(tc RawTestCase) int {
+	msg := &pb.EdgeMessage{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "special_floats":
+		failures += check(tc.Name, "f_nan", math.IsNaN(msg.FNan))
+		failures += check(tc.Name, "f_pos_inf", math.IsInf(msg.FPosInf, 1))
+		failures += check(tc.Name, "f_neg_inf", math.IsInf(msg.FNegInf, -1))
+	case "extreme_ints":
+		failures += check(tc.Name, "f_max_int32", msg.FMaxInt32 == math.MaxInt32)
+		failures += check(tc.Name, "f_min_int32", msg.FMinInt32 == math.MinInt32)
+		failures += check(tc.Name, "f_max_int64", msg.FMaxInt64 == math.MaxInt64)
+		failures += check(tc.Name, "f_min_int64", msg.FMinInt64 == math.MinInt64)
+		failures += check(tc.Name, "f_max_uint32", msg.FMaxUint32 == math.MaxUint32)
+		failures += check(tc.Name, "f_max_uint64", msg.FMaxUint64 == math.MaxUint64)
+	case "unicode_and_binary":
+		failures += check(tc.Name, "f_unicode", msg.FUnicode == "hello \xc3\xa9\xc3\xa0\xc3\xbc \xe4\xb8\x96\xe7\x95\x8c")
+		failures += check(tc.Name, "f_binary.len", len(msg.FBinary) == 6)
+		if len(msg.FBinary) == 6 {
+			failures += check(tc.Name, "f_binary", msg.FBinary[0] == 0x00 && msg.FBinary[1] == 0x01 && msg.FBinary[2] == 0x02 && msg.FBinary[3] == 0xff && msg.FBinary[4] == 0xfe && msg.FBinary[5] == 0xfd)
+		}
+	}
+	return failures
+}
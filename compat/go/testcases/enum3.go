@@ -1,9 +1,55 @@
 package testcases
 
-import "compat/pb"
+import (
+	"fmt"
+	"math"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("enum3", GenerateEnum3, validateEnum3)
+}
+
+// enumTruncations carry enum values wider than int32 on the wire. Readers
+// keep the low 32 bits as a two's-complement int32, as the C++ and Go
+// runtimes do, so each decodes to the value in want.
+var enumTruncations = []struct {
+	name string
+	wire []byte
+	want *pb.EnumMessage
+}{
+	{"wide_high_bits", varintField(1, 1<<32|2),
+		&pb.EnumMessage{Color: pb.Color_COLOR_GREEN}},
+	{"wide_uint32_max", varintField(1, math.MaxUint32),
+		&pb.EnumMessage{Color: -1}},
+	{"wide_int32_overflow", varintField(1, 1<<31),
+		&pb.EnumMessage{Color: math.MinInt32}},
+	{"wide_top_bit", varintField(1, 1<<63),
+		&pb.EnumMessage{}},
+	{"wide_int64_min_plus_one", varintField(1, uint64(1)<<63|1),
+		&pb.EnumMessage{Color: pb.Color_COLOR_RED}},
+	{"wide_packed", packedVarints(2, 1<<32|1, math.MaxUint32-1, 3),
+		&pb.EnumMessage{Colors: []pb.Color{pb.Color_COLOR_RED, -2, pb.Color_COLOR_BLUE}}},
+}
+
+func varintField(num protowire.Number, v uint64) []byte {
+	return protowire.AppendVarint(protowire.AppendTag(nil, num, protowire.VarintType), v)
+}
+
+func packedVarints(num protowire.Number, vs ...uint64) []byte {
+	var body []byte
+	for _, v := range vs {
+		body = protowire.AppendVarint(body, v)
+	}
+	return protowire.AppendBytes(protowire.AppendTag(nil, num, protowire.BytesType), body)
+}
 
 func GenerateEnum3() []TestCase {
-	return []TestCase{
+	cases := []TestCase{
 		{
 			Name: "default",
 			Msg:  &pb.EnumMessage{},
@@ -23,5 +69,51 @@ func GenerateEnum3() []TestCase {
 				Name:   "multi",
 			},
 		},
+		{
+			// Negative values are sign-extended to ten bytes.
+			Name: "negative",
+			Msg:  &pb.EnumMessage{Color: -1, Colors: []pb.Color{math.MinInt32, math.MaxInt32}},
+		},
+	}
+	for _, e := range enumTruncations {
+		cases = append(cases, TestCase{Name: e.name, Msg: e.want, Wire: e.wire})
+	}
+	return cases
+}
+
+func validateEnum3(tc RawTestCase) int {
+	msg := &pb.EnumMessage{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "default":
+		failures += check(tc.Name, "color", msg.Color == pb.Color_COLOR_UNSPECIFIED)
+	case "red":
+		failures += check(tc.Name, "color", msg.Color == pb.Color_COLOR_RED)
+		failures += check(tc.Name, "name", msg.Name == "red_test")
+	case "repeated":
+		failures += check(tc.Name, "color", msg.Color == pb.Color_COLOR_BLUE)
+		failures += check(tc.Name, "colors.len", len(msg.Colors) == 3)
+		if len(msg.Colors) == 3 {
+			failures += check(tc.Name, "colors[0]", msg.Colors[0] == pb.Color_COLOR_RED)
+			failures += check(tc.Name, "colors[1]", msg.Colors[1] == pb.Color_COLOR_GREEN)
+			failures += check(tc.Name, "colors[2]", msg.Colors[2] == pb.Color_COLOR_BLUE)
+		}
+		failures += check(tc.Name, "name", msg.Name == "multi")
+	case "negative":
+		failures += check(tc.Name, "color", msg.Color == -1)
+		failures += check(tc.Name, "colors", len(msg.Colors) == 2 && msg.Colors[0] == math.MinInt32 && msg.Colors[1] == math.MaxInt32)
+	default:
+		for _, e := range enumTruncations {
+			if e.name == tc.Name {
+				failures += check(tc.Name, "color", msg.Color == e.want.Color)
+				failures += check(tc.Name, "colors", proto.Equal(msg, e.want))
+			}
+		}
 	}
+	return failures
 }
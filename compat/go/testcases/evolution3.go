@@ -0,0 +1,158 @@
+package testcases
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"compat/pb"
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("evolution3", GenerateEvolution3, validateEvolution3)
+}
+
+// evolutionWideCount does not fit in an int32. Read as EvolutionV1's int32
+// count it truncates to its low 32 bits, 0x8000_0001, as a C++ cast would.
+const (
+	evolutionWideCount      int64 = 0x1_8000_0001
+	evolutionTruncatedCount int32 = -0x7fff_ffff
+)
+
+// GenerateEvolution3 returns cases written with one version of the evolving
+// message, named for the writer: v1_ cases are EvolutionV1 encodings for a
+// reader with EvolutionV2, and v2_ cases the reverse.
+func GenerateEvolution3() []TestCase {
+	return []TestCase{
+		{Name: "v1_empty", Msg: &pb.EvolutionV1{}},
+		{
+			Name: "v1_all_set",
+			Msg:  &pb.EvolutionV1{Id: 1, Name: "widget", Count: 42, Note: "first"},
+		},
+		{
+			// A negative int32 is sign-extended to ten bytes on the wire, so
+			// the int64 reader sees the same value.
+			Name: "v1_negative_count",
+			Msg:  &pb.EvolutionV1{Id: 2, Count: math.MinInt32},
+		},
+		{Name: "v2_empty", Msg: &pb.EvolutionV2{}},
+		{
+			Name: "v2_all_set",
+			Msg: &pb.EvolutionV2{
+				Id:       3,
+				Title:    "gadget",
+				Count:    7,
+				Detail:   &pb.EvolutionV2_Note{Note: "second"},
+				Tags:     []string{"new", "tags"},
+				Archived: true,
+			},
+		},
+		{
+			Name: "v2_wide_count",
+			Msg:  &pb.EvolutionV2{Id: 4, Count: evolutionWideCount},
+		},
+		{
+			// code is the oneof member EvolutionV1 has never heard of.
+			Name: "v2_code",
+			Msg:  &pb.EvolutionV2{Id: 5, Detail: &pb.EvolutionV2_Code{Code: 9}},
+		},
+	}
+}
+
+// validateEvolution3 decodes each case with the version that did not write
+// it. An EvolutionV1 reader must also keep the fields it does not know, so
+// that re-encoding what it read gives an EvolutionV2 reader the original.
+func validateEvolution3(tc RawTestCase) int {
+	if strings.HasPrefix(tc.Name, "v1_") {
+		return validateEvolutionNewReader(tc)
+	}
+	return validateEvolutionOldReader(tc)
+}
+
+func validateEvolutionNewReader(tc RawTestCase) int {
+	msg := &pb.EvolutionV2{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal as EvolutionV2: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "v1_empty":
+		failures += check(tc.Name, "detail", msg.Detail == nil)
+		failures += check(tc.Name, "tags", len(msg.Tags) == 0)
+		failures += check(tc.Name, "archived", !msg.Archived)
+	case "v1_all_set":
+		failures += check(tc.Name, "id", msg.Id == 1)
+		failures += check(tc.Name, "title", msg.Title == "widget")
+		failures += check(tc.Name, "count", msg.Count == 42)
+		failures += check(tc.Name, "note", msg.GetNote() == "first")
+		failures += check(tc.Name, "tags", len(msg.Tags) == 0)
+		failures += check(tc.Name, "archived", !msg.Archived)
+	case "v1_negative_count":
+		failures += check(tc.Name, "id", msg.Id == 2)
+		failures += check(tc.Name, "count", msg.Count == math.MinInt32)
+		failures += check(tc.Name, "detail", msg.Detail == nil)
+	}
+	failures += check(tc.Name, "unknown_fields", len(msg.ProtoReflect().GetUnknown()) == 0)
+	return failures
+}
+
+func validateEvolutionOldReader(tc RawTestCase) int {
+	msg := &pb.EvolutionV1{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal as EvolutionV1: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	unknown := len(msg.ProtoReflect().GetUnknown())
+	switch tc.Name {
+	case "v2_empty":
+		failures += check(tc.Name, "id", msg.Id == 0)
+		failures += check(tc.Name, "unknown_fields", unknown == 0)
+	case "v2_all_set":
+		failures += check(tc.Name, "id", msg.Id == 3)
+		failures += check(tc.Name, "name", msg.Name == "gadget")
+		failures += check(tc.Name, "count", msg.Count == 7)
+		failures += check(tc.Name, "note", msg.Note == "second")
+		failures += check(tc.Name, "unknown_fields", unknown > 0)
+		failures += checkEvolutionRelay(tc, msg, &pb.EvolutionV2{
+			Id:       3,
+			Title:    "gadget",
+			Count:    7,
+			Detail:   &pb.EvolutionV2_Note{Note: "second"},
+			Tags:     []string{"new", "tags"},
+			Archived: true,
+		})
+	case "v2_wide_count":
+		failures += check(tc.Name, "id", msg.Id == 4)
+		failures += check(tc.Name, "count", msg.Count == evolutionTruncatedCount)
+		failures += check(tc.Name, "unknown_fields", unknown == 0)
+	case "v2_code":
+		failures += check(tc.Name, "id", msg.Id == 5)
+		failures += check(tc.Name, "note", msg.Note == "")
+		failures += check(tc.Name, "unknown_fields", unknown > 0)
+		failures += checkEvolutionRelay(tc, msg, &pb.EvolutionV2{Id: 5, Detail: &pb.EvolutionV2_Code{Code: 9}})
+	}
+	return failures
+}
+
+// checkEvolutionRelay re-encodes what an EvolutionV1 reader decoded and
+// checks that an EvolutionV2 reader gets want from it.
+func checkEvolutionRelay(tc RawTestCase, old *pb.EvolutionV1, want *pb.EvolutionV2) int {
+	data, err := pbutil.Marshal(old)
+	if err != nil {
+		fmt.Printf("  FAIL %s: re-encode as EvolutionV1: %v\n", tc.Name, err)
+		return 1
+	}
+	got := &pb.EvolutionV2{}
+	if err := proto.Unmarshal(data, got); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal relayed bytes as EvolutionV2: %v\n", tc.Name, err)
+		return 1
+	}
+	return check(tc.Name, "relayed", proto.Equal(got, want))
+}
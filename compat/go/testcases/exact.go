@@ -0,0 +1,131 @@
+package testcases
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Divergence names a class of byte-level differences from the Go
+// deterministic encoding that strict comparison may tolerate.
+type Divergence string
+
+const (
+	// DivergenceMapOrder tolerates map entries emitted in a different order.
+	// Map iteration order is unspecified, so this is valid output.
+	DivergenceMapOrder Divergence = "map-order"
+)
+
+// ParseDivergences parses a comma-separated list of divergence names.
+func ParseDivergences(list string) (map[Divergence]bool, error) {
+	allow := map[Divergence]bool{}
+	for _, name := range strings.Split(list, ",") {
+		switch d := Divergence(strings.TrimSpace(name)); d {
+		case "":
+		case DivergenceMapOrder:
+			allow[d] = true
+		default:
+			return nil, fmt.Errorf("unknown divergence %q", name)
+		}
+	}
+	return allow, nil
+}
+
+// CompareExact compares got byte-for-byte with the deterministic Go encoding
+// of want. It returns nil when they are identical, or when they differ only
+// in divergences listed in allow.
+func CompareExact(got []byte, want proto.Message, allow map[Divergence]bool) error {
+	wantData, err := pbutil.Marshal(want)
+	if err != nil {
+		return fmt.Errorf("marshal expected: %w", err)
+	}
+	if bytes.Equal(got, wantData) {
+		return nil
+	}
+	if allow[DivergenceMapOrder] {
+		md := want.ProtoReflect().Descriptor()
+		if g, ok := canonicalMapOrder(md, got); ok {
+			if w, ok := canonicalMapOrder(md, wantData); ok && bytes.Equal(g, w) {
+				return nil
+			}
+		}
+	}
+	if len(got) != len(wantData) {
+		return fmt.Errorf("not byte-exact: %d bytes, Go encodes %d (first difference at offset %d)",
+			len(got), len(wantData), firstDifference(got, wantData))
+	}
+	return fmt.Errorf("not byte-exact: first difference at offset %d", firstDifference(got, wantData))
+}
+
+func firstDifference(a, b []byte) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// canonicalMapOrder rewrites an encoded message so that the entries of each
+// map field are moved to the end, grouped by field number and sorted by their
+// encoded bytes. Nested messages are rewritten recursively; their lengths do
+// not change, and tag and length bytes are copied verbatim. It reports false
+// if data does not parse.
+func canonicalMapOrder(md protoreflect.MessageDescriptor, data []byte) ([]byte, bool) {
+	var out []byte
+	entries := map[protowire.Number][][]byte{}
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, false
+		}
+		m := protowire.ConsumeFieldValue(num, typ, data[n:])
+		if m < 0 {
+			return nil, false
+		}
+		field := data[:n+m]
+		data = data[n+m:]
+
+		fd := md.Fields().ByNumber(num)
+		if fd == nil || typ != protowire.BytesType || fd.Message() == nil {
+			out = append(out, field...)
+			continue
+		}
+		payload, _ := protowire.ConsumeBytes(field[n:])
+		sub, ok := canonicalMapOrder(fd.Message(), payload)
+		if !ok {
+			return nil, false
+		}
+		// Keep the original tag and length bytes so overlong varints in
+		// them still show up as differences.
+		prefix := field[:len(field)-len(payload)]
+		rewritten := append(append([]byte(nil), prefix...), sub...)
+		if fd.IsMap() {
+			entries[num] = append(entries[num], rewritten)
+			continue
+		}
+		out = append(out, rewritten...)
+	}
+
+	nums := make([]protowire.Number, 0, len(entries))
+	for num := range entries {
+		nums = append(nums, num)
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	for _, num := range nums {
+		list := entries[num]
+		sort.Slice(list, func(i, j int) bool { return bytes.Compare(list[i], list[j]) < 0 })
+		for _, e := range list {
+			out = append(out, e...)
+		}
+	}
+	return out, true
+}
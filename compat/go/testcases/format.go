@@ -0,0 +1,181 @@
+package testcases
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// A corpus file starts with a header giving the version of its format, so
+// that a reader refuses a format newer than it knows instead of misreading
+// it:
+//
+//	[4B magic "\xffPBC"][1B version][1B flags][2B reserved, zero]
+//	cases, framed as in WriteTestCase
+//	if flags has corpusIndexed:
+//	  [8B BE offset of a case's name length] per case, sorted by case name
+//	  [4B BE case count][4B magic "PBCI"]
+//
+// Legacy files, written before the header, are a bare run of framed cases,
+// and are still read as such: none starts with the magic, which read as a
+// name length is over 4GB. The index lets a reader find one case by binary
+// search over the offsets, reading only the names it compares, without
+// scanning the cases.
+const (
+	corpusMagic     = "\xffPBC"
+	CorpusVersion   = 1
+	corpusHeaderLen = 8
+	corpusIndexed   = 1 << 0
+
+	indexMagic     = "PBCI"
+	indexFooterLen = 8
+)
+
+// ErrCorpusFormat reports a corpus header this reader does not understand:
+// a format version other than CorpusVersion, or flags it does not know.
+type ErrCorpusFormat struct {
+	Version byte
+	Flags   []byte // the flags and reserved bytes
+}
+
+func (e *ErrCorpusFormat) Error() string {
+	if e.Version != CorpusVersion {
+		return fmt.Sprintf("corpus format version %d, this reader knows %d", e.Version, CorpusVersion)
+	}
+	return fmt.Sprintf("corpus header flags % x not understood", e.Flags)
+}
+
+// splitCorpus returns the framed cases of uncompressed corpus data, with
+// the offset at which they start, and its index if it has one.
+func splitCorpus(data []byte) (cases []byte, start int, index []byte, err error) {
+	if !bytes.HasPrefix(data, []byte(corpusMagic)) {
+		return data, 0, nil, nil
+	}
+	if len(data) < corpusHeaderLen {
+		return nil, 0, nil, &ErrTruncatedCorpus{Offset: 0, What: "header"}
+	}
+	if data[4] != CorpusVersion || data[5]&^corpusIndexed != 0 || data[6] != 0 || data[7] != 0 {
+		return nil, 0, nil, &ErrCorpusFormat{Version: data[4], Flags: data[5:corpusHeaderLen]}
+	}
+	end := len(data)
+	if data[5]&corpusIndexed != 0 {
+		if end-corpusHeaderLen < indexFooterLen || string(data[end-4:]) != indexMagic {
+			return nil, 0, nil, &ErrTruncatedCorpus{Offset: end, What: "index footer"}
+		}
+		size := 8 * int(binary.BigEndian.Uint32(data[end-indexFooterLen:]))
+		if size > end-corpusHeaderLen-indexFooterLen {
+			return nil, 0, nil, &ErrTruncatedCorpus{Offset: corpusHeaderLen, What: "index"}
+		}
+		index = data[end-indexFooterLen-size : end-indexFooterLen]
+		end -= indexFooterLen + size
+	}
+	return data[corpusHeaderLen:end], corpusHeaderLen, index, nil
+}
+
+// WriteCorpusHeader starts a corpus file in the current format, without an
+// index. Every writer of a corpus file calls it before the first case.
+func WriteCorpusHeader(w io.Writer) error {
+	return writeCorpusHeader(w, 0)
+}
+
+func writeCorpusHeader(w io.Writer, flags byte) error {
+	header := [corpusHeaderLen]byte{4: CorpusVersion, 5: flags}
+	copy(header[:], corpusMagic)
+	_, err := w.Write(header[:])
+	return err
+}
+
+// FindTestCase returns the case called name in corpus data. It binary
+// searches the index of a file that has one and scans the cases of one that
+// does not.
+func FindTestCase(data []byte, name string) (RawTestCase, bool, error) {
+	if IsCompressed(data) {
+		var err error
+		if data, err = Decompress(data); err != nil {
+			return RawTestCase{}, false, err
+		}
+	}
+	body, start, index, err := splitCorpus(data)
+	if err != nil {
+		return RawTestCase{}, false, err
+	}
+	if index == nil {
+		cases, err := ReadTestCases(data)
+		if err != nil {
+			return RawTestCase{}, false, err
+		}
+		for _, tc := range cases {
+			if tc.Name == name {
+				return tc, true, nil
+			}
+		}
+		return RawTestCase{}, false, nil
+	}
+
+	n := len(index) / 8
+	at := func(i int) (RawTestCase, error) {
+		off := binary.BigEndian.Uint64(index[8*i:])
+		if off < uint64(start) || off >= uint64(start+len(body)) {
+			return RawTestCase{}, fmt.Errorf("index entry %d: offset %d outside the cases", i, off)
+		}
+		tc, _, err := readCase(body, int(off)-start)
+		return tc, err
+	}
+	var searchErr error
+	i := sort.Search(n, func(i int) bool {
+		tc, err := at(i)
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return tc.Name >= name
+	})
+	if searchErr != nil {
+		return RawTestCase{}, false, searchErr
+	}
+	if i == n {
+		return RawTestCase{}, false, nil
+	}
+	tc, err := at(i)
+	if err != nil || tc.Name != name {
+		return RawTestCase{}, false, err
+	}
+	return tc, true, nil
+}
+
+// writeIndexed writes cases, as WriteCorpus returned them, in the versioned
+// format with an index, given write to frame the cases themselves.
+func writeIndexed(w *bufio.Writer, write func() ([]ManifestCase, error)) ([]ManifestCase, error) {
+	if err := writeCorpusHeader(w, corpusIndexed); err != nil {
+		return nil, err
+	}
+	cases, err := write()
+	if err != nil {
+		return nil, err
+	}
+
+	type entry struct {
+		name string
+		off  uint64
+	}
+	entries := make([]entry, len(cases))
+	off := uint64(corpusHeaderLen)
+	for i, tc := range cases {
+		entries[i] = entry{tc.Name, off}
+		off += 8 + uint64(len(tc.Name)) + uint64(tc.Size)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+	for _, e := range entries {
+		if _, err := w.Write(binary.BigEndian.AppendUint64(nil, e.off)); err != nil {
+			return nil, err
+		}
+	}
+	footer := binary.BigEndian.AppendUint32(nil, uint32(len(cases)))
+	if _, err := w.Write(append(footer, indexMagic...)); err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
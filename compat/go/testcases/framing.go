@@ -1,10 +1,15 @@
 package testcases
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"encoding/binary"
 	"fmt"
 	"io"
 
+	"compat/pbutil"
+
 	"google.golang.org/protobuf/proto"
 )
 
@@ -12,6 +17,34 @@ import (
 type TestCase struct {
 	Name string
 	Msg  proto.Message
+	// GoOnly marks a case the Zig side reads but cannot write, so the Zig
+	// corpus is not expected to contain it.
+	GoOnly bool
+	// Wire, if set, is written to the corpus in place of the encoding of
+	// Msg. It holds hand-crafted bytes that a decoder must turn into Msg,
+	// such as sequences a marshaler never produces.
+	Wire []byte
+	// Tags are the case's own tags; see Category.CaseTags.
+	Tags []Tag
+}
+
+// Data returns the bytes the case is written to the corpus as.
+func (tc TestCase) Data() ([]byte, error) {
+	if tc.Wire != nil {
+		return tc.Wire, nil
+	}
+	return pbutil.Marshal(tc.Msg)
+}
+
+// ErrTruncatedCorpus reports corpus data that ends in the middle of a case.
+// Offset is the position of the incomplete element and What names it.
+type ErrTruncatedCorpus struct {
+	Offset int
+	What   string
+}
+
+func (e *ErrTruncatedCorpus) Error() string {
+	return fmt.Sprintf("truncated %s at offset %d", e.What, e.Offset)
 }
 
 // RawTestCase holds a named raw byte slice (decoded from framing).
@@ -22,14 +55,37 @@ type RawTestCase struct {
 
 // WriteTestCase writes a single test case using 4-byte BE length-prefix framing:
 // [4-byte BE name_len][name bytes][4-byte BE msg_len][msg bytes]
+// The message is marshaled deterministically.
 func WriteTestCase(w io.Writer, name string, msg proto.Message) error {
-	data, err := proto.Marshal(msg)
+	data, err := pbutil.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("marshal %s: %w", name, err)
 	}
 	return WriteTestCaseRaw(w, name, data)
 }
 
+// WriteTestCaseTo writes a test case framed as WriteTestCase does, but
+// marshals msg straight into w's free buffer space instead of into a slice
+// of its own that is then copied, so that streaming a large corpus does not
+// allocate per case. It returns the size of the encoded message.
+func WriteTestCaseTo(w *bufio.Writer, name string, msg proto.Message) (int, error) {
+	size := pbutil.Size(msg)
+	b := w.AvailableBuffer()
+	b = binary.BigEndian.AppendUint32(b, uint32(len(name)))
+	b = append(b, name...)
+	b = binary.BigEndian.AppendUint32(b, uint32(size))
+	start := len(b)
+	b, err := pbutil.MarshalAppend(b, msg)
+	if err != nil {
+		return 0, fmt.Errorf("marshal %s: %w", name, err)
+	}
+	if len(b)-start != size {
+		return 0, fmt.Errorf("marshal %s: encoded %d bytes, sized %d", name, len(b)-start, size)
+	}
+	_, err = w.Write(b)
+	return size, err
+}
+
 // WriteTestCaseRaw writes a single test case from raw bytes.
 func WriteTestCaseRaw(w io.Writer, name string, data []byte) error {
 	// Write name length
@@ -51,38 +107,134 @@ func WriteTestCaseRaw(w io.Writer, name string, data []byte) error {
 	return nil
 }
 
-// ReadTestCases reads all framed test cases from raw data.
-func ReadTestCases(data []byte) ([]RawTestCase, error) {
-	var cases []RawTestCase
-	pos := 0
+// BuildCorpus frames every case of a category into a single corpus file.
+func BuildCorpus(c Category) ([]byte, int, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	if err := WriteCorpusHeader(w); err != nil {
+		return nil, 0, err
+	}
+	cases, err := WriteCorpus(w, c)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), len(cases), nil
+}
 
-	for pos < len(data) {
-		if pos+4 > len(data) {
-			return nil, fmt.Errorf("truncated name length at offset %d", pos)
+// WriteCorpus streams every case of a category to w, framed as BuildCorpus
+// frames them, and returns the name and size of each for the manifest. The
+// caller writes the header and flushes w.
+func WriteCorpus(w *bufio.Writer, c Category) ([]ManifestCase, error) {
+	cases := c.Generate()
+	written := make([]ManifestCase, 0, len(cases))
+	for _, tc := range cases {
+		size := len(tc.Wire)
+		var err error
+		if tc.Wire != nil {
+			err = WriteTestCaseRaw(w, tc.Name, tc.Wire)
+		} else {
+			size, err = WriteTestCaseTo(w, tc.Name, tc.Msg)
 		}
-		nameLen := int(binary.BigEndian.Uint32(data[pos : pos+4]))
-		pos += 4
-
-		if pos+nameLen > len(data) {
-			return nil, fmt.Errorf("truncated name at offset %d", pos)
+		if err != nil {
+			return nil, fmt.Errorf("write %s/%s: %w", c.Name, tc.Name, err)
 		}
-		name := string(data[pos : pos+nameLen])
-		pos += nameLen
+		written = append(written, ManifestCase{Name: tc.Name, Size: size, Tags: c.CaseTags(tc)})
+	}
+	return written, nil
+}
 
-		if pos+4 > len(data) {
-			return nil, fmt.Errorf("truncated message length at offset %d", pos)
+// ReadTestCases reads all framed test cases from raw data. Gzip-compressed
+// corpora (.bin.gz) are decompressed transparently, and the header and index
+// of a versioned corpus are skipped; see splitCorpus.
+func ReadTestCases(data []byte) ([]RawTestCase, error) {
+	if IsCompressed(data) {
+		var err error
+		if data, err = Decompress(data); err != nil {
+			return nil, err
 		}
-		msgLen := int(binary.BigEndian.Uint32(data[pos : pos+4]))
-		pos += 4
+	}
+	body, start, _, err := splitCorpus(data)
+	if err != nil {
+		return nil, err
+	}
 
-		if pos+msgLen > len(data) {
-			return nil, fmt.Errorf("truncated message data at offset %d", pos)
+	var cases []RawTestCase
+	for pos := 0; pos < len(body); {
+		tc, next, err := readCase(body, pos)
+		if err != nil {
+			if e, ok := err.(*ErrTruncatedCorpus); ok {
+				e.Offset += start
+			}
+			return nil, err
 		}
-		msgData := data[pos : pos+msgLen]
-		pos += msgLen
+		cases = append(cases, tc)
+		pos = next
+	}
+	return cases, nil
+}
 
-		cases = append(cases, RawTestCase{Name: name, Data: msgData})
+// readCase reads the case framed at pos in data and returns it with the
+// position after it.
+func readCase(data []byte, pos int) (RawTestCase, int, error) {
+	if pos+4 > len(data) {
+		return RawTestCase{}, 0, &ErrTruncatedCorpus{Offset: pos, What: "name length"}
 	}
+	nameLen := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+	pos += 4
 
-	return cases, nil
+	if pos+nameLen > len(data) {
+		return RawTestCase{}, 0, &ErrTruncatedCorpus{Offset: pos, What: "name"}
+	}
+	name := string(data[pos : pos+nameLen])
+	pos += nameLen
+
+	if pos+4 > len(data) {
+		return RawTestCase{}, 0, &ErrTruncatedCorpus{Offset: pos, What: "message length"}
+	}
+	msgLen := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+	pos += 4
+
+	if pos+msgLen > len(data) {
+		return RawTestCase{}, 0, &ErrTruncatedCorpus{Offset: pos, What: "message data"}
+	}
+	return RawTestCase{Name: name, Data: data[pos : pos+msgLen]}, pos + msgLen, nil
+}
+
+// IsCompressed reports whether data starts with the gzip magic bytes. A raw
+// corpus can never start with them: that would be a name length over 500MB.
+func IsCompressed(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+// Compress gzips framed corpus data.
+func Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress inflates gzip-compressed corpus data.
+func Decompress(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("gzip corpus: %w", err)
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("gzip corpus: %w", err)
+	}
+	return out, nil
 }
@@ -0,0 +1,174 @@
+package testcases_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"compat/testcases"
+)
+
+// framedCases is a corpus whose cases cover the edges of the framing: an
+// empty name, empty data and a name that is not UTF-8.
+func framedCases(t *testing.T) ([]byte, []testcases.RawTestCase) {
+	t.Helper()
+	cases := []testcases.RawTestCase{
+		{Name: "first", Data: []byte{0x08, 0x01}},
+		{Name: "", Data: []byte{0x10, 0x02}},
+		{Name: "empty", Data: []byte{}},
+		{Name: "\xff\xfe", Data: bytes.Repeat([]byte{0x18, 0x03}, 300)},
+	}
+	var buf bytes.Buffer
+	if err := testcases.WriteCorpusHeader(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range cases {
+		if err := testcases.WriteTestCaseRaw(&buf, tc.Name, tc.Data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes(), cases
+}
+
+// TestReadTestCases checks that framed cases read back as written, with and
+// without the header and gzipped.
+func TestReadTestCases(t *testing.T) {
+	data, want := framedCases(t)
+	gz, err := testcases.Compress(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string][]byte{"header": data, "bare": data[8:], "gzip": gz} {
+		got, err := testcases.ReadTestCases(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%s: %d cases, want %d", name, len(got), len(want))
+		}
+		for i := range want {
+			if got[i].Name != want[i].Name || !bytes.Equal(got[i].Data, want[i].Data) {
+				t.Errorf("%s: case %d is %q (%d bytes), want %q (%d bytes)",
+					name, i, got[i].Name, len(got[i].Data), want[i].Name, len(want[i].Data))
+			}
+		}
+	}
+}
+
+// TestReadTestCasesTruncated cuts a corpus at every offset and checks that
+// each cut between cases reads as the cases before it, and each cut inside
+// one is reported with the element and offset where it stops.
+func TestReadTestCasesTruncated(t *testing.T) {
+	data, want := framedCases(t)
+	// ends[i] is the offset after case i; what names the element that is
+	// cut at each offset inside a case.
+	var ends []int
+	what := map[int]string{}
+	off := 8
+	for _, tc := range want {
+		start := off
+		for i := range 4 {
+			what[start+i] = "name length"
+		}
+		for i := range len(tc.Name) {
+			what[start+4+i] = "name"
+		}
+		for i := range 4 {
+			what[start+4+len(tc.Name)+i] = "message length"
+		}
+		for i := range len(tc.Data) {
+			what[start+8+len(tc.Name)+i] = "message data"
+		}
+		off = start + 8 + len(tc.Name) + len(tc.Data)
+		ends = append(ends, off)
+	}
+
+	for cut := 1; cut < len(data); cut++ {
+		got, err := testcases.ReadTestCases(data[:cut])
+		if cut < 8 {
+			// Short of the whole magic the data does not look like a
+			// header, but it is still too short to be a case.
+			var trunc *testcases.ErrTruncatedCorpus
+			if !errors.As(err, &trunc) {
+				t.Errorf("cut at %d: err = %v, want *ErrTruncatedCorpus", cut, err)
+			}
+			continue
+		}
+		n := 0
+		for n < len(ends) && ends[n] <= cut {
+			n++
+		}
+		if n > 0 && ends[n-1] == cut || cut == 8 {
+			if err != nil || len(got) != n {
+				t.Errorf("cut at %d, between cases: %d cases, %v; want %d", cut, len(got), err, n)
+			}
+			continue
+		}
+		var trunc *testcases.ErrTruncatedCorpus
+		if !errors.As(err, &trunc) {
+			t.Errorf("cut at %d: err = %v, want *ErrTruncatedCorpus", cut, err)
+			continue
+		}
+		// The offset reported is where the cut element starts.
+		if trunc.What != what[cut] || what[trunc.Offset] != what[cut] || trunc.Offset > cut {
+			t.Errorf("cut at %d: truncated %s at %d, want %s", cut, trunc.What, trunc.Offset, what[cut])
+		}
+	}
+}
+
+// TestCorpusHeader checks that headers this reader does not understand are
+// refused rather than read as cases.
+func TestCorpusHeader(t *testing.T) {
+	data, _ := framedCases(t)
+	for _, tt := range []struct {
+		name string
+		at   int
+		b    byte
+	}{
+		{"version", 4, testcases.CorpusVersion + 1},
+		{"unknown flag", 5, 0x80},
+		{"reserved", 6, 1},
+		{"reserved last", 7, 1},
+	} {
+		bad := bytes.Clone(data)
+		bad[tt.at] = tt.b
+		var format *testcases.ErrCorpusFormat
+		if _, err := testcases.ReadTestCases(bad); !errors.As(err, &format) {
+			t.Errorf("%s: err = %v, want *ErrCorpusFormat", tt.name, err)
+		}
+	}
+}
+
+// TestDelimitedMatchesCorpus checks that every category's delimited stream
+// holds exactly the payloads of its framed corpus, in order, including the
+// hand-crafted encodings of Wire cases.
+func TestDelimitedMatchesCorpus(t *testing.T) {
+	for _, c := range testcases.Categories() {
+		data, _, err := testcases.BuildCorpus(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := testcases.ReadTestCases(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		n, err := testcases.WriteDelimitedCorpus(&buf, c)
+		if err != nil {
+			t.Fatalf("%s: %v", c.Name, err)
+		}
+		got, err := testcases.ReadDelimited(buf.Bytes())
+		if err != nil {
+			t.Fatalf("%s: %v", c.Name, err)
+		}
+		if n != len(want) || len(got) != len(want) {
+			t.Errorf("%s: %d delimited messages (%d reported), corpus has %d", c.Name, len(got), n, len(want))
+			continue
+		}
+		for i, tc := range want {
+			if !bytes.Equal(got[i], tc.Data) {
+				t.Errorf("%s/%s: delimited message differs from the corpus bytes", c.Name, tc.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package testcases_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"compat/testcases"
+)
+
+// FuzzReadTestCases seeds with every generated corpus, whole, compressed,
+// cut short and without its header, and checks that anything ReadTestCases
+// accepts frames back to the same bytes.
+func FuzzReadTestCases(f *testing.F) {
+	for _, c := range testcases.Categories() {
+		data, _, err := testcases.BuildCorpus(c)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+		f.Add(data[:len(data)/2])
+		f.Add(data[8:])
+		if gz, err := testcases.Compress(data); err == nil {
+			f.Add(gz)
+		}
+	}
+	f.Add([]byte{})
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		cases, err := testcases.ReadTestCases(data)
+		if err != nil {
+			var trunc *testcases.ErrTruncatedCorpus
+			var format *testcases.ErrCorpusFormat
+			if !errors.As(err, &trunc) && !errors.As(err, &format) && !testcases.IsCompressed(data) {
+				t.Fatalf("undocumented error: %v", err)
+			}
+			return
+		}
+		if testcases.IsCompressed(data) {
+			if data, err = testcases.Decompress(data); err != nil {
+				t.Fatalf("ReadTestCases accepted data Decompress rejects: %v", err)
+			}
+		}
+		var buf bytes.Buffer
+		if len(data) > 0 && data[0] == 0xff {
+			// A legacy file cannot start with 0xff, the first byte of
+			// the header's magic.
+			if data[5] != 0 {
+				return // indexed; the index is not re-framed
+			}
+			testcases.WriteCorpusHeader(&buf)
+		}
+		for _, tc := range cases {
+			testcases.WriteTestCaseRaw(&buf, tc.Name, tc.Data)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Fatalf("%d cases re-framed to %x, read from %x", len(cases), buf.Bytes(), data)
+		}
+	})
+}
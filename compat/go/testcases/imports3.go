@@ -0,0 +1,73 @@
+package testcases
+
+import (
+	"fmt"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("imports3", GenerateImports3, validateImports3)
+}
+
+func GenerateImports3() []TestCase {
+	return []TestCase{
+		{
+			Name: "empty",
+			Msg:  &pb.ImportsMessage{},
+		},
+		{
+			Name: "all_set",
+			Msg: &pb.ImportsMessage{
+				Origin: &pb.Point{X: 1, Y: -2},
+				Level:  pb.Level_LEVEL_HIGH,
+				Tagged: &pb.Tagged{
+					Tag:   "checkpoint",
+					Point: &pb.Point{X: 30, Y: 40},
+					Level: pb.Level_LEVEL_LOW,
+				},
+			},
+		},
+		{
+			Name: "path",
+			Msg: &pb.ImportsMessage{
+				Path: []*pb.Point{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: -5, Y: 7}},
+			},
+		},
+	}
+}
+
+func validateImports3(tc RawTestCase) int {
+	msg := &pb.ImportsMessage{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "empty":
+		failures += check(tc.Name, "origin", msg.Origin == nil)
+		failures += check(tc.Name, "level", msg.Level == pb.Level_LEVEL_UNSPECIFIED)
+		failures += check(tc.Name, "tagged", msg.Tagged == nil)
+		failures += check(tc.Name, "path.len", len(msg.Path) == 0)
+	case "all_set":
+		failures += check(tc.Name, "origin.x", msg.GetOrigin().GetX() == 1)
+		failures += check(tc.Name, "origin.y", msg.GetOrigin().GetY() == -2)
+		failures += check(tc.Name, "level", msg.Level == pb.Level_LEVEL_HIGH)
+		failures += check(tc.Name, "tagged.tag", msg.GetTagged().GetTag() == "checkpoint")
+		failures += check(tc.Name, "tagged.point.x", msg.GetTagged().GetPoint().GetX() == 30)
+		failures += check(tc.Name, "tagged.point.y", msg.GetTagged().GetPoint().GetY() == 40)
+		failures += check(tc.Name, "tagged.level", msg.GetTagged().GetLevel() == pb.Level_LEVEL_LOW)
+	case "path":
+		failures += check(tc.Name, "path.len", len(msg.Path) == 3)
+		if len(msg.Path) == 3 {
+			failures += check(tc.Name, "path[1].x", msg.Path[1].X == 1)
+			failures += check(tc.Name, "path[2].x", msg.Path[2].X == -5)
+			failures += check(tc.Name, "path[2].y", msg.Path[2].Y == 7)
+		}
+	}
+	return failures
+}
@@ -0,0 +1,116 @@
+package testcases
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// JSONDir is the corpus subdirectory holding the proto3 JSON form of the
+// categories registered with RegisterJSON, framed like the binary corpus.
+const JSONDir = "json"
+
+var jsonCategories = map[string]bool{}
+
+// RegisterJSON marks an already registered category as also having a JSON
+// corpus. Categories opt in because some binary expectations, such as NaN
+// payloads or unknown fields, do not survive JSON.
+func RegisterJSON(name string) {
+	if _, ok := registry[name]; !ok {
+		panic(fmt.Sprintf("testcases: RegisterJSON(%q) before Register", name))
+	}
+	jsonCategories[name] = true
+}
+
+// HasJSON reports whether the category has a JSON corpus.
+func HasJSON(name string) bool {
+	return jsonCategories[name]
+}
+
+// BuildJSONCorpus frames the proto3 JSON encoding of every case of c. The
+// JSON is compacted, since protojson varies its whitespace between runs.
+func BuildJSONCorpus(c Category) ([]byte, int, error) {
+	var buf bytes.Buffer
+	if err := WriteCorpusHeader(&buf); err != nil {
+		return nil, 0, err
+	}
+	cases := c.Generate()
+	for _, tc := range cases {
+		data, err := protojson.Marshal(tc.Msg)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s/%s: json: %w", c.Name, tc.Name, err)
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, data); err != nil {
+			return nil, 0, fmt.Errorf("%s/%s: json: %w", c.Name, tc.Name, err)
+		}
+		if err := WriteTestCaseRaw(&buf, tc.Name, compact.Bytes()); err != nil {
+			return nil, 0, err
+		}
+	}
+	return buf.Bytes(), len(cases), nil
+}
+
+// ValidateJSON checks one case of a JSON corpus: every object key must be
+// the field's JSON name, which printers emit by default, and the message it
+// parses to must pass the category's validator.
+func ValidateJSON(c Category, tc RawTestCase) int {
+	want, ok := Expected(c.Name + "/" + tc.Name)
+	if !ok {
+		return 0
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(tc.Data, &obj); err != nil {
+		fmt.Printf("  FAIL %s: json: %v\n", tc.Name, err)
+		return 1
+	}
+	failures := checkJSONNames(tc.Name, want.ProtoReflect().Descriptor(), obj)
+
+	msg := want.ProtoReflect().New().Interface()
+	if err := protojson.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: protojson: %v\n", tc.Name, err)
+		return failures + 1
+	}
+	data, err := pbutil.Marshal(msg)
+	if err != nil {
+		fmt.Printf("  FAIL %s: marshal: %v\n", tc.Name, err)
+		return failures + 1
+	}
+	return failures + c.Validate(RawTestCase{Name: tc.Name, Data: data})
+}
+
+// checkJSONNames fails each key of obj, and of the messages nested in it,
+// that is not a JSON name of md. Parsers also accept the proto field name,
+// so this is the only check that json_name was honored.
+func checkJSONNames(name string, md protoreflect.MessageDescriptor, obj map[string]any) int {
+	failures := 0
+	for key, v := range obj {
+		fd := md.Fields().ByJSONName(key)
+		if fd == nil {
+			fmt.Printf("  FAIL %s: key %q is not a JSON name of %s\n", name, key, md.FullName())
+			failures++
+			// Keep checking below a proto-named key.
+			if fd = md.Fields().ByName(protoreflect.Name(key)); fd == nil {
+				continue
+			}
+		}
+		if fd.Message() == nil || fd.IsMap() {
+			continue
+		}
+		elems, ok := v.([]any)
+		if !ok {
+			elems = []any{v}
+		}
+		for _, e := range elems {
+			if sub, ok := e.(map[string]any); ok {
+				failures += checkJSONNames(name, fd.Message(), sub)
+			}
+		}
+	}
+	return failures
+}
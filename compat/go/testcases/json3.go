@@ -0,0 +1,74 @@
+package testcases
+
+import (
+	"fmt"
+	"slices"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("json3", GenerateJSON3, validateJSON3)
+	RegisterJSON("json3")
+}
+
+func GenerateJSON3() []TestCase {
+	return []TestCase{
+		{
+			Name: "empty",
+			Msg:  &pb.JsonNames{},
+		},
+		{
+			Name: "all_set",
+			Msg: &pb.JsonNames{
+				Plain:              1,
+				XLeadingUnderscore: "underscore",
+				Field_1Digit:       2,
+				ALLCAPS:            "caps",
+				Mixed_CaseField:    3,
+				Renamed:            "renamed",
+				ListOfValues:       []int32{4, 5, 6},
+			},
+		},
+		{
+			Name: "nested",
+			Msg: &pb.JsonNames{
+				Plain: 10,
+				Child: &pb.JsonNames{
+					Plain:   11,
+					Renamed: "inner",
+				},
+			},
+		},
+	}
+}
+
+func validateJSON3(tc RawTestCase) int {
+	msg := &pb.JsonNames{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "empty":
+		failures += check(tc.Name, "plain", msg.Plain == 0)
+		failures += check(tc.Name, "child", msg.Child == nil)
+	case "all_set":
+		failures += check(tc.Name, "plain", msg.Plain == 1)
+		failures += check(tc.Name, "_leading_underscore", msg.XLeadingUnderscore == "underscore")
+		failures += check(tc.Name, "field_1_digit", msg.Field_1Digit == 2)
+		failures += check(tc.Name, "ALLCAPS", msg.ALLCAPS == "caps")
+		failures += check(tc.Name, "mixed_Case_field", msg.Mixed_CaseField == 3)
+		failures += check(tc.Name, "renamed", msg.Renamed == "renamed")
+		failures += check(tc.Name, "list_of_values", slices.Equal(msg.ListOfValues, []int32{4, 5, 6}))
+	case "nested":
+		failures += check(tc.Name, "plain", msg.Plain == 10)
+		failures += check(tc.Name, "child.plain", msg.GetChild().GetPlain() == 11)
+		failures += check(tc.Name, "child.renamed", msg.GetChild().GetRenamed() == "inner")
+	}
+	return failures
+}
@@ -0,0 +1,88 @@
+package testcases_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"compat/pbutil"
+	"compat/testcases"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestValidateJSON(t *testing.T) {
+	c, _ := testcases.Lookup("json3")
+	data, _, err := testcases.BuildJSONCorpus(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases, err := testcases.ReadTestCases(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range cases {
+		if n := testcases.ValidateJSON(c, tc); n != 0 {
+			t.Errorf("%s: %d failures on the generated JSON", tc.Name, n)
+		}
+	}
+
+	// Proto field names parse, but do not honor json_name.
+	want, _ := testcases.Expected("json3/nested")
+	protoNames, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := testcases.ValidateJSON(c, testcases.RawTestCase{Name: "nested", Data: protoNames}); n != 4 {
+		t.Errorf("proto-named JSON: got %d failures, want 4 (plain, child, child.plain, child.renamed)", n)
+	}
+}
+
+func TestCaseJSON(t *testing.T) {
+	want, _ := testcases.Expected("json3/nested")
+	data, err := pbutil.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mt := want.ProtoReflect().Type()
+	for _, tc := range []struct {
+		name    string
+		data    []byte
+		mt      bool
+		keepHex bool
+	}{
+		{"canonical", data, true, false},
+		{"unknown_fields", append(data, pbutil.UnknownFields()...), true, true},
+		{"no_type", data, false, true},
+		{"not_a_message", []byte{0xff}, true, true},
+		{"empty_no_type", []byte{}, false, false},
+	} {
+		in := testcases.RawTestCase{Name: tc.name, Data: tc.data}
+		var js []byte
+		if tc.mt {
+			js, err = testcases.MarshalCaseJSON(in, mt)
+		} else {
+			js, err = testcases.MarshalCaseJSON(in, nil)
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got := strings.Contains(string(js), `"hex"`); got != tc.keepHex {
+			t.Errorf("%s: kept hex %v, want %v:\n%s", tc.name, got, tc.keepHex, js)
+		}
+		out, err := testcases.UnmarshalCaseJSON(js)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if out.Name != tc.name || !bytes.Equal(out.Data, tc.data) {
+			t.Errorf("%s: read back %s %x, want %x", tc.name, out.Name, out.Data, tc.data)
+		}
+	}
+
+	// A message shown beside hex must not be edited on its own.
+	js, _ := testcases.MarshalCaseJSON(testcases.RawTestCase{Name: "unknown_fields", Data: append(data, pbutil.UnknownFields()...)}, mt)
+	edited := bytes.Replace(js, []byte(`"customName": 10`), []byte(`"customName": 12`), 1)
+	if _, err := testcases.UnmarshalCaseJSON(edited); err == nil {
+		t.Errorf("edited message beside hex was accepted:\n%s", edited)
+	}
+}
@@ -0,0 +1,90 @@
+package testcases
+
+import (
+	"bytes"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Limits are the decoder resource limits the over-limit vectors are built
+// around. cmd/generate records them in the manifest, so that a reader can
+// configure its decoder from the corpus instead of hard-coding them.
+type Limits struct {
+	// MaxDepth is the number of nested message levels, counting the
+	// top-level message, a decoder must accept. Deeper nesting must fail.
+	MaxDepth int `json:"max_depth"`
+	// MaxMessageBytes is the size of the largest encoded message a decoder
+	// must accept. Larger input must fail before it is decoded.
+	MaxMessageBytes int `json:"max_message_bytes"`
+}
+
+// DefaultLimits matches the Zig runtime's default_max_decode_depth. The
+// size limit is far below what any decoder needs, to keep the vectors that
+// cross it small.
+var DefaultLimits = Limits{MaxDepth: 100, MaxMessageBytes: 64 << 10}
+
+// LimitsDir is the corpus subdirectory holding, per category, wire data
+// that is well-formed but exceeds DefaultLimits. A decoder enforcing the
+// limits must fail on every vector there, without crashing or leaking; one
+// given higher limits would decode them. Like the reject corpus, the Zig
+// side only reads these.
+const LimitsDir = "limits"
+
+var overLimitCategories = map[string]func() []RawTestCase{}
+
+// RegisterOverLimit adds over-limit vectors to an already registered
+// category.
+func RegisterOverLimit(name string, gen func() []RawTestCase) {
+	if _, ok := registry[name]; !ok {
+		panic(fmt.Sprintf("testcases: RegisterOverLimit(%q) before Register", name))
+	}
+	overLimitCategories[name] = gen
+}
+
+// OverLimit returns the category's over-limit vectors, or nil if it has
+// none.
+func OverLimit(name string) []RawTestCase {
+	if gen, ok := overLimitCategories[name]; ok {
+		return gen()
+	}
+	return nil
+}
+
+// BuildOverLimitCorpus frames every over-limit vector of the category.
+func BuildOverLimitCorpus(name string) ([]byte, int, error) {
+	var buf bytes.Buffer
+	if err := WriteCorpusHeader(&buf); err != nil {
+		return nil, 0, err
+	}
+	cases := OverLimit(name)
+	for _, tc := range cases {
+		if err := WriteTestCaseRaw(&buf, tc.Name, tc.Data); err != nil {
+			return nil, 0, err
+		}
+	}
+	return buf.Bytes(), len(cases), nil
+}
+
+// DecodeWithin decodes data into m, failing if it breaks l.
+func DecodeWithin(l Limits, data []byte, m proto.Message) error {
+	if len(data) > l.MaxMessageBytes {
+		return fmt.Errorf("%d bytes exceeds the %d byte limit", len(data), l.MaxMessageBytes)
+	}
+	return proto.UnmarshalOptions{RecursionLimit: l.MaxDepth}.Unmarshal(data, m)
+}
+
+// CheckOverLimit decodes tc as the category's type and fails unless it
+// breaks l but decodes once the limits are lifted, so that a vector meant
+// to cross a limit crosses only that.
+func CheckOverLimit(c Category, tc RawTestCase, l Limits) int {
+	cases := c.Generate()
+	if len(cases) == 0 {
+		return 0
+	}
+	msg := cases[0].Msg.ProtoReflect().New().Interface()
+	failures := check(tc.Name, "over_limit", DecodeWithin(l, tc.Data, msg) != nil)
+	lifted := Limits{MaxDepth: 10 * l.MaxDepth, MaxMessageBytes: 10 * l.MaxMessageBytes}
+	failures += check(tc.Name, "within_lifted_limits", DecodeWithin(lifted, tc.Data, msg) == nil)
+	return failures
+}
@@ -0,0 +1,126 @@
+package testcases
+
+import (
+	"fmt"
+
+	"compat/pb"
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func init() {
+	Register("limits3", GenerateLimits3, validateLimits3)
+	RegisterRejects("limits3", limitRejectCases)
+	RegisterOverLimit("limits3", limitOverCases)
+}
+
+// limitManyCount is how many empty submessages and byte strings the many_
+// cases hold: two bytes each on the wire, but an allocation each to decode.
+const limitManyCount = 20000
+
+// limitChain nests levels LimitNodes, counting the outermost, through child
+// or, with repeated, through a single entry of children.
+func limitChain(levels int, repeated bool) *pb.LimitNode {
+	m := &pb.LimitNode{}
+	for i := 1; i < levels; i++ {
+		if repeated {
+			m = &pb.LimitNode{Children: []*pb.LimitNode{m}}
+		} else {
+			m = &pb.LimitNode{Child: m}
+		}
+	}
+	return m
+}
+
+// limitDepth counts the levels of a chain built by limitChain.
+func limitDepth(m *pb.LimitNode) int {
+	n := 0
+	for m != nil {
+		n++
+		if len(m.Children) == 1 {
+			m = m.Children[0]
+		} else {
+			m = m.Child
+		}
+	}
+	return n
+}
+
+// limitBlob returns a LimitNode holding one blob, sized so that the message
+// encodes to exactly size bytes.
+func limitBlob(size int) *pb.LimitNode {
+	n := size - 1
+	for n+1+protowire.SizeVarint(uint64(n)) > size {
+		n--
+	}
+	return &pb.LimitNode{Blobs: [][]byte{make([]byte, n)}}
+}
+
+// GenerateLimits3 returns vectors at DefaultLimits, which a decoder must
+// accept however it is configured.
+func GenerateLimits3() []TestCase {
+	children := make([]*pb.LimitNode, limitManyCount)
+	for i := range children {
+		children[i] = &pb.LimitNode{}
+	}
+	return []TestCase{
+		{Name: "depth_at_limit", Msg: limitChain(DefaultLimits.MaxDepth, false)},
+		{Name: "depth_at_limit_repeated", Msg: limitChain(DefaultLimits.MaxDepth, true)},
+		{Name: "size_at_limit", Msg: limitBlob(DefaultLimits.MaxMessageBytes)},
+		{Name: "many_empty_children", Msg: &pb.LimitNode{Children: children}},
+		{Name: "many_empty_blobs", Msg: &pb.LimitNode{Blobs: make([][]byte, limitManyCount)}},
+	}
+}
+
+// limitOverCases cross each limit by one: a level too deep, or a byte too
+// long.
+func limitOverCases() []RawTestCase {
+	return []RawTestCase{
+		{Name: "depth_over_limit", Data: pbutil.MustMarshal(limitChain(DefaultLimits.MaxDepth+1, false))},
+		{Name: "depth_over_limit_repeated", Data: pbutil.MustMarshal(limitChain(DefaultLimits.MaxDepth+1, true))},
+		{Name: "size_over_limit", Data: pbutil.MustMarshal(limitBlob(DefaultLimits.MaxMessageBytes + 1))},
+	}
+}
+
+// limitRejectCases declare lengths far beyond the data that follows. A
+// decoder must fail on them without allocating what they declare, and
+// without overflow when adding the length to its position.
+func limitRejectCases() []RejectCase {
+	lengthOnly := func(num protowire.Number, n uint64, body ...byte) []byte {
+		return append(protowire.AppendVarint(protowire.AppendTag(nil, num, protowire.BytesType), n), body...)
+	}
+	// The inner blob claims more than its enclosing child holds, though the
+	// input as a whole has enough bytes.
+	inner := lengthOnly(3, 100, 'x')
+	nested := append(lengthOnly(1, uint64(len(inner)), inner...), make([]byte, 200)...)
+	return []RejectCase{
+		{Name: "blob_length_overflow", Data: lengthOnly(3, 1<<31-1, 'a', 'b', 'c'), Error: ErrTruncated},
+		{Name: "blob_length_max_varint", Data: lengthOnly(3, 1<<64-1, 'a'), Error: ErrTruncated},
+		{Name: "child_length_overflow", Data: lengthOnly(2, 1<<30, 0x12, 0x00), Error: ErrTruncated},
+		{Name: "packed_length_overflow", Data: lengthOnly(4, 1<<28, 1, 2, 3), Error: ErrTruncated},
+		{Name: "nested_length_overflow", Data: nested, Error: ErrTruncated},
+	}
+}
+
+func validateLimits3(tc RawTestCase) int {
+	msg := &pb.LimitNode{}
+	if err := DecodeWithin(DefaultLimits, tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal within limits: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "depth_at_limit", "depth_at_limit_repeated":
+		failures += check(tc.Name, "depth", limitDepth(msg) == DefaultLimits.MaxDepth)
+	case "size_at_limit":
+		failures += check(tc.Name, "size", len(tc.Data) == DefaultLimits.MaxMessageBytes)
+		failures += check(tc.Name, "blobs", len(msg.Blobs) == 1)
+	case "many_empty_children":
+		failures += check(tc.Name, "children", len(msg.Children) == limitManyCount)
+	case "many_empty_blobs":
+		failures += check(tc.Name, "blobs", len(msg.Blobs) == limitManyCount)
+	}
+	return failures
+}
@@ -0,0 +1,189 @@
+package testcases
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ManifestName is the file name of the corpus manifest within a testdata directory.
+const ManifestName = "manifest.json"
+
+// Manifest describes every corpus file written to a testdata directory.
+type Manifest struct {
+	// Profile names the generation profile that wrote the directory, if
+	// known; see Profiles.
+	Profile string `json:"profile,omitempty"`
+	// Limits are the decoder limits the over-limit vectors in LimitsDir
+	// cross.
+	Limits *Limits        `json:"limits,omitempty"`
+	Files  []ManifestFile `json:"files"`
+}
+
+// ManifestFile records the expected shape of a single corpus file.
+type ManifestFile struct {
+	Name   string         `json:"name"`
+	Path   string         `json:"path"`
+	Size   int            `json:"size"`
+	SHA256 string         `json:"sha256"`
+	Cases  []ManifestCase `json:"cases"`
+}
+
+// ManifestCase records the name, encoded size and tags of a single test
+// case. Tags are not in the corpus file, so Verify does not check them.
+type ManifestCase struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+	Tags []Tag  `json:"tags,omitempty"`
+}
+
+// NewManifestFile builds a manifest entry for the framed corpus data stored at path.
+func NewManifestFile(name, path string, data []byte) (ManifestFile, error) {
+	cases, err := ReadTestCases(data)
+	if err != nil {
+		return ManifestFile{}, fmt.Errorf("%s: %w", name, err)
+	}
+	sum := sha256.Sum256(data)
+	mf := ManifestFile{
+		Name:   name,
+		Path:   path,
+		Size:   len(data),
+		SHA256: hex.EncodeToString(sum[:]),
+		Cases:  make([]ManifestCase, 0, len(cases)),
+	}
+	for _, tc := range cases {
+		mf.Cases = append(mf.Cases, ManifestCase{Name: tc.Name, Size: len(tc.Data)})
+	}
+	return mf, nil
+}
+
+// WriteCorpusFile streams the category's corpus to dir/file, gzipped as by
+// Compress if the name ends in .gz, and returns its manifest entry. Unlike
+// writing the result of BuildCorpus it never holds the whole corpus in
+// memory, which the stress profile's corpora would not fit. With indexed,
+// the file is written in the versioned format with a case index; see
+// FindTestCase.
+func WriteCorpusFile(dir, file string, c Category, indexed bool) (mf ManifestFile, err error) {
+	f, err := os.Create(filepath.Join(dir, file))
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	sum := sha256.New()
+	counted := &countingWriter{w: io.MultiWriter(f, sum)}
+	out := io.Writer(counted)
+	var zw *gzip.Writer
+	if strings.HasSuffix(file, ".gz") {
+		if zw, err = gzip.NewWriterLevel(counted, gzip.BestCompression); err != nil {
+			return ManifestFile{}, err
+		}
+		out = zw
+	}
+	w := bufio.NewWriterSize(out, 64<<10)
+	write := func() ([]ManifestCase, error) { return WriteCorpus(w, c) }
+	var cases []ManifestCase
+	if indexed {
+		cases, err = writeIndexed(w, write)
+	} else if err = WriteCorpusHeader(w); err == nil {
+		cases, err = write()
+	}
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	if err := w.Flush(); err != nil {
+		return ManifestFile{}, err
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return ManifestFile{}, err
+		}
+	}
+	return ManifestFile{
+		Name:   c.Name,
+		Path:   file,
+		Size:   int(counted.n),
+		SHA256: hex.EncodeToString(sum.Sum(nil)),
+		Cases:  cases,
+	}, nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// WriteManifest writes the manifest as indented JSON to dir/manifest.json.
+func WriteManifest(dir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	return os.WriteFile(filepath.Join(dir, ManifestName), data, 0o644)
+}
+
+// ReadManifest reads dir/manifest.json.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", ManifestName, err)
+	}
+	return m, nil
+}
+
+// Verify checks every file listed in the manifest against the contents of dir
+// and returns one error per mismatch (missing file, size, hash, or case list).
+func (m *Manifest) Verify(dir string) []error {
+	var errs []error
+	for _, mf := range m.Files {
+		data, err := os.ReadFile(filepath.Join(dir, mf.Path))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", mf.Name, err))
+			continue
+		}
+		got, err := NewManifestFile(mf.Name, mf.Path, data)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if got.Size != mf.Size {
+			errs = append(errs, fmt.Errorf("%s: size %d, manifest says %d (truncated or stale corpus)", mf.Name, got.Size, mf.Size))
+		}
+		if got.SHA256 != mf.SHA256 {
+			errs = append(errs, fmt.Errorf("%s: sha256 %s, manifest says %s", mf.Name, got.SHA256, mf.SHA256))
+		}
+		if len(got.Cases) != len(mf.Cases) {
+			errs = append(errs, fmt.Errorf("%s: %d cases, manifest says %d", mf.Name, len(got.Cases), len(mf.Cases)))
+			continue
+		}
+		for i, c := range mf.Cases {
+			if got.Cases[i].Name != c.Name || got.Cases[i].Size != c.Size {
+				errs = append(errs, fmt.Errorf("%s: case %d is %s (%d bytes), manifest says %s (%d bytes)",
+					mf.Name, i, got.Cases[i].Name, got.Cases[i].Size, c.Name, c.Size))
+			}
+		}
+	}
+	return errs
+}
@@ -1,9 +1,100 @@
 package testcases
 
-import "compat/pb"
+import (
+	"fmt"
+
+	"compat/pb"
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("map3", GenerateMap3, validateMap3)
+}
+
+// mapEntryOrders are messages whose map entries have their key and value
+// fields out of order, missing, repeated or among unknown fields. A map
+// entry is a message like any other: its fields may come in any order, the
+// last of a repeated one wins, a missing one is its zero value and unknown
+// ones are skipped.
+var mapEntryOrders = []struct {
+	name    string
+	entries [][]byte
+	want    *pb.MapMessage
+}{
+	{"entry_value_first_str_str", [][]byte{mapEntry(1, entryString(2, "val"), entryString(1, "key"))},
+		&pb.MapMessage{StrStr: map[string]string{"key": "val"}}},
+	{"entry_value_first_int_str", [][]byte{mapEntry(2, entryString(2, "one"), entryVarint(1, 1))},
+		&pb.MapMessage{IntStr: map[int32]string{1: "one"}}},
+	{"entry_value_first_str_msg", [][]byte{mapEntry(3, entryMsg(2, &pb.MapSubMsg{Id: 1, Text: "alpha"}), entryString(1, "a"))},
+		&pb.MapMessage{StrMsg: map[string]*pb.MapSubMsg{"a": {Id: 1, Text: "alpha"}}}},
+	{"entry_unknown_before", [][]byte{mapEntry(1, entryVarint(3, 7), entryString(1, "key"), entryString(2, "val"))},
+		&pb.MapMessage{StrStr: map[string]string{"key": "val"}}},
+	{"entry_unknown_between", [][]byte{mapEntry(2, entryVarint(1, 5), entryFixed32(15, 0xdeadbeef), entryString(2, "five"))},
+		&pb.MapMessage{IntStr: map[int32]string{5: "five"}}},
+	{"entry_unknown_after", [][]byte{mapEntry(1, entryString(1, "key"), entryString(2, "val"), entryString(4, "x"), entryFixed64(5, 1))},
+		&pb.MapMessage{StrStr: map[string]string{"key": "val"}}},
+	{"entry_unknown_group", [][]byte{mapEntry(1, entryString(1, "key"), entryGroup(6, entryVarint(1, 1), entryString(2, "in group")), entryString(2, "val"))},
+		&pb.MapMessage{StrStr: map[string]string{"key": "val"}}},
+	{"entry_unknown_value_first_str_msg", [][]byte{mapEntry(3, entryVarint(9, 1), entryMsg(2, &pb.MapSubMsg{Id: 2}), entryString(1000, "x"), entryString(1, "b"))},
+		&pb.MapMessage{StrMsg: map[string]*pb.MapSubMsg{"b": {Id: 2}}}},
+	{"entry_key_only", [][]byte{mapEntry(2, entryVarint(1, 5))},
+		&pb.MapMessage{IntStr: map[int32]string{5: ""}}},
+	{"entry_value_only", [][]byte{mapEntry(1, entryString(2, "v"))},
+		&pb.MapMessage{StrStr: map[string]string{"": "v"}}},
+	{"entry_empty", [][]byte{mapEntry(3)},
+		&pb.MapMessage{StrMsg: map[string]*pb.MapSubMsg{"": {}}}},
+	{"entry_fields_repeated", [][]byte{mapEntry(1, entryString(1, "a"), entryString(2, "1"), entryString(2, "2"), entryString(1, "b"))},
+		&pb.MapMessage{StrStr: map[string]string{"b": "2"}}},
+	{"entry_mixed", [][]byte{
+		mapEntry(1, entryString(1, "a"), entryString(2, "1")),
+		mapEntry(1, entryString(2, "2"), entryString(1, "b")),
+		mapEntry(2, entryVarint(7, 0), entryString(2, "three"), entryVarint(1, 3)),
+		mapEntry(1, entryString(3, "?"), entryString(1, "c"), entryString(2, "3")),
+	}, &pb.MapMessage{StrStr: map[string]string{"a": "1", "b": "2", "c": "3"}, IntStr: map[int32]string{3: "three"}}},
+}
+
+// mapEntry encodes fields as an entry of the map field num.
+func mapEntry(num protowire.Number, fields ...[]byte) []byte {
+	var body []byte
+	for _, f := range fields {
+		body = append(body, f...)
+	}
+	return protowire.AppendBytes(protowire.AppendTag(nil, num, protowire.BytesType), body)
+}
+
+func entryString(num protowire.Number, v string) []byte {
+	return protowire.AppendString(protowire.AppendTag(nil, num, protowire.BytesType), v)
+}
+
+func entryVarint(num protowire.Number, v uint64) []byte {
+	return protowire.AppendVarint(protowire.AppendTag(nil, num, protowire.VarintType), v)
+}
+
+func entryFixed32(num protowire.Number, v uint32) []byte {
+	return protowire.AppendFixed32(protowire.AppendTag(nil, num, protowire.Fixed32Type), v)
+}
+
+func entryFixed64(num protowire.Number, v uint64) []byte {
+	return protowire.AppendFixed64(protowire.AppendTag(nil, num, protowire.Fixed64Type), v)
+}
+
+func entryMsg(num protowire.Number, m proto.Message) []byte {
+	return protowire.AppendBytes(protowire.AppendTag(nil, num, protowire.BytesType), pbutil.MustMarshal(m))
+}
+
+func entryGroup(num protowire.Number, fields ...[]byte) []byte {
+	b := protowire.AppendTag(nil, num, protowire.StartGroupType)
+	for _, f := range fields {
+		b = append(b, f...)
+	}
+	return protowire.AppendTag(b, num, protowire.EndGroupType)
+}
 
 func GenerateMap3() []TestCase {
-	return []TestCase{
+	cases := []TestCase{
 		{
 			Name: "empty",
 			Msg:  &pb.MapMessage{},
@@ -30,4 +121,65 @@ func GenerateMap3() []TestCase {
 			},
 		},
 	}
+	for _, o := range mapEntryOrders {
+		var wire []byte
+		for _, e := range o.entries {
+			wire = append(wire, e...)
+		}
+		cases = append(cases, TestCase{Name: o.name, Msg: o.want, Wire: wire})
+	}
+	return cases
+}
+
+func validateMap3(tc RawTestCase) int {
+	msg := &pb.MapMessage{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "empty":
+		failures += check(tc.Name, "str_str.len", len(msg.StrStr) == 0)
+		failures += check(tc.Name, "int_str.len", len(msg.IntStr) == 0)
+		failures += check(tc.Name, "str_msg.len", len(msg.StrMsg) == 0)
+	case "single":
+		failures += check(tc.Name, "str_str.len", len(msg.StrStr) == 1)
+		failures += check(tc.Name, "str_str[key]", msg.StrStr["key"] == "val")
+		failures += check(tc.Name, "int_str.len", len(msg.IntStr) == 1)
+		failures += check(tc.Name, "int_str[1]", msg.IntStr[1] == "one")
+		failures += check(tc.Name, "str_msg.len", len(msg.StrMsg) == 1)
+		if sub, ok := msg.StrMsg["a"]; ok {
+			failures += check(tc.Name, "str_msg[a].id", sub.Id == 1)
+			failures += check(tc.Name, "str_msg[a].text", sub.Text == "alpha")
+		} else {
+			failures += check(tc.Name, "str_msg[a]", false)
+		}
+	case "multiple":
+		failures += check(tc.Name, "str_str.len", len(msg.StrStr) == 2)
+		failures += check(tc.Name, "str_str[a]", msg.StrStr["a"] == "1")
+		failures += check(tc.Name, "str_str[b]", msg.StrStr["b"] == "2")
+		failures += check(tc.Name, "int_str.len", len(msg.IntStr) == 2)
+		failures += check(tc.Name, "int_str[1]", msg.IntStr[1] == "one")
+		failures += check(tc.Name, "int_str[2]", msg.IntStr[2] == "two")
+		failures += check(tc.Name, "str_msg.len", len(msg.StrMsg) == 2)
+		if sub, ok := msg.StrMsg["x"]; ok {
+			failures += check(tc.Name, "str_msg[x].id", sub.Id == 10)
+			failures += check(tc.Name, "str_msg[x].text", sub.Text == "x")
+		} else {
+			failures += check(tc.Name, "str_msg[x]", false)
+		}
+		if sub, ok := msg.StrMsg["y"]; ok {
+			failures += check(tc.Name, "str_msg[y].id", sub.Id == 20)
+			failures += check(tc.Name, "str_msg[y].text", sub.Text == "y")
+		} else {
+			failures += check(tc.Name, "str_msg[y]", false)
+		}
+	default:
+		if expected, ok := Expected("map3/" + tc.Name); ok {
+			failures += checkMapFields(tc.Name, "", expected.ProtoReflect(), msg.ProtoReflect())
+		}
+	}
+	return failures
 }
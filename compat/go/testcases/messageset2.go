@@ -0,0 +1,99 @@
+package testcases
+
+import (
+	"bytes"
+	"fmt"
+
+	"compat/pb"
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("messageset2", GenerateMessageSet2, validateMessageSet2)
+	RegisterTags("messageset2", TagProto2)
+}
+
+// MessageSet wire format: each extension is a group at field 1 holding the
+// extension's field number as type_id and its encoded message.
+const (
+	messageSetItem    protowire.Number = 1
+	messageSetTypeID  protowire.Number = 2
+	messageSetMessage protowire.Number = 3
+)
+
+// Neither the Go runtime (without the protolegacy tag) nor the Zig generator
+// understands MessageSet, so the vectors are hand-encoded and kept as unknown
+// fields: of MessageSetCarrier in Go and of MessageSetContainer in Zig.
+// Re-encoding must reproduce them exactly.
+var messageSetCases = []struct {
+	name string
+	raw  []byte
+}{
+	{"empty", nil},
+	{"item_a", messageSetItemA()},
+	{"item_b", messageSetItemB()},
+	{"both", append(messageSetItemA(), messageSetItemB()...)},
+	// Readers must pass through items whose type_id they do not know.
+	{"unknown_type_id", messageSetEntry(3000, unknownField(1, protowire.VarintType, []byte{7}))},
+	// type_id conventionally comes first, but the order is not fixed.
+	{"message_first", messageSetEntryReversed(1000, pbutil.MustMarshal(&pb.MessageSetItemA{Value: proto.Int32(-1)}))},
+}
+
+func messageSetItemA() []byte {
+	return messageSetEntry(1000, pbutil.MustMarshal(&pb.MessageSetItemA{Value: proto.Int32(42)}))
+}
+
+func messageSetItemB() []byte {
+	return messageSetEntry(2000, pbutil.MustMarshal(&pb.MessageSetItemB{Text: proto.String("hello")}))
+}
+
+func messageSetEntry(typeID uint64, msg []byte) []byte {
+	body := unknownField(messageSetTypeID, protowire.VarintType, protowire.AppendVarint(nil, typeID))
+	body = append(body, unknownField(messageSetMessage, protowire.BytesType, protowire.AppendBytes(nil, msg))...)
+	return unknownGroup(messageSetItem, body)
+}
+
+func messageSetEntryReversed(typeID uint64, msg []byte) []byte {
+	body := unknownField(messageSetMessage, protowire.BytesType, protowire.AppendBytes(nil, msg))
+	body = append(body, unknownField(messageSetTypeID, protowire.VarintType, protowire.AppendVarint(nil, typeID))...)
+	return unknownGroup(messageSetItem, body)
+}
+
+func messageSetRaw(name string) ([]byte, bool) {
+	for _, c := range messageSetCases {
+		if c.name == name {
+			return c.raw, true
+		}
+	}
+	return nil, false
+}
+
+func GenerateMessageSet2() []TestCase {
+	var cases []TestCase
+	for _, c := range messageSetCases {
+		m := &pb.MessageSetCarrier{}
+		if c.raw != nil {
+			m.ProtoReflect().SetUnknown(c.raw)
+		}
+		cases = append(cases, TestCase{Name: c.name, Msg: m})
+	}
+	return cases
+}
+
+func validateMessageSet2(tc RawTestCase) int {
+	msg := &pb.MessageSetCarrier{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	want, ok := messageSetRaw(tc.Name)
+	if !ok {
+		return 0
+	}
+
+	return check(tc.Name, "unknown", bytes.Equal(msg.ProtoReflect().GetUnknown(), want))
+}
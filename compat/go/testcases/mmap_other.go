@@ -0,0 +1,13 @@
+//go:build !unix
+
+package testcases
+
+import (
+	"errors"
+	"os"
+)
+
+// mmapFile always fails here; OpenCorpus reads the file instead.
+func mmapFile(f *os.File, size int64) ([]byte, func() error, error) {
+	return nil, nil, errors.New("mmap not supported")
+}
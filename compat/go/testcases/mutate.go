@@ -0,0 +1,140 @@
+package testcases
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Mutant names start with one of these tags. A must-decode mutant is still a
+// valid encoding of the case's type and any conforming decoder accepts it; a
+// may-fail mutant may be rejected, but must not crash or leak the decoder.
+const (
+	MustDecodeTag = "must/"
+	MayFailTag    = "may/"
+)
+
+// MustDecode reports whether the mutant called name must decode.
+func MustDecode(name string) bool {
+	return strings.HasPrefix(name, MustDecodeTag)
+}
+
+// record is one top-level field of an encoded message.
+type record struct {
+	num        protowire.Number
+	typ        protowire.Type
+	start, end int
+	value      int // offset of the value, after the tag
+}
+
+func records(data []byte) ([]record, error) {
+	var recs []record
+	for pos := 0; pos < len(data); {
+		num, typ, n := protowire.ConsumeTag(data[pos:])
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		m := protowire.ConsumeFieldValue(num, typ, data[pos+n:])
+		if m < 0 {
+			return nil, protowire.ParseError(m)
+		}
+		recs = append(recs, record{num: num, typ: typ, start: pos, end: pos + n + m, value: pos + n})
+		pos += n + m
+	}
+	return recs, nil
+}
+
+// Mutate derives n vectors from tc by flipping bits, changing the length of
+// a length-delimited field, renumbering a field into a range the schema does
+// not use, or repeating a field. Operations that do not apply to the case
+// are skipped, so an empty message yields no mutants. Names are
+// "<tag><case>/<operation>.<index>".
+func Mutate(rng *rand.Rand, tc TestCase, n int) ([]RawTestCase, error) {
+	data, err := tc.Data()
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s: %w", tc.Name, err)
+	}
+	recs, err := records(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", tc.Name, err)
+	}
+	if len(recs) == 0 {
+		return nil, nil
+	}
+	md := tc.Msg.ProtoReflect().Descriptor()
+
+	var mutants []RawTestCase
+	for k := 0; k < n; k++ {
+		r := recs[rng.IntN(len(recs))]
+		var op string
+		var out []byte
+		must := false
+		switch rng.IntN(4) {
+		case 0:
+			op = "bitflip"
+			out = append([]byte(nil), data...)
+			out[rng.IntN(len(out))] ^= 1 << rng.IntN(8)
+		case 1:
+			if r.typ != protowire.BytesType {
+				continue
+			}
+			op = "length"
+			v, _ := protowire.ConsumeBytes(data[r.value:])
+			length := uint64(len(v))
+			switch rng.IntN(3) {
+			case 0:
+				length++
+			case 1:
+				length--
+			default:
+				length = 1<<32 + length
+			}
+			out = append([]byte(nil), data[:r.value]...)
+			out = protowire.AppendVarint(out, length)
+			out = append(out, v...)
+			out = append(out, data[r.end:]...)
+		case 2:
+			// Group tags must match their end tag, so groups keep their
+			// numbers.
+			if r.typ == protowire.StartGroupType {
+				continue
+			}
+			op = "renumber"
+			num := unusedNumber(rng, md)
+			out = append([]byte(nil), data[:r.start]...)
+			out = protowire.AppendTag(out, num, r.typ)
+			out = append(out, data[r.value:]...)
+			// Moving a required field away leaves it missing.
+			fd := md.Fields().ByNumber(r.num)
+			must = fd == nil || fd.Cardinality() != protoreflect.Required
+		default:
+			// A repeated record is valid: scalars take the last value,
+			// messages merge and repeated fields append.
+			op = "duplicate"
+			out = append([]byte(nil), data[:r.end]...)
+			out = append(out, data[r.start:r.end]...)
+			out = append(out, data[r.end:]...)
+			must = true
+		}
+		tag := MayFailTag
+		if must {
+			tag = MustDecodeTag
+		}
+		mutants = append(mutants, RawTestCase{Name: fmt.Sprintf("%s%s/%s.%d", tag, tc.Name, op, k), Data: out})
+	}
+	return mutants, nil
+}
+
+// unusedNumber picks a field number above the ones the schemas use that md
+// does not declare.
+func unusedNumber(rng *rand.Rand, md protoreflect.MessageDescriptor) protowire.Number {
+	for {
+		num := protowire.Number(100000 + rng.IntN(int(protowire.MaxValidNumber)-100000))
+		if md.Fields().ByNumber(num) == nil && !md.ExtensionRanges().Has(num) {
+			return num
+		}
+	}
+}
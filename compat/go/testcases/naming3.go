@@ -0,0 +1,79 @@
+package testcases
+
+import (
+	"fmt"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("naming3", GenerateNaming3, validateNaming3)
+}
+
+func GenerateNaming3() []TestCase {
+	return []TestCase{
+		{
+			Name: "empty",
+			Msg:  &pb.Naming{},
+		},
+		{
+			Name: "keywords",
+			Msg: &pb.Naming{
+				Type:  "type",
+				Fn:    -1,
+				Error: "error",
+				Var:   true,
+			},
+		},
+		{
+			Name: "nested",
+			Msg: &pb.Naming{
+				Node: &pb.Naming_Node{
+					Kind:  pb.Naming_Node_KIND_BRANCH,
+					Child: &pb.Naming_Node_Node{Type: "leaf"},
+				},
+				OtherNode: &pb.Naming_Other_Node{Fn: 7},
+				Other: &pb.Naming_Other{
+					Node: &pb.Naming_Other_Node{Fn: 8},
+					Kind: pb.Naming_Other_KIND_LEAF,
+				},
+				Deepest: &pb.Naming_Deep_Deeper_Deepest{
+					Depth: pb.Naming_Deep_Deeper_Deepest_DEPTH_BOTTOM,
+					Var:   "bottom",
+				},
+			},
+		},
+	}
+}
+
+func validateNaming3(tc RawTestCase) int {
+	msg := &pb.Naming{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "empty":
+		failures += check(tc.Name, "node", msg.Node == nil)
+		failures += check(tc.Name, "type", msg.Type == "")
+		failures += check(tc.Name, "var", msg.Var == false)
+	case "keywords":
+		failures += check(tc.Name, "type", msg.Type == "type")
+		failures += check(tc.Name, "fn", msg.Fn == -1)
+		failures += check(tc.Name, "error", msg.Error == "error")
+		failures += check(tc.Name, "var", msg.Var == true)
+	case "nested":
+		failures += check(tc.Name, "node.kind", msg.GetNode().GetKind() == pb.Naming_Node_KIND_BRANCH)
+		failures += check(tc.Name, "node.child.type", msg.GetNode().GetChild().GetType() == "leaf")
+		failures += check(tc.Name, "other_node.fn", msg.GetOtherNode().GetFn() == 7)
+		failures += check(tc.Name, "other.node.fn", msg.GetOther().GetNode().GetFn() == 8)
+		failures += check(tc.Name, "other.kind", msg.GetOther().GetKind() == pb.Naming_Other_KIND_LEAF)
+		failures += check(tc.Name, "deepest.depth", msg.GetDeepest().GetDepth() == pb.Naming_Deep_Deeper_Deepest_DEPTH_BOTTOM)
+		failures += check(tc.Name, "deepest.var", msg.GetDeepest().GetVar() == "bottom")
+	}
+	return failures
+}
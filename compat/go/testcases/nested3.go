@@ -1,9 +1,22 @@
 package testcases
 
-import "compat/pb"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("nested3", GenerateNested3, validateNested3)
+}
 
 func GenerateNested3() []TestCase {
-	return []TestCase{
+	cases := []TestCase{
 		{
 			Name: "empty",
 			Msg:  &pb.Outer{},
@@ -34,5 +47,83 @@ func GenerateNested3() []TestCase {
 				},
 			},
 		},
+		// Present but empty submessages: zero-length payloads on the
+		// wire, which must decode as set rather than absent.
+		{
+			Name: "empty_middle",
+			Msg:  &pb.Outer{Middle: &pb.Middle{}},
+		},
+		{
+			Name: "empty_direct_inner",
+			Msg:  &pb.Outer{DirectInner: &pb.Inner{}},
+		},
+		{
+			Name: "middle_with_empty_inner",
+			Msg:  &pb.Outer{Middle: &pb.Middle{Inner: &pb.Inner{}}},
+		},
+	}
+	// Inner messages whose encoded size sits at each length-prefix
+	// boundary, which pushes Middle's size across it too.
+	for _, n := range lengthPrefixBoundaries {
+		cases = append(cases, TestCase{
+			Name: fmt.Sprintf("inner_size_%d", n),
+			Msg: &pb.Outer{
+				Middle: &pb.Middle{Inner: &pb.Inner{Label: strings.Repeat("n", labelForSize(n))}},
+			},
+		})
+	}
+	return cases
+}
+
+// labelForSize returns the label length that makes an Inner holding only a
+// label encode to exactly size bytes.
+func labelForSize(size int) int {
+	n := size - 2
+	for 1+protowire.SizeVarint(uint64(n))+n > size {
+		n--
+	}
+	return n
+}
+
+func validateNested3(tc RawTestCase) int {
+	msg := &pb.Outer{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "empty":
+		failures += check(tc.Name, "middle", msg.Middle == nil)
+		failures += check(tc.Name, "direct_inner", msg.DirectInner == nil)
+	case "two_levels":
+		failures += check(tc.Name, "middle.id", msg.Middle != nil && msg.Middle.Id == 10)
+		failures += check(tc.Name, "middle.inner.value", msg.Middle != nil && msg.Middle.Inner != nil && msg.Middle.Inner.Value == 42)
+		failures += check(tc.Name, "middle.inner.label", msg.Middle != nil && msg.Middle.Inner != nil && msg.Middle.Inner.Label == "inner_label")
+		failures += check(tc.Name, "direct_inner.value", msg.DirectInner != nil && msg.DirectInner.Value == 99)
+		failures += check(tc.Name, "direct_inner.label", msg.DirectInner != nil && msg.DirectInner.Label == "direct")
+		failures += check(tc.Name, "name", msg.Name == "outer")
+	case "single_level":
+		failures += check(tc.Name, "middle", msg.Middle == nil)
+		failures += check(tc.Name, "direct_inner.value", msg.DirectInner != nil && msg.DirectInner.Value == 5)
+		failures += check(tc.Name, "direct_inner.label", msg.DirectInner != nil && msg.DirectInner.Label == "only")
+	case "empty_middle":
+		failures += check(tc.Name, "middle", msg.Middle != nil)
+		failures += check(tc.Name, "middle.inner", msg.GetMiddle().GetInner() == nil)
+		failures += check(tc.Name, "direct_inner", msg.DirectInner == nil)
+	case "empty_direct_inner":
+		failures += check(tc.Name, "middle", msg.Middle == nil)
+		failures += check(tc.Name, "direct_inner", msg.DirectInner != nil)
+	case "middle_with_empty_inner":
+		failures += check(tc.Name, "middle", msg.Middle != nil)
+		failures += check(tc.Name, "middle.inner", msg.GetMiddle().GetInner() != nil)
+		failures += check(tc.Name, "direct_inner", msg.DirectInner == nil)
+	case "inner_size_127", "inner_size_128", "inner_size_16383", "inner_size_16384":
+		n, _ := strconv.Atoi(strings.TrimPrefix(tc.Name, "inner_size_"))
+		inner := msg.GetMiddle().GetInner()
+		failures += check(tc.Name, "middle.inner.label", inner.GetLabel() == strings.Repeat("n", labelForSize(n)))
+		failures += check(tc.Name, "middle.inner.size", inner != nil && proto.Size(inner) == n)
 	}
+	return failures
 }
@@ -0,0 +1,127 @@
+package testcases
+
+import (
+	"fmt"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func init() {
+	Register("nestedmap3", GenerateNestedMap3, validateNestedMap3)
+}
+
+// nestedLayer builds a layer of size n: n counts, n leaves with up to two
+// attributes each and n%4 buckets of n names each. The count keys "c<i>"
+// are reused as outer keys by some cases.
+func nestedLayer(n int) *pb.NestedMapLayer {
+	layer := &pb.NestedMapLayer{
+		Counts: map[string]int32{},
+		Leaves: map[int64]*pb.NestedMapLeaf{},
+	}
+	for i := range n {
+		layer.Counts[fmt.Sprintf("c%d", i)] = int32(i)
+		leaf := &pb.NestedMapLeaf{Text: fmt.Sprintf("leaf%d", i), Attrs: map[string]string{}}
+		for j := range i % 3 {
+			leaf.Attrs[fmt.Sprintf("k%d", j)] = fmt.Sprintf("v%d", j)
+		}
+		layer.Leaves[int64(i)*1000-1] = leaf
+	}
+	for range n % 4 {
+		bucket := &pb.NestedMapBucket{
+			Names: map[uint32]string{},
+			Flags: map[bool][]byte{true: {1}, false: {}},
+		}
+		for j := range n {
+			bucket.Names[uint32(j)] = fmt.Sprintf("n%d", j)
+		}
+		layer.Buckets = append(layer.Buckets, bucket)
+	}
+	return layer
+}
+
+// GenerateNestedMap3 writes maps of layers from empty to tens of entries,
+// with empty keys and values, and with the keys of one level reused at
+// another.
+func GenerateNestedMap3() []TestCase {
+	wide := &pb.NestedMaps{Layers: map[string]*pb.NestedMapLayer{}, ById: map[int32]*pb.NestedMapLayer{}}
+	for i := range 32 {
+		wide.Layers[fmt.Sprintf("l%d", i)] = nestedLayer(i % 8)
+		wide.ById[int32(i)] = nestedLayer(8)
+	}
+	return []TestCase{
+		{Name: "empty", Msg: &pb.NestedMaps{}},
+		{Name: "empty_values", Msg: &pb.NestedMaps{
+			Layers: map[string]*pb.NestedMapLayer{"": {}},
+			ById:   map[int32]*pb.NestedMapLayer{0: {}},
+		}},
+		{Name: "single", Msg: &pb.NestedMaps{
+			Layers: map[string]*pb.NestedMapLayer{"a": nestedLayer(1)},
+			ById:   map[int32]*pb.NestedMapLayer{1: nestedLayer(1)},
+		}},
+		{Name: "multi_entry", Msg: &pb.NestedMaps{
+			Layers: map[string]*pb.NestedMapLayer{"a": nestedLayer(2), "b": nestedLayer(3), "c": nestedLayer(4)},
+			ById:   map[int32]*pb.NestedMapLayer{-1: nestedLayer(3), 0: nestedLayer(0), 7: nestedLayer(5)},
+		}},
+		{Name: "shared_keys", Msg: &pb.NestedMaps{
+			Layers: map[string]*pb.NestedMapLayer{"c0": nestedLayer(2), "c1": nestedLayer(2)},
+			ById:   map[int32]*pb.NestedMapLayer{0: nestedLayer(2), 1: nestedLayer(2)},
+		}},
+		{Name: "wide", Msg: wide},
+	}
+}
+
+// validateNestedMap3 checks the decoded message against the generator's
+// entry by entry, naming the path to each entry that is missing or differs.
+func validateNestedMap3(tc RawTestCase) int {
+	msg := &pb.NestedMaps{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+	expected, ok := Expected("nestedmap3/" + tc.Name)
+	if !ok {
+		return 0
+	}
+	return checkMapFields(tc.Name, "", expected.ProtoReflect(), msg.ProtoReflect())
+}
+
+// checkMapFields checks every field of got against want, recursing into
+// map values and list elements, with paths such as "layers[a].buckets[1].names[3]".
+func checkMapFields(name, prefix string, want, got protoreflect.Message) int {
+	failures := 0
+	fields := want.Descriptor().Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		path := prefix + string(fd.Name())
+		switch {
+		case fd.IsMap():
+			wm, gm := want.Get(fd).Map(), got.Get(fd).Map()
+			failures += check(name, path+".len", wm.Len() == gm.Len())
+			wm.Range(func(k protoreflect.MapKey, wv protoreflect.Value) bool {
+				entry := fmt.Sprintf("%s[%v]", path, k.Interface())
+				gv := gm.Get(k)
+				switch {
+				case !gm.Has(k):
+					failures += check(name, entry, false)
+				case fd.MapValue().Message() != nil:
+					failures += checkMapFields(name, entry+".", wv.Message(), gv.Message())
+				default:
+					failures += check(name, entry, wv.Equal(gv))
+				}
+				return true
+			})
+		case fd.IsList():
+			wl, gl := want.Get(fd).List(), got.Get(fd).List()
+			failures += check(name, path+".len", wl.Len() == gl.Len())
+			for j := range min(wl.Len(), gl.Len()) {
+				failures += checkMapFields(name, fmt.Sprintf("%s[%d].", path, j), wl.Get(j).Message(), gl.Get(j).Message())
+			}
+		default:
+			failures += check(name, path, want.Get(fd).Equal(got.Get(fd)))
+		}
+	}
+	return failures
+}
@@ -1,9 +1,65 @@
 package testcases
 
-import "compat/pb"
+import (
+	"fmt"
+
+	"compat/pb"
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("oneof3", GenerateOneof3, validateOneof3)
+}
+
+// oneofOverwrites are wire sequences setting several members of the value
+// oneof in turn. The last member read wins and clears the earlier ones, except
+// that a message member read twice merges, as a repeated message field does.
+var oneofOverwrites = []struct {
+	name   string
+	fields [][]byte
+	want   *pb.OneofMessage
+}{
+	{"overwrite_str_then_int", [][]byte{oneofStr("hello"), oneofInt(42)},
+		&pb.OneofMessage{Value: &pb.OneofMessage_IntVal{IntVal: 42}}},
+	{"overwrite_int_then_str", [][]byte{oneofInt(42), oneofStr("hello")},
+		&pb.OneofMessage{Value: &pb.OneofMessage_StrVal{StrVal: "hello"}}},
+	{"overwrite_msg_then_int", [][]byte{oneofMsg(&pb.SubMsg{Id: 1, Text: "sub"}), oneofInt(7)},
+		&pb.OneofMessage{Value: &pb.OneofMessage_IntVal{IntVal: 7}}},
+	{"overwrite_int_then_msg", [][]byte{oneofInt(7), oneofMsg(&pb.SubMsg{Id: 1, Text: "sub"})},
+		&pb.OneofMessage{Value: &pb.OneofMessage_MsgVal{MsgVal: &pb.SubMsg{Id: 1, Text: "sub"}}}},
+	{"overwrite_bytes_then_str_with_name", [][]byte{oneofBytes([]byte{1, 2, 3}), oneofName("test"), oneofStr("last")},
+		&pb.OneofMessage{Name: "test", Value: &pb.OneofMessage_StrVal{StrVal: "last"}}},
+	{"overwrite_msg_then_msg", [][]byte{oneofMsg(&pb.SubMsg{Id: 1}), oneofMsg(&pb.SubMsg{Text: "b"})},
+		&pb.OneofMessage{Value: &pb.OneofMessage_MsgVal{MsgVal: &pb.SubMsg{Id: 1, Text: "b"}}}},
+	{"overwrite_msg_int_msg", [][]byte{oneofMsg(&pb.SubMsg{Id: 1}), oneofInt(7), oneofMsg(&pb.SubMsg{Text: "b"})},
+		&pb.OneofMessage{Value: &pb.OneofMessage_MsgVal{MsgVal: &pb.SubMsg{Text: "b"}}}},
+}
+
+func oneofName(v string) []byte {
+	return protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), v)
+}
+
+func oneofStr(v string) []byte {
+	return protowire.AppendString(protowire.AppendTag(nil, 10, protowire.BytesType), v)
+}
+
+func oneofInt(v int32) []byte {
+	return protowire.AppendVarint(protowire.AppendTag(nil, 11, protowire.VarintType), uint64(v))
+}
+
+func oneofBytes(v []byte) []byte {
+	return protowire.AppendBytes(protowire.AppendTag(nil, 12, protowire.BytesType), v)
+}
+
+func oneofMsg(m *pb.SubMsg) []byte {
+	return protowire.AppendBytes(protowire.AppendTag(nil, 13, protowire.BytesType), pbutil.MustMarshal(m))
+}
 
 func GenerateOneof3() []TestCase {
-	return []TestCase{
+	cases := []TestCase{
 		{
 			Name: "none_set",
 			Msg: &pb.OneofMessage{
@@ -42,4 +98,64 @@ func GenerateOneof3() []TestCase {
 			},
 		},
 	}
+	for _, o := range oneofOverwrites {
+		var wire []byte
+		for _, f := range o.fields {
+			wire = append(wire, f...)
+		}
+		cases = append(cases, TestCase{Name: o.name, Msg: o.want, Wire: wire})
+	}
+	return cases
+}
+
+func validateOneof3(tc RawTestCase) int {
+	msg := &pb.OneofMessage{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "none_set":
+		failures += check(tc.Name, "name", msg.Name == "empty")
+		failures += check(tc.Name, "value", msg.Value == nil)
+	case "string_variant":
+		failures += check(tc.Name, "name", msg.Name == "test")
+		if v, ok := msg.Value.(*pb.OneofMessage_StrVal); ok {
+			failures += check(tc.Name, "str_val", v.StrVal == "hello")
+		} else {
+			failures += check(tc.Name, "value_type", false)
+		}
+	case "int_variant":
+		failures += check(tc.Name, "name", msg.Name == "test")
+		if v, ok := msg.Value.(*pb.OneofMessage_IntVal); ok {
+			failures += check(tc.Name, "int_val", v.IntVal == 42)
+		} else {
+			failures += check(tc.Name, "value_type", false)
+		}
+	case "bytes_variant":
+		failures += check(tc.Name, "name", msg.Name == "test")
+		if v, ok := msg.Value.(*pb.OneofMessage_BytesVal); ok {
+			failures += check(tc.Name, "bytes_val", len(v.BytesVal) == 3 && v.BytesVal[0] == 0x01 && v.BytesVal[1] == 0x02 && v.BytesVal[2] == 0x03)
+		} else {
+			failures += check(tc.Name, "value_type", false)
+		}
+	case "msg_variant":
+		failures += check(tc.Name, "name", msg.Name == "test")
+		if v, ok := msg.Value.(*pb.OneofMessage_MsgVal); ok {
+			failures += check(tc.Name, "msg_val.id", v.MsgVal != nil && v.MsgVal.Id == 1)
+			failures += check(tc.Name, "msg_val.text", v.MsgVal != nil && v.MsgVal.Text == "sub")
+		} else {
+			failures += check(tc.Name, "value_type", false)
+		}
+	default:
+		for _, o := range oneofOverwrites {
+			if o.name == tc.Name {
+				failures += check(tc.Name, "name", msg.Name == o.want.Name)
+				failures += check(tc.Name, "value", proto.Equal(msg, o.want))
+			}
+		}
+	}
+	return failures
 }
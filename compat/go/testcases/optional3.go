@@ -1,6 +1,16 @@
 package testcases
 
-import "compat/pb"
+import (
+	"fmt"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("optional3", GenerateOptional3, validateOptional3)
+}
 
 func GenerateOptional3() []TestCase {
 	return []TestCase{
@@ -34,3 +44,31 @@ func proto_int32(v int32) *int32     { return &v }
 func proto_string(v string) *string  { return &v }
 func proto_bool(v bool) *bool        { return &v }
 func proto_float64(v float64) *float64 { return &v }
+
+func validateOptional3(tc RawTestCase) int {
+	msg := &pb.OptionalMessage{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "all_unset":
+		failures += check(tc.Name, "opt_int", msg.OptInt == nil)
+		failures += check(tc.Name, "opt_str", msg.OptStr == nil)
+		failures += check(tc.Name, "opt_bool", msg.OptBool == nil)
+		failures += check(tc.Name, "opt_double", msg.OptDouble == nil)
+	case "all_zero":
+		failures += check(tc.Name, "opt_int", msg.OptInt != nil && *msg.OptInt == 0)
+		failures += check(tc.Name, "opt_bool", msg.OptBool != nil && *msg.OptBool == false)
+		failures += check(tc.Name, "opt_double", msg.OptDouble != nil && *msg.OptDouble == 0.0)
+	case "all_nonzero":
+		failures += check(tc.Name, "opt_int", msg.OptInt != nil && *msg.OptInt == 42)
+		failures += check(tc.Name, "opt_str", msg.OptStr != nil && *msg.OptStr == "hello")
+		failures += check(tc.Name, "opt_bool", msg.OptBool != nil && *msg.OptBool == true)
+		failures += check(tc.Name, "opt_double", msg.OptDouble != nil && *msg.OptDouble == 3.14)
+		failures += check(tc.Name, "regular_int", msg.RegularInt == 100)
+	}
+	return failures
+}
@@ -0,0 +1,95 @@
+package testcases
+
+import (
+	"fmt"
+
+	"compat/pb"
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// DescriptorSetName is the file, next to the corpus, holding the descriptor
+// set of options/options3.proto. The schema uses custom file, message and
+// field options; it lives outside proto/ because the Zig generator cannot yet
+// import google/protobuf/descriptor.proto.
+const DescriptorSetName = "options3.desc"
+
+// DescriptorSet is a descriptor set cmd/generate writes next to the corpus,
+// and that cmd/validate checks when another generator has emitted it.
+type DescriptorSet struct {
+	Name     string
+	Build    func() ([]byte, error)
+	Validate func(data []byte) int
+}
+
+// DescriptorSets lists every descriptor set written next to the corpus.
+var DescriptorSets = []DescriptorSet{
+	{Name: DescriptorSetName, Build: OptionsDescriptorSet, Validate: ValidateOptions},
+	{Name: ReservedDescriptorSetName, Build: ReservedDescriptorSet, Validate: ValidateReserved},
+}
+
+// OptionsDescriptorSet returns the serialized FileDescriptorSet of
+// options3.proto and its import, with the custom options set.
+func OptionsDescriptorSet() ([]byte, error) {
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto),
+		protodesc.ToFileDescriptorProto(pb.File_options3_proto),
+	}}
+	return pbutil.Marshal(set)
+}
+
+// ValidateOptions checks that a descriptor set still carries the custom
+// options of options3.proto. Options the reader did not understand must have
+// been kept as unknown fields for this to pass.
+func ValidateOptions(data []byte) int {
+	const name = "options3"
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", name, err)
+		return 1
+	}
+	var file *descriptorpb.FileDescriptorProto
+	for _, f := range set.GetFile() {
+		if f.GetName() == "options3.proto" {
+			file = f
+		}
+	}
+	if check(name, "file", file != nil) > 0 {
+		return 1
+	}
+
+	failures := check(name, "schema_owner", getOption(file.GetOptions(), pb.E_SchemaOwner) == "compat")
+	var record *descriptorpb.DescriptorProto
+	for _, m := range file.GetMessageType() {
+		if m.GetName() == "Record" {
+			record = m
+		}
+	}
+	if check(name, "Record", record != nil) > 0 {
+		return failures + 1
+	}
+	failures += check(name, "Record.table_name", getOption(record.GetOptions(), pb.E_TableName) == "records")
+	for _, f := range record.GetField() {
+		switch f.GetName() {
+		case "id":
+			failures += check(name, "Record.id.indexed", getOption(f.GetOptions(), pb.E_Indexed) == true)
+		case "title":
+			failures += check(name, "Record.title.max_length", getOption(f.GetOptions(), pb.E_MaxLength) == int32(80))
+		}
+	}
+	return failures
+}
+
+// getOption returns the value of an option extension, or nil if opts lacks
+// it. The extensions are registered by package pb, so unmarshaling the set
+// resolves them.
+func getOption(opts proto.Message, xt protoreflect.ExtensionType) any {
+	if opts == nil || !opts.ProtoReflect().IsValid() || !proto.HasExtension(opts, xt) {
+		return nil
+	}
+	return proto.GetExtension(opts, xt)
+}
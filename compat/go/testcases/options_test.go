@@ -0,0 +1,72 @@
+package testcases
+
+import (
+	"testing"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestValidateOptions(t *testing.T) {
+	data, err := OptionsDescriptorSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := ValidateOptions(data); n != 0 {
+		t.Fatalf("ValidateOptions = %d failures, want 0", n)
+	}
+
+	// A reader that drops the options must fail every check.
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range set.GetFile() {
+		if f.GetName() != "options3.proto" {
+			continue
+		}
+		proto.ClearExtension(f.GetOptions(), pb.E_SchemaOwner)
+		f.GetMessageType()[0].Options = nil
+		for _, fd := range f.GetMessageType()[0].GetField() {
+			fd.Options = nil
+		}
+	}
+	stripped, err := proto.Marshal(&set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := ValidateOptions(stripped); n != 4 {
+		t.Fatalf("ValidateOptions on stripped set = %d failures, want 4", n)
+	}
+}
+
+func TestValidateReserved(t *testing.T) {
+	data, err := ReservedDescriptorSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := ValidateReserved(data); n != 0 {
+		t.Fatalf("ValidateReserved = %d failures, want 0", n)
+	}
+
+	// Dropping a range and the names fails both lists, and a field on a
+	// number still reserved fails too.
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		t.Fatal(err)
+	}
+	msg := set.GetFile()[0].GetMessageType()[0]
+	msg.ReservedRange = msg.ReservedRange[1:]
+	msg.ReservedName = nil
+	msg.Field[1].Number = proto.Int32(6)
+	msg.Field[1].Name = proto.String("legacy_name")
+	stripped, err := proto.Marshal(&set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := ValidateReserved(stripped); n != 3 {
+		t.Fatalf("ValidateReserved on stripped set = %d failures, want 3", n)
+	}
+}
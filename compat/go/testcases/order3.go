@@ -0,0 +1,111 @@
+package testcases
+
+import (
+	"fmt"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("order3", GenerateOrder3, validateOrder3)
+}
+
+// orderItem is the element every order3 case repeats: the same but for the
+// mark in its leaf.
+func orderItem(mark int64) *pb.OrderItem {
+	return &pb.OrderItem{
+		Name:   "item",
+		Values: []int32{1, 2, 3},
+		Branch: &pb.OrderBranch{
+			Blob: []byte{0x00, 0xff, 0x00, 0xff},
+			Leaf: &pb.OrderLeaf{Label: "leaf", Mark: mark},
+		},
+	}
+}
+
+// orderCases give each case's length and the element at each index.
+var orderCases = []struct {
+	name string
+	n    int
+	item func(i int) *pb.OrderItem
+}{
+	{"all_identical", 300, func(int) *pb.OrderItem { return orderItem(7) }},
+	{"adjacent_pairs", 400, func(i int) *pb.OrderItem { return orderItem(int64(i / 2)) }},
+	// Runs of one to seven identical elements, each run's mark one more
+	// than the last.
+	{"runs", 512, func(i int) *pb.OrderItem { return orderItem(orderRun(i)) }},
+	{"ascending", 500, func(i int) *pb.OrderItem { return orderItem(int64(i)) }},
+	{"descending", 500, func(i int) *pb.OrderItem { return orderItem(int64(500 - i)) }},
+	// Equal elements that are never adjacent.
+	{"alternating", 400, func(i int) *pb.OrderItem { return orderItem(int64(i % 2)) }},
+	{"one_differs", 512, func(i int) *pb.OrderItem {
+		if i == 255 {
+			return orderItem(-1)
+		}
+		return orderItem(0)
+	}},
+	// Empty elements encode to zero bytes, so every one is identical.
+	{"empty_elements", 300, func(int) *pb.OrderItem { return &pb.OrderItem{} }},
+	{"empty_between", 300, func(i int) *pb.OrderItem {
+		if i%3 == 1 {
+			return &pb.OrderItem{}
+		}
+		return orderItem(int64(i / 3))
+	}},
+}
+
+// orderRun is the mark of element i of the runs case.
+func orderRun(i int) int64 {
+	for run := int64(0); ; run++ {
+		n := int(run%7) + 1
+		if i < n {
+			return run
+		}
+		i -= n
+	}
+}
+
+// GenerateOrder3 writes hundreds of OrderItems per case, in runs of
+// byte-identical elements and in sequences differing only in a leaf's mark.
+func GenerateOrder3() []TestCase {
+	var cases []TestCase
+	for _, c := range orderCases {
+		msg := &pb.OrderList{}
+		for i := range c.n {
+			msg.Items = append(msg.Items, c.item(i))
+		}
+		cases = append(cases, TestCase{Name: c.name, Msg: msg})
+	}
+	return cases
+}
+
+// validateOrder3 checks that every element arrived, in order: the decoded
+// list has as many elements as the generator's and each equals the one at
+// its index.
+func validateOrder3(tc RawTestCase) int {
+	msg := &pb.OrderList{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+	expected, ok := Expected("order3/" + tc.Name)
+	if !ok {
+		return 0
+	}
+	want := expected.(*pb.OrderList)
+
+	if check(tc.Name, "items.count", len(msg.Items) == len(want.Items)) != 0 {
+		fmt.Printf("    %d elements, want %d\n", len(msg.Items), len(want.Items))
+		return 1
+	}
+	for i := range want.Items {
+		if !proto.Equal(msg.Items[i], want.Items[i]) {
+			check(tc.Name, "items.order", false)
+			fmt.Printf("    first difference at element %d: got {%v}, want {%v}\n", i, msg.Items[i], want.Items[i])
+			return 1
+		}
+	}
+	return check(tc.Name, "items.order", true)
+}
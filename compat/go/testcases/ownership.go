@@ -0,0 +1,39 @@
+package testcases
+
+import (
+	"bytes"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// scribbleByte overwrites a decoded case's input in CheckOwnership.
+const scribbleByte = 0xa5
+
+// CheckOwnership tests the ownership contract both runtimes keep: a decoded
+// message owns its strings, bytes and unknown fields, so it is unchanged
+// when the buffer it was decoded from is overwritten. It decodes a private
+// copy of tc.Data as the category's type, scribbles over the copy and
+// compares the message with a decode of the untouched data.
+func CheckOwnership(c Category, tc RawTestCase) int {
+	want, ok := Expected(c.Name + "/" + tc.Name)
+	if !ok {
+		return 0
+	}
+	opts := proto.UnmarshalOptions{AllowPartial: true}
+	ref := want.ProtoReflect().New().Interface()
+	if err := opts.Unmarshal(tc.Data, ref); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+	buf := bytes.Clone(tc.Data)
+	got := want.ProtoReflect().New().Interface()
+	if err := opts.Unmarshal(buf, got); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+	for i := range buf {
+		buf[i] = scribbleByte
+	}
+	return check(tc.Name, "owned", proto.Equal(got, ref))
+}
@@ -0,0 +1,146 @@
+package testcases
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("packed3", GeneratePacked3, validatePacked3)
+}
+
+// Zigzag maps n to 2n for n >= 0 and -2n-1 otherwise, so the varint width
+// steps up between each pair below: sign bugs and off-by-one errors in the
+// shift show up exactly at these values.
+var (
+	zigzag32Boundaries = []int32{0, -1, 1, -64, 63, -65, 64, -8192, 8191, -8193, 8192,
+		-1048576, 1048575, -134217728, 134217727, math.MinInt32, math.MaxInt32}
+	zigzag64Boundaries = []int64{0, -1, 1, -64, 63, -65, 64, -8192, 8191, -8193, 8192,
+		math.MinInt32, math.MaxInt32, -1 << 55, 1<<55 - 1, -1 << 62, 1<<62 - 1, math.MinInt64, math.MaxInt64}
+)
+
+func GeneratePacked3() []TestCase {
+	return []TestCase{
+		{
+			Name: "empty",
+			Msg:  &pb.PackedScalars{},
+		},
+		{
+			Name: "sint_extremes",
+			Msg: &pb.PackedScalars{
+				FSint32: []int32{-1, math.MinInt32, math.MaxInt32},
+				FSint64: []int64{-1, math.MinInt64, math.MaxInt64},
+			},
+		},
+		{
+			Name: "sint_alternating",
+			Msg: &pb.PackedScalars{
+				FSint32: zigzag32Boundaries,
+				FSint64: zigzag64Boundaries,
+			},
+		},
+		{
+			Name: "float_bits",
+			Msg:  &pb.PackedScalars{FFloat: floatsFromBits(packedFloatBits)},
+		},
+		{
+			Name: "fixed_single",
+			Msg: &pb.PackedScalars{
+				FFixed32:  []uint32{math.MaxUint32},
+				FFixed64:  []uint64{math.MaxUint64},
+				FSfixed32: []int32{math.MinInt32},
+				FSfixed64: []int64{math.MinInt64},
+				FFloat:    []float32{1.5},
+			},
+		},
+		{
+			Name: "fixed_large",
+			Msg:  largeFixed(),
+		},
+	}
+}
+
+// largeCount is the element count of the large packed cases: enough for
+// multi-kilobyte packed payloads with two-byte length prefixes.
+const largeCount = 1024
+
+// largeFixed fills every fixed-width packed field with largeCount values
+// that are cheap to recompute on the Zig side.
+func largeFixed() *pb.PackedScalars {
+	m := &pb.PackedScalars{}
+	for i := range largeCount {
+		sign := int64(1 - 2*(i%2))
+		m.FFixed32 = append(m.FFixed32, uint32(i)*2654435761)
+		m.FFixed64 = append(m.FFixed64, uint64(i)*0x9e3779b97f4a7c15)
+		m.FSfixed32 = append(m.FSfixed32, int32(sign)*int32(i*7919))
+		m.FSfixed64 = append(m.FSfixed64, sign*(int64(i)<<40|int64(i)))
+		m.FFloat = append(m.FFloat, float32(i)*0.25-100)
+	}
+	return m
+}
+
+// packedFloatBits are float32 values compared by bit pattern: -0, the
+// smallest and largest denormals, NaNs with payloads and either sign, +Inf
+// and 0.1, which is inexact and differs from float32(float64(0.1)) if the
+// encoder rounds twice.
+var packedFloatBits = []uint32{0x8000_0000, 0x0000_0001, 0x007f_ffff, 0x7fc0_1234, 0xffc0_0001, 0x7f80_0000, 0x3dcc_cccd}
+
+func floatsFromBits(bits []uint32) []float32 {
+	fs := make([]float32, len(bits))
+	for i, b := range bits {
+		fs[i] = math.Float32frombits(b)
+	}
+	return fs
+}
+
+func validatePacked3(tc RawTestCase) int {
+	msg := &pb.PackedScalars{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "empty":
+		failures += check(tc.Name, "f_sint32.len", len(msg.FSint32) == 0)
+		failures += check(tc.Name, "f_sint64.len", len(msg.FSint64) == 0)
+		failures += check(tc.Name, "f_fixed32.len", len(msg.FFixed32) == 0)
+		failures += check(tc.Name, "f_fixed64.len", len(msg.FFixed64) == 0)
+		failures += check(tc.Name, "f_sfixed32.len", len(msg.FSfixed32) == 0)
+		failures += check(tc.Name, "f_sfixed64.len", len(msg.FSfixed64) == 0)
+		failures += check(tc.Name, "f_float.len", len(msg.FFloat) == 0)
+	case "sint_extremes":
+		failures += check(tc.Name, "f_sint32", slices.Equal(msg.FSint32, []int32{-1, math.MinInt32, math.MaxInt32}))
+		failures += check(tc.Name, "f_sint64", slices.Equal(msg.FSint64, []int64{-1, math.MinInt64, math.MaxInt64}))
+	case "sint_alternating":
+		failures += check(tc.Name, "f_sint32", slices.Equal(msg.FSint32, zigzag32Boundaries))
+		failures += check(tc.Name, "f_sint64", slices.Equal(msg.FSint64, zigzag64Boundaries))
+	case "float_bits":
+		failures += check(tc.Name, "f_float.len", len(msg.FFloat) == len(packedFloatBits))
+		for i, f := range msg.FFloat {
+			if i < len(packedFloatBits) {
+				failures += check(tc.Name, fmt.Sprintf("f_float[%d]", i), sameBits32(f, packedFloatBits[i]))
+			}
+		}
+	case "fixed_single":
+		failures += check(tc.Name, "f_fixed32", slices.Equal(msg.FFixed32, []uint32{math.MaxUint32}))
+		failures += check(tc.Name, "f_fixed64", slices.Equal(msg.FFixed64, []uint64{math.MaxUint64}))
+		failures += check(tc.Name, "f_sfixed32", slices.Equal(msg.FSfixed32, []int32{math.MinInt32}))
+		failures += check(tc.Name, "f_sfixed64", slices.Equal(msg.FSfixed64, []int64{math.MinInt64}))
+		failures += check(tc.Name, "f_float", slices.Equal(msg.FFloat, []float32{1.5}))
+	case "fixed_large":
+		want := largeFixed()
+		failures += check(tc.Name, "f_fixed32", slices.Equal(msg.FFixed32, want.FFixed32))
+		failures += check(tc.Name, "f_fixed64", slices.Equal(msg.FFixed64, want.FFixed64))
+		failures += check(tc.Name, "f_sfixed32", slices.Equal(msg.FSfixed32, want.FSfixed32))
+		failures += check(tc.Name, "f_sfixed64", slices.Equal(msg.FSfixed64, want.FSfixed64))
+		failures += check(tc.Name, "f_float", slices.Equal(msg.FFloat, want.FFloat))
+	}
+	return failures
+}
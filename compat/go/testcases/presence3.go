@@ -0,0 +1,187 @@
+package testcases
+
+import (
+	"fmt"
+	"math"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func init() {
+	Register("presence3", GeneratePresence3, validatePresence3)
+}
+
+// presenceShapes are the field shapes PresenceMatrix declares in each form,
+// with a nonzero value for each. Field i_<shape> has implicit presence,
+// o_<shape> is optional and w_<shape>_value the only member of oneof
+// w_<shape>.
+var presenceShapes = []struct {
+	name    string
+	nonzero func() protoreflect.Value
+}{
+	{"int32", func() protoreflect.Value { return protoreflect.ValueOfInt32(-1) }},
+	{"sint64", func() protoreflect.Value { return protoreflect.ValueOfInt64(-1) }},
+	{"fixed32", func() protoreflect.Value { return protoreflect.ValueOfUint32(math.MaxUint32) }},
+	{"double", func() protoreflect.Value { return protoreflect.ValueOfFloat64(1.5) }},
+	{"bool", func() protoreflect.Value { return protoreflect.ValueOfBool(true) }},
+	{"string", func() protoreflect.Value { return protoreflect.ValueOfString("x") }},
+	{"bytes", func() protoreflect.Value { return protoreflect.ValueOfBytes([]byte{0}) }},
+	{"enum", func() protoreflect.Value { return protoreflect.ValueOfEnum(pb.PresenceEnum_PRESENCE_ENUM_ONE.Number()) }},
+	{"message", func() protoreflect.Value {
+		return protoreflect.ValueOfMessage((&pb.PresenceChild{Value: 1}).ProtoReflect())
+	}},
+}
+
+// presenceForms are the three ways PresenceMatrix declares each shape, by
+// case name prefix.
+var presenceForms = []string{"implicit", "optional", "oneof"}
+
+// presenceField returns the field of the given form and shape.
+func presenceField(form, shape string) protoreflect.FieldDescriptor {
+	fields := (&pb.PresenceMatrix{}).ProtoReflect().Descriptor().Fields()
+	switch form {
+	case "implicit":
+		return fields.ByName(protoreflect.Name("i_" + shape))
+	case "optional":
+		return fields.ByName(protoreflect.Name("o_" + shape))
+	default:
+		return fields.ByName(protoreflect.Name("w_" + shape + "_value"))
+	}
+}
+
+// presenceZero is the zero value of fd. Setting it marks an optional or
+// oneof field present, and an empty message present in any form.
+func presenceZero(m protoreflect.Message, fd protoreflect.FieldDescriptor) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		return m.NewField(fd)
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte{})
+	}
+	return fd.Default()
+}
+
+// GeneratePresence3 sets each field of PresenceMatrix alone to its zero
+// value, then every field to its zero and to a nonzero value. The Go
+// encoding leaves out exactly the implicit scalars that hold zero, which
+// the validator requires of the Zig encoding too. One case puts those
+// zeros on the wire anyway, which a decoder must read as unset.
+func GeneratePresence3() []TestCase {
+	cases := []TestCase{{Name: "unset", Msg: &pb.PresenceMatrix{}}}
+	allZero, allNonzero := &pb.PresenceMatrix{}, &pb.PresenceMatrix{}
+	for _, form := range presenceForms {
+		for _, shape := range presenceShapes {
+			fd := presenceField(form, shape.name)
+			m := &pb.PresenceMatrix{}
+			m.ProtoReflect().Set(fd, presenceZero(m.ProtoReflect(), fd))
+			cases = append(cases, TestCase{Name: fmt.Sprintf("%s_%s_zero", form, shape.name), Msg: m})
+			allZero.ProtoReflect().Set(fd, presenceZero(allZero.ProtoReflect(), fd))
+			allNonzero.ProtoReflect().Set(fd, shape.nonzero())
+		}
+	}
+	cases = append(cases,
+		TestCase{Name: "all_zero", Msg: allZero},
+		TestCase{Name: "all_nonzero", Msg: allNonzero},
+		// -0.0 is not zero: its sign bit must survive, so it is written
+		// even with implicit presence.
+		TestCase{Name: "implicit_double_negative_zero", Msg: &pb.PresenceMatrix{IDouble: math.Copysign(0, -1)}},
+		TestCase{Name: "implicit_zero_on_wire", Msg: &pb.PresenceMatrix{}, Wire: implicitZerosOnWire(), GoOnly: true},
+	)
+	return cases
+}
+
+// implicitZerosOnWire encodes every implicit scalar of PresenceMatrix with
+// its zero value, which no marshaler writes.
+func implicitZerosOnWire() []byte {
+	var b []byte
+	for _, shape := range presenceShapes {
+		fd := presenceField("implicit", shape.name)
+		switch fd.Kind() {
+		case protoreflect.MessageKind:
+			continue
+		case protoreflect.Fixed32Kind:
+			b = protowire.AppendTag(b, fd.Number(), protowire.Fixed32Type)
+			b = protowire.AppendFixed32(b, 0)
+		case protoreflect.DoubleKind:
+			b = protowire.AppendTag(b, fd.Number(), protowire.Fixed64Type)
+			b = protowire.AppendFixed64(b, 0)
+		case protoreflect.StringKind, protoreflect.BytesKind:
+			b = protowire.AppendTag(b, fd.Number(), protowire.BytesType)
+			b = protowire.AppendBytes(b, nil)
+		default:
+			b = protowire.AppendTag(b, fd.Number(), protowire.VarintType)
+			b = protowire.AppendVarint(b, 0)
+		}
+	}
+	return b
+}
+
+// validatePresence3 checks, field by field against the generator's case,
+// that the decoded message has each field present exactly when the case
+// does, with the same value, and that the encoding has each field on the
+// wire exactly when it is present.
+func validatePresence3(tc RawTestCase) int {
+	msg := &pb.PresenceMatrix{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+	expected, ok := Expected("presence3/" + tc.Name)
+	if !ok {
+		return 0
+	}
+	want, got := expected.ProtoReflect(), msg.ProtoReflect()
+
+	onWire := map[protowire.Number]int{}
+	for b := tc.Data; len(b) > 0; {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			break
+		}
+		m := protowire.ConsumeFieldValue(num, typ, b[n:])
+		if m < 0 {
+			break
+		}
+		onWire[num]++
+		b = b[n+m:]
+	}
+
+	failures := check(tc.Name, "unknown_fields", len(got.GetUnknown()) == 0)
+	for _, form := range presenceForms {
+		for _, shape := range presenceShapes {
+			fd := presenceField(form, shape.name)
+			name := string(fd.Name())
+			present := want.Has(fd)
+			failures += check(tc.Name, name+".presence", got.Has(fd) == present)
+			if present && got.Has(fd) {
+				failures += check(tc.Name, name, samePresenceValue(fd, want.Get(fd), got.Get(fd)))
+			}
+			// The hand-written case puts fields on the wire that are not
+			// present; the decoder must have dropped them above.
+			if tc.Name != "implicit_zero_on_wire" {
+				wire := 0
+				if present {
+					wire = 1
+				}
+				failures += check(tc.Name, name+".wire", onWire[fd.Number()] == wire)
+			}
+		}
+	}
+	return failures
+}
+
+// samePresenceValue compares a field's values, doubles by their bits so that
+// -0.0 and 0.0 differ.
+func samePresenceValue(fd protoreflect.FieldDescriptor, a, b protoreflect.Value) bool {
+	switch fd.Kind() {
+	case protoreflect.DoubleKind:
+		return math.Float64bits(a.Float()) == math.Float64bits(b.Float())
+	case protoreflect.MessageKind:
+		return proto.Equal(a.Message().Interface(), b.Message().Interface())
+	}
+	return a.Equal(b)
+}
@@ -0,0 +1,54 @@
+package testcases
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Profile is a named size of corpus for cmd/generate: which categories to
+// emit and how many derived vectors to add. CI jobs pick a profile by name,
+// and the manifest records which one produced a directory.
+type Profile struct {
+	Name string
+	// Categories lists the categories to emit; nil means every registered
+	// category.
+	Categories []string
+	// Mutants is the number of mutated vectors derived per case.
+	Mutants int
+	// Delimited also writes each corpus as a protodelim stream.
+	Delimited bool
+}
+
+// DefaultProfile is the profile cmd/generate uses unless told otherwise.
+const DefaultProfile = "standard"
+
+// Profiles lists the generation profiles from smallest to largest.
+var Profiles = []Profile{
+	// smoke keeps to the small scalar, enum, oneof, map and unknown-field
+	// categories, for quick checks on every change.
+	{Name: "smoke", Categories: []string{"enum3", "map3", "oneof3", "required2", "scalar2", "scalar3", "unknown3"}},
+	{Name: "standard"},
+	{Name: "extended", Mutants: 8, Delimited: true},
+	// stress is for nightly runs.
+	{Name: "stress", Mutants: 64, Delimited: true},
+}
+
+// LookupProfile returns the profile called name.
+func LookupProfile(name string) (Profile, error) {
+	for _, p := range Profiles {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	names := make([]string, len(Profiles))
+	for i, p := range Profiles {
+		names[i] = p.Name
+	}
+	return Profile{}, fmt.Errorf("unknown profile %q (have %s)", name, strings.Join(names, ", "))
+}
+
+// Includes reports whether the profile emits the named category.
+func (p Profile) Includes(category string) bool {
+	return p.Categories == nil || slices.Contains(p.Categories, category)
+}
@@ -0,0 +1,82 @@
+package testcases
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// GenerateFunc returns the reference test cases for a corpus category.
+type GenerateFunc func() []TestCase
+
+// ValidateFunc checks a single decoded test case and returns the number of
+// failed assertions.
+type ValidateFunc func(tc RawTestCase) int
+
+// Category pairs a corpus name with its generator and validator.
+type Category struct {
+	Name     string
+	Generate GenerateFunc
+	Validate ValidateFunc
+	// Tags apply to every case of the category; see RegisterTags.
+	Tags []Tag
+}
+
+var registry = map[string]Category{}
+
+// Register adds a corpus category. Category files call it from init so that
+// cmd/generate and cmd/validate pick them up without a hand-maintained list.
+func Register(name string, gen GenerateFunc, validate ValidateFunc) {
+	if _, dup := registry[name]; dup {
+		panic(fmt.Sprintf("testcases: category %q registered twice", name))
+	}
+	registry[name] = Category{Name: name, Generate: gen, Validate: validate}
+}
+
+// Categories returns all registered categories sorted by name.
+func Categories() []Category {
+	cats := make([]Category, 0, len(registry))
+	for _, c := range registry {
+		cats = append(cats, c)
+	}
+	sort.Slice(cats, func(i, j int) bool { return cats[i].Name < cats[j].Name })
+	return cats
+}
+
+// Lookup returns the category registered under name.
+func Lookup(name string) (Category, bool) {
+	c, ok := registry[name]
+	return c, ok
+}
+
+// Expected returns the canonical message for the case named
+// "category/case", freshly built by the category's generator, so tests can
+// decode a vector into a message of the same type and compare the two with
+// proto.Equal.
+func Expected(name string) (proto.Message, bool) {
+	category, caseName, ok := strings.Cut(name, "/")
+	if !ok {
+		return nil, false
+	}
+	c, ok := registry[category]
+	if !ok {
+		return nil, false
+	}
+	for _, tc := range c.Generate() {
+		if tc.Name == caseName {
+			return tc.Msg, true
+		}
+	}
+	return nil, false
+}
+
+func check(name, field string, ok bool) int {
+	assertions = append(assertions, field)
+	if !ok {
+		fmt.Printf("  FAIL %s.%s\n", name, field)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,62 @@
+package testcases_test
+
+import (
+	"sort"
+	"testing"
+
+	"compat/testcases"
+)
+
+// TestRegistry checks that every category is listed once, in order, can be
+// looked up, and generates cases with distinct names that Expected finds.
+func TestRegistry(t *testing.T) {
+	cats := testcases.Categories()
+	if len(cats) == 0 {
+		t.Fatal("no categories registered")
+	}
+	if !sort.SliceIsSorted(cats, func(i, j int) bool { return cats[i].Name < cats[j].Name }) {
+		t.Error("Categories is not sorted by name")
+	}
+	for i, c := range cats {
+		if i > 0 && cats[i-1].Name == c.Name {
+			t.Errorf("%s: listed twice", c.Name)
+		}
+		if c.Generate == nil || c.Validate == nil {
+			t.Errorf("%s: registered without a generator or validator", c.Name)
+			continue
+		}
+		if got, ok := testcases.Lookup(c.Name); !ok || got.Name != c.Name {
+			t.Errorf("Lookup(%q) = %q, %v", c.Name, got.Name, ok)
+		}
+		names := map[string]bool{}
+		for _, tc := range c.Generate() {
+			switch {
+			case tc.Name == "":
+				t.Errorf("%s: case without a name", c.Name)
+			case names[tc.Name]:
+				t.Errorf("%s/%s: generated twice", c.Name, tc.Name)
+			case tc.Msg == nil:
+				t.Errorf("%s/%s: no message", c.Name, tc.Name)
+			}
+			names[tc.Name] = true
+		}
+	}
+	if _, ok := testcases.Lookup("no_such_category"); ok {
+		t.Error("Lookup found an unregistered category")
+	}
+}
+
+// TestRegisterTwice checks that registering a taken name panics instead of
+// replacing the category.
+func TestRegisterTwice(t *testing.T) {
+	c := testcases.Categories()[0]
+	defer func() {
+		if recover() == nil {
+			t.Errorf("registering %s twice did not panic", c.Name)
+		}
+		if got, _ := testcases.Lookup(c.Name); got.Generate == nil {
+			t.Errorf("%s was replaced", c.Name)
+		}
+	}()
+	testcases.Register(c.Name, nil, nil)
+}
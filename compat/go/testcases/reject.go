@@ -0,0 +1,193 @@
+package testcases
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// RejectDir is the corpus subdirectory holding, per category, wire data
+// that every conforming decoder must reject as a message of the category's
+// type. No encoder produces these, so the Zig side only reads them.
+const RejectDir = "reject"
+
+// ErrorCategory is why a decoder must reject a malformed vector. Reject
+// case names start with their category and a slash, as mutant names start
+// with their tag, so that a reader can check it failed for the right reason
+// and not merely failed.
+type ErrorCategory string
+
+const (
+	// ErrTruncated is input that ends inside a field, or a length that
+	// runs past the end of the data or of the enclosing message.
+	ErrTruncated ErrorCategory = "truncated"
+	// ErrOverflow is a varint longer than ten bytes or larger than 64 bits.
+	ErrOverflow ErrorCategory = "overflow"
+	// ErrBadWireType is a tag with wire type 6 or 7.
+	ErrBadWireType ErrorCategory = "bad-wire-type"
+	// ErrBadFieldNumber is field number 0, or an END_GROUP whose number
+	// does not match its START_GROUP.
+	ErrBadFieldNumber ErrorCategory = "bad-field-number"
+	// ErrUTF8 is a proto3 string field holding invalid UTF-8.
+	ErrUTF8 ErrorCategory = "utf8"
+	// ErrRecursionLimit is nesting deeper than any decoder's default limit.
+	ErrRecursionLimit ErrorCategory = "recursion-limit"
+	// ErrRequiredMissing is a proto2 message without a required field.
+	ErrRequiredMissing ErrorCategory = "required-missing"
+)
+
+// ErrorCategories lists every category, in the order reports use.
+var ErrorCategories = []ErrorCategory{
+	ErrTruncated, ErrOverflow, ErrBadWireType, ErrBadFieldNumber,
+	ErrUTF8, ErrRecursionLimit, ErrRequiredMissing,
+}
+
+// RejectCase is a malformed vector and the category it must be rejected
+// with.
+type RejectCase struct {
+	Name  string
+	Data  []byte
+	Error ErrorCategory
+}
+
+// TaggedName is the case's name as framed in the reject corpus:
+// "<category>/<name>".
+func (tc RejectCase) TaggedName() string {
+	return string(tc.Error) + "/" + tc.Name
+}
+
+// ParseRejectName splits a framed reject case name into its category and
+// name. ok is false if the name carries no known category.
+func ParseRejectName(framed string) (cat ErrorCategory, name string, ok bool) {
+	prefix, name, found := strings.Cut(framed, "/")
+	for _, c := range ErrorCategories {
+		if found && prefix == string(c) {
+			return c, name, true
+		}
+	}
+	return "", framed, false
+}
+
+var rejectCategories = map[string]func() []RejectCase{}
+
+// RegisterRejects adds malformed vectors to an already registered category.
+func RegisterRejects(name string, gen func() []RejectCase) {
+	if _, ok := registry[name]; !ok {
+		panic(fmt.Sprintf("testcases: RegisterRejects(%q) before Register", name))
+	}
+	rejectCategories[name] = gen
+}
+
+// Rejects returns the category's malformed vectors, or nil if it has none.
+func Rejects(name string) []RejectCase {
+	if gen, ok := rejectCategories[name]; ok {
+		return gen()
+	}
+	return nil
+}
+
+// BuildRejectCorpus frames every malformed vector of the category under its
+// tagged name.
+func BuildRejectCorpus(name string) ([]byte, int, error) {
+	var buf bytes.Buffer
+	if err := WriteCorpusHeader(&buf); err != nil {
+		return nil, 0, err
+	}
+	cases := Rejects(name)
+	for _, tc := range cases {
+		if err := WriteTestCaseRaw(&buf, tc.TaggedName(), tc.Data); err != nil {
+			return nil, 0, err
+		}
+	}
+	return buf.Bytes(), len(cases), nil
+}
+
+// CheckReject decodes tc as the category's type and fails if the decoder
+// accepts it, so that a vector meant to be malformed really is. Only
+// ErrRequiredMissing vectors are decoded with required fields checked, so
+// the others must be malformed on the wire.
+func CheckReject(c Category, tc RejectCase) int {
+	cases := c.Generate()
+	if len(cases) == 0 {
+		return 0
+	}
+	msg := cases[0].Msg.ProtoReflect().New().Interface()
+	opts := proto.UnmarshalOptions{AllowPartial: tc.Error != ErrRequiredMissing}
+	return check(tc.Name, "rejected", opts.Unmarshal(tc.Data, msg) != nil)
+}
+
+// RejectResultsName is the file, next to a reader's corpus, recording how
+// it handled each reject vector.
+const RejectResultsName = "reject_results.jsonl"
+
+// RejectResult is one line of a reject results file: the reader decoded
+// Case, the tagged name of a vector in the reject corpus of Corpus, and
+// either failed with Error or, if Error is empty, accepted it.
+type RejectResult struct {
+	Corpus string        `json:"corpus"`
+	Case   string        `json:"case"`
+	Error  ErrorCategory `json:"error"`
+}
+
+// ReadRejectResults parses a reject results file, one JSON object a line.
+func ReadRejectResults(r io.Reader) ([]RejectResult, error) {
+	var results []RejectResult
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	for {
+		var res RejectResult
+		if err := dec.Decode(&res); errors.Is(err, io.EOF) {
+			return results, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("reject result %d: %w", len(results)+1, err)
+		}
+		results = append(results, res)
+	}
+}
+
+// CheckRejectResults fails every reject vector that results do not show
+// rejected with its category, including those they do not mention, and
+// records each vector's outcome in res.
+func CheckRejectResults(results []RejectResult, res *Results) int {
+	got := make(map[[2]string]RejectResult, len(results))
+	for _, r := range results {
+		got[[2]string{r.Corpus, r.Case}] = r
+	}
+	failures := 0
+	for _, c := range Categories() {
+		for _, tc := range Rejects(c.Name) {
+			r, ok := got[[2]string{c.Name, tc.TaggedName()}]
+			var detail string
+			switch {
+			case !ok:
+				detail = "no result"
+			case r.Error == "":
+				detail = fmt.Sprintf("accepted, want %s", tc.Error)
+			case r.Error != tc.Error:
+				detail = fmt.Sprintf("rejected as %s, want %s", r.Error, tc.Error)
+			}
+			cr := &pb.CaseResult{
+				Direction:     DirectionReject,
+				Category:      c.Name,
+				Name:          tc.TaggedName(),
+				Status:        pb.CaseStatus_CASE_STATUS_PASS,
+				ErrorCategory: string(r.Error),
+				Detail:        detail,
+			}
+			if detail != "" {
+				fmt.Printf("  FAIL %s/%s: %s\n", c.Name, tc.TaggedName(), detail)
+				cr.Status = pb.CaseStatus_CASE_STATUS_FAIL
+				failures++
+			}
+			res.Add(cr)
+		}
+	}
+	return failures
+}
@@ -1,9 +1,70 @@
 package testcases
 
-import "compat/pb"
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("repeated3", GenerateRepeated3, validateRepeated3)
+}
+
+// repeatedMixed are wire sequences in which one repeated field arrives partly
+// packed and partly as single tagged values, between other fields. Parsers
+// must accept both forms for any packable field and append the values in
+// wire order, so the field reads as if it had been encoded one way.
+var repeatedMixed = []struct {
+	name   string
+	fields [][]byte
+	want   *pb.RepeatedMessage
+}{
+	{"mixed_ints", [][]byte{
+		packedVarints(1, 1, 2), repeatedString("x"), varintField(1, 3),
+		packedDoubles(1.5), varintField(1, 4), packedVarints(1, 5, 6),
+	}, &pb.RepeatedMessage{Ints: []int32{1, 2, 3, 4, 5, 6}, Strings: []string{"x"}, Doubles: []float64{1.5}}},
+	{"mixed_ints_negative", [][]byte{
+		varintField(1, negVarint(-1)), packedVarints(1, negVarint(-2), 3), varintField(1, negVarint(math.MinInt32)),
+	}, &pb.RepeatedMessage{Ints: []int32{-1, -2, 3, math.MinInt32}}},
+	{"mixed_doubles_bools", [][]byte{
+		repeatedDouble(0.5), packedVarints(1, 7), packedDoubles(1.5, 2.5), varintField(4, 1),
+		repeatedDouble(3.5), packedVarints(4, 0, 1),
+	}, &pb.RepeatedMessage{Ints: []int32{7}, Doubles: []float64{0.5, 1.5, 2.5, 3.5}, Bools: []bool{true, false, true}}},
+	// An empty packed run adds nothing but must not reset the field.
+	{"mixed_empty_packed", [][]byte{
+		varintField(1, 8), packedVarints(1), varintField(1, 9), packedVarints(1),
+	}, &pb.RepeatedMessage{Ints: []int32{8, 9}}},
+}
+
+// negVarint is the varint encoding of a negative int32, sign-extended to 64
+// bits as for int64.
+func negVarint(v int32) uint64 {
+	return uint64(int64(v))
+}
+
+func repeatedString(v string) []byte {
+	return protowire.AppendString(protowire.AppendTag(nil, 2, protowire.BytesType), v)
+}
+
+func repeatedDouble(v float64) []byte {
+	return protowire.AppendFixed64(protowire.AppendTag(nil, 3, protowire.Fixed64Type), math.Float64bits(v))
+}
+
+func packedDoubles(vs ...float64) []byte {
+	var body []byte
+	for _, v := range vs {
+		body = protowire.AppendFixed64(body, math.Float64bits(v))
+	}
+	return protowire.AppendBytes(protowire.AppendTag(nil, 3, protowire.BytesType), body)
+}
 
 func GenerateRepeated3() []TestCase {
-	return []TestCase{
+	cases := []TestCase{
 		{
 			Name: "empty",
 			Msg:  &pb.RepeatedMessage{},
@@ -33,5 +94,153 @@ func GenerateRepeated3() []TestCase {
 				},
 			},
 		},
+		{
+			Name: "packed_large",
+			Msg:  largeRepeated(),
+		},
+		{
+			Name: "alias_views",
+			Msg:  aliasViews(),
+		},
+		{
+			// Packed bools other than 0 and 1 all read as true.
+			Name: "packed_bools_nonzero",
+			Msg:  &pb.RepeatedMessage{Bools: []bool{true, false, true, true}},
+			Wire: packedVarints(4, 2, 0, 255, 1<<32),
+		},
+	}
+	for _, m := range repeatedMixed {
+		var wire []byte
+		for _, f := range m.fields {
+			wire = append(wire, f...)
+		}
+		cases = append(cases, TestCase{Name: m.name, Msg: m.want, Wire: wire})
+	}
+	return cases
+}
+
+// largeRepeated fills the packed double and bool fields with largeCount
+// values, as largeFixed does for packed3.
+func largeRepeated() *pb.RepeatedMessage {
+	m := &pb.RepeatedMessage{}
+	for i := range largeCount {
+		m.Doubles = append(m.Doubles, float64(i)/8-50)
+		m.Bools = append(m.Bools, i%3 == 0)
+	}
+	return m
+}
+
+// aliasViewCount is how many strings and byte slices alias_views holds.
+const aliasViewCount = 256
+
+// aliasViews fills the string and bytes fields with overlapping windows of
+// one buffer, so the encoding holds many short fields at different offsets.
+// A decoder that keeps views into its input instead of copying is caught by
+// CheckOwnership.
+func aliasViews() *pb.RepeatedMessage {
+	buf := make([]byte, 4096)
+	for i := range buf {
+		buf[i] = byte('a' + i%26)
+	}
+	s := string(buf)
+	m := &pb.RepeatedMessage{}
+	for i := range aliasViewCount {
+		off, n := i*13%len(buf), 1+i%40
+		end := min(off+n, len(buf))
+		m.Strings = append(m.Strings, s[off:end])
+		m.ByteSlices = append(m.ByteSlices, buf[off:end:end])
+		m.Items = append(m.Items, &pb.RepItem{Id: int32(i), Name: s[end-1 : end]})
+	}
+	return m
+}
+
+func validateRepeated3(tc RawTestCase) int {
+	msg := &pb.RepeatedMessage{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "empty":
+		failures += check(tc.Name, "ints.len", len(msg.Ints) == 0)
+		failures += check(tc.Name, "strings.len", len(msg.Strings) == 0)
+		failures += check(tc.Name, "doubles.len", len(msg.Doubles) == 0)
+		failures += check(tc.Name, "bools.len", len(msg.Bools) == 0)
+		failures += check(tc.Name, "byte_slices.len", len(msg.ByteSlices) == 0)
+		failures += check(tc.Name, "items.len", len(msg.Items) == 0)
+	case "single":
+		failures += check(tc.Name, "ints.len", len(msg.Ints) == 1)
+		if len(msg.Ints) == 1 {
+			failures += check(tc.Name, "ints[0]", msg.Ints[0] == 1)
+		}
+		failures += check(tc.Name, "strings.len", len(msg.Strings) == 1)
+		if len(msg.Strings) == 1 {
+			failures += check(tc.Name, "strings[0]", msg.Strings[0] == "hello")
+		}
+		failures += check(tc.Name, "doubles.len", len(msg.Doubles) == 1)
+		if len(msg.Doubles) == 1 {
+			failures += check(tc.Name, "doubles[0]", msg.Doubles[0] == 1.5)
+		}
+		failures += check(tc.Name, "bools.len", len(msg.Bools) == 1)
+		if len(msg.Bools) == 1 {
+			failures += check(tc.Name, "bools[0]", msg.Bools[0] == true)
+		}
+		failures += check(tc.Name, "byte_slices.len", len(msg.ByteSlices) == 1)
+		if len(msg.ByteSlices) == 1 {
+			failures += check(tc.Name, "byte_slices[0]", len(msg.ByteSlices[0]) == 1 && msg.ByteSlices[0][0] == 0x01)
+		}
+		failures += check(tc.Name, "items.len", len(msg.Items) == 1)
+		if len(msg.Items) == 1 {
+			failures += check(tc.Name, "items[0].id", msg.Items[0].Id == 1)
+			failures += check(tc.Name, "items[0].name", msg.Items[0].Name == "first")
+		}
+	case "multiple":
+		failures += check(tc.Name, "ints.len", len(msg.Ints) == 3)
+		if len(msg.Ints) == 3 {
+			failures += check(tc.Name, "ints[0]", msg.Ints[0] == 1)
+			failures += check(tc.Name, "ints[1]", msg.Ints[1] == 2)
+			failures += check(tc.Name, "ints[2]", msg.Ints[2] == 3)
+		}
+		failures += check(tc.Name, "strings.len", len(msg.Strings) == 3)
+		if len(msg.Strings) == 3 {
+			failures += check(tc.Name, "strings[0]", msg.Strings[0] == "a")
+			failures += check(tc.Name, "strings[1]", msg.Strings[1] == "b")
+			failures += check(tc.Name, "strings[2]", msg.Strings[2] == "c")
+		}
+		failures += check(tc.Name, "doubles.len", len(msg.Doubles) == 3)
+		failures += check(tc.Name, "bools.len", len(msg.Bools) == 3)
+		if len(msg.Bools) == 3 {
+			failures += check(tc.Name, "bools[0]", msg.Bools[0] == true)
+			failures += check(tc.Name, "bools[1]", msg.Bools[1] == false)
+			failures += check(tc.Name, "bools[2]", msg.Bools[2] == true)
+		}
+		failures += check(tc.Name, "byte_slices.len", len(msg.ByteSlices) == 2)
+		failures += check(tc.Name, "items.len", len(msg.Items) == 2)
+		if len(msg.Items) == 2 {
+			failures += check(tc.Name, "items[0].id", msg.Items[0].Id == 1)
+			failures += check(tc.Name, "items[0].name", msg.Items[0].Name == "one")
+			failures += check(tc.Name, "items[1].id", msg.Items[1].Id == 2)
+			failures += check(tc.Name, "items[1].name", msg.Items[1].Name == "two")
+		}
+	case "packed_large":
+		want := largeRepeated()
+		failures += check(tc.Name, "doubles", slices.Equal(msg.Doubles, want.Doubles))
+		failures += check(tc.Name, "bools", slices.Equal(msg.Bools, want.Bools))
+	case "alias_views":
+		failures += check(tc.Name, "alias_views", proto.Equal(msg, aliasViews()))
+	case "packed_bools_nonzero":
+		failures += check(tc.Name, "bools", slices.Equal(msg.Bools, []bool{true, false, true, true}))
+	default:
+		for _, m := range repeatedMixed {
+			if m.name == tc.Name {
+				failures += check(tc.Name, "ints", slices.Equal(msg.Ints, m.want.Ints))
+				failures += check(tc.Name, "strings", slices.Equal(msg.Strings, m.want.Strings))
+				failures += check(tc.Name, "doubles", slices.Equal(msg.Doubles, m.want.Doubles))
+				failures += check(tc.Name, "bools", slices.Equal(msg.Bools, m.want.Bools))
+			}
+		}
 	}
+	return failures
 }
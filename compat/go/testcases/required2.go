@@ -1,6 +1,19 @@
 package testcases
 
-import "compat/pb"
+import (
+	"fmt"
+
+	"compat/pb"
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("required2", GenerateRequired2, validateRequired2)
+	RegisterTags("required2", TagProto2)
+	RegisterRejects("required2", requiredRejectCases)
+}
 
 func GenerateRequired2() []TestCase {
 	return []TestCase{
@@ -22,3 +35,43 @@ func GenerateRequired2() []TestCase {
 		},
 	}
 }
+
+// requiredRejectCases are well-formed on the wire but leave out a required
+// field. They are only rejected by a decoder that checks required fields.
+func requiredRejectCases() []RejectCase {
+	partial := func(m *pb.Required2Message) []byte {
+		b, err := pbutil.MarshalPartial(m)
+		if err != nil {
+			panic(err)
+		}
+		return b
+	}
+	return []RejectCase{
+		{Name: "empty", Data: nil, Error: ErrRequiredMissing},
+		{Name: "missing_id", Data: partial(&pb.Required2Message{ReqName: proto_string("no id")}), Error: ErrRequiredMissing},
+		{Name: "missing_name", Data: partial(&pb.Required2Message{ReqId: proto_int32(1), OptLabel: proto_string("no name")}), Error: ErrRequiredMissing},
+	}
+}
+
+func validateRequired2(tc RawTestCase) int {
+	msg := &pb.Required2Message{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "all_present":
+		failures += check(tc.Name, "req_id", msg.ReqId != nil && *msg.ReqId == 42)
+		failures += check(tc.Name, "req_name", msg.ReqName != nil && *msg.ReqName == "required")
+		failures += check(tc.Name, "opt_value", msg.OptValue != nil && *msg.OptValue == 10)
+		failures += check(tc.Name, "opt_label", msg.OptLabel != nil && *msg.OptLabel == "optional")
+	case "required_only":
+		failures += check(tc.Name, "req_id", msg.ReqId != nil && *msg.ReqId == 1)
+		failures += check(tc.Name, "req_name", msg.ReqName != nil && *msg.ReqName == "min")
+		failures += check(tc.Name, "opt_value", msg.OptValue == nil)
+		failures += check(tc.Name, "opt_label", msg.OptLabel == nil)
+	}
+	return failures
+}
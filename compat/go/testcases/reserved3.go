@@ -0,0 +1,162 @@
+package testcases
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"compat/pb"
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func init() {
+	Register("reserved3", GenerateReserved3, validateReserved3)
+}
+
+// ReservedDescriptorSetName is the file, next to the corpus, holding the
+// descriptor set of reserved3.proto, so that other generators' handling of
+// reserved declarations can be compared with protoc's.
+const ReservedDescriptorSetName = "reserved3.desc"
+
+// reservedFields are wire bytes on numbers ReservedMessage reserves. A
+// decoder must keep them as unknown fields, however the number was reserved.
+var reservedFields = []struct {
+	name string
+	raw  []byte
+}{
+	{"reserved_single", unknownField(2, protowire.VarintType, protowire.AppendVarint(nil, 7))},
+	{"reserved_range", bytes.Join([][]byte{
+		unknownField(5, protowire.BytesType, protowire.AppendString(nil, "legacy")),
+		unknownField(6, protowire.Fixed32Type, protowire.AppendFixed32(nil, 6)),
+		unknownField(7, protowire.Fixed64Type, protowire.AppendFixed64(nil, 7)),
+	}, nil)},
+	{"reserved_to_max", bytes.Join([][]byte{
+		unknownField(1000, protowire.VarintType, protowire.AppendVarint(nil, 1000)),
+		unknownField(protowire.MaxValidNumber, protowire.BytesType, protowire.AppendString(nil, "max")),
+	}, nil)},
+}
+
+// reservedInterleaved puts data on reserved numbers between the declared
+// fields. Decoding gathers the reserved fields, in order, into the unknown
+// fields.
+var reservedInterleaved = bytes.Join([][]byte{
+	protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), 11),
+	unknownField(2, protowire.VarintType, protowire.AppendVarint(nil, 2)),
+	protowire.AppendString(protowire.AppendTag(nil, 3, protowire.BytesType), "kept"),
+	unknownField(6, protowire.Fixed32Type, protowire.AppendFixed32(nil, 6)),
+	packedVarints(4, 1, 2),
+}, nil)
+
+func GenerateReserved3() []TestCase {
+	cases := []TestCase{
+		{Name: "empty", Msg: &pb.ReservedMessage{}},
+		{Name: "declared", Msg: &pb.ReservedMessage{Id: 1, Label: "declared", Values: []int32{1, 2, 3}}},
+	}
+	for _, f := range reservedFields {
+		m := &pb.ReservedMessage{Id: 1}
+		m.ProtoReflect().SetUnknown(f.raw)
+		cases = append(cases, TestCase{Name: f.name, Msg: m})
+	}
+	want := &pb.ReservedMessage{Id: 11, Label: "kept", Values: []int32{1, 2}}
+	want.ProtoReflect().SetUnknown(append(
+		unknownField(2, protowire.VarintType, protowire.AppendVarint(nil, 2)),
+		unknownField(6, protowire.Fixed32Type, protowire.AppendFixed32(nil, 6))...))
+	cases = append(cases, TestCase{Name: "interleaved", Msg: want, Wire: reservedInterleaved})
+	return cases
+}
+
+func validateReserved3(tc RawTestCase) int {
+	msg := &pb.ReservedMessage{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	unknown := msg.ProtoReflect().GetUnknown()
+	switch tc.Name {
+	case "empty":
+		failures += check(tc.Name, "id", msg.Id == 0)
+		failures += check(tc.Name, "unknown_fields", len(unknown) == 0)
+	case "declared":
+		failures += check(tc.Name, "id", msg.Id == 1)
+		failures += check(tc.Name, "label", msg.Label == "declared")
+		failures += check(tc.Name, "values", slices.Equal(msg.Values, []int32{1, 2, 3}))
+		failures += check(tc.Name, "unknown_fields", len(unknown) == 0)
+	case "interleaved":
+		failures += check(tc.Name, "id", msg.Id == 11)
+		failures += check(tc.Name, "label", msg.Label == "kept")
+		failures += check(tc.Name, "values", slices.Equal(msg.Values, []int32{1, 2}))
+		failures += check(tc.Name, "unknown_fields", bytes.Equal(unknown, append(
+			unknownField(2, protowire.VarintType, protowire.AppendVarint(nil, 2)),
+			unknownField(6, protowire.Fixed32Type, protowire.AppendFixed32(nil, 6))...)))
+	default:
+		for _, f := range reservedFields {
+			if f.name == tc.Name {
+				failures += check(tc.Name, "id", msg.Id == 1)
+				failures += check(tc.Name, "unknown_fields", bytes.Equal(unknown, f.raw))
+			}
+		}
+	}
+	return failures
+}
+
+// ReservedDescriptorSet returns the serialized FileDescriptorSet of
+// reserved3.proto.
+func ReservedDescriptorSet() ([]byte, error) {
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(pb.File_reserved3_proto),
+	}}
+	return pbutil.Marshal(set)
+}
+
+// ValidateReserved checks that a descriptor set records the reserved ranges
+// and names of ReservedMessage as protoc does, with end-exclusive ranges and
+// "max" resolved to the largest field number, and that no field uses them.
+func ValidateReserved(data []byte) int {
+	const name = "reserved3"
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", name, err)
+		return 1
+	}
+	var msg *descriptorpb.DescriptorProto
+	for _, f := range set.GetFile() {
+		if f.GetName() != "reserved3.proto" {
+			continue
+		}
+		for _, m := range f.GetMessageType() {
+			if m.GetName() == "ReservedMessage" {
+				msg = m
+			}
+		}
+	}
+	if check(name, "ReservedMessage", msg != nil) > 0 {
+		return 1
+	}
+
+	var ranges [][2]int32
+	for _, r := range msg.GetReservedRange() {
+		ranges = append(ranges, [2]int32{r.GetStart(), r.GetEnd()})
+	}
+	failures := check(name, "reserved_range", slices.Equal(ranges, [][2]int32{
+		{2, 3}, {5, 8}, {1000, int32(protowire.MaxValidNumber) + 1},
+	}))
+	failures += check(name, "reserved_name", slices.Equal(msg.GetReservedName(), []string{"legacy_name", "old_count"}))
+	for _, f := range msg.GetField() {
+		for _, r := range ranges {
+			if f.GetNumber() >= r[0] && f.GetNumber() < r[1] {
+				failures += check(name, f.GetName()+".number", false)
+			}
+		}
+		if slices.Contains(msg.GetReservedName(), f.GetName()) {
+			failures += check(name, f.GetName()+".name", false)
+		}
+	}
+	return failures
+}
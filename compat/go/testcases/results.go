@@ -0,0 +1,199 @@
+package testcases
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"compat/pb"
+	"compat/pbutil"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Directions a CaseResult is recorded under.
+const (
+	DirectionZigToGo = "zig-to-go"
+	DirectionGoToZig = "go-to-zig"
+	DirectionJSON    = "json"
+	DirectionReject  = "reject"
+)
+
+// RoundTripDir is the subdirectory of the Zig corpus holding, per
+// category, the Zig side's re-encoding of each case of the Go corpus it
+// could decode, under the case's name. DirectionGoToZig compares it with
+// the Go corpus.
+const RoundTripDir = "roundtrip"
+
+// diffContext is how many bytes a ByteDiff window starts before the first
+// difference, and diffWindow how many it holds of each side.
+const (
+	diffContext = 16
+	diffWindow  = 64
+)
+
+// Results collects the CompatResults of a run. A nil *Results records
+// nothing, so callers need not check whether results were asked for.
+type Results struct {
+	start time.Time
+	msg   *pb.CompatResults
+}
+
+// NewResults starts recording a run of command by the Go implementation.
+func NewResults(command string) *Results {
+	start := time.Now()
+	return &Results{start: start, msg: &pb.CompatResults{Run: &pb.RunMetadata{
+		Implementation: "go",
+		Version:        goVersion(),
+		Command:        command,
+		Os:             runtime.GOOS,
+		Arch:           runtime.GOARCH,
+		StartUnixNanos: start.UnixNano(),
+	}}}
+}
+
+// goVersion names the Go toolchain and the protobuf runtime it was built
+// with.
+func goVersion() string {
+	v := runtime.Version()
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "google.golang.org/protobuf" {
+				v += " " + dep.Path + " " + dep.Version
+			}
+		}
+	}
+	return v
+}
+
+// Add records one case.
+func (r *Results) Add(c *pb.CaseResult) {
+	if r != nil {
+		r.msg.Cases = append(r.msg.Cases, c)
+	}
+}
+
+// AddCase records a case that failed n checks, or passed if n is 0, with
+// diff, which may be nil, locating its bytes' first difference from the
+// reference.
+func (r *Results) AddCase(direction, category, name string, n int, d time.Duration, diff *pb.ByteDiff) {
+	c := &pb.CaseResult{
+		Direction:     direction,
+		Category:      category,
+		Name:          name,
+		Status:        pb.CaseStatus_CASE_STATUS_PASS,
+		DurationNanos: d.Nanoseconds(),
+		Diff:          diff,
+	}
+	if n > 0 {
+		c.Status = pb.CaseStatus_CASE_STATUS_FAIL
+		c.Detail = fmt.Sprintf("%d check(s) failed", n)
+	}
+	r.Add(c)
+}
+
+// AddKnownBug records a case tagged with the known Zig bug bug that failed
+// n checks: as XFAIL if it failed, and as failed if it passed, since the
+// tag is then stale.
+func (r *Results) AddKnownBug(direction, category, name string, n int, bug Tag, d time.Duration, diff *pb.ByteDiff) {
+	c := &pb.CaseResult{
+		Direction:     direction,
+		Category:      category,
+		Name:          name,
+		Status:        pb.CaseStatus_CASE_STATUS_XFAIL,
+		Detail:        fmt.Sprintf("%d check(s) failed, as expected with %s", n, bug),
+		DurationNanos: d.Nanoseconds(),
+		Diff:          diff,
+	}
+	if n == 0 {
+		c.Status = pb.CaseStatus_CASE_STATUS_FAIL
+		c.Detail = fmt.Sprintf("passed, but is tagged %s", bug)
+	}
+	r.Add(c)
+}
+
+// AddSkipped records a case that was not run, saying why.
+func (r *Results) AddSkipped(direction, category, name, detail string) {
+	r.Add(&pb.CaseResult{Direction: direction, Category: category, Name: name, Status: pb.CaseStatus_CASE_STATUS_SKIP, Detail: detail})
+}
+
+// AddFile records a corpus file that could not be validated as one result
+// with no case name: skipped if the file is missing, failed otherwise.
+func (r *Results) AddFile(direction, category string, failed bool, detail string) {
+	status := pb.CaseStatus_CASE_STATUS_SKIP
+	if failed {
+		status = pb.CaseStatus_CASE_STATUS_FAIL
+	}
+	r.Add(&pb.CaseResult{Direction: direction, Category: category, Status: status, Detail: detail})
+}
+
+// Finish stamps the run's duration and returns what was recorded.
+func (r *Results) Finish() *pb.CompatResults {
+	r.msg.Run.DurationNanos = time.Since(r.start).Nanoseconds()
+	return r.msg
+}
+
+// DiffBytes locates the first difference between want and got, or returns
+// nil if they are equal. The windows are copies, so the inputs may be
+// released, or unmapped, afterwards.
+func DiffBytes(want, got []byte) *pb.ByteDiff {
+	if bytes.Equal(want, got) {
+		return nil
+	}
+	off := 0
+	for off < len(want) && off < len(got) && want[off] == got[off] {
+		off++
+	}
+	start := max(off-diffContext, 0)
+	window := func(b []byte) []byte {
+		return bytes.Clone(b[min(start, len(b)):min(start+diffWindow, len(b))])
+	}
+	return &pb.ByteDiff{
+		Offset:      uint64(off),
+		WindowStart: uint64(start),
+		Want:        window(want),
+		Got:         window(got),
+		WantLen:     uint64(len(want)),
+		GotLen:      uint64(len(got)),
+	}
+}
+
+// WriteResults writes m to path, as proto3 JSON if the name ends in .json
+// and in the binary encoding otherwise.
+func WriteResults(path string, m *pb.CompatResults) error {
+	var data []byte
+	var err error
+	if filepath.Ext(path) == ".json" {
+		data, err = protojson.MarshalOptions{Multiline: true}.Marshal(m)
+	} else {
+		data, err = pbutil.Marshal(m)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ReadResults reads a file written by WriteResults or by the Zig side,
+// choosing the encoding by name as WriteResults does.
+func ReadResults(path string) (*pb.CompatResults, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &pb.CompatResults{}
+	if filepath.Ext(path) == ".json" {
+		err = protojson.Unmarshal(data, m)
+	} else {
+		err = proto.Unmarshal(data, m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
@@ -0,0 +1,75 @@
+package testcases_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"compat/pb"
+	"compat/testcases"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDiffBytes(t *testing.T) {
+	if d := testcases.DiffBytes([]byte{1, 2, 3}, []byte{1, 2, 3}); d != nil {
+		t.Errorf("equal bytes: got %v, want nil", d)
+	}
+	want := bytes.Repeat([]byte{7}, 100)
+	got := append(bytes.Repeat([]byte{7}, 40), 8)
+	d := testcases.DiffBytes(want, got)
+	if d.GetOffset() != 40 || d.GetWindowStart() != 24 || d.GetWantLen() != 100 || d.GetGotLen() != 41 {
+		t.Errorf("got %v", d)
+	}
+	if len(d.GetWant()) != 64 || !bytes.Equal(d.GetGot(), got[24:]) {
+		t.Errorf("windows: want %d bytes, got %x", len(d.GetWant()), d.GetGot())
+	}
+	// A prefix differs where the shorter input ends.
+	if d := testcases.DiffBytes([]byte{1, 2}, []byte{1, 2, 3}); d.GetOffset() != 2 {
+		t.Errorf("prefix: got %v", d)
+	}
+}
+
+// TestResultsRoundTrip writes results in both encodings and reads them back.
+func TestResultsRoundTrip(t *testing.T) {
+	res := testcases.NewResults("validate -results")
+	res.AddCase(testcases.DirectionZigToGo, "scalar3", "all_set", 0, time.Millisecond, nil)
+	res.AddCase(testcases.DirectionGoToZig, "scalar3", "all_set", 2, time.Millisecond, testcases.DiffBytes([]byte{1}, []byte{2}))
+	res.AddFile(testcases.DirectionJSON, "json3", false, "missing")
+	res.AddKnownBug(testcases.DirectionZigToGo, "strings3", "specials", 1, testcases.KnownZigBug(7), time.Millisecond, nil)
+	res.AddKnownBug(testcases.DirectionZigToGo, "strings3", "combining", 0, testcases.KnownZigBug(7), time.Millisecond, nil)
+	m := res.Finish()
+	if m.GetRun().GetImplementation() != "go" || len(m.GetCases()) != 5 {
+		t.Fatalf("got %v", m)
+	}
+	if c := m.GetCases()[1]; c.GetStatus() != pb.CaseStatus_CASE_STATUS_FAIL || c.GetDiff() == nil {
+		t.Errorf("failed case recorded as %v", c)
+	}
+	if c := m.GetCases()[2]; c.GetStatus() != pb.CaseStatus_CASE_STATUS_SKIP || c.GetName() != "" {
+		t.Errorf("missing file recorded as %v", c)
+	}
+	if c := m.GetCases()[3]; c.GetStatus() != pb.CaseStatus_CASE_STATUS_XFAIL {
+		t.Errorf("known bug that failed recorded as %v", c)
+	}
+	if c := m.GetCases()[4]; c.GetStatus() != pb.CaseStatus_CASE_STATUS_FAIL {
+		t.Errorf("known bug that passed recorded as %v", c)
+	}
+
+	var nilResults *testcases.Results
+	nilResults.AddCase(testcases.DirectionZigToGo, "scalar3", "all_set", 0, 0, nil)
+
+	for _, name := range []string{"results.pb", "results.json"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := testcases.WriteResults(path, m); err != nil {
+			t.Fatal(err)
+		}
+		back, err := testcases.ReadResults(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(back, m) {
+			t.Errorf("%s: read back\n  %v\nwant\n  %v", name, back, m)
+		}
+	}
+}
@@ -1,6 +1,17 @@
 package testcases
 
-import "compat/pb"
+import (
+	"fmt"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("scalar2", GenerateScalar2, validateScalar2)
+	RegisterTags("scalar2", TagProto2)
+}
 
 func GenerateScalar2() []TestCase {
 	return []TestCase{
@@ -35,3 +46,36 @@ func proto_float32(v float32) *float32 { return &v }
 func proto_int64(v int64) *int64       { return &v }
 func proto_uint32(v uint32) *uint32    { return &v }
 func proto_uint64(v uint64) *uint64    { return &v }
+
+func validateScalar2(tc RawTestCase) int {
+	msg := &pb.Scalar2Message{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "all_absent":
+		failures += check(tc.Name, "f_double", msg.FDouble == nil)
+		failures += check(tc.Name, "f_int32", msg.FInt32 == nil)
+		failures += check(tc.Name, "f_string", msg.FString == nil)
+	case "all_set":
+		failures += check(tc.Name, "f_double", msg.FDouble != nil && *msg.FDouble == 1.5)
+		failures += check(tc.Name, "f_float", msg.FFloat != nil && *msg.FFloat == 2.5)
+		failures += check(tc.Name, "f_int32", msg.FInt32 != nil && *msg.FInt32 == 42)
+		failures += check(tc.Name, "f_int64", msg.FInt64 != nil && *msg.FInt64 == 100000)
+		failures += check(tc.Name, "f_uint32", msg.FUint32 != nil && *msg.FUint32 == 200)
+		failures += check(tc.Name, "f_uint64", msg.FUint64 != nil && *msg.FUint64 == 300000)
+		failures += check(tc.Name, "f_sint32", msg.FSint32 != nil && *msg.FSint32 == -10)
+		failures += check(tc.Name, "f_sint64", msg.FSint64 != nil && *msg.FSint64 == -20000)
+		failures += check(tc.Name, "f_fixed32", msg.FFixed32 != nil && *msg.FFixed32 == 999)
+		failures += check(tc.Name, "f_fixed64", msg.FFixed64 != nil && *msg.FFixed64 == 888888)
+		failures += check(tc.Name, "f_sfixed32", msg.FSfixed32 != nil && *msg.FSfixed32 == -55)
+		failures += check(tc.Name, "f_sfixed64", msg.FSfixed64 != nil && *msg.FSfixed64 == -66666)
+		failures += check(tc.Name, "f_bool", msg.FBool != nil && *msg.FBool == true)
+		failures += check(tc.Name, "f_string", msg.FString != nil && *msg.FString == "hello")
+		failures += check(tc.Name, "f_bytes", string(msg.FBytes) == "world")
+	}
+	return failures
+}
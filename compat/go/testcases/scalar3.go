@@ -1,13 +1,43 @@
 package testcases
 
 import (
+	"bytes"
+	"fmt"
 	"math"
+	"strconv"
+	"strings"
 
 	"compat/pb"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
 )
 
+func init() {
+	Register("scalar3", GenerateScalar3, validateScalar3)
+	RegisterRejects("scalar3", scalarRejectCases)
+}
+
+// lengthPrefixBoundaries are the lengths either side of a change in the
+// width of a varint length prefix, where size calculations go off by one.
+var lengthPrefixBoundaries = []int{127, 128, 16383, 16384}
+
+// boolVarints are f_bool values no marshaler writes. Any nonzero varint is
+// true, including one whose low 32 bits are zero; an over-long zero is false.
+var boolVarints = []struct {
+	name string
+	wire []byte
+	want bool
+}{
+	{"bool_varint_2", varintField(13, 2), true},
+	{"bool_varint_255", varintField(13, 255), true},
+	{"bool_varint_multibyte", varintField(13, 1<<32), true},
+	{"bool_varint_top_bit", varintField(13, 1<<63), true},
+	{"bool_overlong_zero", append(protowire.AppendTag(nil, 13, protowire.VarintType), 0x80, 0x00), false},
+}
+
 func GenerateScalar3() []TestCase {
-	return []TestCase{
+	cases := []TestCase{
 		{
 			Name: "all_defaults",
 			Msg:  &pb.ScalarMessage{},
@@ -59,5 +89,194 @@ func GenerateScalar3() []TestCase {
 				FLargeTag: 12345,
 			},
 		},
+		{
+			Name: "zigzag_neg_one",
+			Msg: &pb.ScalarMessage{
+				FSint32: -1,
+				FSint64: -1,
+			},
+		},
+		{
+			Name: "zigzag_min",
+			Msg: &pb.ScalarMessage{
+				FSint32: math.MinInt32,
+				FSint64: math.MinInt64,
+			},
+		},
+		{
+			Name: "zigzag_max",
+			Msg: &pb.ScalarMessage{
+				FSint32: math.MaxInt32,
+				FSint64: math.MaxInt64,
+			},
+		},
+		{
+			Name: "negative_zero",
+			Msg: &pb.ScalarMessage{
+				FDouble: math.Copysign(0, -1),
+				FFloat:  float32(math.Copysign(0, -1)),
+			},
+		},
+		{
+			Name: "denormals",
+			Msg: &pb.ScalarMessage{
+				FDouble: math.SmallestNonzeroFloat64,
+				FFloat:  math.SmallestNonzeroFloat32,
+			},
+		},
+		{
+			Name: "nan_payload",
+			Msg: &pb.ScalarMessage{
+				FDouble: math.Float64frombits(nanPayload64),
+				FFloat:  math.Float32frombits(nanPayload32),
+			},
+		},
+		{
+			// Values a float32/float64 mix-up would change: 2^24+1 and
+			// 1e39 don't survive a round trip through float32, and 0.1
+			// has different bits as a float32 and as a float64.
+			Name: "float_conversion",
+			Msg: &pb.ScalarMessage{
+				FDouble: 16777217,
+				FFloat:  0.1,
+			},
+		},
+		{
+			Name: "double_beyond_float32",
+			Msg: &pb.ScalarMessage{
+				FDouble: 1e39,
+			},
+		},
+	}
+	for _, n := range lengthPrefixBoundaries {
+		cases = append(cases, TestCase{
+			Name: fmt.Sprintf("length_%d", n),
+			Msg: &pb.ScalarMessage{
+				FString: strings.Repeat("s", n),
+				FBytes:  bytes.Repeat([]byte{0xb5}, n),
+			},
+		})
+	}
+	for _, b := range boolVarints {
+		cases = append(cases, TestCase{Name: b.name, Msg: &pb.ScalarMessage{FBool: b.want}, Wire: b.wire})
+	}
+	return cases
+}
+
+// NaNs with payload bits set, the double one also sign-negative, so that
+// canonicalizing NaNs or dropping the sign is caught.
+const (
+	nanPayload64 = 0xfff8_0000_dead_beef
+	nanPayload32 = 0x7fc0_1234
+)
+
+// scalarRejectCases break one rule of the wire format each, at the level
+// of a single scalar field, so that every category but the structural ones
+// has a vector a decoder can be checked against.
+func scalarRejectCases() []RejectCase {
+	tag := func(num protowire.Number, typ protowire.Type, rest ...byte) []byte {
+		return append(protowire.AppendTag(nil, num, typ), rest...)
+	}
+	str := func(s string) []byte {
+		return protowire.AppendString(protowire.AppendTag(nil, 14, protowire.BytesType), s)
+	}
+	return []RejectCase{
+		{Name: "varint_unterminated", Data: tag(3, protowire.VarintType, 0x80, 0x80), Error: ErrTruncated},
+		{Name: "fixed32_short", Data: tag(9, protowire.Fixed32Type, 1, 2, 3), Error: ErrTruncated},
+		{Name: "fixed64_short", Data: tag(10, protowire.Fixed64Type, 1, 2, 3, 4, 5, 6, 7), Error: ErrTruncated},
+		{Name: "string_short", Data: tag(14, protowire.BytesType, 5, 'a', 'b'), Error: ErrTruncated},
+		{Name: "tag_unterminated", Data: []byte{0x80}, Error: ErrTruncated},
+		{Name: "varint_eleven_bytes", Data: tag(6, protowire.VarintType, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01), Error: ErrOverflow},
+		{Name: "varint_tenth_byte", Data: tag(6, protowire.VarintType, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x02), Error: ErrOverflow},
+		{Name: "wire_type_6", Data: tag(3, 6, 1), Error: ErrBadWireType},
+		{Name: "wire_type_7", Data: tag(3, 7, 1), Error: ErrBadWireType},
+		{Name: "field_number_zero", Data: tag(0, protowire.VarintType, 1), Error: ErrBadFieldNumber},
+		{Name: "string_invalid_byte", Data: str("ok\xff"), Error: ErrUTF8},
+		{Name: "string_overlong", Data: str("\xc0\xaf"), Error: ErrUTF8},
+		{Name: "string_surrogate", Data: str("\xed\xa0\x80"), Error: ErrUTF8},
+		{Name: "string_truncated_sequence", Data: str("caf\xc3"), Error: ErrUTF8},
+	}
+}
+
+// sameBits compares floats by bit pattern: unlike ==, it tells -0 from 0 and
+// one NaN from another.
+func sameBits(got float64, want uint64) bool { return math.Float64bits(got) == want }
+
+func sameBits32(got float32, want uint32) bool { return math.Float32bits(got) == want }
+
+func validateScalar3(tc RawTestCase) int {
+	msg := &pb.ScalarMessage{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	failures := 0
+	switch tc.Name {
+	case "all_defaults":
+		failures += check(tc.Name, "f_double", msg.FDouble == 0)
+		failures += check(tc.Name, "f_int32", msg.FInt32 == 0)
+		failures += check(tc.Name, "f_bool", msg.FBool == false)
+	case "all_set":
+		failures += check(tc.Name, "f_double", msg.FDouble == 1.5)
+		failures += check(tc.Name, "f_float", msg.FFloat == 2.5)
+		failures += check(tc.Name, "f_int32", msg.FInt32 == 42)
+		failures += check(tc.Name, "f_int64", msg.FInt64 == 100000)
+		failures += check(tc.Name, "f_uint32", msg.FUint32 == 200)
+		failures += check(tc.Name, "f_uint64", msg.FUint64 == 300000)
+		failures += check(tc.Name, "f_sint32", msg.FSint32 == -10)
+		failures += check(tc.Name, "f_sint64", msg.FSint64 == -20000)
+		failures += check(tc.Name, "f_fixed32", msg.FFixed32 == 999)
+		failures += check(tc.Name, "f_fixed64", msg.FFixed64 == 888888)
+		failures += check(tc.Name, "f_sfixed32", msg.FSfixed32 == -55)
+		failures += check(tc.Name, "f_sfixed64", msg.FSfixed64 == -66666)
+		failures += check(tc.Name, "f_bool", msg.FBool == true)
+		failures += check(tc.Name, "f_string", msg.FString == "hello")
+		failures += check(tc.Name, "f_bytes", string(msg.FBytes) == "world")
+		failures += check(tc.Name, "f_large_tag", msg.FLargeTag == 77)
+	case "max_values":
+		failures += check(tc.Name, "f_int32", msg.FInt32 == math.MaxInt32)
+		failures += check(tc.Name, "f_int64", msg.FInt64 == math.MaxInt64)
+		failures += check(tc.Name, "f_uint32", msg.FUint32 == math.MaxUint32)
+		failures += check(tc.Name, "f_uint64", msg.FUint64 == math.MaxUint64)
+	case "min_values":
+		failures += check(tc.Name, "f_int32", msg.FInt32 == math.MinInt32)
+		failures += check(tc.Name, "f_int64", msg.FInt64 == math.MinInt64)
+	case "large_tag_only":
+		failures += check(tc.Name, "f_large_tag", msg.FLargeTag == 12345)
+	case "zigzag_neg_one":
+		failures += check(tc.Name, "f_sint32", msg.FSint32 == -1)
+		failures += check(tc.Name, "f_sint64", msg.FSint64 == -1)
+	case "zigzag_min":
+		failures += check(tc.Name, "f_sint32", msg.FSint32 == math.MinInt32)
+		failures += check(tc.Name, "f_sint64", msg.FSint64 == math.MinInt64)
+	case "zigzag_max":
+		failures += check(tc.Name, "f_sint32", msg.FSint32 == math.MaxInt32)
+		failures += check(tc.Name, "f_sint64", msg.FSint64 == math.MaxInt64)
+	case "negative_zero":
+		failures += check(tc.Name, "f_double", sameBits(msg.FDouble, 0x8000_0000_0000_0000))
+		failures += check(tc.Name, "f_float", sameBits32(msg.FFloat, 0x8000_0000))
+	case "denormals":
+		failures += check(tc.Name, "f_double", sameBits(msg.FDouble, 1))
+		failures += check(tc.Name, "f_float", sameBits32(msg.FFloat, 1))
+	case "nan_payload":
+		failures += check(tc.Name, "f_double", sameBits(msg.FDouble, nanPayload64))
+		failures += check(tc.Name, "f_float", sameBits32(msg.FFloat, nanPayload32))
+	case "float_conversion":
+		failures += check(tc.Name, "f_double", sameBits(msg.FDouble, 0x4170_0000_1000_0000))
+		failures += check(tc.Name, "f_float", sameBits32(msg.FFloat, 0x3dcc_cccd))
+	case "double_beyond_float32":
+		failures += check(tc.Name, "f_double", sameBits(msg.FDouble, 0x4807_8287_f49c_4a1d))
+	case "length_127", "length_128", "length_16383", "length_16384":
+		n, _ := strconv.Atoi(strings.TrimPrefix(tc.Name, "length_"))
+		failures += check(tc.Name, "f_string", msg.FString == strings.Repeat("s", n))
+		failures += check(tc.Name, "f_bytes", bytes.Equal(msg.FBytes, bytes.Repeat([]byte{0xb5}, n)))
+	default:
+		for _, b := range boolVarints {
+			if b.name == tc.Name {
+				failures += check(tc.Name, "f_bool", msg.FBool == b.want)
+			}
+		}
 	}
+	return failures
 }
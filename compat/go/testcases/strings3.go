@@ -0,0 +1,128 @@
+package testcases
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("strings3", GenerateStrings3, validateStrings3)
+}
+
+// stringRunBytes is the length of each run case.
+const stringRunBytes = 1 << 20
+
+// stringEdges are the contents of the strings3 cases other than the runs.
+var stringEdges = []struct {
+	name string
+	text string
+}{
+	{"nul_only", "\x00"},
+	{"nul_embedded", "a\x00b\x00\x00c"},
+	{"nul_edges", "\x00abc\x00"},
+	{"control_chars", controlChars()},
+	// Emoji, the first two and the last code point of CJK Extension B, and
+	// the largest code point.
+	{"utf8_4byte", "\U0001F600\U0001F389\U00020000\U00020001\U0002A6D6\U0010FFFF"},
+	{"utf8_widths", "a\u00e9\u20ac\U0001F600"},
+	// A combining mark with nothing to combine with, a decomposed é, a
+	// stack of marks, conjoining Hangul jamo, a ZWJ sequence, a flag and
+	// an emoji variation selector.
+	{"combining", "\u0301e\u0301a\u0300\u0301\u0302\u0303\u0304\u1100\u1161\u11a8" +
+		"\U0001F468\u200d\U0001F469\u200d\U0001F467\U0001F1FA\U0001F1F3\u263a\ufe0f"},
+	// Byte order mark, line and paragraph separators, the replacement
+	// character, the noncharacters U+FFFE and U+FFFF and a zero-width space.
+	{"specials", "\ufeff\u2028\u2029\ufffd\ufffe\uffff\u200b"},
+}
+
+// controlChars is every C0 control character, DEL and every C1 control
+// character, in order.
+func controlChars() string {
+	var b strings.Builder
+	for r := rune(0); r < 0x20; r++ {
+		b.WriteRune(r)
+	}
+	b.WriteRune(0x7f)
+	for r := rune(0x80); r < 0xa0; r++ {
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// GenerateStrings3 writes each edge content as text, as raw bytes and, one
+// code point per element, as pieces, then megabyte-long runs of one
+// character of each width that matters, as text and raw only. Cases with
+// text beyond ASCII are tagged TagUTF8Strict, and the runs TagLarge.
+func GenerateStrings3() []TestCase {
+	var cases []TestCase
+	for _, e := range stringEdges {
+		var pieces []string
+		for _, r := range e.text {
+			pieces = append(pieces, string(r))
+		}
+		cases = append(cases, TestCase{Name: e.name, Msg: &pb.StringEdges{Text: e.text, Raw: []byte(e.text), Pieces: pieces}, Tags: utf8Tags(e.text)})
+	}
+	for _, run := range []struct{ name, char string }{
+		{"run_ascii", "a"},
+		{"run_nul", "\x00"},
+		{"run_combining", "\u0301"},
+		{"run_4byte", "\U0001F600"},
+	} {
+		text := strings.Repeat(run.char, stringRunBytes/len(run.char))
+		cases = append(cases, TestCase{Name: run.name, Msg: &pb.StringEdges{Text: text, Raw: []byte(text)}, Tags: append(utf8Tags(run.char), TagLarge)})
+	}
+	return cases
+}
+
+// utf8Tags returns TagUTF8Strict if text is not all ASCII.
+func utf8Tags(text string) []Tag {
+	for i := 0; i < len(text); i++ {
+		if text[i] >= utf8.RuneSelf {
+			return []Tag{TagUTF8Strict}
+		}
+	}
+	return nil
+}
+
+// validateStrings3 checks that text, raw and every piece hold exactly the
+// bytes the generator wrote.
+func validateStrings3(tc RawTestCase) int {
+	msg := &pb.StringEdges{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+	expected, ok := Expected("strings3/" + tc.Name)
+	if !ok {
+		return 0
+	}
+	want := expected.(*pb.StringEdges)
+
+	failures := sameContent(tc.Name, "text", want.Text, msg.Text)
+	failures += sameContent(tc.Name, "raw", string(want.Raw), string(msg.Raw))
+	failures += check(tc.Name, "pieces.count", len(msg.Pieces) == len(want.Pieces))
+	for i := range min(len(want.Pieces), len(msg.Pieces)) {
+		failures += sameContent(tc.Name, fmt.Sprintf("pieces[%d]", i), want.Pieces[i], msg.Pieces[i])
+	}
+	return failures
+}
+
+// sameContent checks got against want byte for byte and reports where they
+// first differ, rather than either string, which may be a megabyte long.
+func sameContent(name, field, want, got string) int {
+	if check(name, field, want == got) == 0 {
+		return 0
+	}
+	i := 0
+	for i < len(want) && i < len(got) && want[i] == got[i] {
+		i++
+	}
+	fmt.Printf("    %d bytes, want %d; first difference at byte %d: got %q, want %q\n",
+		len(got), len(want), i, got[i:min(i+8, len(got))], want[i:min(i+8, len(want))])
+	return 1
+}
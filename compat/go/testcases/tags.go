@@ -0,0 +1,145 @@
+package testcases
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Tag labels test cases that share a property a run may want to leave out
+// or treat specially, such as the syntax they need or a Zig bug they are
+// known to hit. Tags are recorded per case in the manifest.
+type Tag string
+
+const (
+	// TagProto2 marks cases of proto2 messages.
+	TagProto2 Tag = "proto2"
+	// TagUTF8Strict marks cases a decoder only gets right if it validates
+	// UTF-8 exactly as the spec says: accepting every well-formed sequence,
+	// up to U+10FFFF and including noncharacters, without replacing any.
+	TagUTF8Strict Tag = "requires-utf8-strict"
+	// TagLarge marks cases of a megabyte or more, which slow runs down.
+	TagLarge Tag = "large"
+	// tagKnownZigBug is the prefix of the tags KnownZigBug makes, and on
+	// its own matches all of them.
+	tagKnownZigBug Tag = "known-zig-bug"
+)
+
+// KnownZigBug is the tag of cases the Zig side fails because of the bug
+// tracked as issue n. Such cases are expected to fail: cmd/validate reports
+// them as XFAIL instead of failing the run, and fails it if one passes, so
+// that the tag is dropped once the bug is fixed.
+func KnownZigBug(n int) Tag {
+	return Tag(fmt.Sprintf("%s#%d", tagKnownZigBug, n))
+}
+
+// KnownBug returns the issue of a KnownZigBug tag.
+func (t Tag) KnownBug() (n int, ok bool) {
+	prefix, issue, found := strings.Cut(string(t), "#")
+	if !found || Tag(prefix) != tagKnownZigBug {
+		return 0, false
+	}
+	n, err := strconv.Atoi(issue)
+	return n, err == nil && n > 0
+}
+
+// Matches reports whether t is pattern, or pattern is "known-zig-bug" and t
+// any KnownZigBug tag.
+func (t Tag) Matches(pattern Tag) bool {
+	if pattern == tagKnownZigBug {
+		_, ok := t.KnownBug()
+		return ok
+	}
+	return t == pattern
+}
+
+// ParseTags parses a comma-separated list of tags. Besides the Tag
+// constants it accepts "known-zig-bug", for every known bug, and
+// "known-zig-bug#N".
+func ParseTags(list string) ([]Tag, error) {
+	var tags []Tag
+	for _, name := range strings.Split(list, ",") {
+		switch t := Tag(strings.TrimSpace(name)); t {
+		case "":
+		case TagProto2, TagUTF8Strict, TagLarge, tagKnownZigBug:
+			tags = append(tags, t)
+		default:
+			if _, ok := t.KnownBug(); !ok {
+				return nil, fmt.Errorf("unknown tag %q", name)
+			}
+			tags = append(tags, t)
+		}
+	}
+	return tags, nil
+}
+
+// RegisterTags tags every case of an already registered category, as
+// TestCase.Tags tags one.
+func RegisterTags(name string, tags ...Tag) {
+	c, ok := registry[name]
+	if !ok {
+		panic(fmt.Sprintf("testcases: RegisterTags(%q) before Register", name))
+	}
+	c.Tags = append(c.Tags, tags...)
+	registry[name] = c
+}
+
+// CaseTags returns the tags of tc: the category's and its own, sorted and
+// without repeats.
+func (c Category) CaseTags(tc TestCase) []Tag {
+	if len(c.Tags) == 0 && len(tc.Tags) == 0 {
+		return nil
+	}
+	tags := slices.Concat(c.Tags, tc.Tags)
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// TagFilter selects cases by their tags.
+type TagFilter struct {
+	// Skip leaves out cases with any of these tags.
+	Skip []Tag
+	// Only, if set, leaves out cases with none of these tags.
+	Only []Tag
+}
+
+// Excludes reports whether f leaves out a case tagged with tags, and why.
+func (f TagFilter) Excludes(tags []Tag) (reason string, ok bool) {
+	for _, t := range tags {
+		for _, p := range f.Skip {
+			if t.Matches(p) {
+				return fmt.Sprintf("tagged %s", t), true
+			}
+		}
+	}
+	if len(f.Only) == 0 {
+		return "", false
+	}
+	for _, t := range tags {
+		for _, p := range f.Only {
+			if t.Matches(p) {
+				return "", false
+			}
+		}
+	}
+	return "not tagged " + joinTags(f.Only, " or "), true
+}
+
+// KnownBugOf returns the first KnownZigBug tag in tags.
+func KnownBugOf(tags []Tag) (Tag, bool) {
+	for _, t := range tags {
+		if _, ok := t.KnownBug(); ok {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+func joinTags(tags []Tag, sep string) string {
+	s := make([]string, len(tags))
+	for i, t := range tags {
+		s[i] = string(t)
+	}
+	return strings.Join(s, sep)
+}
@@ -0,0 +1,118 @@
+package testcases
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func init() {
+	Register("tags3", GenerateTags3, validateTags3)
+}
+
+// tagWidths lists the field numbers of TagWidths and TagWidthStrings with
+// the size in bytes of their tags, the last and first number of each width.
+var tagWidths = []struct {
+	num   protowire.Number
+	width int
+}{
+	{15, 1}, {16, 2},
+	{2047, 2}, {2048, 3},
+	{262143, 3}, {262144, 4},
+	{33554431, 4}, {33554432, 5},
+	{protowire.MaxValidNumber, 5},
+}
+
+// GenerateTags3 sets each boundary field alone, as a varint in TagWidths and
+// as a string in TagWidthStrings, and then all of them together. A field's
+// value is its number, so a decoder that mistakes one tag for another is
+// caught.
+func GenerateTags3() []TestCase {
+	var cases []TestCase
+	for _, tw := range tagWidths {
+		cases = append(cases,
+			TestCase{Name: fmt.Sprintf("varint_%d", tw.num), Msg: tagWidthVarints(tw.num)},
+			TestCase{Name: fmt.Sprintf("len_%d", tw.num), Msg: &pb.TagWidths{Strings: tagWidthStrings(tw.num)}})
+	}
+	var all []protowire.Number
+	for _, tw := range tagWidths {
+		all = append(all, tw.num)
+	}
+	cases = append(cases,
+		TestCase{Name: "varint_all", Msg: tagWidthVarints(all...)},
+		TestCase{Name: "len_all", Msg: &pb.TagWidths{Strings: tagWidthStrings(all...)}})
+	return cases
+}
+
+func tagWidthVarints(nums ...protowire.Number) *pb.TagWidths {
+	m := &pb.TagWidths{}
+	fields := m.ProtoReflect().Descriptor().Fields()
+	for _, n := range nums {
+		m.ProtoReflect().Set(fields.ByNumber(n), protoreflect.ValueOfUint64(uint64(n)))
+	}
+	return m
+}
+
+func tagWidthStrings(nums ...protowire.Number) *pb.TagWidthStrings {
+	m := &pb.TagWidthStrings{}
+	fields := m.ProtoReflect().Descriptor().Fields()
+	for _, n := range nums {
+		m.ProtoReflect().Set(fields.ByNumber(n), protoreflect.ValueOfString(strconv.Itoa(int(n))))
+	}
+	return m
+}
+
+// validateTags3 checks every boundary field against the case's name and, for
+// cases with one field, that its tag was encoded in the fewest bytes.
+func validateTags3(tc RawTestCase) int {
+	msg := &pb.TagWidths{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	kind, which, _ := strings.Cut(tc.Name, "_")
+	failures := check(tc.Name, "unknown_fields", len(msg.ProtoReflect().GetUnknown()) == 0 &&
+		len(msg.GetStrings().ProtoReflect().GetUnknown()) == 0)
+	varints := msg.ProtoReflect()
+	strs := msg.GetStrings().ProtoReflect()
+	for _, tw := range tagWidths {
+		set := which == "all" || which == strconv.Itoa(int(tw.num))
+		name := fmt.Sprintf("f%d", tw.num)
+		v := varints.Get(varints.Descriptor().Fields().ByNumber(tw.num)).Uint()
+		s := strs.Get(strs.Descriptor().Fields().ByNumber(tw.num)).String()
+		if set && kind == "varint" {
+			failures += check(tc.Name, name, v == uint64(tw.num) && s == "")
+		} else if set && kind == "len" {
+			failures += check(tc.Name, "strings."+name, s == strconv.Itoa(int(tw.num)) && v == 0)
+		} else {
+			failures += check(tc.Name, name, v == 0 && s == "")
+		}
+		if set && which != "all" {
+			failures += check(tc.Name, name+".tag_width", tagWidth(tc.Data, kind == "len") == tw.width)
+		}
+	}
+	return failures
+}
+
+// tagWidth returns the size of the first tag in data, or in the strings
+// field's data if inner is set. It returns 0 if data does not parse.
+func tagWidth(data []byte, inner bool) int {
+	if inner {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 || num != 1 || typ != protowire.BytesType {
+			return 0
+		}
+		if data, n = protowire.ConsumeBytes(data[n:]); n < 0 {
+			return 0
+		}
+	}
+	_, _, n := protowire.ConsumeTag(data)
+	return max(n, 0)
+}
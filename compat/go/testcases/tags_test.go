@@ -0,0 +1,75 @@
+package testcases_test
+
+import (
+	"bufio"
+	"io"
+	"slices"
+	"testing"
+
+	"compat/testcases"
+)
+
+func TestParseTags(t *testing.T) {
+	tags, err := testcases.ParseTags("proto2, large,known-zig-bug,known-zig-bug#12,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []testcases.Tag{testcases.TagProto2, testcases.TagLarge, "known-zig-bug", testcases.KnownZigBug(12)}
+	if !slices.Equal(tags, want) {
+		t.Errorf("got %q, want %q", tags, want)
+	}
+	for _, bad := range []string{"proto4", "known-zig-bug#", "known-zig-bug#x", "known-zig-bug#0"} {
+		if _, err := testcases.ParseTags(bad); err == nil {
+			t.Errorf("ParseTags(%q) accepted it", bad)
+		}
+	}
+}
+
+func TestTagFilter(t *testing.T) {
+	bug := testcases.KnownZigBug(3)
+	for _, tt := range []struct {
+		filter   testcases.TagFilter
+		tags     []testcases.Tag
+		excluded bool
+	}{
+		{testcases.TagFilter{}, nil, false},
+		{testcases.TagFilter{Skip: []testcases.Tag{testcases.TagLarge}}, []testcases.Tag{testcases.TagLarge}, true},
+		{testcases.TagFilter{Skip: []testcases.Tag{testcases.TagLarge}}, []testcases.Tag{testcases.TagProto2}, false},
+		{testcases.TagFilter{Skip: []testcases.Tag{"known-zig-bug"}}, []testcases.Tag{bug}, true},
+		{testcases.TagFilter{Skip: []testcases.Tag{testcases.KnownZigBug(4)}}, []testcases.Tag{bug}, false},
+		{testcases.TagFilter{Only: []testcases.Tag{testcases.TagProto2}}, nil, true},
+		{testcases.TagFilter{Only: []testcases.Tag{testcases.TagProto2}}, []testcases.Tag{testcases.TagProto2, bug}, false},
+		// Skip wins over Only.
+		{testcases.TagFilter{Skip: []testcases.Tag{bug}, Only: []testcases.Tag{testcases.TagProto2}}, []testcases.Tag{testcases.TagProto2, bug}, true},
+	} {
+		if reason, got := tt.filter.Excludes(tt.tags); got != tt.excluded || got && reason == "" {
+			t.Errorf("%+v.Excludes(%q) = %q, %v", tt.filter, tt.tags, reason, got)
+		}
+	}
+}
+
+// TestCaseTags checks that category tags reach every case and the manifest.
+func TestCaseTags(t *testing.T) {
+	c, _ := testcases.Lookup("scalar2")
+	cases, err := testcases.WriteCorpus(bufio.NewWriter(io.Discard), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mc := range cases {
+		if !slices.Contains(mc.Tags, testcases.TagProto2) {
+			t.Errorf("scalar2/%s: tags %q", mc.Name, mc.Tags)
+		}
+	}
+
+	c, _ = testcases.Lookup("strings3")
+	tags := map[string][]testcases.Tag{}
+	for _, tc := range c.Generate() {
+		tags[tc.Name] = c.CaseTags(tc)
+	}
+	if got := tags["nul_only"]; got != nil {
+		t.Errorf("strings3/nul_only: tags %q", got)
+	}
+	if got, want := tags["run_4byte"], []testcases.Tag{testcases.TagLarge, testcases.TagUTF8Strict}; !slices.Equal(got, want) {
+		t.Errorf("strings3/run_4byte: tags %q, want %q", got, want)
+	}
+}
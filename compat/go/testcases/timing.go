@@ -0,0 +1,78 @@
+package testcases
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// slowestShown is how many of the slowest cases Timing.Report lists.
+const slowestShown = 10
+
+// Timing accumulates wall-clock times per corpus file and per case, and
+// flags cases slower than a threshold.
+type Timing struct {
+	threshold time.Duration
+	files     []timed
+	cases     []timed
+	slow      int
+}
+
+type timed struct {
+	name string
+	d    time.Duration
+}
+
+// NewTiming returns a tracker that flags cases taking longer than threshold.
+// A zero threshold flags nothing.
+func NewTiming(threshold time.Duration) *Timing {
+	return &Timing{threshold: threshold}
+}
+
+// AddFile records the time taken to validate a whole corpus file.
+func (t *Timing) AddFile(name string, d time.Duration) {
+	t.files = append(t.files, timed{name, d})
+}
+
+// AddCase records the time taken by one case of file and reports whether it
+// exceeded the threshold.
+func (t *Timing) AddCase(file, name string, d time.Duration) bool {
+	t.cases = append(t.cases, timed{file + "/" + name, d})
+	if t.threshold > 0 && d > t.threshold {
+		t.slow++
+		return true
+	}
+	return false
+}
+
+// Slow returns how many cases exceeded the threshold.
+func (t *Timing) Slow() int {
+	return t.slow
+}
+
+// Report writes the time per file and the slowest cases.
+func (t *Timing) Report(w io.Writer) {
+	var total time.Duration
+	fmt.Fprintln(w, "timing:")
+	for _, f := range t.files {
+		fmt.Fprintf(w, "  %-24s %v\n", f.name, f.d)
+		total += f.d
+	}
+	fmt.Fprintf(w, "  %-24s %v (%d cases)\n", "total", total, len(t.cases))
+
+	cases := append([]timed(nil), t.cases...)
+	sort.SliceStable(cases, func(i, j int) bool { return cases[i].d > cases[j].d })
+	if len(cases) > slowestShown {
+		cases = cases[:slowestShown]
+	}
+	if len(cases) > 0 {
+		fmt.Fprintln(w, "slowest cases:")
+		for _, c := range cases {
+			fmt.Fprintf(w, "  %-40s %v\n", c.name, c.d)
+		}
+	}
+	if t.threshold > 0 {
+		fmt.Fprintf(w, "%d case(s) over the %v slow threshold\n", t.slow, t.threshold)
+	}
+}
@@ -0,0 +1,157 @@
+package testcases
+
+import (
+	"bytes"
+	"fmt"
+
+	"compat/pb"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	Register("unknown3", GenerateUnknown3, validateUnknown3)
+	RegisterRejects("unknown3", unknownRejectCases)
+}
+
+// unknownFields are wire bytes holding only fields Inner does not declare,
+// one case per wire type. A decoder must yield an empty Inner and keep the
+// bytes, so that re-encoding reproduces them exactly.
+var unknownFields = []struct {
+	name string
+	raw  []byte
+}{
+	{"varint", unknownField(100, protowire.VarintType, protowire.AppendVarint(nil, 150))},
+	{"i64", unknownField(101, protowire.Fixed64Type, protowire.AppendFixed64(nil, 0x0807060504030201))},
+	{"len", unknownField(102, protowire.BytesType, protowire.AppendBytes(nil, []byte("abc")))},
+	{"group", unknownGroup(103, unknownField(1, protowire.VarintType, []byte{1}))},
+	{"i32", unknownField(104, protowire.Fixed32Type, protowire.AppendFixed32(nil, 0x04030201))},
+	{"max_field_number", unknownField(protowire.MaxValidNumber, protowire.VarintType, []byte{1})},
+}
+
+// unknownGroups are further group encodings a decoder must skip and keep
+// whole: empty, nested, and holding every wire type.
+var unknownGroups = []struct {
+	name string
+	raw  []byte
+}{
+	{"group_empty", unknownGroup(105, nil)},
+	{"group_nested", unknownGroup(106, append(
+		unknownGroup(107, unknownGroup(108, unknownField(1, protowire.VarintType, []byte{2}))),
+		unknownField(2, protowire.VarintType, []byte{3})...))},
+	{"group_all_wire_types", unknownGroup(109, bytes.Join([][]byte{
+		unknownField(1, protowire.VarintType, []byte{1}),
+		unknownField(2, protowire.Fixed64Type, protowire.AppendFixed64(nil, 2)),
+		unknownField(3, protowire.BytesType, protowire.AppendBytes(nil, []byte("grp"))),
+		unknownGroup(4, nil),
+		unknownField(5, protowire.Fixed32Type, protowire.AppendFixed32(nil, 5)),
+	}, nil))},
+}
+
+// unknownRejects are group encodings that never end properly, or nest
+// deeper than any decoder allows. A decoder skipping unknown fields must
+// fail on them rather than stop at the end of the data or at the first
+// END_GROUP it sees, or recurse without bound.
+var unknownRejects = []struct {
+	name string
+	raw  []byte
+	err  ErrorCategory
+}{
+	{"group_unterminated", unknownField(103, protowire.StartGroupType, unknownField(1, protowire.VarintType, []byte{1})), ErrTruncated},
+	{"group_nested_unterminated", unknownField(103, protowire.StartGroupType, unknownGroup(104, nil)), ErrTruncated},
+	{"group_mismatched_end", unknownField(103, protowire.StartGroupType, append(
+		unknownField(1, protowire.VarintType, []byte{1}),
+		protowire.AppendTag(nil, 104, protowire.EndGroupType)...)), ErrBadFieldNumber},
+	{"group_too_deep", unknownGroupChain(2 * protowire.DefaultRecursionLimit), ErrRecursionLimit},
+}
+
+func unknownField(num protowire.Number, typ protowire.Type, value []byte) []byte {
+	return append(protowire.AppendTag(nil, num, typ), value...)
+}
+
+func unknownGroup(num protowire.Number, body []byte) []byte {
+	b := append(protowire.AppendTag(nil, num, protowire.StartGroupType), body...)
+	return protowire.AppendTag(b, num, protowire.EndGroupType)
+}
+
+// unknownGroupChain nests levels empty groups on field 1, two bytes a
+// level. Decoders disagree by one on how they count group depth, so the
+// reject vector goes far past protowire's limit rather than just over it.
+func unknownGroupChain(levels int) []byte {
+	var b []byte
+	for range levels {
+		b = protowire.AppendTag(b, 1, protowire.StartGroupType)
+	}
+	for range levels {
+		b = protowire.AppendTag(b, 1, protowire.EndGroupType)
+	}
+	return b
+}
+
+func unknownOnly(raw []byte) *pb.Inner {
+	m := &pb.Inner{}
+	m.ProtoReflect().SetUnknown(raw)
+	return m
+}
+
+// allUnknown concatenates every single-wire-type case.
+func allUnknown() []byte {
+	var all []byte
+	for _, f := range unknownFields {
+		all = append(all, f.raw...)
+	}
+	return all
+}
+
+// unknownRaw returns the unknown bytes of the case called name.
+func unknownRaw(name string) ([]byte, bool) {
+	if name == "all_wire_types" {
+		return allUnknown(), true
+	}
+	for _, f := range append(unknownFields, unknownGroups...) {
+		if f.name == name {
+			return f.raw, true
+		}
+	}
+	return nil, false
+}
+
+func GenerateUnknown3() []TestCase {
+	var cases []TestCase
+	for _, f := range unknownFields {
+		cases = append(cases, TestCase{Name: f.name, Msg: unknownOnly(f.raw)})
+	}
+	cases = append(cases, TestCase{Name: "all_wire_types", Msg: unknownOnly(allUnknown())})
+	for _, f := range unknownGroups {
+		cases = append(cases, TestCase{Name: f.name, Msg: unknownOnly(f.raw)})
+	}
+	return cases
+}
+
+func unknownRejectCases() []RejectCase {
+	var cases []RejectCase
+	for _, f := range unknownRejects {
+		cases = append(cases, RejectCase{Name: f.name, Data: f.raw, Error: f.err})
+	}
+	return cases
+}
+
+func validateUnknown3(tc RawTestCase) int {
+	msg := &pb.Inner{}
+	if err := proto.Unmarshal(tc.Data, msg); err != nil {
+		fmt.Printf("  FAIL %s: unmarshal: %v\n", tc.Name, err)
+		return 1
+	}
+
+	want, ok := unknownRaw(tc.Name)
+	if !ok {
+		return 0
+	}
+
+	failures := 0
+	failures += check(tc.Name, "value", msg.Value == 0)
+	failures += check(tc.Name, "label", msg.Label == "")
+	failures += check(tc.Name, "unknown", bytes.Equal(msg.ProtoReflect().GetUnknown(), want))
+	return failures
+}
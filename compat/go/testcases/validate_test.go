@@ -0,0 +1,156 @@
+package testcases_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"compat/pb"
+	"compat/pbutil"
+	"compat/testcases"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// captureStdout returns what fn printed to stdout, where validators report.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+	defer func() {
+		os.Stdout = stdout
+	}()
+	fn()
+	w.Close()
+	return <-out
+}
+
+// validate runs the validator of category over tc and returns its failure
+// count and report.
+func validate(t *testing.T, category string, tc testcases.RawTestCase) (int, string) {
+	t.Helper()
+	c, ok := testcases.Lookup(category)
+	if !ok {
+		t.Fatalf("no %s category", category)
+	}
+	var failures int
+	out := captureStdout(t, func() { failures = c.Validate(tc) })
+	return failures, out
+}
+
+// TestValidateGoCorpus checks that every validator accepts the Go corpus
+// silently, so that a failure against the Zig corpus is the Zig side's.
+func TestValidateGoCorpus(t *testing.T) {
+	for _, c := range testcases.Categories() {
+		data, _, err := testcases.BuildCorpus(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cases, err := testcases.ReadTestCases(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tc := range cases {
+			if n, out := validate(t, c.Name, tc); n != 0 || out != "" {
+				t.Errorf("%s/%s: %d failures:\n%s", c.Name, tc.Name, n, out)
+			}
+		}
+	}
+}
+
+// corrupt decodes the Go case category/name, lets edit change it and
+// returns the result encoded.
+func corrupt[M proto.Message](t *testing.T, category, name string, edit func(M)) []byte {
+	t.Helper()
+	want, ok := testcases.Expected(category + "/" + name)
+	if !ok {
+		t.Fatalf("no case %s/%s", category, name)
+	}
+	m := proto.Clone(want).(M)
+	edit(m)
+	b, err := pbutil.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestValidateDiagnostics corrupts single cases the way a broken decoder or
+// encoder would and checks that the validator counts a failure for each
+// line it reports, and reports what went wrong.
+func TestValidateDiagnostics(t *testing.T) {
+	for _, tt := range []struct {
+		category, name string
+		data           func(t *testing.T) []byte
+		want           []string
+	}{
+		{"order3", "all_identical", func(*testing.T) []byte { return []byte{0x0a, 0x05} },
+			[]string{"FAIL all_identical: unmarshal:"}},
+		{"order3", "adjacent_pairs", func(t *testing.T) []byte {
+			return corrupt(t, "order3", "adjacent_pairs", func(m *pb.OrderList) {
+				m.Items = append(m.Items[:5], m.Items[6:]...)
+			})
+		}, []string{"FAIL adjacent_pairs.items.count", "399 elements, want 400"}},
+		{"order3", "ascending", func(t *testing.T) []byte {
+			return corrupt(t, "order3", "ascending", func(m *pb.OrderList) {
+				m.Items[10], m.Items[11] = m.Items[11], m.Items[10]
+			})
+		}, []string{"FAIL ascending.items.order", "first difference at element 10"}},
+		{"strings3", "nul_embedded", func(t *testing.T) []byte {
+			return corrupt(t, "strings3", "nul_embedded", func(m *pb.StringEdges) {
+				m.Text = "a\x00b\x00"
+			})
+		}, []string{"FAIL nul_embedded.text", "4 bytes, want 6; first difference at byte 4"}},
+		{"nestedmap3", "multi_entry", func(t *testing.T) []byte {
+			return corrupt(t, "nestedmap3", "multi_entry", func(m *pb.NestedMaps) {
+				m.Layers["c"].Leaves[999].Attrs["k0"] = "x"
+				delete(m.ById[7].Buckets[0].Names, 2)
+			})
+		}, []string{
+			"FAIL multi_entry.layers[c].leaves[999].attrs[k0]",
+			"FAIL multi_entry.by_id[7].buckets[0].names.len",
+			"FAIL multi_entry.by_id[7].buckets[0].names[2]",
+		}},
+		{"map3", "entry_mixed", func(t *testing.T) []byte {
+			return corrupt(t, "map3", "entry_mixed", func(m *pb.MapMessage) {
+				delete(m.StrStr, "c")
+			})
+		}, []string{"FAIL entry_mixed.str_str.len", "FAIL entry_mixed.str_str[c]"}},
+		{"map3", "single", func(t *testing.T) []byte {
+			return corrupt(t, "map3", "single", func(m *pb.MapMessage) {
+				m.StrStr["key"] = "other"
+			})
+		}, []string{"FAIL single.str_str[key]"}},
+		// An implicit zero written to the wire reads back right, but is
+		// still a difference from the Go encoding.
+		{"presence3", "unset", func(*testing.T) []byte { return []byte{0x08, 0x00} },
+			[]string{"FAIL unset.i_int32.wire"}},
+		{"presence3", "optional_int32_zero", func(t *testing.T) []byte {
+			return corrupt(t, "presence3", "optional_int32_zero", func(m *pb.PresenceMatrix) {
+				m.OInt32 = nil
+			})
+		}, []string{"FAIL optional_int32_zero.o_int32.presence", "FAIL optional_int32_zero.o_int32.wire"}},
+	} {
+		t.Run(tt.category+"/"+tt.name, func(t *testing.T) {
+			n, out := validate(t, tt.category, testcases.RawTestCase{Name: tt.name, Data: tt.data(t)})
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("report lacks %q:\n%s", want, out)
+				}
+			}
+			if fails := strings.Count(out, "  FAIL "); n != fails {
+				t.Errorf("%d failures counted, %d reported:\n%s", n, fails, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package testcases
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// DumpWire renders the wire structure of an encoded message in a
+// protoscope-like, offset-annotated form, one field per line. Length-delimited
+// fields that parse as messages are expanded recursively; anything that cannot
+// be parsed is shown as raw bytes with the parse error.
+func DumpWire(data []byte) string {
+	var sb strings.Builder
+	dumpWire(&sb, data, 0, 0)
+	return sb.String()
+}
+
+func dumpWire(sb *strings.Builder, data []byte, base, depth int) {
+	indent := strings.Repeat("  ", depth)
+	pos := 0
+	for pos < len(data) {
+		num, typ, n := protowire.ConsumeTag(data[pos:])
+		if n < 0 {
+			fmt.Fprintf(sb, "%s[%04x] <bad tag: %v> % x\n", indent, base+pos, protowire.ParseError(n), data[pos:])
+			return
+		}
+		off := base + pos
+		pos += n
+
+		switch typ {
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(data[pos:])
+			if m < 0 {
+				fmt.Fprintf(sb, "%s[%04x] %d: <bad varint: %v>\n", indent, off, num, protowire.ParseError(m))
+				return
+			}
+			fmt.Fprintf(sb, "%s[%04x] %d: %d (zigzag %d)\n", indent, off, num, int64(v), protowire.DecodeZigZag(v))
+			pos += m
+		case protowire.Fixed32Type:
+			v, m := protowire.ConsumeFixed32(data[pos:])
+			if m < 0 {
+				fmt.Fprintf(sb, "%s[%04x] %d: <bad fixed32: %v>\n", indent, off, num, protowire.ParseError(m))
+				return
+			}
+			fmt.Fprintf(sb, "%s[%04x] %d: %di32 (0x%08x, %g)\n", indent, off, num, int32(v), v, math.Float32frombits(v))
+			pos += m
+		case protowire.Fixed64Type:
+			v, m := protowire.ConsumeFixed64(data[pos:])
+			if m < 0 {
+				fmt.Fprintf(sb, "%s[%04x] %d: <bad fixed64: %v>\n", indent, off, num, protowire.ParseError(m))
+				return
+			}
+			fmt.Fprintf(sb, "%s[%04x] %d: %di64 (0x%016x, %g)\n", indent, off, num, int64(v), v, math.Float64frombits(v))
+			pos += m
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(data[pos:])
+			if m < 0 {
+				fmt.Fprintf(sb, "%s[%04x] %d: <bad length: %v>\n", indent, off, num, protowire.ParseError(m))
+				return
+			}
+			valueOff := base + pos + m - len(v)
+			pos += m
+			switch {
+			case len(v) > 0 && isMessage(v):
+				fmt.Fprintf(sb, "%s[%04x] %d: {  // %d bytes\n", indent, off, num, len(v))
+				dumpWire(sb, v, valueOff, depth+1)
+				fmt.Fprintf(sb, "%s}\n", indent)
+			case utf8.Valid(v) && isPrintable(v):
+				fmt.Fprintf(sb, "%s[%04x] %d: %q\n", indent, off, num, v)
+			default:
+				fmt.Fprintf(sb, "%s[%04x] %d: `% x`\n", indent, off, num, v)
+			}
+		case protowire.StartGroupType:
+			v, m := protowire.ConsumeGroup(num, data[pos:])
+			if m < 0 {
+				fmt.Fprintf(sb, "%s[%04x] %d: <bad group: %v>\n", indent, off, num, protowire.ParseError(m))
+				return
+			}
+			fmt.Fprintf(sb, "%s[%04x] %d: !{\n", indent, off, num)
+			dumpWire(sb, v, base+pos, depth+1)
+			fmt.Fprintf(sb, "%s}\n", indent)
+			pos += m
+		default:
+			fmt.Fprintf(sb, "%s[%04x] %d: <unexpected wire type %d> % x\n", indent, off, num, typ, data[pos:])
+			return
+		}
+	}
+}
+
+// isMessage reports whether data parses cleanly as a sequence of fields.
+func isMessage(data []byte) bool {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 || num > protowire.MaxValidNumber {
+			return false
+		}
+		m := protowire.ConsumeFieldValue(num, typ, data[n:])
+		if m < 0 {
+			return false
+		}
+		data = data[n+m:]
+	}
+	return true
+}
+
+func isPrintable(data []byte) bool {
+	for _, r := range string(data) {
+		if r < 0x20 && r != '\n' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+package testcases
+
+import (
+	"encoding/binary"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// WireStats summarizes how an encoded message uses the wire format.
+type WireStats struct {
+	Size int `json:"size"`
+	// Fields counts field records at every depth; each element of a packed
+	// field counts once.
+	Fields int `json:"fields"`
+	// MaxDepth is the deepest level holding a field: 1 for a flat message,
+	// 0 for an empty one.
+	MaxDepth  int            `json:"max_depth"`
+	WireTypes map[string]int `json:"wire_types"`
+	// VarintLengths[i] counts varint values (not tags or lengths) that
+	// encode in i+1 bytes.
+	VarintLengths [binary.MaxVarintLen64]int `json:"varint_lengths"`
+	// Malformed is set if part of the data did not parse; the counts cover
+	// what came before.
+	Malformed bool `json:"malformed,omitempty"`
+}
+
+var wireTypeNames = map[protowire.Type]string{
+	protowire.VarintType:     "varint",
+	protowire.Fixed64Type:    "i64",
+	protowire.BytesType:      "len",
+	protowire.StartGroupType: "sgroup",
+	protowire.EndGroupType:   "egroup",
+	protowire.Fixed32Type:    "i32",
+}
+
+// NewWireStats walks data as a message of type md. Length-delimited fields
+// are descended into when md says they hold a message and packed fields are
+// counted per element. With a nil md, fields that parse as messages are
+// descended into, as in DumpWire.
+func NewWireStats(data []byte, md protoreflect.MessageDescriptor) *WireStats {
+	s := &WireStats{Size: len(data), WireTypes: map[string]int{}}
+	s.walk(data, md, 1)
+	return s
+}
+
+// Add folds o into s: sizes and counts add up and the depth is the larger.
+func (s *WireStats) Add(o *WireStats) {
+	s.Size += o.Size
+	s.Fields += o.Fields
+	s.MaxDepth = max(s.MaxDepth, o.MaxDepth)
+	for k, v := range o.WireTypes {
+		s.WireTypes[k] += v
+	}
+	for i, v := range o.VarintLengths {
+		s.VarintLengths[i] += v
+	}
+	s.Malformed = s.Malformed || o.Malformed
+}
+
+func (s *WireStats) varint(v uint64) {
+	s.VarintLengths[protowire.SizeVarint(v)-1]++
+}
+
+func (s *WireStats) walk(data []byte, md protoreflect.MessageDescriptor, depth int) {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			s.Malformed = true
+			return
+		}
+		data = data[n:]
+		var fd protoreflect.FieldDescriptor
+		if md != nil {
+			fd = md.Fields().ByNumber(num)
+		}
+		s.Fields++
+		s.WireTypes[wireTypeNames[typ]]++
+		s.MaxDepth = max(s.MaxDepth, depth)
+
+		var m int
+		switch typ {
+		case protowire.VarintType:
+			var v uint64
+			v, m = protowire.ConsumeVarint(data)
+			if m >= 0 {
+				s.varint(v)
+			}
+		case protowire.BytesType:
+			var v []byte
+			v, m = protowire.ConsumeBytes(data)
+			if m < 0 {
+				break
+			}
+			switch {
+			case fd != nil && fd.Message() != nil:
+				s.walk(v, fd.Message(), depth+1)
+			case fd != nil && fd.IsList() && isVarintKind(fd.Kind()):
+				// Packed: the record itself was counted above.
+				s.Fields--
+				for len(v) > 0 {
+					x, k := protowire.ConsumeVarint(v)
+					if k < 0 {
+						s.Malformed = true
+						break
+					}
+					s.Fields++
+					s.varint(x)
+					v = v[k:]
+				}
+			case fd != nil && fd.IsList() && fixedSize(fd.Kind()) > 0:
+				s.Fields += len(v)/fixedSize(fd.Kind()) - 1
+			case md == nil && len(v) > 0 && isMessage(v):
+				s.walk(v, nil, depth+1)
+			}
+		case protowire.StartGroupType:
+			var v []byte
+			v, m = protowire.ConsumeGroup(num, data)
+			if m >= 0 {
+				var gmd protoreflect.MessageDescriptor
+				if fd != nil {
+					gmd = fd.Message()
+				}
+				s.walk(v, gmd, depth+1)
+			}
+		default:
+			m = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if m < 0 {
+			s.Malformed = true
+			return
+		}
+		data = data[m:]
+	}
+}
+
+func isVarintKind(k protoreflect.Kind) bool {
+	switch k {
+	case protoreflect.BoolKind, protoreflect.EnumKind,
+		protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Uint32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Uint64Kind:
+		return true
+	}
+	return false
+}
+
+// fixedSize returns the encoded size of a fixed-width kind, or 0.
+func fixedSize(k protoreflect.Kind) int {
+	switch k {
+	case protoreflect.Fixed32Kind, protoreflect.Sfixed32Kind, protoreflect.FloatKind:
+		return 4
+	case protoreflect.Fixed64Kind, protoreflect.Sfixed64Kind, protoreflect.DoubleKind:
+		return 8
+	}
+	return 0
+}
@@ -0,0 +1,34 @@
+package testcases_test
+
+import (
+	"testing"
+
+	"compat/pb"
+	"compat/pbutil"
+	"compat/testcases"
+)
+
+func TestWireStats(t *testing.T) {
+	msg := &pb.RepeatedMessage{
+		Ints:  []int32{1, 300, -1},
+		Items: []*pb.RepItem{{Id: 5, Name: "x"}},
+	}
+	data, err := pbutil.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := testcases.NewWireStats(data, msg.ProtoReflect().Descriptor())
+	// Three packed ints, the item record and its two fields.
+	if s.Fields != 6 || s.MaxDepth != 2 || s.Size != len(data) || s.Malformed {
+		t.Errorf("got %+v", s)
+	}
+	if want := [10]int{2, 1, 0, 0, 0, 0, 0, 0, 0, 1}; s.VarintLengths != want {
+		t.Errorf("varint lengths %v, want %v", s.VarintLengths, want)
+	}
+	if s.WireTypes["len"] != 3 || s.WireTypes["varint"] != 1 {
+		t.Errorf("wire types %v", s.WireTypes)
+	}
+	if bad := testcases.NewWireStats([]byte{0x0a, 0x05}, nil); !bad.Malformed {
+		t.Errorf("truncated data not flagged: %+v", bad)
+	}
+}
@@ -0,0 +1,110 @@
+// Package watchdog turns a hang into a failure that names what hung. A
+// decoder or peer stuck in an infinite loop otherwise leaves a run waiting
+// until CI kills the whole job, with nothing to say which case or call it
+// was on.
+package watchdog
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Watchdog times one thing at a time and reports it if it runs too long.
+// A nil *Watchdog times nothing, so callers need not check whether a
+// timeout was asked for.
+type Watchdog struct {
+	timeout time.Duration
+	expired func(what string, after time.Duration)
+
+	mu    sync.Mutex
+	timer *time.Timer
+	what  string
+	gen   int // bumped by Start and Stop, so a stale timer does nothing
+}
+
+// New returns a Watchdog that calls expired, on a goroutine of its own, when
+// something started with Start is still running after timeout. A timeout of
+// zero or less returns nil.
+func New(timeout time.Duration, expired func(what string, after time.Duration)) *Watchdog {
+	if timeout <= 0 {
+		return nil
+	}
+	return &Watchdog{timeout: timeout, expired: expired}
+}
+
+// Start begins timing what, replacing anything already being timed.
+func (w *Watchdog) Start(what string) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stop()
+	w.what = what
+	gen := w.gen
+	w.timer = time.AfterFunc(w.timeout, func() {
+		w.mu.Lock()
+		what, live := w.what, w.gen == gen
+		w.mu.Unlock()
+		if live {
+			w.expired(what, w.timeout)
+		}
+	})
+}
+
+// Rename names what is running now without restarting the clock, so that a
+// file timed as a whole can report the case it hung in.
+func (w *Watchdog) Rename(what string) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	w.what = what
+	w.mu.Unlock()
+}
+
+// Stop stops timing.
+func (w *Watchdog) Stop() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	w.stop()
+	w.mu.Unlock()
+}
+
+// stop is Stop with mu held.
+func (w *Watchdog) stop() {
+	w.gen++
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+}
+
+// Exit returns an expired func for commands. It writes every goroutine's
+// stack to stderr, then "prog: hung in WHAT after D" so that the line ends
+// the output where a harness showing its tail will find it, and exits with
+// status 1.
+func Exit(prog string) func(string, time.Duration) {
+	return func(what string, after time.Duration) {
+		fmt.Fprintf(os.Stderr, "%s: goroutines at timeout:\n%s\n", prog, Stacks())
+		fmt.Fprintf(os.Stderr, "%s: hung in %s after %v\n", prog, what, after)
+		os.Exit(1)
+	}
+}
+
+// Stacks returns the stacks of all goroutines.
+func Stacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,51 @@
+package watchdog_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"compat/watchdog"
+)
+
+func TestWatchdog(t *testing.T) {
+	hung := make(chan string, 1)
+	w := watchdog.New(20*time.Millisecond, func(what string, after time.Duration) { hung <- what })
+
+	// Finishing in time reports nothing, and neither does restarting.
+	w.Start("map3")
+	w.Start("map3")
+	w.Stop()
+	select {
+	case what := <-hung:
+		t.Fatalf("stopped watchdog reported %q", what)
+	case <-time.After(60 * time.Millisecond):
+	}
+
+	// A rename names the case without restarting the clock.
+	w.Start("map3")
+	w.Rename("map3/multiple")
+	select {
+	case what := <-hung:
+		if what != "map3/multiple" {
+			t.Errorf("hung in %q, want map3/multiple", what)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watchdog never fired")
+	}
+	w.Stop()
+
+	var nilWatchdog *watchdog.Watchdog
+	if watchdog.New(0, nil) != nilWatchdog {
+		t.Error("New(0) returned a watchdog")
+	}
+	nilWatchdog.Start("x")
+	nilWatchdog.Rename("y")
+	nilWatchdog.Stop()
+}
+
+func TestStacks(t *testing.T) {
+	if s := watchdog.Stacks(); !bytes.Contains(s, []byte("TestStacks")) {
+		t.Errorf("stacks do not include the caller:\n%s", s)
+	}
+}
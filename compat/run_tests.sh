@@ -2,14 +2,16 @@
 # Cross-validation test runner:
 # 1. Go generates reference test vectors
 # 2. Zig tests run (includes reading Go vectors + writing Zig vectors)
-# 3. Go validates Zig-produced vectors
+# 3. Go validates Zig-produced vectors and the archived Go corpora
+# 4. go test, which also compares the Zig vectors with testcases.Expected
+# PROFILE picks the corpus size (smoke, standard, extended or stress).
 set -euo pipefail
 
 SCRIPT_DIR="$(cd "$(dirname "$0")" && pwd)"
 cd "$SCRIPT_DIR"
 
 echo "=== Step 1: Generate Go test vectors ==="
-(cd go && go run ./cmd/generate)
+(cd go && go run ./cmd/generate -profile "${PROFILE:-standard}")
 
 echo ""
 echo "=== Step 2: Run Zig compat tests ==="
@@ -17,7 +19,11 @@ zig build test
 
 echo ""
 echo "=== Step 3: Validate Zig test vectors with Go ==="
-(cd go && go run ./cmd/validate)
+(cd go && go run ./cmd/validate -archives)
+
+echo ""
+echo "=== Step 4: Run Go tests ==="
+(cd go && go test ./...)
 
 echo ""
 echo "=== All cross-validation tests passed ==="
@@ -1,4 +1,5 @@
 const std = @import("std");
+const builtin = @import("builtin");
 const testing = std.testing;
 const proto = @import("proto");
 const message = @import("protobuf").message;
@@ -26,6 +27,7 @@ const AcpMessage = proto.acp.AcpMessage;
 const AcpMessageKind = proto.acp.AcpMessageKind;
 const AcpStatusCode = proto.acp.AcpStatusCode;
 const AcpAssetMetadata = proto.acp.AcpAssetMetadata;
+const AcpCompression = proto.acp.AcpCompression;
 const DefaultMessage = proto.default2.DefaultMessage;
 const DefaultColor = proto.default2.DefaultColor;
 const ExtBase = proto.extension2.ExtBase;
@@ -35,7 +37,33 @@ const FileRequest = proto.single_oneof3.FileRequest;
 const TextMessage = proto.text3.TextMessage;
 const PackedScalars = proto.packed3.PackedScalars;
 const SubMessage = proto.text3.SubMessage;
+const ImportsMessage = proto.imports3.ImportsMessage;
+const Tagged = proto.imports_mid3.Tagged;
+const Point = proto.imports_base3.Point;
+const Level = proto.imports_base3.Level;
+const Naming = proto.naming3.Naming;
+const JsonNames = proto.json3.JsonNames;
+const MessageSetContainer = proto.messageset2.MessageSetContainer;
+const EvolutionV1 = proto.evolution3.EvolutionV1;
+const EvolutionV2 = proto.evolution3.EvolutionV2;
+const ReservedMessage = proto.reserved3.ReservedMessage;
+const TagWidths = proto.tags3.TagWidths;
+const TagWidthStrings = proto.tags3.TagWidthStrings;
+const LimitNode = proto.limits3.LimitNode;
+const NestedMaps = proto.nestedmap3.NestedMaps;
+const NestedMapLayer = proto.nestedmap3.NestedMapLayer;
+const NestedMapBucket = proto.nestedmap3.NestedMapBucket;
+const NestedMapLeaf = proto.nestedmap3.NestedMapLeaf;
+const OrderList = proto.order3.OrderList;
+const OrderItem = proto.order3.OrderItem;
+const PresenceMatrix = proto.presence3.PresenceMatrix;
+const PresenceChild = proto.presence3.PresenceChild;
+const PresenceEnum = proto.presence3.PresenceEnum;
+const StringEdges = proto.strings3.StringEdges;
 const TextEnum = proto.text3.TextEnum;
+const CompatResults = proto.compat_results.CompatResults;
+const CaseResult = proto.compat_results.CaseResult;
+const ByteDiff = proto.compat_results.ByteDiff;
 
 const json = @import("protobuf").json;
 const text_format = @import("protobuf").text_format;
@@ -80,6 +108,45 @@ fn decode_msg(comptime T: type, data: []const u8) !T {
     return try T.decode(testing.allocator, data);
 }
 
+/// Checks the ownership contract for every case in a Go corpus file: a
+/// decoded message owns its strings, bytes and unknown fields. Each case is
+/// decoded from a private copy that is then overwritten and freed, and the
+/// message must still encode as a decode of the untouched data does. Cases
+/// that do not decode are left to the other tests.
+fn scribble_vectors(comptime T: type, path: []const u8) !void {
+    const file_data = try read_go_vectors(path);
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var ref = T.decode(testing.allocator, tc.data) catch continue;
+        defer ref.deinit(testing.allocator);
+        var want: std.Io.Writer.Allocating = .init(testing.allocator);
+        defer want.deinit();
+        try ref.encode(&want.writer);
+
+        const buf = try testing.allocator.dupe(u8, tc.data);
+        var msg = T.decode(testing.allocator, buf) catch |err| {
+            testing.allocator.free(buf);
+            return err;
+        };
+        defer msg.deinit(testing.allocator);
+        @memset(buf, 0xa5);
+        testing.allocator.free(buf);
+
+        var got: std.Io.Writer.Allocating = .init(testing.allocator);
+        defer got.deinit();
+        try msg.encode(&got.writer);
+        testing.expectEqualSlices(u8, want.written(), got.written()) catch |err| {
+            std.debug.print("{s}: {s}: decoded message aliases its input\n", .{ path, tc.name });
+            return err;
+        };
+    }
+}
+
 fn write_test_vectors(comptime _: type, cases: anytype, path: []const u8) !void {
     if (std.fs.path.dirname(path)) |dir| {
         std.fs.cwd().makePath(dir) catch {};
@@ -87,17 +154,39 @@ fn write_test_vectors(comptime _: type, cases: anytype, path: []const u8) !void
     var file = try std.fs.cwd().createFile(path, .{});
     defer file.close();
 
-    var buf: [65536]u8 = undefined;
-    var w: std.Io.Writer = .fixed(&buf);
+    var w: std.Io.Writer.Allocating = .init(testing.allocator);
+    defer w.deinit();
+    try framing.write_header(&w.writer);
 
     for (cases) |tc| {
-        var msg_buf: [8192]u8 = undefined;
-        var msg_w: std.Io.Writer = .fixed(&msg_buf);
-        try tc.msg.encode(&msg_w);
-        try framing.write_test_case(&w, tc.name, msg_w.buffered());
+        var msg_w: std.Io.Writer.Allocating = .init(testing.allocator);
+        defer msg_w.deinit();
+        try tc.msg.encode(&msg_w.writer);
+        try framing.write_test_case(&w.writer, tc.name, msg_w.written());
     }
 
-    try file.writeAll(w.buffered());
+    try file.writeAll(w.written());
+}
+
+/// Like write_test_vectors, but frames each message's proto3 JSON encoding.
+fn write_json_vectors(comptime T: type, cases: anytype, path: []const u8) !void {
+    if (std.fs.path.dirname(path)) |dir| {
+        std.fs.cwd().makePath(dir) catch {};
+    }
+    var file = try std.fs.cwd().createFile(path, .{});
+    defer file.close();
+
+    var w: std.Io.Writer.Allocating = .init(testing.allocator);
+    defer w.deinit();
+    try framing.write_header(&w.writer);
+
+    for (cases) |tc| {
+        const json_bytes = try json_encode(T, tc.msg);
+        defer testing.allocator.free(json_bytes);
+        try framing.write_test_case(&w.writer, tc.name, json_bytes);
+    }
+
+    try file.writeAll(w.written());
 }
 
 fn read_go_vectors(path: []const u8) !?[]const u8 {
@@ -228,6 +317,11 @@ test "scalar3: large tag only" {
     try testing.expectEqual(@as(i32, 12345), decoded.f_large_tag);
 }
 
+// NaNs with payload bits set (the double one sign-negative), compared by bit
+// pattern so canonicalizing them is caught. Must match go/testcases/scalar3.go.
+const nan_payload64: u64 = 0xfff8_0000_dead_beef;
+const nan_payload32: u32 = 0x7fc0_1234;
+
 // ── Scalar3 Go vector validation ──────────────────────────────────────
 
 test "scalar3: read Go test vectors" {
@@ -273,6 +367,40 @@ test "scalar3: read Go test vectors" {
             try testing.expectEqual(std.math.minInt(i64), decoded.f_int64);
         } else if (std.mem.eql(u8, tc.name, "large_tag_only")) {
             try testing.expectEqual(@as(i32, 12345), decoded.f_large_tag);
+        } else if (std.mem.eql(u8, tc.name, "zigzag_neg_one")) {
+            try testing.expectEqual(@as(i32, -1), decoded.f_sint32);
+            try testing.expectEqual(@as(i64, -1), decoded.f_sint64);
+        } else if (std.mem.eql(u8, tc.name, "zigzag_min")) {
+            try testing.expectEqual(std.math.minInt(i32), decoded.f_sint32);
+            try testing.expectEqual(std.math.minInt(i64), decoded.f_sint64);
+        } else if (std.mem.eql(u8, tc.name, "zigzag_max")) {
+            try testing.expectEqual(std.math.maxInt(i32), decoded.f_sint32);
+            try testing.expectEqual(std.math.maxInt(i64), decoded.f_sint64);
+        } else if (std.mem.eql(u8, tc.name, "negative_zero")) {
+            try testing.expectEqual(@as(u64, 0x8000_0000_0000_0000), @as(u64, @bitCast(decoded.f_double)));
+            try testing.expectEqual(@as(u32, 0x8000_0000), @as(u32, @bitCast(decoded.f_float)));
+        } else if (std.mem.eql(u8, tc.name, "denormals")) {
+            try testing.expectEqual(@as(u64, 1), @as(u64, @bitCast(decoded.f_double)));
+            try testing.expectEqual(@as(u32, 1), @as(u32, @bitCast(decoded.f_float)));
+        } else if (std.mem.eql(u8, tc.name, "nan_payload")) {
+            try testing.expectEqual(nan_payload64, @as(u64, @bitCast(decoded.f_double)));
+            try testing.expectEqual(nan_payload32, @as(u32, @bitCast(decoded.f_float)));
+        } else if (std.mem.eql(u8, tc.name, "float_conversion")) {
+            try testing.expectEqual(@as(u64, 0x4170_0000_1000_0000), @as(u64, @bitCast(decoded.f_double)));
+            try testing.expectEqual(@as(u32, 0x3dcc_cccd), @as(u32, @bitCast(decoded.f_float)));
+        } else if (std.mem.eql(u8, tc.name, "double_beyond_float32")) {
+            try testing.expectEqual(@as(u64, 0x4807_8287_f49c_4a1d), @as(u64, @bitCast(decoded.f_double)));
+        } else if (std.mem.startsWith(u8, tc.name, "length_")) {
+            const n = try std.fmt.parseInt(usize, tc.name["length_".len..], 10);
+            try testing.expectEqual(n, decoded.f_string.len);
+            try testing.expectEqual(n, decoded.f_bytes.len);
+            for (decoded.f_string) |c| try testing.expectEqual(@as(u8, 's'), c);
+            for (decoded.f_bytes) |c| try testing.expectEqual(@as(u8, 0xb5), c);
+        } else if (std.mem.startsWith(u8, tc.name, "bool_")) {
+            // Go writes f_bool as 2, 255, 1 << 32, 1 << 63 or an over-long
+            // zero; any nonzero varint is true.
+            const want = !std.mem.eql(u8, tc.name, "bool_overlong_zero");
+            try testing.expectEqual(want, decoded.f_bool);
         }
     }
 }
@@ -315,11 +443,73 @@ test "scalar3: write Zig test vectors" {
         .{ .name = "large_tag_only", .msg = .{
             .f_large_tag = 12345,
         } },
+        .{ .name = "zigzag_neg_one", .msg = .{
+            .f_sint32 = -1,
+            .f_sint64 = -1,
+        } },
+        .{ .name = "zigzag_min", .msg = .{
+            .f_sint32 = std.math.minInt(i32),
+            .f_sint64 = std.math.minInt(i64),
+        } },
+        .{ .name = "zigzag_max", .msg = .{
+            .f_sint32 = std.math.maxInt(i32),
+            .f_sint64 = std.math.maxInt(i64),
+        } },
+        .{ .name = "negative_zero", .msg = .{
+            .f_double = -0.0,
+            .f_float = -0.0,
+        } },
+        .{ .name = "denormals", .msg = .{
+            .f_double = std.math.floatTrueMin(f64),
+            .f_float = std.math.floatTrueMin(f32),
+        } },
+        .{ .name = "nan_payload", .msg = .{
+            .f_double = @bitCast(nan_payload64),
+            .f_float = @bitCast(nan_payload32),
+        } },
+        .{ .name = "float_conversion", .msg = .{
+            .f_double = 16777217,
+            .f_float = 0.1,
+        } },
+        .{ .name = "double_beyond_float32", .msg = .{
+            .f_double = 1e39,
+        } },
+        // Lengths either side of a change in the length-prefix width.
+        .{ .name = "length_127", .msg = .{ .f_string = "s" ** 127, .f_bytes = "\xb5" ** 127 } },
+        .{ .name = "length_128", .msg = .{ .f_string = "s" ** 128, .f_bytes = "\xb5" ** 128 } },
+        .{ .name = "length_16383", .msg = .{ .f_string = "s" ** 16383, .f_bytes = "\xb5" ** 16383 } },
+        .{ .name = "length_16384", .msg = .{ .f_string = "s" ** 16384, .f_bytes = "\xb5" ** 16384 } },
+        // The Go side writes these with non-canonical bool varints.
+        .{ .name = "bool_varint_2", .msg = .{ .f_bool = true } },
+        .{ .name = "bool_varint_255", .msg = .{ .f_bool = true } },
+        .{ .name = "bool_varint_multibyte", .msg = .{ .f_bool = true } },
+        .{ .name = "bool_varint_top_bit", .msg = .{ .f_bool = true } },
+        .{ .name = "bool_overlong_zero", .msg = .{} },
     };
 
     try write_test_vectors(ScalarMessage, &cases, "testdata/zig/scalar3.bin");
 }
 
+test "scalar3: reject malformed Go vectors" {
+    // Truncated fields, overlong varints, wire types 6 and 7, field number
+    // 0 and invalid UTF-8 in f_string.
+    const file_data = try read_go_vectors("testdata/go/reject/scalar3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        if (ScalarMessage.decode(testing.allocator, tc.data)) |decoded| {
+            var msg = decoded;
+            msg.deinit(testing.allocator);
+            std.debug.print("reject/scalar3.bin: {s}: decoded without error\n", .{tc.name});
+            return error.TestUnexpectedResult;
+        } else |_| {}
+    }
+}
+
 // ── Nested3 Tests ─────────────────────────────────────────────────────
 
 test "nested3: encode/decode round-trip - empty" {
@@ -378,6 +568,24 @@ test "nested3: read Go test vectors" {
         } else if (std.mem.eql(u8, tc.name, "single_level")) {
             try testing.expectEqual(@as(?Middle, null), decoded.middle);
             try testing.expectEqual(@as(i32, 5), decoded.direct_inner.?.value);
+        } else if (std.mem.eql(u8, tc.name, "empty_middle")) {
+            try testing.expect(decoded.middle != null);
+            try testing.expectEqual(@as(?Inner, null), decoded.middle.?.inner);
+            try testing.expectEqual(@as(?Inner, null), decoded.direct_inner);
+        } else if (std.mem.eql(u8, tc.name, "empty_direct_inner")) {
+            try testing.expectEqual(@as(?Middle, null), decoded.middle);
+            try testing.expect(decoded.direct_inner != null);
+        } else if (std.mem.eql(u8, tc.name, "middle_with_empty_inner")) {
+            try testing.expect(decoded.middle != null);
+            try testing.expect(decoded.middle.?.inner != null);
+            try testing.expectEqual(@as(?Inner, null), decoded.direct_inner);
+        } else if (std.mem.startsWith(u8, tc.name, "inner_size_")) {
+            const size = try std.fmt.parseInt(usize, tc.name["inner_size_".len..], 10);
+            const label = decoded.middle.?.inner.?.label;
+            // A label-only Inner is its tag, length prefix and the label.
+            const prefix: usize = if (label.len < 128) 1 else 2;
+            try testing.expectEqual(size, 1 + prefix + label.len);
+            for (label) |c| try testing.expectEqual(@as(u8, 'n'), c);
         }
     }
 }
@@ -396,6 +604,15 @@ test "nested3: write Zig test vectors" {
         .{ .name = "single_level", .msg = .{
             .direct_inner = .{ .value = 5, .label = "only" },
         } },
+        .{ .name = "empty_middle", .msg = .{ .middle = .{} } },
+        .{ .name = "empty_direct_inner", .msg = .{ .direct_inner = .{} } },
+        .{ .name = "middle_with_empty_inner", .msg = .{ .middle = .{ .inner = .{} } } },
+        // Label lengths that make Inner encode to exactly 127, 128, 16383
+        // and 16384 bytes.
+        .{ .name = "inner_size_127", .msg = .{ .middle = .{ .inner = .{ .label = "n" ** 125 } } } },
+        .{ .name = "inner_size_128", .msg = .{ .middle = .{ .inner = .{ .label = "n" ** 126 } } } },
+        .{ .name = "inner_size_16383", .msg = .{ .middle = .{ .inner = .{ .label = "n" ** 16380 } } } },
+        .{ .name = "inner_size_16384", .msg = .{ .middle = .{ .inner = .{ .label = "n" ** 16381 } } } },
     };
 
     try write_test_vectors(Outer, &cases, "testdata/zig/nested3.bin");
@@ -473,17 +690,44 @@ test "enum3: read Go test vectors" {
             try testing.expectEqual(Color.COLOR_RED, decoded.color);
         } else if (std.mem.eql(u8, tc.name, "repeated")) {
             try testing.expectEqual(@as(usize, 3), decoded.colors.len);
+        } else if (std.mem.eql(u8, tc.name, "negative")) {
+            try testing.expectEqual(@as(i32, -1), @intFromEnum(decoded.color));
+            try testing.expectEqual(@as(usize, 2), decoded.colors.len);
+            try testing.expectEqual(@as(i32, std.math.minInt(i32)), @intFromEnum(decoded.colors[0]));
+            try testing.expectEqual(@as(i32, std.math.maxInt(i32)), @intFromEnum(decoded.colors[1]));
+        } else {
+            for (enum_wide_cases) |want| {
+                if (!std.mem.eql(u8, tc.name, want.name)) continue;
+                try testing.expectEqual(want.msg.color, decoded.color);
+                try testing.expectEqual(want.msg.colors.len, decoded.colors.len);
+                for (want.msg.colors, decoded.colors) |w, d| try testing.expectEqual(w, d);
+            }
         }
     }
 }
 
+const EnumCase = struct { name: []const u8, msg: EnumMessage };
+
+// Final state of the Go wide_* vectors, whose enum varints do not fit in
+// int32; only the low 32 bits count. See go/testcases/enum3.go.
+const enum_wide_cases = [_]EnumCase{
+    .{ .name = "wide_high_bits", .msg = .{ .color = .COLOR_GREEN } },
+    .{ .name = "wide_uint32_max", .msg = .{ .color = @enumFromInt(-1) } },
+    .{ .name = "wide_int32_overflow", .msg = .{ .color = @enumFromInt(std.math.minInt(i32)) } },
+    .{ .name = "wide_top_bit", .msg = .{} },
+    .{ .name = "wide_int64_min_plus_one", .msg = .{ .color = .COLOR_RED } },
+    .{ .name = "wide_packed", .msg = .{ .colors = &[_]Color{ .COLOR_RED, @enumFromInt(-2), .COLOR_BLUE } } },
+};
+
 test "enum3: write Zig test vectors" {
     const colors = &[_]Color{ .COLOR_RED, .COLOR_GREEN, .COLOR_BLUE };
-    const cases = [_]struct { name: []const u8, msg: EnumMessage }{
+    const extremes = &[_]Color{ @enumFromInt(std.math.minInt(i32)), @enumFromInt(std.math.maxInt(i32)) };
+    const cases = [_]EnumCase{
         .{ .name = "default", .msg = .{} },
         .{ .name = "red", .msg = .{ .color = .COLOR_RED, .name = "red_test" } },
         .{ .name = "repeated", .msg = .{ .color = .COLOR_BLUE, .colors = colors, .name = "multi" } },
-    };
+        .{ .name = "negative", .msg = .{ .color = @enumFromInt(-1), .colors = extremes } },
+    } ++ enum_wide_cases;
 
     try write_test_vectors(EnumMessage, &cases, "testdata/zig/enum3.bin");
 }
@@ -537,6 +781,21 @@ test "oneof3: encode/decode round-trip - msg variant" {
     try testing.expectEqualStrings("sub", decoded.value.?.msg_val.text);
 }
 
+// Final state of the Go overwrite_* vectors, which set several members of
+// the oneof in turn; see go/testcases/oneof3.go. A message member read twice
+// merges.
+const OneofCase = struct { name: []const u8, msg: OneofMessage };
+
+const oneof_overwrite_cases = [_]OneofCase{
+    .{ .name = "overwrite_str_then_int", .msg = .{ .value = .{ .int_val = 42 } } },
+    .{ .name = "overwrite_int_then_str", .msg = .{ .value = .{ .str_val = "hello" } } },
+    .{ .name = "overwrite_msg_then_int", .msg = .{ .value = .{ .int_val = 7 } } },
+    .{ .name = "overwrite_int_then_msg", .msg = .{ .value = .{ .msg_val = .{ .id = 1, .text = "sub" } } } },
+    .{ .name = "overwrite_bytes_then_str_with_name", .msg = .{ .name = "test", .value = .{ .str_val = "last" } } },
+    .{ .name = "overwrite_msg_then_msg", .msg = .{ .value = .{ .msg_val = .{ .id = 1, .text = "b" } } } },
+    .{ .name = "overwrite_msg_int_msg", .msg = .{ .value = .{ .msg_val = .{ .text = "b" } } } },
+};
+
 test "oneof3: read Go test vectors" {
     const file_data = try read_go_vectors("testdata/go/oneof3.bin");
     if (file_data == null) return;
@@ -557,18 +816,34 @@ test "oneof3: read Go test vectors" {
             try testing.expectEqual(@as(i32, 42), decoded.value.?.int_val);
         } else if (std.mem.eql(u8, tc.name, "msg_variant")) {
             try testing.expectEqual(@as(i32, 1), decoded.value.?.msg_val.id);
+        } else if (std.mem.startsWith(u8, tc.name, "overwrite_")) {
+            // Several members were on the wire; only the last may remain.
+            for (oneof_overwrite_cases) |want| {
+                if (!std.mem.eql(u8, tc.name, want.name)) continue;
+                try testing.expectEqualStrings(want.msg.name, decoded.name);
+                try testing.expectEqual(std.meta.activeTag(want.msg.value.?), std.meta.activeTag(decoded.value.?));
+                switch (want.msg.value.?) {
+                    .str_val => |v| try testing.expectEqualStrings(v, decoded.value.?.str_val),
+                    .int_val => |v| try testing.expectEqual(v, decoded.value.?.int_val),
+                    .bytes_val => |v| try testing.expectEqualSlices(u8, v, decoded.value.?.bytes_val),
+                    .msg_val => |v| {
+                        try testing.expectEqual(v.id, decoded.value.?.msg_val.id);
+                        try testing.expectEqualStrings(v.text, decoded.value.?.msg_val.text);
+                    },
+                }
+            }
         }
     }
 }
 
 test "oneof3: write Zig test vectors" {
-    const cases = [_]struct { name: []const u8, msg: OneofMessage }{
+    const cases = [_]OneofCase{
         .{ .name = "none_set", .msg = .{ .name = "empty" } },
         .{ .name = "string_variant", .msg = .{ .name = "test", .value = .{ .str_val = "hello" } } },
         .{ .name = "int_variant", .msg = .{ .name = "test", .value = .{ .int_val = 42 } } },
         .{ .name = "bytes_variant", .msg = .{ .name = "test", .value = .{ .bytes_val = "\x01\x02\x03" } } },
         .{ .name = "msg_variant", .msg = .{ .name = "test", .value = .{ .msg_val = .{ .id = 1, .text = "sub" } } } },
-    };
+    } ++ oneof_overwrite_cases;
 
     try write_test_vectors(OneofMessage, &cases, "testdata/zig/oneof3.bin");
 }
@@ -693,6 +968,21 @@ test "repeated3: encode/decode round-trip - multiple" {
     try testing.expectEqualStrings("two", decoded.items[1].name);
 }
 
+const RepeatedCase = struct { name: []const u8, msg: RepeatedMessage };
+
+// Each of these was on the wire partly packed and partly as single tagged
+// values, between other fields; see repeatedMixed in go/testcases/repeated3.go.
+const repeated_mixed_cases = [_]RepeatedCase{
+    .{ .name = "mixed_ints", .msg = .{ .ints = &.{ 1, 2, 3, 4, 5, 6 }, .strings = &.{"x"}, .doubles = &.{1.5} } },
+    .{ .name = "mixed_ints_negative", .msg = .{ .ints = &.{ -1, -2, 3, std.math.minInt(i32) } } },
+    .{ .name = "mixed_doubles_bools", .msg = .{
+        .ints = &.{7},
+        .doubles = &.{ 0.5, 1.5, 2.5, 3.5 },
+        .bools = &.{ true, false, true },
+    } },
+    .{ .name = "mixed_empty_packed", .msg = .{ .ints = &.{ 8, 9 } } },
+};
+
 test "repeated3: read Go test vectors" {
     const file_data = try read_go_vectors("testdata/go/repeated3.bin");
     if (file_data == null) return;
@@ -727,17 +1017,52 @@ test "repeated3: read Go test vectors" {
             try testing.expectEqual(@as(usize, 3), decoded.ints.len);
             try testing.expectEqual(@as(usize, 3), decoded.strings.len);
             try testing.expectEqual(@as(usize, 2), decoded.items.len);
+        } else if (std.mem.eql(u8, tc.name, "packed_large")) {
+            var large: LargePacked = undefined;
+            large.fill();
+            try testing.expectEqualSlices(f64, &large.double, decoded.doubles);
+            try testing.expectEqualSlices(bool, &large.bools, decoded.bools);
+        } else if (std.mem.eql(u8, tc.name, "packed_bools_nonzero")) {
+            // Packed as 2, 0, 255 and 1 << 32.
+            try testing.expectEqualSlices(bool, &.{ true, false, true, true }, decoded.bools);
+        } else if (std.mem.eql(u8, tc.name, "alias_views")) {
+            var views: AliasViews = undefined;
+            views.fill();
+            try testing.expectEqual(@as(usize, alias_view_count), decoded.strings.len);
+            try testing.expectEqual(@as(usize, alias_view_count), decoded.byte_slices.len);
+            try testing.expectEqual(@as(usize, alias_view_count), decoded.items.len);
+            for (views.views, decoded.strings, decoded.byte_slices) |want, str, b| {
+                try testing.expectEqualStrings(want, str);
+                try testing.expectEqualStrings(want, b);
+            }
+            for (views.items, decoded.items) |want, item| {
+                try testing.expectEqual(want.id, item.id);
+                try testing.expectEqualStrings(want.name, item.name);
+            }
+        } else if (std.mem.startsWith(u8, tc.name, "mixed_")) {
+            // Values appear in wire order, whichever form carried them.
+            for (repeated_mixed_cases) |want| {
+                if (!std.mem.eql(u8, tc.name, want.name)) continue;
+                try testing.expectEqualSlices(i32, want.msg.ints, decoded.ints);
+                try testing.expectEqual(want.msg.strings.len, decoded.strings.len);
+                try testing.expectEqualSlices(f64, want.msg.doubles, decoded.doubles);
+                try testing.expectEqualSlices(bool, want.msg.bools, decoded.bools);
+            }
         }
     }
 }
 
 test "repeated3: write Zig test vectors" {
+    var large: LargePacked = undefined;
+    large.fill();
+    var views: AliasViews = undefined;
+    views.fill();
     const single_items = &[_]RepItem{.{ .id = 1, .name = "first" }};
     const multi_items = &[_]RepItem{
         .{ .id = 1, .name = "one" },
         .{ .id = 2, .name = "two" },
     };
-    const cases = [_]struct { name: []const u8, msg: RepeatedMessage }{
+    const cases = [_]RepeatedCase{
         .{ .name = "empty", .msg = .{} },
         .{ .name = "single", .msg = .{
             .ints = &.{1},
@@ -755,9 +1080,24 @@ test "repeated3: write Zig test vectors" {
             .byte_slices = &.{ "\x01", "\x02" },
             .items = multi_items,
         } },
+        .{ .name = "packed_large", .msg = .{
+            .doubles = &large.double,
+            .bools = &large.bools,
+        } },
+        .{ .name = "alias_views", .msg = .{
+            .strings = &views.views,
+            .byte_slices = &views.views,
+            .items = &views.items,
+        } },
+        .{ .name = "packed_bools_nonzero", .msg = .{ .bools = &.{ true, false, true, true } } },
     };
+    // cases points into large and views, so it is not comptime-known and
+    // cannot be concatenated with ++.
+    var all: [cases.len + repeated_mixed_cases.len]RepeatedCase = undefined;
+    @memcpy(all[0..cases.len], &cases);
+    @memcpy(all[cases.len..], &repeated_mixed_cases);
 
-    try write_test_vectors(RepeatedMessage, &cases, "testdata/zig/repeated3.bin");
+    try write_test_vectors(RepeatedMessage, &all, "testdata/zig/repeated3.bin");
 }
 
 // ── Map3 Tests ────────────────────────────────────────────────────────
@@ -854,6 +1194,41 @@ test "map3: encode/decode round-trip - multiple" {
     try testing.expectEqualStrings("y", sub_y.text);
 }
 
+/// A map3 case by its entries, for the entry_ cases that Go writes with the
+/// fields of each map entry out of order, missing, repeated or among
+/// unknown fields; see go/testcases/map3.go.
+const MapEntryCase = struct {
+    name: []const u8,
+    str_str: []const struct { []const u8, []const u8 } = &.{},
+    int_str: []const struct { i32, []const u8 } = &.{},
+    str_msg: []const struct { []const u8, MapSubMsg } = &.{},
+};
+
+const map_entry_cases = [_]MapEntryCase{
+    .{ .name = "entry_value_first_str_str", .str_str = &.{.{ "key", "val" }} },
+    .{ .name = "entry_value_first_int_str", .int_str = &.{.{ 1, "one" }} },
+    .{ .name = "entry_value_first_str_msg", .str_msg = &.{.{ "a", .{ .id = 1, .text = "alpha" } }} },
+    .{ .name = "entry_unknown_before", .str_str = &.{.{ "key", "val" }} },
+    .{ .name = "entry_unknown_between", .int_str = &.{.{ 5, "five" }} },
+    .{ .name = "entry_unknown_after", .str_str = &.{.{ "key", "val" }} },
+    .{ .name = "entry_unknown_group", .str_str = &.{.{ "key", "val" }} },
+    .{ .name = "entry_unknown_value_first_str_msg", .str_msg = &.{.{ "b", .{ .id = 2 } }} },
+    .{ .name = "entry_key_only", .int_str = &.{.{ 5, "" }} },
+    .{ .name = "entry_value_only", .str_str = &.{.{ "", "v" }} },
+    .{ .name = "entry_empty", .str_msg = &.{.{ "", .{} }} },
+    .{ .name = "entry_fields_repeated", .str_str = &.{.{ "b", "2" }} },
+    .{ .name = "entry_mixed", .str_str = &.{ .{ "a", "1" }, .{ "b", "2" }, .{ "c", "3" } }, .int_str = &.{.{ 3, "three" }} },
+};
+
+/// Builds the message c describes, its maps in arena.
+fn map_entry_msg(arena: std.mem.Allocator, c: MapEntryCase) !MapMessage {
+    var msg: MapMessage = .{};
+    for (c.str_str) |e| try msg.str_str.put(arena, e[0], e[1]);
+    for (c.int_str) |e| try msg.int_str.put(arena, e[0], e[1]);
+    for (c.str_msg) |e| try msg.str_msg.put(arena, e[0], e[1]);
+    return msg;
+}
+
 test "map3: read Go test vectors" {
     const file_data = try read_go_vectors("testdata/go/map3.bin");
     if (file_data == null) return;
@@ -891,6 +1266,22 @@ test "map3: read Go test vectors" {
             try testing.expectEqual(@as(i32, 10), sub_x.id);
             const sub_y = decoded.str_msg.get("y").?;
             try testing.expectEqual(@as(i32, 20), sub_y.id);
+        } else if (std.mem.startsWith(u8, tc.name, "entry_")) {
+            // However the fields of each entry came, the maps hold exactly
+            // these entries.
+            for (map_entry_cases) |want| {
+                if (!std.mem.eql(u8, tc.name, want.name)) continue;
+                try testing.expectEqual(want.str_str.len, decoded.str_str.count());
+                for (want.str_str) |e| try testing.expectEqualStrings(e[1], decoded.str_str.get(e[0]).?);
+                try testing.expectEqual(want.int_str.len, decoded.int_str.count());
+                for (want.int_str) |e| try testing.expectEqualStrings(e[1], decoded.int_str.get(e[0]).?);
+                try testing.expectEqual(want.str_msg.len, decoded.str_msg.count());
+                for (want.str_msg) |e| {
+                    const sub = decoded.str_msg.get(e[0]).?;
+                    try testing.expectEqual(e[1].id, sub.id);
+                    try testing.expectEqualStrings(e[1].text, sub.text);
+                }
+            }
         }
     }
 }
@@ -908,6 +1299,7 @@ test "map3: write Zig test vectors" {
 
     var buf: [65536]u8 = undefined;
     var w: std.Io.Writer = .fixed(&buf);
+    try framing.write_header(&w);
 
     // empty
     {
@@ -965,9 +1357,143 @@ test "map3: write Zig test vectors" {
         try framing.write_test_case(&w, "multiple", msg_w.buffered());
     }
 
+    // entry_ cases, written in the usual field order
+    {
+        var arena_state = std.heap.ArenaAllocator.init(testing.allocator);
+        defer arena_state.deinit();
+        for (map_entry_cases) |c| {
+            const msg = try map_entry_msg(arena_state.allocator(), c);
+            var msg_buf: [8192]u8 = undefined;
+            var msg_w: std.Io.Writer = .fixed(&msg_buf);
+            try msg.encode(&msg_w);
+            try framing.write_test_case(&w, c.name, msg_w.buffered());
+        }
+    }
+
     try file.writeAll(w.buffered());
 }
 
+// ── NestedMap3 Tests (maps of messages holding maps) ─────────────────
+
+/// Builds a layer of size n in arena: n counts, n leaves with up to two
+/// attributes each and n%4 buckets of n names each; see
+/// go/testcases/nestedmap3.go.
+fn nested_layer(arena: std.mem.Allocator, n: usize) !NestedMapLayer {
+    var layer: NestedMapLayer = .{};
+    for (0..n) |i| {
+        try layer.counts.put(arena, try std.fmt.allocPrint(arena, "c{d}", .{i}), @intCast(i));
+        var leaf: NestedMapLeaf = .{ .text = try std.fmt.allocPrint(arena, "leaf{d}", .{i}) };
+        for (0..i % 3) |j| {
+            try leaf.attrs.put(arena, try std.fmt.allocPrint(arena, "k{d}", .{j}), try std.fmt.allocPrint(arena, "v{d}", .{j}));
+        }
+        try layer.leaves.put(arena, @as(i64, @intCast(i)) * 1000 - 1, leaf);
+    }
+    const buckets = try arena.alloc(NestedMapBucket, n % 4);
+    for (buckets) |*bucket| {
+        bucket.* = .{};
+        try bucket.flags.put(arena, true, "\x01");
+        try bucket.flags.put(arena, false, "");
+        for (0..n) |j| try bucket.names.put(arena, @intCast(j), try std.fmt.allocPrint(arena, "n{d}", .{j}));
+    }
+    layer.buckets = buckets;
+    return layer;
+}
+
+const NestedMapCase = struct { name: []const u8, msg: NestedMaps };
+
+/// Builds every nestedmap3 case in arena.
+fn nested_map_cases(arena: std.mem.Allocator) ![]NestedMapCase {
+    var empty_values: NestedMaps = .{};
+    try empty_values.layers.put(arena, "", .{});
+    try empty_values.by_id.put(arena, 0, .{});
+
+    var single: NestedMaps = .{};
+    try single.layers.put(arena, "a", try nested_layer(arena, 1));
+    try single.by_id.put(arena, 1, try nested_layer(arena, 1));
+
+    var multi_entry: NestedMaps = .{};
+    for ([_][]const u8{ "a", "b", "c" }, 2..) |key, n| try multi_entry.layers.put(arena, key, try nested_layer(arena, n));
+    for ([_]i32{ -1, 0, 7 }, [_]usize{ 3, 0, 5 }) |key, n| try multi_entry.by_id.put(arena, key, try nested_layer(arena, n));
+
+    var shared_keys: NestedMaps = .{};
+    for ([_][]const u8{ "c0", "c1" }) |key| try shared_keys.layers.put(arena, key, try nested_layer(arena, 2));
+    for ([_]i32{ 0, 1 }) |key| try shared_keys.by_id.put(arena, key, try nested_layer(arena, 2));
+
+    var wide: NestedMaps = .{};
+    for (0..32) |i| {
+        try wide.layers.put(arena, try std.fmt.allocPrint(arena, "l{d}", .{i}), try nested_layer(arena, i % 8));
+        try wide.by_id.put(arena, @intCast(i), try nested_layer(arena, 8));
+    }
+
+    return arena.dupe(NestedMapCase, &.{
+        .{ .name = "empty", .msg = .{} },
+        .{ .name = "empty_values", .msg = empty_values },
+        .{ .name = "single", .msg = single },
+        .{ .name = "multi_entry", .msg = multi_entry },
+        .{ .name = "shared_keys", .msg = shared_keys },
+        .{ .name = "wide", .msg = wide },
+    });
+}
+
+/// Checks that every entry of want is in got with an equal value, and that
+/// got has no others.
+fn expect_map_eql(want: anytype, got: @TypeOf(want)) !void {
+    try testing.expectEqual(want.count(), got.count());
+    var it = want.iterator();
+    while (it.next()) |entry| {
+        const value = got.get(entry.key_ptr.*) orelse return error.TestExpectedEqual;
+        switch (@TypeOf(value)) {
+            []const u8 => try testing.expectEqualStrings(entry.value_ptr.*, value),
+            NestedMapLayer => try expect_layer_eql(entry.value_ptr.*, value),
+            NestedMapLeaf => {
+                try testing.expectEqualStrings(entry.value_ptr.text, value.text);
+                try expect_map_eql(entry.value_ptr.attrs, value.attrs);
+            },
+            else => try testing.expectEqual(entry.value_ptr.*, value),
+        }
+    }
+}
+
+fn expect_layer_eql(want: NestedMapLayer, got: NestedMapLayer) !void {
+    try expect_map_eql(want.counts, got.counts);
+    try expect_map_eql(want.leaves, got.leaves);
+    try testing.expectEqual(want.buckets.len, got.buckets.len);
+    for (want.buckets, got.buckets) |w, g| {
+        try expect_map_eql(w.names, g.names);
+        try expect_map_eql(w.flags, g.flags);
+    }
+}
+
+test "nestedmap3: read Go test vectors" {
+    const file_data = try read_go_vectors("testdata/go/nestedmap3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    var arena_state = std.heap.ArenaAllocator.init(testing.allocator);
+    defer arena_state.deinit();
+    const expected = try nested_map_cases(arena_state.allocator());
+
+    for (cases) |tc| {
+        var decoded = try NestedMaps.decode(testing.allocator, tc.data);
+        defer decoded.deinit(testing.allocator);
+
+        for (expected) |want| {
+            if (!std.mem.eql(u8, tc.name, want.name)) continue;
+            try expect_map_eql(want.msg.layers, decoded.layers);
+            try expect_map_eql(want.msg.by_id, decoded.by_id);
+        }
+    }
+}
+
+test "nestedmap3: write Zig test vectors" {
+    var arena_state = std.heap.ArenaAllocator.init(testing.allocator);
+    defer arena_state.deinit();
+    try write_test_vectors(NestedMaps, try nested_map_cases(arena_state.allocator()), "testdata/zig/nestedmap3.bin");
+}
+
 // ── Optional3 Tests ───────────────────────────────────────────────────
 
 test "optional3: encode/decode round-trip - all unset" {
@@ -1452,11 +1978,64 @@ test "acp: read Go test vectors" {
         } else if (std.mem.eql(u8, tc.name, "deload")) {
             try testing.expectEqual(AcpMessageKind.DELOAD, decoded.kind);
             try testing.expectEqual(@as(usize, 1), decoded.uris.len);
+        } else if (std.mem.startsWith(u8, tc.name, "payload_")) {
+            try testing.expectEqual(AcpMessageKind.UPDATED, decoded.kind);
+            try testing.expectEqual(@as(u32, 1), decoded.chunk_total);
+            try expect_acp_payload(decoded);
         }
     }
 }
 
+// The asset carried by the payload cases; see testcases.AcpAsset.
+fn acp_asset() [4096]u8 {
+    var asset: [4096]u8 = undefined;
+    for (&asset, 0..) |*b, i| b.* = @intCast('a' + (i + i / 64) % 26);
+    return asset;
+}
+
+fn acp_payload_hash(data: []const u8) [71]u8 {
+    var digest: [std.crypto.hash.sha2.Sha256.digest_length]u8 = undefined;
+    std.crypto.hash.sha2.Sha256.hash(data, &digest, .{});
+    var out: [71]u8 = undefined;
+    @memcpy(out[0..7], "sha256:");
+    out[7..].* = std.fmt.bytesToHex(digest, .lower);
+    return out;
+}
+
+fn decompress_acp_payload(allocator: std.mem.Allocator, compression: AcpCompression, chunk: []const u8) ![]u8 {
+    var in: std.Io.Reader = .fixed(chunk);
+    switch (compression) {
+        .UNCOMPRESSED => return allocator.dupe(u8, chunk),
+        .GZIP => {
+            var window: [std.compress.flate.max_window_len]u8 = undefined;
+            var d: std.compress.flate.Decompress = .init(&in, .gzip, &window);
+            return d.reader.allocRemaining(allocator, .unlimited);
+        },
+        .ZSTD => {
+            const window = try allocator.alloc(u8, std.compress.zstd.default_window_len + std.compress.zstd.block_size_max);
+            defer allocator.free(window);
+            var d: std.compress.zstd.Decompress = .init(&in, window, .{});
+            return d.reader.allocRemaining(allocator, .unlimited);
+        },
+        _ => return error.UnknownCompression,
+    }
+}
+
+// Decompresses a single-chunk payload as its metadata says and checks it
+// against payload_hash and file_length.
+fn expect_acp_payload(msg: AcpMessage) !void {
+    const meta = msg.metadata orelse return error.MissingMetadata;
+    const data = try decompress_acp_payload(testing.allocator, meta.compression, msg.payload_chunk);
+    defer testing.allocator.free(data);
+    try testing.expectEqual(meta.file_length, @as(i64, @intCast(data.len)));
+    try testing.expectEqualStrings(meta.payload_hash, &acp_payload_hash(data));
+    const asset = acp_asset();
+    try testing.expectEqualSlices(u8, &asset, data);
+}
+
 test "acp: write Zig test vectors" {
+    const asset = acp_asset();
+    const payload_hash = acp_payload_hash(&asset);
     const discover_uris = &[_][]const u8{
         "asset://models/tree.glb",
         "asset://textures/bark.png",
@@ -1532,6 +2111,24 @@ test "acp: write Zig test vectors" {
             .kind = .DELOAD,
             .uris = deload_uris,
         } },
+        // Zig's std can decompress gzip and zstd but not produce them, so the
+        // compressed payload cases only travel Go to Zig.
+        .{ .name = "payload_uncompressed", .msg = .{
+            .kind = .UPDATED,
+            .request_id = 300,
+            .uri = "asset://meshes/rock.bin",
+            .chunk_index = 0,
+            .chunk_total = 1,
+            .metadata = .{
+                .uri = "asset://meshes/rock.bin",
+                .cache_path = "/var/cache/acp/rock",
+                .payload_hash = &payload_hash,
+                .file_length = asset.len,
+                .uri_version = 1,
+                .compression = .UNCOMPRESSED,
+            },
+            .payload_chunk = &asset,
+        } },
     };
 
     try write_test_vectors(AcpMessage, &cases, "testdata/zig/acp.bin");
@@ -2903,3 +3500,1588 @@ test "packed3: encode/decode round-trip - all fields populated" {
     try testing.expectEqualSlices(i32, msg.f_int32, decoded.f_int32);
     try testing.expectEqualSlices(i64, msg.f_int64, decoded.f_int64);
 }
+
+// Zigzag varint widths step up between each pair: see zigzag32Boundaries in
+// go/testcases/packed3.go, which these must match.
+const zigzag32_boundaries = [_]i32{ 0, -1, 1, -64, 63, -65, 64, -8192, 8191, -8193, 8192, -1048576, 1048575, -134217728, 134217727, std.math.minInt(i32), std.math.maxInt(i32) };
+const zigzag64_boundaries = [_]i64{ 0, -1, 1, -64, 63, -65, 64, -8192, 8191, -8193, 8192, std.math.minInt(i32), std.math.maxInt(i32), -1 << 55, (1 << 55) - 1, -1 << 62, (1 << 62) - 1, std.math.minInt(i64), std.math.maxInt(i64) };
+
+// Float32 values compared by bit pattern; see packedFloatBits in
+// go/testcases/packed3.go.
+const packed_float_bits = [_]u32{ 0x8000_0000, 0x0000_0001, 0x007f_ffff, 0x7fc0_1234, 0xffc0_0001, 0x7f80_0000, 0x3dcc_cccd };
+const packed_floats: [packed_float_bits.len]f32 = @bitCast(packed_float_bits);
+
+// Values of the large packed cases, matching largeFixed and largeRepeated in
+// go/testcases. Filled at runtime, since comptime loops this long would need
+// a raised branch quota.
+const large_count = 1024;
+
+const LargePacked = struct {
+    fixed32: [large_count]u32,
+    fixed64: [large_count]u64,
+    sfixed32: [large_count]i32,
+    sfixed64: [large_count]i64,
+    float: [large_count]f32,
+    double: [large_count]f64,
+    bools: [large_count]bool,
+
+    fn fill(self: *LargePacked) void {
+        for (0..large_count) |i| {
+            const odd = i % 2 == 1;
+            const s32: i32 = @intCast(i * 7919);
+            const s64: i64 = @intCast(i << 40 | i);
+            self.fixed32[i] = @as(u32, @intCast(i)) *% 2654435761;
+            self.fixed64[i] = @as(u64, i) *% 0x9e3779b97f4a7c15;
+            self.sfixed32[i] = if (odd) -s32 else s32;
+            self.sfixed64[i] = if (odd) -s64 else s64;
+            self.float[i] = @as(f32, @floatFromInt(i)) * 0.25 - 100;
+            self.double[i] = @as(f64, @floatFromInt(i)) / 8 - 50;
+            self.bools[i] = i % 3 == 0;
+        }
+    }
+};
+
+// Values of repeated3's alias_views case, matching aliasViews in
+// go/testcases/repeated3.go: overlapping windows of one buffer.
+const alias_view_count = 256;
+
+const AliasViews = struct {
+    buf: [4096]u8,
+    views: [alias_view_count][]const u8,
+    items: [alias_view_count]RepItem,
+
+    fn fill(self: *AliasViews) void {
+        for (&self.buf, 0..) |*c, i| c.* = @intCast('a' + i % 26);
+        for (0..alias_view_count) |i| {
+            const off = i * 13 % self.buf.len;
+            const end = @min(off + 1 + i % 40, self.buf.len);
+            self.views[i] = self.buf[off..end];
+            self.items[i] = .{ .id = @intCast(i), .name = self.buf[end - 1 .. end] };
+        }
+    }
+};
+
+test "packed3: read Go test vectors" {
+    const file_data = try read_go_vectors("testdata/go/packed3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var decoded = try PackedScalars.decode(testing.allocator, tc.data);
+        defer decoded.deinit(testing.allocator);
+
+        if (std.mem.eql(u8, tc.name, "empty")) {
+            try testing.expectEqual(@as(usize, 0), decoded.f_sint32.len);
+            try testing.expectEqual(@as(usize, 0), decoded.f_sint64.len);
+            try testing.expectEqual(@as(usize, 0), decoded.f_fixed32.len);
+            try testing.expectEqual(@as(usize, 0), decoded.f_fixed64.len);
+            try testing.expectEqual(@as(usize, 0), decoded.f_sfixed32.len);
+            try testing.expectEqual(@as(usize, 0), decoded.f_sfixed64.len);
+            try testing.expectEqual(@as(usize, 0), decoded.f_float.len);
+        } else if (std.mem.eql(u8, tc.name, "sint_extremes")) {
+            try testing.expectEqualSlices(i32, &.{ -1, std.math.minInt(i32), std.math.maxInt(i32) }, decoded.f_sint32);
+            try testing.expectEqualSlices(i64, &.{ -1, std.math.minInt(i64), std.math.maxInt(i64) }, decoded.f_sint64);
+        } else if (std.mem.eql(u8, tc.name, "sint_alternating")) {
+            try testing.expectEqualSlices(i32, &zigzag32_boundaries, decoded.f_sint32);
+            try testing.expectEqualSlices(i64, &zigzag64_boundaries, decoded.f_sint64);
+        } else if (std.mem.eql(u8, tc.name, "float_bits")) {
+            try testing.expectEqual(packed_float_bits.len, decoded.f_float.len);
+            for (packed_float_bits, decoded.f_float) |want, got| {
+                try testing.expectEqual(want, @as(u32, @bitCast(got)));
+            }
+        } else if (std.mem.eql(u8, tc.name, "fixed_single")) {
+            try testing.expectEqualSlices(u32, &.{std.math.maxInt(u32)}, decoded.f_fixed32);
+            try testing.expectEqualSlices(u64, &.{std.math.maxInt(u64)}, decoded.f_fixed64);
+            try testing.expectEqualSlices(i32, &.{std.math.minInt(i32)}, decoded.f_sfixed32);
+            try testing.expectEqualSlices(i64, &.{std.math.minInt(i64)}, decoded.f_sfixed64);
+            try testing.expectEqualSlices(f32, &.{1.5}, decoded.f_float);
+        } else if (std.mem.eql(u8, tc.name, "fixed_large")) {
+            var large: LargePacked = undefined;
+            large.fill();
+            try testing.expectEqualSlices(u32, &large.fixed32, decoded.f_fixed32);
+            try testing.expectEqualSlices(u64, &large.fixed64, decoded.f_fixed64);
+            try testing.expectEqualSlices(i32, &large.sfixed32, decoded.f_sfixed32);
+            try testing.expectEqualSlices(i64, &large.sfixed64, decoded.f_sfixed64);
+            try testing.expectEqualSlices(f32, &large.float, decoded.f_float);
+        }
+    }
+}
+
+test "packed3: write Zig test vectors" {
+    var large: LargePacked = undefined;
+    large.fill();
+    const cases = [_]struct { name: []const u8, msg: PackedScalars }{
+        .{ .name = "empty", .msg = .{} },
+        .{ .name = "sint_extremes", .msg = .{
+            .f_sint32 = &.{ -1, std.math.minInt(i32), std.math.maxInt(i32) },
+            .f_sint64 = &.{ -1, std.math.minInt(i64), std.math.maxInt(i64) },
+        } },
+        .{ .name = "sint_alternating", .msg = .{
+            .f_sint32 = &zigzag32_boundaries,
+            .f_sint64 = &zigzag64_boundaries,
+        } },
+        .{ .name = "float_bits", .msg = .{
+            .f_float = &packed_floats,
+        } },
+        .{ .name = "fixed_single", .msg = .{
+            .f_fixed32 = &.{std.math.maxInt(u32)},
+            .f_fixed64 = &.{std.math.maxInt(u64)},
+            .f_sfixed32 = &.{std.math.minInt(i32)},
+            .f_sfixed64 = &.{std.math.minInt(i64)},
+            .f_float = &.{1.5},
+        } },
+        .{ .name = "fixed_large", .msg = .{
+            .f_fixed32 = &large.fixed32,
+            .f_fixed64 = &large.fixed64,
+            .f_sfixed32 = &large.sfixed32,
+            .f_sfixed64 = &large.sfixed64,
+            .f_float = &large.float,
+        } },
+    };
+
+    try write_test_vectors(PackedScalars, &cases, "testdata/zig/packed3.bin");
+}
+
+// ── Unknown3 Tests ────────────────────────────────────────────────────
+
+// Fields Inner does not declare, one per wire type; see go/testcases/unknown3.go.
+const unknown_varint = "\xa0\x06\x96\x01";
+const unknown_i64 = "\xa9\x06\x01\x02\x03\x04\x05\x06\x07\x08";
+const unknown_len = "\xb2\x06\x03abc";
+const unknown_group = "\xbb\x06\x08\x01\xbc\x06";
+const unknown_i32 = "\xc5\x06\x01\x02\x03\x04";
+const unknown_max_field = "\xf8\xff\xff\xff\x0f\x01";
+const unknown_all = unknown_varint ++ unknown_i64 ++ unknown_len ++ unknown_group ++ unknown_i32 ++ unknown_max_field;
+// Groups that are empty, nested, or hold every wire type.
+const unknown_group_empty = "\xcb\x06\xcc\x06";
+const unknown_group_nested = "\xd3\x06\xdb\x06\xe3\x06\x08\x02\xe4\x06\xdc\x06\x10\x03\xd4\x06";
+const unknown_group_all_wire_types = "\xeb\x06\x08\x01\x11\x02\x00\x00\x00\x00\x00\x00\x00\x1a\x03grp\x23\x24\x2d\x05\x00\x00\x00\xec\x06";
+
+const unknown_cases = [_]struct { name: []const u8, msg: Inner }{
+    .{ .name = "varint", .msg = .{ ._unknown_fields = unknown_varint } },
+    .{ .name = "i64", .msg = .{ ._unknown_fields = unknown_i64 } },
+    .{ .name = "len", .msg = .{ ._unknown_fields = unknown_len } },
+    .{ .name = "group", .msg = .{ ._unknown_fields = unknown_group } },
+    .{ .name = "i32", .msg = .{ ._unknown_fields = unknown_i32 } },
+    .{ .name = "max_field_number", .msg = .{ ._unknown_fields = unknown_max_field } },
+    .{ .name = "all_wire_types", .msg = .{ ._unknown_fields = unknown_all } },
+    .{ .name = "group_empty", .msg = .{ ._unknown_fields = unknown_group_empty } },
+    .{ .name = "group_nested", .msg = .{ ._unknown_fields = unknown_group_nested } },
+    .{ .name = "group_all_wire_types", .msg = .{ ._unknown_fields = unknown_group_all_wire_types } },
+};
+
+test "unknown3: read Go test vectors" {
+    const file_data = try read_go_vectors("testdata/go/unknown3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var decoded = try Inner.decode(testing.allocator, tc.data);
+        defer decoded.deinit(testing.allocator);
+
+        for (unknown_cases) |want| {
+            if (!std.mem.eql(u8, tc.name, want.name)) continue;
+            // Nothing known was set, but the bytes survive a re-encode.
+            try testing.expectEqual(@as(i32, 0), decoded.value);
+            try testing.expectEqualStrings("", decoded.label);
+            try testing.expectEqualSlices(u8, want.msg._unknown_fields, decoded._unknown_fields);
+            const again = try encode_to_buf(Inner, decoded);
+            defer testing.allocator.free(again);
+            try testing.expectEqualSlices(u8, tc.data, again);
+        }
+    }
+}
+
+test "unknown3: write Zig test vectors" {
+    try write_test_vectors(Inner, &unknown_cases, "testdata/zig/unknown3.bin");
+}
+
+test "unknown3: reject unterminated Go groups" {
+    // Groups missing their END_GROUP, at the top level or nested, closed
+    // with the wrong field number, or nested far too deep.
+    const file_data = try read_go_vectors("testdata/go/reject/unknown3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        if (Inner.decode(testing.allocator, tc.data)) |decoded| {
+            var msg = decoded;
+            msg.deinit(testing.allocator);
+            std.debug.print("reject/unknown3.bin: {s}: decoded without error\n", .{tc.name});
+            return error.TestUnexpectedResult;
+        } else |_| {}
+    }
+}
+
+// ── Imports3 Tests (types from imported and publicly imported files) ──
+
+test "imports3: read Go test vectors" {
+    const file_data = try read_go_vectors("testdata/go/imports3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var decoded = try ImportsMessage.decode(testing.allocator, tc.data);
+        defer decoded.deinit(testing.allocator);
+
+        if (std.mem.eql(u8, tc.name, "empty")) {
+            try testing.expectEqual(@as(?Point, null), decoded.origin);
+            try testing.expectEqual(Level.LEVEL_UNSPECIFIED, decoded.level);
+            try testing.expectEqual(@as(?Tagged, null), decoded.tagged);
+            try testing.expectEqual(@as(usize, 0), decoded.path.len);
+        } else if (std.mem.eql(u8, tc.name, "all_set")) {
+            try testing.expectEqual(@as(i32, 1), decoded.origin.?.x);
+            try testing.expectEqual(@as(i32, -2), decoded.origin.?.y);
+            try testing.expectEqual(Level.LEVEL_HIGH, decoded.level);
+            try testing.expectEqualStrings("checkpoint", decoded.tagged.?.tag);
+            try testing.expectEqual(@as(i32, 30), decoded.tagged.?.point.?.x);
+            try testing.expectEqual(@as(i32, 40), decoded.tagged.?.point.?.y);
+            try testing.expectEqual(Level.LEVEL_LOW, decoded.tagged.?.level);
+        } else if (std.mem.eql(u8, tc.name, "path")) {
+            try testing.expectEqual(@as(usize, 3), decoded.path.len);
+            try testing.expectEqual(@as(i32, 1), decoded.path[1].x);
+            try testing.expectEqual(@as(i32, -5), decoded.path[2].x);
+            try testing.expectEqual(@as(i32, 7), decoded.path[2].y);
+        }
+    }
+}
+
+test "imports3: write Zig test vectors" {
+    const path = &[_]Point{
+        .{ .x = 0, .y = 0 },
+        .{ .x = 1, .y = 1 },
+        .{ .x = -5, .y = 7 },
+    };
+    const cases = [_]struct { name: []const u8, msg: ImportsMessage }{
+        .{ .name = "empty", .msg = .{} },
+        .{ .name = "all_set", .msg = .{
+            .origin = .{ .x = 1, .y = -2 },
+            .level = .LEVEL_HIGH,
+            .tagged = .{
+                .tag = "checkpoint",
+                .point = .{ .x = 30, .y = 40 },
+                .level = .LEVEL_LOW,
+            },
+        } },
+        .{ .name = "path", .msg = .{ .path = path } },
+    };
+
+    try write_test_vectors(ImportsMessage, &cases, "testdata/zig/imports3.bin");
+}
+
+// ── Naming3 Tests (nested scopes, repeated short names, keywords) ──────
+
+test "naming3: read Go test vectors" {
+    const file_data = try read_go_vectors("testdata/go/naming3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var decoded = try Naming.decode(testing.allocator, tc.data);
+        defer decoded.deinit(testing.allocator);
+
+        if (std.mem.eql(u8, tc.name, "empty")) {
+            try testing.expectEqual(@as(?Naming.Node, null), decoded.node);
+            try testing.expectEqualStrings("", decoded.@"type");
+            try testing.expectEqual(false, decoded.@"var");
+        } else if (std.mem.eql(u8, tc.name, "keywords")) {
+            try testing.expectEqualStrings("type", decoded.@"type");
+            try testing.expectEqual(@as(i32, -1), decoded.@"fn");
+            try testing.expectEqualStrings("error", decoded.@"error");
+            try testing.expectEqual(true, decoded.@"var");
+        } else if (std.mem.eql(u8, tc.name, "nested")) {
+            try testing.expectEqual(Naming.Node.Kind.KIND_BRANCH, decoded.node.?.kind);
+            try testing.expectEqualStrings("leaf", decoded.node.?.child.?.@"type");
+            try testing.expectEqual(@as(i32, 7), decoded.other_node.?.@"fn");
+            try testing.expectEqual(@as(i32, 8), decoded.other.?.node.?.@"fn");
+            try testing.expectEqual(Naming.Other.Kind.KIND_LEAF, decoded.other.?.kind);
+            try testing.expectEqual(Naming.Deep.Deeper.Deepest.Depth.DEPTH_BOTTOM, decoded.deepest.?.depth);
+            try testing.expectEqualStrings("bottom", decoded.deepest.?.@"var");
+        }
+    }
+}
+
+test "naming3: write Zig test vectors" {
+    const cases = [_]struct { name: []const u8, msg: Naming }{
+        .{ .name = "empty", .msg = .{} },
+        .{ .name = "keywords", .msg = .{
+            .@"type" = "type",
+            .@"fn" = -1,
+            .@"error" = "error",
+            .@"var" = true,
+        } },
+        .{ .name = "nested", .msg = .{
+            .node = .{ .kind = .KIND_BRANCH, .child = .{ .@"type" = "leaf" } },
+            .other_node = .{ .@"fn" = 7 },
+            .other = .{ .node = .{ .@"fn" = 8 }, .kind = .KIND_LEAF },
+            .deepest = .{ .depth = .DEPTH_BOTTOM, .@"var" = "bottom" },
+        } },
+    };
+
+    try write_test_vectors(Naming, &cases, "testdata/zig/naming3.bin");
+}
+
+// ── JSON3 Tests (json_name options and awkward field names) ──────────
+
+fn expect_json3_case(name: []const u8, msg: JsonNames) !void {
+    if (std.mem.eql(u8, name, "empty")) {
+        try testing.expectEqual(@as(i32, 0), msg.plain);
+        try testing.expectEqual(@as(?*JsonNames, null), msg.child);
+    } else if (std.mem.eql(u8, name, "all_set")) {
+        try testing.expectEqual(@as(i32, 1), msg.plain);
+        try testing.expectEqualStrings("underscore", msg._leading_underscore);
+        try testing.expectEqual(@as(i32, 2), msg.field_1_digit);
+        try testing.expectEqualStrings("caps", msg.ALLCAPS);
+        try testing.expectEqual(@as(i32, 3), msg.mixed_Case_field);
+        try testing.expectEqualStrings("renamed", msg.renamed);
+        try testing.expectEqualSlices(i32, &.{ 4, 5, 6 }, msg.list_of_values);
+    } else if (std.mem.eql(u8, name, "nested")) {
+        try testing.expectEqual(@as(i32, 10), msg.plain);
+        try testing.expectEqual(@as(i32, 11), msg.child.?.plain);
+        try testing.expectEqualStrings("inner", msg.child.?.renamed);
+    }
+}
+
+test "json3: read Go test vectors" {
+    const file_data = try read_go_vectors("testdata/go/json3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var decoded = try JsonNames.decode(testing.allocator, tc.data);
+        defer decoded.deinit(testing.allocator);
+        try expect_json3_case(tc.name, decoded);
+    }
+}
+
+test "json3: read Go JSON vectors" {
+    const file_data = try read_go_vectors("testdata/go/json/json3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var decoded = try JsonNames.from_json(testing.allocator, tc.data);
+        defer decoded.deinit(testing.allocator);
+        try expect_json3_case(tc.name, decoded);
+    }
+}
+
+test "json3: write Zig test vectors" {
+    // child is recursive, so it is held by (mutable) pointer.
+    var child: JsonNames = .{ .plain = 11, .renamed = "inner" };
+    const cases = [_]struct { name: []const u8, msg: JsonNames }{
+        .{ .name = "empty", .msg = .{} },
+        .{ .name = "all_set", .msg = .{
+            .plain = 1,
+            ._leading_underscore = "underscore",
+            .field_1_digit = 2,
+            .ALLCAPS = "caps",
+            .mixed_Case_field = 3,
+            .renamed = "renamed",
+            .list_of_values = &.{ 4, 5, 6 },
+        } },
+        .{ .name = "nested", .msg = .{
+            .plain = 10,
+            .child = &child,
+        } },
+    };
+
+    try write_test_vectors(JsonNames, &cases, "testdata/zig/json3.bin");
+    try write_json_vectors(JsonNames, &cases, "testdata/zig/json/json3.bin");
+}
+
+// ── MessageSet2 Tests (legacy wire format kept as unknown data) ──────
+
+// Hand-encoded MessageSet items; see go/testcases/messageset2.go.
+const message_set_item_a = "\x0b\x10\xe8\x07\x1a\x02\x08\x2a\x0c";
+const message_set_item_b = "\x0b\x10\xd0\x0f\x1a\x07\x0a\x05hello\x0c";
+const message_set_unknown_type_id = "\x0b\x10\xb8\x17\x1a\x02\x08\x07\x0c";
+const message_set_message_first = "\x0b\x1a\x0b\x08\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01\x10\xe8\x07\x0c";
+
+const message_set_cases = [_]struct { name: []const u8, msg: MessageSetContainer }{
+    .{ .name = "empty", .msg = .{} },
+    .{ .name = "item_a", .msg = .{ ._unknown_fields = message_set_item_a } },
+    .{ .name = "item_b", .msg = .{ ._unknown_fields = message_set_item_b } },
+    .{ .name = "both", .msg = .{ ._unknown_fields = message_set_item_a ++ message_set_item_b } },
+    .{ .name = "unknown_type_id", .msg = .{ ._unknown_fields = message_set_unknown_type_id } },
+    .{ .name = "message_first", .msg = .{ ._unknown_fields = message_set_message_first } },
+};
+
+test "messageset2: read Go test vectors" {
+    const file_data = try read_go_vectors("testdata/go/messageset2.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var decoded = try MessageSetContainer.decode(testing.allocator, tc.data);
+        defer decoded.deinit(testing.allocator);
+
+        for (message_set_cases) |want| {
+            if (!std.mem.eql(u8, tc.name, want.name)) continue;
+            // Items are not mapped to the extension fields; they stay
+            // unknown and survive a re-encode byte for byte.
+            try testing.expect(decoded.item_a == null);
+            try testing.expect(decoded.item_b == null);
+            try testing.expectEqualSlices(u8, want.msg._unknown_fields, decoded._unknown_fields);
+            const again = try encode_to_buf(MessageSetContainer, decoded);
+            defer testing.allocator.free(again);
+            try testing.expectEqualSlices(u8, tc.data, again);
+        }
+    }
+}
+
+test "messageset2: write Zig test vectors" {
+    try write_test_vectors(MessageSetContainer, &message_set_cases, "testdata/zig/messageset2.bin");
+}
+
+// ── Evolution3 Tests (one message before and after a schema change) ──
+
+// Cases named v1_ are written with EvolutionV1 and read with EvolutionV2,
+// and v2_ cases the reverse; see go/testcases/evolution3.go.
+const evolution_v1_cases = [_]struct { name: []const u8, msg: EvolutionV1 }{
+    .{ .name = "v1_empty", .msg = .{} },
+    .{ .name = "v1_all_set", .msg = .{ .id = 1, .name = "widget", .count = 42, .note = "first" } },
+    .{ .name = "v1_negative_count", .msg = .{ .id = 2, .count = std.math.minInt(i32) } },
+};
+
+const evolution_v2_cases = [_]struct { name: []const u8, msg: EvolutionV2 }{
+    .{ .name = "v2_empty", .msg = .{} },
+    .{ .name = "v2_all_set", .msg = .{
+        .id = 3,
+        .title = "gadget",
+        .count = 7,
+        .detail = .{ .note = "second" },
+        .tags = &.{ "new", "tags" },
+        .archived = true,
+    } },
+    .{ .name = "v2_wide_count", .msg = .{ .id = 4, .count = 0x1_8000_0001 } },
+    .{ .name = "v2_code", .msg = .{ .id = 5, .detail = .{ .code = 9 } } },
+};
+
+/// Re-encodes what an EvolutionV1 reader kept of a v2_ case and checks that
+/// an EvolutionV2 reader gets the original fields back.
+fn expect_evolution_relay(old: EvolutionV1, want: EvolutionV2) !void {
+    const again = try encode_to_buf(EvolutionV1, old);
+    defer testing.allocator.free(again);
+    var got = try EvolutionV2.decode(testing.allocator, again);
+    defer got.deinit(testing.allocator);
+
+    try testing.expectEqual(want.id, got.id);
+    try testing.expectEqualStrings(want.title, got.title);
+    try testing.expectEqual(want.count, got.count);
+    try testing.expectEqual(want.archived, got.archived);
+    try testing.expectEqual(want.tags.len, got.tags.len);
+    for (want.tags, got.tags) |w, g| try testing.expectEqualStrings(w, g);
+    try testing.expectEqual(std.meta.activeTag(want.detail.?), std.meta.activeTag(got.detail.?));
+    switch (want.detail.?) {
+        .note => |v| try testing.expectEqualStrings(v, got.detail.?.note),
+        .code => |v| try testing.expectEqual(v, got.detail.?.code),
+    }
+}
+
+test "evolution3: read Go test vectors with the other version" {
+    const file_data = try read_go_vectors("testdata/go/evolution3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        if (std.mem.startsWith(u8, tc.name, "v1_")) {
+            var decoded = try EvolutionV2.decode(testing.allocator, tc.data);
+            defer decoded.deinit(testing.allocator);
+
+            try testing.expectEqual(@as(usize, 0), decoded.tags.len);
+            try testing.expectEqual(false, decoded.archived);
+            try testing.expectEqualStrings("", decoded._unknown_fields);
+            if (std.mem.eql(u8, tc.name, "v1_empty")) {
+                try testing.expectEqual(@as(?EvolutionV2.Detail, null), decoded.detail);
+            } else if (std.mem.eql(u8, tc.name, "v1_all_set")) {
+                try testing.expectEqual(@as(i32, 1), decoded.id);
+                try testing.expectEqualStrings("widget", decoded.title);
+                try testing.expectEqual(@as(i64, 42), decoded.count);
+                try testing.expectEqualStrings("first", decoded.detail.?.note);
+            } else if (std.mem.eql(u8, tc.name, "v1_negative_count")) {
+                try testing.expectEqual(@as(i32, 2), decoded.id);
+                try testing.expectEqual(@as(i64, std.math.minInt(i32)), decoded.count);
+            }
+            continue;
+        }
+
+        var decoded = try EvolutionV1.decode(testing.allocator, tc.data);
+        defer decoded.deinit(testing.allocator);
+
+        if (std.mem.eql(u8, tc.name, "v2_empty")) {
+            try testing.expectEqual(@as(i32, 0), decoded.id);
+            try testing.expectEqualStrings("", decoded._unknown_fields);
+        } else if (std.mem.eql(u8, tc.name, "v2_all_set")) {
+            try testing.expectEqual(@as(i32, 3), decoded.id);
+            try testing.expectEqualStrings("gadget", decoded.name);
+            try testing.expectEqual(@as(i32, 7), decoded.count);
+            try testing.expectEqualStrings("second", decoded.note);
+            try expect_evolution_relay(decoded, evolution_v2_cases[1].msg);
+        } else if (std.mem.eql(u8, tc.name, "v2_wide_count")) {
+            // Only the low 32 bits survive, as with a C++ cast.
+            try testing.expectEqual(@as(i32, 4), decoded.id);
+            try testing.expectEqual(@as(i32, -0x7fff_ffff), decoded.count);
+        } else if (std.mem.eql(u8, tc.name, "v2_code")) {
+            try testing.expectEqual(@as(i32, 5), decoded.id);
+            try testing.expectEqualStrings("", decoded.note);
+            try expect_evolution_relay(decoded, evolution_v2_cases[3].msg);
+        }
+    }
+}
+
+test "evolution3: write Zig test vectors" {
+    std.fs.cwd().makePath("testdata/zig") catch {};
+    var file = try std.fs.cwd().createFile("testdata/zig/evolution3.bin", .{});
+    defer file.close();
+
+    var w: std.Io.Writer.Allocating = .init(testing.allocator);
+    defer w.deinit();
+    try framing.write_header(&w.writer);
+
+    inline for (.{ &evolution_v1_cases, &evolution_v2_cases }) |cases| {
+        for (cases) |tc| {
+            const data = try encode_to_buf(@TypeOf(tc.msg), tc.msg);
+            defer testing.allocator.free(data);
+            try framing.write_test_case(&w.writer, tc.name, data);
+        }
+    }
+
+    try file.writeAll(w.written());
+}
+
+// ── Reserved3 Tests (data on reserved field numbers) ─────────────────
+
+// Fields on numbers ReservedMessage reserves; see go/testcases/reserved3.go.
+const reserved_single = "\x10\x07";
+const reserved_range = "\x2a\x06legacy" ++ "\x35\x06\x00\x00\x00" ++ "\x39\x07\x00\x00\x00\x00\x00\x00\x00";
+const reserved_to_max = "\xc0\x3e\xe8\x07" ++ "\xfa\xff\xff\xff\x0f\x03max";
+// The reserved fields of the interleaved case, gathered in order.
+const reserved_interleaved_unknown = "\x10\x02" ++ "\x35\x06\x00\x00\x00";
+
+const reserved_cases = [_]struct { name: []const u8, msg: ReservedMessage }{
+    .{ .name = "empty", .msg = .{} },
+    .{ .name = "declared", .msg = .{ .id = 1, .label = "declared", .values = &.{ 1, 2, 3 } } },
+    .{ .name = "reserved_single", .msg = .{ .id = 1, ._unknown_fields = reserved_single } },
+    .{ .name = "reserved_range", .msg = .{ .id = 1, ._unknown_fields = reserved_range } },
+    .{ .name = "reserved_to_max", .msg = .{ .id = 1, ._unknown_fields = reserved_to_max } },
+    .{ .name = "interleaved", .msg = .{
+        .id = 11,
+        .label = "kept",
+        .values = &.{ 1, 2 },
+        ._unknown_fields = reserved_interleaved_unknown,
+    } },
+};
+
+test "reserved3: read Go test vectors" {
+    const file_data = try read_go_vectors("testdata/go/reserved3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var decoded = try ReservedMessage.decode(testing.allocator, tc.data);
+        defer decoded.deinit(testing.allocator);
+
+        for (reserved_cases) |want| {
+            if (!std.mem.eql(u8, tc.name, want.name)) continue;
+            try testing.expectEqual(want.msg.id, decoded.id);
+            try testing.expectEqualStrings(want.msg.label, decoded.label);
+            try testing.expectEqualSlices(i32, want.msg.values, decoded.values);
+            // Reserved numbers are unknown fields like any other.
+            try testing.expectEqualSlices(u8, want.msg._unknown_fields, decoded._unknown_fields);
+            if (std.mem.eql(u8, tc.name, "interleaved")) continue;
+            const again = try encode_to_buf(ReservedMessage, decoded);
+            defer testing.allocator.free(again);
+            try testing.expectEqualSlices(u8, tc.data, again);
+        }
+    }
+}
+
+test "reserved3: write Zig test vectors" {
+    try write_test_vectors(ReservedMessage, &reserved_cases, "testdata/zig/reserved3.bin");
+}
+
+test "descriptor: ReservedMessage fields avoid reserved numbers" {
+    const desc = ReservedMessage.descriptor;
+    try testing.expectEqual(@as(usize, 3), desc.fields.len);
+    for (desc.fields, [_]i32{ 1, 3, 4 }) |f, number| {
+        try testing.expectEqual(number, f.number);
+    }
+}
+
+// ── Tags3 Tests (every change in tag width) ───────────────────────────
+
+// The last and first field number of each tag width; see
+// go/testcases/tags3.go. Each field holds its own number.
+const tag_width_numbers = [_]u64{ 15, 16, 2047, 2048, 262143, 262144, 33554431, 33554432, 536870911 };
+
+const tag_width_cases = blk: {
+    var cases: [2 * tag_width_numbers.len + 2]struct { name: []const u8, msg: TagWidths } = undefined;
+    var all_varint: TagWidths = .{};
+    var all_len: TagWidthStrings = .{};
+    for (tag_width_numbers, 0..) |num, i| {
+        const field = std.fmt.comptimePrint("f{d}", .{num});
+        var varint: TagWidths = .{};
+        @field(varint, field) = num;
+        @field(all_varint, field) = num;
+        var len: TagWidthStrings = .{};
+        @field(len, field) = std.fmt.comptimePrint("{d}", .{num});
+        @field(all_len, field) = std.fmt.comptimePrint("{d}", .{num});
+        cases[2 * i] = .{ .name = std.fmt.comptimePrint("varint_{d}", .{num}), .msg = varint };
+        cases[2 * i + 1] = .{ .name = std.fmt.comptimePrint("len_{d}", .{num}), .msg = .{ .strings = len } };
+    }
+    cases[2 * tag_width_numbers.len] = .{ .name = "varint_all", .msg = all_varint };
+    cases[2 * tag_width_numbers.len + 1] = .{ .name = "len_all", .msg = .{ .strings = all_len } };
+    const final = cases;
+    break :blk final;
+};
+
+test "tags3: read Go test vectors" {
+    const file_data = try read_go_vectors("testdata/go/tags3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var decoded = try TagWidths.decode(testing.allocator, tc.data);
+        defer decoded.deinit(testing.allocator);
+
+        for (tag_width_cases) |want| {
+            if (!std.mem.eql(u8, tc.name, want.name)) continue;
+            try testing.expectEqual(want.msg.strings == null, decoded.strings == null);
+            inline for (tag_width_numbers) |num| {
+                const field = std.fmt.comptimePrint("f{d}", .{num});
+                try testing.expectEqual(@field(want.msg, field), @field(decoded, field));
+                if (want.msg.strings) |strings| {
+                    try testing.expectEqualStrings(@field(strings, field), @field(decoded.strings.?, field));
+                }
+            }
+            // Tags take the fewest bytes, so re-encoding matches Go exactly.
+            const again = try encode_to_buf(TagWidths, decoded);
+            defer testing.allocator.free(again);
+            try testing.expectEqualSlices(u8, tc.data, again);
+        }
+    }
+}
+
+test "tags3: write Zig test vectors" {
+    try write_test_vectors(TagWidths, &tag_width_cases, "testdata/zig/tags3.bin");
+}
+
+// ── Limits3 Tests (decoder resource limits) ──────────────────────────
+
+/// The limits go/cmd/generate records in the manifest; see
+/// go/testcases/limits.go.
+const DecodeLimits = struct {
+    max_depth: usize,
+    max_message_bytes: usize,
+};
+
+/// Reads the decoder limits from the Go manifest, or returns null if there
+/// is no manifest or it records none.
+fn read_go_limits() !?DecodeLimits {
+    const data = try read_go_vectors("testdata/go/manifest.json");
+    if (data == null) return null;
+    defer testing.allocator.free(data.?);
+
+    const Manifest = struct { limits: ?DecodeLimits = null };
+    const parsed = try std.json.parseFromSlice(Manifest, testing.allocator, data.?, .{ .ignore_unknown_fields = true });
+    defer parsed.deinit();
+    return parsed.value.limits;
+}
+
+/// Decodes data as a LimitNode under limits, failing input that is too
+/// large before decoding it, as the runtime has no size limit of its own.
+fn decode_within(limits: DecodeLimits, data: []const u8) !LimitNode {
+    if (data.len > limits.max_message_bytes) return error.MessageTooLarge;
+    return LimitNode.decode_inner(testing.allocator, data, limits.max_depth);
+}
+
+fn limit_depth(node: *const LimitNode) usize {
+    var n: usize = 1;
+    var m = node;
+    while (true) : (n += 1) {
+        if (m.children.len == 1) {
+            m = &m.children[0];
+        } else if (m.child) |child| {
+            m = child;
+        } else return n;
+    }
+}
+
+const limit_many_count = 20000;
+
+test "limits3: read Go test vectors within the manifest limits" {
+    const limits = try read_go_limits() orelse return;
+    const file_data = try read_go_vectors("testdata/go/limits3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var decoded = decode_within(limits, tc.data) catch |err| {
+            std.debug.print("limits3.bin: {s}: {s}\n", .{ tc.name, @errorName(err) });
+            return err;
+        };
+        defer decoded.deinit(testing.allocator);
+
+        if (std.mem.startsWith(u8, tc.name, "depth_at_limit")) {
+            try testing.expectEqual(limits.max_depth, limit_depth(&decoded));
+        } else if (std.mem.eql(u8, tc.name, "size_at_limit")) {
+            try testing.expectEqual(limits.max_message_bytes, tc.data.len);
+            try testing.expectEqual(@as(usize, 1), decoded.blobs.len);
+        } else if (std.mem.eql(u8, tc.name, "many_empty_children")) {
+            try testing.expectEqual(@as(usize, limit_many_count), decoded.children.len);
+        } else if (std.mem.eql(u8, tc.name, "many_empty_blobs")) {
+            try testing.expectEqual(@as(usize, limit_many_count), decoded.blobs.len);
+        }
+    }
+}
+
+test "limits3: over-limit Go vectors fail" {
+    // One level too deep or one byte too long. The testing allocator checks
+    // that failing part way through a deep message frees what it built.
+    const limits = try read_go_limits() orelse return;
+    const file_data = try read_go_vectors("testdata/go/limits/limits3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        if (decode_within(limits, tc.data)) |decoded| {
+            var msg = decoded;
+            msg.deinit(testing.allocator);
+            std.debug.print("limits/limits3.bin: {s}: decoded within limits\n", .{tc.name});
+            return error.TestUnexpectedResult;
+        } else |err| {
+            if (std.mem.startsWith(u8, tc.name, "depth_")) {
+                try testing.expectEqual(error.RecursionLimitExceeded, err);
+            }
+        }
+    }
+}
+
+test "limits3: reject Go vectors with oversized lengths" {
+    // Declared lengths far beyond the data, up to the largest varint.
+    const file_data = try read_go_vectors("testdata/go/reject/limits3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        if (LimitNode.decode(testing.allocator, tc.data)) |decoded| {
+            var msg = decoded;
+            msg.deinit(testing.allocator);
+            std.debug.print("reject/limits3.bin: {s}: decoded without error\n", .{tc.name});
+            return error.TestUnexpectedResult;
+        } else |_| {}
+    }
+}
+
+test "limits3: write Zig test vectors" {
+    // DefaultLimits in go/testcases/limits.go; writing must not depend on a
+    // Go manifest being present.
+    const limits = DecodeLimits{ .max_depth = 100, .max_message_bytes = 64 << 10 };
+
+    // Chains of max_depth levels through child and through children.
+    var chain: [limits.max_depth]LimitNode = undefined;
+    var repeated: [limits.max_depth]LimitNode = undefined;
+    chain[chain.len - 1] = .{};
+    repeated[repeated.len - 1] = .{};
+    var i = chain.len - 1;
+    while (i > 0) : (i -= 1) {
+        chain[i - 1] = .{ .child = &chain[i] };
+        repeated[i - 1] = .{ .children = repeated[i .. i + 1] };
+    }
+
+    // One blob sized so the message is exactly max_message_bytes long: a
+    // one-byte tag and a three-byte length.
+    const blob = try testing.allocator.alloc(u8, limits.max_message_bytes - 4);
+    defer testing.allocator.free(blob);
+    @memset(blob, 0);
+    const one_blob = [_][]const u8{blob};
+
+    const children = try testing.allocator.alloc(LimitNode, limit_many_count);
+    defer testing.allocator.free(children);
+    @memset(children, LimitNode{});
+    const blobs = try testing.allocator.alloc([]const u8, limit_many_count);
+    defer testing.allocator.free(blobs);
+    @memset(blobs, "");
+
+    const cases = [_]struct { name: []const u8, msg: LimitNode }{
+        .{ .name = "depth_at_limit", .msg = chain[0] },
+        .{ .name = "depth_at_limit_repeated", .msg = repeated[0] },
+        .{ .name = "size_at_limit", .msg = .{ .blobs = &one_blob } },
+        .{ .name = "many_empty_children", .msg = .{ .children = children } },
+        .{ .name = "many_empty_blobs", .msg = .{ .blobs = blobs } },
+    };
+
+    try write_test_vectors(LimitNode, &cases, "testdata/zig/limits3.bin");
+}
+
+// ── Reject results (go run ./cmd/validate -reject-results) ────────────
+
+/// Names the error category, as go/testcases/reject.go spells it, that a
+/// decode error falls in. Errors outside the categories keep their own name,
+/// so the validator reports them as misclassified.
+fn reject_category(err: anyerror) []const u8 {
+    return switch (err) {
+        error.EndOfStream => "truncated",
+        error.Overflow => "overflow",
+        error.InvalidWireType => "bad-wire-type",
+        error.InvalidFieldNumber => "bad-field-number",
+        error.InvalidUtf8 => "utf8",
+        error.RecursionLimitExceeded => "recursion-limit",
+        else => @errorName(err),
+    };
+}
+
+/// Decodes every vector of reject/<corpus>.bin as T and writes a result line
+/// for each: the category it was rejected with, or an empty one if it
+/// decoded. Case names are plain ASCII, so they need no JSON escaping.
+fn record_rejects(comptime T: type, corpus: []const u8, w: *std.Io.Writer) !void {
+    var path_buf: [256]u8 = undefined;
+    const path = try std.fmt.bufPrint(&path_buf, "testdata/go/reject/{s}.bin", .{corpus});
+    const file_data = try read_go_vectors(path);
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var category: []const u8 = "";
+        if (T.decode(testing.allocator, tc.data)) |decoded| {
+            var msg = decoded;
+            msg.deinit(testing.allocator);
+        } else |err| category = reject_category(err);
+        try w.print("{{\"corpus\":\"{s}\",\"case\":\"{s}\",\"error\":\"{s}\"}}\n", .{ corpus, tc.name, category });
+    }
+}
+
+test "reject: record error categories of Go vectors" {
+    // Only records; the reject tests above fail on accepted vectors. The
+    // runtime does not check required fields, so the required2 vectors are
+    // recorded as accepted and cmd/validate -reject-results reports them.
+    var w: std.Io.Writer.Allocating = .init(testing.allocator);
+    defer w.deinit();
+    try record_rejects(ScalarMessage, "scalar3", &w.writer);
+    try record_rejects(Required2Message, "required2", &w.writer);
+    try record_rejects(Inner, "unknown3", &w.writer);
+    try record_rejects(LimitNode, "limits3", &w.writer);
+
+    std.fs.cwd().makePath("testdata/zig") catch {};
+    var file = try std.fs.cwd().createFile("testdata/zig/reject_results.jsonl", .{});
+    defer file.close();
+    try file.writeAll(w.written());
+}
+
+// ── Results (proto/compat_results.proto) ──────────────────────────────
+
+/// The Go corpora decoded for testdata/zig/results.pb, with their message
+/// types. evolution3 and messageset2 mix types within a file and are left
+/// to their own tests.
+const result_corpora = .{
+    .{ "acp", AcpMessage },
+    .{ "edge3", EdgeMessage },
+    .{ "enum3", EnumMessage },
+    .{ "imports3", ImportsMessage },
+    .{ "json3", JsonNames },
+    .{ "limits3", LimitNode },
+    .{ "map3", MapMessage },
+    .{ "naming3", Naming },
+    .{ "nested3", Outer },
+    .{ "oneof3", OneofMessage },
+    .{ "optional3", OptionalMessage },
+    .{ "packed3", PackedScalars },
+    .{ "repeated3", RepeatedMessage },
+    .{ "required2", Required2Message },
+    .{ "reserved3", ReservedMessage },
+    .{ "scalar2", Scalar2Message },
+    .{ "scalar3", ScalarMessage },
+    .{ "tags3", TagWidths },
+    .{ "unknown3", Inner },
+};
+
+/// Where want and got first differ, with a window of each from 16 bytes
+/// before, as go/testcases.DiffBytes reports it; null if they are equal.
+fn diff_bytes(want: []const u8, got: []const u8) ?ByteDiff {
+    const off = std.mem.indexOfDiff(u8, want, got) orelse return null;
+    const start = off -| 16;
+    return .{
+        .offset = off,
+        .window_start = start,
+        .want = want[@min(start, want.len)..@min(start + 64, want.len)],
+        .got = got[@min(start, got.len)..@min(start + 64, got.len)],
+        .want_len = want.len,
+        .got_len = got.len,
+    };
+}
+
+/// Decodes every case of testdata/go/<corpus>.bin as T and appends a
+/// go-to-zig result for each: it passes if it decodes, and carries a diff if
+/// re-encoding it does not give back the Go bytes. Everything appended is
+/// allocated in arena.
+fn collect_results(comptime T: type, arena: std.mem.Allocator, corpus: []const u8, results: *std.ArrayListUnmanaged(CaseResult)) !void {
+    const path = try std.fmt.allocPrint(arena, "testdata/go/{s}.bin", .{corpus});
+    const file_data = try read_go_vectors(path);
+    if (file_data == null) {
+        try results.append(arena, .{ .direction = "go-to-zig", .category = corpus, .status = .CASE_STATUS_SKIP, .detail = "corpus file missing" });
+        return;
+    }
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var result = CaseResult{
+            .direction = "go-to-zig",
+            .category = corpus,
+            .name = try arena.dupe(u8, tc.name),
+            .status = .CASE_STATUS_PASS,
+        };
+        var timer = try std.time.Timer.start();
+        if (T.decode(testing.allocator, tc.data)) |decoded| {
+            var msg = decoded;
+            defer msg.deinit(testing.allocator);
+            var w: std.Io.Writer.Allocating = .init(arena);
+            try msg.encode(&w.writer);
+            if (diff_bytes(tc.data, w.written())) |diff| {
+                result.diff = .{
+                    .offset = diff.offset,
+                    .window_start = diff.window_start,
+                    .want = try arena.dupe(u8, diff.want),
+                    .got = diff.got,
+                    .want_len = diff.want_len,
+                    .got_len = diff.got_len,
+                };
+                result.detail = "re-encoding differs from the Go bytes";
+            }
+        } else |err| {
+            result.status = .CASE_STATUS_FAIL;
+            result.detail = @errorName(err);
+        }
+        result.duration_nanos = @intCast(timer.read());
+        try results.append(arena, result);
+    }
+}
+
+/// Appends a reject result for every vector of testdata/go/reject/<corpus>.bin
+/// decoded as T: it passes if decoding fails with the category its name is
+/// tagged with.
+fn collect_reject_results(comptime T: type, arena: std.mem.Allocator, corpus: []const u8, results: *std.ArrayListUnmanaged(CaseResult)) !void {
+    const path = try std.fmt.allocPrint(arena, "testdata/go/reject/{s}.bin", .{corpus});
+    const file_data = try read_go_vectors(path);
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        const want = tc.name[0 .. std.mem.indexOfScalar(u8, tc.name, '/') orelse 0];
+        var result = CaseResult{
+            .direction = "reject",
+            .category = corpus,
+            .name = try arena.dupe(u8, tc.name),
+            .status = .CASE_STATUS_FAIL,
+            .detail = "accepted",
+        };
+        var timer = try std.time.Timer.start();
+        if (T.decode(testing.allocator, tc.data)) |decoded| {
+            var msg = decoded;
+            msg.deinit(testing.allocator);
+        } else |err| {
+            result.error_category = reject_category(err);
+            if (std.mem.eql(u8, result.error_category, want)) {
+                result.status = .CASE_STATUS_PASS;
+                result.detail = "";
+            } else {
+                result.detail = try std.fmt.allocPrint(arena, "rejected as {s}, want {s}", .{ result.error_category, want });
+            }
+        }
+        result.duration_nanos = @intCast(timer.read());
+        try results.append(arena, result);
+    }
+}
+
+test "results: write Zig results" {
+    // Read with go run ./cmd/validate's results, or with anything that takes
+    // a CompatResults; failures are recorded rather than failing the test.
+    var arena_state = std.heap.ArenaAllocator.init(testing.allocator);
+    defer arena_state.deinit();
+    const arena = arena_state.allocator();
+
+    const start: i64 = @intCast(std.time.nanoTimestamp());
+    var results: std.ArrayListUnmanaged(CaseResult) = .empty;
+    inline for (result_corpora) |entry| {
+        try collect_results(entry[1], arena, entry[0], &results);
+    }
+    try collect_reject_results(ScalarMessage, arena, "scalar3", &results);
+    try collect_reject_results(Required2Message, arena, "required2", &results);
+    try collect_reject_results(Inner, arena, "unknown3", &results);
+    try collect_reject_results(LimitNode, arena, "limits3", &results);
+
+    const msg = CompatResults{
+        .run = .{
+            .implementation = "zig",
+            .version = "zig " ++ builtin.zig_version_string,
+            .command = "zig build test",
+            .os = @tagName(builtin.os.tag),
+            .arch = @tagName(builtin.cpu.arch),
+            .start_unix_nanos = start,
+            .duration_nanos = @as(i64, @intCast(std.time.nanoTimestamp())) - start,
+        },
+        .cases = results.items,
+    };
+    var w: std.Io.Writer.Allocating = .init(arena);
+    try msg.encode(&w.writer);
+
+    std.fs.cwd().makePath("testdata/zig") catch {};
+    var file = try std.fs.cwd().createFile("testdata/zig/results.pb", .{});
+    defer file.close();
+    try file.writeAll(w.written());
+}
+
+// ── Round trip (go/cmd/validate -direction go-to-zig) ─────────────────
+
+/// The Go corpora re-encoded for testdata/zig/roundtrip as one message type:
+/// those of result_corpora and the single-type corpora written after them.
+/// evolution3 mixes types and is re-encoded by reencode_evolution.
+const roundtrip_corpora = result_corpora ++ .{
+    .{ "messageset2", MessageSetContainer },
+    .{ "nestedmap3", NestedMaps },
+    .{ "order3", OrderList },
+    .{ "presence3", PresenceMatrix },
+    .{ "strings3", StringEdges },
+};
+
+/// Decodes one round-trip case, given its name and bytes, and encodes it
+/// again into w.
+const Reencode = fn (name: []const u8, data: []const u8, w: *std.Io.Writer) anyerror!void;
+
+/// The Reencode of corpora that hold only T.
+fn reencode_as(comptime T: type) Reencode {
+    return struct {
+        fn reencode(_: []const u8, data: []const u8, w: *std.Io.Writer) anyerror!void {
+            var msg = try T.decode(testing.allocator, data);
+            defer msg.deinit(testing.allocator);
+            try msg.encode(w);
+        }
+    }.reencode;
+}
+
+/// Re-encodes an evolution3 case with the version that wrote it: v1_ cases
+/// as EvolutionV1 and v2_ cases as EvolutionV2, as the Go side compares them.
+fn reencode_evolution(name: []const u8, data: []const u8, w: *std.Io.Writer) anyerror!void {
+    if (std.mem.startsWith(u8, name, "v1_")) return reencode_as(EvolutionV1)(name, data, w);
+    return reencode_as(EvolutionV2)(name, data, w);
+}
+
+/// Re-encodes every case of testdata/go/<corpus>.bin with reencode and frames
+/// it, under the same name, in testdata/zig/roundtrip/<corpus>.bin. A case
+/// that does not decode is left out, which cmd/validate reports as missing;
+/// a missing Go corpus writes nothing.
+fn write_roundtrip_vectors(corpus: []const u8, comptime reencode: Reencode) !void {
+    var path_buf: [256]u8 = undefined;
+    const file_data = try read_go_vectors(try std.fmt.bufPrint(&path_buf, "testdata/go/{s}.bin", .{corpus}));
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    var w: std.Io.Writer.Allocating = .init(testing.allocator);
+    defer w.deinit();
+    try framing.write_header(&w.writer);
+    for (cases) |tc| {
+        var msg_w: std.Io.Writer.Allocating = .init(testing.allocator);
+        defer msg_w.deinit();
+        reencode(tc.name, tc.data, &msg_w.writer) catch continue;
+        try framing.write_test_case(&w.writer, tc.name, msg_w.written());
+    }
+
+    std.fs.cwd().makePath("testdata/zig/roundtrip") catch {};
+    var file = try std.fs.cwd().createFile(try std.fmt.bufPrint(&path_buf, "testdata/zig/roundtrip/{s}.bin", .{corpus}), .{});
+    defer file.close();
+    try file.writeAll(w.written());
+}
+
+test "roundtrip: re-encode Go vectors" {
+    // Checked by go run ./cmd/validate -direction go-to-zig, which decodes
+    // each re-encoding and compares it with the Go vector it came from.
+    inline for (roundtrip_corpora) |entry| {
+        try write_roundtrip_vectors(entry[0], reencode_as(entry[1]));
+    }
+    try write_roundtrip_vectors("evolution3", reencode_evolution);
+}
+
+// ── Order3 Tests (repeated message identity and order) ───────────────
+
+/// The element every order3 case repeats: the same but for the mark in its
+/// leaf; see go/testcases/order3.go.
+fn order_item(mark: i64) OrderItem {
+    return .{
+        .name = "item",
+        .values = &.{ 1, 2, 3 },
+        .branch = .{ .blob = "\x00\xff\x00\xff", .leaf = .{ .label = "leaf", .mark = mark } },
+    };
+}
+
+/// The mark of element i of the runs case: runs of one to seven identical
+/// elements, each run's mark one more than the last.
+fn order_run(i: usize) i64 {
+    var rest = i;
+    var run: i64 = 0;
+    while (true) : (run += 1) {
+        const n: usize = @intCast(@mod(run, 7) + 1);
+        if (rest < n) return run;
+        rest -= n;
+    }
+}
+
+// Each case's length and the element at each index.
+const order_cases = [_]struct { name: []const u8, n: usize, item: *const fn (usize) OrderItem }{
+    .{ .name = "all_identical", .n = 300, .item = struct {
+        fn f(_: usize) OrderItem {
+            return order_item(7);
+        }
+    }.f },
+    .{ .name = "adjacent_pairs", .n = 400, .item = struct {
+        fn f(i: usize) OrderItem {
+            return order_item(@intCast(i / 2));
+        }
+    }.f },
+    .{ .name = "runs", .n = 512, .item = struct {
+        fn f(i: usize) OrderItem {
+            return order_item(order_run(i));
+        }
+    }.f },
+    .{ .name = "ascending", .n = 500, .item = struct {
+        fn f(i: usize) OrderItem {
+            return order_item(@intCast(i));
+        }
+    }.f },
+    .{ .name = "descending", .n = 500, .item = struct {
+        fn f(i: usize) OrderItem {
+            return order_item(@intCast(500 - i));
+        }
+    }.f },
+    .{ .name = "alternating", .n = 400, .item = struct {
+        fn f(i: usize) OrderItem {
+            return order_item(@intCast(i % 2));
+        }
+    }.f },
+    .{ .name = "one_differs", .n = 512, .item = struct {
+        fn f(i: usize) OrderItem {
+            return order_item(if (i == 255) -1 else 0);
+        }
+    }.f },
+    .{ .name = "empty_elements", .n = 300, .item = struct {
+        fn f(_: usize) OrderItem {
+            return .{};
+        }
+    }.f },
+    .{ .name = "empty_between", .n = 300, .item = struct {
+        fn f(i: usize) OrderItem {
+            return if (i % 3 == 1) .{} else order_item(@intCast(i / 3));
+        }
+    }.f },
+};
+
+const OrderCase = struct { name: []const u8, msg: OrderList };
+
+/// Builds every order3 case in arena.
+fn order_list_cases(arena: std.mem.Allocator) ![]OrderCase {
+    const cases = try arena.alloc(OrderCase, order_cases.len);
+    for (order_cases, cases) |c, *out| {
+        const items = try arena.alloc(OrderItem, c.n);
+        for (items, 0..) |*item, i| item.* = c.item(i);
+        out.* = .{ .name = c.name, .msg = .{ .items = items } };
+    }
+    return cases;
+}
+
+fn order_item_eql(a: OrderItem, b: OrderItem) bool {
+    if (!std.mem.eql(u8, a.name, b.name) or !std.mem.eql(i32, a.values, b.values)) return false;
+    if ((a.branch == null) != (b.branch == null)) return false;
+    const ab = a.branch orelse return true;
+    const bb = b.branch.?;
+    if (!std.mem.eql(u8, ab.blob, bb.blob) or (ab.leaf == null) != (bb.leaf == null)) return false;
+    const al = ab.leaf orelse return true;
+    const bl = bb.leaf.?;
+    return std.mem.eql(u8, al.label, bl.label) and al.mark == bl.mark;
+}
+
+test "order3: read Go test vectors" {
+    const file_data = try read_go_vectors("testdata/go/order3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    var arena_state = std.heap.ArenaAllocator.init(testing.allocator);
+    defer arena_state.deinit();
+    const expected = try order_list_cases(arena_state.allocator());
+
+    for (cases) |tc| {
+        var decoded = try OrderList.decode(testing.allocator, tc.data);
+        defer decoded.deinit(testing.allocator);
+
+        for (expected) |want| {
+            if (!std.mem.eql(u8, tc.name, want.name)) continue;
+            try testing.expectEqual(want.msg.items.len, decoded.items.len);
+            for (want.msg.items, decoded.items, 0..) |w, d, i| {
+                if (!order_item_eql(w, d)) {
+                    std.debug.print("order3/{s}: first difference at element {d}\n", .{ tc.name, i });
+                    return error.TestUnexpectedResult;
+                }
+            }
+        }
+
+        // Every element, identical or not, is written again in its place.
+        var w: std.Io.Writer.Allocating = .init(testing.allocator);
+        defer w.deinit();
+        try decoded.encode(&w.writer);
+        try testing.expect(std.mem.eql(u8, tc.data, w.written()));
+    }
+}
+
+test "order3: write Zig test vectors" {
+    var arena_state = std.heap.ArenaAllocator.init(testing.allocator);
+    defer arena_state.deinit();
+    try write_test_vectors(OrderList, try order_list_cases(arena_state.allocator()), "testdata/zig/order3.bin");
+}
+
+// ── Presence3 Tests (implicit, optional and oneof presence) ──────────
+
+// Each field shape of PresenceMatrix with its zero and a nonzero value; see
+// go/testcases/presence3.go. Field i_<shape> has implicit presence,
+// o_<shape> is optional and w_<shape> a oneof of the one member
+// w_<shape>_value.
+const presence_shapes = .{
+    .{ "int32", @as(i32, 0), @as(i32, -1) },
+    .{ "sint64", @as(i64, 0), @as(i64, -1) },
+    .{ "fixed32", @as(u32, 0), @as(u32, std.math.maxInt(u32)) },
+    .{ "double", @as(f64, 0), @as(f64, 1.5) },
+    .{ "bool", false, true },
+    .{ "string", @as([]const u8, ""), @as([]const u8, "x") },
+    .{ "bytes", @as([]const u8, ""), @as([]const u8, "\x00") },
+    .{ "enum", PresenceEnum.PRESENCE_ENUM_ZERO, PresenceEnum.PRESENCE_ENUM_ONE },
+    .{ "message", PresenceChild{}, PresenceChild{ .value = 1 } },
+};
+
+// The forms each shape is declared in, by case name prefix and field prefix.
+const presence_forms = .{ .{ "implicit", "i" }, .{ "optional", "o" }, .{ "oneof", "w" } };
+
+const PresenceCase = struct { name: []const u8, msg: PresenceMatrix };
+
+/// Sets the field of form `prefix` and shape `shape` in msg.
+fn set_presence(msg: *PresenceMatrix, comptime prefix: []const u8, comptime shape: []const u8, value: anytype) void {
+    const field = prefix ++ "_" ++ shape;
+    if (comptime std.mem.eql(u8, prefix, "w")) {
+        const Member = @typeInfo(@FieldType(PresenceMatrix, field)).optional.child;
+        @field(msg, field) = @unionInit(Member, field ++ "_value", value);
+    } else {
+        @field(msg, field) = value;
+    }
+}
+
+const presence_cases = blk: {
+    var cases: [presence_forms.len * presence_shapes.len + 4]PresenceCase = undefined;
+    var all_zero: PresenceMatrix = .{};
+    var all_nonzero: PresenceMatrix = .{};
+    cases[0] = .{ .name = "unset", .msg = .{} };
+    var i = 1;
+    inline for (presence_forms) |form| {
+        inline for (presence_shapes) |shape| {
+            var msg: PresenceMatrix = .{};
+            set_presence(&msg, form[1], shape[0], shape[1]);
+            set_presence(&all_zero, form[1], shape[0], shape[1]);
+            set_presence(&all_nonzero, form[1], shape[0], shape[2]);
+            cases[i] = .{ .name = form[0] ++ "_" ++ shape[0] ++ "_zero", .msg = msg };
+            i += 1;
+        }
+    }
+    cases[i] = .{ .name = "all_zero", .msg = all_zero };
+    cases[i + 1] = .{ .name = "all_nonzero", .msg = all_nonzero };
+    cases[i + 2] = .{ .name = "implicit_double_negative_zero", .msg = .{ .i_double = -0.0 } };
+    const final = cases;
+    break :blk final;
+};
+
+test "presence3: read Go test vectors" {
+    const file_data = try read_go_vectors("testdata/go/presence3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var decoded = try PresenceMatrix.decode(testing.allocator, tc.data);
+        defer decoded.deinit(testing.allocator);
+
+        const again = try encode_to_buf(PresenceMatrix, decoded);
+        defer testing.allocator.free(again);
+
+        // Implicit scalars holding zero on the wire read as unset, and so
+        // are not written again.
+        if (std.mem.eql(u8, tc.name, "implicit_zero_on_wire")) {
+            try testing.expectEqual(@as(usize, 0), again.len);
+            continue;
+        }
+        for (presence_cases) |want| {
+            if (!std.mem.eql(u8, tc.name, want.name)) continue;
+            inline for (presence_forms) |form| {
+                inline for (presence_shapes) |shape| {
+                    const field = form[1] ++ "_" ++ shape[0];
+                    if (@typeInfo(@FieldType(PresenceMatrix, field)) == .optional) {
+                        try testing.expectEqual(@field(want.msg, field) == null, @field(decoded, field) == null);
+                    }
+                }
+            }
+            try testing.expectEqual(@as(u64, @bitCast(want.msg.i_double)), @as(u64, @bitCast(decoded.i_double)));
+        }
+        // What is on the wire is exactly what is present, so re-encoding
+        // writes the same bytes.
+        try testing.expectEqualSlices(u8, tc.data, again);
+    }
+}
+
+test "presence3: write Zig test vectors" {
+    try write_test_vectors(PresenceMatrix, &presence_cases, "testdata/zig/presence3.bin");
+}
+
+// ── Strings3 Tests (string content edges) ────────────────────────────
+
+// The contents of the strings3 cases other than the runs; see
+// go/testcases/strings3.go. Each is written as text, as raw bytes and, one
+// code point per element, as pieces.
+const string_edges = [_]struct { name: []const u8, text: []const u8 }{
+    .{ .name = "nul_only", .text = "\x00" },
+    .{ .name = "nul_embedded", .text = "a\x00b\x00\x00c" },
+    .{ .name = "nul_edges", .text = "\x00abc\x00" },
+    .{ .name = "control_chars", .text = control_chars },
+    .{ .name = "utf8_4byte", .text = "\u{1F600}\u{1F389}\u{20000}\u{20001}\u{2A6D6}\u{10FFFF}" },
+    .{ .name = "utf8_widths", .text = "a\u{e9}\u{20ac}\u{1F600}" },
+    .{ .name = "combining", .text = "\u{301}e\u{301}a\u{300}\u{301}\u{302}\u{303}\u{304}\u{1100}\u{1161}\u{11a8}" ++
+        "\u{1F468}\u{200d}\u{1F469}\u{200d}\u{1F467}\u{1F1FA}\u{1F1F3}\u{263a}\u{fe0f}" },
+    .{ .name = "specials", .text = "\u{feff}\u{2028}\u{2029}\u{fffd}\u{fffe}\u{ffff}\u{200b}" },
+};
+
+/// Every C0 control character, DEL and every C1 control character, in order.
+const control_chars = blk: {
+    var s: []const u8 = "";
+    for (0..0x20) |c| s = s ++ [_]u8{c};
+    s = s ++ "\x7f";
+    for (0x80..0xa0) |c| s = s ++ std.unicode.utf8EncodeComptime(c);
+    const final = s;
+    break :blk final;
+};
+
+/// Splits text into one slice per code point.
+fn code_points(comptime text: []const u8) []const []const u8 {
+    comptime {
+        @setEvalBranchQuota(10000);
+        var pieces: []const []const u8 = &.{};
+        var it = (std.unicode.Utf8View.init(text) catch unreachable).iterator();
+        while (it.nextCodepointSlice()) |cp| pieces = pieces ++ [_][]const u8{cp};
+        const final = pieces;
+        return final;
+    }
+}
+
+// The run cases repeat one character of each width that matters to
+// string_run_bytes, as text and raw only.
+const string_runs = [_]struct { name: []const u8, char: []const u8 }{
+    .{ .name = "run_ascii", .char = "a" },
+    .{ .name = "run_nul", .char = "\x00" },
+    .{ .name = "run_combining", .char = "\u{301}" },
+    .{ .name = "run_4byte", .char = "\u{1F600}" },
+};
+
+const string_run_bytes = 1 << 20;
+
+const StringCase = struct { name: []const u8, msg: StringEdges };
+
+/// Builds every strings3 case, the runs in arena.
+fn string_cases(arena: std.mem.Allocator) ![]StringCase {
+    var cases: std.ArrayList(StringCase) = .empty;
+    inline for (string_edges) |e| {
+        try cases.append(arena, .{ .name = e.name, .msg = .{
+            .text = e.text,
+            .raw = e.text,
+            .pieces = comptime code_points(e.text),
+        } });
+    }
+    for (string_runs) |run| {
+        const text = try arena.alloc(u8, string_run_bytes / run.char.len * run.char.len);
+        var i: usize = 0;
+        while (i < text.len) : (i += run.char.len) @memcpy(text[i..][0..run.char.len], run.char);
+        try cases.append(arena, .{ .name = run.name, .msg = .{ .text = text, .raw = text } });
+    }
+    return cases.items;
+}
+
+test "strings3: read Go test vectors" {
+    const file_data = try read_go_vectors("testdata/go/strings3.bin");
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    var arena_state = std.heap.ArenaAllocator.init(testing.allocator);
+    defer arena_state.deinit();
+    const expected = try string_cases(arena_state.allocator());
+
+    for (cases) |tc| {
+        var decoded = try StringEdges.decode(testing.allocator, tc.data);
+        defer decoded.deinit(testing.allocator);
+
+        // The runs are a megabyte long, so compare with eql rather than
+        // print both sides on a mismatch.
+        for (expected) |want| {
+            if (!std.mem.eql(u8, tc.name, want.name)) continue;
+            try testing.expect(std.mem.eql(u8, want.msg.text, decoded.text));
+            try testing.expect(std.mem.eql(u8, want.msg.raw, decoded.raw));
+            try testing.expectEqual(want.msg.pieces.len, decoded.pieces.len);
+            for (want.msg.pieces, decoded.pieces) |w, d| try testing.expectEqualStrings(w, d);
+        }
+
+        // encode_to_buf's buffer is too small for the runs.
+        var w: std.Io.Writer.Allocating = .init(testing.allocator);
+        defer w.deinit();
+        try decoded.encode(&w.writer);
+        try testing.expect(std.mem.eql(u8, tc.data, w.written()));
+    }
+}
+
+test "strings3: write Zig test vectors" {
+    var arena_state = std.heap.ArenaAllocator.init(testing.allocator);
+    defer arena_state.deinit();
+    try write_test_vectors(StringEdges, try string_cases(arena_state.allocator()), "testdata/zig/strings3.bin");
+}
+
+// ── Mutated Go vectors (go run ./cmd/generate -mutate N) ──────────────
+
+/// Decodes every mutant in a mutated/ corpus as T. Names tagged "must/" are
+/// valid encodings and have to decode; "may/" ones may be rejected, but the
+/// testing allocator still catches leaks on the error paths.
+fn decode_mutants(comptime T: type, path: []const u8) !void {
+    const file_data = try read_go_vectors(path);
+    if (file_data == null) return;
+    defer testing.allocator.free(file_data.?);
+
+    const cases = try framing.read_all_test_cases(testing.allocator, file_data.?);
+    defer testing.allocator.free(cases);
+
+    for (cases) |tc| {
+        var decoded = T.decode(testing.allocator, tc.data) catch |err| {
+            if (std.mem.startsWith(u8, tc.name, "must/")) {
+                std.debug.print("{s}: {s}: {s}\n", .{ path, tc.name, @errorName(err) });
+                return err;
+            }
+            continue;
+        };
+        decoded.deinit(testing.allocator);
+    }
+}
+
+test "mutated Go vectors" {
+    try decode_mutants(AcpMessage, "testdata/go/mutated/acp.bin");
+    try decode_mutants(EdgeMessage, "testdata/go/mutated/edge3.bin");
+    try decode_mutants(EnumMessage, "testdata/go/mutated/enum3.bin");
+    // Both versions are wire-compatible, so every case decodes as the newer.
+    try decode_mutants(EvolutionV2, "testdata/go/mutated/evolution3.bin");
+    try decode_mutants(ImportsMessage, "testdata/go/mutated/imports3.bin");
+    try decode_mutants(JsonNames, "testdata/go/mutated/json3.bin");
+    try decode_mutants(LimitNode, "testdata/go/mutated/limits3.bin");
+    try decode_mutants(MapMessage, "testdata/go/mutated/map3.bin");
+    try decode_mutants(MessageSetContainer, "testdata/go/mutated/messageset2.bin");
+    try decode_mutants(Naming, "testdata/go/mutated/naming3.bin");
+    try decode_mutants(Outer, "testdata/go/mutated/nested3.bin");
+    try decode_mutants(NestedMaps, "testdata/go/mutated/nestedmap3.bin");
+    try decode_mutants(OneofMessage, "testdata/go/mutated/oneof3.bin");
+    try decode_mutants(OptionalMessage, "testdata/go/mutated/optional3.bin");
+    try decode_mutants(OrderList, "testdata/go/mutated/order3.bin");
+    try decode_mutants(PackedScalars, "testdata/go/mutated/packed3.bin");
+    try decode_mutants(PresenceMatrix, "testdata/go/mutated/presence3.bin");
+    try decode_mutants(RepeatedMessage, "testdata/go/mutated/repeated3.bin");
+    try decode_mutants(Required2Message, "testdata/go/mutated/required2.bin");
+    try decode_mutants(ReservedMessage, "testdata/go/mutated/reserved3.bin");
+    try decode_mutants(Scalar2Message, "testdata/go/mutated/scalar2.bin");
+    try decode_mutants(ScalarMessage, "testdata/go/mutated/scalar3.bin");
+    try decode_mutants(StringEdges, "testdata/go/mutated/strings3.bin");
+    try decode_mutants(TagWidths, "testdata/go/mutated/tags3.bin");
+    try decode_mutants(Inner, "testdata/go/mutated/unknown3.bin");
+}
+
+test "decoded Go vectors own their data" {
+    try scribble_vectors(AcpMessage, "testdata/go/acp.bin");
+    try scribble_vectors(EdgeMessage, "testdata/go/edge3.bin");
+    try scribble_vectors(EnumMessage, "testdata/go/enum3.bin");
+    try scribble_vectors(EvolutionV2, "testdata/go/evolution3.bin");
+    try scribble_vectors(ImportsMessage, "testdata/go/imports3.bin");
+    try scribble_vectors(JsonNames, "testdata/go/json3.bin");
+    try scribble_vectors(LimitNode, "testdata/go/limits3.bin");
+    try scribble_vectors(MapMessage, "testdata/go/map3.bin");
+    try scribble_vectors(MessageSetContainer, "testdata/go/messageset2.bin");
+    try scribble_vectors(Naming, "testdata/go/naming3.bin");
+    try scribble_vectors(Outer, "testdata/go/nested3.bin");
+    try scribble_vectors(NestedMaps, "testdata/go/nestedmap3.bin");
+    try scribble_vectors(OneofMessage, "testdata/go/oneof3.bin");
+    try scribble_vectors(OptionalMessage, "testdata/go/optional3.bin");
+    try scribble_vectors(OrderList, "testdata/go/order3.bin");
+    try scribble_vectors(PackedScalars, "testdata/go/packed3.bin");
+    try scribble_vectors(PresenceMatrix, "testdata/go/presence3.bin");
+    try scribble_vectors(RepeatedMessage, "testdata/go/repeated3.bin");
+    try scribble_vectors(Required2Message, "testdata/go/required2.bin");
+    try scribble_vectors(ReservedMessage, "testdata/go/reserved3.bin");
+    try scribble_vectors(Scalar2Message, "testdata/go/scalar2.bin");
+    try scribble_vectors(ScalarMessage, "testdata/go/scalar3.bin");
+    try scribble_vectors(StringEdges, "testdata/go/strings3.bin");
+    try scribble_vectors(TagWidths, "testdata/go/tags3.bin");
+    try scribble_vectors(Inner, "testdata/go/unknown3.bin");
+}
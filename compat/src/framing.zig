@@ -2,12 +2,34 @@ const std = @import("std");
 
 /// Test case framing format:
 /// [4-byte BE uint32: name length][name bytes][4-byte BE uint32: message length][message bytes]
+///
+/// A corpus file starts with a header giving its format version (see
+/// testcases/format.go on the Go side), so that a reader refuses a newer
+/// format instead of misreading it; legacy files without one are a bare run
+/// of framed cases:
+/// [4B magic "\xffPBC"][1B version][1B flags][2B reserved, zero] framed cases
+/// and, if flags has corpus_indexed, an index after the cases:
+/// [8-byte BE offset of a case] per case, sorted by case name,
+/// [4-byte BE case count][4B magic "PBCI"]
+pub const corpus_magic = "\xffPBC";
+pub const corpus_version: u8 = 1;
+const corpus_header_len = 8;
+const corpus_indexed: u8 = 1 << 0;
+const index_magic = "PBCI";
+const index_footer_len = 8;
 
 pub const TestCase = struct {
     name: []const u8,
     data: []const u8,
 };
 
+/// Starts a corpus file in the current format, without an index. Every
+/// writer of a corpus file calls it before the first case.
+pub fn write_header(writer: *std.Io.Writer) std.Io.Writer.Error!void {
+    try writer.writeAll(corpus_magic);
+    try writer.writeAll(&.{ corpus_version, 0, 0, 0 });
+}
+
 pub fn write_test_case(writer: *std.Io.Writer, name: []const u8, data: []const u8) std.Io.Writer.Error!void {
     // Write name length (4-byte big-endian)
     try writer.writeAll(&std.mem.toBytes(std.mem.nativeToBig(u32, @intCast(name.len))));
@@ -42,10 +64,73 @@ pub fn read_test_case(data: []const u8, pos: *usize) ?TestCase {
     return .{ .name = name, .data = msg_data };
 }
 
+/// The parts of a corpus file.
+pub const Corpus = struct {
+    /// The framed cases.
+    cases: []const u8,
+    /// Offset of the cases within the file.
+    start: usize,
+    /// Case offsets sorted by name, 8 bytes each; empty without an index.
+    index: []const u8,
+};
+
+pub fn split_corpus(data: []const u8) error{ UnsupportedCorpusVersion, TruncatedCorpus }!Corpus {
+    if (!std.mem.startsWith(u8, data, corpus_magic)) return .{ .cases = data, .start = 0, .index = "" };
+    if (data.len < corpus_header_len) return error.TruncatedCorpus;
+    if (data[4] != corpus_version or data[5] & ~corpus_indexed != 0 or data[6] != 0 or data[7] != 0)
+        return error.UnsupportedCorpusVersion;
+    var end = data.len;
+    var index: []const u8 = "";
+    if (data[5] & corpus_indexed != 0) {
+        if (end - corpus_header_len < index_footer_len or !std.mem.eql(u8, data[end - 4 ..], index_magic))
+            return error.TruncatedCorpus;
+        const n = std.mem.readInt(u32, data[end - index_footer_len ..][0..4], .big);
+        const size = @as(usize, n) * 8;
+        if (size > end - corpus_header_len - index_footer_len) return error.TruncatedCorpus;
+        index = data[end - index_footer_len - size .. end - index_footer_len];
+        end -= index_footer_len + size;
+    }
+    return .{ .cases = data[corpus_header_len..end], .start = corpus_header_len, .index = index };
+}
+
+/// Finds the case called name: by binary search over the index if the
+/// corpus has one, reading only the names it compares, and by scanning the
+/// cases if it does not.
+pub fn find_test_case(data: []const u8, name: []const u8) !?TestCase {
+    const corpus = try split_corpus(data);
+    if (corpus.index.len == 0) {
+        var pos: usize = 0;
+        while (read_test_case(corpus.cases, &pos)) |tc| {
+            if (std.mem.eql(u8, tc.name, name)) return tc;
+        }
+        return null;
+    }
+    var lo: usize = 0;
+    var hi: usize = corpus.index.len / 8;
+    while (lo < hi) {
+        const mid = lo + (hi - lo) / 2;
+        const tc = try indexed_case(corpus, mid);
+        switch (std.mem.order(u8, tc.name, name)) {
+            .lt => lo = mid + 1,
+            .gt => hi = mid,
+            .eq => return tc,
+        }
+    }
+    return null;
+}
+
+fn indexed_case(corpus: Corpus, i: usize) error{TruncatedCorpus}!TestCase {
+    const off = std.mem.readInt(u64, corpus.index[i * 8 ..][0..8], .big);
+    if (off < corpus.start or off - corpus.start >= corpus.cases.len) return error.TruncatedCorpus;
+    var pos: usize = @intCast(off - corpus.start);
+    return read_test_case(corpus.cases, &pos) orelse error.TruncatedCorpus;
+}
+
 pub fn read_all_test_cases(allocator: std.mem.Allocator, data: []const u8) ![]TestCase {
+    const corpus = try split_corpus(data);
     var cases: std.ArrayList(TestCase) = .empty;
     var pos: usize = 0;
-    while (read_test_case(data, &pos)) |tc| {
+    while (read_test_case(corpus.cases, &pos)) |tc| {
         try cases.append(allocator, tc);
     }
     return try cases.toOwnedSlice(allocator);
@@ -88,3 +173,32 @@ test "framing: empty data returns null" {
     var pos: usize = 0;
     try std.testing.expectEqual(@as(?TestCase, null), read_test_case("", &pos));
 }
+
+test "framing: indexed corpus" {
+    var buf: [256]u8 = undefined;
+    var w: std.Io.Writer = .fixed(&buf);
+    try w.writeAll(corpus_magic);
+    try w.writeAll(&.{ corpus_version, corpus_indexed, 0, 0 });
+    try write_test_case(&w, "zeta", "z");
+    const alpha_off = w.buffered().len;
+    try write_test_case(&w, "alpha", "aa");
+    try w.writeInt(u64, alpha_off, .big);
+    try w.writeInt(u64, corpus_header_len, .big);
+    try w.writeInt(u32, 2, .big);
+    try w.writeAll(index_magic);
+    const data = w.buffered();
+
+    const cases = try read_all_test_cases(std.testing.allocator, data);
+    defer std.testing.allocator.free(cases);
+    try std.testing.expectEqual(@as(usize, 2), cases.len);
+    try std.testing.expectEqualStrings("zeta", cases[0].name);
+    try std.testing.expectEqualStrings("alpha", cases[1].name);
+
+    try std.testing.expectEqualStrings("aa", (try find_test_case(data, "alpha")).?.data);
+    try std.testing.expectEqualStrings("z", (try find_test_case(data, "zeta")).?.data);
+    try std.testing.expectEqual(@as(?TestCase, null), try find_test_case(data, "beta"));
+
+    var future = buf;
+    future[4] = corpus_version + 1;
+    try std.testing.expectError(error.UnsupportedCorpusVersion, split_corpus(future[0..data.len]));
+}
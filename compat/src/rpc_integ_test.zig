@@ -17,6 +17,8 @@ const GetItemResponse = proto.service_unary.GetItemResponse;
 const HealthRequest = proto.service_unary.HealthRequest;
 const HealthResponse = proto.service_unary.HealthResponse;
 const EchoMessage = proto.service_unary.EchoMessage;
+const ScalarMessage = proto.scalar3.ScalarMessage;
+const AnyMessage = proto.harness.AnyMessage;
 
 const StreamingService = proto.service_streaming.StreamingService;
 const StreamRequest = proto.service_streaming.StreamRequest;
@@ -85,23 +87,12 @@ const ZigStreamingImpl = struct {
     }
 
     pub fn bidirectional(self: *@This(), _: *rpc.Context, recv_stream: rpc.RecvStream(ChatMessage), send_stream: rpc.SendStream(ChatMessage)) rpc.RpcError!void {
-        // Read all messages first
-        var messages: std.ArrayList(ChatMessage) = .empty;
-        defer {
-            for (messages.items) |*msg| {
-                var m = msg.*;
-                m.deinit(self.allocator);
-            }
-            messages.deinit(self.allocator);
-        }
-        while (true) {
-            const msg = try recv_stream.recv();
-            if (msg == null) break;
-            messages.append(self.allocator, msg.?) catch return error.status_error;
-        }
-        // Echo all back
-        for (messages.items) |msg| {
-            try send_stream.send(ChatMessage{ .sender = "echo", .text = msg.text });
+        // Echo each message as it arrives instead of buffering until
+        // STREAM_END, so a full-duplex client sees interleaved traffic.
+        while (try recv_stream.recv()) |msg| {
+            var m = msg;
+            defer m.deinit(self.allocator);
+            try send_stream.send(ChatMessage{ .sender = "echo", .text = m.text });
         }
     }
 
@@ -203,6 +194,34 @@ fn dispatchCall(
         const encoded = try transport.encodeMessage(EchoMessage, resp);
         defer transport.freePayload(encoded);
         try transport.writeResponse(encoded);
+    } else if (std.mem.eql(u8, method, "/HarnessService/EchoScalar")) {
+        // HarnessService: decode and re-encode, so the Go client's property
+        // suite round-trips random payloads through the Zig codec.
+        const req = ScalarMessage.decode(transport.allocator, req_bytes) catch |err| {
+            try transport.writeError(@errorName(err));
+            return;
+        };
+        defer {
+            var r = req;
+            r.deinit(transport.allocator);
+        }
+        const encoded = try transport.encodeMessage(ScalarMessage, req);
+        defer transport.freePayload(encoded);
+        try transport.writeResponse(encoded);
+    } else if (std.mem.eql(u8, method, "/HarnessService/EchoAny")) {
+        // HarnessService: the Zig side has no type registry, so it round-trips
+        // the Any envelope and passes the packed message through untouched.
+        const req = AnyMessage.decode(transport.allocator, req_bytes) catch |err| {
+            try transport.writeError(@errorName(err));
+            return;
+        };
+        defer {
+            var r = req;
+            r.deinit(transport.allocator);
+        }
+        const encoded = try transport.encodeMessage(AnyMessage, req);
+        defer transport.freePayload(encoded);
+        try transport.writeResponse(encoded);
     } else if (std.mem.eql(u8, method, "/StreamingService/UnaryCall")) {
         const req = try StreamRequest.decode(transport.allocator, req_bytes);
         defer {
@@ -494,13 +513,35 @@ test "go server / zig client: Bidirectional streaming" {
     try proc.transport.writeShutdown();
 }
 
+test "go server / zig client: unknown fields round trip" {
+    var proc: GoProc = undefined;
+    try proc.spawn(build_options.go_rpc_server);
+    proc.setup();
+    defer proc.deinit();
+
+    // The Go server appends fields EchoMessage does not define. Decoding and
+    // re-encoding must carry them back byte for byte, or CheckUnknownFields
+    // answers with an ERROR.
+    var resp = try callUnary(&proc.transport, EchoMessage, EchoMessage, "/HarnessService/UnknownFields", EchoMessage{ .text = "proxy", .code = 7 });
+    defer resp.deinit(testing.allocator);
+    try testing.expect(resp._unknown_fields.len > 0);
+
+    var checked = try callUnary(&proc.transport, EchoMessage, EchoMessage, "/HarnessService/CheckUnknownFields", resp);
+    defer checked.deinit(testing.allocator);
+    try testing.expectEqualStrings("proxy", checked.text);
+    try testing.expectEqual(@as(i32, 7), checked.code);
+
+    try proc.transport.writeShutdown();
+}
+
 // ══════════════════════════════════════════════════════════════════════
 // Tests: Zig server / Go client
 // ══════════════════════════════════════════════════════════════════════
 
-test "zig server / go client: full test suite" {
-    // Spawn Go client which will send requests and validate responses
-    var child = std.process.Child.init(&.{build_options.go_rpc_client}, testing.allocator);
+/// Runs the Go client with argv against the Zig server loop and expects it
+/// to exit cleanly.
+fn runGoClient(argv: []const []const u8) !void {
+    var child = std.process.Child.init(argv, testing.allocator);
     child.stdin_behavior = .Pipe;
     child.stdout_behavior = .Pipe;
     child.stderr_behavior = .Inherit;
@@ -527,3 +568,20 @@ test "zig server / go client: full test suite" {
         else => return error.GoClientCrashed,
     }
 }
+
+test "zig server / go client: full test suite" {
+    // Spawn Go client which will send requests and validate responses
+    try runGoClient(&.{build_options.go_rpc_client});
+}
+
+test "zig server / go client: property round trips" {
+    try runGoClient(&.{ build_options.go_rpc_client, "-suites", "property", "-seed", "1" });
+}
+
+test "zig server / go client: corpus sweep through EchoAny" {
+    try runGoClient(&.{ build_options.go_rpc_client, "-suites", "any" });
+}
+
+test "zig server / go client: unknown fields through EchoScalar" {
+    try runGoClient(&.{ build_options.go_rpc_client, "-suites", "unknown" });
+}
@@ -0,0 +1,82 @@
+const std = @import("std");
+const proto = @import("proto");
+const framing = @import("framing.zig");
+
+/// Round-trips a go/cmd/schemafuzz corpus through the code generated for its
+/// random schema: usage `schemafuzz IN OUT`. Case names are the message path
+/// within the fuzz package and an index ("M0.M3/2"); each case is decoded as
+/// that type, encoded again and written to OUT under the same name. Cases that
+/// fail to decode are reported and left out, which the Go side flags.
+pub fn main() !void {
+    var gpa: std.heap.DebugAllocator(.{}) = .init;
+    defer _ = gpa.deinit();
+    const allocator = gpa.allocator();
+
+    const args = try std.process.argsAlloc(allocator);
+    defer std.process.argsFree(allocator, args);
+    if (args.len != 3) {
+        std.debug.print("usage: schemafuzz IN OUT\n", .{});
+        std.process.exit(2);
+    }
+
+    const data = try std.fs.cwd().readFileAlloc(allocator, args[1], 1 << 30);
+    defer allocator.free(data);
+    const cases = try framing.read_all_test_cases(allocator, data);
+    defer allocator.free(cases);
+
+    var out: std.Io.Writer.Allocating = .init(allocator);
+    defer out.deinit();
+    try framing.write_header(&out.writer);
+    for (cases) |tc| {
+        const sep = std.mem.lastIndexOfScalar(u8, tc.name, '/') orelse {
+            std.debug.print("{s}: case name has no message path\n", .{tc.name});
+            continue;
+        };
+        var msg: std.Io.Writer.Allocating = .init(allocator);
+        defer msg.deinit();
+        const found = round_trip(proto.fuzz, "", tc.name[0..sep], allocator, tc.data, &msg.writer) catch |err| {
+            std.debug.print("{s}: {s}\n", .{ tc.name, @errorName(err) });
+            continue;
+        };
+        if (!found) {
+            std.debug.print("{s}: no generated type {s}\n", .{ tc.name, tc.name[0..sep] });
+            continue;
+        }
+        try framing.write_test_case(&out.writer, tc.name, msg.written());
+    }
+    try std.fs.cwd().writeFile(.{ .sub_path = args[2], .data = out.written() });
+}
+
+/// Finds the message type called `name` among the messages declared in
+/// `Scope`, nested ones included, and re-encodes `data` as it. Returns false
+/// if there is no such type.
+fn round_trip(
+    comptime Scope: type,
+    comptime prefix: []const u8,
+    name: []const u8,
+    allocator: std.mem.Allocator,
+    data: []const u8,
+    writer: *std.Io.Writer,
+) !bool {
+    @setEvalBranchQuota(1_000_000);
+    inline for (@typeInfo(Scope).@"struct".decls) |decl| {
+        const T = @field(Scope, decl.name);
+        if (@TypeOf(T) == type) {
+            if (comptime is_message(T)) {
+                const path = if (prefix.len == 0) decl.name else prefix ++ "." ++ decl.name;
+                if (std.mem.eql(u8, name, path)) {
+                    var msg = try T.decode(allocator, data);
+                    defer msg.deinit(allocator);
+                    try msg.encode(writer);
+                    return true;
+                }
+                if (try round_trip(T, path, name, allocator, data, writer)) return true;
+            }
+        }
+    }
+    return false;
+}
+
+fn is_message(comptime T: type) bool {
+    return @typeInfo(T) == .@"struct" and @hasDecl(T, "decode") and @hasDecl(T, "encode");
+}
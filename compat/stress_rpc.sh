@@ -0,0 +1,46 @@
+#!/bin/bash
+# Multi-connection stress test: starts rpcserver on a Unix socket and runs
+# many rpcclient processes against it at once. Set ADDR to test a server
+# that is already listening instead.
+# Usage: stress_rpc.sh [clients] [suites]
+set -euo pipefail
+
+SCRIPT_DIR="$(cd "$(dirname "$0")" && pwd)"
+CLIENTS="${1:-50}"
+SUITES="${2:-core,errors,flow}"
+BIN="$(mktemp -d)"
+trap 'kill "${SERVER_PID:-}" 2>/dev/null || true; rm -rf "$BIN"' EXIT
+
+(cd "$SCRIPT_DIR/go" && go build -o "$BIN/rpcserver" ./cmd/rpcserver && go build -o "$BIN/rpcclient" ./cmd/rpcclient)
+
+if [ -z "${ADDR:-}" ]; then
+    ADDR="unix:$BIN/rpc.sock"
+    "$BIN/rpcserver" -listen "$ADDR" &
+    SERVER_PID=$!
+    for _ in $(seq 50); do
+        [ -S "$BIN/rpc.sock" ] && break
+        sleep 0.1
+    done
+fi
+
+echo "=== $CLIENTS clients, suites $SUITES, server $ADDR ==="
+pids=()
+for i in $(seq "$CLIENTS"); do
+    "$BIN/rpcclient" -connect "$ADDR" -suites "$SUITES" -read-delay 0 2>"$BIN/client$i.log" &
+    pids+=($!)
+done
+
+failed=0
+for i in "${!pids[@]}"; do
+    if ! wait "${pids[$i]}"; then
+        echo "client $((i + 1)) failed:"
+        cat "$BIN/client$((i + 1)).log"
+        failed=$((failed + 1))
+    fi
+done
+
+if [ "$failed" -gt 0 ]; then
+    echo "=== $failed of $CLIENTS clients failed ==="
+    exit 1
+fi
+echo "=== All $CLIENTS clients passed ==="